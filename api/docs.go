@@ -6,10 +6,14762 @@ import "github.com/swaggo/swag/v2"
 
 const docTemplate = `{
     "schemes": {{ marshal .Schemes }},
-    "components": {"schemas":{"data":{"properties":{"data":{"$ref":"#/components/schemas/inbound.NotificationsResponse"}},"type":"object"},"entity.UserStatus":{"type":"integer","x-enum-varnames":["UserStatusUnknown","UserStatusUnverified","UserStatusActive","UserStatusBanned","UserStatusInactive"]},"inbound.BackupCodeRotateRequest":{"properties":{"current_password":{"type":"string"}},"type":"object"},"inbound.BackupCodeRotateResponse":{"properties":{"recovery_codes":{"items":{"type":"string"},"type":"array","uniqueItems":false}},"type":"object"},"inbound.EmailVerifyRequest":{"properties":{"challenge_token":{"type":"string"}},"type":"object"},"inbound.Login2FARequest":{"properties":{"challenge_token":{"type":"string"},"code":{"type":"string"},"method":{"type":"string"}},"type":"object"},"inbound.Login2FAResponse":{"properties":{"access_token":{"type":"string"},"refresh_token":{"type":"string"}},"type":"object"},"inbound.LoginRequest":{"properties":{"email":{"type":"string"},"password":{"type":"string"}},"type":"object"},"inbound.LoginResponse":{"properties":{"access_token":{"type":"string"},"available_methods":{"items":{"type":"string"},"type":"array","uniqueItems":false},"challenge_token":{"type":"string"},"mfa_required":{"type":"boolean"},"refresh_token":{"type":"string"}},"type":"object"},"inbound.LogoutRequest":{"properties":{"refresh_token":{"type":"string"}},"type":"object"},"inbound.NotificationCategoriesResponse":{"properties":{"categories":{"items":{"$ref":"#/components/schemas/inbound.NotificationCategoryResponse"},"type":"array","uniqueItems":false}},"type":"object"},"inbound.NotificationCategoryResponse":{"properties":{"description":{"type":"string"},"id":{"type":"integer"},"is_mandatory":{"type":"boolean"},"name":{"type":"string"}},"type":"object"},"inbound.NotificationResponse":{"properties":{"category_id":{"type":"integer"},"created_at":{"type":"string"},"data":{"type":"object"},"id":{"type":"integer"},"metadata":{"type":"object"},"read_at":{"type":"string"},"trigger_key":{"type":"string"}},"type":"object"},"inbound.NotificationSettingRequest":{"properties":{"category_id":{"type":"integer"},"channel":{"type":"string"},"is_enabled":{"type":"boolean"}},"type":"object"},"inbound.NotificationSettingResponse":{"properties":{"category_id":{"type":"integer"},"channel":{"type":"string"},"is_enabled":{"type":"boolean"}},"type":"object"},"inbound.NotificationSettingsResponse":{"properties":{"settings":{"items":{"$ref":"#/components/schemas/inbound.NotificationSettingResponse"},"type":"array","uniqueItems":false}},"type":"object"},"inbound.NotificationSettingsUpdateRequest":{"properties":{"settings":{"items":{"$ref":"#/components/schemas/inbound.NotificationSettingRequest"},"type":"array","uniqueItems":false}},"type":"object"},"inbound.NotificationsResponse":{"properties":{"notifications":{"items":{"$ref":"#/components/schemas/inbound.NotificationResponse"},"type":"array","uniqueItems":false}},"type":"object"},"inbound.PasswordChangeRequest":{"properties":{"current_password":{"type":"string"},"new_password":{"type":"string"}},"type":"object"},"inbound.PasswordForgotRequest":{"properties":{"email":{"type":"string"}},"type":"object"},"inbound.PasswordResetRequest":{"properties":{"challenge_token":{"type":"string"},"new_password":{"type":"string"}},"type":"object"},"inbound.ProfilePermissionsResponse":{"properties":{"permissions":{"additionalProperties":{"items":{"type":"string"},"type":"array"},"type":"object"}},"type":"object"},"inbound.ProfileResponse":{"properties":{"avatar_url":{"type":"string"},"email":{"type":"string"},"full_name":{"type":"string"},"id":{"example":"0","type":"string"},"status":{"type":"string"}},"type":"object"},"inbound.RefreshTokenRequest":{"properties":{"refresh_token":{"type":"string"}},"type":"object"},"inbound.RefreshTokenResponse":{"properties":{"access_token":{"type":"string"},"refresh_token":{"type":"string"}},"type":"object"},"inbound.RegisterDeviceRequest":{"properties":{"device_token":{"type":"string"},"platform":{"type":"string"}},"type":"object"},"inbound.RegisterRequest":{"properties":{"email":{"type":"string"},"full_name":{"type":"string"},"password":{"type":"string"}},"type":"object"},"inbound.RegisterResendRequest":{"properties":{"email":{"type":"string"}},"type":"object"},"inbound.RegisterResendResponse":{"type":"object"},"inbound.RemoveDeviceRequest":{"properties":{"device_token":{"type":"string"}},"type":"object"},"inbound.TOTPConfirmRequest":{"properties":{"challenge_token":{"type":"string"},"code":{"type":"string"}},"type":"object"},"inbound.TOTPSetupRequest":{"properties":{"current_password":{"type":"string"},"friendly_name":{"type":"string"}},"type":"object"},"inbound.TOTPSetupResponse":{"properties":{"challenge_token":{"type":"string"},"key":{"type":"string"},"uri":{"type":"string"}},"type":"object"},"inbound.UpdateProfileRequest":{"properties":{"full_name":{"type":"string"}},"type":"object"},"inbound.UserCreateRequest":{"properties":{"email":{"type":"string"},"full_name":{"type":"string"},"password":{"type":"string"},"status":{"type":"integer","x-enum-varnames":["UserStatusUnknown","UserStatusUnverified","UserStatusActive","UserStatusBanned","UserStatusInactive"]}},"type":"object"},"inbound.UserDetailResponse":{"properties":{"user":{"$ref":"#/components/schemas/inbound.UserResponse"}},"type":"object"},"inbound.UserImportResponse":{"properties":{"created":{"type":"integer"},"updated":{"type":"integer"}},"type":"object"},"inbound.UserImportUserRequest":{"properties":{"email":{"type":"string"},"full_name":{"type":"string"},"password":{"type":"string"},"status":{"type":"integer","x-enum-varnames":["UserStatusUnknown","UserStatusUnverified","UserStatusActive","UserStatusBanned","UserStatusInactive"]}},"type":"object"},"inbound.UserResponse":{"properties":{"avatar_url":{"type":"string"},"email":{"type":"string"},"full_name":{"type":"string"},"id":{"example":"0","type":"string"},"status":{"$ref":"#/components/schemas/entity.UserStatus"},"updated_at":{"type":"string"}},"type":"object"},"inbound.UserUpdateRequest":{"properties":{"email":{"type":"string"},"full_name":{"type":"string"},"password":{"type":"string"},"status":{"type":"integer","x-enum-varnames":["UserStatusUnknown","UserStatusUnverified","UserStatusActive","UserStatusBanned","UserStatusInactive"]}},"type":"object"},"inbound.UsersResponse":{"properties":{"users":{"items":{"$ref":"#/components/schemas/inbound.UserResponse"},"type":"array","uniqueItems":false}},"type":"object"},"router.errorResponse":{"properties":{"error":{"additionalProperties":{"type":"string"},"type":"object"},"message":{"example":"example string message","type":"string"}},"type":"object"},"router.successResponse":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}},"securitySchemes":{"BearerAuth":{"description":"Type \"Bearer\" followed by a space and JWT.","in":"header","name":"Authorization","type":"apiKey"}}},
-    "info": {"contact":{"email":"support@gobite.com","name":"Contact Support","url":"https://gobite.com/contact"},"description":"{{escape .Description}}","license":{"name":"MIT","url":"https://mit-license.org/"},"termsOfService":"https://gobite.com/terms","title":"{{.Title}}","version":"{{.Version}}"},
-    "externalDocs": {"description":"","url":""},
-    "paths": {"/api/v1/identity/login":{"post":{"description":"Validates credentials and returns access/refresh tokens. If MFA is required, a challenge is returned.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.LoginRequest"}}},"description":"Login payload","required":true},"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"Authentication result"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid credentials"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error\" example:{\"message\":\"Login failed due to server error\",\"error\":{\"detail\":\"Please try again later\"}}"}},"summary":"Authenticate user","tags":["Identity","Authentication"]}},"/api/v1/identity/login/2fa":{"post":{"description":"Verifies the 2FA code for a login challenge and returns access/refresh tokens.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.Login2FARequest"}}},"description":"2FA login payload","required":true},"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"Authentication result"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid MFA code"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"summary":"Complete 2FA login","tags":["Identity","Authentication"]}},"/api/v1/identity/logout":{"post":{"description":"Invalidates the provided refresh token.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.LogoutRequest"}}},"description":"Logout payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"summary":"Logout","tags":["Identity","Authentication"]}},"/api/v1/identity/logout-all":{"post":{"description":"Invalidates all refresh tokens for the authenticated user.","responses":{"204":{"description":"No Content"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Logout all sessions","tags":["Identity","Profile Security"]}},"/api/v1/identity/mfa/backup_code/rotate":{"post":{"description":"Generates a new set of recovery codes for the authenticated user.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.BackupCodeRotateRequest"}}},"description":"Backup code rotation payload","required":true},"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"Backup codes rotated"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Rotate backup codes","tags":["Identity","Profile Security"]}},"/api/v1/identity/mfa/totp/confirm":{"post":{"description":"Verifies the TOTP code and activates the MFA factor.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.TOTPConfirmRequest"}}},"description":"TOTP confirmation payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"MFA factor not found"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Confirm TOTP","tags":["Identity","Profile Security"]}},"/api/v1/identity/mfa/totp/setup":{"post":{"description":"Creates a TOTP factor and returns the shared secret and otpauth URI.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.TOTPSetupRequest"}}},"description":"TOTP setup payload","required":true},"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"TOTP setup result"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Setup TOTP","tags":["Identity","Profile Security"]}},"/api/v1/identity/password/change":{"post":{"description":"Updates the user's password after validating the current password.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.PasswordChangeRequest"}}},"description":"Change password payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Change password","tags":["Identity","Profile Security"]}},"/api/v1/identity/password/forgot":{"post":{"description":"Sends password reset instructions to the provided email address.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.PasswordForgotRequest"}}},"description":"Forgot password payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"summary":"Request password reset","tags":["Identity","Authentication"]}},"/api/v1/identity/password/reset":{"post":{"description":"Sets a new password using the provided reset token.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.PasswordResetRequest"}}},"description":"Reset password payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"404":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Reset token not found"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"summary":"Reset password","tags":["Identity","Authentication"]}},"/api/v1/identity/profile":{"get":{"description":"Returns profile information for the authenticated user.","responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"Profile result"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Get profile","tags":["Identity","Profile"]},"put":{"description":"Updates profile details for the authenticated user.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.UpdateProfileRequest"}}},"description":"Profile update payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Update profile","tags":["Identity","Profile"]}},"/api/v1/identity/profile/avatar":{"put":{"description":"Updates avatar for the authenticated user.","requestBody":{"content":{"multipart/form-data":{"schema":{"type":"file"}}},"description":"Avatar image","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Update profile avatar","tags":["Identity","Profile"]}},"/api/v1/identity/profile/permissions":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"Permissions list"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Get profile permissions","tags":["Identity","Profile Security"]}},"/api/v1/identity/refresh":{"post":{"description":"Exchanges a refresh token for a new access/refresh token pair.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.RefreshTokenRequest"}}},"description":"Refresh token payload","required":true},"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"Token refresh result"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid refresh token"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"summary":"Refresh access token","tags":["Identity","Authentication"]}},"/api/v1/identity/register":{"post":{"description":"Creates a new account and sends a verification email.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.RegisterRequest"}}},"description":"Registration payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"409":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Email already registered"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"summary":"Register user","tags":["Identity","Authentication"]}},"/api/v1/identity/register/resend":{"post":{"description":"Sends a new verification email when an account exists for the provided address.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.RegisterResendRequest"}}},"description":"Resend verification payload","required":true},"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"Resend result"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"summary":"Resend verification email","tags":["Identity","Authentication"]}},"/api/v1/identity/register/verify":{"post":{"description":"Confirms the user's email address using the provided verification token.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.EmailVerifyRequest"}}},"description":"Email verification payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"404":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Verification token not found"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"summary":"Verify email","tags":["Identity","Authentication"]}},"/api/v1/identity/users":{"get":{"description":"Returns a paginated list of users with optional search and status filters.","parameters":[{"description":"Search by email or full name","in":"query","name":"search","schema":{"type":"string"}},{"description":"Sort by email, full name and etc.","in":"query","name":"sort_by","schema":{"type":"string"}},{"description":"Sort order asc or desc","in":"query","name":"sort_order","schema":{"type":"string"}},{"description":"Filter by statuses (1=unverified|2=active|3=banned|4=deleted)","in":"query","name":"status","schema":{"items":{"type":"integer"},"type":"array"}},{"description":"Filter by created_at \u003e= date_from (RFC3339)","in":"query","name":"date_from","schema":{"type":"string"}},{"description":"Filter by created_at \u003c= date_to (RFC3339)","in":"query","name":"date_to","schema":{"type":"string"}},{"description":"Pagination size","in":"query","name":"size","schema":{"type":"integer"}},{"description":"Pagination page","in":"query","name":"page","schema":{"type":"integer"}}],"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"User list"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid query parameters"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Forbidden"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"List users","tags":["Identity","Management Users"]},"post":{"description":"Creates a new user.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.UserCreateRequest"}}},"description":"User creation payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Forbidden"},"409":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Email already registered"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Create user","tags":["Identity","Management Users"]}},"/api/v1/identity/users-export":{"get":{"description":"Returns user list for export with optional filters.","parameters":[{"description":"Search by email or full name","in":"query","name":"search","schema":{"type":"string"}},{"description":"Filter by user status","in":"query","name":"status","schema":{"items":{"type":"integer"},"type":"array"}},{"description":"Sort by email, full name and etc.","in":"query","name":"sort_by","schema":{"type":"string"}},{"description":"Sort order: asc, desc","in":"query","name":"sort_order","schema":{"type":"string"}},{"description":"Filter by created_at \u003e= date_from (RFC3339)","in":"query","name":"date_from","schema":{"type":"string"}},{"description":"Filter by created_at \u003c= date_to (RFC3339)","in":"query","name":"date_to","schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"User export"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid query parameter"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Forbidden"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Export users","tags":["Identity","Management Users"]}},"/api/v1/identity/users-import":{"post":{"description":"Imports users in bulk.","requestBody":{"content":{"application/json":{"schema":{"items":{"$ref":"#/components/schemas/inbound.UserImportUserRequest"},"type":"array"}}},"description":"User import payload","required":true},"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"User import result"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Forbidden"},"409":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Email already registered"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Import users","tags":["Identity","Management Users"]}},"/api/v1/identity/users/{id}":{"delete":{"description":"Deletes a user by ID.","parameters":[{"description":"User ID","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid path parameter"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Forbidden"},"404":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"User not found"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Delete user","tags":["Identity","Management Users"]},"get":{"description":"Returns user details for a given user ID.","parameters":[{"description":"User ID","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"User detail"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid path parameter"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Forbidden"},"404":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"User not found"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Get user detail","tags":["Identity","Management Users"]},"put":{"description":"Updates a user by ID.","parameters":[{"description":"User ID","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.UserUpdateRequest"}}},"description":"User update payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Forbidden"},"404":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"User not found"},"409":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Email already registered"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Update user","tags":["Identity","Management Users"]}},"/api/v1/notification/categories":{"get":{"description":"Returns all notification categories.","responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"Category list"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"List notification categories","tags":["Notification"]}},"/api/v1/notification/device":{"delete":{"description":"Removes a device token for the authenticated user.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.RemoveDeviceRequest"}}},"description":"Device removal payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Remove device","tags":["Notification"]},"post":{"description":"Registers a device token for the authenticated user.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.RegisterDeviceRequest"}}},"description":"Device registration payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Register device","tags":["Notification"]}},"/api/v1/notification/inbox":{"get":{"description":"Returns inbox notifications for the authenticated user.","parameters":[{"description":"Filter by status (all|read|unread)","in":"query","name":"status","schema":{"type":"string"}},{"description":"Pagination limit","in":"query","name":"limit","schema":{"type":"integer"}},{"description":"Pagination offset","in":"query","name":"offset","schema":{"type":"integer"}}],"responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"Notification list"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid query parameters"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"List inbox","tags":["Inbox"]}},"/api/v1/notification/inbox/read-all":{"put":{"description":"Marks all inbox notifications as read for the authenticated user.","responses":{"204":{"description":"No Content"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Mark all inbox read","tags":["Inbox"]}},"/api/v1/notification/inbox/{id}":{"delete":{"description":"Soft deletes an inbox notification for the authenticated user.","parameters":[{"description":"Notification ID","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid notification id"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Notification not found"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Delete inbox","tags":["Inbox"]}},"/api/v1/notification/inbox/{id}/read":{"patch":{"description":"Marks an inbox notification as read.","parameters":[{"description":"Notification ID","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid notification id"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Notification not found"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Mark inbox read","tags":["Inbox"]}},"/api/v1/notification/settings":{"get":{"description":"Returns notification settings for the authenticated user.","responses":{"200":{"content":{"application/json":{"schema":{"allOf":[{"$ref":"#/components/schemas/data"}],"properties":{"data":{"type":"object"},"message":{"example":"example string message","type":"string"},"meta":{"type":"object"}},"type":"object"}}},"description":"Settings list"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"List notification settings","tags":["Notification"]},"put":{"description":"Updates notification settings for the authenticated user.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/inbound.NotificationSettingsUpdateRequest"}}},"description":"Settings payload","required":true},"responses":{"204":{"description":"No Content"},"400":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Invalid request body"},"401":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Validation error"},"500":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/router.errorResponse"}}},"description":"Internal server error"}},"security":[{"BearerAuth":[]}],"summary":"Update notification settings","tags":["Notification"]}},"/api/v1/notification/stream":{"get":{"description":"Streams notification updates using Server-Sent Events (SSE).","responses":{"200":{"content":{"application/json":{"schema":{"type":"string"}},"text/event-stream":{"schema":{"type":"string"}}},"description":"SSE stream"},"401":{"content":{"application/json":{"schema":{"type":"string"}}},"description":"Unauthorized"},"500":{"content":{"application/json":{"schema":{"type":"string"}}},"description":"streaming unsupported"}},"security":[{"BearerAuth":[]}],"summary":"Stream notifications","tags":["Notification"]}}},
+    "components": {
+        "schemas": {
+            "app.JobResponse": {
+                "properties": {
+                    "failures": {
+                        "type": "integer"
+                    },
+                    "interval_seconds": {
+                        "type": "integer"
+                    },
+                    "kind": {
+                        "type": "string"
+                    },
+                    "last_duration_ms": {
+                        "type": "integer"
+                    },
+                    "last_error": {
+                        "type": "string"
+                    },
+                    "last_run_at": {
+                        "type": "string"
+                    },
+                    "last_succeeded": {
+                        "type": "boolean"
+                    },
+                    "name": {
+                        "type": "string"
+                    },
+                    "next_run_at": {
+                        "type": "string"
+                    },
+                    "running": {
+                        "type": "boolean"
+                    },
+                    "runs": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "app.JobRunResponse": {
+                "properties": {
+                    "job": {
+                        "$ref": "#/components/schemas/app.JobResponse"
+                    }
+                },
+                "type": "object"
+            },
+            "app.JobsResponse": {
+                "properties": {
+                    "jobs": {
+                        "items": {
+                            "$ref": "#/components/schemas/app.JobResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "data": {
+                "properties": {
+                    "data": {
+                        "$ref": "#/components/schemas/inbound.ArchiveReplayResponse"
+                    }
+                },
+                "type": "object"
+            },
+            "entity.UserStatus": {
+                "enum": [
+                    0,
+                    1,
+                    2,
+                    3,
+                    4
+                ],
+                "type": "integer",
+                "x-enum-varnames": [
+                    "UserStatusUnknown",
+                    "UserStatusUnverified",
+                    "UserStatusActive",
+                    "UserStatusBanned",
+                    "UserStatusInactive"
+                ]
+            },
+            "inbound.APIKeyCreateRequest": {
+                "properties": {
+                    "expires_in_days": {
+                        "type": "integer"
+                    },
+                    "name": {
+                        "type": "string"
+                    },
+                    "scopes": {
+                        "additionalProperties": {
+                            "items": {
+                                "type": "string"
+                            },
+                            "type": "array"
+                        },
+                        "type": "object"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.APIKeyCreateResponse": {
+                "properties": {
+                    "created_at": {
+                        "type": "string"
+                    },
+                    "expires_at": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "type": "integer"
+                    },
+                    "key": {
+                        "description": "Key is shown only in this response.",
+                        "type": "string"
+                    },
+                    "last_used_at": {
+                        "type": "string"
+                    },
+                    "name": {
+                        "type": "string"
+                    },
+                    "prefix": {
+                        "type": "string"
+                    },
+                    "scopes": {
+                        "additionalProperties": {
+                            "items": {
+                                "type": "string"
+                            },
+                            "type": "array"
+                        },
+                        "type": "object"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.APIKeyResponse": {
+                "properties": {
+                    "created_at": {
+                        "type": "string"
+                    },
+                    "expires_at": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "type": "integer"
+                    },
+                    "last_used_at": {
+                        "type": "string"
+                    },
+                    "name": {
+                        "type": "string"
+                    },
+                    "prefix": {
+                        "type": "string"
+                    },
+                    "scopes": {
+                        "additionalProperties": {
+                            "items": {
+                                "type": "string"
+                            },
+                            "type": "array"
+                        },
+                        "type": "object"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.APIKeysResponse": {
+                "properties": {
+                    "api_keys": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.APIKeyResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ArchiveReplayRequest": {
+                "properties": {
+                    "destination": {
+                        "type": "string"
+                    },
+                    "from": {
+                        "type": "string"
+                    },
+                    "to": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ArchiveReplayResponse": {
+                "properties": {
+                    "failed": {
+                        "type": "integer"
+                    },
+                    "published": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.AuditTrailResponse": {
+                "properties": {
+                    "events": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.EventResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.BackupCodeRequest": {
+                "properties": {
+                    "current_password": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.BackupCodeResponse": {
+                "properties": {
+                    "recovery_codes": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ClientCredentialsRequest": {
+                "properties": {
+                    "client_id": {
+                        "type": "string"
+                    },
+                    "client_secret": {
+                        "type": "string"
+                    },
+                    "grant_type": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ClientCredentialsResponse": {
+                "properties": {
+                    "access_token": {
+                        "type": "string"
+                    },
+                    "expires_in": {
+                        "type": "integer"
+                    },
+                    "token_type": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.EmailChangeConfirmRequest": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.EmailChangeRequest": {
+                "properties": {
+                    "current_password": {
+                        "type": "string"
+                    },
+                    "new_email": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.EmailVerifyRequest": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "exp": {
+                        "type": "string"
+                    },
+                    "sig": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.EventDetailResponse": {
+                "properties": {
+                    "event": {
+                        "$ref": "#/components/schemas/inbound.EventResponse"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.EventResponse": {
+                "properties": {
+                    "action": {
+                        "type": "string"
+                    },
+                    "actor_id": {
+                        "example": "0",
+                        "type": "string"
+                    },
+                    "correlation_id": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "example": "0",
+                        "type": "string"
+                    },
+                    "ip": {
+                        "type": "string"
+                    },
+                    "metadata": {
+                        "$ref": "#/components/schemas/valueobject.JSONMap"
+                    },
+                    "module": {
+                        "type": "string"
+                    },
+                    "occurred_at": {
+                        "type": "string"
+                    },
+                    "subject_id": {
+                        "example": "0",
+                        "type": "string"
+                    },
+                    "user_agent": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.EventsResponse": {
+                "properties": {
+                    "events": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.EventResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.IdentitiesResponse": {
+                "properties": {
+                    "identities": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.IdentityResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.IdentityResponse": {
+                "properties": {
+                    "created_at": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "type": "integer"
+                    },
+                    "provider": {
+                        "type": "string"
+                    },
+                    "provider_user_id": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.Login2FARequest": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "code": {
+                        "type": "string"
+                    },
+                    "method": {
+                        "type": "string"
+                    },
+                    "remember_device": {
+                        "type": "boolean"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.Login2FAResponse": {
+                "properties": {
+                    "access_token": {
+                        "type": "string"
+                    },
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "csrf_token": {
+                        "type": "string"
+                    },
+                    "device_expires_at": {
+                        "type": "string"
+                    },
+                    "device_token": {
+                        "type": "string"
+                    },
+                    "password_reset_required": {
+                        "description": "PasswordResetRequired means an administrator requires a new password: ChallengeToken\nis a password reset token and no tokens are issued.",
+                        "type": "boolean"
+                    },
+                    "refresh_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.Login2FASMSRequest": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.Login2FASMSResponse": {
+                "properties": {
+                    "destination": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.LoginEventResponse": {
+                "properties": {
+                    "created_at": {
+                        "type": "string"
+                    },
+                    "failure_reason": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "example": "0",
+                        "type": "string"
+                    },
+                    "ip": {
+                        "type": "string"
+                    },
+                    "location": {
+                        "$ref": "#/components/schemas/inbound.LoginLocationResponse"
+                    },
+                    "method": {
+                        "type": "string"
+                    },
+                    "success": {
+                        "type": "boolean"
+                    },
+                    "user_agent": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.LoginLocationResponse": {
+                "properties": {
+                    "city": {
+                        "type": "string"
+                    },
+                    "country": {
+                        "type": "string"
+                    },
+                    "region": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.LoginMFASetupConfirmRequest": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "code": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.LoginMFASetupRequest": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "friendly_name": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.LoginMFASetupResponse": {
+                "properties": {
+                    "key": {
+                        "type": "string"
+                    },
+                    "uri": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.LoginRequest": {
+                "properties": {
+                    "captcha_token": {
+                        "description": "CaptchaToken may be sent instead of the X-Captcha-Token header.",
+                        "type": "string"
+                    },
+                    "device_token": {
+                        "description": "DeviceToken is the trusted device token returned by the 2FA login, skipping the MFA challenge.",
+                        "type": "string"
+                    },
+                    "email": {
+                        "type": "string"
+                    },
+                    "password": {
+                        "type": "string"
+                    },
+                    "username": {
+                        "description": "Username may be sent instead of Email when usernames are enabled.",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.LoginResponse": {
+                "properties": {
+                    "access_token": {
+                        "type": "string"
+                    },
+                    "available_methods": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "csrf_token": {
+                        "description": "CSRFToken replaces RefreshToken when the refresh token is set as a cookie; send it in the\nX-CSRF-Token header to refresh or log out.",
+                        "type": "string"
+                    },
+                    "mfa_required": {
+                        "type": "boolean"
+                    },
+                    "mfa_setup_required": {
+                        "type": "boolean"
+                    },
+                    "password_reset_required": {
+                        "description": "PasswordResetRequired and MfaSetupRequired hold the login until the password is reset\nor a factor is enrolled with challenge_token.",
+                        "type": "boolean"
+                    },
+                    "refresh_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.LogoutRequest": {
+                "properties": {
+                    "refresh_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.MFAFactorDeleteRequest": {
+                "properties": {
+                    "code": {
+                        "type": "string"
+                    },
+                    "current_password": {
+                        "type": "string"
+                    },
+                    "method": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.MFAFactorRenameRequest": {
+                "properties": {
+                    "friendly_name": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.MFAFactorsResponse": {
+                "properties": {
+                    "factors": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.UserMFAFactorResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.MFARecoveryCompleteRequest": {
+                "properties": {
+                    "recovery_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.MFARecoveryRequest": {
+                "properties": {
+                    "email": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.MFARecoveryVerifyRequest": {
+                "properties": {
+                    "backup_code": {
+                        "type": "string"
+                    },
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "password": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.MFARecoveryVerifyResponse": {
+                "properties": {
+                    "available_at": {
+                        "type": "string"
+                    },
+                    "recovery_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.MetadataResponse": {
+                "properties": {
+                    "access_token_ttl_seconds": {
+                        "type": "integer"
+                    },
+                    "api_key_header": {
+                        "type": "string"
+                    },
+                    "endpoints": {
+                        "additionalProperties": {
+                            "type": "string"
+                        },
+                        "type": "object"
+                    },
+                    "features": {
+                        "additionalProperties": {
+                            "type": "boolean"
+                        },
+                        "type": "object"
+                    },
+                    "grant_types_supported": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "issuer": {
+                        "type": "string"
+                    },
+                    "jwks_uri": {
+                        "type": "string"
+                    },
+                    "mfa_methods_supported": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "oauth_providers": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "password_policy": {
+                        "$ref": "#/components/schemas/inbound.PasswordPolicy"
+                    },
+                    "refresh_token_ttl_days": {
+                        "type": "integer"
+                    },
+                    "token_endpoint": {
+                        "type": "string"
+                    },
+                    "token_endpoint_auth_methods_supported": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.NotificationCategoriesResponse": {
+                "properties": {
+                    "categories": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.NotificationCategoryResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.NotificationCategoryResponse": {
+                "properties": {
+                    "description": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "type": "integer"
+                    },
+                    "is_mandatory": {
+                        "type": "boolean"
+                    },
+                    "name": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.NotificationResponse": {
+                "properties": {
+                    "category_id": {
+                        "type": "integer"
+                    },
+                    "created_at": {
+                        "type": "string"
+                    },
+                    "data": {
+                        "type": "object"
+                    },
+                    "id": {
+                        "type": "integer"
+                    },
+                    "metadata": {
+                        "type": "object"
+                    },
+                    "read_at": {
+                        "type": "string"
+                    },
+                    "trigger_key": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.NotificationSettingRequest": {
+                "properties": {
+                    "category_id": {
+                        "type": "integer"
+                    },
+                    "channel": {
+                        "type": "string"
+                    },
+                    "is_enabled": {
+                        "type": "boolean"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.NotificationSettingResponse": {
+                "properties": {
+                    "category_id": {
+                        "type": "integer"
+                    },
+                    "channel": {
+                        "type": "string"
+                    },
+                    "is_enabled": {
+                        "type": "boolean"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.NotificationSettingsResponse": {
+                "properties": {
+                    "settings": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.NotificationSettingResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "version": {
+                        "description": "Version is sent back as If-Match or expected_version when updating the settings.",
+                        "example": "0",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.NotificationSettingsUpdateRequest": {
+                "properties": {
+                    "expected_version": {
+                        "description": "ExpectedVersion is the version read with the settings; the If-Match header takes precedence.",
+                        "example": "0",
+                        "type": "string"
+                    },
+                    "settings": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.NotificationSettingRequest"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.NotificationTriggerResponse": {
+                "properties": {
+                    "data_schema": {
+                        "additionalProperties": {},
+                        "type": "object"
+                    },
+                    "description": {
+                        "type": "string"
+                    },
+                    "key": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.NotificationTriggersResponse": {
+                "properties": {
+                    "triggers": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.NotificationTriggerResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.NotificationsResponse": {
+                "properties": {
+                    "notifications": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.NotificationResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OAuthAuthorizeResponse": {
+                "properties": {
+                    "authorization_url": {
+                        "type": "string"
+                    },
+                    "state": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OAuthCallbackRequest": {
+                "properties": {
+                    "code": {
+                        "type": "string"
+                    },
+                    "error": {
+                        "description": "Error is set instead of Code when the user or provider declined the sign-in.",
+                        "type": "string"
+                    },
+                    "state": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OnboardingStepResponse": {
+                "properties": {
+                    "completed": {
+                        "type": "boolean"
+                    },
+                    "key": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OrgCreateRequest": {
+                "properties": {
+                    "name": {
+                        "type": "string"
+                    },
+                    "slug": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OrgInviteAcceptRequest": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OrgMemberAddRequest": {
+                "properties": {
+                    "email": {
+                        "type": "string"
+                    },
+                    "role": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OrgMemberResponse": {
+                "properties": {
+                    "email": {
+                        "type": "string"
+                    },
+                    "full_name": {
+                        "type": "string"
+                    },
+                    "joined_at": {
+                        "type": "string"
+                    },
+                    "role": {
+                        "type": "string"
+                    },
+                    "user_id": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OrgMemberUpdateRequest": {
+                "properties": {
+                    "role": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OrgMembersResponse": {
+                "properties": {
+                    "members": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.OrgMemberResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OrgResponse": {
+                "properties": {
+                    "created_at": {
+                        "type": "string"
+                    },
+                    "created_by": {
+                        "type": "integer"
+                    },
+                    "id": {
+                        "type": "integer"
+                    },
+                    "name": {
+                        "type": "string"
+                    },
+                    "role": {
+                        "type": "string"
+                    },
+                    "slug": {
+                        "type": "string"
+                    },
+                    "updated_at": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OrgTokenResponse": {
+                "properties": {
+                    "access_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OrgUpdateRequest": {
+                "properties": {
+                    "name": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.OrgsResponse": {
+                "properties": {
+                    "organizations": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.OrgResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.PasswordChangeRequest": {
+                "properties": {
+                    "current_password": {
+                        "type": "string"
+                    },
+                    "new_password": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.PasswordForgotRequest": {
+                "properties": {
+                    "captcha_token": {
+                        "description": "CaptchaToken may be sent instead of the X-Captcha-Token header.",
+                        "type": "string"
+                    },
+                    "email": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.PasswordPolicy": {
+                "properties": {
+                    "breach_check": {
+                        "type": "boolean"
+                    },
+                    "disallow_email": {
+                        "type": "boolean"
+                    },
+                    "history_size": {
+                        "type": "integer"
+                    },
+                    "max_length": {
+                        "type": "integer"
+                    },
+                    "min_length": {
+                        "type": "integer"
+                    },
+                    "require_digit": {
+                        "type": "boolean"
+                    },
+                    "require_lowercase": {
+                        "type": "boolean"
+                    },
+                    "require_symbol": {
+                        "type": "boolean"
+                    },
+                    "require_uppercase": {
+                        "type": "boolean"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.PasswordResetRequest": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "new_password": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ProfileAttributesRequest": {
+                "properties": {
+                    "attributes": {
+                        "description": "Attributes are merged into the stored ones; a key set to null is removed.",
+                        "type": "object"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ProfileAttributesResponse": {
+                "properties": {
+                    "attributes": {
+                        "type": "object"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ProfileDeleteRequest": {
+                "properties": {
+                    "current_password": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ProfileDeleteResponse": {
+                "properties": {
+                    "scheduled_at": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ProfileExportResponse": {
+                "properties": {
+                    "expires_at": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "type": "string"
+                    },
+                    "url": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ProfileLoginsResponse": {
+                "properties": {
+                    "logins": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.LoginEventResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "next_before_id": {
+                        "description": "NextBeforeID is passed as before_id to fetch older entries; 0 on the last page.",
+                        "example": "0",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ProfileOnboardingResponse": {
+                "properties": {
+                    "completed": {
+                        "type": "integer"
+                    },
+                    "percent": {
+                        "type": "integer"
+                    },
+                    "steps": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.OnboardingStepResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "total": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ProfilePermissionsResponse": {
+                "properties": {
+                    "permissions": {
+                        "additionalProperties": {
+                            "items": {
+                                "type": "string"
+                            },
+                            "type": "array"
+                        },
+                        "type": "object"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ProfileResponse": {
+                "properties": {
+                    "avatar_url": {
+                        "type": "string"
+                    },
+                    "email": {
+                        "type": "string"
+                    },
+                    "full_name": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "example": "0",
+                        "type": "string"
+                    },
+                    "status": {
+                        "type": "string"
+                    },
+                    "username": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ProfileSecurityScoreResponse": {
+                "properties": {
+                    "checks": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.SecurityCheckResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "score": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ProfileSettingMFAResponse": {
+                "properties": {
+                    "backup_code_enabled": {
+                        "type": "boolean"
+                    },
+                    "sms_enabled": {
+                        "type": "boolean"
+                    },
+                    "totp_enabled": {
+                        "type": "boolean"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ReauthRequest": {
+                "properties": {
+                    "code": {
+                        "type": "string"
+                    },
+                    "method": {
+                        "type": "string"
+                    },
+                    "password": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ReauthResponse": {
+                "properties": {
+                    "access_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RefreshTokenRequest": {
+                "properties": {
+                    "refresh_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RefreshTokenResponse": {
+                "properties": {
+                    "access_token": {
+                        "type": "string"
+                    },
+                    "csrf_token": {
+                        "type": "string"
+                    },
+                    "refresh_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RegisterDeviceRequest": {
+                "properties": {
+                    "device_token": {
+                        "type": "string"
+                    },
+                    "platform": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RegisterRequest": {
+                "properties": {
+                    "captcha_token": {
+                        "description": "CaptchaToken may be sent instead of the X-Captcha-Token header.",
+                        "type": "string"
+                    },
+                    "email": {
+                        "type": "string"
+                    },
+                    "full_name": {
+                        "type": "string"
+                    },
+                    "password": {
+                        "type": "string"
+                    },
+                    "username": {
+                        "description": "Username is optional and only accepted when usernames are enabled.",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RegisterResendRequest": {
+                "properties": {
+                    "captcha_token": {
+                        "description": "CaptchaToken may be sent instead of the X-Captcha-Token header.",
+                        "type": "string"
+                    },
+                    "email": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RegisterResendResponse": {
+                "type": "object"
+            },
+            "inbound.RemoveDeviceRequest": {
+                "properties": {
+                    "device_token": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RoleCreateRequest": {
+                "properties": {
+                    "name": {
+                        "type": "string"
+                    },
+                    "permissions": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.RolePermissionRequest"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RoleDetailResponse": {
+                "properties": {
+                    "effective_permissions": {
+                        "additionalProperties": {
+                            "items": {
+                                "type": "string"
+                            },
+                            "type": "array"
+                        },
+                        "type": "object"
+                    },
+                    "member_ids": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "name": {
+                        "type": "string"
+                    },
+                    "permissions": {
+                        "additionalProperties": {
+                            "items": {
+                                "type": "string"
+                            },
+                            "type": "array"
+                        },
+                        "type": "object"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RoleMemberRequest": {
+                "properties": {
+                    "user_id": {
+                        "example": "0",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RolePermissionRequest": {
+                "properties": {
+                    "action": {
+                        "type": "string"
+                    },
+                    "object": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RolePermissionsResponse": {
+                "properties": {
+                    "effective_permissions": {
+                        "additionalProperties": {
+                            "items": {
+                                "type": "string"
+                            },
+                            "type": "array"
+                        },
+                        "type": "object"
+                    },
+                    "permissions": {
+                        "additionalProperties": {
+                            "items": {
+                                "type": "string"
+                            },
+                            "type": "array"
+                        },
+                        "type": "object"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RoleSummaryResponse": {
+                "properties": {
+                    "members": {
+                        "type": "integer"
+                    },
+                    "name": {
+                        "type": "string"
+                    },
+                    "permissions": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RoleUpdateRequest": {
+                "properties": {
+                    "permissions": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.RolePermissionRequest"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.RolesResponse": {
+                "properties": {
+                    "roles": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.RoleSummaryResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.SAMLLoginResponse": {
+                "properties": {
+                    "authorization_url": {
+                        "type": "string"
+                    },
+                    "relay_state": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.SMSConfirmRequest": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "code": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.SMSSetupRequest": {
+                "properties": {
+                    "current_password": {
+                        "type": "string"
+                    },
+                    "phone_number": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.SMSSetupResponse": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "destination": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.SecurityCheckResponse": {
+                "properties": {
+                    "key": {
+                        "type": "string"
+                    },
+                    "passed": {
+                        "type": "boolean"
+                    },
+                    "recommendation": {
+                        "type": "string"
+                    },
+                    "weight": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ServiceAccountCreateRequest": {
+                "properties": {
+                    "name": {
+                        "type": "string"
+                    },
+                    "roles": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ServiceAccountCreateResponse": {
+                "properties": {
+                    "client_id": {
+                        "type": "string"
+                    },
+                    "client_secret": {
+                        "description": "ClientSecret is shown only in this response.",
+                        "type": "string"
+                    },
+                    "created_at": {
+                        "type": "string"
+                    },
+                    "created_by": {
+                        "type": "integer"
+                    },
+                    "id": {
+                        "type": "integer"
+                    },
+                    "last_used_at": {
+                        "type": "string"
+                    },
+                    "name": {
+                        "type": "string"
+                    },
+                    "roles": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ServiceAccountResponse": {
+                "properties": {
+                    "client_id": {
+                        "type": "string"
+                    },
+                    "created_at": {
+                        "type": "string"
+                    },
+                    "created_by": {
+                        "type": "integer"
+                    },
+                    "id": {
+                        "type": "integer"
+                    },
+                    "last_used_at": {
+                        "type": "string"
+                    },
+                    "name": {
+                        "type": "string"
+                    },
+                    "roles": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.ServiceAccountsResponse": {
+                "properties": {
+                    "service_accounts": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.ServiceAccountResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.SessionResponse": {
+                "properties": {
+                    "client": {
+                        "type": "string"
+                    },
+                    "device_name": {
+                        "type": "string"
+                    },
+                    "expires_at": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "type": "integer"
+                    },
+                    "ip": {
+                        "type": "string"
+                    },
+                    "last_used_at": {
+                        "type": "string"
+                    },
+                    "started_at": {
+                        "type": "string"
+                    },
+                    "user_agent": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.SessionsResponse": {
+                "properties": {
+                    "sessions": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.SessionResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.TOTPConfirmRequest": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "code": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.TOTPSetupRequest": {
+                "properties": {
+                    "current_password": {
+                        "type": "string"
+                    },
+                    "friendly_name": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.TOTPSetupResponse": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "key": {
+                        "type": "string"
+                    },
+                    "uri": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.TemplateResponse": {
+                "properties": {
+                    "body": {
+                        "type": "string"
+                    },
+                    "channel": {
+                        "type": "string"
+                    },
+                    "subject": {
+                        "type": "string"
+                    },
+                    "trigger_key": {
+                        "type": "string"
+                    },
+                    "updated_at": {
+                        "type": "string"
+                    },
+                    "version": {
+                        "example": "0",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.TemplateSaveRequest": {
+                "properties": {
+                    "body": {
+                        "type": "string"
+                    },
+                    "expected_version": {
+                        "description": "ExpectedVersion is the version read with the template; the If-Match header takes precedence.",
+                        "example": "0",
+                        "type": "string"
+                    },
+                    "subject": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.TrustedDeviceResponse": {
+                "properties": {
+                    "created_at": {
+                        "type": "string"
+                    },
+                    "expires_at": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "type": "integer"
+                    },
+                    "ip": {
+                        "type": "string"
+                    },
+                    "last_used_at": {
+                        "type": "string"
+                    },
+                    "user_agent": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.TrustedDevicesResponse": {
+                "properties": {
+                    "devices": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.TrustedDeviceResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UpdateProfileRequest": {
+                "properties": {
+                    "full_name": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UpdateProfileUsernameRequest": {
+                "properties": {
+                    "username": {
+                        "description": "Username is the new username; empty removes it.",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserCreateRequest": {
+                "properties": {
+                    "email": {
+                        "type": "string"
+                    },
+                    "full_name": {
+                        "type": "string"
+                    },
+                    "password": {
+                        "type": "string"
+                    },
+                    "status": {
+                        "$ref": "#/components/schemas/entity.UserStatus"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserDetailResponse": {
+                "properties": {
+                    "user": {
+                        "$ref": "#/components/schemas/inbound.UserResponse"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserExportJobCreateRequest": {
+                "properties": {
+                    "date_from": {
+                        "type": "string"
+                    },
+                    "date_to": {
+                        "type": "string"
+                    },
+                    "format": {
+                        "type": "string"
+                    },
+                    "search": {
+                        "type": "string"
+                    },
+                    "sort_by": {
+                        "type": "string"
+                    },
+                    "sort_order": {
+                        "type": "string"
+                    },
+                    "status": {
+                        "items": {
+                            "$ref": "#/components/schemas/entity.UserStatus"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserExportJobResponse": {
+                "properties": {
+                    "completed_at": {
+                        "type": "string"
+                    },
+                    "created_at": {
+                        "type": "string"
+                    },
+                    "error": {
+                        "type": "string"
+                    },
+                    "format": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "example": "0",
+                        "type": "string"
+                    },
+                    "rows_total": {
+                        "type": "integer"
+                    },
+                    "rows_written": {
+                        "type": "integer"
+                    },
+                    "status": {
+                        "type": "string"
+                    },
+                    "updated_at": {
+                        "type": "string"
+                    },
+                    "url": {
+                        "type": "string"
+                    },
+                    "url_expires_at": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserImpersonateRequest": {
+                "properties": {
+                    "reason": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserImpersonateResponse": {
+                "properties": {
+                    "access_token": {
+                        "type": "string"
+                    },
+                    "expires_in": {
+                        "type": "integer"
+                    },
+                    "token_type": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserImportFileResponse": {
+                "properties": {
+                    "created": {
+                        "type": "integer"
+                    },
+                    "errors": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.UserImportRowErrorResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "failed": {
+                        "type": "integer"
+                    },
+                    "truncated": {
+                        "type": "boolean"
+                    },
+                    "updated": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserImportResponse": {
+                "properties": {
+                    "created": {
+                        "type": "integer"
+                    },
+                    "updated": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserImportRowErrorResponse": {
+                "properties": {
+                    "field": {
+                        "type": "string"
+                    },
+                    "message": {
+                        "type": "string"
+                    },
+                    "row": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserImportUserRequest": {
+                "properties": {
+                    "email": {
+                        "type": "string"
+                    },
+                    "full_name": {
+                        "type": "string"
+                    },
+                    "password": {
+                        "type": "string"
+                    },
+                    "status": {
+                        "$ref": "#/components/schemas/entity.UserStatus"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserInviteAcceptRequest": {
+                "properties": {
+                    "challenge_token": {
+                        "type": "string"
+                    },
+                    "password": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserInviteRequest": {
+                "properties": {
+                    "email": {
+                        "type": "string"
+                    },
+                    "full_name": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserMFAFactorResponse": {
+                "properties": {
+                    "created_at": {
+                        "type": "string"
+                    },
+                    "friendly_name": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "example": "0",
+                        "type": "string"
+                    },
+                    "is_verified": {
+                        "type": "boolean"
+                    },
+                    "last_used_at": {
+                        "type": "string"
+                    },
+                    "type": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserMFAResponse": {
+                "properties": {
+                    "backup_codes_remaining": {
+                        "type": "integer"
+                    },
+                    "factors": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.UserMFAFactorResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserMFARevokeRequest": {
+                "properties": {
+                    "reason": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserPermissionsResponse": {
+                "properties": {
+                    "permissions": {
+                        "additionalProperties": {
+                            "items": {
+                                "type": "string"
+                            },
+                            "type": "array"
+                        },
+                        "type": "object"
+                    },
+                    "roles": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserResponse": {
+                "properties": {
+                    "avatar_url": {
+                        "type": "string"
+                    },
+                    "email": {
+                        "type": "string"
+                    },
+                    "full_name": {
+                        "type": "string"
+                    },
+                    "id": {
+                        "example": "0",
+                        "type": "string"
+                    },
+                    "status": {
+                        "$ref": "#/components/schemas/entity.UserStatus"
+                    },
+                    "updated_at": {
+                        "type": "string"
+                    },
+                    "version": {
+                        "description": "Version is sent back as If-Match or expected_version when updating the user.",
+                        "example": "0",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserRolesRequest": {
+                "properties": {
+                    "roles": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserRolesResponse": {
+                "properties": {
+                    "roles": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UserUpdateRequest": {
+                "properties": {
+                    "email": {
+                        "type": "string"
+                    },
+                    "expected_version": {
+                        "description": "ExpectedVersion is the version read with the user; the If-Match header takes precedence.",
+                        "example": "0",
+                        "type": "string"
+                    },
+                    "full_name": {
+                        "type": "string"
+                    },
+                    "mfa_required": {
+                        "type": "boolean"
+                    },
+                    "must_reset_password": {
+                        "description": "MustResetPassword and MFARequired force a password reset or an MFA enrollment at the\nuser's next login; omitted leaves them unchanged.",
+                        "type": "boolean"
+                    },
+                    "password": {
+                        "type": "string"
+                    },
+                    "status": {
+                        "$ref": "#/components/schemas/entity.UserStatus"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UsernameAvailabilityResponse": {
+                "properties": {
+                    "available": {
+                        "type": "boolean"
+                    },
+                    "reason": {
+                        "type": "string"
+                    },
+                    "username": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "inbound.UsersResponse": {
+                "properties": {
+                    "users": {
+                        "items": {
+                            "$ref": "#/components/schemas/inbound.UserResponse"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "jwt.JWK": {
+                "properties": {
+                    "alg": {
+                        "type": "string"
+                    },
+                    "crv": {
+                        "description": "Crv, X and Y are set on EC keys.",
+                        "type": "string"
+                    },
+                    "e": {
+                        "type": "string"
+                    },
+                    "kid": {
+                        "type": "string"
+                    },
+                    "kty": {
+                        "type": "string"
+                    },
+                    "n": {
+                        "description": "N and E are set on RSA keys.",
+                        "type": "string"
+                    },
+                    "use": {
+                        "type": "string"
+                    },
+                    "x": {
+                        "type": "string"
+                    },
+                    "y": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "jwt.JWKSet": {
+                "properties": {
+                    "keys": {
+                        "items": {
+                            "$ref": "#/components/schemas/jwt.JWK"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "router.errorResponse": {
+                "properties": {
+                    "error": {
+                        "additionalProperties": {
+                            "type": "string"
+                        },
+                        "type": "object"
+                    },
+                    "message": {
+                        "example": "example string message",
+                        "type": "string"
+                    },
+                    "reason": {
+                        "example": "LOGIN_ACCOUNT_LOCKED",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "router.successResponse": {
+                "allOf": [
+                    {
+                        "$ref": "#/components/schemas/data"
+                    }
+                ],
+                "properties": {
+                    "data": {
+                        "type": "object"
+                    },
+                    "message": {
+                        "example": "example string message",
+                        "type": "string"
+                    },
+                    "meta": {
+                        "type": "object"
+                    }
+                },
+                "type": "object"
+            },
+            "valueobject.JSONMap": {
+                "additionalProperties": {},
+                "type": "object"
+            }
+        },
+        "securitySchemes": {
+            "BearerAuth": {
+                "description": "Type \"Bearer\" followed by a space and JWT.",
+                "in": "header",
+                "name": "Authorization",
+                "type": "apiKey"
+            }
+        }
+    },
+    "info": {
+        "contact": {
+            "email": "support@gobite.com",
+            "name": "Contact Support",
+            "url": "https://gobite.com/contact"
+        },
+        "description": "{{escape .Description}}",
+        "license": {
+            "name": "MIT",
+            "url": "https://mit-license.org/"
+        },
+        "termsOfService": "https://gobite.com/terms",
+        "title": "{{.Title}}",
+        "version": "{{.Version}}"
+    },
+    "externalDocs": {
+        "description": "",
+        "url": ""
+    },
+    "paths": {
+        "/.well-known/gobite-configuration": {
+            "get": {
+                "description": "Lists the token and sign-in endpoints, supported grants, MFA methods, OAuth providers, password policy and enabled features, so client SDKs can configure themselves. Endpoints are absolute when modules.identity.metadata.base_url is set, otherwise relative to this host.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Deployment metadata"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Identity provider metadata",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Returns the public keys of RS256 or ES256 signed access tokens as a JWKS (RFC 7517), the active key first, so other services can verify tokens. Tokens name their key in the kid header.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/jwt.JWKSet"
+                                }
+                            }
+                        },
+                        "description": "Public keys"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Tokens are signed with a shared secret"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "JSON Web Key Set",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/admin/audit": {
+            "get": {
+                "description": "Returns audit events newest first with keyset pagination: pass meta.next_cursor of a page as cursor to get the next one; it is empty on the last page.",
+                "parameters": [
+                    {
+                        "description": "Filter by the user who performed the action",
+                        "in": "query",
+                        "name": "actor_id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Filter by the user or resource the action was performed on",
+                        "in": "query",
+                        "name": "subject_id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Filter by action, e.g. user.create",
+                        "in": "query",
+                        "name": "action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by the first segment of the action, e.g. user",
+                        "in": "query",
+                        "name": "resource_type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by occurred_at \u003e= date_from (RFC3339)",
+                        "in": "query",
+                        "name": "date_from",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by occurred_at \u003c= date_to (RFC3339)",
+                        "in": "query",
+                        "name": "date_to",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Cursor returned as meta.next_cursor by the previous page",
+                        "in": "query",
+                        "name": "cursor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Page size (max 100)",
+                        "in": "query",
+                        "name": "size",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Audit events"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid query parameters"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Search audit trail",
+                "tags": [
+                    "Audit",
+                    "Admin"
+                ]
+            }
+        },
+        "/api/v1/admin/audit/export": {
+            "get": {
+                "description": "Streams every audit event matching the filters as CSV, newest first, up to the configured row limit.",
+                "parameters": [
+                    {
+                        "description": "Filter by the user who performed the action",
+                        "in": "query",
+                        "name": "actor_id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Filter by the user or resource the action was performed on",
+                        "in": "query",
+                        "name": "subject_id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Filter by action, e.g. user.create",
+                        "in": "query",
+                        "name": "action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by the first segment of the action, e.g. user",
+                        "in": "query",
+                        "name": "resource_type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by occurred_at \u003e= date_from (RFC3339)",
+                        "in": "query",
+                        "name": "date_from",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by occurred_at \u003c= date_to (RFC3339)",
+                        "in": "query",
+                        "name": "date_to",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "text/csv": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "CSV file"
+                    },
+                    "400": {
+                        "content": {
+                            "text/csv": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid query parameters"
+                    },
+                    "401": {
+                        "content": {
+                            "text/csv": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "text/csv": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "422": {
+                        "content": {
+                            "text/csv": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "text/csv": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Export audit trail",
+                "tags": [
+                    "Audit",
+                    "Admin"
+                ]
+            }
+        },
+        "/api/v1/admin/jobs": {
+            "get": {
+                "description": "Returns every registered scheduled job and worker with its last run time, duration, and outcome, and the next run of scheduled jobs. The state is per instance: it covers only the instance that answers the request.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Background jobs"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List background jobs",
+                "tags": [
+                    "Admin"
+                ]
+            }
+        },
+        "/api/v1/admin/jobs/{name}/run": {
+            "post": {
+                "description": "Starts a run of a scheduled job in the background, outside its schedule, on the instance that answers the request; the returned state is that instance's. Workers cannot be run manually. The run is published as an audit event and refused while app.maintenance.admin_read_only is set.",
+                "parameters": [
+                    {
+                        "description": "Job name",
+                        "in": "path",
+                        "name": "name",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Job run started"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Job not found"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Job is running or cannot be run manually"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Run background job",
+                "tags": [
+                    "Admin"
+                ]
+            }
+        },
+        "/api/v1/audit/events": {
+            "get": {
+                "description": "Returns a paginated list of audit events, newest first.",
+                "parameters": [
+                    {
+                        "description": "Filter by reporting module, e.g. identity",
+                        "in": "query",
+                        "name": "module",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by action, e.g. auth.login",
+                        "in": "query",
+                        "name": "action",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by the user who performed the action",
+                        "in": "query",
+                        "name": "actor_id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Filter by the user the action was performed on",
+                        "in": "query",
+                        "name": "subject_id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Filter by correlation ID",
+                        "in": "query",
+                        "name": "correlation_id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by occurred_at \u003e= date_from (RFC3339)",
+                        "in": "query",
+                        "name": "date_from",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by occurred_at \u003c= date_to (RFC3339)",
+                        "in": "query",
+                        "name": "date_to",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Pagination size",
+                        "in": "query",
+                        "name": "size",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Pagination page",
+                        "in": "query",
+                        "name": "page",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Audit events"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid query parameters"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List audit events",
+                "tags": [
+                    "Audit"
+                ]
+            }
+        },
+        "/api/v1/audit/events/{id}": {
+            "get": {
+                "description": "Returns an audit event by ID.",
+                "parameters": [
+                    {
+                        "description": "Audit event ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Audit event"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid path parameter"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Audit event not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get audit event",
+                "tags": [
+                    "Audit"
+                ]
+            }
+        },
+        "/api/v1/identity/api-keys": {
+            "get": {
+                "description": "Returns the unrevoked API keys of the authenticated user with their scopes. Only the prefix of each key is shown.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "API keys"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List API keys",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            },
+            "post": {
+                "description": "Issues a key to send in the X-API-Key header instead of a bearer token. Scopes map objects to actions and must be allowed to the user; the key is also limited to what the user is allowed at the time of each request. The key is shown only in this response. API keys cannot manage API keys.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.APIKeyCreateRequest",
+                                        "summary": "request",
+                                        "description": "API key payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "API key payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Created API key"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Scope not allowed, impersonating, or recent authentication required"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Too many active API keys"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Create API key",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/api-keys/{id}": {
+            "delete": {
+                "description": "Revokes one API key of the authenticated user; requests sending it are rejected from then on.",
+                "parameters": [
+                    {
+                        "description": "API key ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid API key id"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "API key not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Revoke API key",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/email/change": {
+            "post": {
+                "description": "Sends a confirmation link to the new address. The current address stays active until the change is confirmed.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.EmailChangeRequest",
+                                        "summary": "request",
+                                        "description": "Change email payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Change email payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.successResponse"
+                                }
+                            }
+                        },
+                        "description": "Confirmation sent"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Recent authentication required (reason step_up_required)"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Email already registered"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Change email",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/email/change/confirm": {
+            "post": {
+                "description": "Switches the account to the new address and signs out every session.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.EmailChangeConfirmRequest",
+                                        "summary": "request",
+                                        "description": "Confirm email change payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Confirm email change payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid or expired token"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Email already registered"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Confirm email change",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/exports": {
+            "post": {
+                "description": "Queues a CSV or XLSX export of the users matching the filters, for exports too large to stream in one request. Poll the returned job until it completes.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.UserExportJobCreateRequest",
+                                        "summary": "request",
+                                        "description": "User export job payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "User export job payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Queued export job"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Queue user export",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/identity/exports/{id}": {
+            "get": {
+                "description": "Returns the status and progress of an export job queued by the caller, with a short-lived download link once it completed.",
+                "parameters": [
+                    {
+                        "description": "Export job ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Export job"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid export job ID"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Export job not found"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get user export job",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/identity/invite/accept": {
+            "post": {
+                "description": "Sets the password of an invited user using the emailed invitation token and activates the account.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.UserInviteAcceptRequest",
+                                        "summary": "request",
+                                        "description": "Accept invitation payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Accept invitation payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid or expired invitation token"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Accept invitation",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/login": {
+            "post": {
+                "description": "Validates credentials and returns access/refresh tokens. If MFA is required, a challenge is returned unless device_token is an active trusted device of the user. When an administrator requires a new password, password_reset_required is set and challenge_token is a password reset token; when an administrator requires MFA and the user has none, mfa_setup_required is set and challenge_token starts the login MFA setup.",
+                "parameters": [
+                    {
+                        "description": "Client type used to pick token lifetimes (e.g. web, mobile, service)",
+                        "in": "header",
+                        "name": "X-Client-Type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Name of the device signing in, shown in the session list and new sign-in alerts",
+                        "in": "header",
+                        "name": "X-Device-Name",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "CAPTCHA response token, required when the endpoint is listed in the captcha config",
+                        "in": "header",
+                        "name": "X-Captcha-Token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.LoginRequest",
+                                        "summary": "request",
+                                        "description": "Login payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Login payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Authentication result"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid credentials"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "CAPTCHA verification failed"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "429": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Too many attempts, see reason and Retry-After",
+                        "headers": {
+                            "Retry-After": {
+                                "description": "Seconds to wait before retrying",
+                                "schema": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error\" example:{\"message\":\"Login failed due to server error\",\"error\":{\"detail\":\"Please try again later\"}}"
+                    }
+                },
+                "summary": "Authenticate user",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/login/2fa": {
+            "post": {
+                "description": "Verifies the 2FA code for a login challenge and returns access/refresh tokens. With remember_device, a device token is also returned; sending it as device_token on later logins skips the MFA challenge until it expires. When an administrator requires a new password, password_reset_required is true and challenge_token is a password reset token instead.",
+                "parameters": [
+                    {
+                        "description": "Client type used to pick token lifetimes (e.g. web, mobile, service)",
+                        "in": "header",
+                        "name": "X-Client-Type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Name of the device signing in, shown in the session list and new sign-in alerts",
+                        "in": "header",
+                        "name": "X-Device-Name",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.Login2FARequest",
+                                        "summary": "request",
+                                        "description": "2FA login payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "2FA login payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Authentication result"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid MFA code"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Complete 2FA login",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/login/2fa/sms": {
+            "post": {
+                "description": "Sends a one-time code to the verified phone of the user behind the login challenge.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.Login2FASMSRequest",
+                                        "summary": "request",
+                                        "description": "SMS login code payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "SMS login code payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Masked destination"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid challenge session"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "429": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Too many SMS codes requested"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Send SMS login code",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/login/mfa-setup": {
+            "post": {
+                "description": "Generates a TOTP secret for a login that returned mfa_setup_required. The secret is kept on the login challenge, so the confirm endpoint takes the same challenge_token.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.LoginMFASetupRequest",
+                                        "summary": "request",
+                                        "description": "Login MFA setup payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Login MFA setup payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "TOTP secret"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid challenge session"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Factor name already used"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Start login MFA setup",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/login/mfa-setup/confirm": {
+            "post": {
+                "description": "Verifies the first TOTP code, enrolls the factor and returns access/refresh tokens.",
+                "parameters": [
+                    {
+                        "description": "Client type used to pick token lifetimes (e.g. web, mobile, service)",
+                        "in": "header",
+                        "name": "X-Client-Type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Name of the device signing in, shown in the session list and new sign-in alerts",
+                        "in": "header",
+                        "name": "X-Device-Name",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.LoginMFASetupConfirmRequest",
+                                        "summary": "request",
+                                        "description": "Login MFA setup confirm payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Login MFA setup confirm payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Authentication result"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid challenge session or code"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "429": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Too many attempts, see reason and Retry-After"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Confirm login MFA setup",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/logout": {
+            "post": {
+                "description": "Invalidates the provided refresh token and the access token of the request. Without refresh_token in the body, the refresh token cookie is used, which requires the X-CSRF-Token header and is cleared.",
+                "parameters": [
+                    {
+                        "description": "CSRF token, required when the refresh token is sent as a cookie",
+                        "in": "header",
+                        "name": "X-CSRF-Token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.LogoutRequest",
+                                        "summary": "request",
+                                        "description": "Logout payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Logout payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Logout",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/logout-all": {
+            "post": {
+                "description": "Invalidates all refresh tokens for the authenticated user, and every access token issued so far.",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Logout all sessions",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/mfa/backup_code/rotate": {
+            "post": {
+                "description": "Generates a new set of recovery codes for the authenticated user.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.BackupCodeRequest",
+                                        "summary": "request",
+                                        "description": "Backup code rotation payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Backup code rotation payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Backup codes rotated"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Recent authentication required (reason step_up_required)"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Rotate backup codes",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/mfa/factors": {
+            "get": {
+                "description": "Returns every MFA factor of the authenticated user, oldest first. A user may enroll several TOTP factors, each under its own friendly name.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "MFA factors"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List MFA factors",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/mfa/factors/{id}": {
+            "delete": {
+                "description": "Removes a TOTP or SMS factor of the authenticated user. The current password is always required; while another factor remains, an MFA code (TOTP or backup code) is required too and a missing one answers 401 with reason mfa_required. Removing the last factor turns MFA off, together with the backup codes and trusted devices.",
+                "parameters": [
+                    {
+                        "description": "MFA factor ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.MFAFactorDeleteRequest",
+                                        "summary": "request",
+                                        "description": "Delete payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Delete payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid password or MFA code"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Recent authentication required (reason step_up_required)"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "MFA factor not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "429": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Too many attempts, see reason and Retry-After"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Delete MFA factor",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            },
+            "patch": {
+                "description": "Changes the friendly name of a TOTP or SMS factor of the authenticated user. TOTP names are unique per user.",
+                "parameters": [
+                    {
+                        "description": "MFA factor ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.MFAFactorRenameRequest",
+                                        "summary": "request",
+                                        "description": "Rename payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Rename payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "MFA factor not found"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Name already used"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Rename MFA factor",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/mfa/recovery": {
+            "post": {
+                "description": "Emails a recovery link when the account exists and has MFA enabled. The response never reveals either.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.MFARecoveryRequest",
+                                        "summary": "request",
+                                        "description": "MFA recovery request payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "MFA recovery request payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.successResponse"
+                                }
+                            }
+                        },
+                        "description": "Recovery requested"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Request MFA recovery",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/mfa/recovery/complete": {
+            "post": {
+                "description": "Removes all MFA factors and backup codes for the account tied to the recovery token.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.MFARecoveryCompleteRequest",
+                                        "summary": "request",
+                                        "description": "MFA recovery complete payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "MFA recovery complete payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid recovery session"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Waiting period has not elapsed"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Complete MFA recovery",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/mfa/recovery/verify": {
+            "post": {
+                "description": "Verifies the emailed token and password (plus an optional backup code or a device that signed in before, which shorten the wait) and returns a recovery token usable once the waiting period ends.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.MFARecoveryVerifyRequest",
+                                        "summary": "request",
+                                        "description": "MFA recovery verify payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "MFA recovery verify payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Waiting period started"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid recovery session or credentials"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Verify MFA recovery",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/mfa/sms/confirm": {
+            "post": {
+                "description": "Verifies the SMS code and activates the MFA factor.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.SMSConfirmRequest",
+                                        "summary": "request",
+                                        "description": "SMS confirmation payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "SMS confirmation payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "SMS factor already exists"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Confirm SMS",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/mfa/sms/setup": {
+            "post": {
+                "description": "Sends a one-time code to the given phone number to start enrolling an SMS factor.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.SMSSetupRequest",
+                                        "summary": "request",
+                                        "description": "SMS setup payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "SMS setup payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "SMS setup result"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "SMS factor already exists"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error or SMS unavailable"
+                    },
+                    "429": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Too many SMS codes requested"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Setup SMS",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/mfa/totp/confirm": {
+            "post": {
+                "description": "Verifies the TOTP code and activates the MFA factor.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.TOTPConfirmRequest",
+                                        "summary": "request",
+                                        "description": "TOTP confirmation payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "TOTP confirmation payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "MFA factor not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Confirm TOTP",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/mfa/totp/setup": {
+            "post": {
+                "description": "Creates a TOTP factor and returns the shared secret and otpauth URI.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.TOTPSetupRequest",
+                                        "summary": "request",
+                                        "description": "TOTP setup payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "TOTP setup payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "TOTP setup result"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Setup TOTP",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/oauth/{provider}/authorize": {
+            "get": {
+                "description": "Returns the provider consent page URL and sets the HttpOnly flow cookie, which binds the sign-in to this browser. The browser is sent to the URL and the provider redirects back to the app's redirect_url with a code and state, which the app posts to the callback endpoint.",
+                "parameters": [
+                    {
+                        "description": "Provider name",
+                        "in": "path",
+                        "name": "provider",
+                        "required": true,
+                        "schema": {
+                            "enum": [
+                                "google",
+                                "github",
+                                "oidc"
+                            ],
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Authorization URL"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Provider not supported"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Start OAuth sign-in",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/oauth/{provider}/callback": {
+            "post": {
+                "description": "Exchanges the provider code for the user's identity, links or provisions the account on first login, and returns tokens or an MFA challenge. The request must carry the flow cookie set by the authorize endpoint in the same browser. A state from the link identity endpoint is refused here; it goes to the link callback.",
+                "parameters": [
+                    {
+                        "description": "Provider name",
+                        "in": "path",
+                        "name": "provider",
+                        "required": true,
+                        "schema": {
+                            "enum": [
+                                "google",
+                                "github",
+                                "oidc"
+                            ],
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Client type used to pick token lifetimes (e.g. web, mobile, service)",
+                        "in": "header",
+                        "name": "X-Client-Type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Name of the device signing in, shown in the session list and new sign-in alerts",
+                        "in": "header",
+                        "name": "X-Device-Name",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.OAuthCallbackRequest",
+                                        "summary": "request",
+                                        "description": "Code and state the provider redirected with"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Code and state the provider redirected with",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Authentication result"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid state, missing flow cookie or rejected code"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "No verified email or account not allowed"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Provider not supported"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Account exists and linking by email is disabled"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Complete OAuth sign-in",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/org-invitations/accept": {
+            "post": {
+                "description": "Makes the authenticated user a member of the organization named by the emailed invitation token, with the invited role. The token only works for the account it was sent to.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.OrgInviteAcceptRequest",
+                                        "summary": "request",
+                                        "description": "Accept invitation payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Accept invitation payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Joined organization"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Not a user session"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid or expired invitation token"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Already a member"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Accept organization invitation",
+                "tags": [
+                    "Identity",
+                    "Organizations"
+                ]
+            }
+        },
+        "/api/v1/identity/orgs": {
+            "get": {
+                "description": "Returns the organizations the authenticated user belongs to, with their role in each.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Organizations"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List my organizations",
+                "tags": [
+                    "Identity",
+                    "Organizations"
+                ]
+            },
+            "post": {
+                "description": "Creates an organization and makes the authenticated user its owner.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.OrgCreateRequest",
+                                        "summary": "request",
+                                        "description": "Organization payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Organization payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Created organization"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Slug already taken"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Create organization",
+                "tags": [
+                    "Identity",
+                    "Organizations"
+                ]
+            }
+        },
+        "/api/v1/identity/orgs/{id}": {
+            "delete": {
+                "description": "Deletes an organization with its memberships. Needs the owner role in it.",
+                "parameters": [
+                    {
+                        "description": "Organization ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid organization id"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Organization not found"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Delete organization",
+                "tags": [
+                    "Identity",
+                    "Organizations"
+                ]
+            },
+            "get": {
+                "description": "Returns an organization the caller is a member of.",
+                "parameters": [
+                    {
+                        "description": "Organization ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Organization"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid organization id"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Organization not found"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get organization",
+                "tags": [
+                    "Identity",
+                    "Organizations"
+                ]
+            },
+            "put": {
+                "description": "Renames an organization. Needs the owner or admin role in it.",
+                "parameters": [
+                    {
+                        "description": "Organization ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.OrgUpdateRequest",
+                                        "summary": "request",
+                                        "description": "Organization payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Organization payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Organization not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Update organization",
+                "tags": [
+                    "Identity",
+                    "Organizations"
+                ]
+            }
+        },
+        "/api/v1/identity/orgs/{id}/members": {
+            "get": {
+                "description": "Returns the members of an organization with their roles.",
+                "parameters": [
+                    {
+                        "description": "Organization ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Members"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid organization id"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List organization members",
+                "tags": [
+                    "Identity",
+                    "Organizations"
+                ]
+            },
+            "post": {
+                "description": "Emails an invitation to join the organization as owner, admin or member; the user joins once they accept it. The answer is the same whether or not the email belongs to an account. Only owners can invite owners.",
+                "parameters": [
+                    {
+                        "description": "Organization ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.OrgMemberAddRequest",
+                                        "summary": "request",
+                                        "description": "Member payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Member payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Organization not found"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Already a member"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Invite organization member",
+                "tags": [
+                    "Identity",
+                    "Organizations"
+                ]
+            }
+        },
+        "/api/v1/identity/orgs/{id}/members/{user_id}": {
+            "delete": {
+                "description": "Removes a member. Members can always remove themselves to leave; the last owner cannot leave.",
+                "parameters": [
+                    {
+                        "description": "Organization ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "User ID",
+                        "in": "path",
+                        "name": "user_id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid path parameter"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Member not found"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Last owner"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Remove organization member",
+                "tags": [
+                    "Identity",
+                    "Organizations"
+                ]
+            },
+            "put": {
+                "description": "Changes the role of a member. Only owners can grant or take away the owner role, and the last owner stays.",
+                "parameters": [
+                    {
+                        "description": "Organization ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "User ID",
+                        "in": "path",
+                        "name": "user_id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.OrgMemberUpdateRequest",
+                                        "summary": "request",
+                                        "description": "Role payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Role payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Member not found"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Last owner"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Update organization member",
+                "tags": [
+                    "Identity",
+                    "Organizations"
+                ]
+            }
+        },
+        "/api/v1/identity/orgs/{id}/token": {
+            "post": {
+                "description": "Issues an access token scoped to the organization through the org_id claim. The refresh token is unchanged and refreshes into an unscoped token.",
+                "parameters": [
+                    {
+                        "description": "Organization ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Client type used to pick the token lifetime (e.g. web, mobile, service)",
+                        "in": "header",
+                        "name": "X-Client-Type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Scoped access token"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid organization id"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Not a member"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Switch organization",
+                "tags": [
+                    "Identity",
+                    "Organizations"
+                ]
+            }
+        },
+        "/api/v1/identity/password/change": {
+            "post": {
+                "description": "Updates the user's password after validating the current password. Access tokens issued before the change stop working.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.PasswordChangeRequest",
+                                        "summary": "request",
+                                        "description": "Change password payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Change password payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Change password",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/password/forgot": {
+            "post": {
+                "description": "Sends password reset instructions to the provided email address.",
+                "parameters": [
+                    {
+                        "description": "CAPTCHA response token, required when the endpoint is listed in the captcha config",
+                        "in": "header",
+                        "name": "X-Captcha-Token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.PasswordForgotRequest",
+                                        "summary": "request",
+                                        "description": "Forgot password payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Forgot password payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "CAPTCHA verification failed"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Request password reset",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/password/reset": {
+            "post": {
+                "description": "Sets a new password using the provided reset token. Other outstanding reset links stop working and every session is signed out.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.PasswordResetRequest",
+                                        "summary": "request",
+                                        "description": "Reset password payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Reset password payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Reset token not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Reset password",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/profile": {
+            "get": {
+                "description": "Returns profile information for the authenticated user.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Profile result"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get profile",
+                "tags": [
+                    "Identity",
+                    "Profile"
+                ]
+            },
+            "put": {
+                "description": "Updates profile details for the authenticated user.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.UpdateProfileRequest",
+                                        "summary": "request",
+                                        "description": "Profile update payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Profile update payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Update profile",
+                "tags": [
+                    "Identity",
+                    "Profile"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/attributes": {
+            "get": {
+                "description": "Returns the free-form attributes apps keep on the authenticated user, e.g. preferences.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Attributes"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get profile attributes",
+                "tags": [
+                    "Identity",
+                    "Profile"
+                ]
+            },
+            "patch": {
+                "description": "Merges the given keys into the attributes of the authenticated user; a key set to null is removed. Keys and sizes are limited by modules.identity.attributes.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.ProfileAttributesRequest",
+                                        "summary": "request",
+                                        "description": "Attributes to set or remove"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Attributes to set or remove",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Stored attributes"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Update profile attributes",
+                "tags": [
+                    "Identity",
+                    "Profile"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/avatar": {
+            "put": {
+                "description": "Updates avatar for the authenticated user.",
+                "requestBody": {
+                    "content": {
+                        "application/x-www-form-urlencoded": {
+                            "schema": {
+                                "title": "avatar",
+                                "type": "file"
+                            }
+                        },
+                        "multipart/form-data": {
+                            "schema": {
+                                "type": "object"
+                            }
+                        }
+                    },
+                    "description": "Avatar image",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Update profile avatar",
+                "tags": [
+                    "Identity",
+                    "Profile"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/delete": {
+            "post": {
+                "description": "Confirms the current password and schedules the account to be anonymized after the grace period. Asking again keeps the original schedule.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.ProfileDeleteRequest",
+                                        "summary": "request",
+                                        "description": "Delete account payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Delete account payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Deletion scheduled"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized or invalid password"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Delete account",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/delete/cancel": {
+            "post": {
+                "description": "Withdraws a deletion scheduled through the delete account endpoint while its grace period is still running.",
+                "responses": {
+                    "204": {
+                        "description": "Deletion cancelled"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "No deletion is pending"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Cancel account deletion",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/export": {
+            "post": {
+                "description": "Assembles the user's profile, sessions and notifications into a zip archive of JSON files and returns the signed link to download it with the same account until expires_at.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Export link"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Export profile data",
+                "tags": [
+                    "Identity",
+                    "Profile"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/export/{id}": {
+            "get": {
+                "description": "Streams the zip archive of a profile data export through the signed link returned by the export. Only the user who requested it can download it, and only until it expires.",
+                "parameters": [
+                    {
+                        "description": "Export ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Link expiry from the signed link",
+                        "in": "query",
+                        "name": "exp",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Signature from the signed link",
+                        "in": "query",
+                        "name": "sig",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/zip": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Zip archive"
+                    },
+                    "401": {
+                        "content": {
+                            "application/zip": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/zip": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid link signature"
+                    },
+                    "404": {
+                        "content": {
+                            "application/zip": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Export not found or expired"
+                    },
+                    "422": {
+                        "content": {
+                            "application/zip": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/zip": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Download profile data export",
+                "tags": [
+                    "Identity",
+                    "Profile"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/identities": {
+            "get": {
+                "description": "Returns the OAuth and SAML identities the authenticated user can sign in with.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Linked identities"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List linked identities",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/identities/{id}": {
+            "delete": {
+                "description": "Removes one linked identity of the authenticated user. Password sign-in keeps working.",
+                "parameters": [
+                    {
+                        "description": "Identity ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid identity id"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Recent authentication required"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Identity not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Unlink identity",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/identities/{provider}/callback": {
+            "post": {
+                "description": "Called by the app with the code and a \"link.\" state the provider redirected back with, authenticated as the user that started the link and from the browser holding its flow cookie. Returns the linked identity.",
+                "parameters": [
+                    {
+                        "description": "Provider name",
+                        "in": "path",
+                        "name": "provider",
+                        "required": true,
+                        "schema": {
+                            "enum": [
+                                "google",
+                                "github",
+                                "oidc"
+                            ],
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.OAuthCallbackRequest",
+                                        "summary": "request",
+                                        "description": "Code and state the provider redirected with"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Code and state the provider redirected with",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Linked identity"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized, invalid state, missing flow cookie, revoked session or rejected code"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Provider not supported"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Identity or its email belongs to another account"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Complete identity link",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/identities/{provider}/link": {
+            "post": {
+                "description": "Returns the provider sign-in URL and sets the flow cookie binding the link to this browser and session. The state starts with \"link.\": when the provider redirects back with it, the app posts the code and state to the link callback instead of the sign-in callback; the SAML assertion consumer service links on its own. Linking is refused when the identity or its email belongs to another account, or once the session that started it is revoked.",
+                "parameters": [
+                    {
+                        "description": "Provider name",
+                        "in": "path",
+                        "name": "provider",
+                        "required": true,
+                        "schema": {
+                            "enum": [
+                                "google",
+                                "github",
+                                "oidc",
+                                "saml"
+                            ],
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Authorization URL"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Recent authentication required"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Provider not supported"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Link identity",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/logins": {
+            "get": {
+                "description": "Returns successful and failed sign-in attempts on the account, newest first, with the IP, user agent and approximate location. Pass next_before_id as before_id to page back.",
+                "parameters": [
+                    {
+                        "description": "Page size (default 20, max 100)",
+                        "in": "query",
+                        "name": "limit",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Only return entries older than this id",
+                        "in": "query",
+                        "name": "before_id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Login history"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid query parameters"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Login history",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/onboarding": {
+            "get": {
+                "description": "Returns the onboarding steps of the authenticated user (email verified, avatar set, MFA enabled, recovery codes saved) and overall completion.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Onboarding checklist"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get profile onboarding checklist",
+                "tags": [
+                    "Identity",
+                    "Profile"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/permissions": {
+            "get": {
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Permissions list"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get profile permissions",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/security/score": {
+            "get": {
+                "description": "Rates the account security of the authenticated user from 0 to 100 (MFA on, recovery codes saved, recent password, no long-idle sessions) with a recommendation for every failed check.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Security score"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get profile security score",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/settings/mfa": {
+            "get": {
+                "description": "Returns MFA settings for the authenticated user.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "MFA settings"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get profile MFA settings",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/username": {
+            "put": {
+                "description": "Sets the username of the authenticated user; an empty username removes it. Only available when usernames are enabled.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.UpdateProfileUsernameRequest",
+                                        "summary": "request",
+                                        "description": "Username payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Username payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Usernames are not enabled"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Username already taken"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Update profile username",
+                "tags": [
+                    "Identity",
+                    "Profile"
+                ]
+            }
+        },
+        "/api/v1/identity/profile/username/availability": {
+            "get": {
+                "description": "Reports whether the username is valid, not reserved, and not taken.",
+                "parameters": [
+                    {
+                        "description": "Username to check",
+                        "in": "query",
+                        "name": "username",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Availability"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Usernames are not enabled"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Check username availability",
+                "tags": [
+                    "Identity",
+                    "Profile"
+                ]
+            }
+        },
+        "/api/v1/identity/reauth": {
+            "post": {
+                "description": "Re-checks the password, and a TOTP or backup code when MFA is enabled, and returns an access token with a fresh auth_time. Sensitive operations answer 403 with reason step_up_required when the sign-in is older than modules.identity.step_up.max_age_minutes; retry them with this token.",
+                "parameters": [
+                    {
+                        "description": "Client type used to pick token lifetimes (e.g. web, mobile, service)",
+                        "in": "header",
+                        "name": "X-Client-Type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.ReauthRequest",
+                                        "summary": "request",
+                                        "description": "Re-authentication payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Re-authentication payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Fresh access token"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid password or MFA code, reason mfa_required when a code is missing"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Not a user session"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "429": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Too many attempts, see reason and Retry-After"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Step-up authentication",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new access/refresh token pair. Token lifetimes follow the client type given at sign-in.",
+                "parameters": [
+                    {
+                        "description": "CSRF token, required when the refresh token is sent as a cookie",
+                        "in": "header",
+                        "name": "X-CSRF-Token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Client type deciding whether the refresh token is kept in a cookie",
+                        "in": "header",
+                        "name": "X-Client-Type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Name of the device signing in, shown in the session list and new sign-in alerts",
+                        "in": "header",
+                        "name": "X-Device-Name",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.RefreshTokenRequest",
+                                        "summary": "request",
+                                        "description": "Refresh token payload; leave refresh_token out to use the refresh token cookie"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Refresh token payload; leave refresh_token out to use the refresh token cookie",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Token refresh result"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid refresh token"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Refresh access token",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/register": {
+            "post": {
+                "description": "Creates a new account and sends a verification email.",
+                "parameters": [
+                    {
+                        "description": "CAPTCHA response token, required when the endpoint is listed in the captcha config",
+                        "in": "header",
+                        "name": "X-Captcha-Token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.RegisterRequest",
+                                        "summary": "request",
+                                        "description": "Registration payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Registration payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "CAPTCHA verification failed"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Email already registered"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Register user",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/register/resend": {
+            "post": {
+                "description": "Sends a new verification email when an account exists for the provided address.",
+                "parameters": [
+                    {
+                        "description": "CAPTCHA response token, required when the endpoint is listed in the captcha config",
+                        "in": "header",
+                        "name": "X-Captcha-Token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.RegisterResendRequest",
+                                        "summary": "request",
+                                        "description": "Resend verification payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Resend verification payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Resend result"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "CAPTCHA verification failed"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Resend verification email",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/register/verify": {
+            "post": {
+                "description": "Confirms the user's email address using the provided verification token.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.EmailVerifyRequest",
+                                        "summary": "request",
+                                        "description": "Email verification payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Email verification payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Verification token not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Verify email",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/roles": {
+            "get": {
+                "description": "Returns every role with its member and direct permission counts.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Role list"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List roles",
+                "tags": [
+                    "Identity",
+                    "Management Roles"
+                ]
+            },
+            "post": {
+                "description": "Creates a role with its permissions.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.RoleCreateRequest",
+                                        "summary": "request",
+                                        "description": "Role payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Role payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Role already exists"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Create role",
+                "tags": [
+                    "Identity",
+                    "Management Roles"
+                ]
+            }
+        },
+        "/api/v1/identity/roles/{role}": {
+            "delete": {
+                "description": "Deletes a role, its permissions and every assignment of it. Protected roles cannot be deleted.",
+                "parameters": [
+                    {
+                        "description": "Role name",
+                        "in": "path",
+                        "name": "role",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden or protected role"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Role not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Delete role",
+                "tags": [
+                    "Identity",
+                    "Management Roles"
+                ]
+            },
+            "get": {
+                "description": "Returns a role with its direct and effective permissions and the IDs of its members.",
+                "parameters": [
+                    {
+                        "description": "Role name",
+                        "in": "path",
+                        "name": "role",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Role detail"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Role not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get role",
+                "tags": [
+                    "Identity",
+                    "Management Roles"
+                ]
+            },
+            "put": {
+                "description": "Replaces every permission granted directly to a role. Members keep the role.",
+                "parameters": [
+                    {
+                        "description": "Role name",
+                        "in": "path",
+                        "name": "role",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.RoleUpdateRequest",
+                                        "summary": "request",
+                                        "description": "Role payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Role payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Role not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Update role",
+                "tags": [
+                    "Identity",
+                    "Management Roles"
+                ]
+            }
+        },
+        "/api/v1/identity/roles/{role}/permissions": {
+            "delete": {
+                "description": "Revokes a permission from a role.",
+                "parameters": [
+                    {
+                        "description": "Role name",
+                        "in": "path",
+                        "name": "role",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Permission object",
+                        "in": "query",
+                        "name": "object",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Permission action",
+                        "in": "query",
+                        "name": "action",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden or last permission of a protected role"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Permission not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Remove role permission",
+                "tags": [
+                    "Identity",
+                    "Management Roles"
+                ]
+            },
+            "get": {
+                "description": "Returns the permissions granted directly to a role and its effective permissions including inherited roles.",
+                "parameters": [
+                    {
+                        "description": "Role name",
+                        "in": "path",
+                        "name": "role",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Role permissions"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Role not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get role permissions",
+                "tags": [
+                    "Identity",
+                    "Management Roles"
+                ]
+            },
+            "post": {
+                "description": "Grants a permission to a role.",
+                "parameters": [
+                    {
+                        "description": "Role name",
+                        "in": "path",
+                        "name": "role",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.RolePermissionRequest",
+                                        "summary": "request",
+                                        "description": "Permission payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Permission payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Role not found"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Permission already granted"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Add role permission",
+                "tags": [
+                    "Identity",
+                    "Management Roles"
+                ]
+            }
+        },
+        "/api/v1/identity/roles/{role}/users": {
+            "post": {
+                "description": "Assigns a role to a user.",
+                "parameters": [
+                    {
+                        "description": "Role name",
+                        "in": "path",
+                        "name": "role",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.RoleMemberRequest",
+                                        "summary": "request",
+                                        "description": "Member payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Member payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Role or user not found"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Role already assigned"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Assign role",
+                "tags": [
+                    "Identity",
+                    "Management Roles"
+                ]
+            }
+        },
+        "/api/v1/identity/roles/{role}/users/{user_id}": {
+            "delete": {
+                "description": "Removes a role from a user. Nobody can remove themselves from a protected role.",
+                "parameters": [
+                    {
+                        "description": "Role name",
+                        "in": "path",
+                        "name": "role",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "User ID",
+                        "in": "path",
+                        "name": "user_id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid path parameter"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden or protected role"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Role not assigned"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Unassign role",
+                "tags": [
+                    "Identity",
+                    "Management Roles"
+                ]
+            }
+        },
+        "/api/v1/identity/saml/acs": {
+            "post": {
+                "description": "Verifies the SAML response, links or provisions the account on first login, applies the configured role rules, and returns tokens or an MFA challenge. With a relay state from the link identity endpoint, the identity is linked to that user instead and returned.",
+                "parameters": [
+                    {
+                        "description": "Client type used to pick token lifetimes (e.g. web, mobile, service)",
+                        "in": "header",
+                        "name": "X-Client-Type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Name of the device signing in, shown in the session list and new sign-in alerts",
+                        "in": "header",
+                        "name": "X-Device-Name",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/x-www-form-urlencoded": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "string"
+                                    },
+                                    {
+                                        "title": "SAMLResponse",
+                                        "type": "string"
+                                    },
+                                    {
+                                        "title": "RelayState",
+                                        "type": "string"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Base64 SAML response | Relay state returned by the login endpoint",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Authentication result"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid relay state, missing flow cookie, or rejected or replayed response"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "No email asserted or account not allowed"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "SAML is not configured"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Account exists and linking by email is disabled"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Complete SAML sign-in",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/saml/login": {
+            "get": {
+                "description": "Returns the identity provider sign-in URL carrying an AuthnRequest and sets the flow cookie binding the sign-in to this browser. The identity provider posts its response to the assertion consumer service with the relay state, and the browser must send the flow cookie along.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Sign-in URL"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "SAML is not configured"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Start SAML sign-in",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/saml/metadata": {
+            "get": {
+                "description": "Returns the metadata XML to register this service with the SAML identity provider.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "text/xml": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Service provider metadata"
+                    },
+                    "404": {
+                        "content": {
+                            "text/xml": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "SAML is not configured"
+                    },
+                    "500": {
+                        "content": {
+                            "text/xml": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "SAML service provider metadata",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/service-accounts": {
+            "get": {
+                "description": "Returns the service accounts with their roles. Client secrets are never shown again after creation.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Service accounts"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List service accounts",
+                "tags": [
+                    "Identity",
+                    "Service Accounts"
+                ]
+            },
+            "post": {
+                "description": "Creates a service account holding the given roles and returns its client ID and secret. The secret is shown only in this response.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.ServiceAccountCreateRequest",
+                                        "summary": "request",
+                                        "description": "Service account payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Service account payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Created service account"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden or protected role"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error or unknown role"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Create service account",
+                "tags": [
+                    "Identity",
+                    "Service Accounts"
+                ]
+            }
+        },
+        "/api/v1/identity/service-accounts/{id}": {
+            "delete": {
+                "description": "Deletes a service account and its roles. Tokens already issued to it lose every permission at once.",
+                "parameters": [
+                    {
+                        "description": "Service account ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid service account id"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Service account not found"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Delete service account",
+                "tags": [
+                    "Identity",
+                    "Service Accounts"
+                ]
+            }
+        },
+        "/api/v1/identity/sessions": {
+            "get": {
+                "description": "Returns the unrevoked, unexpired sessions of the authenticated user with the device and IP that last used each one, and the device name and client type given at sign-in (X-Device-Name, X-Client-Type). A session id changes whenever its refresh token is rotated.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Active sessions"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List sessions",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/sessions/{id}": {
+            "delete": {
+                "description": "Invalidates one refresh token of the authenticated user, signing that device out.",
+                "parameters": [
+                    {
+                        "description": "Session ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid session id"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Session not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Revoke session",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/token": {
+            "post": {
+                "description": "OAuth2 client_credentials grant. The client authenticates with HTTP Basic or with client_id and client_secret in a form or JSON body. The token carries a client_id claim; no refresh token is issued.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.ClientCredentialsRequest",
+                                        "summary": "request",
+                                        "description": "Client credentials payload"
+                                    }
+                                ]
+                            }
+                        },
+                        "application/x-www-form-urlencoded": {
+                            "schema": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "description": "Client credentials payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Access token"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid client credentials"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error or unsupported grant_type"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Service account token",
+                "tags": [
+                    "Identity",
+                    "Authentication"
+                ]
+            }
+        },
+        "/api/v1/identity/trusted-devices": {
+            "get": {
+                "description": "Returns the devices of the authenticated user that skip the MFA challenge on login, remembered through remember_device on the 2FA login.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Trusted devices"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List trusted devices",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/trusted-devices/{id}": {
+            "delete": {
+                "description": "Forgets one trusted device of the authenticated user, so its next login asks for MFA again.",
+                "parameters": [
+                    {
+                        "description": "Trusted device ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid trusted device id"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Trusted device not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Revoke trusted device",
+                "tags": [
+                    "Identity",
+                    "Profile Security"
+                ]
+            }
+        },
+        "/api/v1/identity/users": {
+            "get": {
+                "description": "Returns a paginated list of users with optional search and status filters.",
+                "parameters": [
+                    {
+                        "description": "Search by email or full name",
+                        "in": "query",
+                        "name": "search",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Sort by email, full name and etc.",
+                        "in": "query",
+                        "name": "sort_by",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Sort order asc or desc",
+                        "in": "query",
+                        "name": "sort_order",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by statuses (1=unverified|2=active|3=banned|4=deleted)",
+                        "in": "query",
+                        "name": "status",
+                        "schema": {
+                            "items": {
+                                "type": "integer"
+                            },
+                            "type": "array"
+                        }
+                    },
+                    {
+                        "description": "Filter by created_at \u003e= date_from (RFC3339)",
+                        "in": "query",
+                        "name": "date_from",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by created_at \u003c= date_to (RFC3339)",
+                        "in": "query",
+                        "name": "date_to",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Pagination size",
+                        "in": "query",
+                        "name": "size",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Pagination page",
+                        "in": "query",
+                        "name": "page",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "User list"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid query parameters"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List users",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            },
+            "post": {
+                "description": "Creates a new user.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.UserCreateRequest",
+                                        "summary": "request",
+                                        "description": "User creation payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "User creation payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Email already registered"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Create user",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/identity/users-export": {
+            "get": {
+                "description": "Returns user list for export with optional filters. With format=csv or format=xlsx, or an Accept header asking for one of them, the users are streamed as a file download instead.",
+                "parameters": [
+                    {
+                        "description": "Response format: json (default), csv or xlsx",
+                        "in": "query",
+                        "name": "format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Search by email or full name",
+                        "in": "query",
+                        "name": "search",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by user status",
+                        "in": "query",
+                        "name": "status",
+                        "schema": {
+                            "items": {
+                                "type": "integer"
+                            },
+                            "type": "array"
+                        }
+                    },
+                    {
+                        "description": "Sort by email, full name and etc.",
+                        "in": "query",
+                        "name": "sort_by",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Sort order: asc, desc",
+                        "in": "query",
+                        "name": "sort_order",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by created_at \u003e= date_from (RFC3339)",
+                        "in": "query",
+                        "name": "date_from",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by created_at \u003c= date_to (RFC3339)",
+                        "in": "query",
+                        "name": "date_to",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": {
+                                "schema": {
+                                    "oneOf": [
+                                        {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/components/schemas/data"
+                                                }
+                                            ],
+                                            "properties": {
+                                                "data": {
+                                                    "type": "object"
+                                                },
+                                                "message": {
+                                                    "example": "example string message",
+                                                    "type": "string"
+                                                },
+                                                "meta": {
+                                                    "type": "object"
+                                                }
+                                            },
+                                            "type": "object"
+                                        },
+                                        {
+                                            "type": "string"
+                                        }
+                                    ]
+                                }
+                            }
+                        },
+                        "description": "CSV or XLSX file"
+                    },
+                    "400": {
+                        "content": {
+                            "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid query parameter"
+                    },
+                    "401": {
+                        "content": {
+                            "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    },
+                    "503": {
+                        "content": {
+                            "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Too many exports in progress"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Export users",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/identity/users-import": {
+            "post": {
+                "description": "Imports users in bulk.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "items": {
+                                            "$ref": "#/components/schemas/inbound.UserImportUserRequest"
+                                        },
+                                        "title": "request",
+                                        "type": "array"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "User import payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "User import result"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Email already registered"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    },
+                    "503": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Too many imports in progress"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Import users",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/identity/users-import/file": {
+            "post": {
+                "description": "Imports users from a CSV or XLSX file whose first row names the email, password, full_name and status columns. Invalid rows are skipped and reported; valid rows are saved in batches.",
+                "requestBody": {
+                    "content": {
+                        "application/x-www-form-urlencoded": {
+                            "schema": {
+                                "title": "file",
+                                "type": "file"
+                            }
+                        },
+                        "multipart/form-data": {
+                            "schema": {
+                                "type": "object"
+                            }
+                        }
+                    },
+                    "description": "CSV or XLSX file",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "User import result"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    },
+                    "503": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Too many imports in progress"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Import users from a file",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/identity/users/invite": {
+            "post": {
+                "description": "Creates a pending user and emails an invitation link to choose a password. Inviting an address whose account is still pending sends a new link and invalidates the previous one.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.UserInviteRequest",
+                                        "summary": "request",
+                                        "description": "User invitation payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "User invitation payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Email already registered"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Invite user",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/identity/users/{id}": {
+            "delete": {
+                "description": "Deletes a user by ID.",
+                "parameters": [
+                    {
+                        "description": "User ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid path parameter"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden, or recent authentication required (reason step_up_required)"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "User not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Delete user",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            },
+            "get": {
+                "description": "Returns user details for a given user ID.",
+                "parameters": [
+                    {
+                        "description": "User ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "User detail"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid path parameter"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "User not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get user detail",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            },
+            "put": {
+                "description": "Updates a user by ID. Send the version read with the user as If-Match or expected_version to refuse the update when someone else changed the user meanwhile.",
+                "parameters": [
+                    {
+                        "description": "User ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Expected user version",
+                        "in": "header",
+                        "name": "If-Match",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.UserUpdateRequest",
+                                        "summary": "request",
+                                        "description": "User update payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "User update payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "User not found"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Email already registered, or the user changed since the expected version (reason version_mismatch)"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Update user",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/identity/users/{id}/impersonate": {
+            "post": {
+                "description": "Issues a short-lived access token for another user so support staff can reproduce what they see. The token carries an \"act\" claim naming the caller and \"impersonated\": true; no refresh token is issued and the action is audit logged. Accounts holding any management, admin or SCIM permission cannot be impersonated.",
+                "parameters": [
+                    {
+                        "description": "User ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.UserImpersonateRequest",
+                                        "summary": "request",
+                                        "description": "Impersonation payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Impersonation payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Impersonation token"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden or privileged target"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "User not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Impersonate user",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/identity/users/{id}/mfa": {
+            "delete": {
+                "description": "Removes every MFA factor and backup code of a user after their identity was verified by support. The action is audited and the user is notified.",
+                "parameters": [
+                    {
+                        "description": "User ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.UserMFARevokeRequest",
+                                        "summary": "request",
+                                        "description": "Revocation payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Revocation payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "User not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Revoke user MFA",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            },
+            "get": {
+                "description": "Returns the MFA factors registered by a user. Every lookup is recorded in the audit log.",
+                "parameters": [
+                    {
+                        "description": "User ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "User MFA factors"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid path parameter"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "User not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get user MFA",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/identity/users/{id}/permissions": {
+            "get": {
+                "description": "Returns the roles and effective object/action matrix of a user, including permissions inherited through roles.",
+                "parameters": [
+                    {
+                        "description": "User ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "User permissions"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid path parameter"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "User not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get user effective permissions",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/identity/users/{id}/roles": {
+            "put": {
+                "description": "Replaces the roles assigned directly to a user. An empty list removes every role. The change is audited.",
+                "parameters": [
+                    {
+                        "description": "User ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.UserRolesRequest",
+                                        "summary": "request",
+                                        "description": "Roles payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Roles payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Assigned roles"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden or protected role"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "User not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error or unknown role"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Update user roles",
+                "tags": [
+                    "Identity",
+                    "Management Users"
+                ]
+            }
+        },
+        "/api/v1/notification/archives/replay": {
+            "post": {
+                "description": "Re-publishes the archived messages of a destination whose publish time falls in [from, to).",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.ArchiveReplayRequest",
+                                        "summary": "request",
+                                        "description": "Replay range payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Replay range payload",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Replay result"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Archive disabled"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Replay archived messages",
+                "tags": [
+                    "Notification",
+                    "Management Archives"
+                ]
+            }
+        },
+        "/api/v1/notification/categories": {
+            "get": {
+                "description": "Returns all notification categories.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Category list"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List notification categories",
+                "tags": [
+                    "Notification"
+                ]
+            }
+        },
+        "/api/v1/notification/device": {
+            "delete": {
+                "description": "Removes a device token for the authenticated user.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.RemoveDeviceRequest",
+                                        "summary": "request",
+                                        "description": "Device removal payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Device removal payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Remove device",
+                "tags": [
+                    "Notification"
+                ]
+            },
+            "post": {
+                "description": "Registers a device token for the authenticated user.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.RegisterDeviceRequest",
+                                        "summary": "request",
+                                        "description": "Device registration payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Device registration payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Register device",
+                "tags": [
+                    "Notification"
+                ]
+            }
+        },
+        "/api/v1/notification/inbound/sendgrid": {
+            "post": {
+                "description": "Webhook for SendGrid Inbound Parse (multipart form). Replies sent to a signed notification reply address are stored and published as notification.replied, but only when sent from the address the notification was emailed to. Posts are authenticated by their ECDSA signature once modules.notification.inbound.sendgrid.verification_key is set, and a signature seen before or signed outside modules.notification.inbound.sendgrid.tolerance_seconds is rejected; without a key the shared token in the query is checked instead, so no session is required.",
+                "parameters": [
+                    {
+                        "description": "Webhook token while no verification key is set (modules.notification.inbound.webhook_token)",
+                        "in": "query",
+                        "name": "token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Base64 ECDSA signature of the post (required when modules.notification.inbound.sendgrid.verification_key is set)",
+                        "in": "header",
+                        "name": "X-Twilio-Email-Event-Webhook-Signature",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Unix seconds the post was signed at (required with the signature)",
+                        "in": "header",
+                        "name": "X-Twilio-Email-Event-Webhook-Timestamp",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "multipart/form-data": {
+                            "schema": {
+                                "type": "object"
+                            }
+                        }
+                    }
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid payload"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid webhook token, signature or timestamp"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Inbound mail disabled"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Post already received"
+                    },
+                    "413": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Post too large"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Receive inbound mail from SendGrid",
+                "tags": [
+                    "Notification"
+                ]
+            }
+        },
+        "/api/v1/notification/inbound/ses": {
+            "post": {
+                "description": "Webhook for SES receipt rules delivered through SNS (JSON). Replies sent to a signed notification reply address are stored and published as notification.replied, but only when sent from the address the notification was emailed to. Messages are authenticated by their SNS signature and an allowlisted topic, and a message ID seen before or a message published outside modules.notification.inbound.ses.tolerance_seconds is rejected, so no session is required.",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "type": "object"
+                            }
+                        }
+                    }
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid payload"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid signature, topic or timestamp"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Inbound mail disabled"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Message already received"
+                    },
+                    "413": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Message too large"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Receive inbound mail from SES",
+                "tags": [
+                    "Notification"
+                ]
+            }
+        },
+        "/api/v1/notification/inbox": {
+            "get": {
+                "description": "Returns inbox notifications for the authenticated user.",
+                "parameters": [
+                    {
+                        "description": "Filter by status (all|read|unread)",
+                        "in": "query",
+                        "name": "status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Full-text search over notification content",
+                        "in": "query",
+                        "name": "search",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Pagination limit",
+                        "in": "query",
+                        "name": "limit",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Pagination offset",
+                        "in": "query",
+                        "name": "offset",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Notification list"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid query parameters"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List inbox",
+                "tags": [
+                    "Inbox"
+                ]
+            }
+        },
+        "/api/v1/notification/inbox/read-all": {
+            "put": {
+                "description": "Marks all inbox notifications as read for the authenticated user.",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Mark all inbox read",
+                "tags": [
+                    "Inbox"
+                ]
+            }
+        },
+        "/api/v1/notification/inbox/{id}": {
+            "delete": {
+                "description": "Soft deletes an inbox notification for the authenticated user.",
+                "parameters": [
+                    {
+                        "description": "Notification ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid notification id"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Notification not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Delete inbox",
+                "tags": [
+                    "Inbox"
+                ]
+            }
+        },
+        "/api/v1/notification/inbox/{id}/read": {
+            "patch": {
+                "description": "Marks an inbox notification as read.",
+                "parameters": [
+                    {
+                        "description": "Notification ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid notification id"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Notification not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Mark inbox read",
+                "tags": [
+                    "Inbox"
+                ]
+            }
+        },
+        "/api/v1/notification/settings": {
+            "get": {
+                "description": "Returns notification settings for the authenticated user.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Settings list"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List notification settings",
+                "tags": [
+                    "Notification"
+                ]
+            },
+            "put": {
+                "description": "Updates notification settings for the authenticated user. Send the version read with the settings as If-Match or expected_version to refuse the update when the settings changed meanwhile, e.g. from another device.",
+                "parameters": [
+                    {
+                        "description": "Expected settings version",
+                        "in": "header",
+                        "name": "If-Match",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.NotificationSettingsUpdateRequest",
+                                        "summary": "request",
+                                        "description": "Settings payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Settings payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Settings changed since the expected version (reason version_mismatch)"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Update notification settings",
+                "tags": [
+                    "Notification"
+                ]
+            }
+        },
+        "/api/v1/notification/stream": {
+            "get": {
+                "description": "Streams notification updates using Server-Sent Events (SSE). Emits \"notification\" for new items and \"read\" when any session marks items read. A client that falls too far behind is disconnected and should reload the inbox when it reconnects.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "text/event-stream": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "SSE stream"
+                    },
+                    "401": {
+                        "content": {
+                            "text/event-stream": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "text/event-stream": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "streaming unsupported"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Stream notifications",
+                "tags": [
+                    "Notification"
+                ]
+            }
+        },
+        "/api/v1/notification/templates/{trigger_key}/{channel}": {
+            "get": {
+                "description": "Returns the subject and body of a trigger's template on a channel, with the version a save of it expects.",
+                "parameters": [
+                    {
+                        "description": "Trigger key",
+                        "in": "path",
+                        "name": "trigger_key",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Channel (email, in_app)",
+                        "in": "path",
+                        "name": "channel",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Template"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Template not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Get notification template",
+                "tags": [
+                    "Notification",
+                    "Management Templates"
+                ]
+            },
+            "put": {
+                "description": "Replaces the subject and body of a trigger's template on a channel. Placeholders are checked against the trigger data schema and unknown ones are rejected. Send the version read with the template as If-Match or expected_version to refuse the save when someone else changed the template meanwhile.",
+                "parameters": [
+                    {
+                        "description": "Trigger key",
+                        "in": "path",
+                        "name": "trigger_key",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Channel (email, in_app)",
+                        "in": "path",
+                        "name": "channel",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Expected template version",
+                        "in": "header",
+                        "name": "If-Match",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/inbound.TemplateSaveRequest",
+                                        "summary": "request",
+                                        "description": "Template payload"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Template payload",
+                    "required": true
+                },
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid request body"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Trigger or template not found"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Template changed since the expected version (reason version_mismatch)"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Save notification template",
+                "tags": [
+                    "Notification",
+                    "Management Templates"
+                ]
+            }
+        },
+        "/api/v1/notification/triggers": {
+            "get": {
+                "description": "Returns every trigger key with the template data it expects.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "allOf": [
+                                        {
+                                            "$ref": "#/components/schemas/data"
+                                        }
+                                    ],
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        },
+                                        "message": {
+                                            "example": "example string message",
+                                            "type": "string"
+                                        },
+                                        "meta": {
+                                            "type": "object"
+                                        }
+                                    },
+                                    "type": "object"
+                                }
+                            }
+                        },
+                        "description": "Trigger list"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "List notification triggers",
+                "tags": [
+                    "Notification"
+                ]
+            }
+        },
+        "/api/v1/notification/unsubscribe": {
+            "post": {
+                "description": "Disables the email channel of a category using the signed link embedded in notification emails. Supports RFC 8058 one-click unsubscribe, so no session is required.",
+                "parameters": [
+                    {
+                        "description": "User ID",
+                        "in": "query",
+                        "name": "user_id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Category ID",
+                        "in": "query",
+                        "name": "category_id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Link expiry (unix seconds)",
+                        "in": "query",
+                        "name": "exp",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Link signature",
+                        "in": "query",
+                        "name": "sig",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid query parameters"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Invalid or expired link"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Category not found"
+                    },
+                    "422": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Validation error"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal server error"
+                    }
+                },
+                "summary": "Unsubscribe from category emails",
+                "tags": [
+                    "Notification"
+                ]
+            }
+        },
+        "/api/v1/notification/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket that pushes the same updates as the SSE stream, as JSON text frames of the form {\"event\":\"notification\"|\"read\",\"data\":{...}}. Browsers authenticate with the subprotocols [\"bearer\", \"\u003caccess token\u003e\"]. The server pings the client, which must answer with pongs. A client that falls too far behind is disconnected and should reload the inbox when it reconnects. The connection closes with code 1008 (policy violation) when the access token expires or is revoked; sign in or refresh before reconnecting.",
+                "responses": {
+                    "101": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Switching Protocols"
+                    },
+                    "401": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/router.errorResponse"
+                                }
+                            }
+                        },
+                        "description": "Origin not allowed or not a user token"
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "summary": "Notifications WebSocket",
+                "tags": [
+                    "Notification"
+                ]
+            }
+        }
+    },
     "openapi": "3.1.0"
 }`
 
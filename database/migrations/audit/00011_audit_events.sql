@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Security-relevant events reported by every module (logins, password and MFA changes,
+-- admin mutations). Append-only and without foreign keys, like identity_audit_logs.
+CREATE TABLE audit_events (
+    id BIGINT PRIMARY KEY,
+    event_id VARCHAR NOT NULL UNIQUE, -- envelope id, keeps redelivered messages from being stored twice
+    module VARCHAR NOT NULL, -- e.g. 'identity'
+    action VARCHAR NOT NULL, -- e.g. 'auth.login', 'user.update'
+    actor_id BIGINT NOT NULL DEFAULT 0, -- user who performed the action, 0 when unknown
+    subject_id BIGINT NOT NULL DEFAULT 0, -- user the action was performed on, 0 when none
+    ip VARCHAR NOT NULL DEFAULT '',
+    user_agent VARCHAR NOT NULL DEFAULT '',
+    correlation_id VARCHAR NOT NULL DEFAULT '',
+    metadata JSONB DEFAULT '{}'::JSONB,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_actor_id ON audit_events(actor_id);
+CREATE INDEX idx_audit_events_subject_id ON audit_events(subject_id);
+CREATE INDEX idx_audit_events_action ON audit_events(action);
+CREATE INDEX idx_audit_events_occurred_at ON audit_events(occurred_at);
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_events;
+-- +goose StatementEnd
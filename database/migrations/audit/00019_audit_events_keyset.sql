@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Serves the keyset pagination of the admin audit trail, which walks events newest first
+-- by (occurred_at, id).
+CREATE INDEX idx_audit_events_occurred_at_id ON audit_events(occurred_at DESC, id DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_audit_events_occurred_at_id;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Append-only trail of privileged actions performed on user accounts.
+-- No foreign keys on purpose: entries must outlive the users they reference.
+CREATE TABLE identity_audit_logs (
+    id BIGINT PRIMARY KEY,
+    actor_id BIGINT NOT NULL, -- user who performed the action
+    target_user_id BIGINT NOT NULL, -- user the action was performed on
+    action VARCHAR NOT NULL, -- e.g. 'user.mfa.inspect', 'user.mfa.revoke'
+    metadata JSONB DEFAULT '{}'::JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_identity_audit_logs_actor_id ON identity_audit_logs(actor_id);
+CREATE INDEX idx_identity_audit_logs_target_user_id ON identity_audit_logs(target_user_id);
+CREATE INDEX idx_identity_audit_logs_created_at ON identity_audit_logs(created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identity_audit_logs;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Optional PII minimization: a deterministic HMAC of the normalized email used for lookups,
+-- and the email sealed with the column cipher. Both stay NULL until the feature is enabled
+-- for a deployment; in strict mode the plaintext email is NULL and only the sealed copy remains.
+ALTER TABLE identity_users
+    ADD COLUMN email_hash VARCHAR DEFAULT NULL,
+    ADD COLUMN email_encrypted BYTEA DEFAULT NULL,
+    ALTER COLUMN email DROP NOT NULL,
+    ADD CONSTRAINT chk_identity_users_email_stored CHECK (email IS NOT NULL OR email_encrypted IS NOT NULL);
+
+CREATE UNIQUE INDEX idx_identity_users_email_hash ON identity_users(email_hash) WHERE email_hash IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_identity_users_email_hash;
+
+-- fails while a row keeps only the sealed email; turn strict mode off and restore the
+-- plaintext first
+ALTER TABLE identity_users
+    DROP CONSTRAINT IF EXISTS chk_identity_users_email_stored,
+    ALTER COLUMN email SET NOT NULL,
+    DROP COLUMN IF EXISTS email_encrypted,
+    DROP COLUMN IF EXISTS email_hash;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- When the login that started a refresh token chain happened. Rotation copies it forward,
+-- so sliding expiration can still enforce an absolute session lifetime.
+ALTER TABLE identity_refresh_tokens
+    ADD COLUMN session_started_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+UPDATE identity_refresh_tokens SET session_started_at = created_at;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE identity_refresh_tokens
+    DROP COLUMN IF EXISTS session_started_at;
+-- +goose StatementEnd
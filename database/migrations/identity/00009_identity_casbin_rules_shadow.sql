@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Candidate policy rules evaluated in shadow mode. Decisions are logged when they
+-- diverge from identity_casbin_rules but are never enforced.
+CREATE TABLE identity_casbin_rules_shadow (
+    id BIGSERIAL PRIMARY KEY,
+    ptype VARCHAR NOT NULL,
+    v0 VARCHAR,
+    v1 VARCHAR,
+    v2 VARCHAR,
+    v3 VARCHAR,
+    v4 VARCHAR,
+    v5 VARCHAR,
+
+    CONSTRAINT uq_identity_casbin_rules_shadow UNIQUE (ptype, v0, v1, v2, v3, v4, v5)
+);
+
+CREATE INDEX IF NOT EXISTS idx_identity_casbin_rules_shadow_ptype_v0 ON identity_casbin_rules_shadow(ptype, v0);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identity_casbin_rules_shadow;
+-- +goose StatementEnd
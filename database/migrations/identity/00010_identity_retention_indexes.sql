@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The retention scheduler deletes by age in id-ordered batches.
+CREATE INDEX IF NOT EXISTS idx_identity_refresh_tokens_expires_at ON identity_refresh_tokens(expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_identity_refresh_tokens_expires_at;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Account deletions requested by users themselves. The account is anonymized once
+-- scheduled_at has passed; the row stays afterwards as proof the request was carried out.
+CREATE TABLE identity_user_deletions (
+    user_id BIGINT PRIMARY KEY,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    scheduled_at TIMESTAMPTZ NOT NULL, -- end of the grace period
+    completed_at TIMESTAMPTZ DEFAULT NULL, -- NULL = still pending
+
+    CONSTRAINT fk_identity_user_deletions_user
+        FOREIGN KEY(user_id)
+        REFERENCES identity_users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_identity_user_deletions_pending ON identity_user_deletions(scheduled_at) WHERE completed_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identity_user_deletions;
+-- +goose StatementEnd
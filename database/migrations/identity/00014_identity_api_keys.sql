@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Long-lived API keys (personal access tokens). A key acts for its user through the
+-- X-API-Key header; its scopes are Casbin policies of the subject apikey/<id>.
+CREATE TABLE identity_api_keys (
+    id BIGINT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL, -- leading characters of the key, shown to tell keys apart
+    token VARCHAR NOT NULL, -- Store a hash, not the raw key
+    expires_at TIMESTAMPTZ DEFAULT NULL, -- NULL = never expires
+    last_used_at TIMESTAMPTZ DEFAULT NULL,
+    revoked_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_identity_api_keys_user
+        FOREIGN KEY(user_id)
+        REFERENCES identity_users(id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_identity_api_keys_token ON identity_api_keys(token);
+CREATE INDEX idx_identity_api_keys_user_id ON identity_api_keys(user_id) WHERE revoked_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identity_api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Non-human principals that authenticate with the OAuth2 client_credentials grant. Their
+-- tokens carry the subject client/<client_id>, which is assigned roles like any user.
+CREATE TABLE identity_service_accounts (
+    id BIGINT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    client_id VARCHAR(64) NOT NULL,
+    secret VARCHAR NOT NULL, -- Store a hash, not the raw client secret
+    created_by BIGINT NOT NULL,
+    last_used_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_identity_service_accounts_client_id ON identity_service_accounts(client_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identity_service_accounts;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Successful and failed sign-ins, shown to the user so they can spot activity they do not
+-- recognise. Attempts that matched no account (unknown or throttled) have user_id 0. The
+-- location is filled in afterwards from the IP.
+CREATE TABLE identity_login_events (
+    id BIGINT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    success BOOLEAN NOT NULL,
+    method VARCHAR NOT NULL, -- 'password', 'oauth' or 'mfa'
+    failure_reason VARCHAR NOT NULL DEFAULT '', -- e.g. 'invalid_password', 'account_locked', empty on success
+    ip VARCHAR NOT NULL DEFAULT '',
+    user_agent VARCHAR NOT NULL DEFAULT '',
+    country VARCHAR NOT NULL DEFAULT '',
+    region VARCHAR NOT NULL DEFAULT '',
+    city VARCHAR NOT NULL DEFAULT '',
+    enriched_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_identity_login_events_user_id ON identity_login_events(user_id, id);
+CREATE INDEX idx_identity_login_events_created_at ON identity_login_events(created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identity_login_events;
+-- +goose StatementEnd
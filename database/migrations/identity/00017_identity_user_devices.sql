@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Devices a user signed in from, compared on every login to alert the user about a sign-in
+-- from an unknown device or country. The fingerprint is an HMAC of the client type and
+-- user agent; country is the last one the device was seen in.
+CREATE TABLE identity_user_devices (
+    id BIGINT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    fingerprint VARCHAR NOT NULL,
+    user_agent VARCHAR NOT NULL DEFAULT '',
+    last_ip VARCHAR NOT NULL DEFAULT '',
+    country VARCHAR NOT NULL DEFAULT '',
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_identity_user_devices_user_fingerprint UNIQUE (user_id, fingerprint)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identity_user_devices;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Hashes of passwords a user replaced, newest last, so the password policy can reject
+-- reusing them. Trimmed to the configured history size on every change.
+CREATE TABLE identity_password_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    password VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_identity_password_history_user_id ON identity_password_history(user_id, id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identity_password_history;
+-- +goose StatementEnd
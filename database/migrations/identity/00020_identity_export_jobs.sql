@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- User exports too large to stream in a request. A worker writes the file to object storage
+-- and keeps rows_written current, so the requester can poll the job until it completes.
+CREATE TABLE identity_export_jobs (
+    id BIGINT PRIMARY KEY,
+    requested_by BIGINT NOT NULL,
+    format VARCHAR(8) NOT NULL, -- csv, xlsx
+    filters JSONB NOT NULL DEFAULT '{}'::jsonb,
+    status SMALLINT NOT NULL DEFAULT 1, -- 1 = pending, 2 = running, 3 = completed, 4 = failed
+    rows_total BIGINT NOT NULL DEFAULT 0, -- matching users when the worker started
+    rows_written BIGINT NOT NULL DEFAULT 0,
+    object_key VARCHAR NOT NULL DEFAULT '', -- set once completed
+    error VARCHAR NOT NULL DEFAULT '', -- set once failed
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ DEFAULT NULL,
+
+    CONSTRAINT fk_identity_export_jobs_requested_by
+        FOREIGN KEY(requested_by)
+        REFERENCES identity_users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_identity_export_jobs_requested_by ON identity_export_jobs(requested_by, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identity_export_jobs;
+-- +goose StatementEnd
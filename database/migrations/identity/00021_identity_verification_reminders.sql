@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Verification reminders sent to users who registered but never verified their email,
+-- so each user gets a bounded number of them at a fixed spacing.
+CREATE TABLE identity_verification_reminders (
+    user_id BIGINT PRIMARY KEY,
+    sent_count INT NOT NULL DEFAULT 0,
+    last_sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_identity_verification_reminders_user
+        FOREIGN KEY(user_id)
+        REFERENCES identity_users(id)
+        ON DELETE CASCADE
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identity_verification_reminders;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Devices a user chose to remember when completing an MFA login. A login presenting the
+-- device token of an active entry skips the MFA challenge until the entry expires.
+CREATE TABLE identity_trusted_devices (
+    id BIGINT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    token VARCHAR NOT NULL, -- Store a hash, not the raw device token
+    user_agent VARCHAR NOT NULL DEFAULT '',
+    ip VARCHAR NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ DEFAULT NULL,
+    revoked_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_identity_trusted_devices_user
+        FOREIGN KEY(user_id)
+        REFERENCES identity_users(id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_identity_trusted_devices_token ON identity_trusted_devices(token);
+CREATE INDEX idx_identity_trusted_devices_user_id ON identity_trusted_devices(user_id) WHERE revoked_at IS NULL;
+CREATE INDEX idx_identity_trusted_devices_expires_at ON identity_trusted_devices(expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identity_trusted_devices;
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Organizations are the tenants of the API. Membership lives here; the role a member holds
+-- in an organization is a Casbin g2 rule (user, org role, organization id).
+CREATE TABLE identity_organizations (
+    id BIGINT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(64) NOT NULL,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_identity_organizations_slug ON identity_organizations(slug);
+
+CREATE TRIGGER trg_identity_organizations_set_updated_at
+BEFORE UPDATE ON identity_organizations
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE TABLE identity_organization_members (
+    organization_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (organization_id, user_id),
+
+    CONSTRAINT fk_identity_organization_members_organization
+        FOREIGN KEY(organization_id)
+        REFERENCES identity_organizations(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_identity_organization_members_user
+        FOREIGN KEY(user_id)
+        REFERENCES identity_users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_identity_organization_members_user_id ON identity_organization_members(user_id);
+
+-- Permissions of the built-in organization roles, valid in every organization ('*').
+INSERT INTO identity_casbin_rules (ptype, v0, v1, v2, v3)
+VALUES
+    ('p2', 'org:owner', '*', '*', '*'),
+    ('p2', 'org:admin', '*', 'identity:org', 'read'),
+    ('p2', 'org:admin', '*', 'identity:org', 'update'),
+    ('p2', 'org:admin', '*', 'identity:org:members', '*'),
+    ('p2', 'org:member', '*', 'identity:org', 'read'),
+    ('p2', 'org:member', '*', 'identity:org:members', 'read')
+ON CONFLICT DO NOTHING;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM identity_casbin_rules WHERE ptype IN ('p2', 'g2');
+DROP TABLE IF EXISTS identity_organization_members;
+DROP TABLE IF EXISTS identity_organizations;
+-- +goose StatementEnd
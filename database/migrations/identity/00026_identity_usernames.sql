@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Optional username a user can sign in with instead of the email. Stored lowercase; unique
+-- across every account, deleted ones included, until the account is anonymized.
+ALTER TABLE identity_users ADD COLUMN username VARCHAR DEFAULT NULL;
+
+CREATE UNIQUE INDEX idx_identity_users_lower_case_username ON identity_users (lower(username)) WHERE username IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_identity_users_lower_case_username;
+ALTER TABLE identity_users DROP COLUMN IF EXISTS username;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Free-form profile attributes downstream apps keep on a user, e.g. UI preferences. A flat
+-- JSON object whose keys and sizes are checked by the application.
+ALTER TABLE identity_users ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE identity_users DROP COLUMN IF EXISTS attributes;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Flags an administrator sets to hold a user's next login: until the password is reset, or
+-- until a second factor is enrolled. A successful reset clears must_reset_password.
+ALTER TABLE identity_users ADD COLUMN must_reset_password BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE identity_users ADD COLUMN mfa_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE identity_users DROP COLUMN IF EXISTS mfa_required;
+ALTER TABLE identity_users DROP COLUMN IF EXISTS must_reset_password;
+-- +goose StatementEnd
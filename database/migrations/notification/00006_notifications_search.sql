@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Full-text search over the inbox. Subject/title and body keys in data rank first,
+-- every other string value in data still matches. 'simple' keeps it language-agnostic.
+ALTER TABLE notifications
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', COALESCE(data->>'subject', '') || ' ' || COALESCE(data->>'title', '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(data->>'body', '')), 'B') ||
+        setweight(jsonb_to_tsvector('simple', data, '["string"]'), 'C')
+    ) STORED;
+
+CREATE INDEX idx_notifications_search_vector ON notifications USING GIN (search_vector);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifications_search_vector;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
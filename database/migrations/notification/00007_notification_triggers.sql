@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Registry of trigger keys known to the code. The service upserts it on startup,
+-- so templates can only be created for triggers that actually exist.
+CREATE TABLE notification_triggers (
+    key VARCHAR PRIMARY KEY, -- e.g. 'password_reset'
+    description VARCHAR NOT NULL,
+    data_schema JSONB NOT NULL DEFAULT '{}'::JSONB, -- expected template data: { "field": { "type", "required", "description" } }
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER trg_notification_triggers_set_updated_at
+BEFORE UPDATE ON notification_triggers
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+-- Seed the keys already in use so the foreign key below holds for existing templates.
+INSERT INTO notification_triggers (key, description) VALUES
+    ('email_verify', 'Asks a new user to confirm their email address'),
+    ('password_reset', 'Sends a password reset link'),
+    ('user_welcome', 'Welcomes a newly registered user in the inbox'),
+    ('mfa_revoked', 'Tells a user an administrator removed their two-factor authentication');
+
+ALTER TABLE notification_templates
+    ADD CONSTRAINT fk_notification_templates_trigger
+        FOREIGN KEY(trigger_key) REFERENCES notification_triggers(key) ON UPDATE CASCADE;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notification_templates DROP CONSTRAINT IF EXISTS fk_notification_templates_trigger;
+
+DROP TABLE IF EXISTS notification_triggers;
+-- +goose StatementEnd
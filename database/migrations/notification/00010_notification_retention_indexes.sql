@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The retention scheduler deletes by age in id-ordered batches.
+CREATE INDEX IF NOT EXISTS idx_notifications_created_at ON notifications(created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifications_created_at;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Email replies to a notification, received through the inbound mail webhook. The reply
+-- address is signed per notification, so every row belongs to the notification's user.
+CREATE TABLE notification_replies (
+    id BIGINT PRIMARY KEY,
+    notification_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    provider VARCHAR NOT NULL, -- e.g. 'sendgrid', 'ses'
+    message_id VARCHAR NOT NULL, -- provider message id, keeps webhook retries from being stored twice
+    from_address VARCHAR NOT NULL,
+    subject VARCHAR NOT NULL DEFAULT '',
+    body TEXT NOT NULL DEFAULT '', -- plain text reply with the quoted original removed
+    metadata JSONB NOT NULL DEFAULT '{}'::JSONB,
+    received_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_notification_replies_provider_message UNIQUE (provider, message_id),
+    CONSTRAINT fk_notification_replies_notification
+        FOREIGN KEY(notification_id) REFERENCES notifications(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notification_replies_notification_id ON notification_replies(notification_id);
+CREATE INDEX idx_notification_replies_user_id ON notification_replies(user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_replies;
+-- +goose StatementEnd
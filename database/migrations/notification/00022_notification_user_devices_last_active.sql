@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Device tokens not seen for a while are pruned by the retention scheduler.
+CREATE INDEX idx_notification_user_devices_last_active_at ON notification_user_devices(last_active_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notification_user_devices_last_active_at;
+-- +goose StatementEnd
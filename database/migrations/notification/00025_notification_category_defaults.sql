@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Default settings matrix: whether a channel of a category is on for users without a row in
+-- notification_user_settings. A missing default means on.
+CREATE TABLE notification_category_defaults (
+    category_id BIGINT NOT NULL,
+    channel SMALLINT NOT NULL, -- (0: unknwon, 1: In-App, 2: Email, 3: SMS, 4: Push)
+    is_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (category_id, channel),
+
+    CONSTRAINT fk_notification_category_defaults_category
+        FOREIGN KEY(category_id) REFERENCES notification_categories(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER trg_notification_category_defaults_set_updated_at
+BEFORE UPDATE ON notification_category_defaults
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_timestamp();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_category_defaults;
+-- +goose StatementEnd
@@ -0,0 +1,73 @@
+-- ***** ***** *****
+-- SELECT DATA
+-- ***** ***** *****
+
+-- name: GetAuditEventByID :one
+SELECT id, event_id, module, action, actor_id, subject_id, ip, user_agent, correlation_id, metadata, occurred_at
+FROM audit_events
+WHERE id = @id;
+
+-- name: GetAuditEventFilter :many
+SELECT id, event_id, module, action, actor_id, subject_id, ip, user_agent, correlation_id, metadata, occurred_at
+FROM audit_events
+WHERE
+    (NOT @filter_by_module::boolean OR module = @module::varchar)
+    AND (NOT @filter_by_action::boolean OR action = @action::varchar)
+    AND (NOT @filter_by_actor::boolean OR actor_id = @actor_id::bigint)
+    AND (NOT @filter_by_subject::boolean OR subject_id = @subject_id::bigint)
+    AND (NOT @filter_by_correlation::boolean OR correlation_id = @correlation_id::varchar)
+    AND (NOT @filter_by_date_from::boolean OR occurred_at >= @date_from::timestamptz)
+    AND (NOT @filter_by_date_to::boolean OR occurred_at <= @date_to::timestamptz)
+ORDER BY occurred_at DESC, id DESC
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: GetAuditEventSearch :many
+SELECT id, event_id, module, action, actor_id, subject_id, ip, user_agent, correlation_id, metadata, occurred_at
+FROM audit_events
+WHERE
+    (NOT @filter_by_actor::boolean OR actor_id = @actor_id::bigint)
+    AND (NOT @filter_by_subject::boolean OR subject_id = @subject_id::bigint)
+    AND (NOT @filter_by_action::boolean OR action = @action::varchar)
+    AND (NOT @filter_by_resource_type::boolean OR split_part(action, '.', 1) = @resource_type::varchar)
+    AND (NOT @filter_by_date_from::boolean OR occurred_at >= @date_from::timestamptz)
+    AND (NOT @filter_by_date_to::boolean OR occurred_at <= @date_to::timestamptz)
+    AND (NOT @filter_by_cursor::boolean OR (occurred_at, id) < (@cursor_occurred_at::timestamptz, @cursor_id::bigint))
+ORDER BY occurred_at DESC, id DESC
+LIMIT @page_limit;
+
+-- name: CountAuditEventFilter :one
+SELECT COUNT(id)
+FROM audit_events
+WHERE
+    (NOT @filter_by_module::boolean OR module = @module::varchar)
+    AND (NOT @filter_by_action::boolean OR action = @action::varchar)
+    AND (NOT @filter_by_actor::boolean OR actor_id = @actor_id::bigint)
+    AND (NOT @filter_by_subject::boolean OR subject_id = @subject_id::bigint)
+    AND (NOT @filter_by_correlation::boolean OR correlation_id = @correlation_id::varchar)
+    AND (NOT @filter_by_date_from::boolean OR occurred_at >= @date_from::timestamptz)
+    AND (NOT @filter_by_date_to::boolean OR occurred_at <= @date_to::timestamptz);
+
+-- name: CountAuditEventBefore :one
+SELECT COUNT(id) FROM audit_events WHERE created_at < @before::timestamptz;
+
+-- ***** ***** *****
+-- INSERT DATA
+-- ***** ***** *****
+
+-- name: CreateAuditEvent :execrows
+INSERT INTO audit_events (id, event_id, module, action, actor_id, subject_id, ip, user_agent, correlation_id, metadata, occurred_at)
+VALUES (@id, @event_id, @module, @action, @actor_id, @subject_id, @ip, @user_agent, @correlation_id, @metadata, @occurred_at)
+ON CONFLICT (event_id) DO NOTHING;
+
+-- ***** ***** *****
+-- DELETE DATA
+-- ***** ***** *****
+
+-- name: DeleteAuditEventBefore :execrows
+DELETE FROM audit_events
+WHERE id IN (
+    SELECT id FROM audit_events
+    WHERE created_at < @before::timestamptz
+    ORDER BY id ASC
+    LIMIT @page_limit
+);
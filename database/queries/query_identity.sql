@@ -3,15 +3,47 @@
 -- ***** ***** *****
 
 -- name: GetIdentityUserLoginInfo :one
-SELECT u.id, u.email, u.status, c.password, EXISTS (SELECT 1 FROM identity_mfa_factors m WHERE m.user_id = u.id AND m.is_verified = TRUE) AS has_mfa
+SELECT u.id, u.email, u.email_encrypted, u.status, c.password, EXISTS (SELECT 1 FROM identity_mfa_factors m WHERE m.user_id = u.id AND m.is_verified = TRUE) AS has_mfa, u.must_reset_password, u.mfa_required
 FROM identity_users AS u
 JOIN identity_user_credentials AS c ON u.id = c.user_id
 WHERE 
     lower(u.email) = lower(@email) 
     AND u.deleted_at IS NULL;
 
+-- name: GetIdentityUserLoginInfoByEmailHash :one
+SELECT u.id, u.email, u.email_encrypted, u.status, c.password, EXISTS (SELECT 1 FROM identity_mfa_factors m WHERE m.user_id = u.id AND m.is_verified = TRUE) AS has_mfa, u.must_reset_password, u.mfa_required
+FROM identity_users AS u
+JOIN identity_user_credentials AS c ON u.id = c.user_id
+WHERE 
+    u.email_hash = @email_hash
+    AND u.deleted_at IS NULL;
+
+-- name: GetIdentityUserLoginInfoByUsername :one
+SELECT u.id, u.email, u.email_encrypted, u.status, c.password, EXISTS (SELECT 1 FROM identity_mfa_factors m WHERE m.user_id = u.id AND m.is_verified = TRUE) AS has_mfa, u.must_reset_password, u.mfa_required
+FROM identity_users AS u
+JOIN identity_user_credentials AS c ON u.id = c.user_id
+WHERE 
+    lower(u.username) = lower(@username) 
+    AND u.deleted_at IS NULL;
+
+-- name: GetIdentityUserLoginInfoByConnection :one
+SELECT u.id, u.email, u.email_encrypted, u.status, c.password, EXISTS (SELECT 1 FROM identity_mfa_factors m WHERE m.user_id = u.id AND m.is_verified = TRUE) AS has_mfa, u.must_reset_password, u.mfa_required
+FROM identity_user_connections AS uc
+JOIN identity_users AS u ON u.id = uc.user_id
+JOIN identity_user_credentials AS c ON u.id = c.user_id
+WHERE 
+    uc.provider = @provider
+    AND uc.provider_user_id = @provider_user_id
+    AND u.deleted_at IS NULL;
+
+-- name: GetIdentityUserConnectionsByUserID :many
+SELECT id, provider, provider_user_id, created_at
+FROM identity_user_connections
+WHERE user_id = @user_id
+ORDER BY created_at DESC, id DESC;
+
 -- name: GetIdentityUserCredentialInfo :one
-SELECT u.id, u.email, u.status, c.password
+SELECT u.id, u.email, u.email_encrypted, u.status, c.password, c.updated_at
 FROM identity_users AS u
 JOIN identity_user_credentials AS c ON u.id = c.user_id
 WHERE
@@ -19,26 +51,50 @@ WHERE
     AND u.deleted_at IS NULL;
 
 -- name: GetIdentityUserByEmail :one
-SELECT id, email, full_name, avatar_url, status 
+SELECT id, email, email_encrypted, full_name, avatar_url, status 
 FROM identity_users 
 WHERE 
     lower(email) = lower(@email)
     AND deleted_at IS NULL;
 
 -- name: GetIdentityUserByEmailIncludeDeleted :one
-SELECT id, email, full_name, avatar_url, status 
+SELECT id, email, email_encrypted, full_name, avatar_url, status 
 FROM identity_users
 WHERE 
     lower(email) = lower(@email);
 
+-- name: GetIdentityUserByEmailHash :one
+SELECT id, email, email_encrypted, full_name, avatar_url, status 
+FROM identity_users 
+WHERE 
+    email_hash = @email_hash
+    AND deleted_at IS NULL;
+
+-- name: GetIdentityUserByEmailHashIncludeDeleted :one
+SELECT id, email, email_encrypted, full_name, avatar_url, status 
+FROM identity_users
+WHERE 
+    email_hash = @email_hash;
+
 -- name: GetIdentityUserByEmailsIncludeDeleted :many
-SELECT id, email, full_name, avatar_url, status  
+SELECT id, email, email_hash, full_name, avatar_url, status  
 FROM identity_users
 WHERE 
-    email ILIKE ANY(@emails::varchar[]);
+    email ILIKE ANY(@emails::varchar[])
+    OR email_hash = ANY(@email_hashes::varchar[]);
+
+-- name: GetIdentityUserPlaintextEmails :many
+-- Anonymized rows drop their lookup hash, so only live and restorable accounts are listed.
+SELECT id, email
+FROM identity_users
+WHERE
+    email IS NOT NULL
+    AND (deleted_at IS NULL OR email_hash IS NOT NULL)
+ORDER BY id ASC
+LIMIT @batch_size;
 
 -- name: GetIdentityChallengeUserByTokenPurpose :one
-SELECT u.id AS user_id, u.status, u.email, c.id, c.token, c.purpose, c.metadata
+SELECT u.id AS user_id, u.status, u.email, u.email_encrypted, c.id, c.token, c.purpose, c.metadata
 FROM identity_challenges c
 JOIN identity_users AS u ON u.id = c.user_id
 WHERE 
@@ -48,13 +104,22 @@ WHERE
     AND c.expires_at > NOW();
 
 -- name: GetIdentityUserRefreshToken :one
-SELECT rt.id, rt.user_id, rt.token, rt.expires_at, rt.revoked, rt.replaced_by_token_id, u.email, u.status AS user_status
+SELECT rt.id, rt.user_id, rt.token, rt.expires_at, rt.revoked, rt.replaced_by_token_id, u.email, u.email_encrypted, u.status AS user_status, rt.session_started_at, rt.metadata
 FROM identity_refresh_tokens rt
 JOIN identity_users u ON u.id = rt.user_id
 WHERE 
     rt.token = @token
     AND u.deleted_at IS NULL;
 
+-- name: GetIdentityActiveRefreshTokensByUserID :many
+SELECT id, metadata, created_at, session_started_at, expires_at
+FROM identity_refresh_tokens
+WHERE 
+    user_id = @user_id
+    AND revoked = FALSE
+    AND expires_at > NOW()
+ORDER BY created_at DESC, id DESC;
+
 -- name: GetIdentityMFAFactorByUserID :many
 SELECT id, user_id, type, friendly_name, secret, key_version, is_verified, last_used_at 
 FROM identity_mfa_factors 
@@ -70,6 +135,13 @@ WHERE
     id = @id AND 
     user_id = @user_id;
 
+-- name: GetIdentityMFAFactorAllByUserID :many
+SELECT id, type, friendly_name, is_verified, last_used_at, created_at
+FROM identity_mfa_factors
+WHERE 
+    user_id = @user_id
+ORDER BY created_at ASC;
+
 -- name: GetIdentityMFABackupCodeByUserID :many
 SELECT id, user_id, code, used_at 
 FROM identity_mfa_backup_codes 
@@ -78,25 +150,185 @@ WHERE
     AND used_at IS NULL;
 
 -- name: GetIdentityUserByID :one
-SELECT id, email, full_name, avatar_url, status, updated_at, deleted_at  
+SELECT id, email, email_encrypted, full_name, avatar_url, status, updated_at, deleted_at  
 FROM identity_users 
 WHERE
     id = @id
     AND deleted_at IS NULL;
 
 -- name: GetIdentityUserByIDIncludeDeleted :one
-SELECT id, email, full_name, avatar_url, status, updated_at, deleted_at
+SELECT id, email, email_encrypted, full_name, avatar_url, status, updated_at, deleted_at
 FROM identity_users 
 WHERE
     id = @id;
 
+-- name: GetIdentityUserUsername :one
+SELECT username
+FROM identity_users
+WHERE
+    id = @id
+    AND deleted_at IS NULL;
+
+-- name: GetIdentityUserAttributes :one
+SELECT attributes
+FROM identity_users
+WHERE
+    id = @id
+    AND deleted_at IS NULL;
+
+-- name: LockIdentityUserAttributes :one
+SELECT attributes
+FROM identity_users
+WHERE
+    id = @id
+    AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: LockIdentityUserUpdatedAt :one
+SELECT updated_at
+FROM identity_users
+WHERE
+    id = @id
+    AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: GetIdentityUserDeletion :one
+SELECT user_id, requested_at, scheduled_at, completed_at
+FROM identity_user_deletions
+WHERE
+    user_id = @user_id;
+
+-- name: GetIdentityUserDeletionDue :many
+-- Pending deletions whose grace period ended before due_before, oldest first.
+SELECT user_id, requested_at, scheduled_at, completed_at
+FROM identity_user_deletions
+WHERE
+    completed_at IS NULL
+    AND scheduled_at <= @due_before
+ORDER BY scheduled_at ASC
+LIMIT @page_limit;
+
+-- name: GetIdentityExportJob :one
+SELECT id, requested_by, format, filters, status, rows_total, rows_written, object_key, error, created_at, updated_at, completed_at
+FROM identity_export_jobs
+WHERE
+    id = @id;
+
+-- name: GetIdentityAPIKeyByToken :one
+SELECT k.id, k.user_id, k.expires_at, k.last_used_at, k.revoked_at, u.email, u.email_encrypted, u.status
+FROM identity_api_keys k
+JOIN identity_users u ON u.id = k.user_id
+WHERE 
+    k.token = @token
+    AND u.deleted_at IS NULL;
+
+-- name: GetIdentityActiveAPIKeysByUserID :many
+SELECT id, name, prefix, expires_at, last_used_at, created_at
+FROM identity_api_keys
+WHERE 
+    user_id = @user_id
+    AND revoked_at IS NULL
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetIdentityTrustedDeviceByToken :one
+SELECT id, user_id, expires_at, revoked_at
+FROM identity_trusted_devices
+WHERE 
+    token = @token;
+
+-- name: GetIdentityActiveTrustedDevicesByUserID :many
+SELECT id, user_agent, ip, expires_at, last_used_at, created_at
+FROM identity_trusted_devices
+WHERE 
+    user_id = @user_id
+    AND revoked_at IS NULL
+    AND expires_at > NOW()
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetIdentityServiceAccountByClientID :one
+SELECT id, client_id, secret
+FROM identity_service_accounts
+WHERE
+    client_id = @client_id;
+
+-- name: GetIdentityServiceAccounts :many
+SELECT id, name, client_id, created_by, last_used_at, created_at
+FROM identity_service_accounts
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetIdentityLoginEventsByUserID :many
+SELECT id, success, method, failure_reason, ip, user_agent, country, region, city, created_at
+FROM identity_login_events
+WHERE
+    user_id = @user_id
+    AND (@before_id::bigint = 0 OR id < @before_id::bigint)
+ORDER BY id DESC
+LIMIT @page_limit;
+
+-- name: GetIdentityPasswordHistoryByUserID :many
+SELECT password FROM identity_password_history
+WHERE user_id = @user_id
+ORDER BY id DESC
+LIMIT @page_limit;
+
+-- name: GetIdentityUserDevicesByUserID :many
+SELECT fingerprint, country FROM identity_user_devices WHERE user_id = @user_id;
+
+-- name: GetIdentityVerificationReminderDue :many
+-- Self-registered unverified users registered before registered_before whose last reminder,
+-- if any, was sent before last_sent_before and who got fewer than max_reminders of them.
+-- Invited users have created_by set to the inviter and are left to their invitation.
+SELECT u.id, COALESCE(r.sent_count, 0)::int AS sent_count
+FROM identity_users u
+LEFT JOIN identity_verification_reminders r ON r.user_id = u.id
+WHERE
+    u.status = @status
+    AND u.deleted_at IS NULL
+    AND u.created_by = u.id
+    AND u.created_at < @registered_before
+    AND (r.user_id IS NULL OR (r.sent_count < @max_reminders::int AND r.last_sent_at < @last_sent_before))
+ORDER BY u.id ASC
+LIMIT @page_limit;
+
+-- name: GetIdentityOrganizationByID :one
+SELECT id, name, slug, created_by, created_at, updated_at
+FROM identity_organizations
+WHERE id = @id;
+
+-- name: GetIdentityOrganizationsByUserID :many
+SELECT o.id, o.name, o.slug, o.created_by, o.created_at, o.updated_at
+FROM identity_organizations o
+JOIN identity_organization_members m ON m.organization_id = o.id
+WHERE m.user_id = @user_id
+ORDER BY o.name ASC, o.id ASC;
+
+-- name: GetIdentityOrganizationMembers :many
+SELECT m.user_id, u.email, u.email_encrypted, u.full_name, m.created_at
+FROM identity_organization_members m
+JOIN identity_users u ON u.id = m.user_id
+WHERE m.organization_id = @organization_id
+ORDER BY m.created_at ASC, m.user_id ASC;
+
+-- name: IsIdentityOrganizationMember :one
+SELECT EXISTS (
+    SELECT 1 FROM identity_organization_members
+    WHERE organization_id = @organization_id AND user_id = @user_id
+);
+
+-- name: IsIdentityUsernameTaken :one
+SELECT EXISTS (
+    SELECT 1 FROM identity_users
+    WHERE lower(username) = lower(@username)
+);
+
 -- name: GetIdentityUserFilter :many
-SELECT id, email, full_name, avatar_url, status, updated_at
+SELECT id, email, email_encrypted, full_name, avatar_url, status, updated_at
 FROM identity_users
 WHERE
     (NOT @filter_by_status::boolean OR status = ANY(@statuses::smallint[]))
     AND (
       NOT @filter_by_search::boolean
+      -- rows holding only the sealed email (strict hashed lookup) match by name alone
       OR email ILIKE '%' || @search::varchar || '%'
       OR full_name ILIKE '%' || @search::varchar || '%'
     )
@@ -120,6 +352,14 @@ ORDER BY
   created_at DESC, id DESC
 LIMIT @page_limit OFFSET @page_offset;
 
+-- name: GetIdentityAuditLogBefore :many
+SELECT id, actor_id, target_user_id, action, metadata, created_at
+FROM identity_audit_logs
+WHERE
+    created_at < @before
+ORDER BY id ASC
+LIMIT @page_limit;
+
 -- name: CountIdentityUserFilter :one
 SELECT COUNT(id)
 FROM identity_users
@@ -134,13 +374,35 @@ WHERE
     AND (NOT @filter_by_date_to::boolean OR created_at <= @date_to::timestamptz)
     AND deleted_at IS NULL;
 
+-- name: CountIdentityAuditLogBefore :one
+SELECT COUNT(id) FROM identity_audit_logs WHERE created_at < @before::timestamptz;
+
+-- name: CountIdentityLoginEventBefore :one
+SELECT COUNT(id) FROM identity_login_events WHERE created_at < @before::timestamptz;
+
+-- name: CountIdentityChallengeExpiredBefore :one
+SELECT COUNT(id) FROM identity_challenges WHERE expires_at < @before::timestamptz;
+
+-- name: CountIdentityRefreshTokenExpiredBefore :one
+SELECT COUNT(id) FROM identity_refresh_tokens WHERE expires_at < @before::timestamptz;
+
+-- name: CountIdentityTrustedDeviceExpiredBefore :one
+SELECT COUNT(id) FROM identity_trusted_devices WHERE expires_at < @before::timestamptz;
+
+-- name: CountIdentityActiveAPIKeysByUserID :one
+SELECT COUNT(id) FROM identity_api_keys 
+WHERE 
+    user_id = @user_id 
+    AND revoked_at IS NULL 
+    AND (expires_at IS NULL OR expires_at > NOW());
+
 -- ***** ***** *****
 -- CREATE DATA
 -- ***** ***** *****
 
 -- name: CreateIdentityRefreshToken :exec
-INSERT INTO identity_refresh_tokens (id, user_id, token, expires_at, metadata) 
-VALUES (@id, @user_id, @token, @expires_at, @metadata);
+INSERT INTO identity_refresh_tokens (id, user_id, token, expires_at, metadata, session_started_at) 
+VALUES (@id, @user_id, @token, @expires_at, @metadata, COALESCE(sqlc.narg(session_started_at), NOW()));
 
 -- name: CreateIdentityChallenge :exec
 INSERT INTO identity_challenges (id, user_id, token, purpose, expires_at, metadata) 
@@ -151,17 +413,88 @@ INSERT INTO identity_mfa_factors (id, user_id, type, friendly_name, secret, key_
 VALUES (@id, @user_id, @type, @friendly_name, @secret, @key_version, @is_verified);
 
 -- name: CreateIdentityUser :exec
-INSERT INTO identity_users (id, email, full_name, avatar_url, status, created_by, updated_by)
-VALUES (@id, @email, @full_name, @avatar_url, @status, @created_by, @updated_by);
+INSERT INTO identity_users (id, email, full_name, avatar_url, status, created_by, updated_by, email_hash, username, email_encrypted)
+VALUES (@id, @email, @full_name, @avatar_url, @status, @created_by, @updated_by, @email_hash, @username, @email_encrypted);
 
 -- name: CreateIdentityUserCredential :exec
 INSERT INTO identity_user_credentials (user_id, password)
 VALUES (@user_id, @password);
 
+-- name: CreateIdentityUserConnection :exec
+INSERT INTO identity_user_connections (id, user_id, provider, provider_user_id)
+VALUES (@id, @user_id, @provider, @provider_user_id);
+
+-- name: CreateIdentityAuditLog :exec
+INSERT INTO identity_audit_logs (id, actor_id, target_user_id, action, metadata)
+VALUES (@id, @actor_id, @target_user_id, @action, @metadata);
+
+-- name: CreateIdentityAPIKey :exec
+INSERT INTO identity_api_keys (id, user_id, name, prefix, token, expires_at)
+VALUES (@id, @user_id, @name, @prefix, @token, @expires_at);
+
+-- name: CreateIdentityTrustedDevice :exec
+INSERT INTO identity_trusted_devices (id, user_id, token, user_agent, ip, expires_at)
+VALUES (@id, @user_id, @token, @user_agent, @ip, @expires_at);
+
+-- name: CreateIdentityServiceAccount :exec
+INSERT INTO identity_service_accounts (id, name, client_id, secret, created_by)
+VALUES (@id, @name, @client_id, @secret, @created_by);
+
+-- name: CreateIdentityOrganization :exec
+INSERT INTO identity_organizations (id, name, slug, created_by)
+VALUES (@id, @name, @slug, @created_by);
+
+-- name: CreateIdentityOrganizationMember :execrows
+INSERT INTO identity_organization_members (organization_id, user_id)
+VALUES (@organization_id, @user_id)
+ON CONFLICT DO NOTHING;
+
+-- name: CreateIdentityLoginEvent :exec
+INSERT INTO identity_login_events (id, user_id, success, method, failure_reason, ip, user_agent)
+VALUES (@id, @user_id, @success, @method, @failure_reason, @ip, @user_agent);
+
+-- name: CreateIdentityUserDeletion :one
+-- A repeated request keeps the schedule of the pending one.
+INSERT INTO identity_user_deletions (user_id, scheduled_at)
+VALUES (@user_id, @scheduled_at)
+ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+RETURNING user_id, requested_at, scheduled_at, completed_at;
+
+-- name: CreateIdentityExportJob :one
+INSERT INTO identity_export_jobs (id, requested_by, format, filters)
+VALUES (@id, @requested_by, @format, @filters)
+RETURNING id, requested_by, format, filters, status, rows_total, rows_written, object_key, error, created_at, updated_at, completed_at;
+
 -- name: CreateIdentityMFABackupCodes :copyfrom
 INSERT INTO identity_mfa_backup_codes (id, user_id, code)
 VALUES (@id, @user_id, @code);
 
+-- name: CreateIdentityPasswordHistoryFromCredential :exec
+INSERT INTO identity_password_history (user_id, password)
+SELECT user_id, password FROM identity_user_credentials WHERE user_id = @user_id;
+
+-- name: ClaimIdentityVerificationReminder :execrows
+-- Counts a reminder only when sent_count is still the one the caller read, so two
+-- schedulers never remind the same user twice.
+INSERT INTO identity_verification_reminders (user_id, sent_count, last_sent_at)
+VALUES (@user_id, 1, @sent_at)
+ON CONFLICT (user_id) DO UPDATE SET
+    sent_count = identity_verification_reminders.sent_count + 1,
+    last_sent_at = EXCLUDED.last_sent_at
+WHERE
+    identity_verification_reminders.sent_count = @sent_count::int;
+
+-- name: UpsertIdentityUserDevice :exec
+-- A lookup that found no country keeps the one the device was last seen in.
+INSERT INTO identity_user_devices (id, user_id, fingerprint, user_agent, last_ip, country)
+VALUES (@id, @user_id, @fingerprint, @user_agent, @last_ip, @country)
+ON CONFLICT (user_id, fingerprint)
+DO UPDATE SET
+    user_agent = EXCLUDED.user_agent,
+    last_ip = EXCLUDED.last_ip,
+    country = COALESCE(NULLIF(EXCLUDED.country, ''), identity_user_devices.country),
+    last_seen_at = NOW();
+
 -- ***** ***** *****
 -- UPDATE DATA
 -- ***** ***** *****
@@ -174,6 +507,13 @@ WHERE
     id = @id AND
     user_id = @user_id;
 
+-- name: UpdateIdentityChallengeMetadata :exec
+UPDATE identity_challenges
+SET 
+    metadata = COALESCE(metadata, '{}'::jsonb) || @metadata::jsonb
+WHERE
+    id = @id;
+
 -- name: UpdateIdentityMFALastUsedAt :exec
 UPDATE identity_mfa_factors
 SET 
@@ -182,6 +522,21 @@ WHERE
     id = @id AND
     user_id = @user_id;
 
+-- name: UpdateIdentityMFAFactorFriendlyName :execrows
+UPDATE identity_mfa_factors
+SET 
+    friendly_name = @friendly_name
+WHERE
+    id = @id AND
+    user_id = @user_id;
+
+-- name: UpdateIdentityOrganizationName :execrows
+UPDATE identity_organizations
+SET
+    name = @name
+WHERE
+    id = @id;
+
 -- name: UpdateIdentityUserStatus :exec
 UPDATE identity_users
 SET 
@@ -206,6 +561,28 @@ SET
 WHERE 
     user_id = @user_id;
 
+-- name: RevokeIdentityRefreshTokenOverLimit :execrows
+-- Keeps the newest @keep active sessions of a user and revokes the rest.
+UPDATE identity_refresh_tokens 
+SET 
+    revoked = TRUE
+WHERE 
+    id IN (
+        SELECT id FROM identity_refresh_tokens
+        WHERE user_id = @user_id AND revoked = FALSE AND expires_at > NOW()
+        ORDER BY created_at DESC, id DESC
+        OFFSET @keep
+    );
+
+-- name: RevokeIdentityRefreshTokenByID :execrows
+UPDATE identity_refresh_tokens 
+SET 
+    revoked = TRUE
+WHERE 
+    id = @id
+    AND user_id = @user_id
+    AND revoked = FALSE;
+
 -- name: ReplaceIdentityRefreshToken :execrows
 UPDATE identity_refresh_tokens 
 SET 
@@ -232,6 +609,24 @@ WHERE
     id = @id AND
     deleted_at IS NULL;
 
+-- name: UpdateIdentityUserAttributes :execrows
+UPDATE identity_users
+SET
+    attributes = @attributes,
+    updated_by = @updated_by
+WHERE
+    id = @id
+    AND deleted_at IS NULL;
+
+-- name: UpdateIdentityUserUsername :execrows
+UPDATE identity_users
+SET 
+    username = @username,
+    updated_by = @updated_by
+WHERE
+    id = @id AND
+    deleted_at IS NULL;
+
 -- name: UpdateIdentityUserAvatar :exec
 UPDATE identity_users
 SET 
@@ -257,17 +652,186 @@ WHERE
     AND id = @id 
     AND used_at IS NULL;
 
+-- name: ClearIdentityUserMustResetPassword :exec
+UPDATE identity_users
+SET
+    must_reset_password = FALSE
+WHERE
+    id = @id
+    AND must_reset_password = TRUE;
+
 -- name: PatcIdentityUser :exec
 UPDATE identity_users
 SET 
-    email = COALESCE(sqlc.narg('email'), email),
+    -- a sealed address without a plaintext one replaces the plaintext with NULL
+    email = CASE
+        WHEN sqlc.narg('email')::varchar IS NULL AND sqlc.narg('email_encrypted')::bytea IS NOT NULL THEN NULL
+        ELSE COALESCE(sqlc.narg('email'), email)
+    END,
+    email_encrypted = COALESCE(sqlc.narg('email_encrypted'), email_encrypted),
     full_name = COALESCE(sqlc.narg('full_name'), full_name),
     avatar_url = COALESCE(sqlc.narg('avatar_url'), avatar_url),
     status = COALESCE(sqlc.narg('status')::smallint, status),
-    updated_by = COALESCE(sqlc.narg('updated_by'), updated_by)
+    updated_by = COALESCE(sqlc.narg('updated_by'), updated_by),
+    email_hash = COALESCE(sqlc.narg('email_hash'), email_hash),
+    must_reset_password = COALESCE(sqlc.narg('must_reset_password'), must_reset_password),
+    mfa_required = COALESCE(sqlc.narg('mfa_required'), mfa_required)
 WHERE 
     id = @id;
 
+-- name: UpdateIdentityUserEmail :exec
+UPDATE identity_users
+SET 
+    email = @email,
+    email_hash = @email_hash,
+    email_encrypted = @email_encrypted,
+    updated_by = @updated_by
+WHERE
+    id = @id AND
+    deleted_at IS NULL;
+
+-- name: UpdateIdentityUserEmailLookup :exec
+UPDATE identity_users
+SET 
+    email_hash = @email_hash,
+    email_encrypted = @email_encrypted
+WHERE
+    id = @id;
+
+-- name: SealIdentityUserEmail :execrows
+UPDATE identity_users
+SET 
+    email = NULL,
+    email_hash = @email_hash,
+    email_encrypted = @email_encrypted
+WHERE
+    id = @id
+    AND email = @email;
+
+-- name: UpdateIdentityAPIKeyLastUsedAt :exec
+UPDATE identity_api_keys
+SET
+    last_used_at = NOW()
+WHERE
+    id = @id;
+
+-- name: UpdateIdentityTrustedDeviceLastUsedAt :exec
+UPDATE identity_trusted_devices
+SET
+    last_used_at = NOW()
+WHERE
+    id = @id;
+
+-- name: UpdateIdentityServiceAccountLastUsedAt :exec
+UPDATE identity_service_accounts
+SET
+    last_used_at = NOW()
+WHERE
+    id = @id;
+
+-- name: UpdateIdentityLoginEventLocation :exec
+UPDATE identity_login_events
+SET
+    country = @country,
+    region = @region,
+    city = @city,
+    enriched_at = NOW()
+WHERE
+    id = @id;
+
+-- name: RevokeIdentityAPIKey :execrows
+UPDATE identity_api_keys
+SET
+    revoked_at = NOW()
+WHERE
+    id = @id
+    AND user_id = @user_id
+    AND revoked_at IS NULL;
+
+-- name: RevokeIdentityTrustedDevice :execrows
+UPDATE identity_trusted_devices
+SET
+    revoked_at = NOW()
+WHERE
+    id = @id
+    AND user_id = @user_id
+    AND revoked_at IS NULL;
+
+-- name: RevokeAllIdentityTrustedDevice :exec
+UPDATE identity_trusted_devices
+SET
+    revoked_at = NOW()
+WHERE
+    user_id = @user_id
+    AND revoked_at IS NULL;
+
+-- name: AnonymizeIdentityUser :exec
+UPDATE identity_users
+SET 
+    email = @email,
+    full_name = @full_name,
+    avatar_url = '',
+    status = @status,
+    email_hash = NULL,
+    email_encrypted = NULL,
+    username = NULL,
+    attributes = '{}'::jsonb,
+    updated_by = @id,
+    deleted_at = COALESCE(deleted_at, NOW()),
+    deleted_by = COALESCE(deleted_by, @id)
+WHERE
+    id = @id;
+
+-- name: CompleteIdentityUserDeletion :exec
+UPDATE identity_user_deletions
+SET 
+    completed_at = NOW()
+WHERE
+    user_id = @user_id AND
+    completed_at IS NULL;
+
+-- name: StartIdentityExportJob :execrows
+-- Only a pending job starts, so a redelivered message does not run the export twice.
+UPDATE identity_export_jobs
+SET
+    status = 2,
+    rows_total = @rows_total,
+    updated_at = NOW()
+WHERE
+    id = @id
+    AND status = 1;
+
+-- name: UpdateIdentityExportJobProgress :exec
+UPDATE identity_export_jobs
+SET
+    rows_written = @rows_written,
+    updated_at = NOW()
+WHERE
+    id = @id
+    AND status = 2;
+
+-- name: CompleteIdentityExportJob :exec
+UPDATE identity_export_jobs
+SET
+    status = 3,
+    rows_written = @rows_written,
+    object_key = @object_key,
+    updated_at = NOW(),
+    completed_at = NOW()
+WHERE
+    id = @id;
+
+-- name: FailIdentityExportJob :exec
+UPDATE identity_export_jobs
+SET
+    status = 4,
+    error = @error,
+    updated_at = NOW(),
+    completed_at = NOW()
+WHERE
+    id = @id
+    AND status IN (1, 2);
+
 -- ***** ***** *****
 -- DELETE DATA
 -- ***** ***** *****
@@ -275,5 +839,123 @@ WHERE
 -- name: DeleteIdentityChallengeByID :exec
 DELETE FROM identity_challenges WHERE id = @id;
 
+-- name: DeleteIdentityChallengeByUserPurpose :execrows
+DELETE FROM identity_challenges WHERE user_id = @user_id AND purpose = @purpose;
+
+-- name: DeleteIdentityChallengeByUserPurposes :exec
+-- Outstanding emailed links are dropped once the credential or address they were issued for changes.
+DELETE FROM identity_challenges WHERE user_id = @user_id AND purpose = ANY(@purposes::smallint[]);
+
+-- name: DeleteIdentityChallengeByUserID :exec
+DELETE FROM identity_challenges WHERE user_id = @user_id;
+
 -- name: DeleteIdentityMFABackupCodeByUserID :exec
 DELETE FROM identity_mfa_backup_codes WHERE user_id = @user_id;
+
+-- name: DeleteIdentityMFAFactorByID :execrows
+DELETE FROM identity_mfa_factors WHERE id = @id AND user_id = @user_id;
+
+-- name: DeleteIdentityMFAFactorByUserID :exec
+DELETE FROM identity_mfa_factors WHERE user_id = @user_id;
+
+-- name: DeleteIdentityRefreshTokenByUserID :exec
+DELETE FROM identity_refresh_tokens WHERE user_id = @user_id;
+
+-- name: DeleteIdentityPendingUserDeletion :execrows
+DELETE FROM identity_user_deletions WHERE user_id = @user_id AND completed_at IS NULL;
+
+-- name: DeleteIdentityUserConnection :one
+DELETE FROM identity_user_connections WHERE id = @id AND user_id = @user_id RETURNING provider;
+
+-- name: DeleteIdentityUserConnectionByUserID :exec
+DELETE FROM identity_user_connections WHERE user_id = @user_id;
+
+-- name: DeleteIdentityUserCredential :exec
+DELETE FROM identity_user_credentials WHERE user_id = @user_id;
+
+-- name: DeleteIdentityServiceAccount :one
+DELETE FROM identity_service_accounts WHERE id = @id RETURNING client_id;
+
+-- name: DeleteIdentityAPIKeyByUserID :exec
+DELETE FROM identity_api_keys WHERE user_id = @user_id;
+
+-- name: DeleteIdentityTrustedDeviceByUserID :exec
+DELETE FROM identity_trusted_devices WHERE user_id = @user_id;
+
+-- name: DeleteIdentityLoginEventByUserID :exec
+DELETE FROM identity_login_events WHERE user_id = @user_id;
+
+-- name: DeleteIdentityUserDeviceByUserID :exec
+DELETE FROM identity_user_devices WHERE user_id = @user_id;
+
+-- name: DeleteIdentityPasswordHistoryByUserID :exec
+DELETE FROM identity_password_history WHERE user_id = @user_id;
+
+-- name: DeleteIdentityPasswordHistoryOverflow :exec
+DELETE FROM identity_password_history
+WHERE
+    user_id = @user_id AND
+    id NOT IN (
+        SELECT id FROM identity_password_history
+        WHERE user_id = @user_id
+        ORDER BY id DESC
+        LIMIT @keep
+    );
+
+-- name: DeleteIdentityAuditLogByIDs :execrows
+DELETE FROM identity_audit_logs WHERE id = ANY(@ids::bigint[]);
+
+-- name: DeleteIdentityAuditLogBefore :execrows
+DELETE FROM identity_audit_logs
+WHERE id IN (
+    SELECT id FROM identity_audit_logs
+    WHERE created_at < @before::timestamptz
+    ORDER BY id ASC
+    LIMIT @page_limit
+);
+
+-- name: DeleteIdentityLoginEventBefore :execrows
+DELETE FROM identity_login_events
+WHERE id IN (
+    SELECT id FROM identity_login_events
+    WHERE created_at < @before::timestamptz
+    ORDER BY id ASC
+    LIMIT @page_limit
+);
+
+-- name: DeleteIdentityChallengeExpiredBefore :execrows
+DELETE FROM identity_challenges
+WHERE id IN (
+    SELECT id FROM identity_challenges
+    WHERE expires_at < @before::timestamptz
+    ORDER BY id ASC
+    LIMIT @page_limit
+);
+
+-- name: DeleteIdentityRefreshTokenExpiredBefore :execrows
+DELETE FROM identity_refresh_tokens
+WHERE id IN (
+    SELECT id FROM identity_refresh_tokens
+    WHERE expires_at < @before::timestamptz
+    ORDER BY id ASC
+    LIMIT @page_limit
+);
+
+-- name: DeleteIdentityTrustedDeviceExpiredBefore :execrows
+DELETE FROM identity_trusted_devices
+WHERE id IN (
+    SELECT id FROM identity_trusted_devices
+    WHERE expires_at < @before::timestamptz
+    ORDER BY id ASC
+    LIMIT @page_limit
+);
+
+-- name: DeleteIdentityOrganization :execrows
+DELETE FROM identity_organizations WHERE id = @id;
+
+-- name: DeleteIdentityOrganizationMember :execrows
+DELETE FROM identity_organization_members
+WHERE organization_id = @organization_id AND user_id = @user_id;
+
+-- name: DeleteIdentityOrganizationMembersByUserID :exec
+DELETE FROM identity_organization_members WHERE user_id = @user_id;
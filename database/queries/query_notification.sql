@@ -3,7 +3,7 @@
 -- ***** ***** *****
 
 -- name: GetNotificationTemplateByTriggerChannel :one
-SELECT id, trigger_key, category_id, channel, subject, body
+SELECT id, trigger_key, category_id, channel, subject, body, updated_at
 FROM notification_templates
 WHERE 
     trigger_key = @trigger_key AND 
@@ -14,12 +14,32 @@ SELECT id, name, description, is_mandatory
 FROM notification_categories
 ORDER BY id ASC;
 
+-- name: ListNotificationCategoryDefaults :many
+SELECT category_id, channel, is_enabled
+FROM notification_category_defaults
+ORDER BY category_id ASC, channel ASC;
+
+-- name: ListNotificationTemplates :many
+SELECT id, trigger_key, category_id, channel, subject, body
+FROM notification_templates
+ORDER BY id ASC;
+
 -- name: ListNotificationUserSettings :many
-SELECT user_id, category_id, channel, is_enabled
+SELECT user_id, category_id, channel, is_enabled, updated_at
 FROM notification_user_settings
 WHERE 
     user_id = @user_id;
 
+-- name: LockNotificationUserSettings :many
+SELECT updated_at
+FROM notification_user_settings
+WHERE 
+    user_id = @user_id
+FOR UPDATE;
+
+-- name: LockNotificationUserSettingsOwner :exec
+SELECT pg_advisory_xact_lock(hashtextextended('notification_user_settings', @user_id));
+
 -- name: ListNotificationsByUserAll :many
 SELECT id, user_id, category_id, trigger_key, data, metadata, read_at, created_at
 FROM notifications
@@ -31,6 +51,15 @@ ORDER BY
     id DESC
 LIMIT @page_limit OFFSET @page_offset;
 
+-- name: ListNotificationsByUserExport :many
+SELECT id, category_id, trigger_key, data, metadata, read_at, deleted_at, created_at
+FROM notifications
+WHERE 
+    user_id = @user_id AND 
+    id > @after_id
+ORDER BY id ASC
+LIMIT @page_limit;
+
 -- name: ListNotificationsByUserUnread :many
 SELECT id, user_id, category_id, trigger_key, data, metadata, read_at, created_at
 FROM notifications
@@ -55,6 +84,23 @@ ORDER BY
     id DESC
 LIMIT @page_limit OFFSET @page_offset;
 
+-- name: ListNotificationsByUserSearch :many
+SELECT id, user_id, category_id, trigger_key, data, metadata, read_at, created_at
+FROM notifications
+WHERE 
+    user_id = @user_id AND 
+    deleted_at IS NULL AND 
+    search_vector @@ websearch_to_tsquery('simple', @query::VARCHAR) AND 
+    (
+        @status::VARCHAR = 'all' OR
+        (@status::VARCHAR = 'unread' AND read_at IS NULL) OR
+        (@status::VARCHAR = 'read' AND read_at IS NOT NULL)
+    )
+ORDER BY 
+    created_at DESC, 
+    id DESC
+LIMIT @page_limit OFFSET @page_offset;
+
 -- name: CountNotificationsUnread :one
 SELECT COUNT(*)::BIGINT
 FROM notifications
@@ -63,6 +109,19 @@ WHERE
     read_at IS NULL AND 
     deleted_at IS NULL;
 
+-- name: CountNotificationsBefore :one
+SELECT COUNT(id) FROM notifications WHERE created_at < @before::timestamptz;
+
+-- name: CountNotificationUserDevicesInactiveBefore :one
+SELECT COUNT(id) FROM notification_user_devices WHERE last_active_at < @before::timestamptz;
+
+-- name: GetNotificationByID :one
+SELECT id, user_id, category_id, trigger_key
+FROM notifications
+WHERE
+    id = @id AND
+    deleted_at IS NULL;
+
 -- ***** ***** *****
 -- CREATE DATA
 -- ***** ***** *****
@@ -94,6 +153,11 @@ SET
     next_retry_at = @next_retry_at
 WHERE id = @id;
 
+-- name: CreateNotificationReply :execrows
+INSERT INTO notification_replies (id, notification_id, user_id, provider, message_id, from_address, subject, body, metadata, received_at)
+VALUES (@id, @notification_id, @user_id, @provider, @message_id, @from_address, @subject, @body, @metadata, @received_at)
+ON CONFLICT (provider, message_id) DO NOTHING;
+
 -- name: UpsertNotificationUserSetting :exec
 INSERT INTO notification_user_settings (user_id, category_id, channel, is_enabled)
 VALUES (@user_id, @category_id, @channel, @is_enabled)
@@ -102,6 +166,29 @@ DO UPDATE SET
     is_enabled = EXCLUDED.is_enabled,
     updated_at = NOW();
 
+-- name: SeedNotificationCategory :execrows
+INSERT INTO notification_categories (id, name, description, is_mandatory)
+VALUES (@id, @name, @description, @is_mandatory)
+ON CONFLICT DO NOTHING;
+
+-- name: SeedNotificationCategoryDefault :execrows
+INSERT INTO notification_category_defaults (category_id, channel, is_enabled)
+VALUES (@category_id, @channel, @is_enabled)
+ON CONFLICT DO NOTHING;
+
+-- name: SeedNotificationTemplate :execrows
+INSERT INTO notification_templates (id, trigger_key, category_id, channel, subject, body)
+VALUES (@id, @trigger_key, @category_id, @channel, @subject, @body)
+ON CONFLICT DO NOTHING;
+
+-- name: UpsertNotificationTrigger :exec
+INSERT INTO notification_triggers (key, description, data_schema)
+VALUES (@key, @description, @data_schema)
+ON CONFLICT (key)
+DO UPDATE SET
+    description = EXCLUDED.description,
+    data_schema = EXCLUDED.data_schema;
+
 -- name: MarkNotificationRead :execrows
 UPDATE notifications
 SET read_at = NOW()
@@ -119,6 +206,16 @@ WHERE
     read_at IS NULL AND 
     deleted_at IS NULL;
 
+-- name: UpdateNotificationTemplateContent :execrows
+UPDATE notification_templates
+SET
+    subject = @subject,
+    body = @body
+WHERE 
+    trigger_key = @trigger_key AND 
+    channel = @channel AND
+    (sqlc.narg('expected_updated_at')::timestamptz IS NULL OR updated_at = sqlc.narg('expected_updated_at'));
+
 -- ***** ***** *****
 -- DELETE DATA
 -- ***** ***** *****
@@ -128,6 +225,20 @@ DELETE FROM notification_user_devices
 WHERE 
     device_token = @device_token;
 
+-- name: RemoveNotificationUserDevicesByTokens :execrows
+DELETE FROM notification_user_devices
+WHERE
+    device_token = ANY(@device_tokens::text[]);
+
+-- name: DeleteNotificationUserDevicesInactiveBefore :execrows
+DELETE FROM notification_user_devices
+WHERE id IN (
+    SELECT id FROM notification_user_devices
+    WHERE last_active_at < @before::timestamptz
+    ORDER BY id ASC
+    LIMIT @page_limit
+);
+
 -- name: SoftDeleteNotification :execrows
 UPDATE notifications
 SET deleted_at = NOW()
@@ -135,3 +246,21 @@ WHERE
     id = @id AND 
     user_id = @user_id AND 
     deleted_at IS NULL;
+
+-- name: DeleteNotificationsBefore :execrows
+DELETE FROM notifications
+WHERE id IN (
+    SELECT id FROM notifications
+    WHERE created_at < @before::timestamptz
+    ORDER BY id ASC
+    LIMIT @page_limit
+);
+
+-- name: DeleteNotificationsByUserID :execrows
+DELETE FROM notifications WHERE user_id = @user_id;
+
+-- name: DeleteNotificationUserDevicesByUserID :exec
+DELETE FROM notification_user_devices WHERE user_id = @user_id;
+
+-- name: DeleteNotificationUserSettingsByUserID :exec
+DELETE FROM notification_user_settings WHERE user_id = @user_id;
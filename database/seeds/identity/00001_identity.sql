@@ -13,7 +13,7 @@ VALUES
 
 -- "odwh-23IX-j5Pl" -- "LZEG-lnN4-w8hQ" -- "96qV-hU4h-I9XV" -- "bVjS-aFmF-VLBD" -- "V7Tr-dzp6-HABd" 
 -- "Lfj2-advW-rqBT" -- "JxPf-jbod-QdtW" -- "Ycti-dfZp-jEhh" -- "Tdse-CWnw-eFiG" -- "cH2e-h2ad-tc0o"
-INSERT INTO public.identity_mfa_backup_codes (id, user_id, code) 
+INSERT INTO identity_mfa_backup_codes (id, user_id, code) 
 VALUES
 	(1, 2, '$argon2id$v=19$m=32768,t=3,p=2$5nxw7Ax0BCeF79WoUy7Qdg$YfdP3h5pswzFIOS1mwu6jDzZO/V0fKD/7qmz/FOTbas'),
 	(2, 2, '$argon2id$v=19$m=32768,t=3,p=2$gHLxuEfI/ZJ1U69ZtXMenQ$vGz/ySVvbDkxdr6fmwHTkudWuBxyS6OpvEIDEF4uwQc'),
@@ -27,7 +27,7 @@ VALUES
 	(10, 2, '$argon2id$v=19$m=32768,t=3,p=2$J1c62QAYnxLaS/T5dTWnUg$EHzuFZDN0ilm2AvuK9f5VJRWvBl4PEF1a0SlhRENHHU');
 
 -- key: KFP6EBHKHTWE2PHK5GOK7K2ARBZWQDBV
-INSERT INTO public.identity_mfa_factors (id, user_id, "type", friendly_name, secret, key_version, is_verified) 
+INSERT INTO identity_mfa_factors (id, user_id, "type", friendly_name, secret, key_version, is_verified) 
 VALUES
 	(1, 2, 1, 'user', decode('00013B264043A88BD1B2DAF89CFDA4511848117B551D66398E3F1AFA75ACD5F27F692840D2B96FD43E3AA7D821A3016FCA96938D42B66A311C2F1BDE3942','hex'), 1, true),
 	(2, 2, 3, 'user', decode('','hex'), 1, true);
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The service also upserts these on startup; they are inserted here so the templates below satisfy the foreign key.
+INSERT INTO notification_triggers (key, description) VALUES
+    ('mfa_recovery_requested', 'Sends the link that continues a lost two-factor authentication recovery'),
+    ('mfa_recovery_pending', 'Warns a user that their two-factor authentication will be removed after a waiting period'),
+    ('mfa_recovery_completed', 'Tells a user their two-factor authentication was removed by a recovery')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO notification_templates (id, trigger_key, category_id, channel, subject, body) VALUES
+    (5, 'mfa_recovery_requested', 1, 2, 
+    '[GoBite] Recover your two-factor authentication', 
+    $$<!DOCTYPE html><html lang="en" xmlns="http://www.w3.org/1999/xhtml" xmlns:v="urn:schemas-microsoft-com:vml" xmlns:o="urn:schemas-microsoft-com:office:office"><head><meta charset="utf-8"><meta name="viewport" content="width=device-width,initial-scale=1"><meta name="x-apple-disable-message-reformatting"><meta http-equiv="X-UA-Compatible" content="IE=edge"><title>Recover your two-factor authentication</title><!--[if mso]><xml><o:officedocumentsettings><o:pixelsperinch>96</o:pixelsperinch></o:officedocumentsettings></xml><![endif]--><style>body,html{margin:0!important;padding:0!important;height:100%!important;width:100%!important;font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Arial,sans-serif;background:#f6f7fb;color:#111827}table,td{border-collapse:collapse!important;mso-table-lspace:0!important;mso-table-rspace:0!important}img{-ms-interpolation-mode:bicubic;border:0;outline:0;text-decoration:none;display:block}a{text-decoration:none}@media screen and (max-width:600px){.container{width:100%!important}.px{padding-left:20px!important;padding-right:20px!important}.btn-wrap{width:100%!important}.btn-wrap td{width:100%!important}.btn td{display:block!important;width:100%!important}.btn a{display:block!important;width:100%!important}.logo{max-width:180px!important;height:auto!important}}@media (prefers-color-scheme:dark){body{background:#0b1220!important;color:#e5e7eb!important}.card{background:#111827!important}.muted{color:#9ca3af!important}.divider{border-color:#243244!important}}</style></head><body><div style="display:none;font-size:1px;color:#f6f7fb;line-height:1px;max-height:0;max-width:0;opacity:0;overflow:hidden">Continue recovering access to your account.</div><table role="presentation" width="100%" bgcolor="#f6f7fb" style="width:100%;background:#f6f7fb"><tr><td align="center" style="padding:40px 12px"><table role="presentation" class="container" width="600" style="width:600px;max-width:600px;border-radius:16px;overflow:hidden"><tr><td align="center" style="padding:22px 24px;background:#111827"><img src="https://www.nicehash.com/static/header.png" width="200" alt="{{.company_name}}" class="logo" style="max-width:200px;width:100%;height:auto;display:block;margin:0 auto"></td></tr><tr><td class="card" bgcolor="#ffffff" style="background:#fff;padding:28px 32px" class="px"><h1 style="margin:0 0 12px;font-size:22px;line-height:1.3;color:#111827">Recover two-factor authentication</h1><p class="muted" style="margin:0 0 18px;font-size:15px;line-height:1.6;color:#4b5563">Hi {{.full_name}}, we received a request to recover your account because its two-factor authentication device is no longer available. Continue with the link below to confirm your password. For your protection, the recovery finishes only after a waiting period.</p><table role="presentation" border="0" cellpadding="0" cellspacing="0" width="100%" style="margin:22px 0"><tr><td align="left"><table role="presentation" border="0" cellpadding="0" cellspacing="0" class="btn-wrap" style="border-collapse:separate"><tr><td align="center" bgcolor="#2563eb" class="btn" style="border-radius:10px"><!--[if mso]><v:roundrect xmlns:v="urn:schemas-microsoft-com:vml" xmlns:w="urn:schemas-microsoft-com:office:word" href="{{.recovery_url}}" style="height:44px;v-text-anchor:middle;width:240px" arcsize="18%" stroke="f" fillcolor="#2563eb"><w:anchorlock><center style="color:#fff;font-family:Segoe UI,Arial,sans-serif;font-size:15px;font-weight:600">Continue Recovery</center></v:roundrect><![endif]--><!--[if !mso]><!-- --><a href="{{.recovery_url}}" target="_blank" style="font-size:15px;font-weight:600;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,Arial,sans-serif;color:#fff;text-decoration:none;padding:12px 18px;border-radius:10px;display:inline-block;mso-padding-alt:0">Continue Recovery</a><!--<![endif]--></td></tr></table></td></tr></table><p class="muted" style="margin:0 0 8px;font-size:13px;line-height:1.6;color:#6b7280">If the button doesn’t work, copy and paste this link into your browser:</p><p style="margin:0 0 18px;font-size:13px;line-height:1.6;word-break:break-all"><a href="{{.recovery_url}}" style="color:#2563eb">{{.recovery_url}}</a></p><hr class="divider" style="border:none;border-top:1px solid #e5e7eb;margin:20px 0"><p class="muted" style="margin:0;font-size:12px;line-height:1.6;color:#6b7280">If you didn’t request this, you can ignore this email. Your two-factor authentication stays in place.</p><p class="muted" style="margin:12px 0 0;font-size:12px;line-height:1.6;color:#6b7280">Need help? Contact us at <a href="mailto:{{.support_email}}" style="color:#2563eb">{{.support_email}}</a>.</p></td></tr><tr><td align="center" style="padding:18px 24px"><p class="muted" style="margin:0;font-size:12px;line-height:1.6;color:#9ca3af">© {{.year}} {{.company_name}}. All rights reserved.</p><p class="muted" style="margin:6px 0 0;font-size:12px;line-height:1.6;color:#9ca3af">{{.company_address}}</p></td></tr></table></td></tr></table></body></html>$$
+    ),
+
+    (6, 'mfa_recovery_pending', 1, 2, 
+    '[GoBite] Two-factor authentication will be removed from your account', 
+    $$<!DOCTYPE html><html lang="en" xmlns="http://www.w3.org/1999/xhtml" xmlns:v="urn:schemas-microsoft-com:vml" xmlns:o="urn:schemas-microsoft-com:office:office"><head><meta charset="utf-8"><meta name="viewport" content="width=device-width,initial-scale=1"><meta name="x-apple-disable-message-reformatting"><meta http-equiv="X-UA-Compatible" content="IE=edge"><title>Two-factor authentication recovery in progress</title><!--[if mso]><xml><o:officedocumentsettings><o:pixelsperinch>96</o:pixelsperinch></o:officedocumentsettings></xml><![endif]--><style>body,html{margin:0!important;padding:0!important;height:100%!important;width:100%!important;font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Arial,sans-serif;background:#f6f7fb;color:#111827}table,td{border-collapse:collapse!important;mso-table-lspace:0!important;mso-table-rspace:0!important}img{-ms-interpolation-mode:bicubic;border:0;outline:0;text-decoration:none;display:block}a{text-decoration:none}@media screen and (max-width:600px){.container{width:100%!important}.px{padding-left:20px!important;padding-right:20px!important}.btn-wrap{width:100%!important}.btn-wrap td{width:100%!important}.btn td{display:block!important;width:100%!important}.btn a{display:block!important;width:100%!important}.logo{max-width:180px!important;height:auto!important}}@media (prefers-color-scheme:dark){body{background:#0b1220!important;color:#e5e7eb!important}.card{background:#111827!important}.muted{color:#9ca3af!important}.divider{border-color:#243244!important}}</style></head><body><div style="display:none;font-size:1px;color:#f6f7fb;line-height:1px;max-height:0;max-width:0;opacity:0;overflow:hidden">Your two-factor authentication will be removed soon.</div><table role="presentation" width="100%" bgcolor="#f6f7fb" style="width:100%;background:#f6f7fb"><tr><td align="center" style="padding:40px 12px"><table role="presentation" class="container" width="600" style="width:600px;max-width:600px;border-radius:16px;overflow:hidden"><tr><td align="center" style="padding:22px 24px;background:#111827"><img src="https://www.nicehash.com/static/header.png" width="200" alt="{{.company_name}}" class="logo" style="max-width:200px;width:100%;height:auto;display:block;margin:0 auto"></td></tr><tr><td class="card" bgcolor="#ffffff" style="background:#fff;padding:28px 32px" class="px"><h1 style="margin:0 0 12px;font-size:22px;line-height:1.3;color:#111827">Recovery in progress</h1><p class="muted" style="margin:0 0 18px;font-size:15px;line-height:1.6;color:#4b5563">Hi {{.full_name}}, someone verified a request to remove two-factor authentication from your account. It can be removed after {{.available_at}}. If this was you, no action is needed.</p><table role="presentation" border="0" cellpadding="0" cellspacing="0" width="100%" style="margin:22px 0"><tr><td align="left"><table role="presentation" border="0" cellpadding="0" cellspacing="0" class="btn-wrap" style="border-collapse:separate"><tr><td align="center" bgcolor="#2563eb" class="btn" style="border-radius:10px"><!--[if mso]><v:roundrect xmlns:v="urn:schemas-microsoft-com:vml" xmlns:w="urn:schemas-microsoft-com:office:word" href="{{.security_url}}" style="height:44px;v-text-anchor:middle;width:240px" arcsize="18%" stroke="f" fillcolor="#2563eb"><w:anchorlock><center style="color:#fff;font-family:Segoe UI,Arial,sans-serif;font-size:15px;font-weight:600">Security Settings</center></v:roundrect><![endif]--><!--[if !mso]><!-- --><a href="{{.security_url}}" target="_blank" style="font-size:15px;font-weight:600;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,Arial,sans-serif;color:#fff;text-decoration:none;padding:12px 18px;border-radius:10px;display:inline-block;mso-padding-alt:0">Security Settings</a><!--<![endif]--></td></tr></table></td></tr></table><p class="muted" style="margin:0 0 8px;font-size:13px;line-height:1.6;color:#6b7280">If the button doesn’t work, copy and paste this link into your browser:</p><p style="margin:0 0 18px;font-size:13px;line-height:1.6;word-break:break-all"><a href="{{.security_url}}" style="color:#2563eb">{{.security_url}}</a></p><hr class="divider" style="border:none;border-top:1px solid #e5e7eb;margin:20px 0"><p class="muted" style="margin:0;font-size:12px;line-height:1.6;color:#6b7280">If this wasn’t you, sign in with your authenticator app or a backup code to cancel the recovery, then change your password immediately.</p><p class="muted" style="margin:12px 0 0;font-size:12px;line-height:1.6;color:#6b7280">Need help? Contact us at <a href="mailto:{{.support_email}}" style="color:#2563eb">{{.support_email}}</a>.</p></td></tr><tr><td align="center" style="padding:18px 24px"><p class="muted" style="margin:0;font-size:12px;line-height:1.6;color:#9ca3af">© {{.year}} {{.company_name}}. All rights reserved.</p><p class="muted" style="margin:6px 0 0;font-size:12px;line-height:1.6;color:#9ca3af">{{.company_address}}</p></td></tr></table></td></tr></table></body></html>$$
+    ),
+
+    (7, 'mfa_recovery_pending', 1, 1, 
+    'Two-factor authentication recovery in progress', 
+    'Hi {{full_name}}, two-factor authentication will be removed from your account after {{available_at}}. If this wasn''t you, sign in with your authenticator app to cancel it.'
+    ),
+
+    (8, 'mfa_recovery_completed', 1, 2, 
+    '[GoBite] Two-factor authentication was removed from your account', 
+    $$<!DOCTYPE html><html lang="en" xmlns="http://www.w3.org/1999/xhtml" xmlns:v="urn:schemas-microsoft-com:vml" xmlns:o="urn:schemas-microsoft-com:office:office"><head><meta charset="utf-8"><meta name="viewport" content="width=device-width,initial-scale=1"><meta name="x-apple-disable-message-reformatting"><meta http-equiv="X-UA-Compatible" content="IE=edge"><title>Your two-factor authentication was removed</title><!--[if mso]><xml><o:officedocumentsettings><o:pixelsperinch>96</o:pixelsperinch></o:officedocumentsettings></xml><![endif]--><style>body,html{margin:0!important;padding:0!important;height:100%!important;width:100%!important;font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Arial,sans-serif;background:#f6f7fb;color:#111827}table,td{border-collapse:collapse!important;mso-table-lspace:0!important;mso-table-rspace:0!important}img{-ms-interpolation-mode:bicubic;border:0;outline:0;text-decoration:none;display:block}a{text-decoration:none}@media screen and (max-width:600px){.container{width:100%!important}.px{padding-left:20px!important;padding-right:20px!important}.btn-wrap{width:100%!important}.btn-wrap td{width:100%!important}.btn td{display:block!important;width:100%!important}.btn a{display:block!important;width:100%!important}.logo{max-width:180px!important;height:auto!important}}@media (prefers-color-scheme:dark){body{background:#0b1220!important;color:#e5e7eb!important}.card{background:#111827!important}.muted{color:#9ca3af!important}.divider{border-color:#243244!important}}</style></head><body><div style="display:none;font-size:1px;color:#f6f7fb;line-height:1px;max-height:0;max-width:0;opacity:0;overflow:hidden">Two-factor authentication was removed from your account.</div><table role="presentation" width="100%" bgcolor="#f6f7fb" style="width:100%;background:#f6f7fb"><tr><td align="center" style="padding:40px 12px"><table role="presentation" class="container" width="600" style="width:600px;max-width:600px;border-radius:16px;overflow:hidden"><tr><td align="center" style="padding:22px 24px;background:#111827"><img src="https://www.nicehash.com/static/header.png" width="200" alt="{{.company_name}}" class="logo" style="max-width:200px;width:100%;height:auto;display:block;margin:0 auto"></td></tr><tr><td class="card" bgcolor="#ffffff" style="background:#fff;padding:28px 32px" class="px"><h1 style="margin:0 0 12px;font-size:22px;line-height:1.3;color:#111827">Two-factor authentication removed</h1><p class="muted" style="margin:0 0 18px;font-size:15px;line-height:1.6;color:#4b5563">Hi {{.full_name}}, the account recovery you started has finished and all two-factor authentication methods and backup codes were removed. We recommend setting up two-factor authentication again as soon as possible.</p><table role="presentation" border="0" cellpadding="0" cellspacing="0" width="100%" style="margin:22px 0"><tr><td align="left"><table role="presentation" border="0" cellpadding="0" cellspacing="0" class="btn-wrap" style="border-collapse:separate"><tr><td align="center" bgcolor="#2563eb" class="btn" style="border-radius:10px"><!--[if mso]><v:roundrect xmlns:v="urn:schemas-microsoft-com:vml" xmlns:w="urn:schemas-microsoft-com:office:word" href="{{.security_url}}" style="height:44px;v-text-anchor:middle;width:240px" arcsize="18%" stroke="f" fillcolor="#2563eb"><w:anchorlock><center style="color:#fff;font-family:Segoe UI,Arial,sans-serif;font-size:15px;font-weight:600">Security Settings</center></v:roundrect><![endif]--><!--[if !mso]><!-- --><a href="{{.security_url}}" target="_blank" style="font-size:15px;font-weight:600;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,Arial,sans-serif;color:#fff;text-decoration:none;padding:12px 18px;border-radius:10px;display:inline-block;mso-padding-alt:0">Security Settings</a><!--<![endif]--></td></tr></table></td></tr></table><p class="muted" style="margin:0 0 8px;font-size:13px;line-height:1.6;color:#6b7280">If the button doesn’t work, copy and paste this link into your browser:</p><p style="margin:0 0 18px;font-size:13px;line-height:1.6;word-break:break-all"><a href="{{.security_url}}" style="color:#2563eb">{{.security_url}}</a></p><hr class="divider" style="border:none;border-top:1px solid #e5e7eb;margin:20px 0"><p class="muted" style="margin:0;font-size:12px;line-height:1.6;color:#6b7280">If you didn’t recover your account, contact us immediately and change your password.</p><p class="muted" style="margin:12px 0 0;font-size:12px;line-height:1.6;color:#6b7280">Need help? Contact us at <a href="mailto:{{.support_email}}" style="color:#2563eb">{{.support_email}}</a>.</p></td></tr><tr><td align="center" style="padding:18px 24px"><p class="muted" style="margin:0;font-size:12px;line-height:1.6;color:#9ca3af">© {{.year}} {{.company_name}}. All rights reserved.</p><p class="muted" style="margin:6px 0 0;font-size:12px;line-height:1.6;color:#9ca3af">{{.company_address}}</p></td></tr></table></td></tr></table></body></html>$$
+    ),
+
+    (9, 'mfa_recovery_completed', 1, 1, 
+    'Two-factor authentication removed', 
+    'Hi {{full_name}}, your account recovery finished and two-factor authentication was removed. Set it up again from your security settings.'
+    );
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM notification_templates WHERE id IN (5, 6, 7, 8, 9);
+DELETE FROM notification_triggers WHERE key IN ('mfa_recovery_requested', 'mfa_recovery_pending', 'mfa_recovery_completed');
+-- +goose StatementEnd
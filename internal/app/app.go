@@ -7,22 +7,30 @@ import (
 	"github.com/casbin/casbin/v3"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
 	"github.com/shandysiswandi/gobite/internal/pkg/clock"
 	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/crypto"
 	"github.com/shandysiswandi/gobite/internal/pkg/goroutine"
 	"github.com/shandysiswandi/gobite/internal/pkg/hash"
 	"github.com/shandysiswandi/gobite/internal/pkg/idempotency"
 	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jobs"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 	"github.com/shandysiswandi/gobite/internal/pkg/mail"
 	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
 	"github.com/shandysiswandi/gobite/internal/pkg/mfa"
 	"github.com/shandysiswandi/gobite/internal/pkg/otp"
 	"github.com/shandysiswandi/gobite/internal/pkg/pgxcasbin"
+	"github.com/shandysiswandi/gobite/internal/pkg/pgxguard"
+	"github.com/shandysiswandi/gobite/internal/pkg/retention"
 	"github.com/shandysiswandi/gobite/internal/pkg/router"
+	"github.com/shandysiswandi/gobite/internal/pkg/signedurl"
 	"github.com/shandysiswandi/gobite/internal/pkg/storage"
 	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"github.com/shandysiswandi/gobite/internal/pkg/userdata"
 	"github.com/shandysiswandi/gobite/internal/pkg/validator"
+	"google.golang.org/grpc"
 )
 
 // App wires dependencies and manages service lifecycle.
@@ -30,6 +38,9 @@ type App struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
+	// checkOnly makes every subsystem required and skips the server and modules
+	checkOnly bool
+
 	// configuration
 	config config.Config
 	ins    instrument.Instrumentation
@@ -46,23 +57,33 @@ type App struct {
 	uuid            uid.StringID
 	totp            otp.OTP
 	jwt             jwt.JWT
+	columnCipher    *crypto.Cipher
 	mfaEncryptor    mfa.Encryptor
 	mfaRecoveryCode mfa.RecoveryCodeGenerator
+	signedURL       signedurl.Signer
 
 	// resources
 	dbConn        *pgxpool.Pool
+	moduleDBConns map[string]*pgxpool.Pool  // dedicated pools for modules with their own url or schema
+	dbGuards      map[string]*pgxguard.Pool // guarded pools keyed by module, "" for the shared pool
 	cacheConn     *redis.Client
 	idemp         idempotency.Idempotency
 	mail          mail.Mail
+	httpClient    *http.Client
 	messaging     messaging.Messaging
 	storage       storage.Storage
 	casbin        *casbin.Enforcer
 	casbinWatcher *pgxcasbin.Watcher
+	authzShadow   *authz.Shadow
+	retention     *retention.Scheduler
+	jobs          *jobs.Registry
+	userData      *userdata.Registry
 
 	// server
 	router     *router.Router
 	httpServer *http.Server
 	sseServer  *http.Server
+	grpcServer *grpc.Server // nil while app.server.grpc.enabled is off
 
 	//
 	closers []struct {
@@ -71,27 +92,88 @@ type App struct {
 	}
 }
 
-// New initializes the application with default wiring and returns an App instance.
-func New() *App {
+// New initializes the application with default wiring and returns an App instance. Every
+// startup failure is returned together, after the resources that did open are closed again.
+func New() (*App, error) {
+	app := newApp(false)
+	if err := app.initiate(); err != nil {
+		app.release(context.Background())
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// Check opens every connection the application needs, runs the storage self-check, and
+// closes them again without serving. Optional subsystems are required here, so the returned
+// error lists everything that would keep a real start from being fully functional.
+func Check() error {
+	app := newApp(true)
+	err := app.initiate()
+	app.release(context.Background())
+
+	return err
+}
+
+func newApp(checkOnly bool) *App {
 	ctx, cancel := context.WithCancel(context.Background())
-	app := &App{
-		ctx:    ctx,
-		cancel: cancel,
+
+	return &App{
+		ctx:       ctx,
+		cancel:    cancel,
+		checkOnly: checkOnly,
+	}
+}
+
+// initiate starts the subsystems from their dependency graph. The config is loaded first
+// since every step reads it; check mode stops after the connections and builds no server.
+func (a *App) initiate() error {
+	a.initClosers()
+
+	if err := a.initConfig(); err != nil {
+		return err
+	}
+
+	steps := []startupStep{
+		{name: "instrument", run: a.initInstrument},
+		{name: "libraries", deps: []string{"instrument"}, run: a.initLibraries},
+		{name: "jwt", deps: []string{"libraries"}, run: a.initJWT},
+		{name: "database", deps: []string{"instrument"}, run: a.initDatabase},
+		{name: "cache", run: a.initCache},
+		{name: "mail", run: a.initMail},
+		{name: "http_client", run: a.initHTTPClient},
+		{
+			name: "storage",
+			// uploads fail until it recovers, but sign-in and the rest keep working; an
+			// enabled self-check asks for the old fail-fast behavior
+			optional: !a.checkOnly && !a.config.GetBool("storage.self_check.enabled"),
+			run:      a.initStorage,
+			fallback: func(err error) {
+				a.storage = storage.NewUnavailable(err)
+			},
+		},
+		{name: "messaging", run: a.initMessaging},
+		{name: "casbin", deps: []string{"libraries", "database"}, run: a.initCasbin},
+	}
+
+	if !a.checkOnly {
+		moduleDeps := []string{"http_server", "grpc_server", "cache", "mail", "http_client", "storage", "messaging"}
+		if a.config.GetBool("messaging.topology.bootstrap") {
+			// consumers subscribe once the modules start, so their subscriptions must exist first
+			steps = append(steps, startupStep{name: "messaging_topology", deps: []string{"messaging"}, run: a.initMessagingTopology})
+			moduleDeps = append(moduleDeps, "messaging_topology")
+		}
+
+		steps = append(steps,
+			startupStep{name: "http_server", deps: []string{"jwt", "casbin", "cache"}, run: a.initHTTPServer},
+			startupStep{name: "grpc_server", deps: []string{"jwt", "cache"}, run: a.initGRPCServer},
+			startupStep{
+				name: "modules",
+				deps: moduleDeps,
+				run:  a.initModules,
+			},
+		)
 	}
 
-	app.initConfig()
-	app.initInstrument()
-	app.initLibraries()
-	app.initJWT()
-	app.initDatabase()
-	app.initCache()
-	app.initMail()
-	app.initStorage()
-	app.initMessaging()
-	app.initCasbin()
-	app.initHTTPServer()
-	app.initModules()
-	app.initClosers()
-
-	return app
+	return runStartup(steps)
 }
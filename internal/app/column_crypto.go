@@ -0,0 +1,48 @@
+package app
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/crypto"
+)
+
+// columnKeyRing builds the keys of encrypted columns. mfa.secret is key version 1, the key
+// every value was sealed with before rotation existed; crypto.column.keys adds later versions.
+func (a *App) columnKeyRing() (*crypto.KeyRing, error) {
+	keys := map[uint16][]byte{}
+
+	legacy, err := base64.StdEncoding.DecodeString(a.config.GetString("mfa.secret"))
+	if err != nil {
+		return nil, fmt.Errorf("decode mfa secret: %w", err)
+	}
+	if len(legacy) != 32 {
+		return nil, errors.New("init column crypto: mfa secret must be 32 bytes (AES-256)")
+	}
+	keys[1] = legacy
+
+	for raw, encoded := range a.config.GetMap("crypto.column.keys") {
+		version, err := strconv.ParseUint(raw, 10, 16)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("init column crypto: invalid key version %q", raw)
+		}
+		if version == 1 {
+			return nil, errors.New("init column crypto: key version 1 is mfa.secret")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decode column key version %d: %w", version, err)
+		}
+		keys[uint16(version)] = key
+	}
+
+	current := max(a.config.GetUint16("crypto.column.current_key_version"), 1)
+	ring, err := crypto.NewKeyRing(keys, current)
+	if err != nil {
+		return nil, fmt.Errorf("init column crypto: %w", err)
+	}
+
+	return ring, nil
+}
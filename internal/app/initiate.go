@@ -2,7 +2,7 @@ package app
 
 import (
 	"context"
-	"encoding/base64"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -12,33 +12,46 @@ import (
 	gcs "cloud.google.com/go/storage"
 	"github.com/casbin/casbin/v3"
 	"github.com/casbin/casbin/v3/model"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/nats-io/nats.go"
 	"github.com/nsqio/go-nsq"
 	libOTP "github.com/pquerna/otp"
 	"github.com/redis/go-redis/v9"
-	"github.com/rs/cors"
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
 	"github.com/shandysiswandi/gobite/internal/pkg/clock"
 	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/crypto"
+	"github.com/shandysiswandi/gobite/internal/pkg/denylist"
 	"github.com/shandysiswandi/gobite/internal/pkg/goroutine"
+	"github.com/shandysiswandi/gobite/internal/pkg/grpcserver"
 	"github.com/shandysiswandi/gobite/internal/pkg/hash"
+	"github.com/shandysiswandi/gobite/internal/pkg/httpclient"
 	"github.com/shandysiswandi/gobite/internal/pkg/idempotency"
 	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jobs"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 	"github.com/shandysiswandi/gobite/internal/pkg/mail"
 	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
 	"github.com/shandysiswandi/gobite/internal/pkg/mfa"
 	"github.com/shandysiswandi/gobite/internal/pkg/otp"
 	"github.com/shandysiswandi/gobite/internal/pkg/pgxcasbin"
+	"github.com/shandysiswandi/gobite/internal/pkg/pgxguard"
+	"github.com/shandysiswandi/gobite/internal/pkg/replay"
+	"github.com/shandysiswandi/gobite/internal/pkg/resilience"
+	"github.com/shandysiswandi/gobite/internal/pkg/retention"
 	"github.com/shandysiswandi/gobite/internal/pkg/router"
+	"github.com/shandysiswandi/gobite/internal/pkg/signedurl"
 	"github.com/shandysiswandi/gobite/internal/pkg/storage"
+	"github.com/shandysiswandi/gobite/internal/pkg/throttle"
 	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"github.com/shandysiswandi/gobite/internal/pkg/userdata"
 	"github.com/shandysiswandi/gobite/internal/pkg/validator"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/option"
 )
 
-func (a *App) initConfig() {
+func (a *App) initConfig() error {
 	path := os.Getenv("CONFIG_PATH")
 	if path == "" {
 		path = "/config/config.yaml"
@@ -49,17 +62,18 @@ func (a *App) initConfig() {
 
 	cfg, err := config.NewViper(path)
 	if err != nil {
-		slog.Error("failed to init config", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("init config: %w", err)
 	}
 
 	//nolint:errcheck,gosec // ignore error
 	os.Setenv("TZ", cfg.GetString("app.tz"))
 
 	a.config = cfg
+
+	return nil
 }
 
-func (a *App) initInstrument() {
+func (a *App) initInstrument() error {
 	ins, err := instrument.New(context.Background(), &instrument.Config{
 		Enabled:          true,
 		ServiceName:      a.config.GetString("instrument.service_name"),
@@ -72,38 +86,40 @@ func (a *App) initInstrument() {
 		MaskFields:       a.config.GetArray("instrument.log_mask_fields"),
 	})
 	if err != nil {
-		slog.Error("failed to init instrumentation", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("init instrumentation: %w", err)
 	}
 	a.ins = ins
+
+	return nil
 }
 
-func (a *App) initLibraries() {
+func (a *App) initLibraries() error {
 	a.clock = clock.New()
 	a.uuid = uid.NewUUID()
 	a.goroutine = goroutine.NewManager(a.config.GetInt("app.server.max_goroutine"))
 	a.hmac = hash.NewHMACSHA256(a.config.GetString("hash.hmac.secret"))
 	a.argon2id = hash.NewArgon2id(a.config.GetString("hash.argon2id.pepper"))
 	a.bcrypt = hash.NewBcrypt(a.config.GetInt("hash.bcrypt.cost"), a.config.GetString("hash.bcrypt.pepper"))
+	a.signedURL = signedurl.NewHMAC(a.config.GetString("signed_url.secret"))
+	a.retention = retention.NewScheduler(a.config, a.clock, a.ins)
+	a.jobs = jobs.NewRegistry(a.goroutine, a.clock, a.ins)
+	a.userData = userdata.NewRegistry()
 
 	validator, err := validator.NewV10Validator()
 	if err != nil {
-		slog.Error("failed to init validation v10 validator", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("init validation v10 validator: %w", err)
 	}
 	a.validator = validator
 
 	snow, err := uid.NewSnowflake()
 	if err != nil {
-		slog.Error("failed to init uid number snowflake", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("init uid number snowflake: %w", err)
 	}
 	a.uid = snow
 
 	objID, err := uid.NewObjectIDGenerator()
 	if err != nil {
-		slog.Error("failed to init uid string object_id", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("init uid string object_id: %w", err)
 	}
 	a.oid = objID
 
@@ -114,40 +130,142 @@ func (a *App) initLibraries() {
 		libOTP.DigitsSix,
 	)
 
-	rawKey, err := base64.StdEncoding.DecodeString(a.config.GetString("mfa.secret"))
+	ring, err := a.columnKeyRing()
 	if err != nil {
-		slog.Error("failed to decode mfa secret", "error", err)
-		os.Exit(1)
-	}
-	if len(rawKey) != 32 {
-		slog.Error("failed to init mfacrypto, secret must be 32 bytes (AES-256)", "error", err)
-		os.Exit(1)
+		return err
 	}
-	a.mfaEncryptor = mfa.NewAESGCMEncryptor(mfa.StaticKeyProvider{KeyBytes: rawKey})
+	a.columnCipher = crypto.NewCipher(ring)
+	a.mfaEncryptor = mfa.NewAESGCMEncryptor(a.columnCipher)
 	a.mfaRecoveryCode = mfa.NewRecoveryCode()
+
+	return nil
 }
 
-func (a *App) initJWT() {
-	defaultJWT, err := jwt.NewHS512(jwt.Config{
+func (a *App) initJWT() error {
+	cfg := jwt.Config{
 		Secret:     []byte(a.config.GetString("jwt.secret")),
 		Issuer:     a.config.GetString("jwt.issuer"),
 		Audiences:  a.config.GetArray("jwt.audiences"),
 		TTLMinutes: a.config.GetMinute("jwt.ttl_minutes"),
 		Clock:      a.clock,
 		UUID:       a.uuid,
-	})
+	}
+
+	var (
+		defaultJWT interface {
+			jwt.JWT
+			Reload(ctx context.Context) error
+		}
+		err error
+	)
+	switch alg := strings.ToUpper(strings.TrimSpace(a.config.GetString("jwt.algorithm"))); alg {
+	case "", "HS512":
+		if len(a.config.GetMap("jwt.secret_files")) > 0 {
+			cfg.Keys = jwtKeySource{cfg: a.config}
+			cfg.RejectKeyless = a.config.GetBool("jwt.reject_keyless_tokens")
+		}
+		defaultJWT, err = jwt.NewHS512(cfg)
+	case "RS256":
+		cfg.Keys = jwtKeySource{cfg: a.config}
+		defaultJWT, err = jwt.NewRS256(cfg)
+	case "ES256":
+		cfg.Keys = jwtKeySource{cfg: a.config}
+		defaultJWT, err = jwt.NewES256(cfg)
+	case "V4.LOCAL":
+		if len(a.config.GetMap("jwt.secret_files")) > 0 {
+			cfg.Keys = jwtKeySource{cfg: a.config}
+		}
+		defaultJWT, err = jwt.NewPasetoLocal(cfg)
+	case "V4.PUBLIC":
+		cfg.Keys = jwtKeySource{cfg: a.config}
+		defaultJWT, err = jwt.NewPasetoPublic(cfg)
+	default:
+		return fmt.Errorf("init jwt token: unsupported algorithm %q", alg)
+	}
 	if err != nil {
-		slog.Error("failed to init jwt token", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("init jwt token: %w", err)
 	}
 	a.jwt = defaultJWT
+
+	a.jobs.Schedule(jobs.Job{
+		Name:     "jwt_keys_reload",
+		Interval: a.config.GetSecond("jwt.keys_reload_seconds"),
+		Run:      defaultJWT.Reload,
+	})
+
+	return nil
 }
 
-func (a *App) initDatabase() {
-	config, err := pgxpool.ParseConfig(a.config.GetString("database.url"))
+func (a *App) initDatabase() error {
+	dbURL := a.config.GetString("database.url")
+
+	pool, err := a.newDBPool(dbURL, "", "")
 	if err != nil {
-		slog.Error("failed to parse DB connection string.", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("init DB connection pool: %w", err)
+	}
+
+	a.dbConn = pool
+	a.moduleDBConns = make(map[string]*pgxpool.Pool)
+
+	// modules may target their own schema or database so they can later be split out without a data rewrite
+	for _, module := range []string{"identity", "notification", "audit"} {
+		moduleURL := a.config.GetString("modules." + module + ".database.url")
+		schema := a.config.GetString("modules." + module + ".database.schema")
+		role := a.config.GetString("modules." + module + ".database.role")
+		if moduleURL == "" && schema == "" && role == "" {
+			continue
+		}
+
+		if moduleURL == "" {
+			moduleURL = dbURL
+		}
+		if schema != "" && role == "" {
+			slog.WarnContext(a.ctx, "module schema is only selected by search_path, set a role to enforce isolation",
+				"module", module, "schema", schema)
+		}
+
+		pool, err := a.newDBPool(moduleURL, schema, role)
+		if err != nil {
+			return fmt.Errorf("init module %s DB connection pool: %w", module, err)
+		}
+
+		a.moduleDBConns[module] = pool
+	}
+
+	a.dbGuards = make(map[string]*pgxguard.Pool)
+	guardCfg := pgxguard.Config{
+		AcquireTimeout: time.Duration(a.config.GetInt("database.guard.acquire_timeout_ms")) * time.Millisecond,
+		Breaker: resilience.BreakerConfig{
+			FailureThreshold: a.config.GetInt("database.guard.failure_threshold"),
+			OpenTimeout:      a.config.GetSecond("database.guard.open_timeout_seconds"),
+		},
+		PropagateDeadline: a.config.GetBool("database.deadline.propagate"),
+	}
+
+	guard, err := pgxguard.New("shared", a.dbConn, a.ins.Meter("db.pool"), guardCfg)
+	if err != nil {
+		return fmt.Errorf("init DB pool guard: %w", err)
+	}
+	a.dbGuards[""] = guard
+
+	for module, pool := range a.moduleDBConns {
+		guard, err := pgxguard.New(module, pool, a.ins.Meter("db.pool"), guardCfg)
+		if err != nil {
+			return fmt.Errorf("init module %s DB pool guard: %w", module, err)
+		}
+		a.dbGuards[module] = guard
+	}
+
+	return nil
+}
+
+// newDBPool creates a ping-checked pool; a non-empty schema is applied as the connection search_path.
+// A non-empty role is assumed with SET ROLE on every new connection, so the database itself denies
+// the module the tables of schemas its role was not granted.
+func (a *App) newDBPool(dsn, schema, role string) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
 	}
 
 	config.MaxConns = a.config.GetInt32("database.pool.max_conns")
@@ -156,27 +274,75 @@ func (a *App) initDatabase() {
 	config.MaxConnIdleTime = a.config.GetSecond("database.pool.max_conn_idle_seconds")
 	config.HealthCheckPeriod = a.config.GetSecond("database.pool.health_check_period_seconds")
 
+	if schema != "" {
+		config.ConnConfig.RuntimeParams["search_path"] = schema
+	}
+
+	if role != "" {
+		setRole := "SET ROLE " + pgx.Identifier{role}.Sanitize()
+		config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+			_, err := conn.Exec(ctx, setRole)
+			return err
+		}
+	}
+
+	if a.config.GetBool("database.deadline.cancel_on_context_done") {
+		pgxguard.CancelOnContextDone(config)
+	}
+
 	pool, err := pgxpool.NewWithConfig(a.ctx, config)
 	if err != nil {
-		slog.Error("failed to create DB connection pool", "error", err)
-		os.Exit(1)
+		return nil, err
 	}
 
 	pingCtx, cancel := context.WithTimeout(a.ctx, 5*time.Second)
 	defer cancel()
 	if err := pool.Ping(pingCtx); err != nil {
-		slog.Error("failed to ping DB", "error", err)
-		os.Exit(1)
+		pool.Close()
+		return nil, err
 	}
 
-	a.dbConn = pool
+	return pool, nil
+}
+
+// moduleDBConn returns the dedicated pool of a module, falling back to the shared pool.
+func (a *App) moduleDBConn(module string) *pgxpool.Pool {
+	if pool, ok := a.moduleDBConns[module]; ok {
+		return pool
+	}
+
+	return a.dbConn
 }
 
-func (a *App) initCache() {
+// newResiliencePolicy builds an outbound call policy from the config section at prefix.
+func (a *App) newResiliencePolicy(prefix string) *resilience.Policy {
+	return resilience.NewPolicy(resilience.PolicyConfig{
+		Timeout: a.config.GetSecond(prefix + ".timeout_seconds"),
+		Retry: resilience.RetryConfig{
+			MaxAttempts: a.config.GetInt(prefix + ".max_attempts"),
+			BaseDelay:   time.Duration(a.config.GetInt(prefix+".base_delay_ms")) * time.Millisecond,
+			MaxDelay:    time.Duration(a.config.GetInt(prefix+".max_delay_ms")) * time.Millisecond,
+		},
+		Breaker: resilience.BreakerConfig{
+			FailureThreshold: a.config.GetInt(prefix + ".failure_threshold"),
+			OpenTimeout:      a.config.GetSecond(prefix + ".open_timeout_seconds"),
+		},
+	})
+}
+
+// moduleDB returns the guarded pool a module should run its queries through.
+func (a *App) moduleDB(module string) *pgxguard.Pool {
+	if guard, ok := a.dbGuards[module]; ok {
+		return guard
+	}
+
+	return a.dbGuards[""]
+}
+
+func (a *App) initCache() error {
 	opt, err := redis.ParseURL(a.config.GetString("redis.url"))
 	if err != nil {
-		slog.Error("failed to parse redis url", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("parse redis url: %w", err)
 	}
 
 	rdb := redis.NewClient(opt)
@@ -184,16 +350,17 @@ func (a *App) initCache() {
 	pingCtx, cancel := context.WithTimeout(a.ctx, 5*time.Second)
 	defer cancel()
 	if err := rdb.Ping(pingCtx).Err(); err != nil {
-		slog.Error("failed to init redis", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("init redis: %w", err)
 	}
 
 	a.cacheConn = rdb
 	a.idemp = idempotency.New(a.cacheConn)
+
+	return nil
 }
 
-func (a *App) initMail() {
-	mail, err := mail.NewSMTP(mail.SMTPConfig{
+func (a *App) initMail() error {
+	smtp, err := mail.NewSMTP(mail.SMTPConfig{
 		Host:     a.config.GetString("mail.host"),
 		Port:     a.config.GetInt("mail.port"),
 		Username: a.config.GetString("mail.username"),
@@ -201,15 +368,46 @@ func (a *App) initMail() {
 		From:     a.config.GetString("mail.from"),
 	})
 	if err != nil {
-		slog.Error("failed to init mail", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("init mail: %w", err)
+	}
+
+	// staging environments redirect or capture mail so real users are never reached
+	preview, err := mail.NewPreview(smtp, mail.PreviewConfig{
+		Mode:       a.config.GetString("mail.preview.mode"),
+		RedirectTo: a.config.GetString("mail.preview.redirect_to"),
+		Allowlist:  a.config.GetArray("mail.preview.allowlist"),
+		CaptureDir: a.config.GetString("mail.preview.capture_dir"),
+	})
+	if err != nil {
+		return fmt.Errorf("init mail preview: %w", err)
 	}
 
-	a.mail = mail
+	// a slow or failing provider must not hold notification workers hostage
+	a.mail = mail.NewPropagating(mail.NewResilient(preview, a.newResiliencePolicy("mail.resilience")))
+
+	return nil
+}
+
+func (a *App) initHTTPClient() error {
+	a.httpClient = httpclient.New(httpclient.Config{
+		Timeout:               a.config.GetSecond("http_client.timeout_seconds"),
+		DialTimeout:           a.config.GetSecond("http_client.dial_timeout_seconds"),
+		TLSHandshakeTimeout:   a.config.GetSecond("http_client.tls_handshake_timeout_seconds"),
+		ResponseHeaderTimeout: a.config.GetSecond("http_client.response_header_timeout_seconds"),
+		MaxIdleConnsPerHost:   a.config.GetInt("http_client.max_idle_conns_per_host"),
+		Retry: resilience.RetryConfig{
+			MaxAttempts: a.config.GetInt("http_client.retry.max_attempts"),
+			BaseDelay:   time.Duration(a.config.GetInt("http_client.retry.base_delay_ms")) * time.Millisecond,
+			MaxDelay:    time.Duration(a.config.GetInt("http_client.retry.max_delay_ms")) * time.Millisecond,
+		},
+		InternalHosts: a.config.GetArray("http_client.internal_hosts"),
+	})
+
+	return nil
 }
 
 //nolint:gocognit // it's fine
-func (a *App) initStorage() {
+func (a *App) initStorage() error {
 	driver := strings.TrimSpace(a.config.GetString("storage.driver"))
 
 	var gcsClient *gcs.Client
@@ -222,21 +420,18 @@ func (a *App) initStorage() {
 			// #nosec G304 -- path is from trusted config file.
 			credsJSON, err := os.ReadFile(v)
 			if err != nil {
-				slog.Error("failed to read gcs credentials file", "error", err)
-				os.Exit(1)
+				return fmt.Errorf("read gcs credentials file: %w", err)
 			}
 			creds, err := google.CredentialsFromJSON(a.ctx, credsJSON, gcs.ScopeFullControl)
 			if err != nil {
-				slog.Error("failed to parse gcs credentials file", "error", err)
-				os.Exit(1)
+				return fmt.Errorf("parse gcs credentials file: %w", err)
 			}
 			gcsOptions = append(gcsOptions, option.WithCredentials(creds))
 		}
 		if v := a.config.GetBinary("storage.gcs.credentials_json"); len(v) > 0 {
 			creds, err := google.CredentialsFromJSON(a.ctx, v, gcs.ScopeFullControl)
 			if err != nil {
-				slog.Error("failed to parse gcs credentials json", "error", err)
-				os.Exit(1)
+				return fmt.Errorf("parse gcs credentials json: %w", err)
 			}
 			gcsOptions = append(gcsOptions, option.WithCredentials(creds))
 		}
@@ -249,8 +444,7 @@ func (a *App) initStorage() {
 		if len(gcsOptions) > 0 {
 			client, err := gcs.NewClient(a.ctx, gcsOptions...)
 			if err != nil {
-				slog.Error("failed to init gcs client", "error", err)
-				os.Exit(1)
+				return fmt.Errorf("init gcs client: %w", err)
 			}
 			gcsClient = client
 		}
@@ -278,22 +472,70 @@ func (a *App) initStorage() {
 			SessionToken: strings.TrimSpace(a.config.GetString("storage.minio.session_token")),
 			UseSSL:       a.config.GetBool("storage.minio.use_ssl"),
 		},
+		Params: a.config.GetMap("storage.params"),
 	})
 	if err != nil {
-		slog.Error("failed to init storage", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("init storage: %w", err)
+	}
+
+	a.storage = storage.NewPropagating(stg)
+
+	if a.config.GetBool("storage.self_check.enabled") || a.checkOnly {
+		return a.checkStorage()
+	}
+
+	return nil
+}
+
+// checkStorage probes every bucket the enabled features write to, so a bad endpoint,
+// credential, or bucket policy stops the app at start instead of failing the first upload.
+func (a *App) checkStorage() error {
+	buckets := a.config.GetArray("storage.self_check.buckets")
+	if a.config.GetBool("modules.identity.enabled") {
+		buckets = append(buckets, a.config.GetString("modules.identity.avatar_bucket"))
+		if a.config.GetBool("modules.identity.audit_archive_enabled") {
+			buckets = append(buckets, a.config.GetString("modules.identity.audit_archive_bucket"))
+		}
+	}
+	if a.config.GetBool("modules.notification.enabled") && a.config.GetBool("modules.notification.archive.enabled") {
+		buckets = append(buckets, a.config.GetString("modules.notification.archive.bucket"))
+	}
+
+	timeout := a.config.GetSecond("storage.self_check.timeout_seconds")
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, timeout)
+	defer cancel()
+
+	prefix := a.config.GetString("storage.self_check.prefix")
+	checked := map[string]bool{}
+	for _, bucket := range buckets {
+		bucket = strings.TrimSpace(bucket)
+		if bucket == "" || checked[bucket] {
+			continue
+		}
+		checked[bucket] = true
+
+		if err := storage.Probe(ctx, a.storage, bucket, prefix); err != nil {
+			return fmt.Errorf("storage self-check: %w", err)
+		}
+
+		slog.Info("storage self-check passed", "bucket", bucket)
 	}
 
-	a.storage = stg
+	return nil
 }
 
-func (a *App) initMessaging() {
+func (a *App) initMessaging() error {
 	driver := a.config.GetString("messaging.driver")
 	client, err := messaging.NewFromDriver(a.ctx, driver, messaging.FactoryOptions{
 		NSQ: messaging.NSQConfig{
 			ProducerAddr:         a.config.GetString("messaging.nsq.producer_addr"),
 			ConsumerNSQDAddrs:    a.config.GetArray("messaging.nsq.consumer_nsqd_addrs"),
 			ConsumerLookupdAddrs: a.config.GetArray("messaging.nsq.consumer_lookupd_addrs"),
+			HTTPAddr:             a.config.GetString("messaging.nsq.http_addr"),
 			ProducerConfig: func() *nsq.Config {
 				cfg := nsq.NewConfig()
 				cfg.MaxInFlight = a.config.GetInt("messaging.nsq.producer_config.max_in_flight")
@@ -315,6 +557,16 @@ func (a *App) initMessaging() {
 				return cfg
 			}(),
 		},
+		Kafka: messaging.KafkaConfig{
+			Brokers:     a.config.GetArray("messaging.kafka.brokers"),
+			DialTimeout: a.config.GetSecond("messaging.kafka.dial_timeout"),
+			SASL: messaging.KafkaSASLConfig{
+				Mechanism: a.config.GetString("messaging.kafka.sasl.mechanism"),
+				Username:  a.config.GetString("messaging.kafka.sasl.username"),
+				Password:  a.config.GetString("messaging.kafka.sasl.password"),
+			},
+			TLS: a.messagingTLSConfig("messaging.kafka.tls"),
+		},
 		NATS: messaging.NATSConfig{
 			URL: a.config.GetString("messaging.nats.url"),
 			Options: []nats.Option{
@@ -327,52 +579,73 @@ func (a *App) initMessaging() {
 				nats.RetryOnFailedConnect(a.config.GetBool("messaging.nats.retry_on_failed_connect")),
 				// nats.NoEcho(), if a.config.GetBool("messaging.nats.no_echo") == true
 			},
+			TLS:             a.messagingTLSConfig("messaging.nats.tls"),
+			CredentialsFile: a.config.GetString("messaging.nats.credentials_file"),
+			NKeySeedFile:    a.config.GetString("messaging.nats.nkey_seed_file"),
+			Username:        a.config.GetString("messaging.nats.username"),
+			Password:        a.config.GetString("messaging.nats.password"),
 		},
 	})
 	if err != nil {
-		slog.Error("failed to init messaging", "error", err, "driver", driver)
-		os.Exit(1)
+		return fmt.Errorf("init messaging driver %s: %w", driver, err)
 	}
 
-	a.messaging = client
+	a.messaging = messaging.NewPropagating(client)
+
+	return nil
 }
 
-func (a *App) initCasbin() {
+// messagingTLSConfig reads broker TLS settings from the config section at prefix.
+func (a *App) messagingTLSConfig(prefix string) messaging.TLSConfig {
+	return messaging.TLSConfig{
+		Enabled:            a.config.GetBool(prefix + ".enabled"),
+		CAFile:             a.config.GetString(prefix + ".ca_file"),
+		CertFile:           a.config.GetString(prefix + ".cert_file"),
+		KeyFile:            a.config.GetString(prefix + ".key_file"),
+		ServerName:         a.config.GetString(prefix + ".server_name"),
+		InsecureSkipVerify: a.config.GetBool(prefix + ".insecure_skip_verify"),
+	}
+}
+
+func (a *App) initCasbin() error {
+	// r/p/g are the global roles. r2/p2/g2 are the roles a user holds inside one
+	// organization, the domain, enforced with casbin.NewEnforceContext("2").
 	const rbacModel = `
 [request_definition]
 r = sub, obj, act
+r2 = sub, dom, obj, act
 
 [policy_definition]
 p = sub, obj, act
+p2 = sub, dom, obj, act
 
 [role_definition]
 g = _, _
+g2 = _, _, _
 
 [policy_effect]
 e = some(where (p.eft == allow))
 
 [matchers]
 m = g(r.sub, p.sub) && (p.obj == "*" || r.obj == p.obj) && (p.act == "*" || r.act == p.act)
+m2 = g2(r2.sub, p2.sub, r2.dom) && (p2.dom == "*" || r2.dom == p2.dom) && (p2.obj == "*" || r2.obj == p2.obj) && (p2.act == "*" || r2.act == p2.act)
 `
 	m, err := model.NewModelFromString(rbacModel)
 	if err != nil {
-		slog.Error("failed to create model casbin", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("create model casbin: %w", err)
 	}
 
-	adapter, err := pgxcasbin.NewAdapter(a.ctx, a.dbConn, pgxcasbin.WithTableName("identity_casbin_rules"))
+	adapter, err := pgxcasbin.NewAdapter(a.ctx, a.moduleDBConn("identity"), pgxcasbin.WithTableName("identity_casbin_rules"))
 	if err != nil {
-		slog.Error("failed to create adapter casbin", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("create adapter casbin: %w", err)
 	}
 
 	e, err := casbin.NewEnforcer(m, adapter)
 	if err != nil {
-		slog.Error("failed to init casbin", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("init casbin: %w", err)
 	}
 
-	watcher, err := pgxcasbin.NewWatcherWithPool(a.ctx, a.dbConn,
+	watcher, err := pgxcasbin.NewWatcherWithPool(a.ctx, a.moduleDBConn("identity"),
 		pgxcasbin.OptionWatcher{
 			NotifySelf: true,
 			Channel:    "iam_casbin_psql_watcher",
@@ -381,18 +654,15 @@ m = g(r.sub, p.sub) && (p.obj == "*" || r.obj == p.obj) && (p.act == "*" || r.ac
 		},
 	)
 	if err != nil {
-		slog.Error("failed to create watcher casbin", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("create watcher casbin: %w", err)
 	}
 
 	if err := watcher.SetUpdateCallback(pgxcasbin.DefaultCallback(e)); err != nil {
-		slog.Error("failed to create watcher fallback casbin", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("create watcher fallback casbin: %w", err)
 	}
 
 	if err := e.SetWatcher(watcher); err != nil {
-		slog.Error("failed to set watcher casbin", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("set watcher casbin: %w", err)
 	}
 
 	e.EnableAutoSave(true)
@@ -400,30 +670,65 @@ m = g(r.sub, p.sub) && (p.obj == "*" || r.obj == p.obj) && (p.act == "*" || r.ac
 
 	a.casbin = e
 	a.casbinWatcher = watcher
+
+	if a.config.GetBool("authz.shadow.enabled") {
+		return a.initCasbinShadow(rbacModel)
+	}
+
+	return nil
+}
+
+// initCasbinShadow loads the candidate policy table evaluated in shadow mode. It uses the
+// active model, never saves, and is reloaded on an interval instead of through the watcher.
+func (a *App) initCasbinShadow(rbacModel string) error {
+	m, err := model.NewModelFromString(rbacModel)
+	if err != nil {
+		return fmt.Errorf("create shadow model casbin: %w", err)
+	}
+
+	adapter, err := pgxcasbin.NewAdapter(a.ctx, a.moduleDBConn("identity"),
+		pgxcasbin.WithTableName(a.config.GetString("authz.shadow.table")))
+	if err != nil {
+		return fmt.Errorf("create shadow adapter casbin: %w", err)
+	}
+
+	e, err := casbin.NewSyncedEnforcer(m, adapter)
+	if err != nil {
+		return fmt.Errorf("init shadow casbin: %w", err)
+	}
+
+	e.EnableAutoSave(false)
+
+	a.authzShadow = authz.NewShadow(e, a.ins)
+	a.jobs.Schedule(jobs.Job{
+		Name:     "authz_shadow_reload",
+		Interval: a.config.GetSecond("authz.shadow.reload_seconds"),
+		Run:      a.authzShadow.Reload,
+	})
+
+	return nil
 }
 
-func (a *App) initHTTPServer() {
+func (a *App) initHTTPServer() error {
 	a.router = router.NewRouter(router.Config{
 		Config:     a.config,
 		UUID:       a.uuid,
 		JWT:        a.jwt,
 		Instrument: a.ins,
 		Enforcer:   a.casbin,
+		Denylist:   denylist.New(a.cacheConn),
+		// a Redis outage falls back to per-instance buckets instead of dropping the limits
+		RateLimiter: throttle.NewFallback(throttle.NewBucket(a.cacheConn), throttle.NewMemoryBucket()),
+		Replay:      replay.New(a.cacheConn),
 	})
 
-	routerWithCORS := cors.New(cors.Options{
-		AllowedOrigins: a.config.GetArray("app.server.cors"),
-		AllowedMethods: []string{
-			http.MethodGet,
-			http.MethodPost,
-			http.MethodPut,
-			http.MethodPatch,
-			http.MethodDelete,
-			http.MethodOptions,
-		},
-		AllowedHeaders:   []string{"*"},
-		AllowCredentials: true,
-	}).Handler(a.router)
+	corsPolicy := router.NewCORS(a.config)
+	a.jobs.Schedule(jobs.Job{
+		Name:     "cors_reload",
+		Interval: a.config.GetSecond("app.server.cors.reload_seconds"),
+		Run:      corsPolicy.Reload,
+	})
+	routerWithCORS := corsPolicy.Handler(a.router)
 
 	a.httpServer = &http.Server{
 		Addr:              a.config.GetString("app.server.http.address"),
@@ -439,8 +744,35 @@ func (a *App) initHTTPServer() {
 		Handler:           routerWithCORS,
 		ReadHeaderTimeout: a.config.GetSecond("app.server.sse.read_header_timeout_seconds"),
 	}
+
+	// both servers hand WebSocket connections over to the router, which must close them
+	a.httpServer.RegisterOnShutdown(a.router.CloseWebSockets)
+	a.sseServer.RegisterOnShutdown(a.router.CloseWebSockets)
+
+	return nil
+}
+
+// initGRPCServer builds the gRPC server the modules register their services on, when
+// app.server.grpc.enabled is on.
+func (a *App) initGRPCServer() error {
+	if !a.config.GetBool("app.server.grpc.enabled") {
+		return nil
+	}
+
+	a.grpcServer = grpcserver.New(grpcserver.Config{
+		UUID:        a.uuid,
+		JWT:         a.jwt,
+		Instrument:  a.ins,
+		Denylist:    denylist.New(a.cacheConn),
+		Config:      a.config,
+		RateLimiter: throttle.NewFallback(throttle.NewBucket(a.cacheConn), throttle.NewMemoryBucket()),
+	})
+
+	return nil
 }
 
+// initClosers registers the resource closers. Each one skips a resource that never started,
+// so they are safe to run after a startup that failed halfway.
 func (a *App) initClosers() {
 	a.closers = []struct {
 		name string
@@ -449,12 +781,20 @@ func (a *App) initClosers() {
 		{
 			name: "Instrument",
 			fn: func(ctx context.Context) error {
+				if a.ins == nil {
+					return nil
+				}
+
 				return a.ins.Shutdown(ctx)
 			},
 		},
 		{
 			name: "Messaging",
 			fn: func(context.Context) error {
+				if a.messaging == nil {
+					return nil
+				}
+
 				return a.messaging.Close()
 			},
 		},
@@ -471,13 +811,22 @@ func (a *App) initClosers() {
 		{
 			name: "Redis",
 			fn: func(context.Context) error {
+				if a.cacheConn == nil {
+					return nil
+				}
+
 				return a.cacheConn.Close()
 			},
 		},
 		{
 			name: "Database",
 			fn: func(context.Context) error {
-				a.dbConn.Close()
+				for _, pool := range a.moduleDBConns {
+					pool.Close()
+				}
+				if a.dbConn != nil {
+					a.dbConn.Close()
+				}
 
 				return nil
 			},
@@ -485,12 +834,20 @@ func (a *App) initClosers() {
 		{
 			name: "Storage",
 			fn: func(context.Context) error {
+				if a.storage == nil {
+					return nil
+				}
+
 				return a.storage.Close()
 			},
 		},
 		{
 			name: "Config",
 			fn: func(context.Context) error {
+				if a.config == nil {
+					return nil
+				}
+
 				return a.config.Close()
 			},
 		},
@@ -0,0 +1,236 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/casbin/casbin/v3"
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
+	"github.com/shandysiswandi/gobite/internal/pkg/clock"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jobs"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
+	"github.com/shandysiswandi/gobite/internal/pkg/router"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+const (
+	// jobsAuditModule names the app as the source of the job runs it records.
+	jobsAuditModule = "admin"
+
+	jobsAuditActionRun = "job.run"
+
+	maxJobNameLength = 128
+)
+
+// jobsDashboard serves the admin view of the background jobs. The registry belongs to the
+// app rather than to a module, so the dashboard is registered here and stays available
+// whichever modules are enabled.
+type jobsDashboard struct {
+	jobs        *jobs.Registry
+	enforcer    *casbin.Enforcer
+	authzShadow *authz.Shadow
+	messaging   messaging.Messaging
+	uuid        uid.StringID
+	clock       clock.Clocker
+	ins         instrument.Instrumentation
+}
+
+func (a *App) registerJobsDashboard() {
+	d := &jobsDashboard{
+		jobs:        a.jobs,
+		enforcer:    a.casbin,
+		authzShadow: a.authzShadow,
+		messaging:   a.messaging,
+		uuid:        a.uuid,
+		clock:       a.clock,
+		ins:         a.ins,
+	}
+
+	// Admin background jobs (need authenticated & authorization)
+	a.router.GET("/api/v1/admin/jobs", d.ListJobs)
+	a.router.POST("/api/v1/admin/jobs/:name/run", d.RunJob)
+}
+
+type JobResponse struct {
+	Name            string     `json:"name"`
+	Kind            string     `json:"kind"`
+	IntervalSeconds int64      `json:"interval_seconds"`
+	Running         bool       `json:"running"`
+	Runs            int64      `json:"runs"`
+	Failures        int64      `json:"failures"`
+	LastRunAt       *time.Time `json:"last_run_at"`
+	LastDurationMS  int64      `json:"last_duration_ms"`
+	LastSucceeded   *bool      `json:"last_succeeded"`
+	LastError       string     `json:"last_error"`
+	NextRunAt       *time.Time `json:"next_run_at"`
+}
+
+type JobsResponse struct {
+	Jobs []JobResponse `json:"jobs"`
+}
+
+type JobRunResponse struct {
+	Job JobResponse `json:"job"`
+}
+
+// ListJobs returns the background jobs and workers.
+// @Summary List background jobs
+// @Description Returns every registered scheduled job and worker with its last run time, duration, and outcome, and the next run of scheduled jobs. The state is per instance: it covers only the instance that answers the request.
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=JobsResponse} "Background jobs"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/admin/jobs [get]
+func (d *jobsDashboard) ListJobs(r *router.Request) (any, error) {
+	ctx, span := d.ins.Tracer("app.jobs").Start(r.Context(), "ListJobs")
+	defer span.End()
+
+	if _, err := d.requireAuthorized(ctx, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	statuses := d.jobs.List()
+	items := make([]JobResponse, 0, len(statuses))
+	for _, item := range statuses {
+		items = append(items, toJobResponse(item))
+	}
+
+	return JobsResponse{Jobs: items}, nil
+}
+
+// RunJob starts a scheduled job now.
+// @Summary Run background job
+// @Description Starts a run of a scheduled job in the background, outside its schedule, on the instance that answers the request; the returned state is that instance's. Workers cannot be run manually. The run is published as an audit event and refused while app.maintenance.admin_read_only is set.
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "Job name"
+// @Success 200 {object} router.successResponse{data=JobRunResponse} "Job run started"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Job not found"
+// @Failure 409 {object} router.errorResponse "Job is running or cannot be run manually"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/admin/jobs/{name}/run [post]
+func (d *jobsDashboard) RunJob(r *router.Request) (any, error) {
+	ctx, span := d.ins.Tracer("app.jobs").Start(r.Context(), "RunJob")
+	defer span.End()
+
+	name := r.GetParam("name")
+	if name == "" || len(name) > maxJobNameLength {
+		return nil, goerror.NewInvalidInput(errors.New("name must be 1 to 128 characters"))
+	}
+
+	clm, err := d.requireAuthorized(ctx, constant.PermActUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := d.jobs.Trigger(name)
+	if errors.Is(err, jobs.ErrNotFound) {
+		return nil, goerror.NewBusiness("job not found", goerror.CodeNotFound)
+	}
+	if errors.Is(err, jobs.ErrRunning) {
+		return nil, goerror.NewBusiness("job is already running", goerror.CodeConflict)
+	}
+	if errors.Is(err, jobs.ErrNotRunnable) {
+		return nil, goerror.NewBusiness("job cannot be run manually", goerror.CodeConflict)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to trigger job", "job", name, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	slog.InfoContext(ctx, "job run manually", "user_id", clm.UserID, "job", name)
+	d.recordAudit(ctx, clm, map[string]any{"job": name})
+
+	return JobRunResponse{Job: toJobResponse(status)}, nil
+}
+
+func (d *jobsDashboard) requireAuthorized(ctx context.Context, act string) (*jwt.Claims, error) {
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	ok, err := authz.Enforce(ctx, d.enforcer, d.authzShadow, clm, constant.PermAdminJobs, act)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to check authorization", "user_id", clm.Subject, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	if !ok {
+		return nil, goerror.NewBusiness("account not allowed", goerror.CodeForbidden)
+	}
+
+	return clm, nil
+}
+
+// recordAudit publishes a job run for the audit module, which stores it and forwards it to
+// the SIEM when enabled. The run already started, so a failure is logged instead of failing
+// the request.
+func (d *jobsDashboard) recordAudit(ctx context.Context, clm *jwt.Claims, meta map[string]any) {
+	// name the credential and any impersonator the same way module events do
+	if impersonator := instrument.GetActorID(ctx); impersonator != "" {
+		meta["impersonator_id"] = impersonator
+	}
+	if clm.ClientID != "" {
+		meta["client_id"] = clm.ClientID
+	}
+	if clm.APIKeyID != 0 {
+		meta["api_key_id"] = strconv.FormatInt(clm.APIKeyID, 10)
+	}
+
+	client := instrument.GetClient(ctx)
+	carrier := instrument.CarrierFromContext(ctx)
+	body, err := contracts.Marshal(d.uuid.Generate(), d.clock.Now(), carrier.Headers(), contracts.AuditRecorded{
+		Module:        jobsAuditModule,
+		Action:        jobsAuditActionRun,
+		ActorID:       clm.UserID,
+		IP:            client.IP,
+		UserAgent:     client.UserAgent,
+		CorrelationID: carrier.CorrelationID,
+		Metadata:      meta,
+	})
+	if err == nil {
+		_, err = d.messaging.Publish(ctx, contracts.AuditRecordedDestination, messaging.OutgoingMessage{Body: body})
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to publish audit event", "action", jobsAuditActionRun, "by_user_id", clm.UserID, "error", err)
+	}
+}
+
+func toJobResponse(s jobs.Status) JobResponse {
+	resp := JobResponse{
+		Name:            s.Name,
+		Kind:            string(s.Kind),
+		IntervalSeconds: int64(s.Interval.Seconds()),
+		Running:         s.Running,
+		Runs:            s.Runs,
+		Failures:        s.Failures,
+		LastDurationMS:  s.LastDuration.Milliseconds(),
+		LastError:       s.LastError,
+	}
+	if !s.LastRunAt.IsZero() {
+		resp.LastRunAt = &s.LastRunAt
+	}
+	if s.Runs > 0 {
+		resp.LastSucceeded = &s.LastSucceeded
+	}
+	if !s.NextRunAt.IsZero() {
+		resp.NextRunAt = &s.NextRunAt
+	}
+
+	return resp
+}
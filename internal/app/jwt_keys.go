@@ -0,0 +1,71 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+// jwtKeySource loads the token keys named in the config from files, such as those a secrets
+// manager mounts. It reads the config and the files again on every reload, so a rotation
+// needs no restart:
+//
+//  1. add the new key under a new kid and wait for a reload, so every instance verifies it;
+//  2. set jwt.active_key_id to the new kid, so new tokens are signed with it;
+//  3. once jwt.ttl_minutes has passed, remove the old kid (or keep only its public key).
+type jwtKeySource struct {
+	cfg config.Config
+}
+
+// LoadKeys reads jwt.secret_files for HS512 and v4.local, or jwt.private_keys and
+// jwt.public_keys for RS256, ES256 and v4.public, all kid:path pairs. Keys are taken in kid order, so the default signing key does
+// not depend on map order; once picked, the keyset keeps it across reloads.
+func (s jwtKeySource) LoadKeys(context.Context) ([]jwt.Key, string, error) {
+	activeID := strings.TrimSpace(s.cfg.GetString("jwt.active_key_id"))
+
+	secretPaths := s.cfg.GetMap("jwt.secret_files")
+	privatePaths := s.cfg.GetMap("jwt.private_keys")
+	publicPaths := s.cfg.GetMap("jwt.public_keys")
+
+	keys := make([]jwt.Key, 0, len(secretPaths)+len(privatePaths)+len(publicPaths))
+	for _, kid := range slices.Sorted(maps.Keys(secretPaths)) {
+		data, err := os.ReadFile(strings.TrimSpace(secretPaths[kid]))
+		if err != nil {
+			return nil, "", fmt.Errorf("read secret %q: %w", kid, err)
+		}
+		keys = append(keys, jwt.Key{ID: strings.TrimSpace(kid), Secret: bytes.TrimSpace(data)})
+	}
+
+	for _, kid := range slices.Sorted(maps.Keys(privatePaths)) {
+		data, err := os.ReadFile(strings.TrimSpace(privatePaths[kid]))
+		if err != nil {
+			return nil, "", fmt.Errorf("read private key %q: %w", kid, err)
+		}
+		signer, err := jwt.ParsePrivateKeyPEM(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("private key %q: %w", kid, err)
+		}
+		keys = append(keys, jwt.Key{ID: strings.TrimSpace(kid), Private: signer})
+	}
+
+	for _, kid := range slices.Sorted(maps.Keys(publicPaths)) {
+		data, err := os.ReadFile(strings.TrimSpace(publicPaths[kid]))
+		if err != nil {
+			return nil, "", fmt.Errorf("read public key %q: %w", kid, err)
+		}
+		pub, err := jwt.ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("public key %q: %w", kid, err)
+		}
+		keys = append(keys, jwt.Key{ID: strings.TrimSpace(kid), Public: pub})
+	}
+
+	return keys, activeID, nil
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
+)
+
+// BootstrapMessaging connects to the configured message bus, creates the topics and
+// subscriptions it is missing, and closes the connection again without serving.
+func BootstrapMessaging() error {
+	app := newApp(true)
+	defer app.release(context.Background())
+
+	app.initClosers()
+
+	if err := app.initConfig(); err != nil {
+		return err
+	}
+
+	if err := app.initMessaging(); err != nil {
+		return err
+	}
+
+	return app.initMessagingTopology()
+}
+
+// initMessagingTopology provisions every destination and consumer declared in contracts.
+// Drivers that cannot provision are skipped with a warning.
+func (a *App) initMessagingTopology() error {
+	res, err := messaging.EnsureTopology(a.ctx, a.messaging, a.messagingTopology())
+	if errors.Is(err, messaging.ErrUnsupported) {
+		slog.Warn("messaging driver cannot provision its topology", "driver", a.config.GetString("messaging.driver"))
+		return nil
+	}
+	for _, created := range res.Created {
+		slog.Info("messaging topology created", "resource", created)
+	}
+	if err != nil {
+		return fmt.Errorf("ensure messaging topology: %w", err)
+	}
+
+	return nil
+}
+
+func (a *App) messagingTopology() messaging.Topology {
+	retention := time.Duration(a.config.GetInt("messaging.topology.retention_hours")) * time.Hour
+
+	topics := contracts.Topics()
+	t := messaging.Topology{
+		Topics:                 make([]messaging.Topic, 0, len(topics)),
+		KafkaPartitions:        a.config.GetInt("messaging.topology.kafka_partitions"),
+		KafkaReplicationFactor: a.config.GetInt("messaging.topology.kafka_replication_factor"),
+	}
+	for _, topic := range topics {
+		t.Topics = append(t.Topics, messaging.Topic{
+			Name:          topic.Destination,
+			Subscriptions: topic.Consumers,
+			Retention:     retention,
+		})
+	}
+
+	return t
+}
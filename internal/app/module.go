@@ -1,62 +1,110 @@
 package app
 
 import (
-	"log/slog"
-	"os"
+	"fmt"
 
+	"github.com/shandysiswandi/gobite/internal/audit"
 	"github.com/shandysiswandi/gobite/internal/identity"
 	"github.com/shandysiswandi/gobite/internal/notification"
+	"github.com/shandysiswandi/gobite/internal/pkg/replay"
 )
 
-func (a *App) initModules() {
+func (a *App) initModules() error {
 	if a.config.GetBool("modules.identity.enabled") {
 		if err := identity.New(identity.Dependency{
-			Config:          a.config,
-			Instrument:      a.ins,
-			UID:             a.uid,
-			UUID:            a.uuid,
-			OID:             a.oid,
-			Bcrypt:          a.bcrypt,
-			HMAC:            a.hmac,
-			Argon2ID:        a.argon2id,
-			MFAEncryptor:    a.mfaEncryptor,
-			MFARecoveryCode: a.mfaRecoveryCode,
-			Clock:           a.clock,
-			Validator:       a.validator,
-			Router:          a.router,
-			Totp:            a.totp,
-			DBConn:          a.dbConn,
-			CacheConn:       a.cacheConn,
-			Idempotency:     a.idemp,
-			Messaging:       a.messaging,
-			Storage:         a.storage,
-			Goroutine:       a.goroutine,
-			JWT:             a.jwt,
-			Enforcer:        a.casbin,
+			Ctx:                  a.ctx,
+			Config:               a.config,
+			Instrument:           a.ins,
+			UID:                  a.uid,
+			UUID:                 a.uuid,
+			OID:                  a.oid,
+			Bcrypt:               a.bcrypt,
+			HMAC:                 a.hmac,
+			Argon2ID:             a.argon2id,
+			MFAEncryptor:         a.mfaEncryptor,
+			ColumnCipher:         a.columnCipher,
+			MFARecoveryCode:      a.mfaRecoveryCode,
+			SignedURL:            a.signedURL,
+			Clock:                a.clock,
+			Validator:            a.validator,
+			Router:               a.router,
+			GRPC:                 a.grpcServer,
+			Totp:                 a.totp,
+			DBConn:               a.moduleDB("identity"),
+			CacheConn:            a.cacheConn,
+			Idempotency:          a.idemp,
+			Messaging:            a.messaging,
+			Storage:              a.storage,
+			Goroutine:            a.goroutine,
+			JWT:                  a.jwt,
+			Enforcer:             a.casbin,
+			AuthzShadow:          a.authzShadow,
+			Retention:            a.retention,
+			Jobs:                 a.jobs,
+			UserData:             a.userData,
+			HTTPClient:           a.httpClient,
+			ValidateNotification: notification.ValidateRequested,
 		}); err != nil {
-			slog.Error("failed to init module identity", "error", err)
-			os.Exit(1)
+			return fmt.Errorf("init module identity: %w", err)
 		}
 	}
 
 	if a.config.GetBool("modules.notification.enabled") {
 		if err := notification.New(notification.Dependency{
-			Ctx:        a.ctx,
-			DBConn:     a.dbConn,
-			Messaging:  a.messaging,
-			Config:     a.config,
-			Instrument: a.ins,
-			UID:        a.uid,
-			UUID:       a.uuid,
-			Clock:      a.clock,
-			Goroutine:  a.goroutine,
-			Validator:  a.validator,
-			Router:     a.router,
-			Mail:       a.mail,
-			JWT:        a.jwt,
+			Ctx:         a.ctx,
+			DBConn:      a.moduleDB("notification"),
+			HTTPClient:  a.httpClient,
+			Messaging:   a.messaging,
+			Config:      a.config,
+			Instrument:  a.ins,
+			UID:         a.uid,
+			UUID:        a.uuid,
+			Clock:       a.clock,
+			Validator:   a.validator,
+			Router:      a.router,
+			GRPC:        a.grpcServer,
+			Mail:        a.mail,
+			SignedURL:   a.signedURL,
+			JWT:         a.jwt,
+			Storage:     a.storage,
+			Enforcer:    a.casbin,
+			AuthzShadow: a.authzShadow,
+			Retention:   a.retention,
+			Jobs:        a.jobs,
+			UserData:    a.userData,
+			Replay:      replay.New(a.cacheConn),
 		}); err != nil {
-			slog.Error("failed to init module notification", "error", err)
-			os.Exit(1)
+			return fmt.Errorf("init module notification: %w", err)
 		}
 	}
+
+	if a.config.GetBool("modules.audit.enabled") {
+		if err := audit.New(audit.Dependency{
+			Ctx:         a.ctx,
+			DBConn:      a.moduleDB("audit"),
+			Messaging:   a.messaging,
+			Config:      a.config,
+			Instrument:  a.ins,
+			UID:         a.uid,
+			UUID:        a.uuid,
+			Clock:       a.clock,
+			Validator:   a.validator,
+			Router:      a.router,
+			Enforcer:    a.casbin,
+			AuthzShadow: a.authzShadow,
+			Retention:   a.retention,
+			Jobs:        a.jobs,
+			HTTPClient:  a.httpClient,
+		}); err != nil {
+			return fmt.Errorf("init module audit: %w", err)
+		}
+	}
+
+	a.registerJobsDashboard()
+
+	// modules registered their tables and jobs above, so every run covers all of them
+	a.jobs.Schedule(a.retention.Job())
+	a.jobs.Start(a.ctx)
+
+	return nil
 }
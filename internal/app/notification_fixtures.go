@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/notification"
+)
+
+// SeedNotifications inserts the notification categories, default settings, and templates
+// the database is missing without serving. With verify nothing is written, and any row
+// that is missing or differs from the fixtures fails the run.
+func SeedNotifications(verify bool) error {
+	app := newApp(true)
+	defer app.release(context.Background())
+
+	app.initClosers()
+
+	if err := app.initConfig(); err != nil {
+		return err
+	}
+
+	if err := app.initInstrument(); err != nil {
+		return err
+	}
+
+	if err := app.initDatabase(); err != nil {
+		return err
+	}
+
+	drift, err := notification.Seed(app.ctx, app.moduleDB("notification"), app.config, app.ins, verify)
+	if err != nil {
+		return err
+	}
+	for _, d := range drift {
+		slog.Warn("notification fixture drift", "kind", d.Kind, "key", d.Key, "missing", d.Missing)
+	}
+	if verify && len(drift) > 0 {
+		return fmt.Errorf("notification fixtures drifted in %d rows", len(drift))
+	}
+
+	return nil
+}
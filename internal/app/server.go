@@ -9,9 +9,12 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+
+	"google.golang.org/grpc"
 )
 
-// Start launches the HTTP server and returns a channel closed on shutdown.
+// Start launches the HTTP, SSE and, when enabled, gRPC servers and returns a channel closed
+// on shutdown.
 func (a *App) Start() <-chan struct{} {
 	terminateChan := make(chan struct{})
 
@@ -33,6 +36,22 @@ func (a *App) Start() <-chan struct{} {
 		}
 	}()
 
+	if a.grpcServer != nil {
+		go func() {
+			address := a.config.GetString("app.server.grpc.address")
+			slog.Info("grpc server listening", "address", address)
+
+			l, err := net.Listen("tcp", address)
+			if err == nil {
+				err = a.grpcServer.Serve(l)
+			}
+			if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				slog.Error("failed to listen and serve grpc server", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	go func() {
 		sigint := make(chan os.Signal, 1)
 		signal.Notify(sigint, os.Interrupt, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
@@ -76,12 +95,26 @@ func (a *App) Stop(ctx context.Context) {
 	if err := a.sseServer.Shutdown(ctx); err != nil {
 		slog.ErrorContext(ctx, "failed to close resources", "name", "SSE Server", "error", err)
 	}
+	if a.grpcServer != nil {
+		a.stopGRPCServer(ctx)
+	}
 
-	slog.InfoContext(ctx, "waiting for all goroutine to finish")
-	if err := a.goroutine.Wait(); err != nil {
-		slog.ErrorContext(ctx, "error from goroutines executions", "error", err)
+	a.release(ctx)
+}
+
+// release waits for the background goroutines and closes every resource.
+func (a *App) release(ctx context.Context) {
+	if a.cancel != nil {
+		a.cancel()
+	}
+
+	if a.goroutine != nil {
+		slog.InfoContext(ctx, "waiting for all goroutine to finish")
+		if err := a.goroutine.Wait(); err != nil {
+			slog.ErrorContext(ctx, "error from goroutines executions", "error", err)
+		}
+		slog.InfoContext(ctx, "all goroutines have finished successfully")
 	}
-	slog.InfoContext(ctx, "all goroutines have finished successfully")
 
 	for _, closer := range a.closers {
 		if err := closer.fn(ctx); err != nil {
@@ -89,3 +122,19 @@ func (a *App) Stop(ctx context.Context) {
 		}
 	}
 }
+
+// stopGRPCServer lets the calls in flight finish, and cuts them off once ctx is done.
+func (a *App) stopGRPCServer(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		a.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		a.grpcServer.Stop()
+		slog.ErrorContext(ctx, "failed to close resources", "name", "gRPC Server", "error", ctx.Err())
+	}
+}
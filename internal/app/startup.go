@@ -0,0 +1,83 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// startupStep is one subsystem of the startup graph.
+type startupStep struct {
+	name string
+	// deps names the steps that must finish first; they must be declared earlier.
+	deps []string
+	// optional lets the app start without the step; fallback then installs a stand-in.
+	optional bool
+	run      func() error
+	fallback func(err error)
+}
+
+// runStartup runs every step as soon as its dependencies finished, so independent
+// connections are opened in parallel. A failed required step skips everything that depends
+// on it, while a failed optional step is logged and replaced by its fallback. All failures
+// are returned together, in declaration order.
+func runStartup(steps []startupStep) error {
+	index := make(map[string]int, len(steps))
+	for i, step := range steps {
+		for _, dep := range step.deps {
+			if _, ok := index[dep]; !ok {
+				return fmt.Errorf("startup step %s depends on %s, which is not declared before it", step.name, dep)
+			}
+		}
+		index[step.name] = i
+	}
+
+	done := make([]chan struct{}, len(steps))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	// failed and errs of a step are written before its done channel is closed
+	failed := make([]bool, len(steps))
+	errs := make([]error, len(steps))
+
+	var wg sync.WaitGroup
+	for i, step := range steps {
+		wg.Go(func() {
+			defer close(done[i])
+
+			for _, dep := range step.deps {
+				j := index[dep]
+				<-done[j]
+				if failed[j] {
+					failed[i] = true
+					errs[i] = fmt.Errorf("%s: skipped, %s failed", step.name, dep)
+					return
+				}
+			}
+
+			start := time.Now()
+			err := step.run()
+			if err == nil {
+				slog.Debug("startup step ready", "step", step.name, "duration", time.Since(start))
+				return
+			}
+
+			if step.optional {
+				slog.Warn("optional startup step failed, continuing without it", "step", step.name, "error", err)
+				if step.fallback != nil {
+					step.fallback(err)
+				}
+				return
+			}
+
+			failed[i] = true
+			errs[i] = fmt.Errorf("%s: %w", step.name, err)
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+// Event is one stored audit entry. ActorID and SubjectID are 0 when unknown or not
+// applicable, e.g. a failed login has no actor.
+type Event struct {
+	ID            int64
+	EventID       string
+	Module        string
+	Action        string
+	ActorID       int64
+	SubjectID     int64
+	IP            string
+	UserAgent     string
+	CorrelationID string
+	Metadata      valueobject.JSONMap
+	OccurredAt    time.Time
+}
+
+type EventFilter struct {
+	Module        string
+	Action        string
+	ActorID       int64
+	SubjectID     int64
+	CorrelationID string
+	DateFrom      time.Time
+	DateTo        time.Time
+	Size          int32
+	Offset        int32
+}
+
+// EventSearch filters events for the admin audit trail, which pages by keyset instead of
+// offset so deep pages stay cheap and stable while new events arrive.
+type EventSearch struct {
+	ActorID      int64
+	SubjectID    int64
+	Action       string
+	ResourceType string // first segment of the action, e.g. "user" for "user.create"
+	DateFrom     time.Time
+	DateTo       time.Time
+	After        *EventCursor // start after this event; nil for the first page
+	Limit        int32
+}
+
+// EventCursor is the position of an event in the newest-first order.
+type EventCursor struct {
+	OccurredAt time.Time
+	ID         int64
+}
+
+// ErrCursorInvalid indicates a cursor that was not produced by EventCursor.String.
+var ErrCursorInvalid = errors.New("audit: invalid cursor")
+
+// String encodes the cursor as an opaque token.
+func (c EventCursor) String() string {
+	raw := strconv.FormatInt(c.OccurredAt.UnixNano(), 10) + "." + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseEventCursor decodes a token produced by EventCursor.String.
+func ParseEventCursor(token string) (*EventCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrCursorInvalid
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return nil, ErrCursorInvalid
+	}
+
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrCursorInvalid
+	}
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || i <= 0 {
+		return nil, ErrCursorInvalid
+	}
+
+	return &EventCursor{OccurredAt: time.Unix(0, n).UTC(), ID: i}, nil
+}
@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SIEMFormatECS renders an event as one Elastic Common Schema JSON document.
+	SIEMFormatECS = "ecs"
+	// SIEMFormatCEF renders an event as one ArcSight Common Event Format line.
+	SIEMFormatCEF = "cef"
+)
+
+// SIEMProduct names the device in exported events.
+const SIEMProduct = "gobite"
+
+// SIEMOutcome reports whether the action succeeded, going by the ".failed" suffix the
+// modules use for rejected attempts.
+func (e Event) SIEMOutcome() string {
+	if strings.HasSuffix(e.Action, ".failed") {
+		return "failure"
+	}
+	return "success"
+}
+
+// SIEMCategory maps the action to the ECS event category: sign-ins, sessions and second
+// factors are authentication, role and permission changes are iam.
+func (e Event) SIEMCategory() string {
+	switch prefix, _, _ := strings.Cut(e.Action, "."); prefix {
+	case "auth", "mfa", "password", "trusted_device", "session":
+		return "authentication"
+	default:
+		return "iam"
+	}
+}
+
+// ECS renders the event as an Elastic Common Schema document.
+func (e Event) ECS() ([]byte, error) {
+	doc := map[string]any{
+		"@timestamp": e.OccurredAt.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		"ecs":        map[string]any{"version": "8.11.0"},
+		"event": map[string]any{
+			"id":       e.EventID,
+			"kind":     "event",
+			"action":   e.Action,
+			"category": []string{e.SIEMCategory()},
+			"outcome":  e.SIEMOutcome(),
+			"module":   e.Module,
+			"dataset":  SIEMProduct + ".audit",
+		},
+		"observer": map[string]any{"product": SIEMProduct, "type": "application"},
+	}
+	if e.ActorID != 0 {
+		doc["user"] = map[string]any{"id": strconv.FormatInt(e.ActorID, 10)}
+	}
+	if e.SubjectID != 0 {
+		user, _ := doc["user"].(map[string]any)
+		if user == nil {
+			user = map[string]any{}
+			doc["user"] = user
+		}
+		user["target"] = map[string]any{"id": strconv.FormatInt(e.SubjectID, 10)}
+	}
+	if e.IP != "" {
+		doc["source"] = map[string]any{"ip": e.IP}
+	}
+	if e.UserAgent != "" {
+		doc["user_agent"] = map[string]any{"original": e.UserAgent}
+	}
+	if e.CorrelationID != "" {
+		doc["trace"] = map[string]any{"id": e.CorrelationID}
+	}
+	if len(e.Metadata) > 0 {
+		doc[SIEMProduct] = map[string]any{"metadata": e.Metadata}
+	}
+
+	return json.Marshal(doc)
+}
+
+// CEF renders the event as a Common Event Format line. Metadata is carried as a JSON string
+// in the msg extension since CEF has no nested values.
+func (e Event) CEF() ([]byte, error) {
+	severity := "3"
+	if e.SIEMOutcome() == "failure" {
+		severity = "6"
+	}
+
+	var b strings.Builder
+	b.WriteString("CEF:0|" + SIEMProduct + "|" + SIEMProduct + "|1|")
+	b.WriteString(cefHeader(e.Action) + "|" + cefHeader(e.Action) + "|" + severity + "|")
+
+	ext := []string{
+		"rt=" + strconv.FormatInt(e.OccurredAt.UnixMilli(), 10),
+		"externalId=" + cefValue(e.EventID),
+		"act=" + cefValue(e.Action),
+		"outcome=" + e.SIEMOutcome(),
+		"cat=" + e.SIEMCategory(),
+		"cs1Label=module",
+		"cs1=" + cefValue(e.Module),
+	}
+	if e.ActorID != 0 {
+		ext = append(ext, "suid="+strconv.FormatInt(e.ActorID, 10))
+	}
+	if e.SubjectID != 0 {
+		ext = append(ext, "duid="+strconv.FormatInt(e.SubjectID, 10))
+	}
+	if e.IP != "" {
+		ext = append(ext, "src="+cefValue(e.IP))
+	}
+	if e.UserAgent != "" {
+		ext = append(ext, "requestClientApplication="+cefValue(e.UserAgent))
+	}
+	if e.CorrelationID != "" {
+		ext = append(ext, "cs2Label=correlationId", "cs2="+cefValue(e.CorrelationID))
+	}
+	if len(e.Metadata) > 0 {
+		meta, err := json.Marshal(e.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		ext = append(ext, "msg="+cefValue(string(meta)))
+	}
+	b.WriteString(strings.Join(ext, " "))
+
+	return []byte(b.String()), nil
+}
+
+// cefHeader escapes a CEF header field, where pipes separate the fields.
+func cefHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "\n", " ", "\r", " ").Replace(s)
+}
+
+// cefValue escapes a CEF extension value, where equals signs separate keys from values.
+func cefValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "=", `\=`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
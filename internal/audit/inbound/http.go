@@ -0,0 +1,15 @@
+package inbound
+
+import "github.com/shandysiswandi/gobite/internal/pkg/router"
+
+func RegisterHTTPEndpoint(r *router.Router, uc uc) {
+	end := &HTTPEndpoint{uc: uc}
+
+	// Audit Events (need authenticated & authorization)
+	r.GET("/api/v1/audit/events", end.ListEvents)
+	r.GET("/api/v1/audit/events/:id", end.EventDetail)
+
+	// Admin audit trail (need authenticated & authorization)
+	r.GET("/api/v1/admin/audit", end.SearchEvents)
+	r.GET("/api/v1/admin/audit/export", end.ExportEvents)
+}
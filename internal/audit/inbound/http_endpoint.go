@@ -0,0 +1,311 @@
+package inbound
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/audit/entity"
+	"github.com/shandysiswandi/gobite/internal/audit/usecase"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/router"
+	"github.com/shandysiswandi/gobite/internal/pkg/tabular"
+)
+
+type HTTPEndpoint struct {
+	uc uc
+}
+
+// ListEvents returns audit events with optional filters.
+// @Summary List audit events
+// @Description Returns a paginated list of audit events, newest first.
+// @Tags Audit
+// @Security BearerAuth
+// @Produce json
+// @Param module query string false "Filter by reporting module, e.g. identity"
+// @Param action query string false "Filter by action, e.g. auth.login"
+// @Param actor_id query int false "Filter by the user who performed the action"
+// @Param subject_id query int false "Filter by the user the action was performed on"
+// @Param correlation_id query string false "Filter by correlation ID"
+// @Param date_from query string false "Filter by occurred_at >= date_from (RFC3339)"
+// @Param date_to query string false "Filter by occurred_at <= date_to (RFC3339)"
+// @Param size query int false "Pagination size"
+// @Param page query int false "Pagination page"
+// @Success 200 {object} router.successResponse{data=EventsResponse} "Audit events"
+// @Failure 400 {object} router.errorResponse "Invalid query parameters"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/audit/events [get]
+func (h *HTTPEndpoint) ListEvents(r *router.Request) (any, error) {
+	size, err := r.GetQueryInt32("size")
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := r.GetQueryInt32("page")
+	if err != nil {
+		return nil, err
+	}
+
+	actorID, err := r.GetQueryInt64("actor_id")
+	if err != nil {
+		return nil, err
+	}
+
+	subjectID, err := r.GetQueryInt64("subject_id")
+	if err != nil {
+		return nil, err
+	}
+
+	dateFrom, err := r.GetQueryDate("date_from", time.RFC3339)
+	if err != nil {
+		return nil, err
+	}
+
+	dateTo, err := r.GetQueryDate("date_to", time.RFC3339)
+	if err != nil {
+		return nil, err
+	}
+
+	if !dateFrom.IsZero() && !dateTo.IsZero() && dateFrom.After(dateTo) {
+		return nil, goerror.NewInvalidFormat("date_from must be before date_to")
+	}
+
+	resp, err := h.uc.ListEvents(r.Context(), usecase.ListEventsInput{
+		Module:        r.GetQuery("module"),
+		Action:        r.GetQuery("action"),
+		ActorID:       actorID,
+		SubjectID:     subjectID,
+		CorrelationID: r.GetQuery("correlation_id"),
+		DateFrom:      dateFrom,
+		DateTo:        dateTo,
+		Size:          size,
+		Page:          page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]EventResponse, 0, len(resp.Events))
+	for _, item := range resp.Events {
+		events = append(events, toEventResponse(item))
+	}
+
+	return EventsResponse{
+		total:  resp.Total,
+		size:   resp.Size,
+		page:   resp.Page,
+		Events: events,
+	}, nil
+}
+
+// EventDetail returns one audit event.
+// @Summary Get audit event
+// @Description Returns an audit event by ID.
+// @Tags Audit
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Audit event ID"
+// @Success 200 {object} router.successResponse{data=EventDetailResponse} "Audit event"
+// @Failure 400 {object} router.errorResponse "Invalid path parameter"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Audit event not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/audit/events/{id} [get]
+func (h *HTTPEndpoint) EventDetail(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	ev, err := h.uc.EventDetail(r.Context(), usecase.EventDetailInput{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return EventDetailResponse{Event: toEventResponse(*ev)}, nil
+}
+
+func toEventResponse(ev entity.Event) EventResponse {
+	return EventResponse{
+		ID:            ev.ID,
+		Module:        ev.Module,
+		Action:        ev.Action,
+		ActorID:       ev.ActorID,
+		SubjectID:     ev.SubjectID,
+		IP:            ev.IP,
+		UserAgent:     ev.UserAgent,
+		CorrelationID: ev.CorrelationID,
+		Metadata:      ev.Metadata,
+		OccurredAt:    ev.OccurredAt,
+	}
+}
+
+// SearchEvents returns the admin audit trail.
+// @Summary Search audit trail
+// @Description Returns audit events newest first with keyset pagination: pass meta.next_cursor of a page as cursor to get the next one; it is empty on the last page.
+// @Tags Audit, Admin
+// @Security BearerAuth
+// @Produce json
+// @Param actor_id query int false "Filter by the user who performed the action"
+// @Param subject_id query int false "Filter by the user or resource the action was performed on"
+// @Param action query string false "Filter by action, e.g. user.create"
+// @Param resource_type query string false "Filter by the first segment of the action, e.g. user"
+// @Param date_from query string false "Filter by occurred_at >= date_from (RFC3339)"
+// @Param date_to query string false "Filter by occurred_at <= date_to (RFC3339)"
+// @Param cursor query string false "Cursor returned as meta.next_cursor by the previous page"
+// @Param size query int false "Page size (max 100)"
+// @Success 200 {object} router.successResponse{data=AuditTrailResponse} "Audit events"
+// @Failure 400 {object} router.errorResponse "Invalid query parameters"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/admin/audit [get]
+func (h *HTTPEndpoint) SearchEvents(r *router.Request) (any, error) {
+	in, err := searchEventsInput(r)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.SearchEvents(r.Context(), in)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]EventResponse, 0, len(resp.Events))
+	for _, item := range resp.Events {
+		events = append(events, toEventResponse(item))
+	}
+
+	return AuditTrailResponse{
+		Events:     events,
+		size:       resp.Size,
+		nextCursor: resp.NextCursor,
+	}, nil
+}
+
+// ExportEvents downloads the admin audit trail as CSV.
+// @Summary Export audit trail
+// @Description Streams every audit event matching the filters as CSV, newest first, up to the configured row limit.
+// @Tags Audit, Admin
+// @Security BearerAuth
+// @Produce text/csv
+// @Param actor_id query int false "Filter by the user who performed the action"
+// @Param subject_id query int false "Filter by the user or resource the action was performed on"
+// @Param action query string false "Filter by action, e.g. user.create"
+// @Param resource_type query string false "Filter by the first segment of the action, e.g. user"
+// @Param date_from query string false "Filter by occurred_at >= date_from (RFC3339)"
+// @Param date_to query string false "Filter by occurred_at <= date_to (RFC3339)"
+// @Success 200 {string} string "CSV file"
+// @Failure 400 {object} router.errorResponse "Invalid query parameters"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/admin/audit/export [get]
+func (h *HTTPEndpoint) ExportEvents(r *router.Request) (any, error) {
+	in, err := searchEventsInput(r)
+	if err != nil {
+		return nil, err
+	}
+	in.Cursor = ""
+
+	events, err := h.uc.ExportEvents(r.Context(), in)
+	if err != nil {
+		return nil, err
+	}
+
+	return &router.File{
+		Name:        "audit-events-" + time.Now().UTC().Format("20060102T150405Z") + ".csv",
+		ContentType: tabular.ContentType(tabular.FormatCSV),
+		Write: func(w io.Writer) error {
+			// the tabular writer escapes formula-like cells, since user agents and metadata are
+			// attacker-controlled
+			cw, err := tabular.NewWriter(w, tabular.FormatCSV)
+			if err != nil {
+				return err
+			}
+			if err := cw.WriteRow([]string{
+				"id", "occurred_at", "module", "action", "actor_id", "subject_id", "ip", "user_agent", "correlation_id", "metadata",
+			}); err != nil {
+				return err
+			}
+
+			for ev, err := range events {
+				if err != nil {
+					return err
+				}
+
+				meta, err := json.Marshal(ev.Metadata)
+				if err != nil {
+					return err
+				}
+
+				if err := cw.WriteRow([]string{
+					strconv.FormatInt(ev.ID, 10),
+					ev.OccurredAt.UTC().Format(time.RFC3339Nano),
+					ev.Module,
+					ev.Action,
+					strconv.FormatInt(ev.ActorID, 10),
+					strconv.FormatInt(ev.SubjectID, 10),
+					ev.IP,
+					ev.UserAgent,
+					ev.CorrelationID,
+					string(meta),
+				}); err != nil {
+					return err
+				}
+			}
+
+			return cw.Close()
+		},
+	}, nil
+}
+
+func searchEventsInput(r *router.Request) (usecase.SearchEventsInput, error) {
+	size, err := r.GetQueryInt32("size")
+	if err != nil {
+		return usecase.SearchEventsInput{}, err
+	}
+
+	actorID, err := r.GetQueryInt64("actor_id")
+	if err != nil {
+		return usecase.SearchEventsInput{}, err
+	}
+
+	subjectID, err := r.GetQueryInt64("subject_id")
+	if err != nil {
+		return usecase.SearchEventsInput{}, err
+	}
+
+	dateFrom, err := r.GetQueryDate("date_from", time.RFC3339)
+	if err != nil {
+		return usecase.SearchEventsInput{}, err
+	}
+
+	dateTo, err := r.GetQueryDate("date_to", time.RFC3339)
+	if err != nil {
+		return usecase.SearchEventsInput{}, err
+	}
+
+	if !dateFrom.IsZero() && !dateTo.IsZero() && dateFrom.After(dateTo) {
+		return usecase.SearchEventsInput{}, goerror.NewInvalidFormat("date_from must be before date_to")
+	}
+
+	return usecase.SearchEventsInput{
+		ActorID:      actorID,
+		SubjectID:    subjectID,
+		Action:       r.GetQuery("action"),
+		ResourceType: r.GetQuery("resource_type"),
+		DateFrom:     dateFrom,
+		DateTo:       dateTo,
+		Cursor:       r.GetQuery("cursor"),
+		Size:         size,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package inbound
+
+import (
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+type EventResponse struct {
+	ID            int64               `json:"id,string"`
+	Module        string              `json:"module"`
+	Action        string              `json:"action"`
+	ActorID       int64               `json:"actor_id,string"`
+	SubjectID     int64               `json:"subject_id,string"`
+	IP            string              `json:"ip"`
+	UserAgent     string              `json:"user_agent"`
+	CorrelationID string              `json:"correlation_id"`
+	Metadata      valueobject.JSONMap `json:"metadata"`
+	OccurredAt    time.Time           `json:"occurred_at"`
+}
+
+type EventsResponse struct {
+	Events []EventResponse `json:"events"`
+	// meta
+	total int64
+	size  int32
+	page  int32
+}
+
+func (r EventsResponse) Meta() map[string]any {
+	return map[string]any{
+		"total": r.total,
+		"size":  r.size,
+		"page":  r.page,
+	}
+}
+
+type EventDetailResponse struct {
+	Event EventResponse `json:"event"`
+}
+
+type AuditTrailResponse struct {
+	Events []EventResponse `json:"events"`
+	// meta
+	size       int32
+	nextCursor string
+}
+
+func (r AuditTrailResponse) Meta() map[string]any {
+	return map[string]any{
+		"size":        r.size,
+		"next_cursor": r.nextCursor,
+	}
+}
@@ -0,0 +1,86 @@
+package inbound
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jobs"
+	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+)
+
+func RegisterMQConsumer(
+	ctx context.Context,
+	cfg config.Config,
+	workers *jobs.Registry,
+	messenger messaging.Messaging,
+	uuid uid.StringID,
+	uc ucConsumer,
+	ins instrument.Instrumentation,
+) {
+	mqHanlder := &MQHandler{uc: uc, uuid: uuid, ins: ins}
+
+	enableConsumerNames := cfg.GetArray("modules.audit.consumer_names")
+
+	// a SIEM event is acked once its batch is delivered, so a batch only fills up when as
+	// many messages are handled at once
+	siemConcurrency := cfg.GetInt("modules.audit.siem.batch_size")
+	if siemConcurrency <= 0 {
+		siemConcurrency = 100
+	}
+
+	var consumers = []struct {
+		name               string
+		topic              string // destination where publisher sent message
+		nsqConsumerName    string // for nsq
+		natsConsumerName   string // for nats
+		kafkaConsumerName  string // for kafka
+		pubsubConsumerName string // for google pubusb
+		concurrency        int
+		handler            messaging.Handler
+	}{
+		{
+			name:               contracts.AuditRecordedConsumerAudit,
+			topic:              contracts.AuditRecordedDestination,
+			nsqConsumerName:    contracts.AuditRecordedConsumerAudit,
+			natsConsumerName:   contracts.AuditRecordedConsumerAudit,
+			kafkaConsumerName:  contracts.AuditRecordedConsumerAudit,
+			pubsubConsumerName: contracts.AuditRecordedConsumerAudit,
+			concurrency:        10,
+			handler:            mqHanlder.AuditRecorded,
+		},
+		{
+			name:               contracts.AuditRecordedConsumerSIEM,
+			topic:              contracts.AuditRecordedDestination,
+			nsqConsumerName:    contracts.AuditRecordedConsumerSIEM,
+			natsConsumerName:   contracts.AuditRecordedConsumerSIEM,
+			kafkaConsumerName:  contracts.AuditRecordedConsumerSIEM,
+			pubsubConsumerName: contracts.AuditRecordedConsumerSIEM,
+			concurrency:        siemConcurrency,
+			handler:            mqHanlder.AuditRecordedSIEM,
+		},
+	}
+
+	for _, consumer := range consumers {
+		if len(enableConsumerNames) > 0 && slices.Contains(enableConsumerNames, consumer.name) {
+			workers.Go(ctx, consumer.name, func(pCtx context.Context) error {
+				slog.InfoContext(ctx, "Running job for handling consumer", "consumer", consumer.name)
+				return messenger.Consume(pCtx,
+					consumer.topic,
+					consumer.handler,
+					messaging.WithChannel(consumer.nsqConsumerName),
+					messaging.WithQueueGroup(consumer.natsConsumerName),
+					messaging.WithGroup(consumer.kafkaConsumerName),
+					messaging.WithSubscription(consumer.pubsubConsumerName),
+					messaging.WithAutoAck(true),
+					messaging.WithConcurrency(consumer.concurrency),
+					messaging.WithMaxInFlight(consumer.concurrency),
+				)
+			})
+		}
+	}
+}
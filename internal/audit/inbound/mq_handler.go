@@ -0,0 +1,108 @@
+package inbound
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/audit/usecase"
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+)
+
+type MQHandler struct {
+	uc   ucConsumer
+	uuid uid.StringID
+	ins  instrument.Instrumentation
+}
+
+// ensureCorrelationID makes sure ctx carries the publisher's carrier. Headers restored by
+// the messaging layer win; brokers without headers deliver it inside the envelope.
+func (h *MQHandler) ensureCorrelationID(ctx context.Context, msg messaging.Message) context.Context {
+	if instrument.CarrierFromContext(ctx).CorrelationID != "" {
+		return ctx
+	}
+
+	if carrier := contracts.CarrierOf(msg.Body()); len(carrier) > 0 {
+		ctx = instrument.ExtractCarrier(func(key string) string { return carrier[key] }).Context(ctx)
+		if instrument.CarrierFromContext(ctx).CorrelationID != "" {
+			return ctx
+		}
+	}
+
+	return instrument.SetCorrelationID(ctx, h.uuid.Generate())
+}
+
+func (h *MQHandler) AuditRecorded(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("audit.inbound.mq").Start(ctx, "AuditRecorded")
+	defer span.End()
+
+	in, ok := h.recordEventInput(ctx, msg)
+	if !ok {
+		return nil
+	}
+
+	if err := h.uc.RecordEvent(ctx, in); err != nil {
+		slog.ErrorContext(ctx, "failed to record audit event", "event_id", in.EventID, "action", in.Action, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (h *MQHandler) AuditRecordedSIEM(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("audit.inbound.mq").Start(ctx, "AuditRecordedSIEM")
+	defer span.End()
+
+	in, ok := h.recordEventInput(ctx, msg)
+	if !ok {
+		return nil
+	}
+
+	if err := h.uc.ForwardSIEMEvent(ctx, in); err != nil {
+		slog.ErrorContext(ctx, "failed to forward audit event to siem", "event_id", in.EventID, "action", in.Action, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// recordEventInput decodes an audit recorded message; unreadable bodies are logged and
+// reported as not ok so they are acked instead of redelivered.
+func (h *MQHandler) recordEventInput(ctx context.Context, msg messaging.Message) (usecase.RecordEventInput, bool) {
+	body := msg.Body()
+
+	var payload contracts.AuditRecorded
+	env, err := contracts.Unmarshal(body, &payload)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse message body of audit recorded", "msg_body", string(body), "error", err)
+		return usecase.RecordEventInput{}, false
+	}
+
+	// the envelope ID is stable across redeliveries, the broker message ID is not always
+	eventID := env.ID
+	if eventID == "" {
+		eventID = msg.ID()
+	}
+	if eventID == "" {
+		eventID = h.uuid.Generate()
+	}
+
+	return usecase.RecordEventInput{
+		EventID:       eventID,
+		Module:        payload.Module,
+		Action:        payload.Action,
+		ActorID:       payload.ActorID,
+		SubjectID:     payload.SubjectID,
+		IP:            payload.IP,
+		UserAgent:     payload.UserAgent,
+		CorrelationID: payload.CorrelationID,
+		Metadata:      payload.Metadata,
+		OccurredAt:    env.OccurredAt,
+	}, true
+}
@@ -0,0 +1,23 @@
+package inbound
+
+import (
+	"context"
+	"iter"
+
+	"github.com/shandysiswandi/gobite/internal/audit/entity"
+	"github.com/shandysiswandi/gobite/internal/audit/usecase"
+)
+
+type ucConsumer interface {
+	RecordEvent(ctx context.Context, in usecase.RecordEventInput) error
+	ForwardSIEMEvent(ctx context.Context, in usecase.RecordEventInput) error
+}
+
+type uc interface {
+	ucConsumer
+
+	ListEvents(ctx context.Context, in usecase.ListEventsInput) (*usecase.ListEventsOutput, error)
+	EventDetail(ctx context.Context, in usecase.EventDetailInput) (*entity.Event, error)
+	SearchEvents(ctx context.Context, in usecase.SearchEventsInput) (*usecase.SearchEventsOutput, error)
+	ExportEvents(ctx context.Context, in usecase.SearchEventsInput) (iter.Seq2[entity.Event, error], error)
+}
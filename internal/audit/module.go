@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/casbin/casbin/v3"
+	"github.com/shandysiswandi/gobite/internal/audit/entity"
+	"github.com/shandysiswandi/gobite/internal/audit/inbound"
+	"github.com/shandysiswandi/gobite/internal/audit/outbound/db"
+	"github.com/shandysiswandi/gobite/internal/audit/outbound/siem"
+	"github.com/shandysiswandi/gobite/internal/audit/usecase"
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
+	"github.com/shandysiswandi/gobite/internal/pkg/clock"
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jobs"
+	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
+	"github.com/shandysiswandi/gobite/internal/pkg/pgxguard"
+	"github.com/shandysiswandi/gobite/internal/pkg/resilience"
+	"github.com/shandysiswandi/gobite/internal/pkg/retention"
+	"github.com/shandysiswandi/gobite/internal/pkg/router"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"github.com/shandysiswandi/gobite/internal/pkg/validator"
+)
+
+type Dependency struct {
+	Ctx         context.Context
+	DBConn      *pgxguard.Pool
+	Messaging   messaging.Messaging
+	Config      config.Config
+	Instrument  instrument.Instrumentation
+	UID         uid.NumberID
+	UUID        uid.StringID
+	Clock       clock.Clocker
+	Validator   validator.Validator
+	Router      *router.Router
+	Enforcer    *casbin.Enforcer
+	AuthzShadow *authz.Shadow
+	Retention   *retention.Scheduler
+	Jobs        *jobs.Registry
+	HTTPClient  *http.Client
+}
+
+func New(dep Dependency) error {
+	dbAudit := db.NewDB(dep.DBConn, dep.Instrument)
+
+	ucDep := usecase.Dependency{
+		RepoDB:      dbAudit,
+		Config:      dep.Config,
+		UID:         dep.UID,
+		Clock:       dep.Clock,
+		Validator:   dep.Validator,
+		Instrument:  dep.Instrument,
+		Enforcer:    dep.Enforcer,
+		AuthzShadow: dep.AuthzShadow,
+	}
+
+	if dep.Ctx != nil && dep.Config.GetBool("modules.audit.siem.enabled") {
+		exporter, err := newSIEMExporter(dep)
+		if err != nil {
+			return fmt.Errorf("init siem export: %w", err)
+		}
+		ucDep.RepoSIEM = exporter
+		dep.Jobs.Go(dep.Ctx, "audit_siem_export", exporter.Run)
+	}
+
+	uc := usecase.New(ucDep)
+
+	inbound.RegisterHTTPEndpoint(dep.Router, uc)
+	inbound.RegisterJob(dep.Retention, uc)
+	if dep.Ctx != nil {
+		inbound.RegisterMQConsumer(dep.Ctx, dep.Config, dep.Jobs, dep.Messaging, dep.UUID, uc, dep.Instrument)
+	}
+
+	return nil
+}
+
+func newSIEMExporter(dep Dependency) (*siem.Exporter, error) {
+	contentType := "application/x-ndjson"
+	if dep.Config.GetString("modules.audit.siem.format") == entity.SIEMFormatCEF {
+		contentType = "text/plain; charset=utf-8"
+	}
+
+	return siem.New(dep.HTTPClient, dep.Messaging, dep.UUID, dep.Instrument, siem.Config{
+		Sink:          dep.Config.GetString("modules.audit.siem.sink"),
+		URL:           dep.Config.GetString("modules.audit.siem.https.url"),
+		Token:         dep.Config.GetString("modules.audit.siem.https.token"),
+		ContentType:   contentType,
+		Topic:         dep.Config.GetString("modules.audit.siem.kafka.topic"),
+		BatchSize:     dep.Config.GetInt("modules.audit.siem.batch_size"),
+		QueueSize:     dep.Config.GetInt("modules.audit.siem.queue_size"),
+		FlushInterval: dep.Config.GetSecond("modules.audit.siem.flush_interval_seconds"),
+		Retry: resilience.RetryConfig{
+			MaxAttempts: dep.Config.GetInt("modules.audit.siem.retry.max_attempts"),
+			BaseDelay:   time.Duration(dep.Config.GetInt("modules.audit.siem.retry.base_delay_ms")) * time.Millisecond,
+			MaxDelay:    time.Duration(dep.Config.GetInt("modules.audit.siem.retry.max_delay_ms")) * time.Millisecond,
+		},
+	})
+}
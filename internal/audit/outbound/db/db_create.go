@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shandysiswandi/gobite/internal/audit/entity"
+	"github.com/shandysiswandi/gobite/internal/audit/outbound/db/sqlc"
+)
+
+// CreateEvent stores ev and reports false when an event with the same EventID already exists.
+func (s *DB) CreateEvent(ctx context.Context, ev entity.Event) (_ bool, err error) {
+	ctx, span := s.startSpan(ctx, "CreateEvent")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.query.CreateAuditEvent(ctx, sqlc.CreateAuditEventParams{
+		ID:            ev.ID,
+		EventID:       ev.EventID,
+		Module:        ev.Module,
+		Action:        ev.Action,
+		ActorID:       ev.ActorID,
+		SubjectID:     ev.SubjectID,
+		Ip:            ev.IP,
+		UserAgent:     ev.UserAgent,
+		CorrelationID: ev.CorrelationID,
+		Metadata:      ev.Metadata,
+		OccurredAt:    pgtype.Timestamptz{Valid: true, Time: ev.OccurredAt},
+	})
+	if err != nil {
+		return false, s.mapError(err)
+	}
+
+	return affected > 0, nil
+}
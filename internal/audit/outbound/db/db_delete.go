@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shandysiswandi/gobite/internal/audit/outbound/db/sqlc"
+)
+
+func (s *DB) DeleteEventBefore(ctx context.Context, before time.Time, limit int32) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteEventBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.query.DeleteAuditEventBefore(ctx, sqlc.DeleteAuditEventBeforeParams{
+		Before:    pgtype.Timestamptz{Valid: true, Time: before},
+		PageLimit: limit,
+	})
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
+}
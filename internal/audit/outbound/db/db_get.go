@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shandysiswandi/gobite/internal/audit/entity"
+	"github.com/shandysiswandi/gobite/internal/audit/outbound/db/sqlc"
+)
+
+func (s *DB) GetEventByID(ctx context.Context, id int64) (_ *entity.Event, err error) {
+	ctx, span := s.startSpan(ctx, "GetEventByID")
+	defer func() { s.endSpan(span, err) }()
+
+	row, err := s.query.GetAuditEventByID(ctx, id)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	ev := toEvent(sqlc.GetAuditEventFilterRow(row))
+	return &ev, nil
+}
+
+func (s *DB) GetEventList(ctx context.Context, filter entity.EventFilter) (_ []entity.Event, _ int64, err error) {
+	ctx, span := s.startSpan(ctx, "GetEventList")
+	defer func() { s.endSpan(span, err) }()
+
+	dateFrom := pgtype.Timestamptz{Time: filter.DateFrom, Valid: !filter.DateFrom.IsZero()}
+	dateTo := pgtype.Timestamptz{Time: filter.DateTo, Valid: !filter.DateTo.IsZero()}
+
+	items, err := s.query.GetAuditEventFilter(ctx, sqlc.GetAuditEventFilterParams{
+		FilterByModule:      filter.Module != "",
+		Module:              filter.Module,
+		FilterByAction:      filter.Action != "",
+		Action:              filter.Action,
+		FilterByActor:       filter.ActorID > 0,
+		ActorID:             filter.ActorID,
+		FilterBySubject:     filter.SubjectID > 0,
+		SubjectID:           filter.SubjectID,
+		FilterByCorrelation: filter.CorrelationID != "",
+		CorrelationID:       filter.CorrelationID,
+		FilterByDateFrom:    dateFrom.Valid,
+		DateFrom:            dateFrom,
+		FilterByDateTo:      dateTo.Valid,
+		DateTo:              dateTo,
+		PageOffset:          filter.Offset,
+		PageLimit:           filter.Size,
+	})
+	if err != nil {
+		return nil, 0, s.mapError(err)
+	}
+
+	events := make([]entity.Event, 0, len(items))
+	for _, item := range items {
+		events = append(events, toEvent(item))
+	}
+
+	count, err := s.query.CountAuditEventFilter(ctx, sqlc.CountAuditEventFilterParams{
+		FilterByModule:      filter.Module != "",
+		Module:              filter.Module,
+		FilterByAction:      filter.Action != "",
+		Action:              filter.Action,
+		FilterByActor:       filter.ActorID > 0,
+		ActorID:             filter.ActorID,
+		FilterBySubject:     filter.SubjectID > 0,
+		SubjectID:           filter.SubjectID,
+		FilterByCorrelation: filter.CorrelationID != "",
+		CorrelationID:       filter.CorrelationID,
+		FilterByDateFrom:    dateFrom.Valid,
+		DateFrom:            dateFrom,
+		FilterByDateTo:      dateTo.Valid,
+		DateTo:              dateTo,
+	})
+	if err != nil {
+		return nil, 0, s.mapError(err)
+	}
+
+	return events, count, nil
+}
+
+func (s *DB) CountEventBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "CountEventBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	count, err := s.query.CountAuditEventBefore(ctx, pgtype.Timestamptz{Valid: true, Time: before})
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return count, nil
+}
+
+func toEvent(row sqlc.GetAuditEventFilterRow) entity.Event {
+	ev := entity.Event{
+		ID:            row.ID,
+		EventID:       row.EventID,
+		Module:        row.Module,
+		Action:        row.Action,
+		ActorID:       row.ActorID,
+		SubjectID:     row.SubjectID,
+		IP:            row.Ip,
+		UserAgent:     row.UserAgent,
+		CorrelationID: row.CorrelationID,
+		Metadata:      row.Metadata,
+	}
+	if row.OccurredAt.Valid {
+		ev.OccurredAt = row.OccurredAt.Time
+	}
+
+	return ev
+}
+
+func (s *DB) SearchEvents(ctx context.Context, search entity.EventSearch) (_ []entity.Event, err error) {
+	ctx, span := s.startSpan(ctx, "SearchEvents")
+	defer func() { s.endSpan(span, err) }()
+
+	params := sqlc.GetAuditEventSearchParams{
+		FilterByActor:        search.ActorID > 0,
+		ActorID:              search.ActorID,
+		FilterBySubject:      search.SubjectID > 0,
+		SubjectID:            search.SubjectID,
+		FilterByAction:       search.Action != "",
+		Action:               search.Action,
+		FilterByResourceType: search.ResourceType != "",
+		ResourceType:         search.ResourceType,
+		FilterByDateFrom:     !search.DateFrom.IsZero(),
+		DateFrom:             pgtype.Timestamptz{Time: search.DateFrom, Valid: !search.DateFrom.IsZero()},
+		FilterByDateTo:       !search.DateTo.IsZero(),
+		DateTo:               pgtype.Timestamptz{Time: search.DateTo, Valid: !search.DateTo.IsZero()},
+		PageLimit:            search.Limit,
+	}
+	if search.After != nil {
+		params.FilterByCursor = true
+		params.CursorOccurredAt = pgtype.Timestamptz{Time: search.After.OccurredAt, Valid: true}
+		params.CursorID = search.After.ID
+	}
+
+	items, err := s.query.GetAuditEventSearch(ctx, params)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	events := make([]entity.Event, 0, len(items))
+	for _, item := range items {
+		events = append(events, toEvent(sqlc.GetAuditEventFilterRow(item)))
+	}
+
+	return events, nil
+}
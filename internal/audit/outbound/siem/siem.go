@@ -0,0 +1,269 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/httpclient"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
+	"github.com/shandysiswandi/gobite/internal/pkg/resilience"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	SinkHTTPS = "https"
+	SinkKafka = "kafka"
+
+	defaultBatchSize     = 100
+	defaultQueueSize     = 10000
+	defaultFlushInterval = 5 * time.Second
+	// drainTimeout bounds the last flush on shutdown.
+	drainTimeout = 10 * time.Second
+)
+
+var (
+	// ErrSinkUnknown is returned by New for a sink other than SinkHTTPS and SinkKafka.
+	ErrSinkUnknown = errors.New("siem: unknown sink")
+	// ErrQueueFull is returned by Send when the export queue has no room for the record.
+	ErrQueueFull = errors.New("siem: export queue full")
+)
+
+type Config struct {
+	// Sink selects where batches go: SinkHTTPS or SinkKafka.
+	Sink string
+	// URL receives each batch as one POST, one record per line.
+	URL string
+	// Token is sent as a bearer token to URL when set.
+	Token string
+	// ContentType of the POST body, application/x-ndjson for JSON records.
+	ContentType string
+	// Topic receives one message per record on the message bus.
+	Topic string
+
+	BatchSize     int
+	QueueSize     int
+	FlushInterval time.Duration
+	// Retry bounds the attempts to deliver one batch before it is dropped.
+	Retry resilience.RetryConfig
+}
+
+type sink interface {
+	send(ctx context.Context, batchID string, batch [][]byte) error
+}
+
+// pending is a queued record and where the outcome of its batch is reported.
+type pending struct {
+	record []byte
+	done   chan error
+}
+
+// Exporter batches rendered events in memory and delivers them to the configured sink,
+// retrying a failed batch with backoff. Nothing is held only in memory: Send returns once
+// the batch of its record was delivered or given up on, so the message behind an event is
+// acknowledged only after delivery and redelivered by the broker otherwise.
+type Exporter struct {
+	cfg   Config
+	sink  sink
+	uuid  uid.StringID
+	queue chan pending
+
+	sent    metric.Int64Counter
+	dropped metric.Int64Counter
+	failed  metric.Int64Counter
+}
+
+// New creates the exporter for cfg.Sink. client posts to cfg.URL and publisher publishes
+// to cfg.Topic; only the one the sink uses is needed.
+func New(client *http.Client, publisher messaging.Publisher, uuid uid.StringID, ins instrument.Instrumentation, cfg Config) (*Exporter, error) {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+
+	e := &Exporter{cfg: cfg, uuid: uuid, queue: make(chan pending, cfg.QueueSize)}
+
+	switch strings.TrimSpace(cfg.Sink) {
+	case SinkHTTPS:
+		if cfg.URL == "" {
+			return nil, errors.New("siem: url is required for the https sink")
+		}
+		e.sink = &httpsSink{client: client, url: cfg.URL, token: cfg.Token, contentType: cfg.ContentType}
+	case SinkKafka:
+		if cfg.Topic == "" {
+			return nil, errors.New("siem: topic is required for the kafka sink")
+		}
+		e.sink = &kafkaSink{publisher: publisher, topic: cfg.Topic}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrSinkUnknown, cfg.Sink)
+	}
+
+	meter := ins.Meter("audit.outbound.siem")
+	e.sent, _ = meter.Int64Counter("audit.siem.sent", metric.WithDescription("Events delivered to the SIEM"))
+	e.dropped, _ = meter.Int64Counter("audit.siem.dropped", metric.WithDescription("Events refused because the export queue was full"))
+	e.failed, _ = meter.Int64Counter("audit.siem.failed", metric.WithDescription("Events whose batch ran out of retries"))
+
+	return e, nil
+}
+
+// Send adds a rendered record to the next batch and waits until that batch was delivered.
+// It fails at once with ErrQueueFull when the queue is full, with the delivery error when
+// the batch ran out of retries, and with the context error when ctx ends first; the record
+// may still be delivered then.
+func (e *Exporter) Send(ctx context.Context, record []byte) error {
+	p := pending{record: record, done: make(chan error, 1)}
+
+	select {
+	case e.queue <- p:
+	default:
+		e.dropped.Add(ctx, 1)
+		return ErrQueueFull
+	}
+
+	select {
+	case err := <-p.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run sends a batch whenever BatchSize records are queued or FlushInterval passes, until
+// ctx is done; what is still queued then gets one last attempt.
+func (e *Exporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]pending, 0, e.cfg.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			drainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), drainTimeout)
+			defer cancel()
+
+			for {
+				select {
+				case p := <-e.queue:
+					batch = append(batch, p)
+					if len(batch) >= e.cfg.BatchSize {
+						e.flush(drainCtx, batch)
+						batch = batch[:0]
+					}
+				default:
+					e.flush(drainCtx, batch)
+					return nil
+				}
+			}
+		case p := <-e.queue:
+			batch = append(batch, p)
+			if len(batch) >= e.cfg.BatchSize {
+				e.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			e.flush(ctx, batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// flush delivers batch and reports the outcome to every sender in it.
+func (e *Exporter) flush(ctx context.Context, batch []pending) {
+	if len(batch) == 0 {
+		return
+	}
+
+	records := make([][]byte, len(batch))
+	for i, p := range batch {
+		records[i] = p.record
+	}
+
+	// the same ID on every attempt lets the receiver drop a batch it already took
+	batchID := e.uuid.Generate()
+	err := resilience.Retry(ctx, e.cfg.Retry, func(ctx context.Context) error {
+		return e.sink.send(ctx, batchID, records)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to deliver siem batch", "batch_id", batchID, "size", len(batch), "error", err)
+		e.failed.Add(ctx, int64(len(batch)))
+	} else {
+		e.sent.Add(ctx, int64(len(batch)))
+	}
+
+	for _, p := range batch {
+		p.done <- err
+	}
+}
+
+type httpsSink struct {
+	client      *http.Client
+	url         string
+	token       string
+	contentType string
+}
+
+func (s *httpsSink) send(ctx context.Context, batchID string, batch [][]byte) error {
+	body := append(bytes.Join(batch, []byte("\n")), '\n')
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return resilience.Permanent(err)
+	}
+	req.Header.Set("Content-Type", s.contentType)
+	req.Header.Set(httpclient.HeaderIdempotencyKey, batchID)
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("siem: unexpected status %d", resp.StatusCode)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusRequestTimeout {
+		// the receiver rejected the batch itself, sending it again will not help
+		return resilience.Permanent(err)
+	}
+
+	return err
+}
+
+type kafkaSink struct {
+	publisher messaging.Publisher
+	topic     string
+}
+
+// send publishes the records in order. Delivery is at least once: a retried batch publishes
+// the records that went through before the failure again, so consumers dedupe on event.id.
+func (s *kafkaSink) send(ctx context.Context, batchID string, batch [][]byte) error {
+	for _, record := range batch {
+		if _, err := s.publisher.Publish(ctx, s.topic, messaging.OutgoingMessage{
+			Body: record,
+			Key:  []byte(batchID),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
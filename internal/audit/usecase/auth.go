@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+func (s *Usecase) requireAuthorized(ctx context.Context, obj, act string) (*jwt.Claims, error) {
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	ok, err := authz.Enforce(ctx, s.enforcer, s.authzShadow, clm, obj, act)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to check authorization", "user_id", clm.Subject, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if !ok {
+		return nil, goerror.NewBusiness("account not allowed", goerror.CodeForbidden)
+	}
+
+	return clm, nil
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/audit/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type EventDetailInput struct {
+	ID int64 `validate:"required,gt=0"`
+}
+
+func (s *Usecase) EventDetail(ctx context.Context, in EventDetailInput) (*entity.Event, error) {
+	ctx, span := s.startSpan(ctx, "EventDetail")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if _, err := s.requireAuthorized(ctx, constant.PermAuditEvents, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	ev, err := s.repoDB.GetEventByID(ctx, in.ID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return nil, goerror.NewBusiness("audit event not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get audit event", "id", in.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return ev, nil
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/audit/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type (
+	ListEventsInput struct {
+		Module        string `validate:"max=64"`
+		Action        string `validate:"max=128"`
+		ActorID       int64  `validate:"gte=0"`
+		SubjectID     int64  `validate:"gte=0"`
+		CorrelationID string `validate:"max=128"`
+		DateFrom      time.Time
+		DateTo        time.Time
+		Size          int32
+		Page          int32
+	}
+
+	ListEventsOutput struct {
+		Page   int32
+		Size   int32
+		Total  int64
+		Events []entity.Event
+	}
+)
+
+func (s *Usecase) ListEvents(ctx context.Context, in ListEventsInput) (*ListEventsOutput, error) {
+	ctx, span := s.startSpan(ctx, "ListEvents")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if _, err := s.requireAuthorized(ctx, constant.PermAuditEvents, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	if in.Size <= 0 || in.Size > 100 {
+		in.Size = 20 // default limit
+	}
+	in.Page = max(in.Page, 1)
+
+	events, total, err := s.repoDB.GetEventList(ctx, entity.EventFilter{
+		Module:        in.Module,
+		Action:        in.Action,
+		ActorID:       in.ActorID,
+		SubjectID:     in.SubjectID,
+		CorrelationID: in.CorrelationID,
+		DateFrom:      in.DateFrom,
+		DateTo:        in.DateTo,
+		Size:          in.Size,
+		Offset:        (in.Page - 1) * in.Size,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo list audit events", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &ListEventsOutput{
+		Page:   in.Page,
+		Size:   in.Size,
+		Total:  total,
+		Events: events,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/audit/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+// maxUserAgentLength keeps a hostile User-Agent header from bloating the table.
+const maxUserAgentLength = 512
+
+type RecordEventInput struct {
+	EventID       string `validate:"required,max=128"`
+	Module        string `validate:"required,max=64"`
+	Action        string `validate:"required,max=128"`
+	ActorID       int64  `validate:"gte=0"`
+	SubjectID     int64  `validate:"gte=0"`
+	IP            string `validate:"max=64"`
+	UserAgent     string
+	CorrelationID string `validate:"max=128"`
+	Metadata      map[string]any
+	OccurredAt    time.Time
+}
+
+// RecordEvent stores one audit event. Invalid events are logged and dropped instead of
+// being redelivered forever; a duplicate EventID is ignored.
+func (s *Usecase) RecordEvent(ctx context.Context, in RecordEventInput) error {
+	ctx, span := s.startSpan(ctx, "RecordEvent")
+	defer span.End()
+
+	in.Module = strings.TrimSpace(in.Module)
+	in.Action = strings.TrimSpace(in.Action)
+	if len(in.UserAgent) > maxUserAgentLength {
+		in.UserAgent = in.UserAgent[:maxUserAgentLength]
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		slog.ErrorContext(ctx, "Validation failed", "error", err)
+		return nil
+	}
+
+	if in.CorrelationID == "" {
+		in.CorrelationID = instrument.CarrierFromContext(ctx).CorrelationID
+	}
+	if in.OccurredAt.IsZero() {
+		in.OccurredAt = s.clock.Now()
+	}
+	if in.Metadata == nil {
+		in.Metadata = map[string]any{}
+	}
+
+	created, err := s.repoDB.CreateEvent(ctx, entity.Event{
+		ID:            s.uid.Generate(),
+		EventID:       in.EventID,
+		Module:        in.Module,
+		Action:        in.Action,
+		ActorID:       in.ActorID,
+		SubjectID:     in.SubjectID,
+		IP:            in.IP,
+		UserAgent:     in.UserAgent,
+		CorrelationID: in.CorrelationID,
+		Metadata:      valueobject.JSONMap(in.Metadata),
+		OccurredAt:    in.OccurredAt,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo create audit event", "event_id", in.EventID, "action", in.Action, "error", err)
+		return err
+	}
+
+	if !created {
+		slog.InfoContext(ctx, "audit event already recorded", "event_id", in.EventID)
+	}
+
+	return nil
+}
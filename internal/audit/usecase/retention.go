@@ -0,0 +1,14 @@
+package usecase
+
+import "github.com/shandysiswandi/gobite/internal/pkg/retention"
+
+// RetentionTargets lists the audit tables cleaned up by the retention scheduler.
+func (s *Usecase) RetentionTargets() []retention.Target {
+	return []retention.Target{
+		{
+			Table: "audit_events",
+			Count: s.repoDB.CountEventBefore,
+			Purge: s.repoDB.DeleteEventBefore,
+		},
+	}
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/audit/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+// exportPageSize is how many events an export reads per query.
+const exportPageSize = 500
+
+type (
+	SearchEventsInput struct {
+		ActorID      int64  `validate:"gte=0"`
+		SubjectID    int64  `validate:"gte=0"`
+		Action       string `validate:"max=128"`
+		ResourceType string `validate:"max=64,excludesall=."`
+		DateFrom     time.Time
+		DateTo       time.Time
+		Cursor       string `validate:"max=128"`
+		Size         int32
+	}
+
+	SearchEventsOutput struct {
+		Size   int32
+		Events []entity.Event
+		// NextCursor continues after the last event; empty on the last page.
+		NextCursor string
+	}
+)
+
+// SearchEvents pages the audit trail newest first for compliance investigations. Pages are
+// addressed by an opaque cursor, so events recorded meanwhile never shift or repeat results.
+func (s *Usecase) SearchEvents(ctx context.Context, in SearchEventsInput) (*SearchEventsOutput, error) {
+	ctx, span := s.startSpan(ctx, "SearchEvents")
+	defer span.End()
+
+	search, err := s.eventSearch(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Size <= 0 || in.Size > 100 {
+		in.Size = 20 // default limit
+	}
+	// one extra row tells whether another page follows
+	search.Limit = in.Size + 1
+
+	events, err := s.repoDB.SearchEvents(ctx, search)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo search audit events", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	out := &SearchEventsOutput{Size: in.Size, Events: events}
+	if len(events) > int(in.Size) {
+		out.Events = events[:in.Size]
+		last := out.Events[len(out.Events)-1]
+		out.NextCursor = entity.EventCursor{OccurredAt: last.OccurredAt, ID: last.ID}.String()
+	}
+
+	return out, nil
+}
+
+// ExportEvents authorizes and validates an export up front, then returns every matching
+// event newest first, read page by page while the caller consumes them. The export stops
+// at modules.audit.export_max_rows events.
+func (s *Usecase) ExportEvents(ctx context.Context, in SearchEventsInput) (iter.Seq2[entity.Event, error], error) {
+	ctx, span := s.startSpan(ctx, "ExportEvents")
+	defer span.End()
+
+	search, err := s.eventSearch(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	search.Limit = exportPageSize
+
+	maxRows := s.cfg.GetInt("modules.audit.export_max_rows")
+
+	slog.InfoContext(ctx, "audit events export started",
+		"actor_id", in.ActorID,
+		"subject_id", in.SubjectID,
+		"action", in.Action,
+		"resource_type", in.ResourceType,
+		"date_from", in.DateFrom,
+		"date_to", in.DateTo,
+	)
+
+	return func(yield func(entity.Event, error) bool) {
+		sent := 0
+		for {
+			events, err := s.repoDB.SearchEvents(ctx, search)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to repo search audit events", "error", err)
+				yield(entity.Event{}, goerror.NewServer(err))
+				return
+			}
+
+			for _, ev := range events {
+				if maxRows > 0 && sent >= maxRows {
+					return
+				}
+				if !yield(ev, nil) {
+					return
+				}
+				sent++
+			}
+
+			if len(events) < int(search.Limit) {
+				return
+			}
+			last := events[len(events)-1]
+			search.After = &entity.EventCursor{OccurredAt: last.OccurredAt, ID: last.ID}
+		}
+	}, nil
+}
+
+func (s *Usecase) eventSearch(ctx context.Context, in SearchEventsInput) (entity.EventSearch, error) {
+	if err := s.validator.Validate(in); err != nil {
+		return entity.EventSearch{}, goerror.NewInvalidInput(err)
+	}
+
+	if _, err := s.requireAuthorized(ctx, constant.PermAuditEvents, constant.PermActRead); err != nil {
+		return entity.EventSearch{}, err
+	}
+
+	search := entity.EventSearch{
+		ActorID:      in.ActorID,
+		SubjectID:    in.SubjectID,
+		Action:       in.Action,
+		ResourceType: in.ResourceType,
+		DateFrom:     in.DateFrom,
+		DateTo:       in.DateTo,
+	}
+
+	if in.Cursor != "" {
+		after, err := entity.ParseEventCursor(in.Cursor)
+		if err != nil {
+			return entity.EventSearch{}, goerror.NewInvalidInput(nil, "cursor", "invalid cursor")
+		}
+		search.After = after
+	}
+
+	return search, nil
+}
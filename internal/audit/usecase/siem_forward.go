@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/audit/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+// ForwardSIEMEvent delivers one authentication or authorization event to the SIEM and
+// returns once it was delivered, failing when the export queue is full or the delivery
+// ran out of retries so the message is redelivered. Actions outside
+// modules.audit.siem.actions are skipped, and an event that cannot be rendered is logged
+// and dropped so the message is not redelivered forever.
+func (s *Usecase) ForwardSIEMEvent(ctx context.Context, in RecordEventInput) error {
+	ctx, span := s.startSpan(ctx, "ForwardSIEMEvent")
+	defer span.End()
+
+	if s.repoSIEM == nil {
+		return nil
+	}
+
+	in.Action = strings.TrimSpace(in.Action)
+	if !s.siemForwards(in.Action) {
+		return nil
+	}
+
+	if in.OccurredAt.IsZero() {
+		in.OccurredAt = s.clock.Now()
+	}
+	if len(in.UserAgent) > maxUserAgentLength {
+		in.UserAgent = in.UserAgent[:maxUserAgentLength]
+	}
+
+	ev := entity.Event{
+		EventID:       in.EventID,
+		Module:        strings.TrimSpace(in.Module),
+		Action:        in.Action,
+		ActorID:       in.ActorID,
+		SubjectID:     in.SubjectID,
+		IP:            in.IP,
+		UserAgent:     in.UserAgent,
+		CorrelationID: in.CorrelationID,
+		Metadata:      valueobject.JSONMap(in.Metadata),
+		OccurredAt:    in.OccurredAt,
+	}
+
+	var (
+		record []byte
+		err    error
+	)
+	if s.cfg.GetString("modules.audit.siem.format") == entity.SIEMFormatCEF {
+		record, err = ev.CEF()
+	} else {
+		record, err = ev.ECS()
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to render siem event", "event_id", in.EventID, "action", in.Action, "error", err)
+		return nil
+	}
+
+	if err := s.repoSIEM.Send(ctx, record); err != nil {
+		slog.ErrorContext(ctx, "failed to send siem event", "event_id", in.EventID, "action", in.Action, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	return nil
+}
+
+// siemForwards reports whether action matches one of the configured prefixes; an empty
+// list forwards every action.
+func (s *Usecase) siemForwards(action string) bool {
+	prefixes := s.cfg.GetArray("modules.audit.siem.actions")
+	if len(prefixes) == 0 {
+		return true
+	}
+
+	for _, p := range prefixes {
+		if p = strings.TrimSpace(p); p != "" && strings.HasPrefix(action, p) {
+			return true
+		}
+	}
+
+	return false
+}
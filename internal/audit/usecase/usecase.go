@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/casbin/casbin/v3"
+	"github.com/shandysiswandi/gobite/internal/audit/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
+	"github.com/shandysiswandi/gobite/internal/pkg/clock"
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"github.com/shandysiswandi/gobite/internal/pkg/validator"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type repoDB interface {
+	CreateEvent(ctx context.Context, ev entity.Event) (bool, error)
+	GetEventByID(ctx context.Context, id int64) (*entity.Event, error)
+	GetEventList(ctx context.Context, filter entity.EventFilter) ([]entity.Event, int64, error)
+	SearchEvents(ctx context.Context, search entity.EventSearch) ([]entity.Event, error)
+	CountEventBefore(ctx context.Context, before time.Time) (int64, error)
+	DeleteEventBefore(ctx context.Context, before time.Time, limit int32) (int64, error)
+}
+
+type repoSIEM interface {
+	Send(ctx context.Context, record []byte) error
+}
+
+type Usecase struct {
+	repoDB      repoDB
+	repoSIEM    repoSIEM
+	cfg         config.Config
+	uid         uid.NumberID
+	clock       clock.Clocker
+	validator   validator.Validator
+	ins         instrument.Instrumentation
+	enforcer    *casbin.Enforcer
+	authzShadow *authz.Shadow
+}
+
+type Dependency struct {
+	RepoDB      repoDB
+	RepoSIEM    repoSIEM // nil when the SIEM export is disabled
+	Config      config.Config
+	UID         uid.NumberID
+	Clock       clock.Clocker
+	Validator   validator.Validator
+	Instrument  instrument.Instrumentation
+	Enforcer    *casbin.Enforcer
+	AuthzShadow *authz.Shadow
+}
+
+func New(dep Dependency) *Usecase {
+	return &Usecase{
+		repoDB:      dep.RepoDB,
+		repoSIEM:    dep.RepoSIEM,
+		cfg:         dep.Config,
+		uid:         dep.UID,
+		clock:       dep.Clock,
+		validator:   dep.Validator,
+		ins:         dep.Instrument,
+		enforcer:    dep.Enforcer,
+		authzShadow: dep.AuthzShadow,
+	}
+}
+
+func (s *Usecase) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return s.ins.Tracer("audit.usecase").Start(ctx, name)
+}
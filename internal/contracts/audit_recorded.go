@@ -0,0 +1,23 @@
+package contracts
+
+const (
+	AuditRecordedDestination   string = "audit_recorded"
+	AuditRecordedConsumerAudit string = "audit_recorded_audit"
+	AuditRecordedConsumerSIEM  string = "audit_recorded_siem"
+)
+
+// AuditRecorded is published by any module for a security-relevant action so the audit
+// module can store it. The envelope ID deduplicates redelivered messages.
+type AuditRecorded struct {
+	Module        string         `json:"module"`
+	Action        string         `json:"action"`
+	ActorID       int64          `json:"actor_id,omitempty"`
+	SubjectID     int64          `json:"subject_id,omitempty"`
+	IP            string         `json:"ip,omitempty"`
+	UserAgent     string         `json:"user_agent,omitempty"`
+	CorrelationID string         `json:"correlation_id,omitempty"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+}
+
+func (AuditRecorded) EventType() string { return "audit.recorded" }
+func (AuditRecorded) EventVersion() int { return 1 }
@@ -0,0 +1,12 @@
+// Package contracts defines the versioned event payloads exchanged between modules.
+//
+// Producers publish a payload wrapped in an Envelope that names its type and
+// version, and consumers decode it back into the same Go type, so both sides of a
+// destination share one definition. Each payload also ships a JSON Schema under
+// schema/ for consumers written outside this repository.
+//
+// Adding an optional field keeps the version. Renaming, removing, or changing the
+// meaning of a field requires a new version; the previous version stays until
+// every consumer has moved over. Consumers still accept the bare payloads that were
+// published before envelopes existed and treat them as version 1.
+package contracts
@@ -0,0 +1,111 @@
+package contracts
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrTypeMismatch is returned when an envelope carries a different event type than expected.
+	ErrTypeMismatch = errors.New("contracts: event type mismatch")
+	// ErrUnsupportedVersion is returned when an envelope carries a version the consumer does not know.
+	ErrUnsupportedVersion = errors.New("contracts: unsupported event version")
+	// ErrSchemaNotFound is returned when no schema is registered for an event type and version.
+	ErrSchemaNotFound = errors.New("contracts: schema not found")
+)
+
+//go:embed schema/*.json
+var schemas embed.FS
+
+// Event is implemented by every payload defined in this package.
+type Event interface {
+	// EventType is the stable name of the event, e.g. "user.registered".
+	EventType() string
+	// EventVersion is the payload version, starting at 1.
+	EventVersion() int
+}
+
+// Envelope is the wire format of every event published between modules.
+type Envelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+	// Carrier holds the correlation ID, user, and tenant of the publisher, keyed by header
+	// name, for brokers such as NSQ that cannot carry message headers.
+	Carrier map[string]string `json:"carrier,omitempty"`
+}
+
+// Marshal wraps ev in an envelope and encodes it as JSON. carrier may be nil.
+func Marshal(id string, occurredAt time.Time, carrier map[string]string, ev Event) ([]byte, error) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(Envelope{
+		ID:         id,
+		Type:       ev.EventType(),
+		Version:    ev.EventVersion(),
+		OccurredAt: occurredAt,
+		Data:       data,
+		Carrier:    carrier,
+	})
+}
+
+// CarrierOf returns the carrier of the envelope in body, or nil when there is none.
+func CarrierOf(body []byte) map[string]string {
+	var env struct {
+		Carrier map[string]string `json:"carrier"`
+	}
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil
+	}
+	return env.Carrier
+}
+
+// Unmarshal decodes body into ev and returns its envelope. Bodies without an envelope are
+// decoded as the bare version 1 payload published before envelopes were introduced.
+func Unmarshal(body []byte, ev Event) (*Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, err
+	}
+
+	if env.Type == "" {
+		if ev.EventVersion() != 1 {
+			return nil, fmt.Errorf("%w: %s v1", ErrUnsupportedVersion, ev.EventType())
+		}
+		if err := json.Unmarshal(body, ev); err != nil {
+			return nil, err
+		}
+
+		return &Envelope{Type: ev.EventType(), Version: 1, Data: body}, nil
+	}
+
+	if env.Type != ev.EventType() {
+		return nil, fmt.Errorf("%w: got %s, want %s", ErrTypeMismatch, env.Type, ev.EventType())
+	}
+	if env.Version != ev.EventVersion() {
+		return nil, fmt.Errorf("%w: %s v%d", ErrUnsupportedVersion, env.Type, env.Version)
+	}
+	if err := json.Unmarshal(env.Data, ev); err != nil {
+		return nil, err
+	}
+
+	return &env, nil
+}
+
+// Schema returns the JSON Schema of the payload for an event type and version.
+func Schema(eventType string, version int) ([]byte, error) {
+	b, err := schemas.ReadFile(fmt.Sprintf("schema/%s.v%d.json", eventType, version))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s v%d", ErrSchemaNotFound, eventType, version)
+	}
+
+	return b, nil
+}
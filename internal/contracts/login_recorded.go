@@ -0,0 +1,17 @@
+package contracts
+
+const (
+	LoginRecordedDestination      string = "user_login_recorded"
+	LoginRecordedConsumerIdentity string = "user_login_recorded_identity"
+)
+
+// LoginRecorded is published after a sign-in attempt is added to the login history, so its
+// location can be looked up from the IP without slowing the login down.
+type LoginRecorded struct {
+	LoginEventID int64  `json:"login_event_id"`
+	UserID       int64  `json:"user_id"`
+	IP           string `json:"ip"`
+}
+
+func (LoginRecorded) EventType() string { return "user.login_recorded" }
+func (LoginRecorded) EventVersion() int { return 1 }
@@ -0,0 +1,27 @@
+package contracts
+
+import "time"
+
+const (
+	MFARecoveryDestination          string = "user_mfa_recovery"
+	MFARecoveryConsumerNotification string = "user_mfa_recovery_notification"
+)
+
+const (
+	MFARecoveryStageRequested string = "requested"
+	MFARecoveryStagePending   string = "pending"
+	MFARecoveryStageCompleted string = "completed"
+)
+
+// MFARecovery is published at every stage of a lost two-factor authentication recovery.
+type MFARecovery struct {
+	UserID         int64     `json:"user_id"`
+	Email          string    `json:"email"`
+	FullName       string    `json:"full_name"`
+	Stage          string    `json:"stage"`
+	ChallengeToken string    `json:"challenge_token,omitempty"`
+	AvailableAt    time.Time `json:"available_at,omitzero"`
+}
+
+func (MFARecovery) EventType() string { return "user.mfa_recovery" }
+func (MFARecovery) EventVersion() int { return 1 }
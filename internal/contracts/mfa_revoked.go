@@ -0,0 +1,16 @@
+package contracts
+
+const (
+	MFARevokedDestination          string = "user_mfa_revoked"
+	MFARevokedConsumerNotification string = "user_mfa_revoked_notification"
+)
+
+// MFARevoked is published when an administrator removes a user's two-factor authentication.
+type MFARevoked struct {
+	UserID   int64  `json:"user_id"`
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+}
+
+func (MFARevoked) EventType() string { return "user.mfa_revoked" }
+func (MFARevoked) EventVersion() int { return 1 }
@@ -0,0 +1,26 @@
+package contracts
+
+import "time"
+
+const (
+	NewSignInDestination          string = "user_new_sign_in"
+	NewSignInConsumerNotification string = "user_new_sign_in_notification"
+)
+
+// NewSignIn is published when a user signs in from a device or a country not seen before
+// on their account, so they can react if it was not them.
+type NewSignIn struct {
+	UserID     int64     `json:"user_id"`
+	Email      string    `json:"email"`
+	FullName   string    `json:"full_name"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	DeviceName string    `json:"device_name,omitempty"`
+	Country    string    `json:"country"`
+	NewDevice  bool      `json:"new_device"`
+	NewCountry bool      `json:"new_country"`
+	SignedInAt time.Time `json:"signed_in_at"`
+}
+
+func (NewSignIn) EventType() string { return "user.new_sign_in" }
+func (NewSignIn) EventVersion() int { return 1 }
@@ -0,0 +1,18 @@
+package contracts
+
+const NotificationRepliedDestination string = "notification_replied"
+
+// NotificationReplied is published by the notification module when a user answers a
+// notification email. Modules that start workflows from replies consume it.
+type NotificationReplied struct {
+	ReplyID        int64  `json:"reply_id"`
+	NotificationID int64  `json:"notification_id"`
+	UserID         int64  `json:"user_id"`
+	TriggerKey     string `json:"trigger_key"`
+	From           string `json:"from"`
+	Subject        string `json:"subject,omitempty"`
+	Body           string `json:"body"`
+}
+
+func (NotificationReplied) EventType() string { return "notification.replied" }
+func (NotificationReplied) EventVersion() int { return 1 }
@@ -0,0 +1,19 @@
+package contracts
+
+const (
+	NotificationRequestedDestination          string = "notification_requested"
+	NotificationRequestedConsumerNotification string = "notification_requested_notification"
+)
+
+// NotificationRequested asks the notification module to deliver a registered trigger
+// to a user. Any module can publish it without a dedicated destination of its own.
+type NotificationRequested struct {
+	UserID     int64          `json:"user_id"`
+	Email      string         `json:"email,omitempty"`
+	TriggerKey string         `json:"trigger_key"`
+	Channels   []string       `json:"channels"`
+	Data       map[string]any `json:"data"`
+}
+
+func (NotificationRequested) EventType() string { return "notification.requested" }
+func (NotificationRequested) EventVersion() int { return 1 }
@@ -0,0 +1,16 @@
+package contracts
+
+const (
+	PasswordForgotDestination          string = "user_forgot_password"
+	PasswordForgotConsumerNotification string = "user_forgot_password_notification"
+)
+
+// PasswordForgot is published when a user asks for a password reset link.
+type PasswordForgot struct {
+	UserID         int64  `json:"user_id"`
+	Email          string `json:"email"`
+	ChallengeToken string `json:"challenge_token"`
+}
+
+func (PasswordForgot) EventType() string { return "user.password_forgot" }
+func (PasswordForgot) EventVersion() int { return 1 }
@@ -0,0 +1,16 @@
+package contracts
+
+const (
+	PushTokensRejectedDestination          string = "notification_push_tokens_rejected"
+	PushTokensRejectedConsumerNotification string = "notification_push_tokens_rejected_notification"
+)
+
+// PushTokensRejected is published by the push gateway with the device tokens the provider
+// reported as no longer deliverable, such as FCM UNREGISTERED or APNs Unregistered.
+type PushTokensRejected struct {
+	Tokens []string `json:"tokens"`
+	Reason string   `json:"reason"`
+}
+
+func (PushTokensRejected) EventType() string { return "notification.push_tokens_rejected" }
+func (PushTokensRejected) EventVersion() int { return 1 }
@@ -0,0 +1,18 @@
+package contracts
+
+const (
+	SessionRevokedDestination          string = "user_session_revoked"
+	SessionRevokedConsumerNotification string = "user_session_revoked_notification"
+)
+
+// SessionRevoked is published when the oldest sessions of a user are signed out by the session limit.
+type SessionRevoked struct {
+	UserID       int64  `json:"user_id"`
+	Email        string `json:"email"`
+	FullName     string `json:"full_name"`
+	RevokedCount int64  `json:"revoked_count"`
+	Limit        int    `json:"limit"`
+}
+
+func (SessionRevoked) EventType() string { return "user.session_revoked" }
+func (SessionRevoked) EventVersion() int { return 1 }
@@ -0,0 +1,29 @@
+package contracts
+
+// Topic is a destination together with the consumers that read it.
+type Topic struct {
+	Destination string
+	Consumers   []string
+}
+
+// Topics lists every destination published in this repository with its consumers, so
+// the message bus can be provisioned before the producers and consumers start.
+// Destinations read only outside this repository have no consumers here.
+func Topics() []Topic {
+	return []Topic{
+		{Destination: UserRegisteredDestination, Consumers: []string{UserRegisteredConsumerNotification}},
+		{Destination: PasswordForgotDestination, Consumers: []string{PasswordForgotConsumerNotification}},
+		{Destination: MFARevokedDestination, Consumers: []string{MFARevokedConsumerNotification}},
+		{Destination: MFARecoveryDestination, Consumers: []string{MFARecoveryConsumerNotification}},
+		{Destination: SessionRevokedDestination, Consumers: []string{SessionRevokedConsumerNotification}},
+		{Destination: NewSignInDestination, Consumers: []string{NewSignInConsumerNotification}},
+		{Destination: NotificationRequestedDestination, Consumers: []string{NotificationRequestedConsumerNotification}},
+		{Destination: NotificationRepliedDestination},
+		{Destination: PushTokensRejectedDestination, Consumers: []string{PushTokensRejectedConsumerNotification}},
+		{Destination: UserDeletionScheduledDestination, Consumers: []string{UserDeletionScheduledConsumerIdentity}},
+		{Destination: LoginRecordedDestination, Consumers: []string{LoginRecordedConsumerIdentity}},
+		{Destination: UserExportRequestedDestination, Consumers: []string{UserExportRequestedConsumerIdentity}},
+		{Destination: VerificationReminderDueDestination, Consumers: []string{VerificationReminderDueConsumerIdentity}},
+		{Destination: AuditRecordedDestination, Consumers: []string{AuditRecordedConsumerAudit, AuditRecordedConsumerSIEM}},
+	}
+}
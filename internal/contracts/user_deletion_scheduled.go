@@ -0,0 +1,18 @@
+package contracts
+
+import "time"
+
+const (
+	UserDeletionScheduledDestination      string = "user_deletion_scheduled"
+	UserDeletionScheduledConsumerIdentity string = "user_deletion_scheduled_identity"
+)
+
+// UserDeletionScheduled is published, delayed where the broker allows it, when a user asks
+// for their account to be deleted. The account is anonymized once ScheduledAt has passed.
+type UserDeletionScheduled struct {
+	UserID      int64     `json:"user_id"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+func (UserDeletionScheduled) EventType() string { return "user.deletion_scheduled" }
+func (UserDeletionScheduled) EventVersion() int { return 1 }
@@ -0,0 +1,15 @@
+package contracts
+
+const (
+	UserExportRequestedDestination      string = "user_export_requested"
+	UserExportRequestedConsumerIdentity string = "user_export_requested_identity"
+)
+
+// UserExportRequested is published when an administrator queues an asynchronous user export.
+// The worker reads the filters and format from the job itself.
+type UserExportRequested struct {
+	JobID int64 `json:"job_id"`
+}
+
+func (UserExportRequested) EventType() string { return "user.export_requested" }
+func (UserExportRequested) EventVersion() int { return 1 }
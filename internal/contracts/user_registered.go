@@ -0,0 +1,17 @@
+package contracts
+
+const (
+	UserRegisteredDestination          string = "user_registration"
+	UserRegisteredConsumerNotification string = "user_registration_notification"
+)
+
+// UserRegistered is published when an account is created and awaits email verification.
+type UserRegistered struct {
+	UserID         int64  `json:"user_id"`
+	Email          string `json:"email"`
+	FullName       string `json:"full_name"`
+	ChallengeToken string `json:"challenge_token"`
+}
+
+func (UserRegistered) EventType() string { return "user.registered" }
+func (UserRegistered) EventVersion() int { return 1 }
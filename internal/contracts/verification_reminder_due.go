@@ -0,0 +1,16 @@
+package contracts
+
+const (
+	VerificationReminderDueDestination      string = "user_verification_reminder_due"
+	VerificationReminderDueConsumerIdentity string = "user_verification_reminder_due_identity"
+)
+
+// VerificationReminderDue is published by the reminder scheduler for a user who has not
+// verified their email yet. Reminder counts from 1 for the first reminder.
+type VerificationReminderDue struct {
+	UserID   int64 `json:"user_id"`
+	Reminder int32 `json:"reminder"`
+}
+
+func (VerificationReminderDue) EventType() string { return "user.verification_reminder_due" }
+func (VerificationReminderDue) EventVersion() int { return 1 }
@@ -41,6 +41,24 @@ type Challenge struct {
 	Metadata  valueobject.JSONMap
 }
 
+type MFAFactorInfo struct {
+	ID           int64
+	Type         MFAType
+	FriendlyName string
+	IsVerified   bool
+	LastUsedAt   *time.Time
+	CreatedAt    time.Time
+}
+
+type AuditLog struct {
+	ID           int64
+	ActorID      int64
+	TargetUserID int64
+	Action       AuditAction
+	Metadata     valueobject.JSONMap
+	CreatedAt    time.Time
+}
+
 type MFABackupCode struct {
 	ID     int64
 	UserID int64
@@ -54,7 +72,7 @@ type RefreshToken struct {
 	ExpiresAt         time.Time
 	Revoked           bool
 	ReplacedByTokenID int64
-	Metadata          valueobject.JSONMap
+	Metadata          SessionMetadata
 }
 
 // ---- //
@@ -75,13 +93,18 @@ type UserLoginInfo struct {
 	Status   UserStatus
 	Password string
 	HasMFA   bool
+	// MustResetPassword and MFARequired are set by an administrator and hold the login
+	// until the user resets the password or enrolls a second factor.
+	MustResetPassword bool
+	MFARequired       bool
 }
 
 type UserCredentialInfo struct {
-	ID       int64
-	Email    string
-	Status   UserStatus
-	Password string
+	ID                int64
+	Email             string
+	Status            UserStatus
+	Password          string
+	PasswordUpdatedAt time.Time
 }
 
 type RotateRefreshToken struct {
@@ -90,6 +113,10 @@ type RotateRefreshToken struct {
 	UserID       int64
 	NewToken     string
 	NewExpiresAt time.Time
+	// SessionStartedAt is carried over from the rotated token.
+	SessionStartedAt time.Time
+	// Metadata describes the client that performed the rotation.
+	Metadata SessionMetadata
 }
 
 type UserRefreshToken struct {
@@ -101,6 +128,66 @@ type UserRefreshToken struct {
 	RefreshRevoked           bool
 	RefreshReplacedByTokenID *int64
 	RefreshExpiresAt         time.Time
+	RefreshSessionStartedAt  time.Time
+	RefreshMetadata          SessionMetadata
+}
+
+// Session is an active refresh token as shown to its owner. CreatedAt is when the
+// token was issued, so it doubles as the last time the session was used.
+type Session struct {
+	ID               int64
+	IP               string
+	UserAgent        string
+	Client           string
+	DeviceName       string
+	CreatedAt        time.Time
+	SessionStartedAt time.Time
+	ExpiresAt        time.Time
+}
+
+// LoginEvent is a sign-in attempt on a known account. The location is empty until the
+// IP has been looked up, and stays empty for private addresses or when lookups are off.
+type LoginEvent struct {
+	ID            int64
+	UserID        int64
+	Success       bool
+	Method        LoginMethod
+	FailureReason string
+	IP            string
+	UserAgent     string
+	Location      GeoLocation
+	CreatedAt     time.Time
+}
+
+// UserDevice is a device a user signed in from. Fingerprint is an HMAC of the client type
+// and user agent, and Country the last country the device was seen in (empty when unknown).
+type UserDevice struct {
+	ID          int64
+	UserID      int64
+	Fingerprint string
+	UserAgent   string
+	IP          string
+	Country     string
+}
+
+// TrustedDevice is a device a user chose to remember when completing an MFA login. A login
+// presenting its device token skips the MFA challenge until ExpiresAt.
+type TrustedDevice struct {
+	ID         int64
+	UserID     int64
+	UserAgent  string
+	IP         string
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// GeoLocation is the coarse location of an IP address.
+type GeoLocation struct {
+	Country string
+	Region  string
+	City    string
 }
 
 type VerifyUserRegistration struct {
@@ -111,6 +198,27 @@ type VerifyUserRegistration struct {
 	NewUserStatus UserStatus
 }
 
+// AcceptUserInvite activates an invited user with the password they chose.
+type AcceptUserInvite struct {
+	ChallengeID int64
+	UserID      int64
+	Hash        string
+}
+
+// UserEmail is the email of a user, as read to seal it.
+type UserEmail struct {
+	UserID int64
+	Email  string
+}
+
+type ChangeUserEmail struct {
+	ChallengeID     int64
+	UserID          int64
+	Email           string
+	EmailHash       string
+	EmailSealedOnly bool // only the sealed copy of a hashed email is stored
+}
+
 type UserListFilterData struct {
 	IsFilterBySearch bool
 	IsFilterByStatus bool
@@ -125,30 +233,155 @@ type UserListFilterData struct {
 }
 
 type NewUser struct {
-	ID        int64
-	Email     string
-	FullName  string
-	AvatarURL string
-	Status    UserStatus
-	CreatedBy int64
-	UpdatedBy int64
+	ID              int64
+	Email           string
+	EmailHash       string // empty unless hashed email lookup is enabled
+	EmailSealedOnly bool   // only the sealed copy of a hashed email is stored
+	FullName        string
+	AvatarURL       string
+	Status          UserStatus
+	Username        string // empty when the user has none
+	CreatedBy       int64
+	UpdatedBy       int64
 }
 
 type PatchUser struct {
-	ID        int64
-	Email     string
-	FullName  string
-	AvatarURL string
-	Status    UserStatus
-	UpdatedBy int64
+	ID              int64
+	Email           string
+	EmailHash       string // empty unless hashed email lookup is enabled
+	EmailSealedOnly bool   // only the sealed copy of a hashed email is stored
+	FullName        string
+	AvatarURL       string
+	Status          UserStatus
+	UpdatedBy       int64
+	// MustResetPassword and MFARequired, when set, change the flags an administrator
+	// forces on the next login.
+	MustResetPassword *bool
+	MFARequired       *bool
+	// ExpectedVersion, when set, refuses the patch if the user changed since that version.
+	ExpectedVersion valueobject.Version
 }
 
 type UpsertUser struct {
-	ID        int64
-	Email     string
-	FullName  string
-	AvatarURL string
-	Status    UserStatus
-	CreatedBy int64
-	UpdatedBy int64
+	ID              int64
+	Email           string
+	EmailHash       string // empty unless hashed email lookup is enabled
+	EmailSealedOnly bool   // only the sealed copy of a hashed email is stored
+	FullName        string
+	AvatarURL       string
+	Status          UserStatus
+	CreatedBy       int64
+	UpdatedBy       int64
+}
+
+type OAuthIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FullName      string
+	AvatarURL     string
+}
+
+// SAMLIdentity is what a verified SAML assertion says about the user: the NameID and every
+// attribute value, keyed by attribute name and friendly name. AssertionID and ExpiresAt,
+// the end of the assertion's validity window, let a consumed assertion be refused again.
+type SAMLIdentity struct {
+	Subject     string
+	Attributes  map[string][]string
+	AssertionID string
+	ExpiresAt   time.Time
+}
+
+// UserConnection is an external identity, an OAuth account or a SAML subject, a user signs
+// in with. Identities linked by the user live in identity_user_connections next to the ones
+// created on first sign-in rather than in a table of their own, so sign-in resolves both
+// alike and the unique provider subject keeps an identity on one account.
+type UserConnection struct {
+	ID             int64
+	UserID         int64
+	Provider       string
+	ProviderUserID string
+	CreatedAt      time.Time
+}
+
+// UserDeletion is an account deletion requested by its owner. CompletedAt is set once the
+// account has been anonymized.
+type UserDeletion struct {
+	UserID      int64
+	RequestedAt time.Time
+	ScheduledAt time.Time
+	CompletedAt *time.Time
+}
+
+// ExportJob is an asynchronous user export. Filters holds the UserExportInput it was
+// requested with; ObjectKey is set once the file is in object storage.
+type ExportJob struct {
+	ID          int64
+	RequestedBy int64
+	Format      string
+	Filters     valueobject.JSONMap
+	Status      ExportJobStatus
+	RowsTotal   int64
+	RowsWritten int64
+	ObjectKey   string
+	Error       string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// VerificationReminder is an unverified user due a reminder to verify their email, with the
+// number of reminders already sent.
+type VerificationReminder struct {
+	UserID    int64
+	SentCount int32
+}
+
+// AnonymizeUser replaces the personal data of a user with placeholders.
+type AnonymizeUser struct {
+	ID       int64
+	Email    string
+	FullName string
+}
+
+// APIKey is a long-lived key a user authenticates with instead of a token.
+type APIKey struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	Prefix     string
+	Scopes     map[string][]string // object -> actions, held as Casbin policies of the key
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// APIKeyUser is an API key found by its hash together with the user it acts for.
+type APIKeyUser struct {
+	ID         int64
+	UserID     int64
+	UserEmail  string
+	UserStatus UserStatus
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// ServiceAccount is a non-human principal that obtains tokens with the client_credentials grant.
+type ServiceAccount struct {
+	ID         int64
+	Name       string
+	ClientID   string
+	Roles      []string
+	CreatedBy  int64
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// ServiceAccountCredential is what a service account is authenticated against.
+type ServiceAccountCredential struct {
+	ID       int64
+	ClientID string
+	Secret   string // hash of the client secret
 }
@@ -111,6 +111,32 @@ const (
 	ChallengePurposeMFASetupConfirm     ChallengePurpose = 2
 	ChallengePurposePasswordForgotReset ChallengePurpose = 3
 	ChallengePurposeRegisterVerify      ChallengePurpose = 4
+	ChallengePurposeMFARecoveryVerify   ChallengePurpose = 5  // emailed link that starts an MFA recovery
+	ChallengePurposeMFARecoveryPending  ChallengePurpose = 6  // verified recovery waiting out its delay
+	ChallengePurposeEmailChange         ChallengePurpose = 7  // link sent to the new address of an email change
+	ChallengePurposeMFASMSSetupConfirm  ChallengePurpose = 8  // code texted to the phone of a new SMS factor
+	ChallengePurposeUserInvite          ChallengePurpose = 9  // link emailed to a user invited by an administrator
+	ChallengePurposeMFAEnroll           ChallengePurpose = 10 // login held until a user required to use MFA enrolls a factor
+	ChallengePurposeOrgInvite           ChallengePurpose = 11 // link emailed to a user invited into an organization
+)
+
+var (
+	// ChallengePurposesOnPasswordChange are the emailed links that stop working once the
+	// password changes, whichever way it was changed.
+	ChallengePurposesOnPasswordChange = []ChallengePurpose{
+		ChallengePurposePasswordForgotReset,
+	}
+
+	// ChallengePurposesOnEmailChange are the emailed links that stop working once the address
+	// changes, since they went to an address the account no longer answers to.
+	ChallengePurposesOnEmailChange = []ChallengePurpose{
+		ChallengePurposePasswordForgotReset,
+		ChallengePurposeRegisterVerify,
+		ChallengePurposeMFARecoveryVerify,
+		ChallengePurposeEmailChange,
+		ChallengePurposeOrgInvite,
+		ChallengePurposeUserInvite,
+	}
 )
 
 type MFAType int16
@@ -147,3 +173,113 @@ func (mt MFAType) String() string {
 		return "Unknown"
 	}
 }
+
+// ExportJobStatus is the progress of an asynchronous user export.
+type ExportJobStatus int16
+
+const (
+	ExportJobStatusUnknown   ExportJobStatus = 0
+	ExportJobStatusPending   ExportJobStatus = 1 // queued, waiting for a worker
+	ExportJobStatusRunning   ExportJobStatus = 2
+	ExportJobStatusCompleted ExportJobStatus = 3 // the file is in object storage
+	ExportJobStatusFailed    ExportJobStatus = 4
+)
+
+func (es ExportJobStatus) String() string {
+	switch es {
+	case ExportJobStatusPending:
+		return "Pending"
+	case ExportJobStatusRunning:
+		return "Running"
+	case ExportJobStatusCompleted:
+		return "Completed"
+	case ExportJobStatusFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// LoginMethod is the step of a sign-in recorded in the login history.
+type LoginMethod string
+
+const (
+	LoginMethodPassword LoginMethod = "password"
+	LoginMethodOAuth    LoginMethod = "oauth"
+	LoginMethodSAML     LoginMethod = "saml"
+	LoginMethodMFA      LoginMethod = "mfa"
+)
+
+func (lm LoginMethod) String() string {
+	return string(lm)
+}
+
+type AuditAction string
+
+const (
+	AuditActionAuthLogin       AuditAction = "auth.login"
+	AuditActionAuthLoginFailed AuditAction = "auth.login.failed"
+	AuditActionAuthLogout      AuditAction = "auth.logout"
+	AuditActionAuthLogoutAll   AuditAction = "auth.logout.all"
+	AuditActionAuthReauth      AuditAction = "auth.reauth"
+	AuditActionPasswordChange  AuditAction = "password.change"
+	AuditActionPasswordReset   AuditAction = "password.reset"
+
+	AuditActionProfileExport        AuditAction = "profile.export"
+	AuditActionProfileDeleteRequest AuditAction = "profile.delete.request"
+	AuditActionProfileDeleteCancel  AuditAction = "profile.delete.cancel"
+	AuditActionProfileUsername      AuditAction = "profile.username.update"
+	AuditActionProfileAttributes    AuditAction = "profile.attributes.update"
+
+	AuditActionMFATOTPEnable   AuditAction = "mfa.totp.enable"
+	AuditActionMFASMSEnable    AuditAction = "mfa.sms.enable"
+	AuditActionMFABackupCodes  AuditAction = "mfa.backup_code.generate"
+	AuditActionMFAFactorRename AuditAction = "mfa.factor.rename"
+	AuditActionMFAFactorDelete AuditAction = "mfa.factor.delete"
+
+	AuditActionUserCreate       AuditAction = "user.create"
+	AuditActionUserInvite       AuditAction = "user.invite"
+	AuditActionUserInviteAccept AuditAction = "user.invite.accept"
+	AuditActionUserUpdate       AuditAction = "user.update"
+	AuditActionUserDelete       AuditAction = "user.delete"
+	AuditActionUserImport       AuditAction = "user.import"
+	AuditActionUserExport       AuditAction = "user.export"
+	AuditActionUserMFAInspect   AuditAction = "user.mfa.inspect"
+	AuditActionUserMFARevoke    AuditAction = "user.mfa.revoke"
+	AuditActionUserMFARecover   AuditAction = "user.mfa.recover"
+	AuditActionUserRoles        AuditAction = "user.roles.update"
+	AuditActionUserAnonymize    AuditAction = "user.anonymize"
+	AuditActionUserImpersonate  AuditAction = "user.impersonate"
+
+	AuditActionAPIKeyCreate AuditAction = "api_key.create"
+	AuditActionAPIKeyRevoke AuditAction = "api_key.revoke"
+
+	AuditActionIdentityLink   AuditAction = "identity.link"
+	AuditActionIdentityUnlink AuditAction = "identity.unlink"
+
+	AuditActionTrustedDeviceCreate AuditAction = "trusted_device.create"
+	AuditActionTrustedDeviceRevoke AuditAction = "trusted_device.revoke"
+
+	AuditActionServiceAccountCreate AuditAction = "service_account.create"
+	AuditActionServiceAccountDelete AuditAction = "service_account.delete"
+
+	AuditActionRoleCreate           AuditAction = "role.create"
+	AuditActionRoleUpdate           AuditAction = "role.update"
+	AuditActionRoleDelete           AuditAction = "role.delete"
+	AuditActionRolePermissionAdd    AuditAction = "role.permission.add"
+	AuditActionRolePermissionRemove AuditAction = "role.permission.remove"
+	AuditActionRoleMemberAdd        AuditAction = "role.member.add"
+	AuditActionRoleMemberRemove     AuditAction = "role.member.remove"
+
+	AuditActionOrgCreate       AuditAction = "org.create"
+	AuditActionOrgUpdate       AuditAction = "org.update"
+	AuditActionOrgDelete       AuditAction = "org.delete"
+	AuditActionOrgMemberInvite AuditAction = "org.member.invite"
+	AuditActionOrgMemberAdd    AuditAction = "org.member.add"
+	AuditActionOrgMemberUpdate AuditAction = "org.member.update"
+	AuditActionOrgMemberRemove AuditAction = "org.member.remove"
+)
+
+func (aa AuditAction) String() string {
+	return string(aa)
+}
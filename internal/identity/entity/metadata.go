@@ -0,0 +1,255 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+// MetadataVersionKey holds the schema version inside a stored metadata blob. Blobs written
+// before versioning have no version and are read as version 0.
+const MetadataVersionKey = "v"
+
+// MaxSessionUserAgentLength bounds the user agent kept on a session.
+const MaxSessionUserAgentLength = 512
+
+// MaxSessionDeviceNameLength bounds, in characters, the device name kept on a session.
+const MaxSessionDeviceNameLength = 100
+
+// ErrInvalidMetadata is returned when a metadata blob fails validation, on write or on read.
+var ErrInvalidMetadata = errors.New("entity: invalid metadata")
+
+// metadata is a typed shape of a JSONB metadata column. Writers and readers both go through
+// EncodeMetadata and DecodeMetadata, so a field cannot drift between the two.
+type metadata interface {
+	// version is the schema version the type writes.
+	version() int
+	// migrate upgrades a value decoded from an older version in place.
+	migrate(from int)
+	validate() error
+}
+
+// EncodeMetadata validates m and returns it as a JSON map stamped with its schema version.
+func EncodeMetadata(m metadata) (valueobject.JSONMap, error) {
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMetadata, err)
+	}
+
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var out valueobject.JSONMap
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	out[MetadataVersionKey] = m.version()
+
+	return out, nil
+}
+
+// DecodeMetadata reads src into T, upgrading blobs written by an older version, and validates
+// the result. Blobs from a newer version are read as far as this version understands them.
+func DecodeMetadata[T any, P interface {
+	*T
+	metadata
+}](src valueobject.JSONMap) (T, error) {
+	var out T
+
+	raw, err := json.Marshal(src)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return out, fmt.Errorf("%w: %w", ErrInvalidMetadata, err)
+	}
+
+	m := P(&out)
+	if from := src.GetInt(MetadataVersionKey); from < m.version() {
+		m.migrate(from)
+	}
+	if err := m.validate(); err != nil {
+		return out, fmt.Errorf("%w: %w", ErrInvalidMetadata, err)
+	}
+
+	return out, nil
+}
+
+// SessionMetadata describes the client a refresh token was issued to.
+type SessionMetadata struct {
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	// Client is the client type sent at sign-in, empty when none was given.
+	Client string `json:"client,omitempty"`
+	// DeviceName is the name the client gave its device, such as "Alice's iPhone", empty
+	// when none was given.
+	DeviceName string `json:"device_name,omitempty"`
+}
+
+func (*SessionMetadata) version() int { return 1 }
+
+func (*SessionMetadata) migrate(int) {}
+
+func (m *SessionMetadata) validate() error {
+	if m.IP != "" && net.ParseIP(m.IP) == nil {
+		return errors.New("ip is not an address")
+	}
+	if len(m.UserAgent) > MaxSessionUserAgentLength {
+		return errors.New("user_agent is too long")
+	}
+	if utf8.RuneCountInString(m.DeviceName) > MaxSessionDeviceNameLength {
+		return errors.New("device_name is too long")
+	}
+	return nil
+}
+
+// TOTPSetupMetadata is kept on a ChallengePurposeMFASetupConfirm challenge until the first
+// code confirms the authenticator app.
+type TOTPSetupMetadata struct {
+	// Secret is the base64 ciphertext of the TOTP seed.
+	Secret       string `json:"secret"`
+	FriendlyName string `json:"friendly_name"`
+	KeyVersion   int    `json:"key_version"`
+}
+
+func (*TOTPSetupMetadata) version() int { return 1 }
+
+func (m *TOTPSetupMetadata) migrate(int) {
+	if m.KeyVersion == 0 {
+		m.KeyVersion = 1
+	}
+}
+
+func (m *TOTPSetupMetadata) validate() error {
+	if m.Secret == "" {
+		return errors.New("secret is required")
+	}
+	if strings.TrimSpace(m.FriendlyName) == "" {
+		return errors.New("friendly_name is required")
+	}
+	if m.KeyVersion < 1 {
+		return errors.New("key_version must be positive")
+	}
+	return nil
+}
+
+// SMSSetupMetadata is kept on a ChallengePurposeMFASMSSetupConfirm challenge until the texted
+// code confirms the phone number.
+type SMSSetupMetadata struct {
+	// Phone is the base64 ciphertext of the phone number.
+	Phone string `json:"phone"`
+	// Code is the HMAC of the texted code.
+	Code string `json:"code"`
+	// FriendlyName is the masked phone number shown to the user.
+	FriendlyName string `json:"friendly_name"`
+	KeyVersion   int    `json:"key_version"`
+}
+
+func (*SMSSetupMetadata) version() int { return 1 }
+
+func (m *SMSSetupMetadata) migrate(int) {
+	if m.KeyVersion == 0 {
+		m.KeyVersion = 1
+	}
+}
+
+func (m *SMSSetupMetadata) validate() error {
+	if m.Phone == "" {
+		return errors.New("phone is required")
+	}
+	if m.Code == "" {
+		return errors.New("code is required")
+	}
+	if m.KeyVersion < 1 {
+		return errors.New("key_version must be positive")
+	}
+	return nil
+}
+
+// MFALoginMetadata is kept on a ChallengePurposeMFALogin challenge. The SMS fields are set
+// when an SMS code is texted for it.
+type MFALoginMetadata struct {
+	// PasswordResetRequired makes the challenge, once passed, end in a password reset
+	// instead of a session, as an administrator requires a new password.
+	PasswordResetRequired bool `json:"password_reset_required,omitempty"`
+	// SMSCode is the HMAC of the texted code.
+	SMSCode string `json:"sms_code,omitempty"`
+	// SMSExpiresAt is the Unix time, in seconds, the texted code stops working.
+	SMSExpiresAt int64 `json:"sms_expires_at,omitempty"`
+}
+
+func (*MFALoginMetadata) version() int { return 1 }
+
+func (*MFALoginMetadata) migrate(int) {}
+
+func (m *MFALoginMetadata) validate() error {
+	if (m.SMSCode == "") != (m.SMSExpiresAt == 0) {
+		return errors.New("sms_code and sms_expires_at go together")
+	}
+	return nil
+}
+
+// EmailChangeMetadata is kept on a ChallengePurposeEmailChange challenge until the link sent
+// to the new address is opened.
+type EmailChangeMetadata struct {
+	NewEmail string `json:"new_email"`
+}
+
+func (*EmailChangeMetadata) version() int { return 1 }
+
+func (*EmailChangeMetadata) migrate(int) {}
+
+func (m *EmailChangeMetadata) validate() error {
+	if !strings.Contains(m.NewEmail, "@") {
+		return errors.New("new_email is not an address")
+	}
+	return nil
+}
+
+// OrgInviteMetadata is kept on a ChallengePurposeOrgInvite challenge until the invited user
+// accepts, and names the organization and the role they join with.
+type OrgInviteMetadata struct {
+	OrgID     int64  `json:"org_id,string"`
+	Role      string `json:"role"`
+	InvitedBy int64  `json:"invited_by,string"`
+}
+
+func (*OrgInviteMetadata) version() int { return 1 }
+
+func (*OrgInviteMetadata) migrate(int) {}
+
+func (m *OrgInviteMetadata) validate() error {
+	if m.OrgID <= 0 {
+		return errors.New("org_id is required")
+	}
+	if _, ok := OrgRoleFromString(m.Role); !ok {
+		return errors.New("role is not an organization role")
+	}
+	return nil
+}
+
+// MFARecoveryMetadata is kept on a ChallengePurposeMFARecoveryPending challenge for the
+// waiting period of an MFA recovery.
+type MFARecoveryMetadata struct {
+	AvailableAt time.Time `json:"available_at"`
+	// Signals are the extra proofs given with the recovery, which shorten the wait.
+	Signals []string `json:"signals"`
+}
+
+func (*MFARecoveryMetadata) version() int { return 1 }
+
+func (*MFARecoveryMetadata) migrate(int) {}
+
+func (m *MFARecoveryMetadata) validate() error {
+	if m.AvailableAt.IsZero() {
+		return errors.New("available_at is required")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OrgRole is the role a member holds in one organization. It is stored as a Casbin g2 rule
+// (user, "org:<role>", organization id), so the same user can be an owner in one
+// organization and a plain member in another.
+type OrgRole string
+
+const (
+	OrgRoleOwner  OrgRole = "owner"
+	OrgRoleAdmin  OrgRole = "admin"
+	OrgRoleMember OrgRole = "member"
+)
+
+// orgRolePrefix keeps organization roles apart from global roles in the Casbin tables.
+const orgRolePrefix = "org:"
+
+// OrgRoleFromString parses a role name, reporting false for anything but the built-in roles.
+func OrgRoleFromString(s string) (OrgRole, bool) {
+	switch r := OrgRole(strings.ToLower(strings.TrimSpace(s))); r {
+	case OrgRoleOwner, OrgRoleAdmin, OrgRoleMember:
+		return r, true
+	default:
+		return "", false
+	}
+}
+
+// OrgRoleFromSubject parses the Casbin role subject written by Subject.
+func OrgRoleFromSubject(sub string) (OrgRole, bool) {
+	name, ok := strings.CutPrefix(sub, orgRolePrefix)
+	if !ok {
+		return "", false
+	}
+	return OrgRoleFromString(name)
+}
+
+func (r OrgRole) String() string {
+	return string(r)
+}
+
+// Subject is the Casbin role subject of r.
+func (r OrgRole) Subject() string {
+	return orgRolePrefix + string(r)
+}
+
+// OrgDomain is the Casbin domain of the organization orgID.
+func OrgDomain(orgID int64) string {
+	return strconv.FormatInt(orgID, 10)
+}
+
+// Organization is a tenant: users become members and act within it with an org-scoped role.
+type Organization struct {
+	ID        int64
+	Name      string
+	Slug      string
+	CreatedBy int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// Role is the caller's role in the organization, set when listing their organizations.
+	Role OrgRole
+}
+
+type OrgMember struct {
+	UserID   int64
+	Email    string
+	FullName string
+	Role     OrgRole
+	JoinedAt time.Time
+}
@@ -0,0 +1,74 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/shandysiswandi/gobite/internal/identity/usecase"
+	identityv1 "github.com/shandysiswandi/gobite/internal/pkg/pb/identity/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCService serves IdentityService, authenticated by the gRPC server's interceptors.
+type GRPCService struct {
+	identityv1.UnimplementedIdentityServiceServer
+
+	uc uc
+}
+
+func RegisterGRPCService(s grpc.ServiceRegistrar, uc uc) {
+	identityv1.RegisterIdentityServiceServer(s, &GRPCService{uc: uc})
+}
+
+func (g *GRPCService) GetProfile(ctx context.Context, _ *identityv1.GetProfileRequest) (*identityv1.GetProfileResponse, error) {
+	out, err := g.uc.Profile(ctx, usecase.ProfileInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &identityv1.GetProfileResponse{
+		Id:        out.ID,
+		Email:     out.Email,
+		FullName:  out.FullName,
+		Username:  out.Username,
+		AvatarUrl: out.AvatarURL,
+		Status:    out.Status,
+	}, nil
+}
+
+func (g *GRPCService) GetPermissions(ctx context.Context, _ *identityv1.GetPermissionsRequest) (*identityv1.GetPermissionsResponse, error) {
+	out, err := g.uc.ProfilePermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	perms := make(map[string]*identityv1.Actions, len(out))
+	for object, actions := range out {
+		perms[object] = &identityv1.Actions{Actions: actions}
+	}
+
+	return &identityv1.GetPermissionsResponse{Permissions: perms}, nil
+}
+
+func (g *GRPCService) ListSessions(ctx context.Context, _ *identityv1.ListSessionsRequest) (*identityv1.ListSessionsResponse, error) {
+	out, err := g.uc.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*identityv1.Session, 0, len(out.Sessions))
+	for _, session := range out.Sessions {
+		sessions = append(sessions, &identityv1.Session{
+			Id:         session.ID,
+			Ip:         session.IP,
+			UserAgent:  session.UserAgent,
+			Client:     session.Client,
+			DeviceName: session.DeviceName,
+			LastUsedAt: timestamppb.New(session.CreatedAt),
+			StartedAt:  timestamppb.New(session.SessionStartedAt),
+			ExpiresAt:  timestamppb.New(session.ExpiresAt),
+		})
+	}
+
+	return &identityv1.ListSessionsResponse{Sessions: sessions}, nil
+}
@@ -2,15 +2,30 @@ package inbound
 
 import (
 	"context"
+	"iter"
 
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/identity/usecase"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 	"github.com/shandysiswandi/gobite/internal/pkg/router"
 )
 
 type uc interface {
 	Login(ctx context.Context, in usecase.LoginInput) (*usecase.LoginOutput, error)
 	Login2FA(ctx context.Context, in usecase.Login2FAInput) (*usecase.Login2FAOutput, error)
+	Login2FASMS(ctx context.Context, in usecase.Login2FASMSInput) (*usecase.Login2FASMSOutput, error)
+	LoginMFASetup(ctx context.Context, in usecase.LoginMFASetupInput) (*usecase.LoginMFASetupOutput, error)
+	LoginMFASetupConfirm(ctx context.Context, in usecase.LoginMFASetupConfirmInput) (*usecase.LoginOutput, error)
+	OAuthAuthorize(ctx context.Context, in usecase.OAuthAuthorizeInput) (*usecase.OAuthAuthorizeOutput, error)
+	LoginOAuth(ctx context.Context, in usecase.LoginOAuthInput) (*usecase.LoginOutput, error)
+	SAMLMetadata(ctx context.Context) ([]byte, error)
+	SAMLAuthorize(ctx context.Context) (*usecase.SAMLAuthorizeOutput, error)
+	LoginSAML(ctx context.Context, in usecase.LoginSAMLInput) (*usecase.LoginOutput, error)
 	RefreshToken(ctx context.Context, in usecase.RefreshTokenInput) (*usecase.RefreshTokenOutput, error)
+	Reauthenticate(ctx context.Context, in usecase.ReauthenticateInput) (*usecase.ReauthenticateOutput, error)
+	ClientCredentialsToken(ctx context.Context, in usecase.ClientCredentialsTokenInput) (*usecase.ClientCredentialsTokenOutput, error)
+	Metadata(ctx context.Context) (*usecase.MetadataOutput, error)
+	JWKS(ctx context.Context) (*jwt.JWKSet, error)
 
 	Register(ctx context.Context, in usecase.RegisterInput) error
 	RegisterResend(ctx context.Context, in usecase.RegisterResendInput) error
@@ -19,67 +34,251 @@ type uc interface {
 	PasswordForgot(ctx context.Context, in usecase.PasswordForgotInput) error
 	PasswordReset(ctx context.Context, in usecase.PasswordResetInput) error
 	PasswordChange(ctx context.Context, in usecase.PasswordChangeInput) error
+	EmailChange(ctx context.Context, in usecase.EmailChangeInput) error
+	EmailChangeConfirm(ctx context.Context, in usecase.EmailChangeConfirmInput) error
 
 	Logout(ctx context.Context, in usecase.LogoutInput) error
 	LogoutAll(ctx context.Context, in usecase.LogoutAllInput) error
+	ListSessions(ctx context.Context) (*usecase.ListSessionsOutput, error)
+	RevokeSession(ctx context.Context, in usecase.RevokeSessionInput) error
+	ListTrustedDevices(ctx context.Context) (*usecase.ListTrustedDevicesOutput, error)
+	RevokeTrustedDevice(ctx context.Context, in usecase.RevokeTrustedDeviceInput) error
+	ListIdentities(ctx context.Context) (*usecase.ListIdentitiesOutput, error)
+	LinkIdentityAuthorize(ctx context.Context, in usecase.LinkIdentityAuthorizeInput) (*usecase.OAuthAuthorizeOutput, error)
+	LinkOAuth(ctx context.Context, in usecase.LinkOAuthInput) (*entity.UserConnection, error)
+	LinkSAML(ctx context.Context, in usecase.LinkSAMLInput) (*entity.UserConnection, error)
+	UnlinkIdentity(ctx context.Context, in usecase.UnlinkIdentityInput) error
+
+	APIKeyCreate(ctx context.Context, in usecase.APIKeyCreateInput) (*usecase.APIKeyCreateOutput, error)
+	APIKeyList(ctx context.Context) (*usecase.APIKeyListOutput, error)
+	APIKeyRevoke(ctx context.Context, in usecase.APIKeyRevokeInput) error
+	VerifyAPIKey(ctx context.Context, key string) (jwt.Claims, error)
 
 	Profile(ctx context.Context, in usecase.ProfileInput) (*usecase.ProfileOutput, error)
 	ProfileUpdate(ctx context.Context, in usecase.ProfileUpdateInput) error
 	ProfileUpdateAvatar(ctx context.Context, in usecase.ProfileUpdateAvatarInput) error
+	ProfileUpdateUsername(ctx context.Context, in usecase.ProfileUpdateUsernameInput) error
+	UsernameAvailability(ctx context.Context, in usecase.UsernameAvailabilityInput) (*usecase.UsernameAvailabilityOutput, error)
+	ProfileAttributes(ctx context.Context) (*usecase.ProfileAttributesOutput, error)
+	ProfileUpdateAttributes(ctx context.Context, in usecase.ProfileUpdateAttributesInput) (*usecase.ProfileAttributesOutput, error)
 	ProfilePermissions(ctx context.Context) (map[string][]string, error)
 	ProfileSettingMFA(ctx context.Context) (*usecase.ProfileSettingMFAOutput, error)
+	ProfileOnboarding(ctx context.Context) (*usecase.ProfileOnboardingOutput, error)
+	ProfileExport(ctx context.Context) (*usecase.ProfileExportOutput, error)
+	ProfileExportDownload(ctx context.Context, in usecase.ProfileExportDownloadInput) (*usecase.ProfileExportDownloadOutput, error)
+	ProfileDelete(ctx context.Context, in usecase.ProfileDeleteInput) (*usecase.ProfileDeleteOutput, error)
+	ProfileDeleteCancel(ctx context.Context) error
+	ProfileLogins(ctx context.Context, in usecase.ProfileLoginsInput) (*usecase.ProfileLoginsOutput, error)
+	ProfileSecurityScore(ctx context.Context) (*usecase.ProfileSecurityScoreOutput, error)
 
 	UserList(ctx context.Context, in usecase.UserListInput) (*usecase.UserListOutput, error)
 	UserDetail(ctx context.Context, in usecase.UserDetailInput) (*usecase.UserDetailOutput, error)
 	UserCreate(ctx context.Context, in usecase.UserCreateInput) error
+	UserInvite(ctx context.Context, in usecase.UserInviteInput) error
+	UserInviteAccept(ctx context.Context, in usecase.UserInviteAcceptInput) error
 	UserUpdate(ctx context.Context, in usecase.UserUpdateInput) error
 	UserDelete(ctx context.Context, in usecase.UserDeleteInput) error
 	UserExport(ctx context.Context, in usecase.UserExportInput) (*usecase.UserExportOutput, error)
+	UserExportStream(ctx context.Context, in usecase.UserExportInput) (iter.Seq2[entity.User, error], error)
+	UserExportJobCreate(ctx context.Context, in usecase.UserExportJobCreateInput) (*usecase.UserExportJobOutput, error)
+	UserExportJobGet(ctx context.Context, in usecase.UserExportJobGetInput) (*usecase.UserExportJobOutput, error)
 	UserImport(ctx context.Context, in usecase.UserImportInput) (*usecase.UserImportOutput, error)
+	UserImportFile(ctx context.Context, in usecase.UserImportFileInput) (*usecase.UserImportFileOutput, error)
+	UserMFA(ctx context.Context, in usecase.UserMFAInput) (*usecase.UserMFAOutput, error)
+	UserMFARevoke(ctx context.Context, in usecase.UserMFARevokeInput) error
+	UserImpersonate(ctx context.Context, in usecase.UserImpersonateInput) (*usecase.UserImpersonateOutput, error)
+	UserPermissions(ctx context.Context, in usecase.UserPermissionsInput) (*usecase.UserPermissionsOutput, error)
+	UserRolesUpdate(ctx context.Context, in usecase.UserRolesUpdateInput) (*usecase.UserRolesUpdateOutput, error)
+
+	RoleList(ctx context.Context) (*usecase.RoleListOutput, error)
+	RoleDetail(ctx context.Context, in usecase.RoleDetailInput) (*usecase.RoleDetailOutput, error)
+	RoleCreate(ctx context.Context, in usecase.RoleCreateInput) error
+	RoleUpdate(ctx context.Context, in usecase.RoleUpdateInput) error
+	RoleDelete(ctx context.Context, in usecase.RoleDeleteInput) error
+	RolePermissions(ctx context.Context, in usecase.RolePermissionsInput) (*usecase.RolePermissionsOutput, error)
+	RolePermissionAdd(ctx context.Context, in usecase.RolePermissionInput) error
+	RolePermissionRemove(ctx context.Context, in usecase.RolePermissionInput) error
+	RoleMemberAdd(ctx context.Context, in usecase.RoleMemberInput) error
+	RoleMemberRemove(ctx context.Context, in usecase.RoleMemberInput) error
+
+	ServiceAccountCreate(ctx context.Context, in usecase.ServiceAccountCreateInput) (*usecase.ServiceAccountCreateOutput, error)
+	ServiceAccountList(ctx context.Context) (*usecase.ServiceAccountListOutput, error)
+	ServiceAccountDelete(ctx context.Context, in usecase.ServiceAccountDeleteInput) error
+
+	OrgCreate(ctx context.Context, in usecase.OrgCreateInput) (*entity.Organization, error)
+	OrgList(ctx context.Context) (*usecase.OrgListOutput, error)
+	OrgDetail(ctx context.Context, in usecase.OrgIDInput) (*entity.Organization, error)
+	OrgUpdate(ctx context.Context, in usecase.OrgUpdateInput) error
+	OrgDelete(ctx context.Context, in usecase.OrgIDInput) error
+	OrgToken(ctx context.Context, in usecase.OrgTokenInput) (*usecase.OrgTokenOutput, error)
+	OrgMemberList(ctx context.Context, in usecase.OrgIDInput) (*usecase.OrgMemberListOutput, error)
+	OrgMemberAdd(ctx context.Context, in usecase.OrgMemberAddInput) error
+	OrgInviteAccept(ctx context.Context, in usecase.OrgInviteAcceptInput) (*entity.Organization, error)
+	OrgMemberUpdate(ctx context.Context, in usecase.OrgMemberUpdateInput) error
+	OrgMemberRemove(ctx context.Context, in usecase.OrgMemberRemoveInput) error
+
+	SCIMUserList(ctx context.Context, in usecase.SCIMUserListInput) (*usecase.SCIMUserListOutput, error)
+	SCIMUserGet(ctx context.Context, in usecase.SCIMUserIDInput) (*entity.User, error)
+	SCIMUserCreate(ctx context.Context, in usecase.SCIMUserCreateInput) (*entity.User, error)
+	SCIMUserUpdate(ctx context.Context, in usecase.SCIMUserUpdateInput) (*entity.User, error)
+	SCIMUserDelete(ctx context.Context, in usecase.SCIMUserIDInput) error
+	SCIMGroupList(ctx context.Context, in usecase.SCIMGroupListInput) (*usecase.SCIMGroupListOutput, error)
+	SCIMGroupGet(ctx context.Context, in usecase.SCIMGroupIDInput) (*usecase.SCIMGroup, error)
+	SCIMGroupCreate(ctx context.Context, in usecase.SCIMGroupCreateInput) (*usecase.SCIMGroup, error)
+	SCIMGroupUpdate(ctx context.Context, in usecase.SCIMGroupUpdateInput) (*usecase.SCIMGroup, error)
+	SCIMGroupDelete(ctx context.Context, in usecase.SCIMGroupIDInput) error
 
 	TOTPSetup(ctx context.Context, in usecase.TOTPSetupInput) (*usecase.TOTPSetupOutput, error)
 	TOTPConfirm(ctx context.Context, in usecase.TOTPConfirmInput) error
+	SMSSetup(ctx context.Context, in usecase.SMSSetupInput) (*usecase.SMSSetupOutput, error)
+	SMSConfirm(ctx context.Context, in usecase.SMSConfirmInput) error
 	BackupCode(ctx context.Context, in usecase.BackupCodeInput) (*usecase.BackupCodeOutput, error)
+	ListMFAFactors(ctx context.Context) (*usecase.ListMFAFactorsOutput, error)
+	RenameMFAFactor(ctx context.Context, in usecase.RenameMFAFactorInput) error
+	DeleteMFAFactor(ctx context.Context, in usecase.DeleteMFAFactorInput) error
+
+	MFARecoveryRequest(ctx context.Context, in usecase.MFARecoveryRequestInput) error
+	MFARecoveryVerify(ctx context.Context, in usecase.MFARecoveryVerifyInput) (*usecase.MFARecoveryVerifyOutput, error)
+	MFARecoveryComplete(ctx context.Context, in usecase.MFARecoveryCompleteInput) error
 }
 
-func RegisterHTTPEndpoint(r *router.Router, uc uc) {
-	end := &HTTPEndpoint{uc: uc}
+func RegisterHTTPEndpoint(r *router.Router, uc uc, refreshCookie RefreshCookieConfig, flowCookie FlowCookieConfig, exportLimit, importLimit router.Middleware) {
+	end := &HTTPEndpoint{uc: uc, refreshCookie: refreshCookie, flowCookie: flowCookie}
+
+	r.UseAPIKey(uc)
 
 	// Auth & User Management
 	r.POST("/api/v1/identity/login", end.Login)
 	r.POST("/api/v1/identity/login/2fa", end.Login2FA)
+	r.POST("/api/v1/identity/login/2fa/sms", end.Login2FASMS)
+	r.POST("/api/v1/identity/login/mfa-setup", end.LoginMFASetup)
+	r.POST("/api/v1/identity/login/mfa-setup/confirm", end.LoginMFASetupConfirm)
 	r.POST("/api/v1/identity/refresh", end.RefreshToken)
+	r.POST("/api/v1/identity/reauth", end.Reauth, router.PersonalOnly) // need authenticated
+	r.POST("/api/v1/identity/token", end.ClientCredentialsToken)
+	r.GET("/.well-known/gobite-configuration", end.Metadata)
+	r.GET(usecase.JWKSPath, end.JWKS)
+	//
+	r.GET("/api/v1/identity/oauth/:provider/authorize", end.OAuthAuthorize)
+	r.POST("/api/v1/identity/oauth/:provider/callback", end.OAuthCallback)
+	//
+	r.GET("/api/v1/identity/saml/metadata", end.SAMLMetadata)
+	r.GET("/api/v1/identity/saml/login", end.SAMLLogin)
+	r.POST("/api/v1/identity/saml/acs", end.SAMLACS)
 	//
 	r.POST("/api/v1/identity/register", end.Register)
 	r.POST("/api/v1/identity/register/resend", end.RegisterResend)
 	r.POST("/api/v1/identity/register/verify", end.RegisterVerify)
 	//
-	r.POST("/api/v1/identity/logout", end.Logout)
-	r.POST("/api/v1/identity/logout-all", end.LogoutAll) // need authenticated
+	r.POST("/api/v1/identity/logout", end.Logout, router.PersonalOnly)
+	r.POST("/api/v1/identity/logout-all", end.LogoutAll, router.PersonalOnly)         // need authenticated
+	r.GET("/api/v1/identity/sessions", end.ListSessions, router.PersonalOnly)         // need authenticated
+	r.DELETE("/api/v1/identity/sessions/:id", end.RevokeSession, router.PersonalOnly) // need authenticated
+
+	r.GET("/api/v1/identity/trusted-devices", end.ListTrustedDevices, router.PersonalOnly)         // need authenticated
+	r.DELETE("/api/v1/identity/trusted-devices/:id", end.RevokeTrustedDevice, router.PersonalOnly) // need authenticated
+
+	r.GET("/api/v1/identity/profile/identities", end.ListIdentities, router.PersonalOnly)                           // need authenticated
+	r.POST("/api/v1/identity/profile/identities/:provider/link", end.LinkIdentity, router.PersonalOnly)             // need authenticated
+	r.POST("/api/v1/identity/profile/identities/:provider/callback", end.LinkIdentityCallback, router.PersonalOnly) // need authenticated
+	r.DELETE("/api/v1/identity/profile/identities/:id", end.UnlinkIdentity, router.PersonalOnly)                    // need authenticated
+
+	// API Keys (need authenticated with a token)
+	r.POST("/api/v1/identity/api-keys", end.APIKeyCreate, router.PersonalOnly)
+	r.GET("/api/v1/identity/api-keys", end.APIKeyList, router.PersonalOnly)
+	r.DELETE("/api/v1/identity/api-keys/:id", end.APIKeyRevoke, router.PersonalOnly)
 
 	// Password Management
 	r.POST("/api/v1/identity/password/forgot", end.PasswordForgot)
 	r.POST("/api/v1/identity/password/reset", end.PasswordReset)
-	r.POST("/api/v1/identity/password/change", end.PasswordChange) // need authenticated
+	r.POST("/api/v1/identity/password/change", end.PasswordChange, router.PersonalOnly) // need authenticated
+
+	// Email Management
+	r.POST("/api/v1/identity/email/change", end.EmailChange, router.PersonalOnly) // need authenticated
+	r.POST("/api/v1/identity/email/change/confirm", end.EmailChangeConfirm)
+
+	// Invitation (public, the emailed token authenticates)
+	r.POST("/api/v1/identity/invite/accept", end.UserInviteAccept)
+
+	// MFA (TOTP, SMS)
+	r.POST("/api/v1/identity/mfa/totp/setup", end.TOTPSetup, router.PersonalOnly)     // need authenticated
+	r.POST("/api/v1/identity/mfa/totp/confirm", end.TOTPConfirm, router.PersonalOnly) // need authenticated
+	r.POST("/api/v1/identity/mfa/sms/setup", end.SMSSetup, router.PersonalOnly)       // need authenticated
+	r.POST("/api/v1/identity/mfa/sms/confirm", end.SMSConfirm, router.PersonalOnly)   // need authenticated
+	r.POST("/api/v1/identity/mfa/backup-code", end.BackupCode, router.PersonalOnly)   // need authenticated
 
-	// MFA (TOTP)
-	r.POST("/api/v1/identity/mfa/totp/setup", end.TOTPSetup)     // need authenticated
-	r.POST("/api/v1/identity/mfa/totp/confirm", end.TOTPConfirm) // need authenticated
-	r.POST("/api/v1/identity/mfa/backup-code", end.BackupCode)   // need authenticated
+	r.GET("/api/v1/identity/mfa/factors", end.ListMFAFactors, router.PersonalOnly)         // need authenticated
+	r.PATCH("/api/v1/identity/mfa/factors/:id", end.RenameMFAFactor, router.PersonalOnly)  // need authenticated
+	r.DELETE("/api/v1/identity/mfa/factors/:id", end.DeleteMFAFactor, router.PersonalOnly) // need authenticated
+
+	// MFA Recovery (lost second factor)
+	r.POST("/api/v1/identity/mfa/recovery", end.MFARecoveryRequest)
+	r.POST("/api/v1/identity/mfa/recovery/verify", end.MFARecoveryVerify)
+	r.POST("/api/v1/identity/mfa/recovery/complete", end.MFARecoveryComplete)
 
 	// User Profile (need authenticated)
-	r.GET("/api/v1/identity/profile", end.Profile)
-	r.PUT("/api/v1/identity/profile", end.ProfileUpdate)
-	r.PUT("/api/v1/identity/profile/avatar", end.ProfileUpdateAvatar)
-	r.GET("/api/v1/identity/profile/permissions", end.ProfilePermissions)
-	r.GET("/api/v1/identity/profile/settings/mfa", end.ProfileSettingMFA)
+	r.GET("/api/v1/identity/profile", end.Profile, router.PersonalOnly)
+	r.PUT("/api/v1/identity/profile", end.ProfileUpdate, router.PersonalOnly)
+	r.PUT("/api/v1/identity/profile/avatar", end.ProfileUpdateAvatar, router.PersonalOnly)
+	r.PUT("/api/v1/identity/profile/username", end.ProfileUpdateUsername, router.PersonalOnly)
+	r.GET("/api/v1/identity/profile/username/availability", end.UsernameAvailability, router.PersonalOnly)
+	r.GET("/api/v1/identity/profile/attributes", end.ProfileAttributes, router.PersonalOnly)
+	r.PATCH("/api/v1/identity/profile/attributes", end.ProfileUpdateAttributes, router.PersonalOnly)
+	r.GET("/api/v1/identity/profile/permissions", end.ProfilePermissions, router.PersonalOnly)
+	r.GET("/api/v1/identity/profile/settings/mfa", end.ProfileSettingMFA, router.PersonalOnly)
+	r.GET("/api/v1/identity/profile/onboarding", end.ProfileOnboarding, router.PersonalOnly)
+	r.POST("/api/v1/identity/profile/export", end.ProfileExport, router.PersonalOnly)
+	r.GET("/api/v1/identity/profile/export/:id", end.ProfileExportDownload, router.PersonalOnly)
+	r.POST("/api/v1/identity/profile/delete", end.ProfileDelete, router.PersonalOnly)
+	r.POST("/api/v1/identity/profile/delete/cancel", end.ProfileDeleteCancel, router.PersonalOnly)
+	r.GET("/api/v1/identity/profile/logins", end.ProfileLogins, router.PersonalOnly)
+	r.GET("/api/v1/identity/profile/security/score", end.ProfileSecurityScore, router.PersonalOnly)
 
 	// User Directory (need authenticated & authorization)
 	r.GET("/api/v1/identity/users", end.UserList)
 	r.GET("/api/v1/identity/users/:id", end.UserDetail)
 	r.POST("/api/v1/identity/users", end.UserCreate)
+	r.POST("/api/v1/identity/users/invite", end.UserInvite)
 	r.PUT("/api/v1/identity/users/:id", end.UserUpdate)
 	r.DELETE("/api/v1/identity/users/:id", end.UserDelete)
-	r.GET("/api/v1/identity/users-export", end.UserExport)
-	r.POST("/api/v1/identity/users-import", end.UserImport)
+	r.GET("/api/v1/identity/users/:id/mfa", end.UserMFA)
+	r.DELETE("/api/v1/identity/users/:id/mfa", end.UserMFARevoke)
+	r.POST("/api/v1/identity/users/:id/impersonate", end.UserImpersonate)
+	r.GET("/api/v1/identity/users/:id/permissions", end.UserPermissions)
+	r.PUT("/api/v1/identity/users/:id/roles", end.UserRolesUpdate)
+	r.GET("/api/v1/identity/users-export", end.UserExport, exportLimit)
+	r.POST("/api/v1/identity/users-import", end.UserImport, importLimit)
+	r.POST("/api/v1/identity/users-import/file", end.UserImportFile, importLimit)
+	r.POST("/api/v1/identity/exports", end.UserExportJobCreate)
+	r.GET("/api/v1/identity/exports/:id", end.UserExportJobGet)
+
+	// Role Management (need authenticated & authorization)
+	r.GET("/api/v1/identity/roles", end.RoleList)
+	r.POST("/api/v1/identity/roles", end.RoleCreate)
+	r.GET("/api/v1/identity/roles/:role", end.RoleDetail)
+	r.PUT("/api/v1/identity/roles/:role", end.RoleUpdate)
+	r.DELETE("/api/v1/identity/roles/:role", end.RoleDelete)
+	r.GET("/api/v1/identity/roles/:role/permissions", end.RolePermissions)
+	r.POST("/api/v1/identity/roles/:role/permissions", end.RolePermissionAdd)
+	r.DELETE("/api/v1/identity/roles/:role/permissions", end.RolePermissionRemove)
+	r.POST("/api/v1/identity/roles/:role/users", end.RoleMemberAdd)
+	r.DELETE("/api/v1/identity/roles/:role/users/:user_id", end.RoleMemberRemove)
+
+	// Service Accounts (need authenticated & authorization)
+	r.GET("/api/v1/identity/service-accounts", end.ServiceAccountList)
+	r.POST("/api/v1/identity/service-accounts", end.ServiceAccountCreate)
+	r.DELETE("/api/v1/identity/service-accounts/:id", end.ServiceAccountDelete)
+
+	// Organizations (need authenticated; access goes by the role held in each organization)
+	r.GET("/api/v1/identity/orgs", end.OrgList)
+	r.POST("/api/v1/identity/orgs", end.OrgCreate)
+	r.GET("/api/v1/identity/orgs/:id", end.OrgDetail)
+	r.PUT("/api/v1/identity/orgs/:id", end.OrgUpdate)
+	r.DELETE("/api/v1/identity/orgs/:id", end.OrgDelete)
+	r.POST("/api/v1/identity/orgs/:id/token", end.OrgToken)
+	r.GET("/api/v1/identity/orgs/:id/members", end.OrgMemberList)
+	r.POST("/api/v1/identity/orgs/:id/members", end.OrgMemberAdd)
+	r.PUT("/api/v1/identity/orgs/:id/members/:user_id", end.OrgMemberUpdate)
+	r.DELETE("/api/v1/identity/orgs/:id/members/:user_id", end.OrgMemberRemove)
+	r.POST("/api/v1/identity/org-invitations/accept", end.OrgInviteAccept, router.PersonalOnly)
 }
@@ -0,0 +1,174 @@
+package inbound
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/router"
+)
+
+// headerCSRFToken carries the CSRF token a refresh or logout authenticated by the refresh
+// cookie must send.
+const headerCSRFToken = "X-CSRF-Token"
+
+// RefreshCookieConfig makes browser clients keep their refresh token in an HttpOnly cookie
+// instead of the JSON body, out of reach of scripts. Because the browser sends the cookie on
+// its own, a refresh or logout that relies on it must also send the CSRF token in the
+// X-CSRF-Token header, matching the CSRF cookie (double submit); a cross-site page can
+// neither read the token nor set the header.
+type RefreshCookieConfig struct {
+	// Enabled turns cookie mode on.
+	Enabled bool
+	// ClientTypes limits cookie mode to these X-Client-Type values; empty means every client.
+	ClientTypes []string
+	// Name is the name of the HttpOnly refresh token cookie.
+	Name string
+	// CSRFName is the name of the CSRF cookie, readable by scripts.
+	CSRFName string
+	// Domain and Path scope the refresh token cookie; the CSRF cookie is scoped to Domain and "/"
+	// so the pages of the site can read it.
+	Domain string
+	Path   string
+	// Secure restricts both cookies to HTTPS.
+	Secure bool
+	// SameSite is the SameSite attribute of both cookies.
+	SameSite http.SameSite
+	// MaxAge is how long the browser keeps the cookies; the refresh token itself may expire sooner.
+	MaxAge time.Duration
+}
+
+func (c RefreshCookieConfig) appliesTo(clientType string) bool {
+	if !c.Enabled {
+		return false
+	}
+	if len(c.ClientTypes) == 0 {
+		return true
+	}
+
+	return slices.Contains(c.ClientTypes, strings.ToLower(strings.TrimSpace(clientType)))
+}
+
+func (c RefreshCookieConfig) cookies(refreshToken, csrfToken string, maxAge int) []*http.Cookie {
+	return []*http.Cookie{
+		{
+			Name:     c.Name,
+			Value:    refreshToken,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			MaxAge:   maxAge,
+			Secure:   c.Secure,
+			HttpOnly: true,
+			SameSite: c.SameSite,
+		},
+		{
+			Name:     c.CSRFName,
+			Value:    csrfToken,
+			Domain:   c.Domain,
+			Path:     "/",
+			MaxAge:   maxAge,
+			Secure:   c.Secure,
+			SameSite: c.SameSite,
+		},
+	}
+}
+
+// tokenResponse returns out, a pointer to a token response, as it is, or with the refresh
+// token moved into the refresh cookie when the client uses cookie mode or sent the refresh
+// token as a cookie. In that case a new CSRF token is set in its cookie and in csrfToken.
+func (h *HTTPEndpoint) tokenResponse(r *router.Request, viaCookie bool, out any, refreshToken, csrfToken *string) any {
+	if *refreshToken == "" || (!viaCookie && !h.refreshCookie.appliesTo(r.Header.Get(headerClientType))) {
+		return out
+	}
+
+	csrf := rand.Text()
+	cookies := h.refreshCookie.cookies(*refreshToken, csrf, int(h.refreshCookie.MaxAge.Seconds()))
+	*refreshToken = ""
+	*csrfToken = csrf
+
+	return &router.WithCookies{Data: out, Cookies: cookies}
+}
+
+// cookieRefreshToken returns the refresh token of the refresh cookie, or "" when cookie mode is
+// off or the cookie is absent. The CSRF header must match the CSRF cookie.
+func (h *HTTPEndpoint) cookieRefreshToken(r *router.Request) (string, error) {
+	if !h.refreshCookie.Enabled {
+		return "", nil
+	}
+
+	token, err := r.Cookie(h.refreshCookie.Name)
+	if err != nil || token.Value == "" {
+		return "", nil
+	}
+
+	csrf, err := r.Cookie(h.refreshCookie.CSRFName)
+	header := r.Header.Get(headerCSRFToken)
+	if err != nil || csrf.Value == "" || subtle.ConstantTimeCompare([]byte(header), []byte(csrf.Value)) != 1 {
+		return "", goerror.NewBusiness("invalid CSRF token", goerror.CodeForbidden)
+	}
+
+	return token.Value, nil
+}
+
+// clearRefreshCookies expires both cookies.
+func (h *HTTPEndpoint) clearRefreshCookies() []*http.Cookie {
+	return h.refreshCookie.cookies("", "", -1)
+}
+
+// FlowCookieConfig is the HttpOnly cookie that binds an OAuth or SAML flow to the browser
+// that started it: the callback is only accepted from a browser holding the cookie set by
+// the authorize endpoint, so a state or code lured into another browser is worthless.
+type FlowCookieConfig struct {
+	// Name is the name of the cookie.
+	Name string
+	// Domain and Path scope the cookie; Path must cover the callbacks.
+	Domain string
+	Path   string
+	// Secure restricts the cookie to HTTPS.
+	Secure bool
+	// SameSite is the SameSite attribute of the cookie. The SAML assertion consumer service
+	// is reached by a cross-site form post, which only carries a "none" cookie.
+	SameSite http.SameSite
+}
+
+func (c FlowCookieConfig) cookie(binding string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     c.Name,
+		Value:    binding,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		MaxAge:   maxAge,
+		Secure:   c.Secure,
+		HttpOnly: true,
+		SameSite: c.SameSite,
+	}
+}
+
+// flowBinding returns the binding of the flow cookie, or "".
+func (h *HTTPEndpoint) flowBinding(r *router.Request) string {
+	c, err := r.Cookie(h.flowCookie.Name)
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
+
+// withFlowCookie returns resp, the result of an authorize endpoint, setting the flow cookie.
+func (h *HTTPEndpoint) withFlowCookie(resp any, binding string, ttl time.Duration) any {
+	return &router.WithCookies{Data: resp, Cookies: []*http.Cookie{h.flowCookie.cookie(binding, int(ttl.Seconds()))}}
+}
+
+// clearFlowCookie returns resp, the result of a callback, expiring the flow cookie, so a flow
+// is completed once per browser. resp may already set cookies.
+func (h *HTTPEndpoint) clearFlowCookie(resp any) any {
+	expired := h.flowCookie.cookie("", -1)
+	if wc, ok := resp.(*router.WithCookies); ok {
+		wc.Cookies = append(wc.Cookies, expired)
+		return wc
+	}
+	return &router.WithCookies{Data: resp, Cookies: []*http.Cookie{expired}}
+}
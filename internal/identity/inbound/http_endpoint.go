@@ -2,34 +2,56 @@ package inbound
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/identity/usecase"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/router"
+	"github.com/shandysiswandi/gobite/internal/pkg/signedurl"
+	"github.com/shandysiswandi/gobite/internal/pkg/tabular"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 )
 
+// headerClientType names the kind of client signing in (e.g. web, mobile, service). It selects
+// the token lifetimes configured under modules.identity.token_ttl.
+const headerClientType = "X-Client-Type"
+
+// headerDeviceName carries the name the client gives its device, such as "Alice's iPhone". It
+// is kept on the session and shown in the session list and new sign-in alerts.
+const headerDeviceName = "X-Device-Name"
+
 // HTTPEndpoint exposes HTTP handlers for authentication and profile workflows.
 type HTTPEndpoint struct {
-	uc uc
+	uc            uc
+	refreshCookie RefreshCookieConfig
+	flowCookie    FlowCookieConfig
 }
 
 // Login authenticates a user and returns tokens or an MFA challenge.
 // @Summary Authenticate user
-// @Description Validates credentials and returns access/refresh tokens. If MFA is required, a challenge is returned.
+// @Description Validates credentials and returns access/refresh tokens. If MFA is required, a challenge is returned unless device_token is an active trusted device of the user. When an administrator requires a new password, password_reset_required is set and challenge_token is a password reset token; when an administrator requires MFA and the user has none, mfa_setup_required is set and challenge_token starts the login MFA setup.
 // @Tags Identity, Authentication
 // @Accept json
 // @Produce json
 // @Param request body LoginRequest true "Login payload"
+// @Param X-Client-Type header string false "Client type used to pick token lifetimes (e.g. web, mobile, service)"
+// @Param X-Device-Name header string false "Name of the device signing in, shown in the session list and new sign-in alerts"
+// @Param X-Captcha-Token header string false "CAPTCHA response token, required when the endpoint is listed in the captcha config"
 // @Success 200 {object} router.successResponse{data=LoginResponse} "Authentication result"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Invalid credentials"
+// @Failure 403 {object} router.errorResponse "CAPTCHA verification failed"
 // @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 429 {object} router.errorResponse "Too many attempts, see reason and Retry-After"
+// @Header 429 {integer} Retry-After "Seconds to wait before retrying"
 // @Failure 500 {object} router.errorResponse "Internal server error" example:{"message":"Login failed due to server error","error":{"detail":"Please try again later"}}
 // @Router /api/v1/identity/login [post]
 func (h *HTTPEndpoint) Login(r *router.Request) (any, error) {
@@ -39,29 +61,228 @@ func (h *HTTPEndpoint) Login(r *router.Request) (any, error) {
 	}
 
 	resp, err := h.uc.Login(r.Context(), usecase.LoginInput{
-		Email:    req.Email,
-		Password: req.Password,
+		Email:        req.Email,
+		Username:     req.Username,
+		Password:     req.Password,
+		IP:           r.RemoteAddr,
+		UserAgent:    r.UserAgent(),
+		ClientType:   r.Header.Get(headerClientType),
+		DeviceName:   r.Header.Get(headerDeviceName),
+		CaptchaToken: r.CaptchaToken(req.CaptchaToken),
+		DeviceToken:  req.DeviceToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &LoginResponse{
+		AccessToken:      resp.AccessToken,
+		RefreshToken:     resp.RefreshToken,
+		MfaRequired:      resp.MfaRequired,
+		ChallengeToken:   resp.ChallengeToken,
+		AvailableMethods: resp.AvailableMethods,
+
+		PasswordResetRequired: resp.PasswordResetRequired,
+		MfaSetupRequired:      resp.MfaSetupRequired,
+	}
+
+	return h.tokenResponse(r, false, out, &out.RefreshToken, &out.CSRFToken), nil
+}
+
+// OAuthAuthorize starts a sign-in with an external identity provider.
+// @Summary Start OAuth sign-in
+// @Description Returns the provider consent page URL and sets the HttpOnly flow cookie, which binds the sign-in to this browser. The browser is sent to the URL and the provider redirects back to the app's redirect_url with a code and state, which the app posts to the callback endpoint.
+// @Tags Identity, Authentication
+// @Produce json
+// @Param provider path string true "Provider name" Enums(google, github, oidc)
+// @Success 200 {object} router.successResponse{data=OAuthAuthorizeResponse} "Authorization URL"
+// @Failure 404 {object} router.errorResponse "Provider not supported"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/oauth/{provider}/authorize [get]
+func (h *HTTPEndpoint) OAuthAuthorize(r *router.Request) (any, error) {
+	resp, err := h.uc.OAuthAuthorize(r.Context(), usecase.OAuthAuthorizeInput{
+		Provider: r.GetParam("provider"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return h.withFlowCookie(OAuthAuthorizeResponse{
+		AuthorizationURL: resp.AuthorizationURL,
+		State:            resp.State,
+	}, resp.Binding, resp.BindingTTL), nil
+}
+
+// OAuthCallback completes a sign-in with an external identity provider.
+// @Summary Complete OAuth sign-in
+// @Description Exchanges the provider code for the user's identity, links or provisions the account on first login, and returns tokens or an MFA challenge. The request must carry the flow cookie set by the authorize endpoint in the same browser. A state from the link identity endpoint is refused here; it goes to the link callback.
+// @Tags Identity, Authentication
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name" Enums(google, github, oidc)
+// @Param request body OAuthCallbackRequest true "Code and state the provider redirected with"
+// @Param X-Client-Type header string false "Client type used to pick token lifetimes (e.g. web, mobile, service)"
+// @Param X-Device-Name header string false "Name of the device signing in, shown in the session list and new sign-in alerts"
+// @Success 200 {object} router.successResponse{data=LoginResponse} "Authentication result"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Invalid state, missing flow cookie or rejected code"
+// @Failure 403 {object} router.errorResponse "No verified email or account not allowed"
+// @Failure 404 {object} router.errorResponse "Provider not supported"
+// @Failure 409 {object} router.errorResponse "Account exists and linking by email is disabled"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/oauth/{provider}/callback [post]
+func (h *HTTPEndpoint) OAuthCallback(r *router.Request) (any, error) {
+	var req OAuthCallbackRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	if req.Error != "" {
+		return nil, goerror.NewBusiness("oauth sign-in was not completed: "+req.Error, goerror.CodeUnauthorized)
+	}
+
+	resp, err := h.uc.LoginOAuth(r.Context(), usecase.LoginOAuthInput{
+		Provider:   r.GetParam("provider"),
+		Code:       req.Code,
+		State:      req.State,
+		Binding:    h.flowBinding(r),
+		IP:         r.RemoteAddr,
+		UserAgent:  r.UserAgent(),
+		ClientType: r.Header.Get(headerClientType),
+		DeviceName: r.Header.Get(headerDeviceName),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return LoginResponse{
+	out := &LoginResponse{
 		AccessToken:      resp.AccessToken,
 		RefreshToken:     resp.RefreshToken,
 		MfaRequired:      resp.MfaRequired,
 		ChallengeToken:   resp.ChallengeToken,
 		AvailableMethods: resp.AvailableMethods,
+
+		PasswordResetRequired: resp.PasswordResetRequired,
+		MfaSetupRequired:      resp.MfaSetupRequired,
+	}
+
+	return h.clearFlowCookie(h.tokenResponse(r, false, out, &out.RefreshToken, &out.CSRFToken)), nil
+}
+
+// SAMLMetadata serves the SAML service provider metadata.
+// @Summary SAML service provider metadata
+// @Description Returns the metadata XML to register this service with the SAML identity provider.
+// @Tags Identity, Authentication
+// @Produce xml
+// @Success 200 {string} string "Service provider metadata"
+// @Failure 404 {object} router.errorResponse "SAML is not configured"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/saml/metadata [get]
+func (h *HTTPEndpoint) SAMLMetadata(r *router.Request) (any, error) {
+	metadata, err := h.uc.SAMLMetadata(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return &router.File{
+		ContentType: "application/samlmetadata+xml",
+		Write: func(w io.Writer) error {
+			_, err := w.Write(metadata)
+			return err
+		},
 	}, nil
 }
 
+// SAMLLogin starts an SP-initiated sign-in with the SAML identity provider.
+// @Summary Start SAML sign-in
+// @Description Returns the identity provider sign-in URL carrying an AuthnRequest and sets the flow cookie binding the sign-in to this browser. The identity provider posts its response to the assertion consumer service with the relay state, and the browser must send the flow cookie along.
+// @Tags Identity, Authentication
+// @Produce json
+// @Success 200 {object} router.successResponse{data=SAMLLoginResponse} "Sign-in URL"
+// @Failure 404 {object} router.errorResponse "SAML is not configured"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/saml/login [get]
+func (h *HTTPEndpoint) SAMLLogin(r *router.Request) (any, error) {
+	resp, err := h.uc.SAMLAuthorize(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return h.withFlowCookie(SAMLLoginResponse{
+		AuthorizationURL: resp.AuthorizationURL,
+		RelayState:       resp.State,
+	}, resp.Binding, resp.BindingTTL), nil
+}
+
+// SAMLACS is the assertion consumer service the SAML identity provider posts to.
+// @Summary Complete SAML sign-in
+// @Description Verifies the SAML response, links or provisions the account on first login, applies the configured role rules, and returns tokens or an MFA challenge. With a relay state from the link identity endpoint, the identity is linked to that user instead and returned.
+// @Tags Identity, Authentication
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param SAMLResponse formData string true "Base64 SAML response"
+// @Param RelayState formData string true "Relay state returned by the login endpoint"
+// @Param X-Client-Type header string false "Client type used to pick token lifetimes (e.g. web, mobile, service)"
+// @Param X-Device-Name header string false "Name of the device signing in, shown in the session list and new sign-in alerts"
+// @Success 200 {object} router.successResponse{data=LoginResponse} "Authentication result"
+// @Failure 401 {object} router.errorResponse "Invalid relay state, missing flow cookie, or rejected or replayed response"
+// @Failure 403 {object} router.errorResponse "No email asserted or account not allowed"
+// @Failure 404 {object} router.errorResponse "SAML is not configured"
+// @Failure 409 {object} router.errorResponse "Account exists and linking by email is disabled"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/saml/acs [post]
+func (h *HTTPEndpoint) SAMLACS(r *router.Request) (any, error) {
+	if state := r.PostFormValue("RelayState"); usecase.IsLinkState(state) {
+		conn, err := h.uc.LinkSAML(r.Context(), usecase.LinkSAMLInput{
+			SAMLResponse: r.PostFormValue("SAMLResponse"),
+			RelayState:   state,
+			Binding:      h.flowBinding(r),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return h.clearFlowCookie(toIdentityResponse(*conn)), nil
+	}
+
+	resp, err := h.uc.LoginSAML(r.Context(), usecase.LoginSAMLInput{
+		SAMLResponse: r.PostFormValue("SAMLResponse"),
+		RelayState:   r.PostFormValue("RelayState"),
+		Binding:      h.flowBinding(r),
+		IP:           r.RemoteAddr,
+		UserAgent:    r.UserAgent(),
+		ClientType:   r.Header.Get(headerClientType),
+		DeviceName:   r.Header.Get(headerDeviceName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &LoginResponse{
+		AccessToken:      resp.AccessToken,
+		RefreshToken:     resp.RefreshToken,
+		MfaRequired:      resp.MfaRequired,
+		ChallengeToken:   resp.ChallengeToken,
+		AvailableMethods: resp.AvailableMethods,
+
+		PasswordResetRequired: resp.PasswordResetRequired,
+		MfaSetupRequired:      resp.MfaSetupRequired,
+	}
+
+	return h.clearFlowCookie(h.tokenResponse(r, false, out, &out.RefreshToken, &out.CSRFToken)), nil
+}
+
 // Login2FA completes an 2FA login challenge and issues tokens.
 // @Summary Complete 2FA login
-// @Description Verifies the 2FA code for a login challenge and returns access/refresh tokens.
+// @Description Verifies the 2FA code for a login challenge and returns access/refresh tokens. With remember_device, a device token is also returned; sending it as device_token on later logins skips the MFA challenge until it expires. When an administrator requires a new password, password_reset_required is true and challenge_token is a password reset token instead.
 // @Tags Identity, Authentication
 // @Accept json
 // @Produce json
 // @Param request body Login2FARequest true "2FA login payload"
+// @Param X-Client-Type header string false "Client type used to pick token lifetimes (e.g. web, mobile, service)"
+// @Param X-Device-Name header string false "Name of the device signing in, shown in the session list and new sign-in alerts"
 // @Success 200 {object} router.successResponse{data=Login2FAResponse} "Authentication result"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Invalid MFA code"
@@ -78,24 +299,282 @@ func (h *HTTPEndpoint) Login2FA(r *router.Request) (any, error) {
 		ChallengeToken: req.ChallengeToken,
 		Method:         entity.MFATypeFromString(req.Method),
 		Code:           req.Code,
+		IP:             r.RemoteAddr,
+		UserAgent:      r.UserAgent(),
+		ClientType:     r.Header.Get(headerClientType),
+		DeviceName:     r.Header.Get(headerDeviceName),
+		RememberDevice: req.RememberDevice,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Login2FAResponse{
+		PasswordResetRequired: resp.PasswordResetRequired,
+		ChallengeToken:        resp.ChallengeToken,
+
+		AccessToken:  resp.AccessToken,
+		RefreshToken: resp.RefreshToken,
+		DeviceToken:  resp.DeviceToken,
+	}
+	if resp.DeviceToken != "" {
+		out.DeviceExpiresAt = &resp.DeviceExpiresAt
+	}
+
+	return h.tokenResponse(r, false, out, &out.RefreshToken, &out.CSRFToken), nil
+}
+
+// Login2FASMS texts a login code to the SMS factor of a 2FA login challenge.
+// @Summary Send SMS login code
+// @Description Sends a one-time code to the verified phone of the user behind the login challenge.
+// @Tags Identity, Authentication
+// @Accept json
+// @Produce json
+// @Param request body Login2FASMSRequest true "SMS login code payload"
+// @Success 200 {object} router.successResponse{data=Login2FASMSResponse} "Masked destination"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Invalid challenge session"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 429 {object} router.errorResponse "Too many SMS codes requested"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/login/2fa/sms [post]
+func (h *HTTPEndpoint) Login2FASMS(r *router.Request) (any, error) {
+	var req Login2FASMSRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.Login2FASMS(r.Context(), usecase.Login2FASMSInput{
+		ChallengeToken: req.ChallengeToken,
+		IP:             r.RemoteAddr,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return Login2FASMSResponse{Destination: resp.Destination}, nil
+}
+
+// LoginMFASetup starts the MFA enrollment a login is held for.
+// @Summary Start login MFA setup
+// @Description Generates a TOTP secret for a login that returned mfa_setup_required. The secret is kept on the login challenge, so the confirm endpoint takes the same challenge_token.
+// @Tags Identity, Authentication
+// @Accept json
+// @Produce json
+// @Param request body LoginMFASetupRequest true "Login MFA setup payload"
+// @Success 200 {object} router.successResponse{data=LoginMFASetupResponse} "TOTP secret"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Invalid challenge session"
+// @Failure 409 {object} router.errorResponse "Factor name already used"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/login/mfa-setup [post]
+func (h *HTTPEndpoint) LoginMFASetup(r *router.Request) (any, error) {
+	var req LoginMFASetupRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.LoginMFASetup(r.Context(), usecase.LoginMFASetupInput{
+		ChallengeToken: req.ChallengeToken,
+		FriendlyName:   req.FriendlyName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return LoginMFASetupResponse{Key: resp.Key, URI: resp.URI}, nil
+}
+
+// LoginMFASetupConfirm finishes the MFA enrollment a login is held for.
+// @Summary Confirm login MFA setup
+// @Description Verifies the first TOTP code, enrolls the factor and returns access/refresh tokens.
+// @Tags Identity, Authentication
+// @Accept json
+// @Produce json
+// @Param request body LoginMFASetupConfirmRequest true "Login MFA setup confirm payload"
+// @Param X-Client-Type header string false "Client type used to pick token lifetimes (e.g. web, mobile, service)"
+// @Param X-Device-Name header string false "Name of the device signing in, shown in the session list and new sign-in alerts"
+// @Success 200 {object} router.successResponse{data=LoginResponse} "Authentication result"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Invalid challenge session or code"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 429 {object} router.errorResponse "Too many attempts, see reason and Retry-After"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/login/mfa-setup/confirm [post]
+func (h *HTTPEndpoint) LoginMFASetupConfirm(r *router.Request) (any, error) {
+	var req LoginMFASetupConfirmRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.LoginMFASetupConfirm(r.Context(), usecase.LoginMFASetupConfirmInput{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+		IP:             r.RemoteAddr,
+		UserAgent:      r.UserAgent(),
+		ClientType:     r.Header.Get(headerClientType),
+		DeviceName:     r.Header.Get(headerDeviceName),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return Login2FAResponse{
+	out := &LoginResponse{
 		AccessToken:  resp.AccessToken,
 		RefreshToken: resp.RefreshToken,
+	}
+
+	return h.tokenResponse(r, false, out, &out.RefreshToken, &out.CSRFToken), nil
+}
+
+// ClientCredentialsToken issues an access token to a service account.
+// @Summary Service account token
+// @Description OAuth2 client_credentials grant. The client authenticates with HTTP Basic or with client_id and client_secret in a form or JSON body. The token carries a client_id claim; no refresh token is issued.
+// @Tags Identity, Authentication
+// @Accept json,x-www-form-urlencoded
+// @Produce json
+// @Param request body ClientCredentialsRequest true "Client credentials payload"
+// @Success 200 {object} router.successResponse{data=ClientCredentialsResponse} "Access token"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Invalid client credentials"
+// @Failure 422 {object} router.errorResponse "Validation error or unsupported grant_type"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/token [post]
+func (h *HTTPEndpoint) ClientCredentialsToken(r *router.Request) (any, error) {
+	var req ClientCredentialsRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			return nil, goerror.NewInvalidFormat()
+		}
+		req.GrantType = r.PostForm.Get("grant_type")
+		req.ClientID = r.PostForm.Get("client_id")
+		req.ClientSecret = r.PostForm.Get("client_secret")
+	} else if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	if id, secret, ok := r.BasicAuth(); ok {
+		req.ClientID, req.ClientSecret = id, secret
+	}
+
+	out, err := h.uc.ClientCredentialsToken(r.Context(), usecase.ClientCredentialsTokenInput{
+		GrantType:    req.GrantType,
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ClientCredentialsResponse{
+		AccessToken: out.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   out.ExpiresIn,
+	}, nil
+}
+
+// metadataEndpoints are the paths listed by Metadata, relative to the configured base URL.
+var metadataEndpoints = map[string]string{
+	"login":           "/api/v1/identity/login",
+	"login_2fa":       "/api/v1/identity/login/2fa",
+	"login_2fa_sms":   "/api/v1/identity/login/2fa/sms",
+	"refresh":         "/api/v1/identity/refresh",
+	"logout":          "/api/v1/identity/logout",
+	"register":        "/api/v1/identity/register",
+	"password_forgot": "/api/v1/identity/password/forgot",
+	"password_reset":  "/api/v1/identity/password/reset",
+	"oauth_authorize": "/api/v1/identity/oauth/{provider}/authorize",
+	"mfa_recovery":    "/api/v1/identity/mfa/recovery",
+	"profile":         "/api/v1/identity/profile",
+	"permissions":     "/api/v1/identity/profile/permissions",
+}
+
+// JWKS publishes the keys access tokens are verified with.
+// @Summary JSON Web Key Set
+// @Description Returns the public keys of RS256 or ES256 signed access tokens as a JWKS (RFC 7517), the active key first, so other services can verify tokens. Tokens name their key in the kid header.
+// @Tags Identity, Authentication
+// @Produce json
+// @Success 200 {object} jwt.JWKSet "Public keys"
+// @Failure 404 {object} router.errorResponse "Tokens are signed with a shared secret"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /.well-known/jwks.json [get]
+func (h *HTTPEndpoint) JWKS(r *router.Request) (any, error) {
+	set, err := h.uc.JWKS(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	// a JWKS is read by verifiers as is, outside the response envelope
+	return &router.File{
+		ContentType: "application/json",
+		Write: func(w io.Writer) error {
+			return json.NewEncoder(w).Encode(set)
+		},
+	}, nil
+}
+
+// Metadata describes the deployment for client SDKs.
+// @Summary Identity provider metadata
+// @Description Lists the token and sign-in endpoints, supported grants, MFA methods, OAuth providers, password policy and enabled features, so client SDKs can configure themselves. Endpoints are absolute when modules.identity.metadata.base_url is set, otherwise relative to this host.
+// @Tags Identity, Authentication
+// @Produce json
+// @Success 200 {object} router.successResponse{data=MetadataResponse} "Deployment metadata"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /.well-known/gobite-configuration [get]
+func (h *HTTPEndpoint) Metadata(r *router.Request) (any, error) {
+	out, err := h.uc.Metadata(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make(map[string]string, len(metadataEndpoints))
+	for name, path := range metadataEndpoints {
+		endpoints[name] = out.BaseURL + path
+	}
+
+	oauthProviders := out.OAuthProviders
+	if oauthProviders == nil {
+		oauthProviders = []string{}
+	}
+
+	return MetadataResponse{
+		Issuer:                            out.Issuer,
+		TokenEndpoint:                     out.BaseURL + "/api/v1/identity/token",
+		Endpoints:                         endpoints,
+		JWKSURI:                           out.JWKSURI,
+		GrantTypesSupported:               []string{"client_credentials"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+		APIKeyHeader:                      router.HeaderAPIKey,
+		AccessTokenTTLSeconds:             out.AccessTokenTTLSeconds,
+		RefreshTokenTTLDays:               out.RefreshTokenTTLDays,
+		MFAMethodsSupported:               out.MFAMethods,
+		OAuthProviders:                    oauthProviders,
+		PasswordPolicy: PasswordPolicy{
+			MinLength:        out.PasswordPolicy.MinLength,
+			MaxLength:        out.PasswordPolicy.MaxLength,
+			RequireLowercase: out.PasswordPolicy.RequireLower,
+			RequireUppercase: out.PasswordPolicy.RequireUpper,
+			RequireDigit:     out.PasswordPolicy.RequireDigit,
+			RequireSymbol:    out.PasswordPolicy.RequireSymbol,
+			DisallowEmail:    out.PasswordPolicy.DisallowEmail,
+			HistorySize:      out.PasswordPolicy.HistorySize,
+			BreachCheck:      out.PasswordPolicy.BreachCheck,
+		},
+		Features: out.Features,
 	}, nil
 }
 
 // RefreshToken issues a new access token using a refresh token.
 // @Summary Refresh access token
-// @Description Exchanges a refresh token for a new access/refresh token pair.
+// @Description Exchanges a refresh token for a new access/refresh token pair. Token lifetimes follow the client type given at sign-in.
 // @Tags Identity, Authentication
 // @Accept json
 // @Produce json
-// @Param request body RefreshTokenRequest true "Refresh token payload"
+// @Param request body RefreshTokenRequest true "Refresh token payload; leave refresh_token out to use the refresh token cookie"
+// @Param X-CSRF-Token header string false "CSRF token, required when the refresh token is sent as a cookie"
+// @Param X-Client-Type header string false "Client type deciding whether the refresh token is kept in a cookie"
+// @Param X-Device-Name header string false "Name of the device signing in, shown in the session list and new sign-in alerts"
 // @Success 200 {object} router.successResponse{data=RefreshTokenResponse} "Token refresh result"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Invalid refresh token"
@@ -108,15 +587,68 @@ func (h *HTTPEndpoint) RefreshToken(r *router.Request) (any, error) {
 		return nil, err
 	}
 
-	resp, err := h.uc.RefreshToken(r.Context(), usecase.RefreshTokenInput{RefreshToken: req.RefreshToken})
+	viaCookie := false
+	if req.RefreshToken == "" {
+		token, err := h.cookieRefreshToken(r)
+		if err != nil {
+			return nil, err
+		}
+		req.RefreshToken, viaCookie = token, token != ""
+	}
+
+	resp, err := h.uc.RefreshToken(r.Context(), usecase.RefreshTokenInput{
+		RefreshToken: req.RefreshToken,
+		IP:           r.RemoteAddr,
+		UserAgent:    r.UserAgent(),
+		DeviceName:   r.Header.Get(headerDeviceName),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return RefreshTokenResponse{
+	out := &RefreshTokenResponse{
 		AccessToken:  resp.AccessToken,
 		RefreshToken: resp.RefreshToken,
-	}, nil
+	}
+
+	return h.tokenResponse(r, viaCookie, out, &out.RefreshToken, &out.CSRFToken), nil
+}
+
+// Reauth re-authenticates the current user for a sensitive operation.
+// @Summary Step-up authentication
+// @Description Re-checks the password, and a TOTP or backup code when MFA is enabled, and returns an access token with a fresh auth_time. Sensitive operations answer 403 with reason step_up_required when the sign-in is older than modules.identity.step_up.max_age_minutes; retry them with this token.
+// @Tags Identity, Authentication
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body ReauthRequest true "Re-authentication payload"
+// @Param X-Client-Type header string false "Client type used to pick token lifetimes (e.g. web, mobile, service)"
+// @Success 200 {object} router.successResponse{data=ReauthResponse} "Fresh access token"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Invalid password or MFA code, reason mfa_required when a code is missing"
+// @Failure 403 {object} router.errorResponse "Not a user session"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 429 {object} router.errorResponse "Too many attempts, see reason and Retry-After"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/reauth [post]
+func (h *HTTPEndpoint) Reauth(r *router.Request) (any, error) {
+	var req ReauthRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.Reauthenticate(r.Context(), usecase.ReauthenticateInput{
+		Password:   req.Password,
+		Method:     entity.MFATypeFromString(req.Method),
+		Code:       req.Code,
+		IP:         r.RemoteAddr,
+		ClientType: r.Header.Get(headerClientType),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ReauthResponse{AccessToken: resp.AccessToken}, nil
 }
 
 // Register creates a new user account.
@@ -125,8 +657,10 @@ func (h *HTTPEndpoint) RefreshToken(r *router.Request) (any, error) {
 // @Tags Identity, Authentication
 // @Accept json
 // @Param request body RegisterRequest true "Registration payload"
+// @Param X-Captcha-Token header string false "CAPTCHA response token, required when the endpoint is listed in the captcha config"
 // @Success 204 "No Content"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 403 {object} router.errorResponse "CAPTCHA verification failed"
 // @Failure 409 {object} router.errorResponse "Email already registered"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
@@ -138,9 +672,11 @@ func (h *HTTPEndpoint) Register(r *router.Request) (any, error) {
 	}
 
 	if err := h.uc.Register(r.Context(), usecase.RegisterInput{
-		Email:    req.Email,
-		Password: req.Password,
-		FullName: req.FullName,
+		Email:        req.Email,
+		Password:     req.Password,
+		FullName:     req.FullName,
+		Username:     req.Username,
+		CaptchaToken: r.CaptchaToken(req.CaptchaToken),
 	}); err != nil {
 		return nil, err
 	}
@@ -155,8 +691,10 @@ func (h *HTTPEndpoint) Register(r *router.Request) (any, error) {
 // @Accept json
 // @Produce json
 // @Param request body RegisterResendRequest true "Resend verification payload"
+// @Param X-Captcha-Token header string false "CAPTCHA response token, required when the endpoint is listed in the captcha config"
 // @Success 200 {object} router.successResponse{data=RegisterResendResponse} "Resend result"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 403 {object} router.errorResponse "CAPTCHA verification failed"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
 // @Router /api/v1/identity/register/resend [post]
@@ -167,7 +705,8 @@ func (h *HTTPEndpoint) RegisterResend(r *router.Request) (any, error) {
 	}
 
 	if err := h.uc.RegisterResend(r.Context(), usecase.RegisterResendInput{
-		Email: req.Email,
+		Email:        req.Email,
+		CaptchaToken: r.CaptchaToken(req.CaptchaToken),
 	}); err != nil {
 		return nil, err
 	}
@@ -193,7 +732,11 @@ func (h *HTTPEndpoint) RegisterVerify(r *router.Request) (any, error) {
 		return nil, err
 	}
 
-	return nil, h.uc.RegisterVerify(r.Context(), usecase.RegisterVerifyInput{ChallengeToken: req.ChallengeToken})
+	return nil, h.uc.RegisterVerify(r.Context(), usecase.RegisterVerifyInput{
+		ChallengeToken: req.ChallengeToken,
+		Expires:        req.Expires,
+		Signature:      req.Signature,
+	})
 }
 
 // PasswordForgot initiates a password reset flow.
@@ -202,8 +745,10 @@ func (h *HTTPEndpoint) RegisterVerify(r *router.Request) (any, error) {
 // @Tags Identity, Authentication
 // @Accept json
 // @Param request body PasswordForgotRequest true "Forgot password payload"
+// @Param X-Captcha-Token header string false "CAPTCHA response token, required when the endpoint is listed in the captcha config"
 // @Success 204 "No Content"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 403 {object} router.errorResponse "CAPTCHA verification failed"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
 // @Router /api/v1/identity/password/forgot [post]
@@ -213,7 +758,10 @@ func (h *HTTPEndpoint) PasswordForgot(r *router.Request) (any, error) {
 		return nil, err
 	}
 
-	if err := h.uc.PasswordForgot(r.Context(), usecase.PasswordForgotInput{Email: req.Email}); err != nil {
+	if err := h.uc.PasswordForgot(r.Context(), usecase.PasswordForgotInput{
+		Email:        req.Email,
+		CaptchaToken: r.CaptchaToken(req.CaptchaToken),
+	}); err != nil {
 		return nil, err
 	}
 
@@ -222,7 +770,7 @@ func (h *HTTPEndpoint) PasswordForgot(r *router.Request) (any, error) {
 
 // PasswordReset completes a password reset using a reset token.
 // @Summary Reset password
-// @Description Sets a new password using the provided reset token.
+// @Description Sets a new password using the provided reset token. Other outstanding reset links stop working and every session is signed out.
 // @Tags Identity, Authentication
 // @Accept json
 // @Param request body PasswordResetRequest true "Reset password payload"
@@ -246,7 +794,7 @@ func (h *HTTPEndpoint) PasswordReset(r *router.Request) (any, error) {
 
 // PasswordChange updates the current user's password.
 // @Summary Change password
-// @Description Updates the user's password after validating the current password.
+// @Description Updates the user's password after validating the current password. Access tokens issued before the change stop working.
 // @Tags Identity, Profile Security
 // @Security BearerAuth
 // @Accept json
@@ -269,40 +817,448 @@ func (h *HTTPEndpoint) PasswordChange(r *router.Request) (any, error) {
 	})
 }
 
-// Logout revokes a refresh token.
-// @Summary Logout
-// @Description Invalidates the provided refresh token.
-// @Tags Identity, Authentication
+// EmailChange starts an email change for the current user.
+// @Summary Change email
+// @Description Sends a confirmation link to the new address. The current address stays active until the change is confirmed.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
 // @Accept json
-// @Param request body LogoutRequest true "Logout payload"
-// @Success 204 "No Content"
+// @Produce json
+// @Param request body EmailChangeRequest true "Change email payload"
+// @Success 200 {object} router.successResponse "Confirmation sent"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Recent authentication required (reason step_up_required)"
+// @Failure 409 {object} router.errorResponse "Email already registered"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/logout [post]
-func (h *HTTPEndpoint) Logout(r *router.Request) (any, error) {
-	var req LogoutRequest
+// @Router /api/v1/identity/email/change [post]
+func (h *HTTPEndpoint) EmailChange(r *router.Request) (any, error) {
+	var req EmailChangeRequest
 	if err := r.DecodeBody(&req); err != nil {
 		return nil, err
 	}
 
-	return nil, h.uc.Logout(r.Context(), usecase.LogoutInput{RefreshToken: req.RefreshToken})
+	if err := h.uc.EmailChange(r.Context(), usecase.EmailChangeInput{
+		NewEmail:        req.NewEmail,
+		CurrentPassword: req.CurrentPassword,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &EmailChangeResponse{}, nil
 }
 
-// LogoutAll revokes all active sessions for the current user.
-// @Summary Logout all sessions
-// @Description Invalidates all refresh tokens for the authenticated user.
+// EmailChangeConfirm completes an email change using the emailed token.
+// @Summary Confirm email change
+// @Description Switches the account to the new address and signs out every session.
 // @Tags Identity, Profile Security
-// @Security BearerAuth
+// @Accept json
+// @Param request body EmailChangeConfirmRequest true "Confirm email change payload"
 // @Success 204 "No Content"
-// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Invalid or expired token"
+// @Failure 409 {object} router.errorResponse "Email already registered"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/logout-all [post]
-func (h *HTTPEndpoint) LogoutAll(r *router.Request) (any, error) {
-	return nil, h.uc.LogoutAll(r.Context(), usecase.LogoutAllInput{})
-}
-
+// @Router /api/v1/identity/email/change/confirm [post]
+func (h *HTTPEndpoint) EmailChangeConfirm(r *router.Request) (any, error) {
+	var req EmailChangeConfirmRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.EmailChangeConfirm(r.Context(), usecase.EmailChangeConfirmInput{
+		ChallengeToken: req.ChallengeToken,
+	})
+}
+
+// Logout revokes a refresh token.
+// @Summary Logout
+// @Description Invalidates the provided refresh token and the access token of the request. Without refresh_token in the body, the refresh token cookie is used, which requires the X-CSRF-Token header and is cleared.
+// @Tags Identity, Authentication
+// @Accept json
+// @Param request body LogoutRequest true "Logout payload"
+// @Param X-CSRF-Token header string false "CSRF token, required when the refresh token is sent as a cookie"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/logout [post]
+func (h *HTTPEndpoint) Logout(r *router.Request) (any, error) {
+	var req LogoutRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	viaCookie := false
+	if req.RefreshToken == "" {
+		token, err := h.cookieRefreshToken(r)
+		if err != nil {
+			return nil, err
+		}
+		req.RefreshToken, viaCookie = token, token != ""
+	}
+
+	if err := h.uc.Logout(r.Context(), usecase.LogoutInput{RefreshToken: req.RefreshToken}); err != nil {
+		return nil, err
+	}
+
+	if viaCookie {
+		return &router.WithCookies{Cookies: h.clearRefreshCookies()}, nil
+	}
+
+	return nil, nil
+}
+
+// LogoutAll revokes all active sessions for the current user.
+// @Summary Logout all sessions
+// @Description Invalidates all refresh tokens for the authenticated user, and every access token issued so far.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/logout-all [post]
+func (h *HTTPEndpoint) LogoutAll(r *router.Request) (any, error) {
+	return nil, h.uc.LogoutAll(r.Context(), usecase.LogoutAllInput{})
+}
+
+// ListSessions returns the active sessions of the current user.
+// @Summary List sessions
+// @Description Returns the unrevoked, unexpired sessions of the authenticated user with the device and IP that last used each one, and the device name and client type given at sign-in (X-Device-Name, X-Client-Type). A session id changes whenever its refresh token is rotated.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=SessionsResponse} "Active sessions"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/sessions [get]
+func (h *HTTPEndpoint) ListSessions(r *router.Request) (any, error) {
+	out, err := h.uc.ListSessions(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]SessionResponse, 0, len(out.Sessions))
+	for _, session := range out.Sessions {
+		resp = append(resp, SessionResponse{
+			ID:         session.ID,
+			IP:         session.IP,
+			UserAgent:  session.UserAgent,
+			Client:     session.Client,
+			DeviceName: session.DeviceName,
+			LastUsedAt: session.CreatedAt,
+			StartedAt:  session.SessionStartedAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+
+	return SessionsResponse{Sessions: resp}, nil
+}
+
+// RevokeSession revokes a single session of the current user.
+// @Summary Revoke session
+// @Description Invalidates one refresh token of the authenticated user, signing that device out.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid session id"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 404 {object} router.errorResponse "Session not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/sessions/{id} [delete]
+func (h *HTTPEndpoint) RevokeSession(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.RevokeSession(r.Context(), usecase.RevokeSessionInput{ID: id})
+}
+
+// ListTrustedDevices returns the trusted devices of the current user.
+// @Summary List trusted devices
+// @Description Returns the devices of the authenticated user that skip the MFA challenge on login, remembered through remember_device on the 2FA login.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=TrustedDevicesResponse} "Trusted devices"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/trusted-devices [get]
+func (h *HTTPEndpoint) ListTrustedDevices(r *router.Request) (any, error) {
+	out, err := h.uc.ListTrustedDevices(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]TrustedDeviceResponse, 0, len(out.Devices))
+	for _, device := range out.Devices {
+		resp = append(resp, TrustedDeviceResponse{
+			ID:         device.ID,
+			IP:         device.IP,
+			UserAgent:  device.UserAgent,
+			LastUsedAt: device.LastUsedAt,
+			CreatedAt:  device.CreatedAt,
+			ExpiresAt:  device.ExpiresAt,
+		})
+	}
+
+	return TrustedDevicesResponse{Devices: resp}, nil
+}
+
+// RevokeTrustedDevice revokes a trusted device of the current user.
+// @Summary Revoke trusted device
+// @Description Forgets one trusted device of the authenticated user, so its next login asks for MFA again.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Param id path int true "Trusted device ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid trusted device id"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 404 {object} router.errorResponse "Trusted device not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/trusted-devices/{id} [delete]
+func (h *HTTPEndpoint) RevokeTrustedDevice(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.RevokeTrustedDevice(r.Context(), usecase.RevokeTrustedDeviceInput{ID: id})
+}
+
+// ListIdentities returns the external identities linked to the current user.
+// @Summary List linked identities
+// @Description Returns the OAuth and SAML identities the authenticated user can sign in with.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=IdentitiesResponse} "Linked identities"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/identities [get]
+func (h *HTTPEndpoint) ListIdentities(r *router.Request) (any, error) {
+	out, err := h.uc.ListIdentities(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]IdentityResponse, 0, len(out.Identities))
+	for _, conn := range out.Identities {
+		resp = append(resp, toIdentityResponse(conn))
+	}
+
+	return IdentitiesResponse{Identities: resp}, nil
+}
+
+// LinkIdentity starts linking an external identity to the current user.
+// @Summary Link identity
+// @Description Returns the provider sign-in URL and sets the flow cookie binding the link to this browser and session. The state starts with "link.": when the provider redirects back with it, the app posts the code and state to the link callback instead of the sign-in callback; the SAML assertion consumer service links on its own. Linking is refused when the identity or its email belongs to another account, or once the session that started it is revoked.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Produce json
+// @Param provider path string true "Provider name" Enums(google, github, oidc, saml)
+// @Success 200 {object} router.successResponse{data=OAuthAuthorizeResponse} "Authorization URL"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Recent authentication required"
+// @Failure 404 {object} router.errorResponse "Provider not supported"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/identities/{provider}/link [post]
+func (h *HTTPEndpoint) LinkIdentity(r *router.Request) (any, error) {
+	resp, err := h.uc.LinkIdentityAuthorize(r.Context(), usecase.LinkIdentityAuthorizeInput{
+		Provider: r.GetParam("provider"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return h.withFlowCookie(OAuthAuthorizeResponse{
+		AuthorizationURL: resp.AuthorizationURL,
+		State:            resp.State,
+	}, resp.Binding, resp.BindingTTL), nil
+}
+
+// LinkIdentityCallback completes linking an OAuth identity to the current user.
+// @Summary Complete identity link
+// @Description Called by the app with the code and a "link." state the provider redirected back with, authenticated as the user that started the link and from the browser holding its flow cookie. Returns the linked identity.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name" Enums(google, github, oidc)
+// @Param request body OAuthCallbackRequest true "Code and state the provider redirected with"
+// @Success 200 {object} router.successResponse{data=IdentityResponse} "Linked identity"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized, invalid state, missing flow cookie, revoked session or rejected code"
+// @Failure 404 {object} router.errorResponse "Provider not supported"
+// @Failure 409 {object} router.errorResponse "Identity or its email belongs to another account"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/identities/{provider}/callback [post]
+func (h *HTTPEndpoint) LinkIdentityCallback(r *router.Request) (any, error) {
+	var req OAuthCallbackRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	if req.Error != "" {
+		return nil, goerror.NewBusiness("identity link was not completed: "+req.Error, goerror.CodeUnauthorized)
+	}
+
+	conn, err := h.uc.LinkOAuth(r.Context(), usecase.LinkOAuthInput{
+		Provider: r.GetParam("provider"),
+		Code:     req.Code,
+		State:    req.State,
+		Binding:  h.flowBinding(r),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return h.clearFlowCookie(toIdentityResponse(*conn)), nil
+}
+
+// UnlinkIdentity removes an external identity from the current user.
+// @Summary Unlink identity
+// @Description Removes one linked identity of the authenticated user. Password sign-in keeps working.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Param id path int true "Identity ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid identity id"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Recent authentication required"
+// @Failure 404 {object} router.errorResponse "Identity not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/identities/{id} [delete]
+func (h *HTTPEndpoint) UnlinkIdentity(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.UnlinkIdentity(r.Context(), usecase.UnlinkIdentityInput{ID: id})
+}
+
+func toIdentityResponse(conn entity.UserConnection) IdentityResponse {
+	return IdentityResponse{
+		ID:             conn.ID,
+		Provider:       conn.Provider,
+		ProviderUserID: conn.ProviderUserID,
+		CreatedAt:      conn.CreatedAt,
+	}
+}
+
+// APIKeyCreate issues an API key for the current user.
+// @Summary Create API key
+// @Description Issues a key to send in the X-API-Key header instead of a bearer token. Scopes map objects to actions and must be allowed to the user; the key is also limited to what the user is allowed at the time of each request. The key is shown only in this response. API keys cannot manage API keys.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body APIKeyCreateRequest true "API key payload"
+// @Success 200 {object} router.successResponse{data=APIKeyCreateResponse} "Created API key"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Scope not allowed, impersonating, or recent authentication required"
+// @Failure 409 {object} router.errorResponse "Too many active API keys"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/api-keys [post]
+func (h *HTTPEndpoint) APIKeyCreate(r *router.Request) (any, error) {
+	var req APIKeyCreateRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	out, err := h.uc.APIKeyCreate(r.Context(), usecase.APIKeyCreateInput{
+		Name:          req.Name,
+		Scopes:        req.Scopes,
+		ExpiresInDays: req.ExpiresInDays,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return APIKeyCreateResponse{
+		APIKeyResponse: toAPIKeyResponse(out.APIKey),
+		Key:            out.Key,
+	}, nil
+}
+
+// APIKeyList returns the API keys of the current user.
+// @Summary List API keys
+// @Description Returns the unrevoked API keys of the authenticated user with their scopes. Only the prefix of each key is shown.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=APIKeysResponse} "API keys"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/api-keys [get]
+func (h *HTTPEndpoint) APIKeyList(r *router.Request) (any, error) {
+	out, err := h.uc.APIKeyList(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]APIKeyResponse, 0, len(out.APIKeys))
+	for _, key := range out.APIKeys {
+		resp = append(resp, toAPIKeyResponse(key))
+	}
+
+	return APIKeysResponse{APIKeys: resp}, nil
+}
+
+// APIKeyRevoke revokes an API key of the current user.
+// @Summary Revoke API key
+// @Description Revokes one API key of the authenticated user; requests sending it are rejected from then on.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid API key id"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "API key not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/api-keys/{id} [delete]
+func (h *HTTPEndpoint) APIKeyRevoke(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.APIKeyRevoke(r.Context(), usecase.APIKeyRevokeInput{ID: id})
+}
+
+func toAPIKeyResponse(key entity.APIKey) APIKeyResponse {
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = map[string][]string{}
+	}
+
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
 // TOTPSetup registers a new TOTP factor for the current user.
 // @Summary Setup TOTP
 // @Description Creates a TOTP factor and returns the shared secret and otpauth URI.
@@ -310,57 +1266,1682 @@ func (h *HTTPEndpoint) LogoutAll(r *router.Request) (any, error) {
 // @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param request body TOTPSetupRequest true "TOTP setup payload"
-// @Success 200 {object} router.successResponse{data=TOTPSetupResponse} "TOTP setup result"
+// @Param request body TOTPSetupRequest true "TOTP setup payload"
+// @Success 200 {object} router.successResponse{data=TOTPSetupResponse} "TOTP setup result"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/mfa/totp/setup [post]
+func (h *HTTPEndpoint) TOTPSetup(r *router.Request) (any, error) {
+	var req TOTPSetupRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.TOTPSetup(r.Context(), usecase.TOTPSetupInput{
+		FriendlyName:    req.FriendlyName,
+		CurrentPassword: req.CurrentPassword,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return TOTPSetupResponse{
+		ChallengeToken: resp.ChallengeToken,
+		Key:            resp.Key,
+		URI:            resp.URI,
+	}, nil
+}
+
+// TOTPConfirm verifies a TOTP code to activate the factor.
+// @Summary Confirm TOTP
+// @Description Verifies the TOTP code and activates the MFA factor.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Accept json
+// @Param request body TOTPConfirmRequest true "TOTP confirmation payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 404 {object} router.errorResponse "MFA factor not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/mfa/totp/confirm [post]
+func (h *HTTPEndpoint) TOTPConfirm(r *router.Request) (any, error) {
+	var req TOTPConfirmRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	if err := h.uc.TOTPConfirm(r.Context(), usecase.TOTPConfirmInput{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// SMSSetup texts a confirmation code to the phone of a new SMS factor.
+// @Summary Setup SMS
+// @Description Sends a one-time code to the given phone number to start enrolling an SMS factor.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body SMSSetupRequest true "SMS setup payload"
+// @Success 200 {object} router.successResponse{data=SMSSetupResponse} "SMS setup result"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 409 {object} router.errorResponse "SMS factor already exists"
+// @Failure 422 {object} router.errorResponse "Validation error or SMS unavailable"
+// @Failure 429 {object} router.errorResponse "Too many SMS codes requested"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/mfa/sms/setup [post]
+func (h *HTTPEndpoint) SMSSetup(r *router.Request) (any, error) {
+	var req SMSSetupRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.SMSSetup(r.Context(), usecase.SMSSetupInput{
+		PhoneNumber:     req.PhoneNumber,
+		CurrentPassword: req.CurrentPassword,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return SMSSetupResponse{
+		ChallengeToken: resp.ChallengeToken,
+		Destination:    resp.Destination,
+	}, nil
+}
+
+// SMSConfirm verifies the texted code to activate the SMS factor.
+// @Summary Confirm SMS
+// @Description Verifies the SMS code and activates the MFA factor.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Accept json
+// @Param request body SMSConfirmRequest true "SMS confirmation payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 409 {object} router.errorResponse "SMS factor already exists"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/mfa/sms/confirm [post]
+func (h *HTTPEndpoint) SMSConfirm(r *router.Request) (any, error) {
+	var req SMSConfirmRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	if err := h.uc.SMSConfirm(r.Context(), usecase.SMSConfirmInput{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BackupCode rotates backup codes for the current user.
+// @Summary Rotate backup codes
+// @Description Generates a new set of recovery codes for the authenticated user.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body BackupCodeRequest true "Backup code rotation payload"
+// @Success 200 {object} router.successResponse{data=BackupCodeResponse} "Backup codes rotated"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Recent authentication required (reason step_up_required)"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/mfa/backup_code/rotate [post]
+func (h *HTTPEndpoint) BackupCode(r *router.Request) (any, error) {
+	var req BackupCodeRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.BackupCode(r.Context(), usecase.BackupCodeInput{CurrentPassword: req.CurrentPassword})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackupCodeResponse{RecoveryCodes: resp.RecoveryCodes}, nil
+}
+
+// ListMFAFactors returns the MFA factors of the current user.
+// @Summary List MFA factors
+// @Description Returns every MFA factor of the authenticated user, oldest first. A user may enroll several TOTP factors, each under its own friendly name.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=MFAFactorsResponse} "MFA factors"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/mfa/factors [get]
+func (h *HTTPEndpoint) ListMFAFactors(r *router.Request) (any, error) {
+	out, err := h.uc.ListMFAFactors(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	factors := make([]UserMFAFactorResponse, 0, len(out.Factors))
+	for _, f := range out.Factors {
+		factors = append(factors, UserMFAFactorResponse{
+			ID:           f.ID,
+			Type:         f.Type.String(),
+			FriendlyName: f.FriendlyName,
+			IsVerified:   f.IsVerified,
+			LastUsedAt:   f.LastUsedAt,
+			CreatedAt:    f.CreatedAt,
+		})
+	}
+
+	return MFAFactorsResponse{Factors: factors}, nil
+}
+
+// RenameMFAFactor renames an MFA factor of the current user.
+// @Summary Rename MFA factor
+// @Description Changes the friendly name of a TOTP or SMS factor of the authenticated user. TOTP names are unique per user.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Accept json
+// @Param id path int true "MFA factor ID"
+// @Param request body MFAFactorRenameRequest true "Rename payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 404 {object} router.errorResponse "MFA factor not found"
+// @Failure 409 {object} router.errorResponse "Name already used"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/mfa/factors/{id} [patch]
+func (h *HTTPEndpoint) RenameMFAFactor(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	var req MFAFactorRenameRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.RenameMFAFactor(r.Context(), usecase.RenameMFAFactorInput{
+		ID:           id,
+		FriendlyName: req.FriendlyName,
+	})
+}
+
+// DeleteMFAFactor removes an MFA factor of the current user.
+// @Summary Delete MFA factor
+// @Description Removes a TOTP or SMS factor of the authenticated user. The current password is always required; while another factor remains, an MFA code (TOTP or backup code) is required too and a missing one answers 401 with reason mfa_required. Removing the last factor turns MFA off, together with the backup codes and trusted devices.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Accept json
+// @Param id path int true "MFA factor ID"
+// @Param request body MFAFactorDeleteRequest true "Delete payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Invalid password or MFA code"
+// @Failure 403 {object} router.errorResponse "Recent authentication required (reason step_up_required)"
+// @Failure 404 {object} router.errorResponse "MFA factor not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 429 {object} router.errorResponse "Too many attempts, see reason and Retry-After"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/mfa/factors/{id} [delete]
+func (h *HTTPEndpoint) DeleteMFAFactor(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	var req MFAFactorDeleteRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.DeleteMFAFactor(r.Context(), usecase.DeleteMFAFactorInput{
+		ID:              id,
+		CurrentPassword: req.CurrentPassword,
+		Method:          entity.MFATypeFromString(req.Method),
+		Code:            req.Code,
+		IP:              r.RemoteAddr,
+	})
+}
+
+// MFARecoveryRequest starts recovery for a user who lost their second factor.
+// @Summary Request MFA recovery
+// @Description Emails a recovery link when the account exists and has MFA enabled. The response never reveals either.
+// @Tags Identity, Authentication
+// @Accept json
+// @Produce json
+// @Param request body MFARecoveryRequest true "MFA recovery request payload"
+// @Success 200 {object} router.successResponse "Recovery requested"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/mfa/recovery [post]
+func (h *HTTPEndpoint) MFARecoveryRequest(r *router.Request) (any, error) {
+	var req MFARecoveryRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	if err := h.uc.MFARecoveryRequest(r.Context(), usecase.MFARecoveryRequestInput{Email: req.Email}); err != nil {
+		return nil, err
+	}
+
+	return &MFARecoveryRequestResponse{}, nil
+}
+
+// MFARecoveryVerify checks the recovery signals and starts the waiting period.
+// @Summary Verify MFA recovery
+// @Description Verifies the emailed token and password (plus an optional backup code or a device that signed in before, which shorten the wait) and returns a recovery token usable once the waiting period ends.
+// @Tags Identity, Authentication
+// @Accept json
+// @Produce json
+// @Param request body MFARecoveryVerifyRequest true "MFA recovery verify payload"
+// @Success 200 {object} router.successResponse{data=MFARecoveryVerifyResponse} "Waiting period started"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Invalid recovery session or credentials"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/mfa/recovery/verify [post]
+func (h *HTTPEndpoint) MFARecoveryVerify(r *router.Request) (any, error) {
+	var req MFARecoveryVerifyRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.MFARecoveryVerify(r.Context(), usecase.MFARecoveryVerifyInput{
+		ChallengeToken: req.ChallengeToken,
+		Password:       req.Password,
+		BackupCode:     req.BackupCode,
+		IP:             r.RemoteAddr,
+		UserAgent:      r.UserAgent(),
+		ClientType:     r.Header.Get(headerClientType),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &MFARecoveryVerifyResponse{
+		RecoveryToken: resp.RecoveryToken,
+		AvailableAt:   resp.AvailableAt,
+	}, nil
+}
+
+// MFARecoveryComplete removes every MFA factor once the waiting period has elapsed.
+// @Summary Complete MFA recovery
+// @Description Removes all MFA factors and backup codes for the account tied to the recovery token.
+// @Tags Identity, Authentication
+// @Accept json
+// @Param request body MFARecoveryCompleteRequest true "MFA recovery complete payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Invalid recovery session"
+// @Failure 403 {object} router.errorResponse "Waiting period has not elapsed"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/mfa/recovery/complete [post]
+func (h *HTTPEndpoint) MFARecoveryComplete(r *router.Request) (any, error) {
+	var req MFARecoveryCompleteRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.MFARecoveryComplete(r.Context(), usecase.MFARecoveryCompleteInput{
+		RecoveryToken: req.RecoveryToken,
+	})
+}
+
+// ProfileUpdate updates the current user's profile information.
+// @Summary Update profile
+// @Description Updates profile details for the authenticated user.
+// @Tags Identity, Profile
+// @Security BearerAuth
+// @Accept json
+// @Param request body UpdateProfileRequest true "Profile update payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile [put]
+func (h *HTTPEndpoint) ProfileUpdate(r *router.Request) (any, error) {
+	var req UpdateProfileRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.ProfileUpdate(r.Context(), usecase.ProfileUpdateInput{FullName: req.FullName})
+}
+
+// ProfileUpdateUsername sets, changes, or removes the current user's username.
+// @Summary Update profile username
+// @Description Sets the username of the authenticated user; an empty username removes it. Only available when usernames are enabled.
+// @Tags Identity, Profile
+// @Security BearerAuth
+// @Accept json
+// @Param request body UpdateProfileUsernameRequest true "Username payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 404 {object} router.errorResponse "Usernames are not enabled"
+// @Failure 409 {object} router.errorResponse "Username already taken"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/username [put]
+func (h *HTTPEndpoint) ProfileUpdateUsername(r *router.Request) (any, error) {
+	var req UpdateProfileUsernameRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.ProfileUpdateUsername(r.Context(), usecase.ProfileUpdateUsernameInput{Username: req.Username})
+}
+
+// UsernameAvailability checks whether a username can be taken.
+// @Summary Check username availability
+// @Description Reports whether the username is valid, not reserved, and not taken.
+// @Tags Identity, Profile
+// @Security BearerAuth
+// @Produce json
+// @Param username query string true "Username to check"
+// @Success 200 {object} router.successResponse{data=UsernameAvailabilityResponse} "Availability"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 404 {object} router.errorResponse "Usernames are not enabled"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/username/availability [get]
+func (h *HTTPEndpoint) UsernameAvailability(r *router.Request) (any, error) {
+	resp, err := h.uc.UsernameAvailability(r.Context(), usecase.UsernameAvailabilityInput{
+		Username: r.GetQuery("username"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return UsernameAvailabilityResponse{
+		Username:  resp.Username,
+		Available: resp.Available,
+		Reason:    resp.Reason,
+	}, nil
+}
+
+// ProfileAttributes returns the current user's custom profile attributes.
+// @Summary Get profile attributes
+// @Description Returns the free-form attributes apps keep on the authenticated user, e.g. preferences.
+// @Tags Identity, Profile
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=ProfileAttributesResponse} "Attributes"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/attributes [get]
+func (h *HTTPEndpoint) ProfileAttributes(r *router.Request) (any, error) {
+	resp, err := h.uc.ProfileAttributes(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return ProfileAttributesResponse{Attributes: resp.Attributes}, nil
+}
+
+// ProfileUpdateAttributes merges changes into the current user's custom profile attributes.
+// @Summary Update profile attributes
+// @Description Merges the given keys into the attributes of the authenticated user; a key set to null is removed. Keys and sizes are limited by modules.identity.attributes.
+// @Tags Identity, Profile
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body ProfileAttributesRequest true "Attributes to set or remove"
+// @Success 200 {object} router.successResponse{data=ProfileAttributesResponse} "Stored attributes"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/attributes [patch]
+func (h *HTTPEndpoint) ProfileUpdateAttributes(r *router.Request) (any, error) {
+	var req ProfileAttributesRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.ProfileUpdateAttributes(r.Context(), usecase.ProfileUpdateAttributesInput{
+		Attributes: req.Attributes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ProfileAttributesResponse{Attributes: resp.Attributes}, nil
+}
+
+// ProfileUpdateAvatar updates the current user's avatar URL.
+// @Summary Update profile avatar
+// @Description Updates avatar for the authenticated user.
+// @Tags Identity, Profile
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Param avatar formData file true "Avatar image"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/avatar [put]
+func (h *HTTPEndpoint) ProfileUpdateAvatar(r *router.Request) (any, error) {
+	ctx := r.Context()
+
+	file, err := r.StreamSingleFile("avatar")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			slog.ErrorContext(ctx, "failed to close file", "error", err)
+		}
+	}()
+
+	head := make([]byte, 512)
+	n, err := file.Read(head)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, goerror.NewInvalidFormat()
+	}
+
+	return nil, h.uc.ProfileUpdateAvatar(ctx, usecase.ProfileUpdateAvatarInput{
+		File:        io.MultiReader(bytes.NewReader(head[:n]), file),
+		ContentType: http.DetectContentType(head[:n]),
+	})
+}
+
+// Profile retrieves the current user's profile details.
+// @Summary Get profile
+// @Description Returns profile information for the authenticated user.
+// @Tags Identity, Profile
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=ProfileResponse} "Profile result"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile [get]
+func (h *HTTPEndpoint) Profile(r *router.Request) (any, error) {
+	resp, err := h.uc.Profile(r.Context(), usecase.ProfileInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	return ProfileResponse{
+		ID:        resp.ID,
+		Email:     resp.Email,
+		FullName:  resp.FullName,
+		Username:  resp.Username,
+		AvatarURL: resp.AvatarURL,
+		Status:    resp.Status,
+	}, nil
+}
+
+// @Summary Get profile permissions
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Success 200 {object} router.successResponse{data=ProfilePermissionsResponse} "Permissions list"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/permissions [get]
+func (h *HTTPEndpoint) ProfilePermissions(r *router.Request) (any, error) {
+	resp, err := h.uc.ProfilePermissions(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	if resp == nil {
+		resp = map[string][]string{}
+	}
+
+	return ProfilePermissionsResponse{Permissions: resp}, nil
+}
+
+// @Summary Get profile MFA settings
+// @Description Returns MFA settings for the authenticated user.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=ProfileSettingMFAResponse} "MFA settings"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/settings/mfa [get]
+func (h *HTTPEndpoint) ProfileSettingMFA(r *router.Request) (any, error) {
+	resp, err := h.uc.ProfileSettingMFA(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return ProfileSettingMFAResponse{
+		TOTPEnabled:       resp.TOTPEnabled,
+		BackupCodeEnabled: resp.BackupCodeEnabled,
+		SMSEnabled:        resp.SMSEnabled,
+	}, nil
+}
+
+// @Summary Get profile onboarding checklist
+// @Description Returns the onboarding steps of the authenticated user (email verified, avatar set, MFA enabled, recovery codes saved) and overall completion.
+// @Tags Identity, Profile
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=ProfileOnboardingResponse} "Onboarding checklist"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/onboarding [get]
+func (h *HTTPEndpoint) ProfileOnboarding(r *router.Request) (any, error) {
+	resp, err := h.uc.ProfileOnboarding(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]OnboardingStepResponse, 0, len(resp.Steps))
+	for _, step := range resp.Steps {
+		steps = append(steps, OnboardingStepResponse{Key: step.Key, Completed: step.Completed})
+	}
+
+	return ProfileOnboardingResponse{
+		Steps:     steps,
+		Completed: resp.Completed,
+		Total:     resp.Total,
+		Percent:   resp.Percent,
+	}, nil
+}
+
+// @Summary Get profile security score
+// @Description Rates the account security of the authenticated user from 0 to 100 (MFA on, recovery codes saved, recent password, no long-idle sessions) with a recommendation for every failed check.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=ProfileSecurityScoreResponse} "Security score"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/security/score [get]
+func (h *HTTPEndpoint) ProfileSecurityScore(r *router.Request) (any, error) {
+	resp, err := h.uc.ProfileSecurityScore(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make([]SecurityCheckResponse, 0, len(resp.Checks))
+	for _, check := range resp.Checks {
+		checks = append(checks, SecurityCheckResponse{
+			Key:            check.Key,
+			Passed:         check.Passed,
+			Weight:         check.Weight,
+			Recommendation: check.Recommendation,
+		})
+	}
+
+	return ProfileSecurityScoreResponse{
+		Score:  resp.Score,
+		Checks: checks,
+	}, nil
+}
+
+// ProfileExport exports the current user's personal data.
+// @Summary Export profile data
+// @Description Assembles the user's profile, sessions and notifications into a zip archive of JSON files and returns the signed link to download it with the same account until expires_at.
+// @Tags Identity, Profile
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=ProfileExportResponse} "Export link"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/export [post]
+func (h *HTTPEndpoint) ProfileExport(r *router.Request) (any, error) {
+	resp, err := h.uc.ProfileExport(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return ProfileExportResponse{
+		ID:        resp.ID,
+		URL:       resp.URL,
+		ExpiresAt: resp.ExpiresAt,
+	}, nil
+}
+
+// ProfileExportDownload streams an archive made by ProfileExport.
+// @Summary Download profile data export
+// @Description Streams the zip archive of a profile data export through the signed link returned by the export. Only the user who requested it can download it, and only until it expires.
+// @Tags Identity, Profile
+// @Security BearerAuth
+// @Produce application/zip
+// @Param id path string true "Export ID"
+// @Param exp query string true "Link expiry from the signed link"
+// @Param sig query string true "Signature from the signed link"
+// @Success 200 {string} string "Zip archive"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Invalid link signature"
+// @Failure 404 {object} router.errorResponse "Export not found or expired"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/export/{id} [get]
+func (h *HTTPEndpoint) ProfileExportDownload(r *router.Request) (any, error) {
+	resp, err := h.uc.ProfileExportDownload(r.Context(), usecase.ProfileExportDownloadInput{
+		ID:        r.GetParam("id"),
+		Expires:   r.GetQuery(signedurl.ParamExpires),
+		Signature: r.GetQuery(signedurl.ParamSignature),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &router.File{
+		Name:        "profile-export.zip",
+		ContentType: "application/zip",
+		Write: func(w io.Writer) error {
+			defer resp.Body.Close()
+
+			_, err := io.Copy(w, resp.Body)
+			return err
+		},
+	}, nil
+}
+
+// ProfileDelete schedules the deletion of the current user's account.
+// @Summary Delete account
+// @Description Confirms the current password and schedules the account to be anonymized after the grace period. Asking again keeps the original schedule.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body ProfileDeleteRequest true "Delete account payload"
+// @Success 200 {object} router.successResponse{data=ProfileDeleteResponse} "Deletion scheduled"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized or invalid password"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/delete [post]
+func (h *HTTPEndpoint) ProfileDelete(r *router.Request) (any, error) {
+	var req ProfileDeleteRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.ProfileDelete(r.Context(), usecase.ProfileDeleteInput{
+		CurrentPassword: req.CurrentPassword,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ProfileDeleteResponse{ScheduledAt: resp.ScheduledAt}, nil
+}
+
+// ProfileDeleteCancel cancels the pending deletion of the current user's account.
+// @Summary Cancel account deletion
+// @Description Withdraws a deletion scheduled through the delete account endpoint while its grace period is still running.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Produce json
+// @Success 204 "Deletion cancelled"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 404 {object} router.errorResponse "No deletion is pending"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/delete/cancel [post]
+func (h *HTTPEndpoint) ProfileDeleteCancel(r *router.Request) (any, error) {
+	return nil, h.uc.ProfileDeleteCancel(r.Context())
+}
+
+// ProfileLogins returns the current user's login history.
+// @Summary Login history
+// @Description Returns successful and failed sign-in attempts on the account, newest first, with the IP, user agent and approximate location. Pass next_before_id as before_id to page back.
+// @Tags Identity, Profile Security
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param before_id query string false "Only return entries older than this id"
+// @Success 200 {object} router.successResponse{data=ProfileLoginsResponse} "Login history"
+// @Failure 400 {object} router.errorResponse "Invalid query parameters"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/profile/logins [get]
+func (h *HTTPEndpoint) ProfileLogins(r *router.Request) (any, error) {
+	limit, err := r.GetQueryInt32("limit")
+	if err != nil {
+		return nil, err
+	}
+
+	beforeID, err := r.GetQueryInt64("before_id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.ProfileLogins(r.Context(), usecase.ProfileLoginsInput{
+		Limit:    limit,
+		BeforeID: beforeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logins := make([]LoginEventResponse, 0, len(resp.Logins))
+	for _, ev := range resp.Logins {
+		logins = append(logins, LoginEventResponse{
+			ID:            ev.ID,
+			Success:       ev.Success,
+			Method:        ev.Method.String(),
+			FailureReason: ev.FailureReason,
+			IP:            ev.IP,
+			UserAgent:     ev.UserAgent,
+			Location: LoginLocationResponse{
+				Country: ev.Location.Country,
+				Region:  ev.Location.Region,
+				City:    ev.Location.City,
+			},
+			CreatedAt: ev.CreatedAt,
+		})
+	}
+
+	return ProfileLoginsResponse{
+		Logins:       logins,
+		NextBeforeID: resp.NextBeforeID,
+	}, nil
+}
+
+// UserList returns a list of users with optional filters.
+// @Summary List users
+// @Description Returns a paginated list of users with optional search and status filters.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Produce json
+// @Param search query string false "Search by email or full name"
+// @Param sort_by query string false "Sort by email, full name and etc."
+// @Param sort_order query string false "Sort order asc or desc"
+// @Param status query []int false "Filter by statuses (1=unverified|2=active|3=banned|4=deleted)"
+// @Param date_from query string false "Filter by created_at >= date_from (RFC3339)"
+// @Param date_to query string false "Filter by created_at <= date_to (RFC3339)"
+// @Param size query int false "Pagination size"
+// @Param page query int false "Pagination page"
+// @Success 200 {object} router.successResponse{data=UsersResponse} "User list"
+// @Failure 400 {object} router.errorResponse "Invalid query parameters"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/users [get]
+func (h *HTTPEndpoint) UserList(r *router.Request) (any, error) {
+	size, err := r.GetQueryInt32("size")
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := r.GetQueryInt32("page")
+	if err != nil {
+		return nil, err
+	}
+
+	dateFrom, err := r.GetQueryDate("date_from", time.RFC3339)
+	if err != nil {
+		return nil, err
+	}
+
+	dateTo, err := r.GetQueryDate("date_to", time.RFC3339)
+	if err != nil {
+		return nil, err
+	}
+
+	if !dateFrom.IsZero() && !dateTo.IsZero() && dateFrom.After(dateTo) {
+		return nil, goerror.NewInvalidFormat("date_from must be before date_to")
+	}
+
+	resp, err := h.uc.UserList(r.Context(), usecase.UserListInput{
+		Search:    r.GetQuery("search"),
+		Statuses:  r.GetQueries("status"),
+		SortBy:    r.GetQuery("sort_by"),
+		SortOrder: r.GetQuery("sort_order"),
+		DateFrom:  dateFrom,
+		DateTo:    dateTo,
+		Size:      size,
+		Page:      page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]UserResponse, 0, len(resp.Users))
+	for _, item := range resp.Users {
+		users = append(users, UserResponse{
+			ID:        item.ID,
+			Email:     item.Email,
+			FullName:  item.FullName,
+			AvatarURL: item.AvatarURL,
+			Status:    item.Status,
+			UpdateAt:  item.UpdatedAt,
+			Version:   valueobject.VersionOf(item.UpdatedAt),
+		})
+	}
+
+	return UsersResponse{
+		total: resp.Total,
+		size:  resp.Size,
+		page:  resp.Page,
+		Users: users,
+	}, nil
+}
+
+// @Summary Get user detail
+// @Description Returns user details for a given user ID.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} router.successResponse{data=UserDetailResponse} "User detail"
+// @Failure 400 {object} router.errorResponse "Invalid path parameter"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "User not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/users/{id} [get]
+func (h *HTTPEndpoint) UserDetail(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.UserDetail(r.Context(), usecase.UserDetailInput{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return UserDetailResponse{User: UserResponse{
+		ID:        resp.User.ID,
+		Email:     resp.User.Email,
+		FullName:  resp.User.FullName,
+		AvatarURL: resp.User.AvatarURL,
+		Status:    resp.User.Status,
+		UpdateAt:  resp.User.UpdatedAt,
+		Version:   valueobject.VersionOf(resp.User.UpdatedAt),
+	}}, nil
+}
+
+// @Summary Create user
+// @Description Creates a new user.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body UserCreateRequest true "User creation payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 409 {object} router.errorResponse "Email already registered"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/users [post]
+func (h *HTTPEndpoint) UserCreate(r *router.Request) (any, error) {
+	var req UserCreateRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	if err := h.uc.UserCreate(r.Context(), usecase.UserCreateInput{
+		Email:    req.Email,
+		Password: req.Password,
+		FullName: req.FullName,
+		Status:   req.Status,
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// @Summary Invite user
+// @Description Creates a pending user and emails an invitation link to choose a password. Inviting an address whose account is still pending sends a new link and invalidates the previous one.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body UserInviteRequest true "User invitation payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 409 {object} router.errorResponse "Email already registered"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/users/invite [post]
+func (h *HTTPEndpoint) UserInvite(r *router.Request) (any, error) {
+	var req UserInviteRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.UserInvite(r.Context(), usecase.UserInviteInput{
+		Email:    req.Email,
+		FullName: req.FullName,
+	})
+}
+
+// UserInviteAccept activates an invited account with the password chosen by the invitee.
+// @Summary Accept invitation
+// @Description Sets the password of an invited user using the emailed invitation token and activates the account.
+// @Tags Identity, Authentication
+// @Accept json
+// @Param request body UserInviteAcceptRequest true "Accept invitation payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Invalid or expired invitation token"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/invite/accept [post]
+func (h *HTTPEndpoint) UserInviteAccept(r *router.Request) (any, error) {
+	var req UserInviteAcceptRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.UserInviteAccept(r.Context(), usecase.UserInviteAcceptInput{
+		ChallengeToken: req.ChallengeToken,
+		Password:       req.Password,
+	})
+}
+
+// @Summary Update user
+// @Description Updates a user by ID. Send the version read with the user as If-Match or expected_version to refuse the update when someone else changed the user meanwhile.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param If-Match header string false "Expected user version"
+// @Param request body UserUpdateRequest true "User update payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "User not found"
+// @Failure 409 {object} router.errorResponse "Email already registered, or the user changed since the expected version (reason version_mismatch)"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/users/{id} [put]
+func (h *HTTPEndpoint) UserUpdate(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	var req UserUpdateRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	version, err := r.ExpectedVersion(req.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.uc.UserUpdate(r.Context(), usecase.UserUpdateInput{
+		ID:                id,
+		Email:             req.Email,
+		Password:          req.Password,
+		FullName:          req.FullName,
+		Status:            req.Status,
+		MustResetPassword: req.MustResetPassword,
+		MFARequired:       req.MFARequired,
+		ExpectedVersion:   version,
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// @Summary Delete user
+// @Description Deletes a user by ID.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid path parameter"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden, or recent authentication required (reason step_up_required)"
+// @Failure 404 {object} router.errorResponse "User not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/users/{id} [delete]
+func (h *HTTPEndpoint) UserDelete(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.uc.UserDelete(r.Context(), usecase.UserDeleteInput{ID: id}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// @Summary Get user MFA
+// @Description Returns the MFA factors registered by a user. Every lookup is recorded in the audit log.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} router.successResponse{data=UserMFAResponse} "User MFA factors"
+// @Failure 400 {object} router.errorResponse "Invalid path parameter"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "User not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/users/{id}/mfa [get]
+func (h *HTTPEndpoint) UserMFA(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.UserMFA(r.Context(), usecase.UserMFAInput{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	factors := make([]UserMFAFactorResponse, 0, len(resp.Factors))
+	for _, f := range resp.Factors {
+		factors = append(factors, UserMFAFactorResponse{
+			ID:           f.ID,
+			Type:         f.Type.String(),
+			FriendlyName: f.FriendlyName,
+			IsVerified:   f.IsVerified,
+			LastUsedAt:   f.LastUsedAt,
+			CreatedAt:    f.CreatedAt,
+		})
+	}
+
+	return UserMFAResponse{
+		Factors:              factors,
+		BackupCodesRemaining: resp.BackupCodesRemaining,
+	}, nil
+}
+
+// @Summary Revoke user MFA
+// @Description Removes every MFA factor and backup code of a user after their identity was verified by support. The action is audited and the user is notified.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Accept json
+// @Param id path int true "User ID"
+// @Param request body UserMFARevokeRequest true "Revocation payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "User not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/users/{id}/mfa [delete]
+func (h *HTTPEndpoint) UserMFARevoke(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	var req UserMFARevokeRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	if err := h.uc.UserMFARevoke(r.Context(), usecase.UserMFARevokeInput{
+		ID:     id,
+		Reason: req.Reason,
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// @Summary Impersonate user
+// @Description Issues a short-lived access token for another user so support staff can reproduce what they see. The token carries an "act" claim naming the caller and "impersonated": true; no refresh token is issued and the action is audit logged. Accounts holding any management, admin or SCIM permission cannot be impersonated.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body UserImpersonateRequest true "Impersonation payload"
+// @Success 200 {object} router.successResponse{data=UserImpersonateResponse} "Impersonation token"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden or privileged target"
+// @Failure 404 {object} router.errorResponse "User not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/users/{id}/impersonate [post]
+func (h *HTTPEndpoint) UserImpersonate(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	var req UserImpersonateRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	out, err := h.uc.UserImpersonate(r.Context(), usecase.UserImpersonateInput{
+		ID:     id,
+		Reason: req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return UserImpersonateResponse{
+		AccessToken: out.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   out.ExpiresIn,
+	}, nil
+}
+
+// @Summary Export users
+// @Description Returns user list for export with optional filters. With format=csv or format=xlsx, or an Accept header asking for one of them, the users are streamed as a file download instead.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Response format: json (default), csv or xlsx"
+// @Param search query string false "Search by email or full name"
+// @Param status query []int false "Filter by user status"
+// @Param sort_by query string false "Sort by email, full name and etc."
+// @Param sort_order query string false "Sort order: asc, desc"
+// @Param date_from query string false "Filter by created_at >= date_from (RFC3339)"
+// @Param date_to query string false "Filter by created_at <= date_to (RFC3339)"
+// @Success 200 {object} router.successResponse{data=UsersResponse} "User export"
+// @Success 200 {string} string "CSV or XLSX file"
+// @Failure 400 {object} router.errorResponse "Invalid query parameter"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Failure 503 {object} router.errorResponse "Too many exports in progress"
+// @Router /api/v1/identity/users-export [get]
+func (h *HTTPEndpoint) UserExport(r *router.Request) (any, error) {
+	dateFrom, err := r.GetQueryDate("date_from", time.RFC3339)
+	if err != nil {
+		return nil, err
+	}
+
+	dateTo, err := r.GetQueryDate("date_to", time.RFC3339)
+	if err != nil {
+		return nil, err
+	}
+
+	if !dateFrom.IsZero() && !dateTo.IsZero() && dateFrom.After(dateTo) {
+		return nil, goerror.NewInvalidFormat("date_from must be before date_to")
+	}
+
+	format, err := userExportFormat(r)
+	if err != nil {
+		return nil, err
+	}
+
+	in := usecase.UserExportInput{
+		Search:    r.GetQuery("search"),
+		Statuses:  r.GetQueries("status"),
+		SortBy:    r.GetQuery("sort_by"),
+		SortOrder: r.GetQuery("sort_order"),
+		DateFrom:  dateFrom,
+		DateTo:    dateTo,
+	}
+
+	if format != "" {
+		return h.userExportFile(r, in, format)
+	}
+
+	resp, err := h.uc.UserExport(r.Context(), in)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]UserResponse, 0, len(resp.Users))
+	for _, item := range resp.Users {
+		users = append(users, UserResponse{
+			ID:        item.ID,
+			Email:     item.Email,
+			FullName:  item.FullName,
+			AvatarURL: item.AvatarURL,
+			Status:    item.Status,
+			UpdateAt:  item.UpdatedAt,
+			Version:   valueobject.VersionOf(item.UpdatedAt),
+		})
+	}
+
+	return UserExportResponse{Users: users}, nil
+}
+
+func (h *HTTPEndpoint) userExportFile(r *router.Request, in usecase.UserExportInput, format tabular.Format) (any, error) {
+	users, err := h.uc.UserExportStream(r.Context(), in)
+	if err != nil {
+		return nil, err
+	}
+
+	return &router.File{
+		Name:        "users-" + time.Now().UTC().Format("20060102T150405Z") + "." + string(format),
+		ContentType: tabular.ContentType(format),
+		Write: func(w io.Writer) error {
+			tw, err := tabular.NewWriter(w, format)
+			if err != nil {
+				return err
+			}
+
+			if err := tw.WriteRow(usecase.UserExportHeader); err != nil {
+				return err
+			}
+
+			for user, err := range users {
+				if err != nil {
+					return err
+				}
+				if err := tw.WriteRow(usecase.UserExportRecord(user)); err != nil {
+					return err
+				}
+			}
+
+			return tw.Close()
+		},
+	}, nil
+}
+
+// userExportFormat reads the file format from the format query parameter or, without it,
+// the Accept header. An empty format means the JSON response.
+func userExportFormat(r *router.Request) (tabular.Format, error) {
+	switch format := strings.ToLower(r.GetQuery("format")); format {
+	case "json":
+		return "", nil
+	case string(tabular.FormatCSV), string(tabular.FormatXLSX):
+		return tabular.Format(format), nil
+	case "":
+	default:
+		return "", goerror.NewInvalidFormat("format must be json, csv or xlsx")
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "text/csv"):
+		return tabular.FormatCSV, nil
+	case strings.Contains(accept, tabular.ContentType(tabular.FormatXLSX)):
+		return tabular.FormatXLSX, nil
+	default:
+		return "", nil
+	}
+}
+
+// @Summary Queue user export
+// @Description Queues a CSV or XLSX export of the users matching the filters, for exports too large to stream in one request. Poll the returned job until it completes.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body UserExportJobCreateRequest true "User export job payload"
+// @Success 200 {object} router.successResponse{data=UserExportJobResponse} "Queued export job"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/exports [post]
+func (h *HTTPEndpoint) UserExportJobCreate(r *router.Request) (any, error) {
+	var req UserExportJobCreateRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	if !req.DateFrom.IsZero() && !req.DateTo.IsZero() && req.DateFrom.After(req.DateTo) {
+		return nil, goerror.NewInvalidFormat("date_from must be before date_to")
+	}
+
+	statuses := make([]string, 0, len(req.Status))
+	for _, status := range req.Status {
+		statuses = append(statuses, strconv.Itoa(int(status)))
+	}
+
+	resp, err := h.uc.UserExportJobCreate(r.Context(), usecase.UserExportJobCreateInput{
+		Format: tabular.Format(strings.ToLower(req.Format)),
+		Filter: usecase.UserExportInput{
+			Search:    req.Search,
+			Statuses:  statuses,
+			SortBy:    req.SortBy,
+			SortOrder: req.SortOrder,
+			DateFrom:  req.DateFrom,
+			DateTo:    req.DateTo,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserExportJobResponse(resp), nil
+}
+
+// @Summary Get user export job
+// @Description Returns the status and progress of an export job queued by the caller, with a short-lived download link once it completed.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Export job ID"
+// @Success 200 {object} router.successResponse{data=UserExportJobResponse} "Export job"
+// @Failure 400 {object} router.errorResponse "Invalid export job ID"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Export job not found"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/exports/{id} [get]
+func (h *HTTPEndpoint) UserExportJobGet(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.UserExportJobGet(r.Context(), usecase.UserExportJobGetInput{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserExportJobResponse(resp), nil
+}
+
+func toUserExportJobResponse(out *usecase.UserExportJobOutput) UserExportJobResponse {
+	resp := UserExportJobResponse{
+		ID:          out.Job.ID,
+		Format:      out.Job.Format,
+		Status:      out.Job.Status.String(),
+		RowsTotal:   out.Job.RowsTotal,
+		RowsWritten: out.Job.RowsWritten,
+		Error:       out.Job.Error,
+		URL:         out.URL,
+		CreatedAt:   out.Job.CreatedAt,
+		UpdatedAt:   out.Job.UpdatedAt,
+		CompletedAt: out.Job.CompletedAt,
+	}
+	if !out.ExpiresAt.IsZero() {
+		resp.URLExpiresAt = &out.ExpiresAt
+	}
+
+	return resp
+}
+
+// @Summary Import users
+// @Description Imports users in bulk.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body UserImportRequest true "User import payload"
+// @Success 200 {object} router.successResponse{data=UserImportResponse} "User import result"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 409 {object} router.errorResponse "Email already registered"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Failure 503 {object} router.errorResponse "Too many imports in progress"
+// @Router /api/v1/identity/users-import [post]
+func (h *HTTPEndpoint) UserImport(r *router.Request) (any, error) {
+	var req UserImportRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	users := make([]usecase.UserImportUserInput, 0, len(req))
+	for _, item := range req {
+		users = append(users, usecase.UserImportUserInput{
+			Email:    item.Email,
+			Password: item.Password,
+			FullName: item.FullName,
+			Status:   item.Status,
+		})
+	}
+
+	resp, err := h.uc.UserImport(r.Context(), usecase.UserImportInput{Users: users})
+	if err != nil {
+		return nil, err
+	}
+
+	return UserImportResponse{
+		Created: resp.Created,
+		Updated: resp.Updated,
+	}, nil
+}
+
+// @Summary Import users from a file
+// @Description Imports users from a CSV or XLSX file whose first row names the email, password, full_name and status columns. Invalid rows are skipped and reported; valid rows are saved in batches.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX file"
+// @Success 200 {object} router.successResponse{data=UserImportFileResponse} "User import result"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Failure 503 {object} router.errorResponse "Too many imports in progress"
+// @Router /api/v1/identity/users-import/file [post]
+func (h *HTTPEndpoint) UserImportFile(r *router.Request) (any, error) {
+	ctx := r.Context()
+
+	part, err := r.StreamSingleFilePart("file")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := part.Close(); err != nil {
+			slog.ErrorContext(ctx, "failed to close file", "error", err)
+		}
+	}()
+
+	format, err := tabular.DetectFormat(part.FileName(), part.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, goerror.NewInvalidInput(nil, "file", "file must be a .csv or .xlsx")
+	}
+
+	resp, err := h.uc.UserImportFile(ctx, usecase.UserImportFileInput{Format: format, File: part})
+	if err != nil {
+		return nil, err
+	}
+
+	rowErrors := make([]UserImportRowErrorResponse, 0, len(resp.Errors))
+	for _, item := range resp.Errors {
+		rowErrors = append(rowErrors, UserImportRowErrorResponse{
+			Row:     item.Row,
+			Field:   item.Field,
+			Message: item.Message,
+		})
+	}
+
+	return UserImportFileResponse{
+		Created:   resp.Created,
+		Updated:   resp.Updated,
+		Failed:    resp.Failed,
+		Truncated: resp.Truncated,
+		Errors:    rowErrors,
+	}, nil
+}
+
+// @Summary Get user effective permissions
+// @Description Returns the roles and effective object/action matrix of a user, including permissions inherited through roles.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} router.successResponse{data=UserPermissionsResponse} "User permissions"
+// @Failure 400 {object} router.errorResponse "Invalid path parameter"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "User not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/users/{id}/permissions [get]
+func (h *HTTPEndpoint) UserPermissions(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.UserPermissions(r.Context(), usecase.UserPermissionsInput{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	roles := resp.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	return UserPermissionsResponse{
+		Roles:       roles,
+		Permissions: resp.Permissions,
+	}, nil
+}
+
+// @Summary Update user roles
+// @Description Replaces the roles assigned directly to a user. An empty list removes every role. The change is audited.
+// @Tags Identity, Management Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body UserRolesRequest true "Roles payload"
+// @Success 200 {object} router.successResponse{data=UserRolesResponse} "Assigned roles"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
-// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 403 {object} router.errorResponse "Forbidden or protected role"
+// @Failure 404 {object} router.errorResponse "User not found"
+// @Failure 422 {object} router.errorResponse "Validation error or unknown role"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/mfa/totp/setup [post]
-func (h *HTTPEndpoint) TOTPSetup(r *router.Request) (any, error) {
-	var req TOTPSetupRequest
+// @Router /api/v1/identity/users/{id}/roles [put]
+func (h *HTTPEndpoint) UserRolesUpdate(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	var req UserRolesRequest
 	if err := r.DecodeBody(&req); err != nil {
 		return nil, err
 	}
 
-	resp, err := h.uc.TOTPSetup(r.Context(), usecase.TOTPSetupInput{
-		FriendlyName:    req.FriendlyName,
-		CurrentPassword: req.CurrentPassword,
+	resp, err := h.uc.UserRolesUpdate(r.Context(), usecase.UserRolesUpdateInput{
+		ID:    id,
+		Roles: req.Roles,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return TOTPSetupResponse{
-		ChallengeToken: resp.ChallengeToken,
-		Key:            resp.Key,
-		URI:            resp.URI,
+	roles := resp.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	return UserRolesResponse{Roles: roles}, nil
+}
+
+// @Summary List roles
+// @Description Returns every role with its member and direct permission counts.
+// @Tags Identity, Management Roles
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=RolesResponse} "Role list"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/roles [get]
+func (h *HTTPEndpoint) RoleList(r *router.Request) (any, error) {
+	resp, err := h.uc.RoleList(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]RoleSummaryResponse, 0, len(resp.Roles))
+	for _, item := range resp.Roles {
+		roles = append(roles, RoleSummaryResponse{
+			Name:        item.Name,
+			Members:     item.Members,
+			Permissions: item.Permissions,
+		})
+	}
+
+	return RolesResponse{Roles: roles}, nil
+}
+
+// @Summary Get role
+// @Description Returns a role with its direct and effective permissions and the IDs of its members.
+// @Tags Identity, Management Roles
+// @Security BearerAuth
+// @Produce json
+// @Param role path string true "Role name"
+// @Success 200 {object} router.successResponse{data=RoleDetailResponse} "Role detail"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Role not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/roles/{role} [get]
+func (h *HTTPEndpoint) RoleDetail(r *router.Request) (any, error) {
+	resp, err := h.uc.RoleDetail(r.Context(), usecase.RoleDetailInput{Role: r.GetParam("role")})
+	if err != nil {
+		return nil, err
+	}
+
+	memberIDs := make([]string, 0, len(resp.MemberIDs))
+	for _, id := range resp.MemberIDs {
+		memberIDs = append(memberIDs, strconv.FormatInt(id, 10))
+	}
+
+	return RoleDetailResponse{
+		Name:                 resp.Name,
+		Permissions:          resp.Permissions,
+		EffectivePermissions: resp.EffectivePermissions,
+		MemberIDs:            memberIDs,
 	}, nil
 }
 
-// TOTPConfirm verifies a TOTP code to activate the factor.
-// @Summary Confirm TOTP
-// @Description Verifies the TOTP code and activates the MFA factor.
-// @Tags Identity, Profile Security
+// @Summary Create role
+// @Description Creates a role with its permissions.
+// @Tags Identity, Management Roles
 // @Security BearerAuth
 // @Accept json
-// @Param request body TOTPConfirmRequest true "TOTP confirmation payload"
+// @Param request body RoleCreateRequest true "Role payload"
 // @Success 204 "No Content"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
-// @Failure 404 {object} router.errorResponse "MFA factor not found"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 409 {object} router.errorResponse "Role already exists"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/mfa/totp/confirm [post]
-func (h *HTTPEndpoint) TOTPConfirm(r *router.Request) (any, error) {
-	var req TOTPConfirmRequest
+// @Router /api/v1/identity/roles [post]
+func (h *HTTPEndpoint) RoleCreate(r *router.Request) (any, error) {
+	var req RoleCreateRequest
 	if err := r.DecodeBody(&req); err != nil {
 		return nil, err
 	}
 
-	if err := h.uc.TOTPConfirm(r.Context(), usecase.TOTPConfirmInput{
-		ChallengeToken: req.ChallengeToken,
-		Code:           req.Code,
+	if err := h.uc.RoleCreate(r.Context(), usecase.RoleCreateInput{
+		Name:        req.Name,
+		Permissions: toRolePermissions(req.Permissions),
 	}); err != nil {
 		return nil, err
 	}
@@ -368,472 +2949,662 @@ func (h *HTTPEndpoint) TOTPConfirm(r *router.Request) (any, error) {
 	return nil, nil
 }
 
-// BackupCode rotates backup codes for the current user.
-// @Summary Rotate backup codes
-// @Description Generates a new set of recovery codes for the authenticated user.
-// @Tags Identity, Profile Security
+// @Summary Update role
+// @Description Replaces every permission granted directly to a role. Members keep the role.
+// @Tags Identity, Management Roles
 // @Security BearerAuth
 // @Accept json
-// @Produce json
-// @Param request body BackupCodeRequest true "Backup code rotation payload"
-// @Success 200 {object} router.successResponse{data=BackupCodeResponse} "Backup codes rotated"
+// @Param role path string true "Role name"
+// @Param request body RoleUpdateRequest true "Role payload"
+// @Success 204 "No Content"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Role not found"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/mfa/backup_code/rotate [post]
-func (h *HTTPEndpoint) BackupCode(r *router.Request) (any, error) {
-	var req BackupCodeRequest
+// @Router /api/v1/identity/roles/{role} [put]
+func (h *HTTPEndpoint) RoleUpdate(r *router.Request) (any, error) {
+	var req RoleUpdateRequest
 	if err := r.DecodeBody(&req); err != nil {
 		return nil, err
 	}
 
-	resp, err := h.uc.BackupCode(r.Context(), usecase.BackupCodeInput{CurrentPassword: req.CurrentPassword})
-	if err != nil {
+	if err := h.uc.RoleUpdate(r.Context(), usecase.RoleUpdateInput{
+		Role:        r.GetParam("role"),
+		Permissions: toRolePermissions(req.Permissions),
+	}); err != nil {
 		return nil, err
 	}
 
-	return &BackupCodeResponse{RecoveryCodes: resp.RecoveryCodes}, nil
+	return nil, nil
 }
 
-// ProfileUpdate updates the current user's profile information.
-// @Summary Update profile
-// @Description Updates profile details for the authenticated user.
-// @Tags Identity, Profile
+// @Summary Delete role
+// @Description Deletes a role, its permissions and every assignment of it. Protected roles cannot be deleted.
+// @Tags Identity, Management Roles
 // @Security BearerAuth
-// @Accept json
-// @Param request body UpdateProfileRequest true "Profile update payload"
+// @Param role path string true "Role name"
 // @Success 204 "No Content"
-// @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden or protected role"
+// @Failure 404 {object} router.errorResponse "Role not found"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/profile [put]
-func (h *HTTPEndpoint) ProfileUpdate(r *router.Request) (any, error) {
-	var req UpdateProfileRequest
-	if err := r.DecodeBody(&req); err != nil {
+// @Router /api/v1/identity/roles/{role} [delete]
+func (h *HTTPEndpoint) RoleDelete(r *router.Request) (any, error) {
+	if err := h.uc.RoleDelete(r.Context(), usecase.RoleDeleteInput{Role: r.GetParam("role")}); err != nil {
 		return nil, err
 	}
 
-	return nil, h.uc.ProfileUpdate(r.Context(), usecase.ProfileUpdateInput{FullName: req.FullName})
+	return nil, nil
 }
 
-// ProfileUpdateAvatar updates the current user's avatar URL.
-// @Summary Update profile avatar
-// @Description Updates avatar for the authenticated user.
-// @Tags Identity, Profile
+// @Summary Get role permissions
+// @Description Returns the permissions granted directly to a role and its effective permissions including inherited roles.
+// @Tags Identity, Management Roles
 // @Security BearerAuth
-// @Accept multipart/form-data
-// @Param avatar formData file true "Avatar image"
+// @Produce json
+// @Param role path string true "Role name"
+// @Success 200 {object} router.successResponse{data=RolePermissionsResponse} "Role permissions"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Role not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/roles/{role}/permissions [get]
+func (h *HTTPEndpoint) RolePermissions(r *router.Request) (any, error) {
+	resp, err := h.uc.RolePermissions(r.Context(), usecase.RolePermissionsInput{Role: r.GetParam("role")})
+	if err != nil {
+		return nil, err
+	}
+
+	return RolePermissionsResponse{
+		Permissions:          resp.Permissions,
+		EffectivePermissions: resp.EffectivePermissions,
+	}, nil
+}
+
+// @Summary Add role permission
+// @Description Grants a permission to a role.
+// @Tags Identity, Management Roles
+// @Security BearerAuth
+// @Accept json
+// @Param role path string true "Role name"
+// @Param request body RolePermissionRequest true "Permission payload"
 // @Success 204 "No Content"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Role not found"
+// @Failure 409 {object} router.errorResponse "Permission already granted"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/profile/avatar [put]
-func (h *HTTPEndpoint) ProfileUpdateAvatar(r *router.Request) (any, error) {
-	ctx := r.Context()
+// @Router /api/v1/identity/roles/{role}/permissions [post]
+func (h *HTTPEndpoint) RolePermissionAdd(r *router.Request) (any, error) {
+	var req RolePermissionRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
 
-	file, err := r.StreamSingleFile("avatar")
-	if err != nil {
+	if err := h.uc.RolePermissionAdd(r.Context(), usecase.RolePermissionInput{
+		Role:   r.GetParam("role"),
+		Object: req.Object,
+		Action: req.Action,
+	}); err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			slog.ErrorContext(ctx, "failed to close file", "error", err)
-		}
-	}()
 
-	head := make([]byte, 512)
-	n, err := file.Read(head)
-	if err != nil && !errors.Is(err, io.EOF) {
-		return nil, goerror.NewInvalidFormat()
+	return nil, nil
+}
+
+// @Summary Remove role permission
+// @Description Revokes a permission from a role.
+// @Tags Identity, Management Roles
+// @Security BearerAuth
+// @Param role path string true "Role name"
+// @Param object query string true "Permission object"
+// @Param action query string true "Permission action"
+// @Success 204 "No Content"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden or last permission of a protected role"
+// @Failure 404 {object} router.errorResponse "Permission not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/roles/{role}/permissions [delete]
+func (h *HTTPEndpoint) RolePermissionRemove(r *router.Request) (any, error) {
+	if err := h.uc.RolePermissionRemove(r.Context(), usecase.RolePermissionInput{
+		Role:   r.GetParam("role"),
+		Object: r.GetQuery("object"),
+		Action: r.GetQuery("action"),
+	}); err != nil {
+		return nil, err
 	}
 
-	return nil, h.uc.ProfileUpdateAvatar(ctx, usecase.ProfileUpdateAvatarInput{
-		File:        io.MultiReader(bytes.NewReader(head[:n]), file),
-		ContentType: http.DetectContentType(head[:n]),
-	})
+	return nil, nil
 }
 
-// Profile retrieves the current user's profile details.
-// @Summary Get profile
-// @Description Returns profile information for the authenticated user.
-// @Tags Identity, Profile
+// @Summary Assign role
+// @Description Assigns a role to a user.
+// @Tags Identity, Management Roles
 // @Security BearerAuth
-// @Produce json
-// @Success 200 {object} router.successResponse{data=ProfileResponse} "Profile result"
+// @Accept json
+// @Param role path string true "Role name"
+// @Param request body RoleMemberRequest true "Member payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Role or user not found"
+// @Failure 409 {object} router.errorResponse "Role already assigned"
+// @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/profile [get]
-func (h *HTTPEndpoint) Profile(r *router.Request) (any, error) {
-	resp, err := h.uc.Profile(r.Context(), usecase.ProfileInput{})
-	if err != nil {
+// @Router /api/v1/identity/roles/{role}/users [post]
+func (h *HTTPEndpoint) RoleMemberAdd(r *router.Request) (any, error) {
+	var req RoleMemberRequest
+	if err := r.DecodeBody(&req); err != nil {
 		return nil, err
 	}
 
-	return ProfileResponse{
-		ID:        resp.ID,
-		Email:     resp.Email,
-		FullName:  resp.FullName,
-		AvatarURL: resp.AvatarURL,
-		Status:    resp.Status,
-	}, nil
+	if err := h.uc.RoleMemberAdd(r.Context(), usecase.RoleMemberInput{
+		Role:   r.GetParam("role"),
+		UserID: req.UserID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
 }
 
-// @Summary Get profile permissions
-// @Tags Identity, Profile Security
+// @Summary Unassign role
+// @Description Removes a role from a user. Nobody can remove themselves from a protected role.
+// @Tags Identity, Management Roles
 // @Security BearerAuth
-// @Success 200 {object} router.successResponse{data=ProfilePermissionsResponse} "Permissions list"
+// @Param role path string true "Role name"
+// @Param user_id path int true "User ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid path parameter"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden or protected role"
+// @Failure 404 {object} router.errorResponse "Role not assigned"
+// @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/profile/permissions [get]
-func (h *HTTPEndpoint) ProfilePermissions(r *router.Request) (any, error) {
-	resp, err := h.uc.ProfilePermissions(r.Context())
+// @Router /api/v1/identity/roles/{role}/users/{user_id} [delete]
+func (h *HTTPEndpoint) RoleMemberRemove(r *router.Request) (any, error) {
+	userID, err := r.GetParamInt64("user_id")
 	if err != nil {
 		return nil, err
 	}
 
-	if resp == nil {
-		resp = map[string][]string{}
+	if err := h.uc.RoleMemberRemove(r.Context(), usecase.RoleMemberInput{
+		Role:   r.GetParam("role"),
+		UserID: userID,
+	}); err != nil {
+		return nil, err
 	}
 
-	return ProfilePermissionsResponse{Permissions: resp}, nil
+	return nil, nil
 }
 
-// @Summary Get profile MFA settings
-// @Description Returns MFA settings for the authenticated user.
-// @Tags Identity, Profile Security
+func toRolePermissions(in []RolePermissionRequest) []usecase.RolePermission {
+	out := make([]usecase.RolePermission, 0, len(in))
+	for _, p := range in {
+		out = append(out, usecase.RolePermission{Object: p.Object, Action: p.Action})
+	}
+	return out
+}
+
+// ServiceAccountList returns every service account.
+// @Summary List service accounts
+// @Description Returns the service accounts with their roles. Client secrets are never shown again after creation.
+// @Tags Identity, Service Accounts
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {object} router.successResponse{data=ProfileSettingMFAResponse} "MFA settings"
+// @Success 200 {object} router.successResponse{data=ServiceAccountsResponse} "Service accounts"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/profile/settings/mfa [get]
-func (h *HTTPEndpoint) ProfileSettingMFA(r *router.Request) (any, error) {
-	resp, err := h.uc.ProfileSettingMFA(r.Context())
+// @Router /api/v1/identity/service-accounts [get]
+func (h *HTTPEndpoint) ServiceAccountList(r *router.Request) (any, error) {
+	out, err := h.uc.ServiceAccountList(r.Context())
 	if err != nil {
 		return nil, err
 	}
 
-	return ProfileSettingMFAResponse{
-		TOTPEnabled:       resp.TOTPEnabled,
-		BackupCodeEnabled: resp.BackupCodeEnabled,
-		SMSEnabled:        resp.SMSEnabled,
-	}, nil
+	resp := make([]ServiceAccountResponse, 0, len(out.ServiceAccounts))
+	for _, sa := range out.ServiceAccounts {
+		resp = append(resp, toServiceAccountResponse(sa))
+	}
+
+	return ServiceAccountsResponse{ServiceAccounts: resp}, nil
 }
 
-// UserList returns a list of users with optional filters.
-// @Summary List users
-// @Description Returns a paginated list of users with optional search and status filters.
-// @Tags Identity, Management Users
+// ServiceAccountCreate registers a service account.
+// @Summary Create service account
+// @Description Creates a service account holding the given roles and returns its client ID and secret. The secret is shown only in this response.
+// @Tags Identity, Service Accounts
 // @Security BearerAuth
+// @Accept json
 // @Produce json
-// @Param search query string false "Search by email or full name"
-// @Param sort_by query string false "Sort by email, full name and etc."
-// @Param sort_order query string false "Sort order asc or desc"
-// @Param status query []int false "Filter by statuses (1=unverified|2=active|3=banned|4=deleted)"
-// @Param date_from query string false "Filter by created_at >= date_from (RFC3339)"
-// @Param date_to query string false "Filter by created_at <= date_to (RFC3339)"
-// @Param size query int false "Pagination size"
-// @Param page query int false "Pagination page"
-// @Success 200 {object} router.successResponse{data=UsersResponse} "User list"
-// @Failure 400 {object} router.errorResponse "Invalid query parameters"
+// @Param request body ServiceAccountCreateRequest true "Service account payload"
+// @Success 200 {object} router.successResponse{data=ServiceAccountCreateResponse} "Created service account"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
-// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 403 {object} router.errorResponse "Forbidden or protected role"
+// @Failure 422 {object} router.errorResponse "Validation error or unknown role"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/users [get]
-func (h *HTTPEndpoint) UserList(r *router.Request) (any, error) {
-	size, err := r.GetQueryInt32("size")
-	if err != nil {
+// @Router /api/v1/identity/service-accounts [post]
+func (h *HTTPEndpoint) ServiceAccountCreate(r *router.Request) (any, error) {
+	var req ServiceAccountCreateRequest
+	if err := r.DecodeBody(&req); err != nil {
 		return nil, err
 	}
 
-	page, err := r.GetQueryInt32("page")
+	out, err := h.uc.ServiceAccountCreate(r.Context(), usecase.ServiceAccountCreateInput{
+		Name:  req.Name,
+		Roles: req.Roles,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	dateFrom, err := r.GetQueryDate("date_from", time.RFC3339)
+	return ServiceAccountCreateResponse{
+		ServiceAccountResponse: toServiceAccountResponse(out.ServiceAccount),
+		ClientSecret:           out.ClientSecret,
+	}, nil
+}
+
+// ServiceAccountDelete removes a service account.
+// @Summary Delete service account
+// @Description Deletes a service account and its roles. Tokens already issued to it lose every permission at once.
+// @Tags Identity, Service Accounts
+// @Security BearerAuth
+// @Param id path int true "Service account ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid service account id"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Service account not found"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/service-accounts/{id} [delete]
+func (h *HTTPEndpoint) ServiceAccountDelete(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
 	if err != nil {
 		return nil, err
 	}
 
-	dateTo, err := r.GetQueryDate("date_to", time.RFC3339)
-	if err != nil {
-		return nil, err
+	return nil, h.uc.ServiceAccountDelete(r.Context(), usecase.ServiceAccountDeleteInput{ID: id})
+}
+
+func toServiceAccountResponse(sa entity.ServiceAccount) ServiceAccountResponse {
+	roles := sa.Roles
+	if roles == nil {
+		roles = []string{}
 	}
 
-	if !dateFrom.IsZero() && !dateTo.IsZero() && dateFrom.After(dateTo) {
-		return nil, goerror.NewInvalidFormat("date_from must be before date_to")
+	return ServiceAccountResponse{
+		ID:         sa.ID,
+		Name:       sa.Name,
+		ClientID:   sa.ClientID,
+		Roles:      roles,
+		CreatedBy:  sa.CreatedBy,
+		LastUsedAt: sa.LastUsedAt,
+		CreatedAt:  sa.CreatedAt,
 	}
+}
 
-	resp, err := h.uc.UserList(r.Context(), usecase.UserListInput{
-		Search:    r.GetQuery("search"),
-		Statuses:  r.GetQueries("status"),
-		SortBy:    r.GetQuery("sort_by"),
-		SortOrder: r.GetQuery("sort_order"),
-		DateFrom:  dateFrom,
-		DateTo:    dateTo,
-		Size:      size,
-		Page:      page,
-	})
+// OrgList returns the organizations of the authenticated user.
+// @Summary List my organizations
+// @Description Returns the organizations the authenticated user belongs to, with their role in each.
+// @Tags Identity, Organizations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=OrgsResponse} "Organizations"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/orgs [get]
+func (h *HTTPEndpoint) OrgList(r *router.Request) (any, error) {
+	out, err := h.uc.OrgList(r.Context())
 	if err != nil {
 		return nil, err
 	}
 
-	users := make([]UserResponse, 0, len(resp.Users))
-	for _, item := range resp.Users {
-		users = append(users, UserResponse{
-			ID:        item.ID,
-			Email:     item.Email,
-			FullName:  item.FullName,
-			AvatarURL: item.AvatarURL,
-			Status:    item.Status,
-			UpdateAt:  item.UpdatedAt,
-		})
+	resp := make([]OrgResponse, 0, len(out.Organizations))
+	for _, org := range out.Organizations {
+		resp = append(resp, toOrgResponse(org))
 	}
 
-	return UsersResponse{
-		total: resp.Total,
-		size:  resp.Size,
-		page:  resp.Page,
-		Users: users,
-	}, nil
+	return OrgsResponse{Organizations: resp}, nil
 }
 
-// @Summary Get user detail
-// @Description Returns user details for a given user ID.
-// @Tags Identity, Management Users
+// OrgCreate creates an organization.
+// @Summary Create organization
+// @Description Creates an organization and makes the authenticated user its owner.
+// @Tags Identity, Organizations
 // @Security BearerAuth
+// @Accept json
 // @Produce json
-// @Param id path int true "User ID"
-// @Success 200 {object} router.successResponse{data=UserDetailResponse} "User detail"
-// @Failure 400 {object} router.errorResponse "Invalid path parameter"
+// @Param request body OrgCreateRequest true "Organization payload"
+// @Success 200 {object} router.successResponse{data=OrgResponse} "Created organization"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
 // @Failure 403 {object} router.errorResponse "Forbidden"
-// @Failure 404 {object} router.errorResponse "User not found"
+// @Failure 409 {object} router.errorResponse "Slug already taken"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/users/{id} [get]
-func (h *HTTPEndpoint) UserDetail(r *router.Request) (any, error) {
-	id, err := r.GetParamInt64("id")
-	if err != nil {
+// @Router /api/v1/identity/orgs [post]
+func (h *HTTPEndpoint) OrgCreate(r *router.Request) (any, error) {
+	var req OrgCreateRequest
+	if err := r.DecodeBody(&req); err != nil {
 		return nil, err
 	}
 
-	resp, err := h.uc.UserDetail(r.Context(), usecase.UserDetailInput{ID: id})
+	org, err := h.uc.OrgCreate(r.Context(), usecase.OrgCreateInput{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return UserDetailResponse{User: UserResponse{
-		ID:        resp.User.ID,
-		Email:     resp.User.Email,
-		FullName:  resp.User.FullName,
-		AvatarURL: resp.User.AvatarURL,
-		Status:    resp.User.Status,
-		UpdateAt:  resp.User.UpdatedAt,
-	}}, nil
+	return toOrgResponse(*org), nil
 }
 
-// @Summary Create user
-// @Description Creates a new user.
-// @Tags Identity, Management Users
+// OrgDetail returns one organization.
+// @Summary Get organization
+// @Description Returns an organization the caller is a member of.
+// @Tags Identity, Organizations
 // @Security BearerAuth
-// @Accept json
 // @Produce json
-// @Param request body UserCreateRequest true "User creation payload"
-// @Success 204 "No Content"
-// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Param id path int true "Organization ID"
+// @Success 200 {object} router.successResponse{data=OrgResponse} "Organization"
+// @Failure 400 {object} router.errorResponse "Invalid organization id"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
 // @Failure 403 {object} router.errorResponse "Forbidden"
-// @Failure 409 {object} router.errorResponse "Email already registered"
-// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 404 {object} router.errorResponse "Organization not found"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/users [post]
-func (h *HTTPEndpoint) UserCreate(r *router.Request) (any, error) {
-	var req UserCreateRequest
-	if err := r.DecodeBody(&req); err != nil {
+// @Router /api/v1/identity/orgs/{id} [get]
+func (h *HTTPEndpoint) OrgDetail(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
 		return nil, err
 	}
 
-	if err := h.uc.UserCreate(r.Context(), usecase.UserCreateInput{
-		Email:    req.Email,
-		Password: req.Password,
-		FullName: req.FullName,
-		Status:   req.Status,
-	}); err != nil {
+	org, err := h.uc.OrgDetail(r.Context(), usecase.OrgIDInput{ID: id})
+	if err != nil {
 		return nil, err
 	}
 
-	return nil, nil
+	return toOrgResponse(*org), nil
 }
 
-// @Summary Update user
-// @Description Updates a user by ID.
-// @Tags Identity, Management Users
+// OrgUpdate renames an organization.
+// @Summary Update organization
+// @Description Renames an organization. Needs the owner or admin role in it.
+// @Tags Identity, Organizations
 // @Security BearerAuth
 // @Accept json
-// @Produce json
-// @Param id path int true "User ID"
-// @Param request body UserUpdateRequest true "User update payload"
+// @Param id path int true "Organization ID"
+// @Param request body OrgUpdateRequest true "Organization payload"
 // @Success 204 "No Content"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
 // @Failure 403 {object} router.errorResponse "Forbidden"
-// @Failure 404 {object} router.errorResponse "User not found"
-// @Failure 409 {object} router.errorResponse "Email already registered"
+// @Failure 404 {object} router.errorResponse "Organization not found"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/users/{id} [put]
-func (h *HTTPEndpoint) UserUpdate(r *router.Request) (any, error) {
+// @Router /api/v1/identity/orgs/{id} [put]
+func (h *HTTPEndpoint) OrgUpdate(r *router.Request) (any, error) {
 	id, err := r.GetParamInt64("id")
 	if err != nil {
 		return nil, err
 	}
 
-	var req UserUpdateRequest
+	var req OrgUpdateRequest
 	if err := r.DecodeBody(&req); err != nil {
 		return nil, err
 	}
 
-	if err := h.uc.UserUpdate(r.Context(), usecase.UserUpdateInput{
-		ID:       id,
-		Email:    req.Email,
-		Password: req.Password,
-		FullName: req.FullName,
-		Status:   req.Status,
-	}); err != nil {
+	return nil, h.uc.OrgUpdate(r.Context(), usecase.OrgUpdateInput{ID: id, Name: req.Name})
+}
+
+// OrgDelete removes an organization.
+// @Summary Delete organization
+// @Description Deletes an organization with its memberships. Needs the owner role in it.
+// @Tags Identity, Organizations
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid organization id"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Organization not found"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/orgs/{id} [delete]
+func (h *HTTPEndpoint) OrgDelete(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
 		return nil, err
 	}
 
-	return nil, nil
+	return nil, h.uc.OrgDelete(r.Context(), usecase.OrgIDInput{ID: id})
 }
 
-// @Summary Delete user
-// @Description Deletes a user by ID.
-// @Tags Identity, Management Users
+// OrgToken switches the caller into an organization.
+// @Summary Switch organization
+// @Description Issues an access token scoped to the organization through the org_id claim. The refresh token is unchanged and refreshes into an unscoped token.
+// @Tags Identity, Organizations
 // @Security BearerAuth
-// @Param id path int true "User ID"
-// @Success 204 "No Content"
-// @Failure 400 {object} router.errorResponse "Invalid path parameter"
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param X-Client-Type header string false "Client type used to pick the token lifetime (e.g. web, mobile, service)"
+// @Success 200 {object} router.successResponse{data=OrgTokenResponse} "Scoped access token"
+// @Failure 400 {object} router.errorResponse "Invalid organization id"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
-// @Failure 403 {object} router.errorResponse "Forbidden"
-// @Failure 404 {object} router.errorResponse "User not found"
-// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 403 {object} router.errorResponse "Not a member"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/users/{id} [delete]
-func (h *HTTPEndpoint) UserDelete(r *router.Request) (any, error) {
+// @Router /api/v1/identity/orgs/{id}/token [post]
+func (h *HTTPEndpoint) OrgToken(r *router.Request) (any, error) {
 	id, err := r.GetParamInt64("id")
 	if err != nil {
 		return nil, err
 	}
 
-	if err := h.uc.UserDelete(r.Context(), usecase.UserDeleteInput{ID: id}); err != nil {
+	out, err := h.uc.OrgToken(r.Context(), usecase.OrgTokenInput{
+		ID:         id,
+		ClientType: r.Header.Get(headerClientType),
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return nil, nil
+	return OrgTokenResponse{AccessToken: out.AccessToken}, nil
 }
 
-// @Summary Export users
-// @Description Returns user list for export with optional filters.
-// @Tags Identity, Management Users
+// OrgMemberList returns the members of an organization.
+// @Summary List organization members
+// @Description Returns the members of an organization with their roles.
+// @Tags Identity, Organizations
 // @Security BearerAuth
 // @Produce json
-// @Param search query string false "Search by email or full name"
-// @Param status query []int false "Filter by user status"
-// @Param sort_by query string false "Sort by email, full name and etc."
-// @Param sort_order query string false "Sort order: asc, desc"
-// @Param date_from query string false "Filter by created_at >= date_from (RFC3339)"
-// @Param date_to query string false "Filter by created_at <= date_to (RFC3339)"
-// @Success 200 {object} router.successResponse{data=UsersResponse} "User export"
-// @Failure 400 {object} router.errorResponse "Invalid query parameter"
+// @Param id path int true "Organization ID"
+// @Success 200 {object} router.successResponse{data=OrgMembersResponse} "Members"
+// @Failure 400 {object} router.errorResponse "Invalid organization id"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
 // @Failure 403 {object} router.errorResponse "Forbidden"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/users-export [get]
-func (h *HTTPEndpoint) UserExport(r *router.Request) (any, error) {
-	dateFrom, err := r.GetQueryDate("date_from", time.RFC3339)
+// @Router /api/v1/identity/orgs/{id}/members [get]
+func (h *HTTPEndpoint) OrgMemberList(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
 	if err != nil {
 		return nil, err
 	}
 
-	dateTo, err := r.GetQueryDate("date_to", time.RFC3339)
+	out, err := h.uc.OrgMemberList(r.Context(), usecase.OrgIDInput{ID: id})
 	if err != nil {
 		return nil, err
 	}
 
-	if !dateFrom.IsZero() && !dateTo.IsZero() && dateFrom.After(dateTo) {
-		return nil, goerror.NewInvalidFormat("date_from must be before date_to")
+	resp := make([]OrgMemberResponse, 0, len(out.Members))
+	for _, m := range out.Members {
+		resp = append(resp, OrgMemberResponse{
+			UserID:   m.UserID,
+			Email:    m.Email,
+			FullName: m.FullName,
+			Role:     m.Role.String(),
+			JoinedAt: m.JoinedAt,
+		})
 	}
 
-	resp, err := h.uc.UserExport(r.Context(), usecase.UserExportInput{
-		Search:    r.GetQuery("search"),
-		Statuses:  r.GetQueries("status"),
-		SortBy:    r.GetQuery("sort_by"),
-		SortOrder: r.GetQuery("sort_order"),
-		DateFrom:  dateFrom,
-		DateTo:    dateTo,
-	})
+	return OrgMembersResponse{Members: resp}, nil
+}
+
+// OrgMemberAdd invites a user into an organization.
+// @Summary Invite organization member
+// @Description Emails an invitation to join the organization as owner, admin or member; the user joins once they accept it. The answer is the same whether or not the email belongs to an account. Only owners can invite owners.
+// @Tags Identity, Organizations
+// @Security BearerAuth
+// @Accept json
+// @Param id path int true "Organization ID"
+// @Param request body OrgMemberAddRequest true "Member payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Organization not found"
+// @Failure 409 {object} router.errorResponse "Already a member"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/orgs/{id}/members [post]
+func (h *HTTPEndpoint) OrgMemberAdd(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
 	if err != nil {
 		return nil, err
 	}
 
-	users := make([]UserResponse, 0, len(resp.Users))
-	for _, item := range resp.Users {
-		users = append(users, UserResponse{
-			ID:        item.ID,
-			Email:     item.Email,
-			FullName:  item.FullName,
-			AvatarURL: item.AvatarURL,
-			Status:    item.Status,
-			UpdateAt:  item.UpdatedAt,
-		})
+	var req OrgMemberAddRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
 	}
 
-	return UserExportResponse{Users: users}, nil
+	return nil, h.uc.OrgMemberAdd(r.Context(), usecase.OrgMemberAddInput{
+		OrgID: id,
+		Email: req.Email,
+		Role:  req.Role,
+	})
 }
 
-// @Summary Import users
-// @Description Imports users in bulk.
-// @Tags Identity, Management Users
+// OrgInviteAccept joins the organization the caller was invited into.
+// @Summary Accept organization invitation
+// @Description Makes the authenticated user a member of the organization named by the emailed invitation token, with the invited role. The token only works for the account it was sent to.
+// @Tags Identity, Organizations
 // @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param request body UserImportRequest true "User import payload"
-// @Success 200 {object} router.successResponse{data=UserImportResponse} "User import result"
+// @Param request body OrgInviteAcceptRequest true "Accept invitation payload"
+// @Success 200 {object} router.successResponse{data=OrgResponse} "Joined organization"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Not a user session"
+// @Failure 404 {object} router.errorResponse "Invalid or expired invitation token"
+// @Failure 409 {object} router.errorResponse "Already a member"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/org-invitations/accept [post]
+func (h *HTTPEndpoint) OrgInviteAccept(r *router.Request) (any, error) {
+	var req OrgInviteAcceptRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	org, err := h.uc.OrgInviteAccept(r.Context(), usecase.OrgInviteAcceptInput{
+		ChallengeToken: req.ChallengeToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrgResponse(*org), nil
+}
+
+// OrgMemberUpdate changes the role of a member.
+// @Summary Update organization member
+// @Description Changes the role of a member. Only owners can grant or take away the owner role, and the last owner stays.
+// @Tags Identity, Organizations
+// @Security BearerAuth
+// @Accept json
+// @Param id path int true "Organization ID"
+// @Param user_id path int true "User ID"
+// @Param request body OrgMemberUpdateRequest true "Role payload"
+// @Success 204 "No Content"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
 // @Failure 403 {object} router.errorResponse "Forbidden"
-// @Failure 409 {object} router.errorResponse "Email already registered"
+// @Failure 404 {object} router.errorResponse "Member not found"
+// @Failure 409 {object} router.errorResponse "Last owner"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
-// @Router /api/v1/identity/users-import [post]
-func (h *HTTPEndpoint) UserImport(r *router.Request) (any, error) {
-	var req UserImportRequest
+// @Router /api/v1/identity/orgs/{id}/members/{user_id} [put]
+func (h *HTTPEndpoint) OrgMemberUpdate(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := r.GetParamInt64("user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	var req OrgMemberUpdateRequest
 	if err := r.DecodeBody(&req); err != nil {
 		return nil, err
 	}
 
-	users := make([]usecase.UserImportUserInput, 0, len(req))
-	for _, item := range req {
-		users = append(users, usecase.UserImportUserInput{
-			Email:    item.Email,
-			Password: item.Password,
-			FullName: item.FullName,
-			Status:   item.Status,
-		})
+	return nil, h.uc.OrgMemberUpdate(r.Context(), usecase.OrgMemberUpdateInput{
+		OrgID:  id,
+		UserID: userID,
+		Role:   req.Role,
+	})
+}
+
+// OrgMemberRemove takes a user out of an organization.
+// @Summary Remove organization member
+// @Description Removes a member. Members can always remove themselves to leave; the last owner cannot leave.
+// @Tags Identity, Organizations
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param user_id path int true "User ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid path parameter"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Member not found"
+// @Failure 409 {object} router.errorResponse "Last owner"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/identity/orgs/{id}/members/{user_id} [delete]
+func (h *HTTPEndpoint) OrgMemberRemove(r *router.Request) (any, error) {
+	id, err := r.GetParamInt64("id")
+	if err != nil {
+		return nil, err
 	}
 
-	resp, err := h.uc.UserImport(r.Context(), usecase.UserImportInput{Users: users})
+	userID, err := r.GetParamInt64("user_id")
 	if err != nil {
 		return nil, err
 	}
 
-	return UserImportResponse{
-		Created: resp.Created,
-		Updated: resp.Updated,
-	}, nil
+	return nil, h.uc.OrgMemberRemove(r.Context(), usecase.OrgMemberRemoveInput{OrgID: id, UserID: userID})
+}
+
+func toOrgResponse(org entity.Organization) OrgResponse {
+	return OrgResponse{
+		ID:        org.ID,
+		Name:      org.Name,
+		Slug:      org.Slug,
+		CreatedBy: org.CreatedBy,
+		Role:      org.Role.String(),
+		CreatedAt: org.CreatedAt,
+		UpdatedAt: org.UpdatedAt,
+	}
 }
@@ -4,11 +4,18 @@ import (
 	"time"
 
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 )
 
 type LoginRequest struct {
-	Email    string `json:"email"`
+	Email string `json:"email,omitempty"`
+	// Username may be sent instead of Email when usernames are enabled.
+	Username string `json:"username,omitempty"`
 	Password string `json:"password"`
+	// CaptchaToken may be sent instead of the X-Captcha-Token header.
+	CaptchaToken string `json:"captcha_token,omitempty"`
+	// DeviceToken is the trusted device token returned by the 2FA login, skipping the MFA challenge.
+	DeviceToken string `json:"device_token,omitempty"`
 }
 
 type LoginResponse struct {
@@ -17,12 +24,42 @@ type LoginResponse struct {
 	AvailableMethods []string `json:"available_methods,omitempty"`
 	AccessToken      string   `json:"access_token,omitempty"`
 	RefreshToken     string   `json:"refresh_token,omitempty"`
+	// PasswordResetRequired and MfaSetupRequired hold the login until the password is reset
+	// or a factor is enrolled with challenge_token.
+	PasswordResetRequired bool `json:"password_reset_required,omitempty"`
+	MfaSetupRequired      bool `json:"mfa_setup_required,omitempty"`
+	// CSRFToken replaces RefreshToken when the refresh token is set as a cookie; send it in the
+	// X-CSRF-Token header to refresh or log out.
+	CSRFToken string `json:"csrf_token,omitempty"`
+}
+
+type OAuthAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+// OAuthCallbackRequest carries the query parameters the provider redirected the browser
+// to the app with.
+type OAuthCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+	// Error is set instead of Code when the user or provider declined the sign-in.
+	Error string `json:"error,omitempty"`
+}
+
+type SAMLLoginResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	RelayState       string `json:"relay_state"`
 }
 
 type RegisterRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 	FullName string `json:"full_name"`
+	// Username is optional and only accepted when usernames are enabled.
+	Username string `json:"username,omitempty"`
+	// CaptchaToken may be sent instead of the X-Captcha-Token header.
+	CaptchaToken string `json:"captcha_token,omitempty"`
 }
 
 type RegisterResponse struct{}
@@ -33,6 +70,8 @@ func (RegisterResponse) Message() string {
 
 type RegisterResendRequest struct {
 	Email string `json:"email"`
+	// CaptchaToken may be sent instead of the X-Captcha-Token header.
+	CaptchaToken string `json:"captcha_token,omitempty"`
 }
 
 type RegisterResendResponse struct{}
@@ -43,10 +82,14 @@ func (RegisterResendResponse) Message() string {
 
 type EmailVerifyRequest struct {
 	ChallengeToken string `json:"challenge_token"`
+	Expires        string `json:"exp"`
+	Signature      string `json:"sig"`
 }
 
 type PasswordForgotRequest struct {
 	Email string `json:"email"`
+	// CaptchaToken may be sent instead of the X-Captcha-Token header.
+	CaptchaToken string `json:"captcha_token,omitempty"`
 }
 
 type PasswordForgotResponse struct{}
@@ -65,28 +108,268 @@ type PasswordChangeRequest struct {
 	NewPassword     string `json:"new_password"`
 }
 
+type EmailChangeRequest struct {
+	NewEmail        string `json:"new_email"`
+	CurrentPassword string `json:"current_password"`
+}
+
+type EmailChangeResponse struct{}
+
+func (EmailChangeResponse) Message() string {
+	return "We have sent a confirmation link to the new email address."
+}
+
+type EmailChangeConfirmRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
 type Login2FARequest struct {
 	ChallengeToken string `json:"challenge_token"`
 	Method         string `json:"method"`
 	Code           string `json:"code"`
+	RememberDevice bool   `json:"remember_device,omitempty"`
 }
 
 type Login2FAResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	// PasswordResetRequired means an administrator requires a new password: ChallengeToken
+	// is a password reset token and no tokens are issued.
+	PasswordResetRequired bool   `json:"password_reset_required,omitempty"`
+	ChallengeToken        string `json:"challenge_token,omitempty"`
+
+	AccessToken     string     `json:"access_token"`
+	RefreshToken    string     `json:"refresh_token,omitempty"`
+	DeviceToken     string     `json:"device_token,omitempty"`
+	DeviceExpiresAt *time.Time `json:"device_expires_at,omitempty"`
+	CSRFToken       string     `json:"csrf_token,omitempty"`
+}
+
+type ReauthRequest struct {
+	Password string `json:"password"`
+	Method   string `json:"method,omitempty"`
+	Code     string `json:"code,omitempty"`
+}
+
+type ReauthResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+type Login2FASMSRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
+type Login2FASMSResponse struct {
+	Destination string `json:"destination"`
+}
+
+type LoginMFASetupRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	FriendlyName   string `json:"friendly_name"`
+}
+
+type LoginMFASetupResponse struct {
+	Key string `json:"key"`
+	URI string `json:"uri"`
+}
+
+type LoginMFASetupConfirmRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
 }
 
 type LogoutRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type SessionResponse struct {
+	ID         int64     `json:"id"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	Client     string    `json:"client,omitempty"`
+	DeviceName string    `json:"device_name,omitempty"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	StartedAt  time.Time `json:"started_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type SessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
+
+type TrustedDeviceResponse struct {
+	ID         int64      `json:"id"`
+	IP         string     `json:"ip"`
+	UserAgent  string     `json:"user_agent"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+}
+
+type TrustedDevicesResponse struct {
+	Devices []TrustedDeviceResponse `json:"devices"`
+}
+
+type IdentityResponse struct {
+	ID             int64     `json:"id"`
+	Provider       string    `json:"provider"`
+	ProviderUserID string    `json:"provider_user_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type IdentitiesResponse struct {
+	Identities []IdentityResponse `json:"identities"`
+}
+
+type APIKeyCreateRequest struct {
+	Name          string              `json:"name"`
+	Scopes        map[string][]string `json:"scopes"`
+	ExpiresInDays int                 `json:"expires_in_days"`
+}
+
+type APIKeyResponse struct {
+	ID         int64               `json:"id"`
+	Name       string              `json:"name"`
+	Prefix     string              `json:"prefix"`
+	Scopes     map[string][]string `json:"scopes"`
+	ExpiresAt  *time.Time          `json:"expires_at"`
+	LastUsedAt *time.Time          `json:"last_used_at"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+type APIKeyCreateResponse struct {
+	APIKeyResponse
+	// Key is shown only in this response.
+	Key string `json:"key"`
+}
+
+type APIKeysResponse struct {
+	APIKeys []APIKeyResponse `json:"api_keys"`
+}
+
+type ServiceAccountCreateRequest struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+type ServiceAccountResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	ClientID   string     `json:"client_id"`
+	Roles      []string   `json:"roles"`
+	CreatedBy  int64      `json:"created_by"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ServiceAccountCreateResponse struct {
+	ServiceAccountResponse
+	// ClientSecret is shown only in this response.
+	ClientSecret string `json:"client_secret"`
+}
+
+type ServiceAccountsResponse struct {
+	ServiceAccounts []ServiceAccountResponse `json:"service_accounts"`
+}
+
+type OrgCreateRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type OrgUpdateRequest struct {
+	Name string `json:"name"`
+}
+
+type OrgResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedBy int64     `json:"created_by"`
+	Role      string    `json:"role,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type OrgsResponse struct {
+	Organizations []OrgResponse `json:"organizations"`
+}
+
+type OrgTokenResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+type OrgMemberAddRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type OrgInviteAcceptRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
+type OrgMemberUpdateRequest struct {
+	Role string `json:"role"`
+}
+
+type OrgMemberResponse struct {
+	UserID   int64     `json:"user_id"`
+	Email    string    `json:"email"`
+	FullName string    `json:"full_name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type OrgMembersResponse struct {
+	Members []OrgMemberResponse `json:"members"`
+}
+
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
 type RefreshTokenResponse struct {
 	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	CSRFToken    string `json:"csrf_token,omitempty"`
+}
+
+type ClientCredentialsRequest struct {
+	GrantType    string `json:"grant_type"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+type ClientCredentialsResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type MetadataResponse struct {
+	Issuer                            string            `json:"issuer"`
+	TokenEndpoint                     string            `json:"token_endpoint"`
+	Endpoints                         map[string]string `json:"endpoints"`
+	JWKSURI                           string            `json:"jwks_uri,omitempty"`
+	GrantTypesSupported               []string          `json:"grant_types_supported"`
+	TokenEndpointAuthMethodsSupported []string          `json:"token_endpoint_auth_methods_supported"`
+	APIKeyHeader                      string            `json:"api_key_header"`
+	AccessTokenTTLSeconds             int64             `json:"access_token_ttl_seconds"`
+	RefreshTokenTTLDays               int64             `json:"refresh_token_ttl_days"`
+	MFAMethodsSupported               []string          `json:"mfa_methods_supported"`
+	OAuthProviders                    []string          `json:"oauth_providers"`
+	PasswordPolicy                    PasswordPolicy    `json:"password_policy"`
+	Features                          map[string]bool   `json:"features"`
+}
+
+type PasswordPolicy struct {
+	MinLength        int  `json:"min_length"`
+	MaxLength        int  `json:"max_length"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireDigit     bool `json:"require_digit"`
+	RequireSymbol    bool `json:"require_symbol"`
+	DisallowEmail    bool `json:"disallow_email"`
+	HistorySize      int  `json:"history_size"`
+	BreachCheck      bool `json:"breach_check"`
 }
 
 type TOTPSetupRequest struct {
@@ -105,6 +388,21 @@ type TOTPConfirmRequest struct {
 	Code           string `json:"code"`
 }
 
+type SMSSetupRequest struct {
+	PhoneNumber     string `json:"phone_number"`
+	CurrentPassword string `json:"current_password"`
+}
+
+type SMSSetupResponse struct {
+	ChallengeToken string `json:"challenge_token"`
+	Destination    string `json:"destination"`
+}
+
+type SMSConfirmRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
 type BackupCodeRequest struct {
 	CurrentPassword string `json:"current_password"`
 }
@@ -113,10 +411,69 @@ type BackupCodeResponse struct {
 	RecoveryCodes []string `json:"recovery_codes"`
 }
 
+type MFAFactorsResponse struct {
+	Factors []UserMFAFactorResponse `json:"factors"`
+}
+
+type MFAFactorRenameRequest struct {
+	FriendlyName string `json:"friendly_name"`
+}
+
+type MFAFactorDeleteRequest struct {
+	CurrentPassword string `json:"current_password"`
+	Method          string `json:"method"`
+	Code            string `json:"code"`
+}
+
+type MFARecoveryRequest struct {
+	Email string `json:"email"`
+}
+
+type MFARecoveryRequestResponse struct{}
+
+func (MFARecoveryRequestResponse) Message() string {
+	return "If an account with that email has two-factor authentication, we have sent a recovery link."
+}
+
+type MFARecoveryVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Password       string `json:"password"`
+	BackupCode     string `json:"backup_code"`
+}
+
+type MFARecoveryVerifyResponse struct {
+	RecoveryToken string    `json:"recovery_token"`
+	AvailableAt   time.Time `json:"available_at"`
+}
+
+type MFARecoveryCompleteRequest struct {
+	RecoveryToken string `json:"recovery_token"`
+}
+
 type UpdateProfileRequest struct {
 	FullName string `json:"full_name"`
 }
 
+type UpdateProfileUsernameRequest struct {
+	// Username is the new username; empty removes it.
+	Username string `json:"username"`
+}
+
+type ProfileAttributesRequest struct {
+	// Attributes are merged into the stored ones; a key set to null is removed.
+	Attributes map[string]any `json:"attributes" swaggertype:"object"`
+}
+
+type ProfileAttributesResponse struct {
+	Attributes valueobject.JSONMap `json:"attributes" swaggertype:"object"`
+}
+
+type UsernameAvailabilityResponse struct {
+	Username  string `json:"username"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
+
 type ProfilePermissionsResponse struct {
 	Permissions map[string][]string `json:"permissions"`
 }
@@ -127,14 +484,76 @@ type ProfileSettingMFAResponse struct {
 	SMSEnabled        bool `json:"sms_enabled"`
 }
 
+type OnboardingStepResponse struct {
+	Key       string `json:"key"`
+	Completed bool   `json:"completed"`
+}
+
+type ProfileOnboardingResponse struct {
+	Steps     []OnboardingStepResponse `json:"steps"`
+	Completed int                      `json:"completed"`
+	Total     int                      `json:"total"`
+	Percent   int                      `json:"percent"`
+}
+
+type SecurityCheckResponse struct {
+	Key            string `json:"key"`
+	Passed         bool   `json:"passed"`
+	Weight         int    `json:"weight"`
+	Recommendation string `json:"recommendation,omitempty"`
+}
+
+type ProfileSecurityScoreResponse struct {
+	Score  int                     `json:"score"`
+	Checks []SecurityCheckResponse `json:"checks"`
+}
+
 type ProfileResponse struct {
 	ID        int64  `json:"id,string"`
 	Email     string `json:"email"`
 	FullName  string `json:"full_name"`
+	Username  string `json:"username,omitempty"`
 	AvatarURL string `json:"avatar_url"`
 	Status    string `json:"status"`
 }
 
+type ProfileExportResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ProfileDeleteRequest struct {
+	CurrentPassword string `json:"current_password"`
+}
+
+type ProfileDeleteResponse struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+type LoginLocationResponse struct {
+	Country string `json:"country"`
+	Region  string `json:"region"`
+	City    string `json:"city"`
+}
+
+type LoginEventResponse struct {
+	ID            int64                 `json:"id,string"`
+	Success       bool                  `json:"success"`
+	Method        string                `json:"method"`
+	FailureReason string                `json:"failure_reason"`
+	IP            string                `json:"ip"`
+	UserAgent     string                `json:"user_agent"`
+	Location      LoginLocationResponse `json:"location"`
+	CreatedAt     time.Time             `json:"created_at"`
+}
+
+type ProfileLoginsResponse struct {
+	Logins []LoginEventResponse `json:"logins"`
+	// NextBeforeID is passed as before_id to fetch older entries; 0 on the last page.
+	NextBeforeID int64 `json:"next_before_id,string"`
+}
+
 type UserResponse struct {
 	ID        int64             `json:"id,string"`
 	Email     string            `json:"email"`
@@ -142,6 +561,8 @@ type UserResponse struct {
 	AvatarURL string            `json:"avatar_url"`
 	Status    entity.UserStatus `json:"status"`
 	UpdateAt  time.Time         `json:"updated_at"`
+	// Version is sent back as If-Match or expected_version when updating the user.
+	Version valueobject.Version `json:"version,string"`
 }
 
 type UserCreateRequest struct {
@@ -151,11 +572,27 @@ type UserCreateRequest struct {
 	Status   entity.UserStatus `json:"status"`
 }
 
+type UserInviteRequest struct {
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+}
+
+type UserInviteAcceptRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Password       string `json:"password"`
+}
+
 type UserUpdateRequest struct {
 	Email    string            `json:"email,omitempty"`
 	Password string            `json:"password,omitempty"`
 	FullName string            `json:"full_name,omitempty"`
 	Status   entity.UserStatus `json:"status,omitempty"`
+	// MustResetPassword and MFARequired force a password reset or an MFA enrollment at the
+	// user's next login; omitted leaves them unchanged.
+	MustResetPassword *bool `json:"must_reset_password,omitempty"`
+	MFARequired       *bool `json:"mfa_required,omitempty"`
+	// ExpectedVersion is the version read with the user; the If-Match header takes precedence.
+	ExpectedVersion int64 `json:"expected_version,omitempty,string"`
 }
 
 type UsersResponse struct {
@@ -178,10 +615,75 @@ type UserDetailResponse struct {
 	User UserResponse `json:"user"`
 }
 
+type UserMFAFactorResponse struct {
+	ID           int64      `json:"id,string"`
+	Type         string     `json:"type"`
+	FriendlyName string     `json:"friendly_name"`
+	IsVerified   bool       `json:"is_verified"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type UserMFAResponse struct {
+	Factors              []UserMFAFactorResponse `json:"factors"`
+	BackupCodesRemaining int                     `json:"backup_codes_remaining"`
+}
+
+type UserPermissionsResponse struct {
+	Roles       []string            `json:"roles"`
+	Permissions map[string][]string `json:"permissions"`
+}
+
+type UserRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+type UserRolesResponse struct {
+	Roles []string `json:"roles"`
+}
+
+type UserMFARevokeRequest struct {
+	Reason string `json:"reason"`
+}
+
+type UserImpersonateRequest struct {
+	Reason string `json:"reason"`
+}
+
+type UserImpersonateResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
 type UserExportResponse struct {
 	Users []UserResponse `json:"users"`
 }
 
+type UserExportJobCreateRequest struct {
+	Format    string              `json:"format"`
+	Search    string              `json:"search"`
+	Status    []entity.UserStatus `json:"status"`
+	SortBy    string              `json:"sort_by"`
+	SortOrder string              `json:"sort_order"`
+	DateFrom  time.Time           `json:"date_from"`
+	DateTo    time.Time           `json:"date_to"`
+}
+
+type UserExportJobResponse struct {
+	ID           int64      `json:"id,string"`
+	Format       string     `json:"format"`
+	Status       string     `json:"status"`
+	RowsTotal    int64      `json:"rows_total"`
+	RowsWritten  int64      `json:"rows_written"`
+	Error        string     `json:"error,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	URLExpiresAt *time.Time `json:"url_expires_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
 type UserImportRequest []UserImportUserRequest
 
 type UserImportUserRequest struct {
@@ -195,3 +697,57 @@ type UserImportResponse struct {
 	Created int `json:"created"`
 	Updated int `json:"updated"`
 }
+
+type UserImportFileResponse struct {
+	Created   int                          `json:"created"`
+	Updated   int                          `json:"updated"`
+	Failed    int                          `json:"failed"`
+	Truncated bool                         `json:"truncated"`
+	Errors    []UserImportRowErrorResponse `json:"errors"`
+}
+
+type UserImportRowErrorResponse struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type RolePermissionRequest struct {
+	Object string `json:"object"`
+	Action string `json:"action"`
+}
+
+type RoleCreateRequest struct {
+	Name        string                  `json:"name"`
+	Permissions []RolePermissionRequest `json:"permissions"`
+}
+
+type RoleUpdateRequest struct {
+	Permissions []RolePermissionRequest `json:"permissions"`
+}
+
+type RoleMemberRequest struct {
+	UserID int64 `json:"user_id,string"`
+}
+
+type RoleSummaryResponse struct {
+	Name        string `json:"name"`
+	Members     int    `json:"members"`
+	Permissions int    `json:"permissions"`
+}
+
+type RolesResponse struct {
+	Roles []RoleSummaryResponse `json:"roles"`
+}
+
+type RoleDetailResponse struct {
+	Name                 string              `json:"name"`
+	Permissions          map[string][]string `json:"permissions"`
+	EffectivePermissions map[string][]string `json:"effective_permissions"`
+	MemberIDs            []string            `json:"member_ids"`
+}
+
+type RolePermissionsResponse struct {
+	Permissions          map[string][]string `json:"permissions"`
+	EffectivePermissions map[string][]string `json:"effective_permissions"`
+}
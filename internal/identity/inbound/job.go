@@ -0,0 +1,89 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/jobs"
+	"github.com/shandysiswandi/gobite/internal/pkg/retention"
+)
+
+type ucJob interface {
+	RetentionTargets() []retention.Target
+	ScheduleVerificationReminders(ctx context.Context) (int, error)
+	ProcessDueUserDeletions(ctx context.Context) (int, error)
+	ArchiveAuditLogs(ctx context.Context) (int64, error)
+	SealPlaintextEmails(ctx context.Context) (int, error)
+}
+
+func RegisterJob(sched *retention.Scheduler, uc ucJob) {
+	sched.Register(uc.RetentionTargets()...)
+}
+
+// RegisterVerificationReminderJob looks for users due a verification reminder every
+// modules.identity.verification_reminder.check_interval_minutes. The reminders themselves
+// are sent by the user_verification_reminder_due_identity consumer.
+func RegisterVerificationReminderJob(registry *jobs.Registry, cfg config.Config, uc ucJob) {
+	interval := cfg.GetMinute("modules.identity.verification_reminder.check_interval_minutes")
+	if !cfg.GetBool("modules.identity.verification_reminder.enabled") {
+		interval = 0
+	}
+
+	registry.Schedule(jobs.Job{
+		Name:     "identity_verification_reminders",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			// failures are logged by the usecase and retried on the next tick
+			_, err := uc.ScheduleVerificationReminders(ctx)
+			return err
+		},
+	})
+}
+
+// RegisterUserDeletionJob anonymizes the accounts whose deletion grace period is over every
+// modules.identity.account_deletion.check_interval_minutes.
+func RegisterUserDeletionJob(registry *jobs.Registry, cfg config.Config, uc ucJob) {
+	registry.Schedule(jobs.Job{
+		Name:     "identity_user_deletions",
+		Interval: cfg.GetMinute("modules.identity.account_deletion.check_interval_minutes"),
+		Run: func(ctx context.Context) error {
+			// failures are logged by the usecase and retried on the next tick
+			_, err := uc.ProcessDueUserDeletions(ctx)
+			return err
+		},
+	})
+}
+
+// RegisterAuditLogArchiveJob moves audit entries past their retention window to object storage
+// every modules.identity.audit_archive_interval_minutes, independently of retention.enabled.
+func RegisterAuditLogArchiveJob(registry *jobs.Registry, cfg config.Config, uc ucJob) {
+	interval := cfg.GetMinute("modules.identity.audit_archive_interval_minutes")
+	if !cfg.GetBool("modules.identity.audit_archive_enabled") {
+		interval = 0
+	}
+
+	registry.Schedule(jobs.Job{
+		Name:     "identity_audit_log_archive",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			// failures are logged by the usecase and retried on the next tick
+			_, err := uc.ArchiveAuditLogs(ctx)
+			return err
+		},
+	})
+}
+
+// RegisterEmailSealJob replaces plaintext emails with their sealed copy every
+// modules.identity.email_seal_interval_minutes. Each run does nothing unless
+// email_lookup_hash_strict is on.
+func RegisterEmailSealJob(registry *jobs.Registry, cfg config.Config, uc ucJob) {
+	registry.Schedule(jobs.Job{
+		Name:     "identity_email_seal",
+		Interval: cfg.GetMinute("modules.identity.email_seal_interval_minutes"),
+		Run: func(ctx context.Context) error {
+			// failures are logged by the usecase and retried on the next tick
+			_, err := uc.SealPlaintextEmails(ctx)
+			return err
+		},
+	})
+}
@@ -0,0 +1,94 @@
+package inbound
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jobs"
+	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+)
+
+func RegisterMQConsumer(
+	ctx context.Context,
+	cfg config.Config,
+	workers *jobs.Registry,
+	messenger messaging.Messaging,
+	uuid uid.StringID,
+	uc ucConsumer,
+	ins instrument.Instrumentation,
+) {
+	mqHanlder := &MQHandler{uc: uc, uuid: uuid, ins: ins}
+
+	enableConsumerNames := cfg.GetArray("modules.identity.consumer_names")
+
+	var consumers = []struct {
+		name               string
+		topic              string // destination where publisher sent message
+		nsqConsumerName    string // for nsq
+		natsConsumerName   string // for nats
+		kafkaConsumerName  string // for kafka
+		pubsubConsumerName string // for google pubusb
+		handler            messaging.Handler
+	}{
+		{
+			name:               contracts.UserDeletionScheduledConsumerIdentity,
+			topic:              contracts.UserDeletionScheduledDestination,
+			nsqConsumerName:    contracts.UserDeletionScheduledConsumerIdentity,
+			natsConsumerName:   contracts.UserDeletionScheduledConsumerIdentity,
+			kafkaConsumerName:  contracts.UserDeletionScheduledConsumerIdentity,
+			pubsubConsumerName: contracts.UserDeletionScheduledConsumerIdentity,
+			handler:            mqHanlder.UserDeletionScheduled,
+		},
+		{
+			name:               contracts.LoginRecordedConsumerIdentity,
+			topic:              contracts.LoginRecordedDestination,
+			nsqConsumerName:    contracts.LoginRecordedConsumerIdentity,
+			natsConsumerName:   contracts.LoginRecordedConsumerIdentity,
+			kafkaConsumerName:  contracts.LoginRecordedConsumerIdentity,
+			pubsubConsumerName: contracts.LoginRecordedConsumerIdentity,
+			handler:            mqHanlder.LoginRecorded,
+		},
+		{
+			name:               contracts.UserExportRequestedConsumerIdentity,
+			topic:              contracts.UserExportRequestedDestination,
+			nsqConsumerName:    contracts.UserExportRequestedConsumerIdentity,
+			natsConsumerName:   contracts.UserExportRequestedConsumerIdentity,
+			kafkaConsumerName:  contracts.UserExportRequestedConsumerIdentity,
+			pubsubConsumerName: contracts.UserExportRequestedConsumerIdentity,
+			handler:            mqHanlder.UserExportRequested,
+		},
+		{
+			name:               contracts.VerificationReminderDueConsumerIdentity,
+			topic:              contracts.VerificationReminderDueDestination,
+			nsqConsumerName:    contracts.VerificationReminderDueConsumerIdentity,
+			natsConsumerName:   contracts.VerificationReminderDueConsumerIdentity,
+			kafkaConsumerName:  contracts.VerificationReminderDueConsumerIdentity,
+			pubsubConsumerName: contracts.VerificationReminderDueConsumerIdentity,
+			handler:            mqHanlder.VerificationReminderDue,
+		},
+	}
+
+	for _, consumer := range consumers {
+		if len(enableConsumerNames) > 0 && slices.Contains(enableConsumerNames, consumer.name) {
+			workers.Go(ctx, consumer.name, func(pCtx context.Context) error {
+				slog.InfoContext(ctx, "Running job for handling consumer", "consumer", consumer.name)
+				return messenger.Consume(pCtx,
+					consumer.topic,
+					consumer.handler,
+					messaging.WithChannel(consumer.nsqConsumerName),
+					messaging.WithQueueGroup(consumer.natsConsumerName),
+					messaging.WithGroup(consumer.kafkaConsumerName),
+					messaging.WithSubscription(consumer.pubsubConsumerName),
+					messaging.WithAutoAck(true),
+					messaging.WithConcurrency(10),
+					messaging.WithMaxInFlight(10),
+				)
+			})
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package inbound
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/usecase"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+)
+
+type ucConsumer interface {
+	AnonymizeUser(ctx context.Context, in usecase.AnonymizeUserInput) error
+	EnrichLoginEvent(ctx context.Context, in usecase.EnrichLoginEventInput) error
+	RunUserExportJob(ctx context.Context, in usecase.RunUserExportJobInput) error
+	SendVerificationReminder(ctx context.Context, in usecase.SendVerificationReminderInput) error
+}
+
+type MQHandler struct {
+	uc   ucConsumer
+	uuid uid.StringID
+	ins  instrument.Instrumentation
+}
+
+// ensureCorrelationID makes sure ctx carries the publisher's carrier. Headers restored by
+// the messaging layer win; brokers without headers deliver it inside the envelope.
+func (h *MQHandler) ensureCorrelationID(ctx context.Context, msg messaging.Message) context.Context {
+	if instrument.CarrierFromContext(ctx).CorrelationID != "" {
+		return ctx
+	}
+
+	if carrier := contracts.CarrierOf(msg.Body()); len(carrier) > 0 {
+		ctx = instrument.ExtractCarrier(func(key string) string { return carrier[key] }).Context(ctx)
+		if instrument.CarrierFromContext(ctx).CorrelationID != "" {
+			return ctx
+		}
+	}
+
+	return instrument.SetCorrelationID(ctx, h.uuid.Generate())
+}
+
+func (h *MQHandler) UserDeletionScheduled(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("identity.inbound.mq").Start(ctx, "UserDeletionScheduled")
+	defer span.End()
+
+	body := msg.Body()
+
+	var payload contracts.UserDeletionScheduled
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
+		slog.ErrorContext(ctx, "failed to parse message body of user deletion scheduled", "msg_body", string(body), "error", err)
+		return nil
+	}
+
+	if err := h.uc.AnonymizeUser(ctx, usecase.AnonymizeUserInput{UserID: payload.UserID}); err != nil {
+		slog.ErrorContext(ctx, "failed to anonymize user", "user_id", payload.UserID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (h *MQHandler) LoginRecorded(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("identity.inbound.mq").Start(ctx, "LoginRecorded")
+	defer span.End()
+
+	body := msg.Body()
+
+	var payload contracts.LoginRecorded
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
+		slog.ErrorContext(ctx, "failed to parse message body of login recorded", "msg_body", string(body), "error", err)
+		return nil
+	}
+
+	if err := h.uc.EnrichLoginEvent(ctx, usecase.EnrichLoginEventInput{
+		LoginEventID: payload.LoginEventID,
+		IP:           payload.IP,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to enrich login event", "login_event_id", payload.LoginEventID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (h *MQHandler) UserExportRequested(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("identity.inbound.mq").Start(ctx, "UserExportRequested")
+	defer span.End()
+
+	body := msg.Body()
+
+	var payload contracts.UserExportRequested
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
+		slog.ErrorContext(ctx, "failed to parse message body of user export requested", "msg_body", string(body), "error", err)
+		return nil
+	}
+
+	if err := h.uc.RunUserExportJob(ctx, usecase.RunUserExportJobInput{JobID: payload.JobID}); err != nil {
+		slog.ErrorContext(ctx, "failed to run user export job", "job_id", payload.JobID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (h *MQHandler) VerificationReminderDue(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("identity.inbound.mq").Start(ctx, "VerificationReminderDue")
+	defer span.End()
+
+	body := msg.Body()
+
+	var payload contracts.VerificationReminderDue
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
+		slog.ErrorContext(ctx, "failed to parse message body of verification reminder due", "msg_body", string(body), "error", err)
+		return nil
+	}
+
+	if err := h.uc.SendVerificationReminder(ctx, usecase.SendVerificationReminderInput{
+		UserID:   payload.UserID,
+		Reminder: payload.Reminder,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to send verification reminder", "user_id", payload.UserID, "error", err)
+		return err
+	}
+
+	return nil
+}
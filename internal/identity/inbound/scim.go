@@ -0,0 +1,684 @@
+package inbound
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/identity/usecase"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/router"
+	"github.com/shandysiswandi/gobite/internal/pkg/validator"
+)
+
+// scimMaxBody caps a SCIM request body; a group PUT listing every member is the largest.
+const scimMaxBody = 1 << 20
+
+var (
+	// scimFilter matches the one filter form identity providers send, `attribute eq "value"`.
+	scimFilter = regexp.MustCompile(`(?i)^\s*([a-z.]+)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+	// scimMemberFilter matches a path removing one member, `members[value eq "id"]`.
+	scimMemberFilter = regexp.MustCompile(`(?i)^\s*members\s*\[\s*value\s+eq\s+("(?:[^"\\]|\\.)*")\s*\]\s*$`)
+)
+
+// SCIMEndpoint serves SCIM 2.0 (RFC 7644) provisioning. It writes its own resources and
+// errors as application/scim+json instead of the API envelope, as identity providers expect.
+type SCIMEndpoint struct {
+	uc uc
+}
+
+// RegisterSCIMEndpoint mounts /scim/v2. Identity providers authenticate with an API key sent
+// as a bearer token, held by a user with identity:scim.
+func RegisterSCIMEndpoint(r *router.Router, uc uc) {
+	end := &SCIMEndpoint{uc: uc}
+
+	r.HandleRaw(http.MethodGet, "/scim/v2/ServiceProviderConfig", scimHandler(end.ServiceProviderConfig))
+
+	r.HandleRaw(http.MethodGet, "/scim/v2/Users", scimHandler(end.UserList))
+	r.HandleRaw(http.MethodPost, "/scim/v2/Users", scimHandler(end.UserCreate))
+	r.HandleRaw(http.MethodGet, "/scim/v2/Users/:id", scimHandler(end.UserGet))
+	r.HandleRaw(http.MethodPut, "/scim/v2/Users/:id", scimHandler(end.UserReplace))
+	r.HandleRaw(http.MethodPatch, "/scim/v2/Users/:id", scimHandler(end.UserPatch))
+	r.HandleRaw(http.MethodDelete, "/scim/v2/Users/:id", scimHandler(end.UserDelete))
+
+	r.HandleRaw(http.MethodGet, "/scim/v2/Groups", scimHandler(end.GroupList))
+	r.HandleRaw(http.MethodPost, "/scim/v2/Groups", scimHandler(end.GroupCreate))
+	r.HandleRaw(http.MethodGet, "/scim/v2/Groups/:id", scimHandler(end.GroupGet))
+	r.HandleRaw(http.MethodPut, "/scim/v2/Groups/:id", scimHandler(end.GroupReplace))
+	r.HandleRaw(http.MethodPatch, "/scim/v2/Groups/:id", scimHandler(end.GroupPatch))
+	r.HandleRaw(http.MethodDelete, "/scim/v2/Groups/:id", scimHandler(end.GroupDelete))
+}
+
+func (h *SCIMEndpoint) ServiceProviderConfig(*http.Request) (int, any, error) {
+	return http.StatusOK, SCIMServiceProviderConfig{
+		Schemas: []string{scimSchemaServiceConfig},
+		Patch:   SCIMSupported{Supported: true},
+		Filter:  SCIMFilterSupport{Supported: true, MaxResults: 100},
+		AuthenticationSchemes: []SCIMAuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "API key",
+			Description: "An API key with the identity:scim scope, sent as a bearer token",
+		}},
+	}, nil
+}
+
+func (h *SCIMEndpoint) UserList(r *http.Request) (int, any, error) {
+	userName, err := scimFilterValue(r, "userName")
+	if err != nil {
+		return 0, nil, err
+	}
+
+	resp, err := h.uc.SCIMUserList(r.Context(), usecase.SCIMUserListInput{
+		UserName:   userName,
+		StartIndex: scimQueryInt(r, "startIndex", 1),
+		Count:      scimQueryInt(r, "count", -1),
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	users := make([]SCIMUser, 0, len(resp.Users))
+	for _, user := range resp.Users {
+		users = append(users, toSCIMUser(user))
+	}
+
+	return http.StatusOK, SCIMListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: resp.Total,
+		StartIndex:   resp.StartIndex,
+		ItemsPerPage: len(users),
+		Resources:    users,
+	}, nil
+}
+
+func (h *SCIMEndpoint) UserGet(r *http.Request) (int, any, error) {
+	id, err := scimUserID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	user, err := h.uc.SCIMUserGet(r.Context(), usecase.SCIMUserIDInput{ID: id})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, toSCIMUser(*user), nil
+}
+
+func (h *SCIMEndpoint) UserCreate(r *http.Request) (int, any, error) {
+	var req SCIMUser
+	if err := scimDecode(r, &req); err != nil {
+		return 0, nil, err
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	user, err := h.uc.SCIMUserCreate(r.Context(), usecase.SCIMUserCreateInput{
+		UserName: scimUserName(req),
+		FullName: scimFullName(req),
+		Active:   active,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusCreated, toSCIMUser(*user), nil
+}
+
+func (h *SCIMEndpoint) UserReplace(r *http.Request) (int, any, error) {
+	id, err := scimUserID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var req SCIMUser
+	if err := scimDecode(r, &req); err != nil {
+		return 0, nil, err
+	}
+
+	userName, fullName := scimUserName(req), scimFullName(req)
+	user, err := h.uc.SCIMUserUpdate(r.Context(), usecase.SCIMUserUpdateInput{
+		ID:       id,
+		UserName: &userName,
+		FullName: &fullName,
+		Active:   req.Active,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, toSCIMUser(*user), nil
+}
+
+func (h *SCIMEndpoint) UserPatch(r *http.Request) (int, any, error) {
+	id, err := scimUserID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var req SCIMPatchRequest
+	if err := scimDecode(r, &req); err != nil {
+		return 0, nil, err
+	}
+
+	in := usecase.SCIMUserUpdateInput{ID: id}
+	for _, op := range req.Operations {
+		if err := scimUserPatch(&in, op); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	user, err := h.uc.SCIMUserUpdate(r.Context(), in)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, toSCIMUser(*user), nil
+}
+
+func (h *SCIMEndpoint) UserDelete(r *http.Request) (int, any, error) {
+	id, err := scimUserID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err := h.uc.SCIMUserDelete(r.Context(), usecase.SCIMUserIDInput{ID: id}); err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusNoContent, nil, nil
+}
+
+func (h *SCIMEndpoint) GroupList(r *http.Request) (int, any, error) {
+	displayName, err := scimFilterValue(r, "displayName")
+	if err != nil {
+		return 0, nil, err
+	}
+
+	resp, err := h.uc.SCIMGroupList(r.Context(), usecase.SCIMGroupListInput{DisplayName: displayName})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// the provisionable roles are few, so they come in one page whatever was asked for
+	groups := make([]SCIMGroup, 0, len(resp.Groups))
+	for _, group := range resp.Groups {
+		groups = append(groups, toSCIMGroup(group))
+	}
+
+	return http.StatusOK, SCIMListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: int64(len(groups)),
+		StartIndex:   1,
+		ItemsPerPage: len(groups),
+		Resources:    groups,
+	}, nil
+}
+
+func (h *SCIMEndpoint) GroupGet(r *http.Request) (int, any, error) {
+	group, err := h.uc.SCIMGroupGet(r.Context(), usecase.SCIMGroupIDInput{ID: scimParam(r, "id")})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, toSCIMGroup(*group), nil
+}
+
+func (h *SCIMEndpoint) GroupCreate(r *http.Request) (int, any, error) {
+	var req SCIMGroup
+	if err := scimDecode(r, &req); err != nil {
+		return 0, nil, err
+	}
+
+	members, err := scimMemberIDs(req.Members)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	group, err := h.uc.SCIMGroupCreate(r.Context(), usecase.SCIMGroupCreateInput{
+		DisplayName: req.DisplayName,
+		Members:     members,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusCreated, toSCIMGroup(*group), nil
+}
+
+func (h *SCIMEndpoint) GroupReplace(r *http.Request) (int, any, error) {
+	var req SCIMGroup
+	if err := scimDecode(r, &req); err != nil {
+		return 0, nil, err
+	}
+
+	id := scimParam(r, "id")
+	if req.DisplayName != "" && !strings.EqualFold(req.DisplayName, id) {
+		return 0, nil, &scimRequestError{scimType: "mutability", detail: "displayName of a group cannot change"}
+	}
+
+	members, err := scimMemberIDs(req.Members)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	group, err := h.uc.SCIMGroupUpdate(r.Context(), usecase.SCIMGroupUpdateInput{
+		ID:             id,
+		ReplaceMembers: true,
+		Members:        members,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, toSCIMGroup(*group), nil
+}
+
+func (h *SCIMEndpoint) GroupPatch(r *http.Request) (int, any, error) {
+	var req SCIMPatchRequest
+	if err := scimDecode(r, &req); err != nil {
+		return 0, nil, err
+	}
+
+	in := usecase.SCIMGroupUpdateInput{ID: scimParam(r, "id")}
+	for _, op := range req.Operations {
+		if err := scimGroupPatch(&in, op); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	group, err := h.uc.SCIMGroupUpdate(r.Context(), in)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, toSCIMGroup(*group), nil
+}
+
+func (h *SCIMEndpoint) GroupDelete(r *http.Request) (int, any, error) {
+	if err := h.uc.SCIMGroupDelete(r.Context(), usecase.SCIMGroupIDInput{ID: scimParam(r, "id")}); err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusNoContent, nil, nil
+}
+
+// scimRequestError is a request the SCIM protocol rejects with a 400 and a scimType.
+type scimRequestError struct {
+	scimType string
+	detail   string
+}
+
+func (e *scimRequestError) Error() string {
+	return e.detail
+}
+
+// scimHandler writes what fn returns, a resource or an error, as application/scim+json.
+func scimHandler(fn func(r *http.Request) (int, any, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, scimMaxBody)
+
+		status, resp, err := fn(r)
+		if err != nil {
+			status, resp = scimErrorResponse(err)
+		}
+
+		if status == http.StatusNoContent {
+			w.WriteHeader(status)
+			return
+		}
+
+		w.Header().Set("Content-Type", scimContentType)
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			slog.ErrorContext(r.Context(), "failed to write scim response", "error", err)
+		}
+	})
+}
+
+// scimErrorResponse maps err to the status and error body of RFC 7644 section 3.12.
+func scimErrorResponse(err error) (int, SCIMError) {
+	status, scimType, detail := http.StatusInternalServerError, "", "Internal server error"
+
+	var reqErr *scimRequestError
+	var gerr *goerror.Error
+	switch {
+	case errors.As(err, &reqErr):
+		status, scimType, detail = http.StatusBadRequest, reqErr.scimType, reqErr.detail
+	case errors.As(err, &gerr) && gerr.StatusCode() != http.StatusInternalServerError:
+		status, detail = gerr.StatusCode(), gerr.Msg()
+
+		switch gerr.Code() {
+		case goerror.CodeInvalidInput, goerror.CodeInvalidFormat:
+			// SCIM answers a bad attribute value with 400, not 422
+			status, scimType = http.StatusBadRequest, "invalidValue"
+
+			var errValidate validator.V10ValidationError
+			if errors.As(err, &errValidate) {
+				detail = scimValidationDetail(errValidate.Values())
+			}
+		case goerror.CodeConflict:
+			scimType = "uniqueness"
+		}
+	}
+
+	return status, SCIMError{
+		Schemas:  []string{scimSchemaError},
+		Status:   strconv.Itoa(status),
+		SCIMType: scimType,
+		Detail:   detail,
+	}
+}
+
+func scimValidationDetail(fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fields[key])
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func scimDecode(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return &scimRequestError{scimType: "invalidSyntax", detail: "Invalid request body"}
+	}
+
+	return nil
+}
+
+func scimParam(r *http.Request, key string) string {
+	return httprouter.ParamsFromContext(r.Context()).ByName(key)
+}
+
+// scimUserID parses the id in the path; an id that is not ours cannot name a user.
+func scimUserID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(scimParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, goerror.NewBusiness("user not found", goerror.CodeNotFound)
+	}
+
+	return id, nil
+}
+
+func scimQueryInt(r *http.Request, key string, fallback int32) int32 {
+	v, err := strconv.ParseInt(r.URL.Query().Get(key), 10, 32)
+	if err != nil {
+		return fallback
+	}
+
+	return int32(v)
+}
+
+// scimFilterValue returns the value of a `attribute eq "value"` filter, the only one
+// supported, or empty when the request has no filter.
+func scimFilterValue(r *http.Request, attribute string) (string, error) {
+	filter := r.URL.Query().Get("filter")
+	if filter == "" {
+		return "", nil
+	}
+
+	m := scimFilter.FindStringSubmatch(filter)
+	if m == nil || !strings.EqualFold(m[1], attribute) {
+		return "", &scimRequestError{scimType: "invalidFilter", detail: "only filter=" + attribute + ` eq "value" is supported`}
+	}
+
+	value, err := strconv.Unquote(m[2])
+	if err != nil {
+		return "", &scimRequestError{scimType: "invalidFilter", detail: "invalid filter value"}
+	}
+
+	return value, nil
+}
+
+// scimUserName falls back to the primary email for providers that send userName as an
+// opaque identifier; a user here signs in with their email.
+func scimUserName(u SCIMUser) string {
+	if strings.Contains(u.UserName, "@") || len(u.Emails) == 0 {
+		return u.UserName
+	}
+
+	for _, email := range u.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+
+	return u.Emails[0].Value
+}
+
+// scimFullName picks the most complete name a provider sent.
+func scimFullName(u SCIMUser) string {
+	if u.Name != nil {
+		if name := strings.TrimSpace(u.Name.Formatted); name != "" {
+			return name
+		}
+		if name := strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName); name != "" {
+			return name
+		}
+	}
+
+	if name := strings.TrimSpace(u.DisplayName); name != "" {
+		return name
+	}
+
+	return u.UserName
+}
+
+// scimUserPatch applies one PATCH operation to in. Attributes a user does not have here,
+// such as title or phoneNumbers, are ignored so providers can sync their full profile.
+func scimUserPatch(in *usecase.SCIMUserUpdateInput, op SCIMPatchOperation) error {
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+	case "remove":
+		// none of the attributes kept here can be cleared
+		return nil
+	default:
+		return &scimRequestError{scimType: "invalidSyntax", detail: "unsupported op " + op.Op}
+	}
+
+	if op.Path != "" {
+		return scimUserAttribute(in, op.Path, op.Value)
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &values); err != nil {
+		return &scimRequestError{scimType: "invalidValue", detail: "value of an operation without path must be an object"}
+	}
+
+	for path, value := range values {
+		if err := scimUserAttribute(in, path, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scimUserAttribute(in *usecase.SCIMUserUpdateInput, path string, value json.RawMessage) error {
+	switch strings.ToLower(path) {
+	case "active":
+		active, err := scimBool(value)
+		if err != nil {
+			return err
+		}
+		in.Active = &active
+	case "username":
+		var userName string
+		if err := json.Unmarshal(value, &userName); err != nil {
+			return &scimRequestError{scimType: "invalidValue", detail: "userName must be a string"}
+		}
+		in.UserName = &userName
+	case "name.formatted", "displayname":
+		var fullName string
+		if err := json.Unmarshal(value, &fullName); err != nil {
+			return &scimRequestError{scimType: "invalidValue", detail: path + " must be a string"}
+		}
+		in.FullName = &fullName
+	case "name":
+		var name SCIMName
+		if err := json.Unmarshal(value, &name); err != nil {
+			return &scimRequestError{scimType: "invalidValue", detail: "name must be an object"}
+		}
+		if fullName := scimFullName(SCIMUser{Name: &name}); fullName != "" {
+			in.FullName = &fullName
+		}
+	}
+
+	return nil
+}
+
+// scimBool reads a boolean, also in the "True" and "False" strings some providers send.
+func scimBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+
+	return false, &scimRequestError{scimType: "invalidValue", detail: "active must be a boolean"}
+}
+
+// scimGroupPatch applies one PATCH operation on the members of a group to in.
+func scimGroupPatch(in *usecase.SCIMGroupUpdateInput, op SCIMPatchOperation) error {
+	path := strings.TrimSpace(op.Path)
+
+	if m := scimMemberFilter.FindStringSubmatch(path); m != nil {
+		if !strings.EqualFold(op.Op, "remove") {
+			return &scimRequestError{scimType: "invalidPath", detail: "only remove can target a single member"}
+		}
+		value, err := strconv.Unquote(m[1])
+		if err != nil {
+			return &scimRequestError{scimType: "invalidPath", detail: "invalid member filter"}
+		}
+		ids, err := scimMemberIDs([]SCIMMember{{Value: value}})
+		if err != nil {
+			return err
+		}
+		in.Remove = append(in.Remove, ids...)
+		return nil
+	}
+
+	var members []SCIMMember
+	switch {
+	case strings.EqualFold(path, "members"):
+		if len(op.Value) > 0 && string(op.Value) != "null" {
+			if err := json.Unmarshal(op.Value, &members); err != nil {
+				return &scimRequestError{scimType: "invalidValue", detail: "members must be a list"}
+			}
+		}
+	case path == "":
+		var value struct {
+			DisplayName *string       `json:"displayName"`
+			Members     *[]SCIMMember `json:"members"`
+		}
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return &scimRequestError{scimType: "invalidValue", detail: "value of an operation without path must be an object"}
+		}
+		if value.DisplayName != nil && !strings.EqualFold(*value.DisplayName, in.ID) {
+			return &scimRequestError{scimType: "mutability", detail: "displayName of a group cannot change"}
+		}
+		if value.Members == nil {
+			return nil
+		}
+		members = *value.Members
+	case strings.EqualFold(path, "displayName"):
+		return &scimRequestError{scimType: "mutability", detail: "displayName of a group cannot change"}
+	default:
+		return &scimRequestError{scimType: "invalidPath", detail: "unsupported path " + path}
+	}
+
+	ids, err := scimMemberIDs(members)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(op.Op) {
+	case "add":
+		in.Add = append(in.Add, ids...)
+	case "remove":
+		if len(members) == 0 {
+			// removing the attribute itself empties the group
+			in.ReplaceMembers, in.Members, in.Add, in.Remove = true, nil, nil, nil
+			return nil
+		}
+		in.Remove = append(in.Remove, ids...)
+	case "replace":
+		in.ReplaceMembers, in.Members, in.Add, in.Remove = true, ids, nil, nil
+	default:
+		return &scimRequestError{scimType: "invalidSyntax", detail: "unsupported op " + op.Op}
+	}
+
+	return nil
+}
+
+func scimMemberIDs(members []SCIMMember) ([]int64, error) {
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member.Value, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, &scimRequestError{scimType: "invalidValue", detail: "invalid member " + strconv.Quote(member.Value)}
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func toSCIMUser(user entity.User) SCIMUser {
+	id := strconv.FormatInt(user.ID, 10)
+	active := user.Status == entity.UserStatusActive
+
+	u := SCIMUser{
+		Schemas:     []string{scimSchemaUser},
+		ID:          id,
+		UserName:    user.Email,
+		Name:        &SCIMName{Formatted: user.FullName},
+		DisplayName: user.FullName,
+		Emails:      []SCIMEmail{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta:        &SCIMMeta{ResourceType: "User", Location: "/scim/v2/Users/" + id},
+	}
+	if !user.UpdatedAt.IsZero() {
+		u.Meta.LastModified = &user.UpdatedAt
+	}
+
+	return u
+}
+
+func toSCIMGroup(group usecase.SCIMGroup) SCIMGroup {
+	members := make([]SCIMMember, 0, len(group.Members))
+	for _, id := range group.Members {
+		members = append(members, SCIMMember{Value: strconv.FormatInt(id, 10)})
+	}
+
+	return SCIMGroup{
+		Schemas:     []string{scimSchemaGroup},
+		ID:          group.Name,
+		DisplayName: group.Name,
+		Members:     members,
+		Meta:        &SCIMMeta{ResourceType: "Group", Location: "/scim/v2/Groups/" + group.Name},
+	}
+}
@@ -1,52 +1,84 @@
 package identity
 
 import (
+	"cmp"
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
 	"github.com/casbin/casbin/v3"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 	"github.com/shandysiswandi/gobite/internal/identity/inbound"
+	"github.com/shandysiswandi/gobite/internal/identity/outbound/captcha"
 	"github.com/shandysiswandi/gobite/internal/identity/outbound/db"
+	"github.com/shandysiswandi/gobite/internal/identity/outbound/geoip"
 	"github.com/shandysiswandi/gobite/internal/identity/outbound/mq"
+	"github.com/shandysiswandi/gobite/internal/identity/outbound/oauth"
+	"github.com/shandysiswandi/gobite/internal/identity/outbound/saml"
+	"github.com/shandysiswandi/gobite/internal/identity/outbound/smsprovider"
 	"github.com/shandysiswandi/gobite/internal/identity/usecase"
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
 	"github.com/shandysiswandi/gobite/internal/pkg/clock"
 	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/crypto"
+	"github.com/shandysiswandi/gobite/internal/pkg/denylist"
 	"github.com/shandysiswandi/gobite/internal/pkg/goroutine"
 	"github.com/shandysiswandi/gobite/internal/pkg/hash"
 	"github.com/shandysiswandi/gobite/internal/pkg/idempotency"
 	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jobs"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
 	"github.com/shandysiswandi/gobite/internal/pkg/mfa"
 	"github.com/shandysiswandi/gobite/internal/pkg/otp"
+	"github.com/shandysiswandi/gobite/internal/pkg/pgxguard"
+	"github.com/shandysiswandi/gobite/internal/pkg/replay"
+	"github.com/shandysiswandi/gobite/internal/pkg/resilience"
+	"github.com/shandysiswandi/gobite/internal/pkg/retention"
 	"github.com/shandysiswandi/gobite/internal/pkg/router"
+	"github.com/shandysiswandi/gobite/internal/pkg/signedurl"
 	"github.com/shandysiswandi/gobite/internal/pkg/storage"
+	"github.com/shandysiswandi/gobite/internal/pkg/throttle"
 	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"github.com/shandysiswandi/gobite/internal/pkg/userdata"
 	"github.com/shandysiswandi/gobite/internal/pkg/validator"
+	"google.golang.org/grpc"
 )
 
 type Dependency struct {
-	DBConn          *pgxpool.Pool              `validate:"required"`
-	CacheConn       *redis.Client              `validate:"required"`
-	Goroutine       *goroutine.Manager         `validate:"required"`
-	Enforcer        *casbin.Enforcer           `validate:"required"`
-	Router          *router.Router             `validate:"required"`
-	Idempotency     idempotency.Idempotency    `validate:"required"`
-	Messaging       messaging.Messaging        `validate:"required"`
-	Storage         storage.Storage            `validate:"required"`
-	Config          config.Config              `validate:"required"`
-	Instrument      instrument.Instrumentation `validate:"required"`
-	UID             uid.NumberID               `validate:"required"`
-	UUID            uid.StringID               `validate:"required"`
-	OID             uid.StringID               `validate:"required"`
-	HMAC            hash.Hash                  `validate:"required"`
-	Bcrypt          hash.Hash                  `validate:"required"`
-	Argon2ID        hash.Hash                  `validate:"required"`
-	MFAEncryptor    mfa.Encryptor              `validate:"required"`
-	MFARecoveryCode mfa.RecoveryCodeGenerator  `validate:"required"`
-	Clock           clock.Clocker              `validate:"required"`
-	Totp            otp.OTP                    `validate:"required"`
-	Validator       validator.Validator        `validate:"required"`
-	JWT             jwt.JWT                    `validate:"required"`
+	Ctx                  context.Context
+	DBConn               *pgxguard.Pool     `validate:"required"`
+	CacheConn            *redis.Client      `validate:"required"`
+	Goroutine            *goroutine.Manager `validate:"required"`
+	Enforcer             *casbin.Enforcer   `validate:"required"`
+	AuthzShadow          *authz.Shadow
+	GRPC                 *grpc.Server
+	Retention            *retention.Scheduler       `validate:"required"`
+	Jobs                 *jobs.Registry             `validate:"required"`
+	Router               *router.Router             `validate:"required"`
+	Idempotency          idempotency.Idempotency    `validate:"required"`
+	Messaging            messaging.Messaging        `validate:"required"`
+	Storage              storage.Storage            `validate:"required"`
+	Config               config.Config              `validate:"required"`
+	Instrument           instrument.Instrumentation `validate:"required"`
+	UID                  uid.NumberID               `validate:"required"`
+	UUID                 uid.StringID               `validate:"required"`
+	OID                  uid.StringID               `validate:"required"`
+	HMAC                 hash.Hash                  `validate:"required"`
+	Bcrypt               hash.Hash                  `validate:"required"`
+	Argon2ID             hash.Hash                  `validate:"required"`
+	MFAEncryptor         mfa.Encryptor              `validate:"required"`
+	ColumnCipher         *crypto.Cipher             `validate:"required"`
+	MFARecoveryCode      mfa.RecoveryCodeGenerator  `validate:"required"`
+	SignedURL            signedurl.Signer           `validate:"required"`
+	Clock                clock.Clocker              `validate:"required"`
+	Totp                 otp.OTP                    `validate:"required"`
+	Validator            validator.Validator        `validate:"required"`
+	JWT                  jwt.JWT                    `validate:"required"`
+	HTTPClient           *http.Client               `validate:"required"`
+	UserData             *userdata.Registry         `validate:"required"`
+	ValidateNotification mq.NotificationValidator   `validate:"required"`
 }
 
 func New(dep Dependency) error {
@@ -54,13 +86,51 @@ func New(dep Dependency) error {
 		return err
 	}
 
-	dbAuth := db.NewDB(dep.DBConn, dep.Instrument)
-	repoMsg := mq.NewMessaging(dep.Messaging, dep.Instrument)
+	dbAuth := db.NewDB(dep.DBConn, dep.Instrument, dep.ColumnCipher)
+	repoMsg := mq.NewMessaging(dep.Messaging, dep.UUID, dep.Clock, dep.Instrument, dep.ValidateNotification)
+	repoOAuth := oauth.New(dep.HTTPClient, dep.Instrument, map[string]oauth.ProviderConfig{
+		oauth.ProviderGoogle: oauthProviderConfig(dep.Config, oauth.ProviderGoogle),
+		oauth.ProviderGitHub: oauthProviderConfig(dep.Config, oauth.ProviderGitHub),
+		oauth.ProviderOIDC:   oauthProviderConfig(dep.Config, oauth.ProviderOIDC),
+	})
+	repoSAML, err := saml.New(dep.HTTPClient, dep.Instrument, saml.Config{
+		EntityID:       dep.Config.GetString("modules.identity.saml.entity_id"),
+		MetadataURL:    dep.Config.GetString("modules.identity.saml.metadata_url"),
+		ACSURL:         dep.Config.GetString("modules.identity.saml.acs_url"),
+		IDPMetadata:    dep.Config.GetString("modules.identity.saml.idp_metadata"),
+		IDPMetadataURL: dep.Config.GetString("modules.identity.saml.idp_metadata_url"),
+		KeyPEM:         dep.Config.GetString("modules.identity.saml.sp_key"),
+		CertificatePEM: dep.Config.GetString("modules.identity.saml.sp_certificate"),
+	})
+	if err != nil {
+		return err
+	}
+	repoSMS := smsprovider.New(dep.HTTPClient, dep.Instrument, smsProviderConfig(dep.Config))
+	repoGeoIP := geoip.New(dep.HTTPClient, dep.Instrument, geoip.Config{
+		Driver:  strings.TrimSpace(dep.Config.GetString("modules.identity.login_history.geoip.driver")),
+		BaseURL: dep.Config.GetString("modules.identity.login_history.geoip.base_url"),
+		Token:   dep.Config.GetString("modules.identity.login_history.geoip.token"),
+	})
+	repoCaptcha := captcha.New(dep.HTTPClient, dep.Instrument, captcha.Config{
+		Driver:    strings.TrimSpace(dep.Config.GetString("modules.identity.captcha.driver")),
+		Secret:    dep.Config.GetString("modules.identity.captcha.secret"),
+		VerifyURL: dep.Config.GetString("modules.identity.captcha.verify_url"),
+		MinScore:  dep.Config.GetFloat64("modules.identity.captcha.min_score"),
+	})
 
 	uc := usecase.New(usecase.Dependency{
 		RepoDB:          dbAuth,
 		RepoMessaging:   repoMsg,
+		RepoAudit:       repoMsg,
+		RepoOAuth:       repoOAuth,
+		RepoSAML:        repoSAML,
+		RepoSMS:         repoSMS,
+		RepoGeoIP:       repoGeoIP,
+		RepoCaptcha:     repoCaptcha,
 		Idempotency:     dep.Idempotency,
+		Throttle:        throttle.New(dep.CacheConn),
+		Denylist:        denylist.New(dep.CacheConn),
+		Replay:          replay.New(dep.CacheConn),
 		Validator:       dep.Validator,
 		Config:          dep.Config,
 		Storage:         dep.Storage,
@@ -69,6 +139,7 @@ func New(dep Dependency) error {
 		Argon2ID:        dep.Argon2ID,
 		MFAEncryptor:    dep.MFAEncryptor,
 		MFARecoveryCode: dep.MFARecoveryCode,
+		SignedURL:       dep.SignedURL,
 		UID:             dep.UID,
 		UUID:            dep.UUID,
 		OID:             dep.OID,
@@ -77,10 +148,137 @@ func New(dep Dependency) error {
 		JWT:             dep.JWT,
 		Instrument:      dep.Instrument,
 		Enforcer:        dep.Enforcer,
+		AuthzShadow:     dep.AuthzShadow,
 		Goroutine:       dep.Goroutine,
+		UserData:        dep.UserData,
 	})
 
-	inbound.RegisterHTTPEndpoint(dep.Router, uc)
+	// identity owns roles and permissions, so it enriches every access token issued by the app
+	dep.JWT.Use(uc)
+
+	// exports and imports hold a database connection for their whole run
+	inbound.RegisterHTTPEndpoint(dep.Router, uc, refreshCookieConfig(dep.Config), flowCookieConfig(dep.Config),
+		router.ConcurrencyLimit(router.ConcurrencyLimitConfig{
+			Name:         "identity_users_export",
+			Limit:        dep.Config.GetInt("modules.identity.concurrency_limit.users_export"),
+			QueueTimeout: dep.Config.GetSecond("modules.identity.concurrency_limit.queue_timeout_seconds"),
+		}),
+		router.ConcurrencyLimit(router.ConcurrencyLimitConfig{
+			Name:         "identity_users_import",
+			Limit:        dep.Config.GetInt("modules.identity.concurrency_limit.users_import"),
+			QueueTimeout: dep.Config.GetSecond("modules.identity.concurrency_limit.queue_timeout_seconds"),
+		}),
+	)
+	inbound.RegisterSCIMEndpoint(dep.Router, uc)
+	if dep.GRPC != nil {
+		inbound.RegisterGRPCService(dep.GRPC, uc)
+	}
+	inbound.RegisterJob(dep.Retention, uc)
+	inbound.RegisterVerificationReminderJob(dep.Jobs, dep.Config, uc)
+	inbound.RegisterUserDeletionJob(dep.Jobs, dep.Config, uc)
+	inbound.RegisterAuditLogArchiveJob(dep.Jobs, dep.Config, uc)
+	inbound.RegisterEmailSealJob(dep.Jobs, dep.Config, uc)
+	if dep.Ctx != nil {
+		inbound.RegisterMQConsumer(dep.Ctx, dep.Config, dep.Jobs, dep.Messaging, dep.UUID, uc, dep.Instrument)
+	}
 
 	return nil
 }
+
+func oauthProviderConfig(cfg config.Config, name string) oauth.ProviderConfig {
+	prefix := "modules.identity.oauth.providers." + name
+
+	var scopes []string
+	for _, scope := range cfg.GetArray(prefix + ".scopes") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return oauth.ProviderConfig{
+		ClientID:     cfg.GetString(prefix + ".client_id"),
+		ClientSecret: cfg.GetString(prefix + ".client_secret"),
+		RedirectURL:  cfg.GetString(prefix + ".redirect_url"),
+		Scopes:       scopes,
+		Issuer:       cfg.GetString(prefix + ".issuer"),
+	}
+}
+
+func refreshCookieConfig(cfg config.Config) inbound.RefreshCookieConfig {
+	prefix := "modules.identity.refresh_cookie"
+
+	var clientTypes []string
+	for _, client := range cfg.GetArray(prefix + ".client_types") {
+		if client = strings.ToLower(strings.TrimSpace(client)); client != "" {
+			clientTypes = append(clientTypes, client)
+		}
+	}
+
+	sameSite := cookieSameSite(cfg.GetString(prefix+".same_site"), http.SameSiteStrictMode)
+
+	maxAge := cfg.GetDay(prefix + ".max_age_days")
+	if maxAge <= 0 {
+		maxAge = cfg.GetDay("modules.identity.refresh_token_ttl_days")
+	}
+
+	return inbound.RefreshCookieConfig{
+		Enabled:     cfg.GetBool(prefix + ".enabled"),
+		ClientTypes: clientTypes,
+		Name:        cmp.Or(strings.TrimSpace(cfg.GetString(prefix+".name")), "gobite_refresh"),
+		CSRFName:    cmp.Or(strings.TrimSpace(cfg.GetString(prefix+".csrf_name")), "gobite_csrf"),
+		Domain:      strings.TrimSpace(cfg.GetString(prefix + ".domain")),
+		Path:        cmp.Or(strings.TrimSpace(cfg.GetString(prefix+".path")), "/api/v1/identity"),
+		Secure:      cfg.GetBool(prefix + ".secure"),
+		SameSite:    sameSite,
+		MaxAge:      maxAge,
+	}
+}
+
+func flowCookieConfig(cfg config.Config) inbound.FlowCookieConfig {
+	prefix := "modules.identity.flow_cookie"
+
+	return inbound.FlowCookieConfig{
+		Name:     cmp.Or(strings.TrimSpace(cfg.GetString(prefix+".name")), "gobite_flow"),
+		Domain:   strings.TrimSpace(cfg.GetString(prefix + ".domain")),
+		Path:     cmp.Or(strings.TrimSpace(cfg.GetString(prefix+".path")), "/api/v1/identity"),
+		Secure:   cfg.GetBool(prefix + ".secure"),
+		SameSite: cookieSameSite(cfg.GetString(prefix+".same_site"), http.SameSiteLaxMode),
+	}
+}
+
+func cookieSameSite(value string, fallback http.SameSite) http.SameSite {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return fallback
+	}
+}
+
+func smsProviderConfig(cfg config.Config) smsprovider.Config {
+	return smsprovider.Config{
+		Driver:           strings.TrimSpace(cfg.GetString("mfa.sms.driver")),
+		From:             cfg.GetString("mfa.sms.from"),
+		BaseURL:          cfg.GetString("mfa.sms.base_url"),
+		TwilioAccountSID: cfg.GetString("mfa.sms.twilio.account_sid"),
+		TwilioAuthToken:  cfg.GetString("mfa.sms.twilio.auth_token"),
+		VonageAPIKey:     cfg.GetString("mfa.sms.vonage.api_key"),
+		VonageAPISecret:  cfg.GetString("mfa.sms.vonage.api_secret"),
+		Resilience: resilience.PolicyConfig{
+			Timeout: cfg.GetSecond("mfa.sms.resilience.timeout_seconds"),
+			Retry: resilience.RetryConfig{
+				MaxAttempts: cfg.GetInt("mfa.sms.resilience.max_attempts"),
+				BaseDelay:   time.Duration(cfg.GetInt("mfa.sms.resilience.base_delay_ms")) * time.Millisecond,
+				MaxDelay:    time.Duration(cfg.GetInt("mfa.sms.resilience.max_delay_ms")) * time.Millisecond,
+			},
+			Breaker: resilience.BreakerConfig{
+				FailureThreshold: cfg.GetInt("mfa.sms.resilience.failure_threshold"),
+				OpenTimeout:      cfg.GetSecond("mfa.sms.resilience.open_timeout_seconds"),
+			},
+		},
+	}
+}
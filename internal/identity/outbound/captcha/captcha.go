@@ -0,0 +1,160 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	DriverRecaptcha = "recaptcha"
+	DriverHCaptcha  = "hcaptcha"
+	DriverTurnstile = "turnstile"
+)
+
+// ErrDisabled is returned by Verify when no driver is configured.
+var ErrDisabled = errors.New("captcha: no driver configured")
+
+type Config struct {
+	// Driver selects the verification service; an empty or unknown driver disables it.
+	Driver string
+	// Secret is the server-side secret issued with the site key.
+	Secret string
+	// VerifyURL overrides the service verification endpoint, e.g. for a test double.
+	VerifyURL string
+	// MinScore rejects tokens scored below it by services that score (reCAPTCHA v3 and
+	// hCaptcha Enterprise); 0 accepts every passing token.
+	MinScore float64
+}
+
+// Captcha checks the response token a client got from a CAPTCHA widget. reCAPTCHA,
+// hCaptcha and Turnstile share the same siteverify protocol and differ only in endpoint.
+type Captcha struct {
+	client    *http.Client
+	ins       instrument.Instrumentation
+	driver    string
+	verifyURL string
+	secret    string
+	minScore  float64
+}
+
+// New creates the adapter for cfg.Driver.
+func New(client *http.Client, ins instrument.Instrumentation, cfg Config) *Captcha {
+	c := &Captcha{client: client, ins: ins, secret: cfg.Secret, minScore: cfg.MinScore}
+
+	var fallback string
+	switch cfg.Driver {
+	case DriverRecaptcha:
+		fallback = "https://www.google.com/recaptcha/api/siteverify"
+	case DriverHCaptcha:
+		fallback = "https://api.hcaptcha.com/siteverify"
+	case DriverTurnstile:
+		fallback = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	default:
+		return c
+	}
+
+	c.driver = cfg.Driver
+	c.verifyURL = fallback
+	if override := strings.TrimSpace(cfg.VerifyURL); override != "" {
+		c.verifyURL = override
+	}
+
+	return c
+}
+
+func (c *Captcha) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return c.ins.Tracer("identity.outbound.captcha").Start(ctx, name)
+}
+
+// Enabled reports whether a driver is configured.
+func (c *Captcha) Enabled() bool {
+	return c.driver != ""
+}
+
+// Verify reports whether the service accepts token; a missing token is not accepted. The
+// error is set only when the service could not be asked. remoteIP is optional and lets the
+// service compare it with the address that solved the challenge.
+func (c *Captcha) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	ctx, span := c.startSpan(ctx, "Verify")
+	defer span.End()
+
+	if c.driver == "" {
+		return false, ErrDisabled
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return false, nil
+	}
+
+	ok, err := c.verify(ctx, token, remoteIP)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return false, err
+	}
+
+	return ok, nil
+}
+
+func (c *Captcha) verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	form := url.Values{"secret": {c.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	const maxBody = 64 << 10
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return false, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("captcha: %s returned status %d", c.driver, resp.StatusCode)
+	}
+
+	var result struct {
+		Success    bool     `json:"success"`
+		Score      *float64 `json:"score"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return false, fmt.Errorf("captcha: decode %s response: %w", c.driver, err)
+	}
+
+	// a wrong secret is a configuration error, not a bad token
+	for _, code := range result.ErrorCodes {
+		if code == "invalid-input-secret" || code == "missing-input-secret" {
+			return false, fmt.Errorf("captcha: %s rejected the secret: %s", c.driver, code)
+		}
+	}
+
+	if !result.Success {
+		return false, nil
+	}
+
+	return result.Score == nil || *result.Score >= c.minScore, nil
+}
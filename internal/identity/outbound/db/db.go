@@ -3,31 +3,134 @@ package db
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/identity/outbound/db/sqlc"
+	"github.com/shandysiswandi/gobite/internal/pkg/crypto"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
-	"github.com/shandysiswandi/gobite/internal/pkg/sqlc"
+	"github.com/shandysiswandi/gobite/internal/pkg/pgxguard"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type DB struct {
-	conn  *pgxpool.Pool
-	query *sqlc.Queries
-	ins   instrument.Instrumentation
+	conn   *pgxguard.Pool
+	query  *sqlc.Queries
+	ins    instrument.Instrumentation
+	cipher *crypto.Cipher
 }
 
-func NewDB(conn *pgxpool.Pool, ins instrument.Instrumentation) *DB {
+func NewDB(conn *pgxguard.Pool, ins instrument.Instrumentation, cipher *crypto.Cipher) *DB {
 	return &DB{
-		conn:  conn,
-		query: sqlc.New(conn),
-		ins:   ins,
+		conn:   conn,
+		query:  sqlc.New(conn),
+		ins:    ins,
+		cipher: cipher,
 	}
 }
 
+// txKey carries the transaction of WithinTx in a context.
+type txKey struct{}
+
+// WithinTx runs fn in one transaction, committed when fn returns nil and rolled back
+// otherwise. Every call made with the context passed to fn joins it; a method that runs its
+// own transaction takes a savepoint inside it, and a nested WithinTx joins the outer one.
+func (s *DB) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	ctx, span := s.startSpan(ctx, "WithinTx")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return s.mapError(err)
+	}
+	defer func() {
+		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return s.mapError(tx.Commit(ctx))
+}
+
+// dbtx is the transaction of WithinTx when ctx carries one, and the pool otherwise.
+func (s *DB) dbtx(ctx context.Context) sqlc.DBTX {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return s.conn
+}
+
+func (s *DB) queries(ctx context.Context) *sqlc.Queries {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return s.query.WithTx(tx)
+	}
+
+	return s.query
+}
+
+// beginTx starts the transaction of a method, as a savepoint when ctx is already in WithinTx.
+func (s *DB) beginTx(ctx context.Context) (pgx.Tx, error) {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx.Begin(ctx)
+	}
+
+	return s.conn.BeginTx(ctx, pgx.TxOptions{})
+}
+
+func emailScope(userID int64) crypto.Scope {
+	return crypto.Scope{OwnerID: userID, Purpose: crypto.PurposeEmail}
+}
+
+// emailColumns returns the plaintext and sealed email columns of a user. An address stored
+// with a lookup hash is also sealed, and sealedOnly leaves the plaintext column NULL.
+func (s *DB) emailColumns(userID int64, email, emailHash string, sealedOnly bool) (pgtype.Text, []byte, error) {
+	plain := pgtype.Text{Valid: true, String: email}
+	if emailHash == "" {
+		return plain, nil, nil
+	}
+
+	sealed, err := s.cipher.EncryptText(plain, emailScope(userID))
+	if err != nil {
+		return pgtype.Text{}, nil, err
+	}
+
+	if sealedOnly {
+		return pgtype.Text{}, sealed, nil
+	}
+
+	return plain, sealed, nil
+}
+
+// openEmail returns the plaintext email of a row, opening the sealed copy when the row keeps
+// only that.
+func (s *DB) openEmail(userID int64, email pgtype.Text, sealed []byte) (string, error) {
+	if email.Valid {
+		return email.String, nil
+	}
+
+	opened, err := s.cipher.DecryptText(sealed, emailScope(userID))
+	if err != nil {
+		return "", err
+	}
+
+	return opened.String, nil
+}
+
 // - 23505 unique violation → maybe goerror.ErrConflict
 // - 23503 foreign_key_violation → maybe goerror.ErrNotFound or a specific “invalid reference”
 // - 23502 not_null_violation → goerror.ErrInvalid / validation
@@ -67,6 +170,184 @@ func (s *DB) DeleteChallenge(ctx context.Context, id int64) (err error) {
 	ctx, span := s.startSpan(ctx, "DeleteChallenge")
 	defer func() { s.endSpan(span, err) }()
 
-	err = s.mapError(s.query.DeleteIdentityChallengeByID(ctx, id))
+	err = s.mapError(s.queries(ctx).DeleteIdentityChallengeByID(ctx, id))
 	return err
 }
+
+func (s *DB) DeleteChallengeByUserPurpose(ctx context.Context, userID int64, p entity.ChallengePurpose) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteChallengeByUserPurpose")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.queries(ctx).DeleteIdentityChallengeByUserPurpose(ctx, sqlc.DeleteIdentityChallengeByUserPurposeParams{
+		UserID:  userID,
+		Purpose: p,
+	})
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
+}
+
+// DeletePendingUserDeletion cancels a scheduled deletion and reports whether one was pending.
+func (s *DB) DeletePendingUserDeletion(ctx context.Context, userID int64) (_ bool, err error) {
+	ctx, span := s.startSpan(ctx, "DeletePendingUserDeletion")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.queries(ctx).DeleteIdentityPendingUserDeletion(ctx, userID)
+	if err != nil {
+		return false, s.mapError(err)
+	}
+
+	return affected > 0, nil
+}
+
+func (s *DB) DeleteAuditLogByIDs(ctx context.Context, ids []int64) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteAuditLogByIDs")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.queries(ctx).DeleteIdentityAuditLogByIDs(ctx, ids)
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
+}
+
+func (s *DB) DeleteAuditLogBefore(ctx context.Context, before time.Time, limit int32) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteAuditLogBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.queries(ctx).DeleteIdentityAuditLogBefore(ctx, sqlc.DeleteIdentityAuditLogBeforeParams{
+		Before:    pgtype.Timestamptz{Valid: true, Time: before},
+		PageLimit: limit,
+	})
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
+}
+
+func (s *DB) DeleteChallengeExpiredBefore(ctx context.Context, before time.Time, limit int32) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteChallengeExpiredBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.queries(ctx).DeleteIdentityChallengeExpiredBefore(ctx, sqlc.DeleteIdentityChallengeExpiredBeforeParams{
+		Before:    pgtype.Timestamptz{Valid: true, Time: before},
+		PageLimit: limit,
+	})
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
+}
+
+func (s *DB) DeleteRefreshTokenExpiredBefore(ctx context.Context, before time.Time, limit int32) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteRefreshTokenExpiredBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.queries(ctx).DeleteIdentityRefreshTokenExpiredBefore(ctx, sqlc.DeleteIdentityRefreshTokenExpiredBeforeParams{
+		Before:    pgtype.Timestamptz{Valid: true, Time: before},
+		PageLimit: limit,
+	})
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
+}
+
+func (s *DB) DeleteTrustedDeviceExpiredBefore(ctx context.Context, before time.Time, limit int32) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteTrustedDeviceExpiredBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.queries(ctx).DeleteIdentityTrustedDeviceExpiredBefore(ctx, sqlc.DeleteIdentityTrustedDeviceExpiredBeforeParams{
+		Before:    pgtype.Timestamptz{Valid: true, Time: before},
+		PageLimit: limit,
+	})
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
+}
+
+func (s *DB) DeleteLoginEventBefore(ctx context.Context, before time.Time, limit int32) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteLoginEventBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.queries(ctx).DeleteIdentityLoginEventBefore(ctx, sqlc.DeleteIdentityLoginEventBeforeParams{
+		Before:    pgtype.Timestamptz{Valid: true, Time: before},
+		PageLimit: limit,
+	})
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
+}
+
+// DeleteServiceAccount removes a service account and returns its client ID.
+func (s *DB) DeleteServiceAccount(ctx context.Context, id int64) (_ string, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteServiceAccount")
+	defer func() { s.endSpan(span, err) }()
+
+	clientID, err := s.queries(ctx).DeleteIdentityServiceAccount(ctx, id)
+	if err != nil {
+		return "", s.mapError(err)
+	}
+
+	return clientID, nil
+}
+
+func (s *DB) DeleteUserConnection(ctx context.Context, id, userID int64) (_ string, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteUserConnection")
+	defer func() { s.endSpan(span, err) }()
+
+	provider, err := s.queries(ctx).DeleteIdentityUserConnection(ctx, sqlc.DeleteIdentityUserConnectionParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return "", s.mapError(err)
+	}
+
+	return provider, nil
+}
+
+func (s *DB) DeleteOrganization(ctx context.Context, id int64) (err error) {
+	ctx, span := s.startSpan(ctx, "DeleteOrganization")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).DeleteIdentityOrganization(ctx, id)
+	if err != nil {
+		return s.mapError(err)
+	}
+
+	if rows == 0 {
+		return goerror.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *DB) DeleteOrganizationMember(ctx context.Context, orgID, userID int64) (err error) {
+	ctx, span := s.startSpan(ctx, "DeleteOrganizationMember")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).DeleteIdentityOrganizationMember(ctx, sqlc.DeleteIdentityOrganizationMemberParams{
+		OrganizationID: orgID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return s.mapError(err)
+	}
+
+	if rows == 0 {
+		return goerror.ErrNotFound
+	}
+
+	return nil
+}
@@ -2,17 +2,19 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
-	"github.com/shandysiswandi/gobite/internal/pkg/sqlc"
+	"github.com/shandysiswandi/gobite/internal/identity/outbound/db/sqlc"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 )
 
 func (s *DB) CreateChallenge(ctx context.Context, in entity.Challenge) (err error) {
 	ctx, span := s.startSpan(ctx, "CreateChallenge")
 	defer func() { s.endSpan(span, err) }()
 
-	err = s.mapError(s.query.CreateIdentityChallenge(ctx, sqlc.CreateIdentityChallengeParams{
+	err = s.mapError(s.queries(ctx).CreateIdentityChallenge(ctx, sqlc.CreateIdentityChallengeParams{
 		ID:        in.ID,
 		UserID:    in.UserID,
 		Token:     in.Token,
@@ -23,16 +25,182 @@ func (s *DB) CreateChallenge(ctx context.Context, in entity.Challenge) (err erro
 	return err
 }
 
+func (s *DB) CreateUserConnection(ctx context.Context, in entity.UserConnection) (err error) {
+	ctx, span := s.startSpan(ctx, "CreateUserConnection")
+	defer func() { s.endSpan(span, err) }()
+
+	err = s.mapError(s.queries(ctx).CreateIdentityUserConnection(ctx, sqlc.CreateIdentityUserConnectionParams{
+		ID:             in.ID,
+		UserID:         in.UserID,
+		Provider:       in.Provider,
+		ProviderUserID: in.ProviderUserID,
+	}))
+	return err
+}
+
+func (s *DB) CreateAuditLog(ctx context.Context, in entity.AuditLog) (err error) {
+	ctx, span := s.startSpan(ctx, "CreateAuditLog")
+	defer func() { s.endSpan(span, err) }()
+
+	err = s.mapError(s.queries(ctx).CreateIdentityAuditLog(ctx, sqlc.CreateIdentityAuditLogParams{
+		ID:           in.ID,
+		ActorID:      in.ActorID,
+		TargetUserID: in.TargetUserID,
+		Action:       in.Action.String(),
+		Metadata:     in.Metadata,
+	}))
+	return err
+}
+
 func (s *DB) CreateRefreshToken(ctx context.Context, in entity.RefreshToken) (err error) {
 	ctx, span := s.startSpan(ctx, "CreateRefreshToken")
 	defer func() { s.endSpan(span, err) }()
 
-	err = s.mapError(s.query.CreateIdentityRefreshToken(ctx, sqlc.CreateIdentityRefreshTokenParams{
+	meta, err := entity.EncodeMetadata(&in.Metadata)
+	if err != nil {
+		return err
+	}
+
+	err = s.mapError(s.queries(ctx).CreateIdentityRefreshToken(ctx, sqlc.CreateIdentityRefreshTokenParams{
 		ID:        in.ID,
 		UserID:    in.UserID,
 		Token:     in.Token,
 		ExpiresAt: pgtype.Timestamptz{Valid: true, Time: in.ExpiresAt},
-		Metadata:  in.Metadata,
+		Metadata:  meta,
+	}))
+	return err
+}
+
+func (s *DB) CreateUserDeletion(ctx context.Context, userID int64, scheduledAt time.Time) (_ *entity.UserDeletion, err error) {
+	ctx, span := s.startSpan(ctx, "CreateUserDeletion")
+	defer func() { s.endSpan(span, err) }()
+
+	row, err := s.queries(ctx).CreateIdentityUserDeletion(ctx, sqlc.CreateIdentityUserDeletionParams{
+		UserID:      userID,
+		ScheduledAt: pgtype.Timestamptz{Valid: true, Time: scheduledAt},
+	})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	return toUserDeletion(row), nil
+}
+
+func (s *DB) CreateExportJob(ctx context.Context, in entity.ExportJob) (_ *entity.ExportJob, err error) {
+	ctx, span := s.startSpan(ctx, "CreateExportJob")
+	defer func() { s.endSpan(span, err) }()
+
+	row, err := s.queries(ctx).CreateIdentityExportJob(ctx, sqlc.CreateIdentityExportJobParams{
+		ID:          in.ID,
+		RequestedBy: in.RequestedBy,
+		Format:      in.Format,
+		Filters:     in.Filters,
+	})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	return toExportJob(row), nil
+}
+
+func (s *DB) CreateAPIKey(ctx context.Context, in entity.APIKey, tokenHash string) (err error) {
+	ctx, span := s.startSpan(ctx, "CreateAPIKey")
+	defer func() { s.endSpan(span, err) }()
+
+	expiresAt := pgtype.Timestamptz{}
+	if in.ExpiresAt != nil {
+		expiresAt = pgtype.Timestamptz{Valid: true, Time: *in.ExpiresAt}
+	}
+
+	err = s.mapError(s.queries(ctx).CreateIdentityAPIKey(ctx, sqlc.CreateIdentityAPIKeyParams{
+		ID:        in.ID,
+		UserID:    in.UserID,
+		Name:      in.Name,
+		Prefix:    in.Prefix,
+		Token:     tokenHash,
+		ExpiresAt: expiresAt,
 	}))
 	return err
 }
+
+func (s *DB) CreateTrustedDevice(ctx context.Context, in entity.TrustedDevice, tokenHash string) (err error) {
+	ctx, span := s.startSpan(ctx, "CreateTrustedDevice")
+	defer func() { s.endSpan(span, err) }()
+
+	err = s.mapError(s.queries(ctx).CreateIdentityTrustedDevice(ctx, sqlc.CreateIdentityTrustedDeviceParams{
+		ID:        in.ID,
+		UserID:    in.UserID,
+		Token:     tokenHash,
+		UserAgent: in.UserAgent,
+		Ip:        in.IP,
+		ExpiresAt: pgtype.Timestamptz{Valid: true, Time: in.ExpiresAt},
+	}))
+	return err
+}
+
+func (s *DB) CreateServiceAccount(ctx context.Context, in entity.ServiceAccount, secretHash string) (err error) {
+	ctx, span := s.startSpan(ctx, "CreateServiceAccount")
+	defer func() { s.endSpan(span, err) }()
+
+	err = s.mapError(s.queries(ctx).CreateIdentityServiceAccount(ctx, sqlc.CreateIdentityServiceAccountParams{
+		ID:        in.ID,
+		Name:      in.Name,
+		ClientID:  in.ClientID,
+		Secret:    secretHash,
+		CreatedBy: in.CreatedBy,
+	}))
+	return err
+}
+
+func (s *DB) CreateLoginEvent(ctx context.Context, in entity.LoginEvent) (err error) {
+	ctx, span := s.startSpan(ctx, "CreateLoginEvent")
+	defer func() { s.endSpan(span, err) }()
+
+	err = s.mapError(s.queries(ctx).CreateIdentityLoginEvent(ctx, sqlc.CreateIdentityLoginEventParams{
+		ID:            in.ID,
+		UserID:        in.UserID,
+		Success:       in.Success,
+		Method:        in.Method.String(),
+		FailureReason: in.FailureReason,
+		Ip:            in.IP,
+		UserAgent:     in.UserAgent,
+	}))
+	return err
+}
+
+// UpsertUserDevice remembers a device, or refreshes when and where a known one was last seen.
+func (s *DB) UpsertUserDevice(ctx context.Context, in entity.UserDevice) (err error) {
+	ctx, span := s.startSpan(ctx, "UpsertUserDevice")
+	defer func() { s.endSpan(span, err) }()
+
+	err = s.mapError(s.queries(ctx).UpsertIdentityUserDevice(ctx, sqlc.UpsertIdentityUserDeviceParams{
+		ID:          in.ID,
+		UserID:      in.UserID,
+		Fingerprint: in.Fingerprint,
+		UserAgent:   in.UserAgent,
+		LastIp:      in.IP,
+		Country:     in.Country,
+	}))
+	return err
+}
+
+// CreateOrganizationMember adds userID to an organization, returning goerror.ErrConflict
+// when they already belong to it.
+func (s *DB) CreateOrganizationMember(ctx context.Context, orgID, userID int64) (err error) {
+	ctx, span := s.startSpan(ctx, "CreateOrganizationMember")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).CreateIdentityOrganizationMember(ctx, sqlc.CreateIdentityOrganizationMemberParams{
+		OrganizationID: orgID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return s.mapError(err)
+	}
+
+	if rows == 0 {
+		return goerror.ErrConflict
+	}
+
+	return nil
+}
@@ -3,44 +3,188 @@ package db
 import (
 	"context"
 	"fmt"
+	"iter"
+	"log/slog"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
-	"github.com/shandysiswandi/gobite/internal/pkg/sqlc"
+	"github.com/shandysiswandi/gobite/internal/identity/outbound/db/sqlc"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 )
 
 func (s *DB) GetUserLoginInfo(ctx context.Context, email string) (_ *entity.UserLoginInfo, err error) {
 	ctx, span := s.startSpan(ctx, "GetUserLoginInfo")
 	defer func() { s.endSpan(span, err) }()
 
-	result, err := s.query.GetIdentityUserLoginInfo(ctx, email)
+	result, err := s.queries(ctx).GetIdentityUserLoginInfo(ctx, email)
 	if err != nil {
 		return nil, s.mapError(err)
 	}
 
+	stored, err := s.openEmail(result.ID, result.Email, result.EmailEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
 	return &entity.UserLoginInfo{
 		ID:       result.ID,
-		Email:    result.Email,
+		Email:    stored,
 		Status:   result.Status,
 		Password: result.Password,
 		HasMFA:   result.HasMfa,
+
+		MustResetPassword: result.MustResetPassword,
+		MFARequired:       result.MfaRequired,
 	}, nil
 }
 
-func (s *DB) GetUserCredentialInfo(ctx context.Context, id int64) (_ *entity.UserCredentialInfo, err error) {
-	ctx, span := s.startSpan(ctx, "GetUserCredentialInfo")
+func (s *DB) GetUserLoginInfoByEmailHash(ctx context.Context, emailHash string) (_ *entity.UserLoginInfo, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserLoginInfoByEmailHash")
 	defer func() { s.endSpan(span, err) }()
 
-	result, err := s.query.GetIdentityUserCredentialInfo(ctx, id)
+	result, err := s.queries(ctx).GetIdentityUserLoginInfoByEmailHash(ctx, pgtype.Text{Valid: true, String: emailHash})
 	if err != nil {
 		return nil, s.mapError(err)
 	}
 
-	return &entity.UserCredentialInfo{
+	email, err := s.openEmail(result.ID, result.Email, result.EmailEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.UserLoginInfo{
+		ID:       result.ID,
+		Email:    email,
+		Status:   result.Status,
+		Password: result.Password,
+		HasMFA:   result.HasMfa,
+
+		MustResetPassword: result.MustResetPassword,
+		MFARequired:       result.MfaRequired,
+	}, nil
+}
+
+func (s *DB) GetUserLoginInfoByUsername(ctx context.Context, username string) (_ *entity.UserLoginInfo, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserLoginInfoByUsername")
+	defer func() { s.endSpan(span, err) }()
+
+	result, err := s.queries(ctx).GetIdentityUserLoginInfoByUsername(ctx, username)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	email, err := s.openEmail(result.ID, result.Email, result.EmailEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.UserLoginInfo{
 		ID:       result.ID,
+		Email:    email,
 		Status:   result.Status,
-		Email:    result.Email,
 		Password: result.Password,
+		HasMFA:   result.HasMfa,
+
+		MustResetPassword: result.MustResetPassword,
+		MFARequired:       result.MfaRequired,
+	}, nil
+}
+
+// GetUserAttributes returns the profile attributes of the user. With lock, the user row
+// stays locked until the transaction of ctx ends, so a read-modify-write is not lost.
+func (s *DB) GetUserAttributes(ctx context.Context, userID int64, lock bool) (_ valueobject.JSONMap, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserAttributes")
+	defer func() { s.endSpan(span, err) }()
+
+	var attrs valueobject.JSONMap
+	if lock {
+		attrs, err = s.queries(ctx).LockIdentityUserAttributes(ctx, userID)
+	} else {
+		attrs, err = s.queries(ctx).GetIdentityUserAttributes(ctx, userID)
+	}
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	return attrs, nil
+}
+
+// GetUsername returns the username of the user, empty when they have none.
+func (s *DB) GetUsername(ctx context.Context, userID int64) (_ string, err error) {
+	ctx, span := s.startSpan(ctx, "GetUsername")
+	defer func() { s.endSpan(span, err) }()
+
+	username, err := s.queries(ctx).GetIdentityUserUsername(ctx, userID)
+	if err != nil {
+		return "", s.mapError(err)
+	}
+
+	return username.String, nil
+}
+
+// IsUsernameTaken reports whether any account, deleted ones included, holds username.
+func (s *DB) IsUsernameTaken(ctx context.Context, username string) (_ bool, err error) {
+	ctx, span := s.startSpan(ctx, "IsUsernameTaken")
+	defer func() { s.endSpan(span, err) }()
+
+	taken, err := s.queries(ctx).IsIdentityUsernameTaken(ctx, username)
+	if err != nil {
+		return false, s.mapError(err)
+	}
+
+	return taken, nil
+}
+
+func (s *DB) GetUserLoginInfoByConnection(ctx context.Context, provider, providerUserID string) (_ *entity.UserLoginInfo, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserLoginInfoByConnection")
+	defer func() { s.endSpan(span, err) }()
+
+	result, err := s.queries(ctx).GetIdentityUserLoginInfoByConnection(ctx, sqlc.GetIdentityUserLoginInfoByConnectionParams{
+		Provider:       provider,
+		ProviderUserID: providerUserID,
+	})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	email, err := s.openEmail(result.ID, result.Email, result.EmailEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.UserLoginInfo{
+		ID:       result.ID,
+		Email:    email,
+		Status:   result.Status,
+		Password: result.Password,
+		HasMFA:   result.HasMfa,
+
+		MustResetPassword: result.MustResetPassword,
+		MFARequired:       result.MfaRequired,
+	}, nil
+}
+
+func (s *DB) GetUserCredentialInfo(ctx context.Context, id int64) (_ *entity.UserCredentialInfo, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserCredentialInfo")
+	defer func() { s.endSpan(span, err) }()
+
+	result, err := s.queries(ctx).GetIdentityUserCredentialInfo(ctx, id)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	email, err := s.openEmail(result.ID, result.Email, result.EmailEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.UserCredentialInfo{
+		ID:                result.ID,
+		Status:            result.Status,
+		Email:             email,
+		Password:          result.Password,
+		PasswordUpdatedAt: result.UpdatedAt.Time,
 	}, nil
 }
 
@@ -48,7 +192,7 @@ func (s *DB) GetChallengeUserByTokenPurpose(ctx context.Context, token string, p
 	ctx, span := s.startSpan(ctx, "GetChallengeUserByTokenPurpose")
 	defer func() { s.endSpan(span, err) }()
 
-	result, err := s.query.GetIdentityChallengeUserByTokenPurpose(ctx, sqlc.GetIdentityChallengeUserByTokenPurposeParams{
+	result, err := s.queries(ctx).GetIdentityChallengeUserByTokenPurpose(ctx, sqlc.GetIdentityChallengeUserByTokenPurposeParams{
 		Token:   token,
 		Purpose: p,
 	})
@@ -56,13 +200,18 @@ func (s *DB) GetChallengeUserByTokenPurpose(ctx context.Context, token string, p
 		return nil, s.mapError(err)
 	}
 
+	email, err := s.openEmail(result.UserID, result.Email, result.EmailEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
 	return &entity.ChallengeUser{
 		ChallengeID:       result.ID,
 		ChallengePurpose:  result.Purpose,
 		ChallengeToken:    result.Token,
 		ChallengeMetadata: result.Metadata,
 		UserID:            result.UserID,
-		UserEmail:         result.Email,
+		UserEmail:         email,
 		UserStatus:        result.Status,
 	}, nil
 }
@@ -71,7 +220,7 @@ func (s *DB) GetUserRefreshToken(ctx context.Context, token string) (_ *entity.U
 	ctx, span := s.startSpan(ctx, "GetUserRefreshToken")
 	defer func() { s.endSpan(span, err) }()
 
-	result, err := s.query.GetIdentityUserRefreshToken(ctx, token)
+	result, err := s.queries(ctx).GetIdentityUserRefreshToken(ctx, token)
 	if err != nil {
 		return nil, s.mapError(err)
 	}
@@ -81,45 +230,145 @@ func (s *DB) GetUserRefreshToken(ctx context.Context, token string) (_ *entity.U
 		replacedByTokenID = &result.ReplacedByTokenID.Int64
 	}
 
+	// the metadata only describes the client, so a token with unreadable metadata still refreshes
+	meta, err := entity.DecodeMetadata[entity.SessionMetadata](result.Metadata)
+	if err != nil {
+		slog.WarnContext(ctx, "invalid refresh token metadata", "refresh_token_id", result.ID, "error", err)
+	}
+
+	email, err := s.openEmail(result.UserID, result.Email, result.EmailEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
 	return &entity.UserRefreshToken{
 		UserID:                   result.UserID,
-		UserEmail:                result.Email,
+		UserEmail:                email,
 		UserStatus:               result.UserStatus,
 		RefreshID:                result.ID,
 		RefreshToken:             result.Token,
 		RefreshRevoked:           result.Revoked,
 		RefreshReplacedByTokenID: replacedByTokenID,
 		RefreshExpiresAt:         result.ExpiresAt.Time,
+		RefreshSessionStartedAt:  result.SessionStartedAt.Time,
+		RefreshMetadata:          meta,
 	}, nil
 }
 
+func (s *DB) GetActiveSessions(ctx context.Context, userID int64) (_ []entity.Session, err error) {
+	ctx, span := s.startSpan(ctx, "GetActiveSessions")
+	defer func() { s.endSpan(span, err) }()
+
+	results, err := s.queries(ctx).GetIdentityActiveRefreshTokensByUserID(ctx, userID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	sessions := make([]entity.Session, 0, len(results))
+	for _, result := range results {
+		// a session with unreadable metadata is still listed, so it can be revoked
+		meta, err := entity.DecodeMetadata[entity.SessionMetadata](result.Metadata)
+		if err != nil {
+			slog.WarnContext(ctx, "invalid refresh token metadata", "refresh_token_id", result.ID, "error", err)
+		}
+
+		sessions = append(sessions, entity.Session{
+			ID:               result.ID,
+			IP:               meta.IP,
+			UserAgent:        meta.UserAgent,
+			Client:           meta.Client,
+			DeviceName:       meta.DeviceName,
+			CreatedAt:        result.CreatedAt.Time,
+			SessionStartedAt: result.SessionStartedAt.Time,
+			ExpiresAt:        result.ExpiresAt.Time,
+		})
+	}
+
+	return sessions, nil
+}
+
 func (s *DB) GetUserByEmail(ctx context.Context, email string, includeDeleted bool) (_ *entity.User, err error) {
 	ctx, span := s.startSpan(ctx, "GetUserByEmail")
 	defer func() { s.endSpan(span, err) }()
 
 	if includeDeleted {
-		result, err := s.query.GetIdentityUserByEmailIncludeDeleted(ctx, email)
+		result, err := s.queries(ctx).GetIdentityUserByEmailIncludeDeleted(ctx, email)
 		if err != nil {
 			return nil, s.mapError(err)
 		}
 
+		stored, err := s.openEmail(result.ID, result.Email, result.EmailEncrypted)
+		if err != nil {
+			return nil, err
+		}
+
 		return &entity.User{
 			ID:        result.ID,
-			Email:     result.Email,
+			Email:     stored,
 			FullName:  result.FullName,
 			AvatarURL: result.AvatarUrl,
 			Status:    result.Status,
 		}, nil
 	}
 
-	result, err := s.query.GetIdentityUserByEmail(ctx, email)
+	result, err := s.queries(ctx).GetIdentityUserByEmail(ctx, email)
 	if err != nil {
 		return nil, s.mapError(err)
 	}
 
+	stored, err := s.openEmail(result.ID, result.Email, result.EmailEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
 	return &entity.User{
 		ID:        result.ID,
-		Email:     result.Email,
+		Email:     stored,
+		FullName:  result.FullName,
+		AvatarURL: result.AvatarUrl,
+		Status:    result.Status,
+	}, nil
+}
+
+func (s *DB) GetUserByEmailHash(ctx context.Context, emailHash string, includeDeleted bool) (_ *entity.User, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserByEmailHash")
+	defer func() { s.endSpan(span, err) }()
+
+	arg := pgtype.Text{Valid: true, String: emailHash}
+
+	if includeDeleted {
+		result, err := s.queries(ctx).GetIdentityUserByEmailHashIncludeDeleted(ctx, arg)
+		if err != nil {
+			return nil, s.mapError(err)
+		}
+
+		email, err := s.openEmail(result.ID, result.Email, result.EmailEncrypted)
+		if err != nil {
+			return nil, err
+		}
+
+		return &entity.User{
+			ID:        result.ID,
+			Email:     email,
+			FullName:  result.FullName,
+			AvatarURL: result.AvatarUrl,
+			Status:    result.Status,
+		}, nil
+	}
+
+	result, err := s.queries(ctx).GetIdentityUserByEmailHash(ctx, arg)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	email, err := s.openEmail(result.ID, result.Email, result.EmailEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.User{
+		ID:        result.ID,
+		Email:     email,
 		FullName:  result.FullName,
 		AvatarURL: result.AvatarUrl,
 		Status:    result.Status,
@@ -130,7 +379,7 @@ func (s *DB) GetMFAFactorByUserID(ctx context.Context, userID int64, isVerified
 	ctx, span := s.startSpan(ctx, "GetMFAFactorByUserID")
 	defer func() { s.endSpan(span, err) }()
 
-	items, err := s.query.GetIdentityMFAFactorByUserID(ctx, sqlc.GetIdentityMFAFactorByUserIDParams{
+	items, err := s.queries(ctx).GetIdentityMFAFactorByUserID(ctx, sqlc.GetIdentityMFAFactorByUserIDParams{
 		UserID:     userID,
 		IsVerified: isVerified,
 	})
@@ -160,7 +409,7 @@ func (s *DB) GetMFAFactorByID(ctx context.Context, id int64, userID int64) (_ *e
 	ctx, span := s.startSpan(ctx, "GetMFAFactorByID")
 	defer func() { s.endSpan(span, err) }()
 
-	result, err := s.query.GetIdentityMFAFactorByID(ctx, sqlc.GetIdentityMFAFactorByIDParams{
+	result, err := s.queries(ctx).GetIdentityMFAFactorByID(ctx, sqlc.GetIdentityMFAFactorByIDParams{
 		ID:     id,
 		UserID: userID,
 	})
@@ -181,11 +430,40 @@ func (s *DB) GetMFAFactorByID(ctx context.Context, id int64, userID int64) (_ *e
 	return item, nil
 }
 
+func (s *DB) GetMFAFactorAllByUserID(ctx context.Context, userID int64) (_ []entity.MFAFactorInfo, err error) {
+	ctx, span := s.startSpan(ctx, "GetMFAFactorAllByUserID")
+	defer func() { s.endSpan(span, err) }()
+
+	results, err := s.queries(ctx).GetIdentityMFAFactorAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	items := make([]entity.MFAFactorInfo, 0, len(results))
+	for _, result := range results {
+		item := entity.MFAFactorInfo{
+			ID:           result.ID,
+			Type:         result.Type,
+			FriendlyName: result.FriendlyName,
+			IsVerified:   result.IsVerified,
+		}
+		if result.LastUsedAt.Valid {
+			item.LastUsedAt = &result.LastUsedAt.Time
+		}
+		if result.CreatedAt.Valid {
+			item.CreatedAt = result.CreatedAt.Time
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
 func (s *DB) GetMFABackupCodeByUserID(ctx context.Context, userID int64) (_ []entity.MFABackupCode, err error) {
 	ctx, span := s.startSpan(ctx, "GetMFABackupCodeByUserID")
 	defer func() { s.endSpan(span, err) }()
 
-	results, err := s.query.GetIdentityMFABackupCodeByUserID(ctx, userID)
+	results, err := s.queries(ctx).GetIdentityMFABackupCodeByUserID(ctx, userID)
 	if err != nil {
 		return nil, s.mapError(err)
 	}
@@ -209,7 +487,7 @@ func (s *DB) GetUserList(ctx context.Context, filter entity.UserListFilterData)
 	dateFrom := pgtype.Timestamptz{Time: filter.DateFrom, Valid: !filter.DateFrom.IsZero()}
 	dateTo := pgtype.Timestamptz{Time: filter.DateTo, Valid: !filter.DateTo.IsZero()}
 
-	items, err := s.query.GetIdentityUserFilter(ctx, sqlc.GetIdentityUserFilterParams{
+	items, err := s.queries(ctx).GetIdentityUserFilter(ctx, sqlc.GetIdentityUserFilterParams{
 		FilterByStatus:   filter.IsFilterByStatus,
 		FilterBySearch:   filter.IsFilterBySearch,
 		FilterByDateFrom: !filter.DateFrom.IsZero(),
@@ -230,9 +508,14 @@ func (s *DB) GetUserList(ctx context.Context, filter entity.UserListFilterData)
 
 	users := make([]entity.User, 0, len(items))
 	for _, item := range items {
+		email, err := s.openEmail(item.ID, item.Email, item.EmailEncrypted)
+		if err != nil {
+			return nil, 0, err
+		}
+
 		user := entity.User{
 			ID:        item.ID,
-			Email:     item.Email,
+			Email:     email,
 			FullName:  item.FullName,
 			AvatarURL: item.AvatarUrl,
 			Status:    item.Status,
@@ -244,7 +527,7 @@ func (s *DB) GetUserList(ctx context.Context, filter entity.UserListFilterData)
 		users = append(users, user)
 	}
 
-	count, err := s.query.CountIdentityUserFilter(ctx, sqlc.CountIdentityUserFilterParams{
+	count, err := s.queries(ctx).CountIdentityUserFilter(ctx, sqlc.CountIdentityUserFilterParams{
 		FilterByStatus:   filter.IsFilterByStatus,
 		FilterBySearch:   filter.IsFilterBySearch,
 		FilterByDateFrom: !filter.DateFrom.IsZero(),
@@ -261,19 +544,112 @@ func (s *DB) GetUserList(ctx context.Context, filter entity.UserListFilterData)
 	return users, count, nil
 }
 
+// streamIdentityUsers is GetIdentityUserFilter without paging. It lives here because sqlc
+// only generates queries that collect every row into a slice.
+const streamIdentityUsers = `SELECT id, email, email_encrypted, full_name, avatar_url, status, updated_at
+FROM identity_users
+WHERE
+    (NOT $1::boolean OR status = ANY($2::smallint[]))
+    AND (
+      NOT $3::boolean
+      -- rows holding only the sealed email (strict hashed lookup) match by name alone
+      OR email ILIKE '%' || $4::varchar || '%'
+      OR full_name ILIKE '%' || $4::varchar || '%'
+    )
+    AND (NOT $5::boolean OR created_at >= $6::timestamptz)
+    AND (NOT $7::boolean OR created_at <= $8::timestamptz)
+    AND deleted_at IS NULL
+ORDER BY
+  CASE WHEN $9::varchar = 'email:asc'  THEN email END ASC,
+  CASE WHEN $9::varchar = 'email:desc' THEN email END DESC,
+  CASE WHEN $9::varchar = 'full_name:asc'  THEN full_name END ASC,
+  CASE WHEN $9::varchar = 'full_name:desc' THEN full_name END DESC,
+  CASE WHEN $9::varchar = 'updated_at:asc'  THEN updated_at END ASC,
+  CASE WHEN $9::varchar = 'updated_at:desc' THEN updated_at END DESC,
+  CASE WHEN $9::varchar = 'status:asc'  THEN status END ASC,
+  CASE WHEN $9::varchar = 'status:desc' THEN status END DESC,
+  created_at DESC, id DESC
+`
+
+// StreamUsers returns every user matching filter from a single query, yielding each row as
+// it arrives from the server so an export holds one user in memory at a time. filter.Page
+// and filter.Size are ignored.
+func (s *DB) StreamUsers(ctx context.Context, filter entity.UserListFilterData) iter.Seq2[entity.User, error] {
+	return func(yield func(entity.User, error) bool) {
+		var err error
+		ctx, span := s.startSpan(ctx, "StreamUsers")
+		defer func() { s.endSpan(span, err) }()
+
+		rows, err := s.dbtx(ctx).Query(ctx, streamIdentityUsers,
+			filter.IsFilterByStatus,
+			filter.Statuses,
+			filter.IsFilterBySearch,
+			filter.Search,
+			!filter.DateFrom.IsZero(),
+			pgtype.Timestamptz{Time: filter.DateFrom, Valid: !filter.DateFrom.IsZero()},
+			!filter.DateTo.IsZero(),
+			pgtype.Timestamptz{Time: filter.DateTo, Valid: !filter.DateTo.IsZero()},
+			fmt.Sprintf("%s:%s", filter.OrderBy, filter.OrderDirection),
+		)
+		if err != nil {
+			yield(entity.User{}, s.mapError(err))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item sqlc.GetIdentityUserFilterRow
+			if err = rows.Scan(&item.ID, &item.Email, &item.EmailEncrypted, &item.FullName, &item.AvatarUrl, &item.Status, &item.UpdatedAt); err != nil {
+				yield(entity.User{}, s.mapError(err))
+				return
+			}
+
+			var email string
+			if email, err = s.openEmail(item.ID, item.Email, item.EmailEncrypted); err != nil {
+				yield(entity.User{}, err)
+				return
+			}
+
+			user := entity.User{
+				ID:        item.ID,
+				Email:     email,
+				FullName:  item.FullName,
+				AvatarURL: item.AvatarUrl,
+				Status:    item.Status,
+			}
+			if item.UpdatedAt.Valid {
+				user.UpdatedAt = item.UpdatedAt.Time
+			}
+
+			if !yield(user, nil) {
+				return
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			yield(entity.User{}, s.mapError(err))
+		}
+	}
+}
+
 func (s *DB) GetUserByID(ctx context.Context, id int64, includeDeleted bool) (_ *entity.User, err error) {
 	ctx, span := s.startSpan(ctx, "GetUserByID")
 	defer func() { s.endSpan(span, err) }()
 
 	if includeDeleted {
-		result, err := s.query.GetIdentityUserByIDIncludeDeleted(ctx, id)
+		result, err := s.queries(ctx).GetIdentityUserByIDIncludeDeleted(ctx, id)
 		if err != nil {
 			return nil, s.mapError(err)
 		}
 
+		email, err := s.openEmail(result.ID, result.Email, result.EmailEncrypted)
+		if err != nil {
+			return nil, err
+		}
+
 		item := &entity.User{
 			ID:        result.ID,
-			Email:     result.Email,
+			Email:     email,
 			FullName:  result.FullName,
 			AvatarURL: result.AvatarUrl,
 			Status:    result.Status,
@@ -288,14 +664,19 @@ func (s *DB) GetUserByID(ctx context.Context, id int64, includeDeleted bool) (_
 		return item, nil
 	}
 
-	result, err := s.query.GetIdentityUserByID(ctx, id)
+	result, err := s.queries(ctx).GetIdentityUserByID(ctx, id)
 	if err != nil {
 		return nil, s.mapError(err)
 	}
 
+	email, err := s.openEmail(result.ID, result.Email, result.EmailEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
 	item := &entity.User{
 		ID:        result.ID,
-		Email:     result.Email,
+		Email:     email,
 		FullName:  result.FullName,
 		AvatarURL: result.AvatarUrl,
 		Status:    result.Status,
@@ -309,3 +690,521 @@ func (s *DB) GetUserByID(ctx context.Context, id int64, includeDeleted bool) (_
 
 	return item, nil
 }
+
+func (s *DB) GetAuditLogBefore(ctx context.Context, before time.Time, limit int32) (_ []entity.AuditLog, err error) {
+	ctx, span := s.startSpan(ctx, "GetAuditLogBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	results, err := s.queries(ctx).GetIdentityAuditLogBefore(ctx, sqlc.GetIdentityAuditLogBeforeParams{
+		Before:    pgtype.Timestamptz{Valid: true, Time: before},
+		PageLimit: limit,
+	})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	items := make([]entity.AuditLog, 0, len(results))
+	for _, result := range results {
+		items = append(items, entity.AuditLog{
+			ID:           result.ID,
+			ActorID:      result.ActorID,
+			TargetUserID: result.TargetUserID,
+			Action:       entity.AuditAction(result.Action),
+			Metadata:     result.Metadata,
+			CreatedAt:    result.CreatedAt.Time,
+		})
+	}
+
+	return items, nil
+}
+
+func (s *DB) CountAuditLogBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "CountAuditLogBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	count, err := s.queries(ctx).CountIdentityAuditLogBefore(ctx, pgtype.Timestamptz{Valid: true, Time: before})
+	return count, s.mapError(err)
+}
+
+func (s *DB) CountChallengeExpiredBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "CountChallengeExpiredBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	count, err := s.queries(ctx).CountIdentityChallengeExpiredBefore(ctx, pgtype.Timestamptz{Valid: true, Time: before})
+	return count, s.mapError(err)
+}
+
+func (s *DB) CountRefreshTokenExpiredBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "CountRefreshTokenExpiredBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	count, err := s.queries(ctx).CountIdentityRefreshTokenExpiredBefore(ctx, pgtype.Timestamptz{Valid: true, Time: before})
+	return count, s.mapError(err)
+}
+
+func (s *DB) CountTrustedDeviceExpiredBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "CountTrustedDeviceExpiredBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	count, err := s.queries(ctx).CountIdentityTrustedDeviceExpiredBefore(ctx, pgtype.Timestamptz{Valid: true, Time: before})
+	return count, s.mapError(err)
+}
+
+func (s *DB) GetUserDeletion(ctx context.Context, userID int64) (_ *entity.UserDeletion, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserDeletion")
+	defer func() { s.endSpan(span, err) }()
+
+	row, err := s.queries(ctx).GetIdentityUserDeletion(ctx, userID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	return toUserDeletion(row), nil
+}
+
+func (s *DB) GetUserDeletionDue(ctx context.Context, dueBefore time.Time, limit int32) (_ []entity.UserDeletion, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserDeletionDue")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).GetIdentityUserDeletionDue(ctx, sqlc.GetIdentityUserDeletionDueParams{
+		DueBefore: pgtype.Timestamptz{Valid: true, Time: dueBefore},
+		PageLimit: limit,
+	})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	items := make([]entity.UserDeletion, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, *toUserDeletion(row))
+	}
+
+	return items, nil
+}
+
+// GetUserPlaintextEmails returns up to limit users whose email is still stored in plaintext.
+func (s *DB) GetUserPlaintextEmails(ctx context.Context, limit int32) (_ []entity.UserEmail, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserPlaintextEmails")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).GetIdentityUserPlaintextEmails(ctx, limit)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	items := make([]entity.UserEmail, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, entity.UserEmail{UserID: row.ID, Email: row.Email.String})
+	}
+
+	return items, nil
+}
+
+func toUserDeletion(row sqlc.IdentityUserDeletion) *entity.UserDeletion {
+	item := &entity.UserDeletion{
+		UserID:      row.UserID,
+		RequestedAt: row.RequestedAt.Time,
+		ScheduledAt: row.ScheduledAt.Time,
+	}
+	if row.CompletedAt.Valid {
+		item.CompletedAt = &row.CompletedAt.Time
+	}
+
+	return item
+}
+
+func (s *DB) GetExportJob(ctx context.Context, id int64) (_ *entity.ExportJob, err error) {
+	ctx, span := s.startSpan(ctx, "GetExportJob")
+	defer func() { s.endSpan(span, err) }()
+
+	row, err := s.queries(ctx).GetIdentityExportJob(ctx, id)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	return toExportJob(row), nil
+}
+
+func toExportJob(row sqlc.IdentityExportJob) *entity.ExportJob {
+	item := &entity.ExportJob{
+		ID:          row.ID,
+		RequestedBy: row.RequestedBy,
+		Format:      row.Format,
+		Filters:     row.Filters,
+		Status:      row.Status,
+		RowsTotal:   row.RowsTotal,
+		RowsWritten: row.RowsWritten,
+		ObjectKey:   row.ObjectKey,
+		Error:       row.Error,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+	if row.CompletedAt.Valid {
+		item.CompletedAt = &row.CompletedAt.Time
+	}
+
+	return item
+}
+
+func (s *DB) GetVerificationReminderDue(ctx context.Context, registeredBefore, lastSentBefore time.Time, maxReminders, limit int32) (_ []entity.VerificationReminder, err error) {
+	ctx, span := s.startSpan(ctx, "GetVerificationReminderDue")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).GetIdentityVerificationReminderDue(ctx, sqlc.GetIdentityVerificationReminderDueParams{
+		Status:           entity.UserStatusUnverified,
+		RegisteredBefore: pgtype.Timestamptz{Valid: true, Time: registeredBefore},
+		MaxReminders:     maxReminders,
+		LastSentBefore:   pgtype.Timestamptz{Valid: true, Time: lastSentBefore},
+		PageLimit:        limit,
+	})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	items := make([]entity.VerificationReminder, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, entity.VerificationReminder{UserID: row.ID, SentCount: row.SentCount})
+	}
+
+	return items, nil
+}
+
+func (s *DB) GetAPIKeyByToken(ctx context.Context, token string) (_ *entity.APIKeyUser, err error) {
+	ctx, span := s.startSpan(ctx, "GetAPIKeyByToken")
+	defer func() { s.endSpan(span, err) }()
+
+	row, err := s.queries(ctx).GetIdentityAPIKeyByToken(ctx, token)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	email, err := s.openEmail(row.UserID, row.Email, row.EmailEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.APIKeyUser{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		UserEmail:  email,
+		UserStatus: row.Status,
+		ExpiresAt:  toTimePtr(row.ExpiresAt),
+		LastUsedAt: toTimePtr(row.LastUsedAt),
+		RevokedAt:  toTimePtr(row.RevokedAt),
+	}, nil
+}
+
+func (s *DB) GetActiveAPIKeys(ctx context.Context, userID int64) (_ []entity.APIKey, err error) {
+	ctx, span := s.startSpan(ctx, "GetActiveAPIKeys")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).GetIdentityActiveAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	keys := make([]entity.APIKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, entity.APIKey{
+			ID:         row.ID,
+			UserID:     userID,
+			Name:       row.Name,
+			Prefix:     row.Prefix,
+			ExpiresAt:  toTimePtr(row.ExpiresAt),
+			LastUsedAt: toTimePtr(row.LastUsedAt),
+			CreatedAt:  row.CreatedAt.Time,
+		})
+	}
+
+	return keys, nil
+}
+
+func (s *DB) CountActiveAPIKeys(ctx context.Context, userID int64) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "CountActiveAPIKeys")
+	defer func() { s.endSpan(span, err) }()
+
+	count, err := s.queries(ctx).CountIdentityActiveAPIKeysByUserID(ctx, userID)
+	return count, s.mapError(err)
+}
+
+func (s *DB) GetTrustedDeviceByToken(ctx context.Context, token string) (_ *entity.TrustedDevice, err error) {
+	ctx, span := s.startSpan(ctx, "GetTrustedDeviceByToken")
+	defer func() { s.endSpan(span, err) }()
+
+	row, err := s.queries(ctx).GetIdentityTrustedDeviceByToken(ctx, token)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	return &entity.TrustedDevice{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		ExpiresAt: row.ExpiresAt.Time,
+		RevokedAt: toTimePtr(row.RevokedAt),
+	}, nil
+}
+
+func (s *DB) GetActiveTrustedDevices(ctx context.Context, userID int64) (_ []entity.TrustedDevice, err error) {
+	ctx, span := s.startSpan(ctx, "GetActiveTrustedDevices")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).GetIdentityActiveTrustedDevicesByUserID(ctx, userID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	devices := make([]entity.TrustedDevice, 0, len(rows))
+	for _, row := range rows {
+		devices = append(devices, entity.TrustedDevice{
+			ID:         row.ID,
+			UserID:     userID,
+			UserAgent:  row.UserAgent,
+			IP:         row.Ip,
+			ExpiresAt:  row.ExpiresAt.Time,
+			LastUsedAt: toTimePtr(row.LastUsedAt),
+			CreatedAt:  row.CreatedAt.Time,
+		})
+	}
+
+	return devices, nil
+}
+
+func (s *DB) GetUserConnections(ctx context.Context, userID int64) (_ []entity.UserConnection, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserConnections")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).GetIdentityUserConnectionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	conns := make([]entity.UserConnection, 0, len(rows))
+	for _, row := range rows {
+		conns = append(conns, entity.UserConnection{
+			ID:             row.ID,
+			UserID:         userID,
+			Provider:       row.Provider,
+			ProviderUserID: row.ProviderUserID,
+			CreatedAt:      row.CreatedAt.Time,
+		})
+	}
+
+	return conns, nil
+}
+
+func (s *DB) GetServiceAccountByClientID(ctx context.Context, clientID string) (_ *entity.ServiceAccountCredential, err error) {
+	ctx, span := s.startSpan(ctx, "GetServiceAccountByClientID")
+	defer func() { s.endSpan(span, err) }()
+
+	row, err := s.queries(ctx).GetIdentityServiceAccountByClientID(ctx, clientID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	return &entity.ServiceAccountCredential{
+		ID:       row.ID,
+		ClientID: row.ClientID,
+		Secret:   row.Secret,
+	}, nil
+}
+
+func (s *DB) GetServiceAccounts(ctx context.Context) (_ []entity.ServiceAccount, err error) {
+	ctx, span := s.startSpan(ctx, "GetServiceAccounts")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).GetIdentityServiceAccounts(ctx)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	accounts := make([]entity.ServiceAccount, 0, len(rows))
+	for _, row := range rows {
+		accounts = append(accounts, entity.ServiceAccount{
+			ID:         row.ID,
+			Name:       row.Name,
+			ClientID:   row.ClientID,
+			CreatedBy:  row.CreatedBy,
+			LastUsedAt: toTimePtr(row.LastUsedAt),
+			CreatedAt:  row.CreatedAt.Time,
+		})
+	}
+
+	return accounts, nil
+}
+
+// GetLoginEvents returns up to limit login events of a user, newest first, starting below
+// beforeID when it is set.
+func (s *DB) GetLoginEvents(ctx context.Context, userID, beforeID int64, limit int32) (_ []entity.LoginEvent, err error) {
+	ctx, span := s.startSpan(ctx, "GetLoginEvents")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).GetIdentityLoginEventsByUserID(ctx, sqlc.GetIdentityLoginEventsByUserIDParams{
+		UserID:    userID,
+		BeforeID:  beforeID,
+		PageLimit: limit,
+	})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	events := make([]entity.LoginEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, entity.LoginEvent{
+			ID:            row.ID,
+			UserID:        userID,
+			Success:       row.Success,
+			Method:        entity.LoginMethod(row.Method),
+			FailureReason: row.FailureReason,
+			IP:            row.Ip,
+			UserAgent:     row.UserAgent,
+			Location: entity.GeoLocation{
+				Country: row.Country,
+				Region:  row.Region,
+				City:    row.City,
+			},
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+
+	return events, nil
+}
+
+func (s *DB) CountLoginEventBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "CountLoginEventBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	count, err := s.queries(ctx).CountIdentityLoginEventBefore(ctx, pgtype.Timestamptz{Valid: true, Time: before})
+	return count, s.mapError(err)
+}
+
+// GetUserDevices returns the fingerprint and last country of every device the user signed in from.
+func (s *DB) GetUserDevices(ctx context.Context, userID int64) (_ []entity.UserDevice, err error) {
+	ctx, span := s.startSpan(ctx, "GetUserDevices")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).GetIdentityUserDevicesByUserID(ctx, userID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	devices := make([]entity.UserDevice, 0, len(rows))
+	for _, row := range rows {
+		devices = append(devices, entity.UserDevice{
+			UserID:      userID,
+			Fingerprint: row.Fingerprint,
+			Country:     row.Country,
+		})
+	}
+
+	return devices, nil
+}
+
+func toTimePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
+
+// GetPasswordHistory returns up to limit hashes of passwords userID replaced, newest first.
+func (s *DB) GetPasswordHistory(ctx context.Context, userID int64, limit int32) (_ []string, err error) {
+	ctx, span := s.startSpan(ctx, "GetPasswordHistory")
+	defer func() { s.endSpan(span, err) }()
+
+	hashes, err := s.queries(ctx).GetIdentityPasswordHistoryByUserID(ctx, sqlc.GetIdentityPasswordHistoryByUserIDParams{
+		UserID:    userID,
+		PageLimit: limit,
+	})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	return hashes, nil
+}
+
+func (s *DB) GetOrganizationByID(ctx context.Context, id int64) (_ *entity.Organization, err error) {
+	ctx, span := s.startSpan(ctx, "GetOrganizationByID")
+	defer func() { s.endSpan(span, err) }()
+
+	row, err := s.queries(ctx).GetIdentityOrganizationByID(ctx, id)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	org := toOrganization(row)
+	return &org, nil
+}
+
+// GetOrganizationsByUserID returns the organizations userID is a member of, by name.
+func (s *DB) GetOrganizationsByUserID(ctx context.Context, userID int64) (_ []entity.Organization, err error) {
+	ctx, span := s.startSpan(ctx, "GetOrganizationsByUserID")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).GetIdentityOrganizationsByUserID(ctx, userID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	orgs := make([]entity.Organization, 0, len(rows))
+	for _, row := range rows {
+		orgs = append(orgs, toOrganization(row))
+	}
+
+	return orgs, nil
+}
+
+// GetOrganizationMembers returns the members of an organization, oldest first. Roles are
+// kept in Casbin and left empty here.
+func (s *DB) GetOrganizationMembers(ctx context.Context, orgID int64) (_ []entity.OrgMember, err error) {
+	ctx, span := s.startSpan(ctx, "GetOrganizationMembers")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).GetIdentityOrganizationMembers(ctx, orgID)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	members := make([]entity.OrgMember, 0, len(rows))
+	for _, row := range rows {
+		email, err := s.openEmail(row.UserID, row.Email, row.EmailEncrypted)
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, entity.OrgMember{
+			UserID:   row.UserID,
+			Email:    email,
+			FullName: row.FullName,
+			JoinedAt: row.CreatedAt.Time,
+		})
+	}
+
+	return members, nil
+}
+
+func (s *DB) IsOrganizationMember(ctx context.Context, orgID, userID int64) (_ bool, err error) {
+	ctx, span := s.startSpan(ctx, "IsOrganizationMember")
+	defer func() { s.endSpan(span, err) }()
+
+	ok, err := s.queries(ctx).IsIdentityOrganizationMember(ctx, sqlc.IsIdentityOrganizationMemberParams{
+		OrganizationID: orgID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return false, s.mapError(err)
+	}
+
+	return ok, nil
+}
+
+func toOrganization(row sqlc.IdentityOrganization) entity.Organization {
+	return entity.Organization{
+		ID:        row.ID,
+		Name:      row.Name,
+		Slug:      row.Slug,
+		CreatedBy: row.CreatedBy,
+		CreatedAt: row.CreatedAt.Time,
+		UpdatedAt: row.UpdatedAt.Time,
+	}
+}
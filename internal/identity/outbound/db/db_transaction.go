@@ -10,15 +10,15 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/identity/outbound/db/sqlc"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
-	"github.com/shandysiswandi/gobite/internal/pkg/sqlc"
 )
 
 func (s *DB) NewRegistration(ctx context.Context, user entity.NewUser, chal entity.Challenge, hash string) (err error) {
 	ctx, span := s.startSpan(ctx, "NewRegistration")
 	defer func() { s.endSpan(span, err) }()
 
-	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := s.beginTx(ctx)
 	if err != nil {
 		return err
 	}
@@ -28,16 +28,24 @@ func (s *DB) NewRegistration(ctx context.Context, user entity.NewUser, chal enti
 		}
 	}()
 
+	email, sealedEmail, err := s.emailColumns(user.ID, user.Email, user.EmailHash, user.EmailSealedOnly)
+	if err != nil {
+		return err
+	}
+
 	wtx := s.query.WithTx(tx)
 
 	if err := wtx.CreateIdentityUser(ctx, sqlc.CreateIdentityUserParams{
-		ID:        user.ID,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		AvatarUrl: user.AvatarURL,
-		Status:    user.Status,
-		CreatedBy: user.CreatedBy,
-		UpdatedBy: user.UpdatedBy,
+		ID:             user.ID,
+		Email:          email,
+		FullName:       user.FullName,
+		AvatarUrl:      user.AvatarURL,
+		Status:         user.Status,
+		CreatedBy:      user.CreatedBy,
+		UpdatedBy:      user.UpdatedBy,
+		EmailHash:      pgtype.Text{Valid: user.EmailHash != "", String: user.EmailHash},
+		Username:       pgtype.Text{Valid: user.Username != "", String: user.Username},
+		EmailEncrypted: sealedEmail,
 	}); err != nil {
 		return s.mapError(err)
 	}
@@ -71,7 +79,7 @@ func (s *DB) NewUser(ctx context.Context, user entity.NewUser, hash string) (err
 	ctx, span := s.startSpan(ctx, "NewUser")
 	defer func() { s.endSpan(span, err) }()
 
-	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := s.beginTx(ctx)
 	if err != nil {
 		return err
 	}
@@ -81,16 +89,72 @@ func (s *DB) NewUser(ctx context.Context, user entity.NewUser, hash string) (err
 		}
 	}()
 
+	email, sealedEmail, err := s.emailColumns(user.ID, user.Email, user.EmailHash, user.EmailSealedOnly)
+	if err != nil {
+		return err
+	}
+
 	wtx := s.query.WithTx(tx)
 
 	if err := wtx.CreateIdentityUser(ctx, sqlc.CreateIdentityUserParams{
-		ID:        user.ID,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		AvatarUrl: user.AvatarURL,
-		Status:    user.Status,
-		CreatedBy: user.CreatedBy,
-		UpdatedBy: user.UpdatedBy,
+		ID:             user.ID,
+		Email:          email,
+		FullName:       user.FullName,
+		AvatarUrl:      user.AvatarURL,
+		Status:         user.Status,
+		CreatedBy:      user.CreatedBy,
+		UpdatedBy:      user.UpdatedBy,
+		EmailHash:      pgtype.Text{Valid: user.EmailHash != "", String: user.EmailHash},
+		EmailEncrypted: sealedEmail,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.CreateIdentityUserCredential(ctx, sqlc.CreateIdentityUserCredentialParams{
+		UserID:   user.ID,
+		Password: hash,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return s.mapError(err)
+	}
+
+	return nil
+}
+
+func (s *DB) NewConnectedUser(ctx context.Context, user entity.NewUser, hash string, conn entity.UserConnection) (err error) {
+	ctx, span := s.startSpan(ctx, "NewConnectedUser")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
+		}
+	}()
+
+	email, sealedEmail, err := s.emailColumns(user.ID, user.Email, user.EmailHash, user.EmailSealedOnly)
+	if err != nil {
+		return err
+	}
+
+	wtx := s.query.WithTx(tx)
+
+	if err := wtx.CreateIdentityUser(ctx, sqlc.CreateIdentityUserParams{
+		ID:             user.ID,
+		Email:          email,
+		FullName:       user.FullName,
+		AvatarUrl:      user.AvatarURL,
+		Status:         user.Status,
+		CreatedBy:      user.CreatedBy,
+		UpdatedBy:      user.UpdatedBy,
+		EmailHash:      pgtype.Text{Valid: user.EmailHash != "", String: user.EmailHash},
+		EmailEncrypted: sealedEmail,
 	}); err != nil {
 		return s.mapError(err)
 	}
@@ -102,6 +166,15 @@ func (s *DB) NewUser(ctx context.Context, user entity.NewUser, hash string) (err
 		return s.mapError(err)
 	}
 
+	if err := wtx.CreateIdentityUserConnection(ctx, sqlc.CreateIdentityUserConnectionParams{
+		ID:             conn.ID,
+		UserID:         user.ID,
+		Provider:       conn.Provider,
+		ProviderUserID: conn.ProviderUserID,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return s.mapError(err)
 	}
@@ -117,7 +190,7 @@ func (s *DB) UpsertUsers(ctx context.Context, users []entity.UpsertUser, hashes
 		return 0, 0, nil
 	}
 
-	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := s.beginTx(ctx)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -130,23 +203,41 @@ func (s *DB) UpsertUsers(ctx context.Context, users []entity.UpsertUser, hashes
 	wtx := s.query.WithTx(tx)
 
 	emails := make([]string, 0, len(users))
+	emailHashes := make([]string, 0, len(users))
 	for _, user := range users {
 		emails = append(emails, user.Email)
+		if user.EmailHash != "" {
+			emailHashes = append(emailHashes, user.EmailHash)
+		}
 	}
 
-	existingUsers, err := wtx.GetIdentityUserByEmailsIncludeDeleted(ctx, emails)
+	existingUsers, err := wtx.GetIdentityUserByEmailsIncludeDeleted(ctx, sqlc.GetIdentityUserByEmailsIncludeDeletedParams{
+		Emails:      emails,
+		EmailHashes: emailHashes,
+	})
 	if err != nil {
 		return 0, 0, s.mapError(err)
 	}
 
+	// rows holding only the sealed email are matched by their lookup hash
 	existingByEmail := make(map[string]sqlc.GetIdentityUserByEmailsIncludeDeletedRow, len(existingUsers))
+	existingByHash := make(map[string]sqlc.GetIdentityUserByEmailsIncludeDeletedRow, len(existingUsers))
 	for _, user := range existingUsers {
-		existingByEmail[strings.ToLower(user.Email)] = user
+		if user.Email.Valid {
+			existingByEmail[strings.ToLower(user.Email.String)] = user
+		}
+		if user.EmailHash.Valid {
+			existingByHash[user.EmailHash.String] = user
+		}
 	}
 
 	for _, user := range users {
 		normalizedEmail := strings.ToLower(user.Email)
-		if existing, ok := existingByEmail[normalizedEmail]; ok {
+		existing, ok := existingByEmail[normalizedEmail]
+		if !ok && user.EmailHash != "" {
+			existing, ok = existingByHash[user.EmailHash]
+		}
+		if ok {
 			updated++
 			patchArg := sqlc.PatcIdentityUserParams{
 				ID:        existing.ID,
@@ -172,19 +263,30 @@ func (s *DB) UpsertUsers(ctx context.Context, users []entity.UpsertUser, hashes
 				}); err != nil {
 					return 0, 0, s.mapError(err)
 				}
+
+				if err := invalidateChallenges(ctx, wtx, existing.ID, entity.ChallengePurposesOnPasswordChange); err != nil {
+					return 0, 0, s.mapError(err)
+				}
 			}
 			continue
 		}
 
 		created++
+		email, sealedEmail, err := s.emailColumns(user.ID, user.Email, user.EmailHash, user.EmailSealedOnly)
+		if err != nil {
+			return 0, 0, err
+		}
+
 		if err := wtx.CreateIdentityUser(ctx, sqlc.CreateIdentityUserParams{
-			ID:        user.ID,
-			Email:     user.Email,
-			FullName:  user.FullName,
-			AvatarUrl: user.AvatarURL,
-			Status:    user.Status,
-			CreatedBy: user.CreatedBy,
-			UpdatedBy: user.UpdatedBy,
+			ID:             user.ID,
+			Email:          email,
+			FullName:       user.FullName,
+			AvatarUrl:      user.AvatarURL,
+			Status:         user.Status,
+			CreatedBy:      user.CreatedBy,
+			UpdatedBy:      user.UpdatedBy,
+			EmailHash:      pgtype.Text{Valid: user.EmailHash != "", String: user.EmailHash},
+			EmailEncrypted: sealedEmail,
 		}); err != nil {
 			return 0, 0, s.mapError(err)
 		}
@@ -210,12 +312,13 @@ func (s *DB) PatchUser(ctx context.Context, user entity.PatchUser, hash string)
 	ctx, span := s.startSpan(ctx, "PatchUser")
 	defer func() { s.endSpan(span, err) }()
 
-	if hash == "" && user.Email == "" && user.FullName == "" && user.Status.IsUnknown() {
+	if hash == "" && user.Email == "" && user.FullName == "" && user.Status.IsUnknown() &&
+		user.MustResetPassword == nil && user.MFARequired == nil {
 		// nothing to patch
 		return nil
 	}
 
-	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := s.beginTx(ctx)
 	if err != nil {
 		return err
 	}
@@ -227,6 +330,16 @@ func (s *DB) PatchUser(ctx context.Context, user entity.PatchUser, hash string)
 
 	wtx := s.query.WithTx(tx)
 
+	if !user.ExpectedVersion.IsZero() {
+		updatedAt, err := wtx.LockIdentityUserUpdatedAt(ctx, user.ID)
+		if err != nil {
+			return s.mapError(err)
+		}
+		if !user.ExpectedVersion.Matches(updatedAt.Time) {
+			return goerror.ErrVersionMismatch
+		}
+	}
+
 	if hash != "" {
 		if err := wtx.UpdateIdentityUserCredential(ctx, sqlc.UpdateIdentityUserCredentialParams{
 			UserID:   user.ID,
@@ -234,6 +347,16 @@ func (s *DB) PatchUser(ctx context.Context, user entity.PatchUser, hash string)
 		}); err != nil {
 			return s.mapError(err)
 		}
+
+		if err := invalidateChallenges(ctx, wtx, user.ID, entity.ChallengePurposesOnPasswordChange); err != nil {
+			return s.mapError(err)
+		}
+	}
+
+	if user.Email != "" {
+		if err := invalidateChallenges(ctx, wtx, user.ID, entity.ChallengePurposesOnEmailChange); err != nil {
+			return s.mapError(err)
+		}
 	}
 
 	patchArg := sqlc.PatcIdentityUserParams{
@@ -241,7 +364,15 @@ func (s *DB) PatchUser(ctx context.Context, user entity.PatchUser, hash string)
 		UpdatedBy: pgtype.Int8{Valid: true, Int64: user.UpdatedBy},
 	}
 	if user.Email != "" {
-		patchArg.Email = pgtype.Text{Valid: true, String: user.Email}
+		email, sealedEmail, err := s.emailColumns(user.ID, user.Email, user.EmailHash, user.EmailSealedOnly)
+		if err != nil {
+			return err
+		}
+		patchArg.Email = email
+		patchArg.EmailEncrypted = sealedEmail
+	}
+	if user.EmailHash != "" {
+		patchArg.EmailHash = pgtype.Text{Valid: true, String: user.EmailHash}
 	}
 	if user.FullName != "" {
 		patchArg.FullName = pgtype.Text{Valid: true, String: user.FullName}
@@ -250,49 +381,16 @@ func (s *DB) PatchUser(ctx context.Context, user entity.PatchUser, hash string)
 	if !user.Status.IsUnknown() {
 		patchArg.Status = pgtype.Int2{Valid: true, Int16: int16(user.Status)}
 	}
-
-	if err := wtx.PatcIdentityUser(ctx, patchArg); err != nil {
-		return err
+	if user.MustResetPassword != nil {
+		patchArg.MustResetPassword = pgtype.Bool{Valid: true, Bool: *user.MustResetPassword}
 	}
-
-	if err = tx.Commit(ctx); err != nil {
-		return s.mapError(err)
+	if user.MFARequired != nil {
+		patchArg.MfaRequired = pgtype.Bool{Valid: true, Bool: *user.MFARequired}
 	}
 
-	return nil
-}
-
-func (s *DB) NewMFAFactorTOTP(ctx context.Context, fTOTP entity.MFAFactor, challengeID int64) (err error) {
-	ctx, span := s.startSpan(ctx, "NewMFAFactorTOTP")
-	defer func() { s.endSpan(span, err) }()
-
-	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
-	if err != nil {
+	if err := wtx.PatcIdentityUser(ctx, patchArg); err != nil {
 		return err
 	}
-	defer func() {
-		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
-			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
-		}
-	}()
-
-	wtx := s.query.WithTx(tx)
-
-	if err := wtx.CreateIdentityMFAFactor(ctx, sqlc.CreateIdentityMFAFactorParams{
-		ID:           fTOTP.ID,
-		UserID:       fTOTP.UserID,
-		Type:         fTOTP.Type,
-		FriendlyName: fTOTP.FriendlyName,
-		Secret:       fTOTP.Secret,
-		KeyVersion:   fTOTP.KeyVersion,
-		IsVerified:   fTOTP.IsVerified,
-	}); err != nil {
-		return s.mapError(err)
-	}
-
-	if err := wtx.DeleteIdentityChallengeByID(ctx, challengeID); err != nil {
-		return s.mapError(err)
-	}
 
 	if err = tx.Commit(ctx); err != nil {
 		return s.mapError(err)
@@ -301,11 +399,11 @@ func (s *DB) NewMFAFactorTOTP(ctx context.Context, fTOTP entity.MFAFactor, chall
 	return nil
 }
 
-func (s *DB) NewRefreshToken(ctx context.Context, ref entity.RefreshToken, challengeID int64) (err error) {
-	ctx, span := s.startSpan(ctx, "NewRefreshToken")
+func (s *DB) NewMFAFactor(ctx context.Context, factor entity.MFAFactor, challengeID int64) (err error) {
+	ctx, span := s.startSpan(ctx, "NewMFAFactor")
 	defer func() { s.endSpan(span, err) }()
 
-	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := s.beginTx(ctx)
 	if err != nil {
 		return err
 	}
@@ -317,12 +415,14 @@ func (s *DB) NewRefreshToken(ctx context.Context, ref entity.RefreshToken, chall
 
 	wtx := s.query.WithTx(tx)
 
-	if err := wtx.CreateIdentityRefreshToken(ctx, sqlc.CreateIdentityRefreshTokenParams{
-		ID:        ref.ID,
-		UserID:    ref.UserID,
-		Token:     ref.Token,
-		ExpiresAt: pgtype.Timestamptz{Valid: true, Time: ref.ExpiresAt},
-		Metadata:  ref.Metadata,
+	if err := wtx.CreateIdentityMFAFactor(ctx, sqlc.CreateIdentityMFAFactorParams{
+		ID:           factor.ID,
+		UserID:       factor.UserID,
+		Type:         factor.Type,
+		FriendlyName: factor.FriendlyName,
+		Secret:       factor.Secret,
+		KeyVersion:   factor.KeyVersion,
+		IsVerified:   factor.IsVerified,
 	}); err != nil {
 		return s.mapError(err)
 	}
@@ -346,7 +446,7 @@ func (s *DB) NewBackupCodes(ctx context.Context, userID int64, codes []entity.MF
 		return nil
 	}
 
-	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := s.beginTx(ctx)
 	if err != nil {
 		return err
 	}
@@ -401,7 +501,7 @@ func (s *DB) VerifyUserRegistration(ctx context.Context, data entity.VerifyUserR
 	ctx, span := s.startSpan(ctx, "VerifyUserRegistration")
 	defer func() { s.endSpan(span, err) }()
 
-	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := s.beginTx(ctx)
 	if err != nil {
 		return err
 	}
@@ -433,11 +533,66 @@ func (s *DB) VerifyUserRegistration(ctx context.Context, data entity.VerifyUserR
 	return nil
 }
 
-func (s *DB) ResetUserPassword(ctx context.Context, userID, challengeID int64, newHash string) (err error) {
+// UpdateUserCredential sets a new password, drops the emailed links issued for the old one and
+// revokes the trusted devices, so the next login asks for MFA again. The replaced hash joins
+// the password history, which is trimmed to the keepHistory newest. A reset an administrator
+// required is fulfilled.
+func (s *DB) UpdateUserCredential(ctx context.Context, userID int64, hash string, keepHistory int32) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateUserCredential")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
+		}
+	}()
+
+	wtx := s.query.WithTx(tx)
+
+	if err := rotatePasswordHistory(ctx, wtx, userID, keepHistory); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.UpdateIdentityUserCredential(ctx, sqlc.UpdateIdentityUserCredentialParams{
+		Password: hash,
+		UserID:   userID,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := invalidateChallenges(ctx, wtx, userID, entity.ChallengePurposesOnPasswordChange); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.RevokeAllIdentityTrustedDevice(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.ClearIdentityUserMustResetPassword(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return s.mapError(err)
+	}
+
+	return nil
+}
+
+// ResetUserPassword sets the password chosen through a reset link. Every outstanding reset
+// link, the used one included, is dropped and every refresh token and trusted device is
+// revoked, so whoever held the old password or another link is locked out. The replaced hash
+// joins the password history, which is trimmed to the keepHistory newest. A reset an
+// administrator required is fulfilled.
+func (s *DB) ResetUserPassword(ctx context.Context, userID, challengeID int64, newHash string, keepHistory int32) (err error) {
 	ctx, span := s.startSpan(ctx, "ResetUserPassword")
 	defer func() { s.endSpan(span, err) }()
 
-	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := s.beginTx(ctx)
 	if err != nil {
 		return err
 	}
@@ -449,6 +604,10 @@ func (s *DB) ResetUserPassword(ctx context.Context, userID, challengeID int64, n
 
 	wtx := s.query.WithTx(tx)
 
+	if err := rotatePasswordHistory(ctx, wtx, userID, keepHistory); err != nil {
+		return s.mapError(err)
+	}
+
 	if err := wtx.UpdateIdentityUserCredential(ctx, sqlc.UpdateIdentityUserCredentialParams{
 		Password: newHash,
 		UserID:   userID,
@@ -460,6 +619,128 @@ func (s *DB) ResetUserPassword(ctx context.Context, userID, challengeID int64, n
 		return s.mapError(err)
 	}
 
+	if err := invalidateChallenges(ctx, wtx, userID, entity.ChallengePurposesOnPasswordChange); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.RevokeAllIdentityRefreshToken(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.RevokeAllIdentityTrustedDevice(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.ClearIdentityUserMustResetPassword(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return s.mapError(err)
+	}
+
+	return nil
+}
+
+// AcceptUserInvite sets the password chosen by an invited user, activates the account,
+// and consumes the invitation.
+func (s *DB) AcceptUserInvite(ctx context.Context, ai entity.AcceptUserInvite) (err error) {
+	ctx, span := s.startSpan(ctx, "AcceptUserInvite")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
+		}
+	}()
+
+	wtx := s.query.WithTx(tx)
+
+	if err := wtx.UpdateIdentityUserCredential(ctx, sqlc.UpdateIdentityUserCredentialParams{
+		Password: ai.Hash,
+		UserID:   ai.UserID,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.UpdateIdentityUserStatus(ctx, sqlc.UpdateIdentityUserStatusParams{
+		ID:        ai.UserID,
+		NewStatus: entity.UserStatusActive,
+		OldStatus: entity.UserStatusUnverified,
+		UpdatedBy: ai.UserID,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityChallengeByID(ctx, ai.ChallengeID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := invalidateChallenges(ctx, wtx, ai.UserID, entity.ChallengePurposesOnPasswordChange); err != nil {
+		return s.mapError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return s.mapError(err)
+	}
+
+	return nil
+}
+
+// ChangeUserEmail switches the user to the confirmed address, consumes the challenge, drops
+// the links emailed to the old address, and revokes every refresh token and trusted device
+// so sessions opened under the old address end.
+func (s *DB) ChangeUserEmail(ctx context.Context, ce entity.ChangeUserEmail) (err error) {
+	ctx, span := s.startSpan(ctx, "ChangeUserEmail")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
+		}
+	}()
+
+	email, sealedEmail, err := s.emailColumns(ce.UserID, ce.Email, ce.EmailHash, ce.EmailSealedOnly)
+	if err != nil {
+		return err
+	}
+
+	wtx := s.query.WithTx(tx)
+
+	if err := wtx.UpdateIdentityUserEmail(ctx, sqlc.UpdateIdentityUserEmailParams{
+		Email:          email,
+		EmailHash:      pgtype.Text{Valid: ce.EmailHash != "", String: ce.EmailHash},
+		EmailEncrypted: sealedEmail,
+		UpdatedBy:      ce.UserID,
+		ID:             ce.UserID,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityChallengeByID(ctx, ce.ChallengeID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := invalidateChallenges(ctx, wtx, ce.UserID, entity.ChallengePurposesOnEmailChange); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.RevokeAllIdentityRefreshToken(ctx, ce.UserID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.RevokeAllIdentityTrustedDevice(ctx, ce.UserID); err != nil {
+		return s.mapError(err)
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return s.mapError(err)
 	}
@@ -471,7 +752,7 @@ func (s *DB) VerifyUserMFAFactor(ctx context.Context, userID, challengeID, facto
 	ctx, span := s.startSpan(ctx, "VerifyUserMFAFactor")
 	defer func() { s.endSpan(span, err) }()
 
-	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := s.beginTx(ctx)
 	if err != nil {
 		return err
 	}
@@ -505,7 +786,12 @@ func (s *DB) RotateRefreshToken(ctx context.Context, ro entity.RotateRefreshToke
 	ctx, span := s.startSpan(ctx, "RotateRefreshToken")
 	defer func() { s.endSpan(span, err) }()
 
-	tx, err := s.conn.BeginTx(ctx, pgx.TxOptions{})
+	meta, err := entity.EncodeMetadata(&ro.Metadata)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.beginTx(ctx)
 	if err != nil {
 		return err
 	}
@@ -534,6 +820,11 @@ func (s *DB) RotateRefreshToken(ctx context.Context, ro entity.RotateRefreshToke
 		UserID:    ro.UserID,
 		Token:     ro.NewToken,
 		ExpiresAt: pgtype.Timestamptz{Valid: true, Time: ro.NewExpiresAt},
+		Metadata:  meta,
+		SessionStartedAt: pgtype.Timestamptz{
+			Valid: !ro.SessionStartedAt.IsZero(),
+			Time:  ro.SessionStartedAt,
+		},
 	}); err != nil {
 		return s.mapError(err)
 	}
@@ -544,3 +835,366 @@ func (s *DB) RotateRefreshToken(ctx context.Context, ro entity.RotateRefreshToke
 
 	return nil
 }
+
+func (s *DB) RevokeUserMFA(ctx context.Context, userID int64, audit entity.AuditLog) (err error) {
+	ctx, span := s.startSpan(ctx, "RevokeUserMFA")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
+		}
+	}()
+
+	wtx := s.query.WithTx(tx)
+
+	if err := wtx.DeleteIdentityMFAFactorByUserID(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityMFABackupCodeByUserID(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.RevokeAllIdentityTrustedDevice(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.CreateIdentityAuditLog(ctx, sqlc.CreateIdentityAuditLogParams{
+		ID:           audit.ID,
+		ActorID:      audit.ActorID,
+		TargetUserID: audit.TargetUserID,
+		Action:       audit.Action.String(),
+		Metadata:     audit.Metadata,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return s.mapError(err)
+	}
+
+	return nil
+}
+
+// DeleteMFAFactor removes one factor of a user. When it was the last second factor, the
+// backup codes and the trusted devices go with it, since they would no longer guard anything;
+// lastFactor reports that case.
+func (s *DB) DeleteMFAFactor(ctx context.Context, factorID, userID int64) (lastFactor bool, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteMFAFactor")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
+		}
+	}()
+
+	wtx := s.query.WithTx(tx)
+
+	rows, err := wtx.DeleteIdentityMFAFactorByID(ctx, sqlc.DeleteIdentityMFAFactorByIDParams{
+		ID:     factorID,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, s.mapError(err)
+	}
+	if rows == 0 {
+		return false, goerror.ErrNotFound
+	}
+
+	remaining, err := wtx.GetIdentityMFAFactorByUserID(ctx, sqlc.GetIdentityMFAFactorByUserIDParams{
+		UserID:     userID,
+		IsVerified: true,
+	})
+	if err != nil {
+		return false, s.mapError(err)
+	}
+
+	lastFactor = true
+	for _, factor := range remaining {
+		if factor.Type != entity.MFATypeBackupCode {
+			lastFactor = false
+			break
+		}
+	}
+
+	if lastFactor {
+		for _, factor := range remaining {
+			if _, err := wtx.DeleteIdentityMFAFactorByID(ctx, sqlc.DeleteIdentityMFAFactorByIDParams{
+				ID:     factor.ID,
+				UserID: userID,
+			}); err != nil {
+				return false, s.mapError(err)
+			}
+		}
+
+		if err := wtx.DeleteIdentityMFABackupCodeByUserID(ctx, userID); err != nil {
+			return false, s.mapError(err)
+		}
+
+		if err := wtx.RevokeAllIdentityTrustedDevice(ctx, userID); err != nil {
+			return false, s.mapError(err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, s.mapError(err)
+	}
+
+	return lastFactor, nil
+}
+
+func (s *DB) NewMFARecoveryPending(ctx context.Context, chal entity.Challenge, challengeID int64) (err error) {
+	ctx, span := s.startSpan(ctx, "NewMFARecoveryPending")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
+		}
+	}()
+
+	wtx := s.query.WithTx(tx)
+
+	// only one recovery may be pending per user; a new one replaces the old
+	if _, err := wtx.DeleteIdentityChallengeByUserPurpose(ctx, sqlc.DeleteIdentityChallengeByUserPurposeParams{
+		UserID:  chal.UserID,
+		Purpose: chal.Purpose,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.CreateIdentityChallenge(ctx, sqlc.CreateIdentityChallengeParams{
+		ID:        chal.ID,
+		UserID:    chal.UserID,
+		Token:     chal.Token,
+		Purpose:   chal.Purpose,
+		ExpiresAt: pgtype.Timestamptz{Valid: true, Time: chal.ExpiresAt},
+		Metadata:  chal.Metadata,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityChallengeByID(ctx, challengeID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return s.mapError(err)
+	}
+
+	return nil
+}
+
+func (s *DB) CompleteMFARecovery(ctx context.Context, userID, challengeID int64, audit entity.AuditLog) (err error) {
+	ctx, span := s.startSpan(ctx, "CompleteMFARecovery")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
+		}
+	}()
+
+	wtx := s.query.WithTx(tx)
+
+	if err := wtx.DeleteIdentityMFAFactorByUserID(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityMFABackupCodeByUserID(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.RevokeAllIdentityTrustedDevice(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityChallengeByID(ctx, challengeID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.CreateIdentityAuditLog(ctx, sqlc.CreateIdentityAuditLogParams{
+		ID:           audit.ID,
+		ActorID:      audit.ActorID,
+		TargetUserID: audit.TargetUserID,
+		Action:       audit.Action.String(),
+		Metadata:     audit.Metadata,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return s.mapError(err)
+	}
+
+	return nil
+}
+
+// AnonymizeUser replaces the profile of a user with placeholders, removes everything that
+// lets anyone sign in as them, and marks their deletion request completed.
+func (s *DB) AnonymizeUser(ctx context.Context, au entity.AnonymizeUser) (err error) {
+	ctx, span := s.startSpan(ctx, "AnonymizeUser")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
+		}
+	}()
+
+	wtx := s.query.WithTx(tx)
+
+	if err := wtx.AnonymizeIdentityUser(ctx, sqlc.AnonymizeIdentityUserParams{
+		Email:    pgtype.Text{Valid: true, String: au.Email},
+		FullName: au.FullName,
+		Status:   entity.UserStatusInactive,
+		ID:       au.ID,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityUserCredential(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityUserConnectionByUserID(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityMFAFactorByUserID(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityMFABackupCodeByUserID(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityChallengeByUserID(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityRefreshTokenByUserID(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityAPIKeyByUserID(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityTrustedDeviceByUserID(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityLoginEventByUserID(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityUserDeviceByUserID(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityPasswordHistoryByUserID(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.DeleteIdentityOrganizationMembersByUserID(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err := wtx.CompleteIdentityUserDeletion(ctx, au.ID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return s.mapError(err)
+	}
+
+	return nil
+}
+
+// invalidateChallenges drops the user's outstanding challenges of the given purposes.
+func invalidateChallenges(ctx context.Context, wtx *sqlc.Queries, userID int64, purposes []entity.ChallengePurpose) error {
+	ps := make([]int16, len(purposes))
+	for i, p := range purposes {
+		ps[i] = int16(p)
+	}
+
+	return wtx.DeleteIdentityChallengeByUserPurposes(ctx, sqlc.DeleteIdentityChallengeByUserPurposesParams{
+		UserID:   userID,
+		Purposes: ps,
+	})
+}
+
+// rotatePasswordHistory moves the current password hash of userID into the history before
+// it is replaced, keeping only the keep newest entries.
+func rotatePasswordHistory(ctx context.Context, wtx *sqlc.Queries, userID int64, keep int32) error {
+	if keep > 0 {
+		if err := wtx.CreateIdentityPasswordHistoryFromCredential(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	return wtx.DeleteIdentityPasswordHistoryOverflow(ctx, sqlc.DeleteIdentityPasswordHistoryOverflowParams{
+		UserID: userID,
+		Keep:   max(keep, 0),
+	})
+}
+
+// CreateOrganization stores an organization with its creator as the first member. A taken
+// slug is reported as goerror.ErrConflict.
+func (s *DB) CreateOrganization(ctx context.Context, org entity.Organization) (err error) {
+	ctx, span := s.startSpan(ctx, "CreateOrganization")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rErr := tx.Rollback(ctx); rErr != nil && !errors.Is(rErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(ctx, "failed to rolback", "error", rErr)
+		}
+	}()
+
+	wtx := s.query.WithTx(tx)
+
+	if err := wtx.CreateIdentityOrganization(ctx, sqlc.CreateIdentityOrganizationParams{
+		ID:        org.ID,
+		Name:      org.Name,
+		Slug:      org.Slug,
+		CreatedBy: org.CreatedBy,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	if _, err := wtx.CreateIdentityOrganizationMember(ctx, sqlc.CreateIdentityOrganizationMemberParams{
+		OrganizationID: org.ID,
+		UserID:         org.CreatedBy,
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	return tx.Commit(ctx)
+}
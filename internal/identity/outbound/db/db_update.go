@@ -2,31 +2,120 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
-	"github.com/shandysiswandi/gobite/internal/pkg/sqlc"
+	"github.com/shandysiswandi/gobite/internal/identity/outbound/db/sqlc"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 )
 
 func (s *DB) RevokeRefreshToken(ctx context.Context, token string) (err error) {
 	ctx, span := s.startSpan(ctx, "RevokeRefreshToken")
 	defer func() { s.endSpan(span, err) }()
 
-	return s.mapError(s.query.RevokeIdentityRefreshToken(ctx, token))
+	return s.mapError(s.queries(ctx).RevokeIdentityRefreshToken(ctx, token))
 }
 
 func (s *DB) RevokeAllRefreshToken(ctx context.Context, userID int64) (err error) {
 	ctx, span := s.startSpan(ctx, "RevokeAllRefreshToken")
 	defer func() { s.endSpan(span, err) }()
 
-	return s.mapError(s.query.RevokeAllIdentityRefreshToken(ctx, userID))
+	return s.mapError(s.queries(ctx).RevokeAllIdentityRefreshToken(ctx, userID))
+}
+
+func (s *DB) RevokeSession(ctx context.Context, id, userID int64) (err error) {
+	ctx, span := s.startSpan(ctx, "RevokeSession")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).RevokeIdentityRefreshTokenByID(ctx, sqlc.RevokeIdentityRefreshTokenByIDParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return s.mapError(err)
+	}
+
+	if rows == 0 {
+		return goerror.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *DB) RevokeAPIKey(ctx context.Context, id, userID int64) (err error) {
+	ctx, span := s.startSpan(ctx, "RevokeAPIKey")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).RevokeIdentityAPIKey(ctx, sqlc.RevokeIdentityAPIKeyParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return s.mapError(err)
+	}
+
+	if rows == 0 {
+		return goerror.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *DB) UpdateAPIKeyLastUsedAt(ctx context.Context, id int64) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateAPIKeyLastUsedAt")
+	defer func() { s.endSpan(span, err) }()
+
+	return s.mapError(s.queries(ctx).UpdateIdentityAPIKeyLastUsedAt(ctx, id))
+}
+
+func (s *DB) RevokeTrustedDevice(ctx context.Context, id, userID int64) (err error) {
+	ctx, span := s.startSpan(ctx, "RevokeTrustedDevice")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).RevokeIdentityTrustedDevice(ctx, sqlc.RevokeIdentityTrustedDeviceParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return s.mapError(err)
+	}
+
+	if rows == 0 {
+		return goerror.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *DB) UpdateTrustedDeviceLastUsedAt(ctx context.Context, id int64) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateTrustedDeviceLastUsedAt")
+	defer func() { s.endSpan(span, err) }()
+
+	return s.mapError(s.queries(ctx).UpdateIdentityTrustedDeviceLastUsedAt(ctx, id))
+}
+
+func (s *DB) RevokeRefreshTokenOverLimit(ctx context.Context, userID int64, keep int32) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "RevokeRefreshTokenOverLimit")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.queries(ctx).RevokeIdentityRefreshTokenOverLimit(ctx, sqlc.RevokeIdentityRefreshTokenOverLimitParams{
+		UserID: userID,
+		Keep:   keep,
+	})
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
 }
 
 func (s *DB) MarkMFABackupCodeUsed(ctx context.Context, bcID, userID int64) (_ bool, err error) {
 	ctx, span := s.startSpan(ctx, "MarkMFABackupCodeUsed")
 	defer func() { s.endSpan(span, err) }()
 
-	rows, err := s.query.MarkIdentityMFABackupCodeUsed(ctx, sqlc.MarkIdentityMFABackupCodeUsedParams{
+	rows, err := s.queries(ctx).MarkIdentityMFABackupCodeUsed(ctx, sqlc.MarkIdentityMFABackupCodeUsedParams{
 		UserID: userID,
 		ID:     bcID,
 	})
@@ -37,32 +126,104 @@ func (s *DB) MarkMFABackupCodeUsed(ctx context.Context, bcID, userID int64) (_ b
 	return rows == 1, nil
 }
 
+func (s *DB) UpdateChallengeMetadata(ctx context.Context, id int64, meta valueobject.JSONMap) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateChallengeMetadata")
+	defer func() { s.endSpan(span, err) }()
+
+	return s.mapError(s.queries(ctx).UpdateIdentityChallengeMetadata(ctx, sqlc.UpdateIdentityChallengeMetadataParams{
+		Metadata: meta,
+		ID:       id,
+	}))
+}
+
 func (s *DB) UpdateMFALastUsedAt(ctx context.Context, factorID, userID int64) (err error) {
 	ctx, span := s.startSpan(ctx, "UpdateMFALastUsedAt")
 	defer func() { s.endSpan(span, err) }()
 
-	return s.mapError(s.query.UpdateIdentityMFALastUsedAt(ctx, sqlc.UpdateIdentityMFALastUsedAtParams{
+	return s.mapError(s.queries(ctx).UpdateIdentityMFALastUsedAt(ctx, sqlc.UpdateIdentityMFALastUsedAtParams{
 		ID:     factorID,
 		UserID: userID,
 	}))
 }
 
+func (s *DB) UpdateMFAFactorFriendlyName(ctx context.Context, factorID, userID int64, friendlyName string) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateMFAFactorFriendlyName")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).UpdateIdentityMFAFactorFriendlyName(ctx, sqlc.UpdateIdentityMFAFactorFriendlyNameParams{
+		FriendlyName: friendlyName,
+		ID:           factorID,
+		UserID:       userID,
+	})
+	if err != nil {
+		return s.mapError(err)
+	}
+
+	if rows == 0 {
+		return goerror.ErrNotFound
+	}
+
+	return nil
+}
+
 func (s *DB) UpdateUserProfile(ctx context.Context, id int64, fullName string) (err error) {
 	ctx, span := s.startSpan(ctx, "UpdateUserProfile")
 	defer func() { s.endSpan(span, err) }()
 
-	return s.mapError(s.query.UpdateIdentityUserName(ctx, sqlc.UpdateIdentityUserNameParams{
+	return s.mapError(s.queries(ctx).UpdateIdentityUserName(ctx, sqlc.UpdateIdentityUserNameParams{
 		ID:        id,
 		FullName:  fullName,
 		UpdatedBy: id,
 	}))
 }
 
+// UpdateUserAttributes replaces the profile attributes of the user.
+func (s *DB) UpdateUserAttributes(ctx context.Context, id, updatedBy int64, attrs valueobject.JSONMap) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateUserAttributes")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).UpdateIdentityUserAttributes(ctx, sqlc.UpdateIdentityUserAttributesParams{
+		Attributes: attrs,
+		UpdatedBy:  updatedBy,
+		ID:         id,
+	})
+	if err != nil {
+		return s.mapError(err)
+	}
+
+	if rows == 0 {
+		return goerror.ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateUsername sets the username of the user; an empty username removes it.
+func (s *DB) UpdateUsername(ctx context.Context, id int64, username string) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateUsername")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).UpdateIdentityUserUsername(ctx, sqlc.UpdateIdentityUserUsernameParams{
+		ID:        id,
+		Username:  pgtype.Text{Valid: username != "", String: username},
+		UpdatedBy: id,
+	})
+	if err != nil {
+		return s.mapError(err)
+	}
+
+	if rows == 0 {
+		return goerror.ErrNotFound
+	}
+
+	return nil
+}
+
 func (s *DB) UpdateUserAvatar(ctx context.Context, id int64, avatarURL string) (err error) {
 	ctx, span := s.startSpan(ctx, "UpdateUserAvatar")
 	defer func() { s.endSpan(span, err) }()
 
-	return s.mapError(s.query.UpdateIdentityUserAvatar(ctx, sqlc.UpdateIdentityUserAvatarParams{
+	return s.mapError(s.queries(ctx).UpdateIdentityUserAvatar(ctx, sqlc.UpdateIdentityUserAvatarParams{
 		ID:        id,
 		AvatarUrl: avatarURL,
 		UpdatedBy: id,
@@ -73,30 +234,161 @@ func (s *DB) UpdateUserStatus(ctx context.Context, id int64, oldStatus, newStatu
 	ctx, span := s.startSpan(ctx, "UpdateUserStatus")
 	defer func() { s.endSpan(span, err) }()
 
-	return s.mapError(s.query.UpdateIdentityUserStatus(ctx, sqlc.UpdateIdentityUserStatusParams{
+	return s.mapError(s.queries(ctx).UpdateIdentityUserStatus(ctx, sqlc.UpdateIdentityUserStatusParams{
 		ID:        id,
 		NewStatus: newStatus,
 		OldStatus: oldStatus,
 	}))
 }
 
-func (s *DB) UpdateUserCredential(ctx context.Context, userID int64, hash string) (err error) {
-	ctx, span := s.startSpan(ctx, "UpdateUserCredential")
+func (s *DB) MarkUserDeleted(ctx context.Context, id, byID int64) (err error) {
+	ctx, span := s.startSpan(ctx, "MarkUserDeleted")
 	defer func() { s.endSpan(span, err) }()
 
-	err = s.mapError(s.query.UpdateIdentityUserCredential(ctx, sqlc.UpdateIdentityUserCredentialParams{
-		Password: hash,
-		UserID:   userID,
+	return s.mapError(s.queries(ctx).MarkIdentityUserDeleted(ctx, sqlc.MarkIdentityUserDeletedParams{
+		DeletedBy: pgtype.Int8{Valid: true, Int64: byID},
+		ID:        id,
 	}))
-	return err
 }
 
-func (s *DB) MarkUserDeleted(ctx context.Context, id, byID int64) (err error) {
-	ctx, span := s.startSpan(ctx, "MarkUserDeleted")
+// UpdateUserEmailLookup stores the lookup hash and the sealed copy of email for a row written
+// before hashed email lookup was enabled.
+func (s *DB) UpdateUserEmailLookup(ctx context.Context, id int64, email, emailHash string) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateUserEmailLookup")
 	defer func() { s.endSpan(span, err) }()
 
-	return s.mapError(s.query.MarkIdentityUserDeleted(ctx, sqlc.MarkIdentityUserDeletedParams{
-		DeletedBy: pgtype.Int8{Valid: true, Int64: byID},
+	_, sealedEmail, err := s.emailColumns(id, email, emailHash, false)
+	if err != nil {
+		return err
+	}
+
+	return s.mapError(s.queries(ctx).UpdateIdentityUserEmailLookup(ctx, sqlc.UpdateIdentityUserEmailLookupParams{
+		EmailHash:      pgtype.Text{Valid: true, String: emailHash},
+		EmailEncrypted: sealedEmail,
+		ID:             id,
+	}))
+}
+
+// SealUserEmail replaces the plaintext email of a user with its sealed copy and lookup hash.
+// It reports false when the email changed since it was read, leaving the row untouched.
+func (s *DB) SealUserEmail(ctx context.Context, id int64, email, emailHash string) (_ bool, err error) {
+	ctx, span := s.startSpan(ctx, "SealUserEmail")
+	defer func() { s.endSpan(span, err) }()
+
+	_, sealedEmail, err := s.emailColumns(id, email, emailHash, true)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := s.queries(ctx).SealIdentityUserEmail(ctx, sqlc.SealIdentityUserEmailParams{
+		EmailHash:      pgtype.Text{Valid: true, String: emailHash},
+		EmailEncrypted: sealedEmail,
+		ID:             id,
+		Email:          pgtype.Text{Valid: true, String: email},
+	})
+	if err != nil {
+		return false, s.mapError(err)
+	}
+
+	return affected > 0, nil
+}
+
+func (s *DB) UpdateServiceAccountLastUsedAt(ctx context.Context, id int64) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateServiceAccountLastUsedAt")
+	defer func() { s.endSpan(span, err) }()
+
+	return s.mapError(s.queries(ctx).UpdateIdentityServiceAccountLastUsedAt(ctx, id))
+}
+
+func (s *DB) UpdateLoginEventLocation(ctx context.Context, id int64, loc entity.GeoLocation) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateLoginEventLocation")
+	defer func() { s.endSpan(span, err) }()
+
+	return s.mapError(s.queries(ctx).UpdateIdentityLoginEventLocation(ctx, sqlc.UpdateIdentityLoginEventLocationParams{
+		Country: loc.Country,
+		Region:  loc.Region,
+		City:    loc.City,
+		ID:      id,
+	}))
+}
+
+func (s *DB) ClaimVerificationReminder(ctx context.Context, userID int64, sentCount int32, sentAt time.Time) (_ bool, err error) {
+	ctx, span := s.startSpan(ctx, "ClaimVerificationReminder")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).ClaimIdentityVerificationReminder(ctx, sqlc.ClaimIdentityVerificationReminderParams{
+		UserID:    userID,
+		SentAt:    pgtype.Timestamptz{Valid: true, Time: sentAt},
+		SentCount: sentCount,
+	})
+	if err != nil {
+		return false, s.mapError(err)
+	}
+
+	return rows == 1, nil
+}
+
+func (s *DB) StartExportJob(ctx context.Context, id, rowsTotal int64) (_ bool, err error) {
+	ctx, span := s.startSpan(ctx, "StartExportJob")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).StartIdentityExportJob(ctx, sqlc.StartIdentityExportJobParams{
+		RowsTotal: rowsTotal,
 		ID:        id,
+	})
+	if err != nil {
+		return false, s.mapError(err)
+	}
+
+	return rows == 1, nil
+}
+
+func (s *DB) UpdateExportJobProgress(ctx context.Context, id, rowsWritten int64) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateExportJobProgress")
+	defer func() { s.endSpan(span, err) }()
+
+	return s.mapError(s.queries(ctx).UpdateIdentityExportJobProgress(ctx, sqlc.UpdateIdentityExportJobProgressParams{
+		RowsWritten: rowsWritten,
+		ID:          id,
 	}))
 }
+
+func (s *DB) CompleteExportJob(ctx context.Context, id, rowsWritten int64, objectKey string) (err error) {
+	ctx, span := s.startSpan(ctx, "CompleteExportJob")
+	defer func() { s.endSpan(span, err) }()
+
+	return s.mapError(s.queries(ctx).CompleteIdentityExportJob(ctx, sqlc.CompleteIdentityExportJobParams{
+		RowsWritten: rowsWritten,
+		ObjectKey:   objectKey,
+		ID:          id,
+	}))
+}
+
+func (s *DB) FailExportJob(ctx context.Context, id int64, reason string) (err error) {
+	ctx, span := s.startSpan(ctx, "FailExportJob")
+	defer func() { s.endSpan(span, err) }()
+
+	return s.mapError(s.queries(ctx).FailIdentityExportJob(ctx, sqlc.FailIdentityExportJobParams{
+		Error: reason,
+		ID:    id,
+	}))
+}
+
+func (s *DB) UpdateOrganizationName(ctx context.Context, id int64, name string) (err error) {
+	ctx, span := s.startSpan(ctx, "UpdateOrganizationName")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.queries(ctx).UpdateIdentityOrganizationName(ctx, sqlc.UpdateIdentityOrganizationNameParams{
+		Name: name,
+		ID:   id,
+	})
+	if err != nil {
+		return s.mapError(err)
+	}
+
+	if rows == 0 {
+		return goerror.ErrNotFound
+	}
+
+	return nil
+}
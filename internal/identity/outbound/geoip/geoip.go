@@ -0,0 +1,134 @@
+package geoip
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	DriverIPAPI  = "ipapi"
+	DriverIPInfo = "ipinfo"
+)
+
+// ErrDisabled is returned by Lookup when no lookup driver is configured.
+var ErrDisabled = errors.New("geoip: no driver configured")
+
+type Config struct {
+	// Driver selects the lookup service; an empty or unknown driver disables lookups.
+	Driver string
+	// BaseURL overrides the service API root, e.g. for a paid endpoint or a self-hosted mirror.
+	BaseURL string
+	// Token authenticates against the service when it needs one.
+	Token string
+}
+
+type lookuper interface {
+	lookup(ctx context.Context, client *http.Client, ip string) (*entity.GeoLocation, error)
+}
+
+// GeoIP resolves an IP address to a coarse location through the configured service.
+type GeoIP struct {
+	client *http.Client
+	ins    instrument.Instrumentation
+	driver lookuper
+}
+
+// New creates the adapter for cfg.Driver.
+func New(client *http.Client, ins instrument.Instrumentation, cfg Config) *GeoIP {
+	g := &GeoIP{client: client, ins: ins}
+
+	switch cfg.Driver {
+	case DriverIPAPI:
+		g.driver = &ipAPI{baseURL: baseURL(cfg.BaseURL, "https://pro.ip-api.com"), token: cfg.Token}
+	case DriverIPInfo:
+		g.driver = &ipInfo{baseURL: baseURL(cfg.BaseURL, "https://ipinfo.io"), token: cfg.Token}
+	}
+
+	return g
+}
+
+func (g *GeoIP) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return g.ins.Tracer("identity.outbound.geoip").Start(ctx, name)
+}
+
+// Enabled reports whether a driver is configured.
+func (g *GeoIP) Enabled() bool {
+	return g.driver != nil
+}
+
+// Lookup returns the location of ip. Private, loopback and malformed addresses have no
+// public location and return nil without calling the service.
+func (g *GeoIP) Lookup(ctx context.Context, ip string) (*entity.GeoLocation, error) {
+	ctx, span := g.startSpan(ctx, "Lookup")
+	defer span.End()
+
+	if g.driver == nil {
+		return nil, ErrDisabled
+	}
+
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return nil, nil
+	}
+
+	loc, err := g.driver.lookup(ctx, g.client, addr.Unmap().String())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return loc, nil
+}
+
+func baseURL(override, fallback string) string {
+	if override = strings.TrimRight(strings.TrimSpace(override), "/"); override != "" {
+		return override
+	}
+	return fallback
+}
+
+func getJSON(ctx context.Context, client *http.Client, endpoint, driver string, auth func(*http.Request), dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if auth != nil {
+		auth(req)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	const maxBody = 64 << 10
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		const maxDetail = 200
+		detail := strings.TrimSpace(string(body))
+		if len(detail) > maxDetail {
+			detail = detail[:maxDetail]
+		}
+		return fmt.Errorf("geoip: %s returned status %d: %s", driver, resp.StatusCode, detail)
+	}
+
+	return json.Unmarshal(body, dst)
+}
@@ -0,0 +1,39 @@
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+)
+
+// ipAPI looks addresses up with ip-api.com over HTTPS. Its free endpoint serves plain HTTP
+// only, so this uses the pro endpoint, which needs a key.
+type ipAPI struct {
+	baseURL string
+	token   string
+}
+
+func (a *ipAPI) lookup(ctx context.Context, client *http.Client, ip string) (*entity.GeoLocation, error) {
+	var out struct {
+		Status     string `json:"status"`
+		Message    string `json:"message"`
+		Country    string `json:"country"`
+		RegionName string `json:"regionName"`
+		City       string `json:"city"`
+	}
+
+	q := url.Values{"fields": {"status,message,country,regionName,city"}, "key": {a.token}}
+	endpoint := a.baseURL + "/json/" + url.PathEscape(ip) + "?" + q.Encode()
+	if err := getJSON(ctx, client, endpoint, DriverIPAPI, nil, &out); err != nil {
+		return nil, err
+	}
+
+	if out.Status != "success" {
+		return nil, fmt.Errorf("geoip: %s lookup failed: %s", DriverIPAPI, out.Message)
+	}
+
+	return &entity.GeoLocation{Country: out.Country, Region: out.RegionName, City: out.City}, nil
+}
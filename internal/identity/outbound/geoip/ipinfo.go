@@ -0,0 +1,36 @@
+package geoip
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+)
+
+// ipInfo looks addresses up with ipinfo.io. The token is optional for low volumes.
+type ipInfo struct {
+	baseURL string
+	token   string
+}
+
+func (i *ipInfo) lookup(ctx context.Context, client *http.Client, ip string) (*entity.GeoLocation, error) {
+	var out struct {
+		Country string `json:"country"`
+		Region  string `json:"region"`
+		City    string `json:"city"`
+	}
+
+	endpoint := i.baseURL + "/" + url.PathEscape(ip) + "/json"
+	auth := func(r *http.Request) {
+		if i.token != "" {
+			r.Header.Set("Authorization", "Bearer "+i.token)
+		}
+	}
+
+	if err := getJSON(ctx, client, endpoint, DriverIPInfo, auth, &out); err != nil {
+		return nil, err
+	}
+
+	return &entity.GeoLocation{Country: out.Country, Region: out.Region, City: out.City}, nil
+}
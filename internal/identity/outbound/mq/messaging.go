@@ -2,75 +2,118 @@ package mq
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"time"
 
-	"github.com/shandysiswandi/gobite/internal/identity/usecase"
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/pkg/clock"
 	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
 	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
-	"github.com/shandysiswandi/gobite/internal/shared/event"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
 	"go.opentelemetry.io/otel/codes"
 )
 
-const keyOfCorrelationID string = "cID"
+// NotificationValidator checks a notification request against the schema of its trigger.
+type NotificationValidator func(msg contracts.NotificationRequested) error
 
 type Messaging struct {
-	client messaging.Messaging
-	ins    instrument.Instrumentation
+	client               messaging.Messaging
+	uuid                 uid.StringID
+	clock                clock.Clocker
+	ins                  instrument.Instrumentation
+	validateNotification NotificationValidator
 }
 
-func NewMessaging(client messaging.Messaging, ins instrument.Instrumentation) *Messaging {
-	return &Messaging{client: client, ins: ins}
+func NewMessaging(
+	client messaging.Messaging,
+	uuid uid.StringID,
+	clock clock.Clocker,
+	ins instrument.Instrumentation,
+	validateNotification NotificationValidator,
+) *Messaging {
+	return &Messaging{client: client, uuid: uuid, clock: clock, ins: ins, validateNotification: validateNotification}
 }
 
-func (m *Messaging) PublishUserRegistration(ctx context.Context, msg usecase.UserRegistrationEvent) error {
-	ctx, span := m.ins.Tracer("identity.outbound.mq").Start(ctx, "PublishUserRegistration")
-	defer span.End()
+func (m *Messaging) PublishUserRegistration(ctx context.Context, msg contracts.UserRegistered) error {
+	return m.publish(ctx, "PublishUserRegistration", contracts.UserRegisteredDestination, msg)
+}
 
-	body, err := json.Marshal(event.UserRegistrationMessage{
-		UserID:         msg.UserID,
-		Email:          msg.Email,
-		FullName:       msg.FullName,
-		ChallengeToken: msg.ChallengeToken,
-	})
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+func (m *Messaging) PublishUserForgotPassword(ctx context.Context, msg contracts.PasswordForgot) error {
+	return m.publish(ctx, "PublishUserForgotPassword", contracts.PasswordForgotDestination, msg)
+}
+
+func (m *Messaging) PublishUserMFARevoked(ctx context.Context, msg contracts.MFARevoked) error {
+	return m.publish(ctx, "PublishUserMFARevoked", contracts.MFARevokedDestination, msg)
+}
+
+func (m *Messaging) PublishUserMFARecovery(ctx context.Context, msg contracts.MFARecovery) error {
+	return m.publish(ctx, "PublishUserMFARecovery", contracts.MFARecoveryDestination, msg)
+}
+
+func (m *Messaging) PublishUserSessionRevoked(ctx context.Context, msg contracts.SessionRevoked) error {
+	return m.publish(ctx, "PublishUserSessionRevoked", contracts.SessionRevokedDestination, msg)
+}
+
+func (m *Messaging) PublishUserNewSignIn(ctx context.Context, msg contracts.NewSignIn) error {
+	return m.publish(ctx, "PublishUserNewSignIn", contracts.NewSignInDestination, msg)
+}
+
+// PublishNotificationRequested rejects data that does not match the trigger schema instead of
+// queueing a message the notification module would drop.
+func (m *Messaging) PublishNotificationRequested(ctx context.Context, msg contracts.NotificationRequested) error {
+	if err := m.validateNotification(msg); err != nil {
 		return err
 	}
 
-	cID := instrument.GetCorrelationID(ctx)
-	if _, err := m.client.Publish(ctx, event.UserRegistrationDestination, messaging.OutgoingMessage{
-		Body:    body,
-		Headers: []messaging.Header{{Key: keyOfCorrelationID, Value: []byte(cID)}},
-	}); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return err
+	return m.publish(ctx, "PublishNotificationRequested", contracts.NotificationRequestedDestination, msg)
+}
+
+func (m *Messaging) PublishAuditRecorded(ctx context.Context, msg contracts.AuditRecorded) error {
+	return m.publish(ctx, "PublishAuditRecorded", contracts.AuditRecordedDestination, msg)
+}
+
+func (m *Messaging) PublishLoginRecorded(ctx context.Context, msg contracts.LoginRecorded) error {
+	return m.publish(ctx, "PublishLoginRecorded", contracts.LoginRecordedDestination, msg)
+}
+
+func (m *Messaging) PublishUserExportRequested(ctx context.Context, msg contracts.UserExportRequested) error {
+	return m.publish(ctx, "PublishUserExportRequested", contracts.UserExportRequestedDestination, msg)
+}
+
+func (m *Messaging) PublishVerificationReminderDue(ctx context.Context, msg contracts.VerificationReminderDue) error {
+	return m.publish(ctx, "PublishVerificationReminderDue", contracts.VerificationReminderDueDestination, msg)
+}
+
+// PublishUserDeletionScheduled delivers msg after delay. Nothing is sent on brokers without
+// delayed delivery, where the account deletion job alone carries out the schedule.
+func (m *Messaging) PublishUserDeletionScheduled(ctx context.Context, msg contracts.UserDeletionScheduled, delay time.Duration) error {
+	err := m.publishAfter(ctx, "PublishUserDeletionScheduled", contracts.UserDeletionScheduledDestination, msg, delay)
+	if errors.Is(err, messaging.ErrUnsupported) {
+		return nil
 	}
 
-	return nil
+	return err
+}
+
+// publish wraps ev in a contracts envelope and sends it to destination. The caller's carrier
+// rides in the envelope too, since not every broker delivers headers.
+func (m *Messaging) publish(ctx context.Context, name, destination string, ev contracts.Event) error {
+	return m.publishAfter(ctx, name, destination, ev, 0)
 }
 
-func (m *Messaging) PublishUserForgotPassword(ctx context.Context, msg usecase.UserForgotPasswordEvent) error {
-	ctx, span := m.ins.Tracer("identity.outbound.mq").Start(ctx, "PublishUserForgotPassword")
+// publishAfter is publish with a delivery delay; zero delivers at once.
+func (m *Messaging) publishAfter(ctx context.Context, name, destination string, ev contracts.Event, delay time.Duration) error {
+	ctx, span := m.ins.Tracer("identity.outbound.mq").Start(ctx, name)
 	defer span.End()
 
-	body, err := json.Marshal(event.UserForgotPasswordMessage{
-		UserID:         msg.UserID,
-		Email:          msg.Email,
-		ChallengeToken: msg.ChallengeToken,
-	})
+	body, err := contracts.Marshal(m.uuid.Generate(), m.clock.Now(), instrument.CarrierFromContext(ctx).Headers(), ev)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
 
-	cID := instrument.GetCorrelationID(ctx)
-	if _, err := m.client.Publish(ctx, event.UserForgotPasswordDestination, messaging.OutgoingMessage{
-		Body:    body,
-		Headers: []messaging.Header{{Key: keyOfCorrelationID, Value: []byte(cID)}},
-	}); err != nil {
+	if _, err := m.client.Publish(ctx, destination, messaging.OutgoingMessage{Body: body, Delay: delay}); err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return err
@@ -0,0 +1,339 @@
+package saml
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Config struct {
+	// EntityID names this service provider to the IdP; it defaults to MetadataURL.
+	EntityID string
+	// MetadataURL is where this service provider publishes its metadata.
+	MetadataURL string
+	// ACSURL is the assertion consumer service the IdP posts responses to.
+	ACSURL string
+	// IDPMetadata is the IdP metadata XML; when empty it is fetched from IDPMetadataURL.
+	IDPMetadata    string
+	IDPMetadataURL string
+	// KeyPEM and CertificatePEM sign AuthnRequests and decrypt assertions; both are optional.
+	KeyPEM         string
+	CertificatePEM string
+}
+
+// SAML signs users in through a SAML 2.0 identity provider with SP-initiated SSO: the
+// AuthnRequest goes out with the HTTP-Redirect binding and the response comes back to the
+// assertion consumer service with the HTTP-POST binding.
+type SAML struct {
+	client         *http.Client
+	ins            instrument.Instrumentation
+	idpMetadata    string
+	idpMetadataURL string
+
+	mu  sync.Mutex
+	sp  *saml.ServiceProvider
+	idp bool
+}
+
+// New creates the adapter. Without IdP metadata SAML is off and every call reports
+// goerror.ErrNotFound; a malformed URL, key, or certificate fails startup.
+func New(client *http.Client, ins instrument.Instrumentation, cfg Config) (*SAML, error) {
+	s := &SAML{
+		client:         client,
+		ins:            ins,
+		idpMetadata:    strings.TrimSpace(cfg.IDPMetadata),
+		idpMetadataURL: strings.TrimSpace(cfg.IDPMetadataURL),
+	}
+	if s.idpMetadata == "" && s.idpMetadataURL == "" {
+		return s, nil
+	}
+
+	metadataURL, err := url.Parse(cfg.MetadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("saml: metadata url: %w", err)
+	}
+	acsURL, err := url.Parse(cfg.ACSURL)
+	if err != nil {
+		return nil, fmt.Errorf("saml: acs url: %w", err)
+	}
+
+	s.sp = &saml.ServiceProvider{
+		EntityID:    cfg.EntityID,
+		HTTPClient:  client,
+		MetadataURL: *metadataURL,
+		AcsURL:      *acsURL,
+	}
+
+	if cfg.KeyPEM != "" || cfg.CertificatePEM != "" {
+		if s.sp.Key, err = parseKey(cfg.KeyPEM); err != nil {
+			return nil, fmt.Errorf("saml: key: %w", err)
+		}
+		if s.sp.Certificate, err = parseCertificate(cfg.CertificatePEM); err != nil {
+			return nil, fmt.Errorf("saml: certificate: %w", err)
+		}
+		s.sp.SignatureMethod = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	}
+
+	return s, nil
+}
+
+func (s *SAML) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return s.ins.Tracer("identity.outbound.saml").Start(ctx, name)
+}
+
+// Metadata returns the service provider metadata XML to register with the IdP.
+func (s *SAML) Metadata(ctx context.Context) ([]byte, error) {
+	_, span := s.startSpan(ctx, "Metadata")
+	defer span.End()
+
+	if s.sp == nil {
+		return nil, goerror.ErrNotFound
+	}
+
+	out, err := xml.MarshalIndent(s.sp.Metadata(), "", "  ")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), out...), nil
+}
+
+// AuthnRequestURL returns the IdP sign-in URL carrying an AuthnRequest with requestID,
+// which the response must name in InResponseTo, and relayState, which the IdP posts back.
+func (s *SAML) AuthnRequestURL(ctx context.Context, requestID, relayState string) (string, error) {
+	ctx, span := s.startSpan(ctx, "AuthnRequestURL")
+	defer span.End()
+
+	u, err := s.authnRequestURL(ctx, requestID, relayState)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+
+	return u, nil
+}
+
+func (s *SAML) authnRequestURL(ctx context.Context, requestID, relayState string) (string, error) {
+	sp, err := s.provider(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	location := sp.GetSSOBindingLocation(saml.HTTPRedirectBinding)
+	if location == "" {
+		return "", errors.New("saml: idp has no HTTP-Redirect single sign-on service")
+	}
+
+	req, err := sp.MakeAuthenticationRequest(location, saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return "", err
+	}
+	req.ID = requestID
+
+	// the library appends the relay state to the query as is
+	u, err := req.Redirect(url.QueryEscape(relayState), sp)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+// ParseResponse verifies a base64 SAMLResponse posted to the assertion consumer service:
+// its signature, audience, validity window, and that it answers requestID.
+func (s *SAML) ParseResponse(ctx context.Context, samlResponse, requestID string) (*entity.SAMLIdentity, error) {
+	ctx, span := s.startSpan(ctx, "ParseResponse")
+	defer span.End()
+
+	identity, err := s.parseResponse(ctx, samlResponse, requestID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+func (s *SAML) parseResponse(ctx context.Context, samlResponse, requestID string) (*entity.SAMLIdentity, error) {
+	sp, err := s.provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(samlResponse)
+	if err != nil {
+		return nil, fmt.Errorf("saml: decode response: %w", err)
+	}
+
+	assertion, err := sp.ParseXMLResponse(raw, []string{requestID}, sp.AcsURL)
+	if err != nil {
+		// the library hides why a response was rejected behind a fixed message
+		var invalid *saml.InvalidResponseError
+		if errors.As(err, &invalid) && invalid.PrivateErr != nil {
+			return nil, fmt.Errorf("saml: invalid response: %w", invalid.PrivateErr)
+		}
+		return nil, err
+	}
+
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || assertion.Subject.NameID.Value == "" {
+		return nil, errors.New("saml: assertion has no subject")
+	}
+
+	identity := &entity.SAMLIdentity{
+		Subject:     assertion.Subject.NameID.Value,
+		Attributes:  make(map[string][]string),
+		AssertionID: assertion.ID,
+		ExpiresAt:   assertionExpiry(assertion),
+	}
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			values := make([]string, 0, len(attr.Values))
+			for _, v := range attr.Values {
+				values = append(values, v.Value)
+			}
+			// IdPs name attributes by URI or by friendly name, so both lead to the values
+			identity.Attributes[attr.Name] = append(identity.Attributes[attr.Name], values...)
+			if attr.FriendlyName != "" && attr.FriendlyName != attr.Name {
+				identity.Attributes[attr.FriendlyName] = append(identity.Attributes[attr.FriendlyName], values...)
+			}
+		}
+	}
+
+	return identity, nil
+}
+
+// assertionExpiry returns the end of the validity window of assertion: the earliest
+// NotOnOrAfter of its conditions and bearer subject confirmations.
+func assertionExpiry(assertion *saml.Assertion) time.Time {
+	var expiry time.Time
+	earliest := func(t time.Time) {
+		if !t.IsZero() && (expiry.IsZero() || t.Before(expiry)) {
+			expiry = t
+		}
+	}
+
+	if assertion.Conditions != nil {
+		earliest(assertion.Conditions.NotOnOrAfter)
+	}
+	for _, sc := range assertion.Subject.SubjectConfirmations {
+		if sc.SubjectConfirmationData != nil {
+			earliest(sc.SubjectConfirmationData.NotOnOrAfter)
+		}
+	}
+
+	return expiry
+}
+
+// provider returns the service provider, loading the IdP metadata on first use so an
+// unreachable IdP does not stop the service from starting.
+func (s *SAML) provider(ctx context.Context) (*saml.ServiceProvider, error) {
+	if s.sp == nil {
+		return nil, goerror.ErrNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.idp {
+		return s.sp, nil
+	}
+
+	data := []byte(s.idpMetadata)
+	if len(data) == 0 {
+		var err error
+		if data, err = s.fetch(ctx, s.idpMetadataURL); err != nil {
+			return nil, fmt.Errorf("saml: fetch idp metadata: %w", err)
+		}
+	}
+
+	metadata, err := samlsp.ParseMetadata(data)
+	if err != nil {
+		return nil, fmt.Errorf("saml: parse idp metadata: %w", err)
+	}
+
+	s.sp.IDPMetadata = metadata
+	s.idp = true
+
+	return s.sp, nil
+}
+
+func (s *SAML) fetch(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	const maxBody = 1 << 20
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: unexpected status %d", u, resp.StatusCode)
+	}
+
+	return bytes.TrimSpace(body), nil
+}
+
+func parseKey(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("key cannot sign")
+	}
+
+	return signer, nil
+}
+
+func parseCertificate(data string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
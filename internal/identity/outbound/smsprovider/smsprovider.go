@@ -0,0 +1,159 @@
+package smsprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/resilience"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	DriverTwilio = "twilio"
+	DriverVonage = "vonage"
+)
+
+// ErrDisabled is returned by Send when no SMS driver is configured.
+var ErrDisabled = errors.New("smsprovider: no driver configured")
+
+type Config struct {
+	// Driver selects the gateway; an empty or unknown driver disables SMS.
+	Driver string
+	// From is the sender number or alphanumeric sender id.
+	From string
+	// BaseURL overrides the gateway API root, mainly for sandboxes.
+	BaseURL string
+
+	TwilioAccountSID string
+	TwilioAuthToken  string
+
+	VonageAPIKey    string
+	VonageAPISecret string
+
+	// Resilience bounds every send with a timeout, retry and circuit breaker, so a degraded
+	// gateway fails fast instead of stalling sign-ins.
+	Resilience resilience.PolicyConfig
+}
+
+type gateway interface {
+	send(ctx context.Context, client *http.Client, to, body string) error
+}
+
+// SMSProvider delivers text messages through the configured gateway.
+type SMSProvider struct {
+	client  *http.Client
+	ins     instrument.Instrumentation
+	policy  *resilience.Policy
+	gateway gateway
+}
+
+// New creates the adapter for cfg.Driver.
+func New(client *http.Client, ins instrument.Instrumentation, cfg Config) *SMSProvider {
+	p := &SMSProvider{client: client, ins: ins, policy: resilience.NewPolicy(cfg.Resilience)}
+
+	switch cfg.Driver {
+	case DriverTwilio:
+		p.gateway = &twilio{
+			baseURL:    baseURL(cfg.BaseURL, "https://api.twilio.com"),
+			accountSID: cfg.TwilioAccountSID,
+			authToken:  cfg.TwilioAuthToken,
+			from:       cfg.From,
+		}
+	case DriverVonage:
+		p.gateway = &vonage{
+			baseURL:   baseURL(cfg.BaseURL, "https://rest.nexmo.com"),
+			apiKey:    cfg.VonageAPIKey,
+			apiSecret: cfg.VonageAPISecret,
+			from:      cfg.From,
+		}
+	}
+
+	return p
+}
+
+func (p *SMSProvider) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return p.ins.Tracer("identity.outbound.smsprovider").Start(ctx, name)
+}
+
+// Enabled reports whether a driver is configured.
+func (p *SMSProvider) Enabled() bool {
+	return p.gateway != nil
+}
+
+// Send texts body to the E.164 number to through the resilience policy. While the breaker
+// is open it fails with resilience.ErrCircuitOpen without calling the gateway.
+func (p *SMSProvider) Send(ctx context.Context, to, body string) error {
+	ctx, span := p.startSpan(ctx, "Send")
+	defer span.End()
+
+	err := ErrDisabled
+	if p.gateway != nil {
+		err = p.policy.Do(ctx, func(ctx context.Context) error {
+			return p.gateway.send(ctx, p.client, to, body)
+		})
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func baseURL(override, fallback string) string {
+	if override = strings.TrimRight(strings.TrimSpace(override), "/"); override != "" {
+		return override
+	}
+	return fallback
+}
+
+func postForm(ctx context.Context, client *http.Client, endpoint string, form url.Values, auth func(*http.Request)) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if auth != nil {
+		auth(req)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	const maxBody = 1 << 20
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	return body, resp.StatusCode, nil
+}
+
+// statusError describes a rejected send. Client errors other than rate limiting are marked
+// permanent: the gateway answered, so they neither trip the breaker nor are retried.
+func statusError(driver string, status int, body []byte) error {
+	const maxDetail = 200
+	detail := strings.TrimSpace(string(body))
+	if len(detail) > maxDetail {
+		detail = detail[:maxDetail]
+	}
+
+	err := fmt.Errorf("smsprovider: %s: unexpected status %d: %s", driver, status, detail)
+	if status >= 400 && status < 500 && status != http.StatusTooManyRequests {
+		return resilience.Permanent(err)
+	}
+
+	return err
+}
@@ -0,0 +1,37 @@
+package smsprovider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// twilio sends through the Programmable Messaging API.
+type twilio struct {
+	baseURL    string
+	accountSID string
+	authToken  string
+	from       string
+}
+
+func (t *twilio) send(ctx context.Context, client *http.Client, to, body string) error {
+	endpoint := t.baseURL + "/2010-04-01/Accounts/" + url.PathEscape(t.accountSID) + "/Messages.json"
+
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", t.from)
+	form.Set("Body", body)
+
+	resp, status, err := postForm(ctx, client, endpoint, form, func(r *http.Request) {
+		r.SetBasicAuth(t.accountSID, t.authToken)
+	})
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusCreated && status != http.StatusOK {
+		return statusError(DriverTwilio, status, resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package smsprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// vonage sends through the SMS API, which answers 200 and reports failures per message.
+type vonage struct {
+	baseURL   string
+	apiKey    string
+	apiSecret string
+	from      string
+}
+
+func (v *vonage) send(ctx context.Context, client *http.Client, to, body string) error {
+	form := url.Values{}
+	form.Set("api_key", v.apiKey)
+	form.Set("api_secret", v.apiSecret)
+	form.Set("from", v.from)
+	// the API expects the number without the leading plus
+	form.Set("to", strings.TrimPrefix(to, "+"))
+	form.Set("text", body)
+
+	resp, status, err := postForm(ctx, client, v.baseURL+"/sms/json", form, nil)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return statusError(DriverVonage, status, resp)
+	}
+
+	var result struct {
+		Messages []struct {
+			Status    string `json:"status"`
+			ErrorText string `json:"error-text"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return err
+	}
+
+	if len(result.Messages) == 0 {
+		return fmt.Errorf("smsprovider: %s: empty response", DriverVonage)
+	}
+
+	for _, m := range result.Messages {
+		if m.Status != "0" {
+			return fmt.Errorf("smsprovider: %s: status %s: %s", DriverVonage, m.Status, m.ErrorText)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+const (
+	// apiKeyPrefix starts every API key so leaked keys are easy to recognise and scan for.
+	apiKeyPrefix = "gbk_"
+	// apiKeyDisplayLen is how much of a key is kept in clear to tell keys apart.
+	apiKeyDisplayLen = 12
+	// apiKeySubjectPrefix starts the Casbin subject of a key. Role names cannot contain
+	// a slash, so these subjects never show up as roles.
+	apiKeySubjectPrefix = "apikey/"
+)
+
+func apiKeySubject(id int64) string {
+	return apiKeySubjectPrefix + strconv.FormatInt(id, 10)
+}
+
+type (
+	APIKeyCreateInput struct {
+		Name          string              `validate:"required,min=3,max=100"`
+		Scopes        map[string][]string `validate:"max=50,dive,keys,required,max=100,endkeys,min=1,max=10,dive,required,max=50"`
+		ExpiresInDays int                 `validate:"gte=0"`
+	}
+
+	APIKeyCreateOutput struct {
+		Key    string
+		APIKey entity.APIKey
+	}
+)
+
+// APIKeyCreate issues an API key for the authenticated user. The key is returned once and
+// only its hash is stored. Every scope must be allowed to the user, a key cannot be used
+// to create or manage other keys, and the user must have signed in recently.
+func (s *Usecase) APIKeyCreate(ctx context.Context, in APIKeyCreateInput) (*APIKeyCreateOutput, error) {
+	ctx, span := s.startSpan(ctx, "APIKeyCreate")
+	defer span.End()
+
+	in.Name = strings.TrimSpace(in.Name)
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.apiKeyOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireRecentAuth(ctx, clm); err != nil {
+		return nil, err
+	}
+
+	if limit := s.cfg.GetInt("modules.identity.api_key.max_active"); limit > 0 {
+		count, err := s.repoDB.CountActiveAPIKeys(ctx, clm.UserID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo count active api keys", "user_id", clm.UserID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+
+		if count >= int64(limit) {
+			return nil, goerror.NewBusiness("too many active API keys", goerror.CodeConflict)
+		}
+	}
+
+	days := in.ExpiresInDays
+	if maxDays := s.cfg.GetInt("modules.identity.api_key.max_ttl_days"); maxDays > 0 {
+		if days > maxDays {
+			return nil, goerror.NewBusiness("API key lifetime exceeds the allowed maximum", goerror.CodeInvalidInput)
+		}
+		if days == 0 {
+			days = maxDays
+		}
+	}
+
+	rules, err := s.apiKeyRules(ctx, clm.UserID, in.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newAPIKey()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate api key", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	keyHash, err := s.hmac.Hash(key)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash api key", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	now := s.clock.Now()
+	apiKey := entity.APIKey{
+		ID:        s.uid.Generate(),
+		UserID:    clm.UserID,
+		Name:      in.Name,
+		Prefix:    key[:apiKeyDisplayLen],
+		Scopes:    in.Scopes,
+		CreatedAt: now,
+	}
+	if days > 0 {
+		expiresAt := now.Add(time.Duration(days) * 24 * time.Hour)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repoDB.CreateAPIKey(ctx, apiKey, string(keyHash)); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create api key", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	for i := range rules {
+		rules[i][0] = apiKeySubject(apiKey.ID)
+	}
+
+	if len(rules) > 0 {
+		if _, err := s.enforcer.AddPolicies(rules); err != nil {
+			slog.ErrorContext(ctx, "failed to add api key scopes", "api_key_id", apiKey.ID, "error", err)
+			// a key without its scopes is useless, so it is not handed out
+			if rerr := s.repoDB.RevokeAPIKey(ctx, apiKey.ID, clm.UserID); rerr != nil {
+				slog.ErrorContext(ctx, "failed to repo revoke api key", "api_key_id", apiKey.ID, "error", rerr)
+			}
+			return nil, goerror.NewServer(err)
+		}
+	}
+
+	s.recordAudit(ctx, entity.AuditActionAPIKeyCreate, clm.UserID, clm.UserID, map[string]any{
+		"api_key_id": strconv.FormatInt(apiKey.ID, 10),
+		"name":       apiKey.Name,
+		"scopes":     apiKey.Scopes,
+	})
+
+	return &APIKeyCreateOutput{Key: key, APIKey: apiKey}, nil
+}
+
+// apiKeyRules turns the requested scopes into Casbin policies, rejecting any scope the
+// owner does not hold. The subject of each policy is left for the caller to fill in.
+func (s *Usecase) apiKeyRules(ctx context.Context, userID int64, scopes map[string][]string) ([][]string, error) {
+	owner := strconv.FormatInt(userID, 10)
+	rules := make([][]string, 0, len(scopes))
+	seen := make(map[[2]string]struct{})
+
+	for obj, acts := range scopes {
+		obj = strings.TrimSpace(obj)
+		for _, act := range acts {
+			act = strings.TrimSpace(act)
+			if _, ok := seen[[2]string{obj, act}]; ok {
+				continue
+			}
+			seen[[2]string{obj, act}] = struct{}{}
+
+			ok, err := authz.EnforceSubject(ctx, s.enforcer, s.authzShadow, owner, obj, act)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to check authorization", "user_id", userID, "error", err)
+				return nil, goerror.NewServer(err)
+			}
+			if !ok {
+				return nil, goerror.NewBusiness("scope not allowed: "+obj+" "+act, goerror.CodeForbidden)
+			}
+
+			rules = append(rules, []string{"", obj, act})
+		}
+	}
+
+	return rules, nil
+}
+
+type APIKeyListOutput struct {
+	APIKeys []entity.APIKey
+}
+
+// APIKeyList returns the unrevoked API keys of the authenticated user with their scopes.
+func (s *Usecase) APIKeyList(ctx context.Context) (*APIKeyListOutput, error) {
+	ctx, span := s.startSpan(ctx, "APIKeyList")
+	defer span.End()
+
+	clm, err := s.apiKeyOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repoDB.GetActiveAPIKeys(ctx, clm.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get active api keys", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	for i := range keys {
+		keys[i].Scopes, err = s.rolePermissions(apiKeySubject(keys[i].ID))
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get api key scopes", "api_key_id", keys[i].ID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+	}
+
+	return &APIKeyListOutput{APIKeys: keys}, nil
+}
+
+type APIKeyRevokeInput struct {
+	ID int64 `validate:"required,gt=0"`
+}
+
+// APIKeyRevoke revokes one of the authenticated user's API keys and drops its scopes.
+func (s *Usecase) APIKeyRevoke(ctx context.Context, in APIKeyRevokeInput) error {
+	ctx, span := s.startSpan(ctx, "APIKeyRevoke")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.apiKeyOwner(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.repoDB.RevokeAPIKey(ctx, in.ID, clm.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("API key not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo revoke api key", "api_key_id", in.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	// the key is already unusable, a leftover policy only grants a revoked subject
+	s.removeAPIKeyScopes(ctx, in.ID)
+
+	s.recordAudit(ctx, entity.AuditActionAPIKeyRevoke, clm.UserID, clm.UserID, map[string]any{
+		"api_key_id": strconv.FormatInt(in.ID, 10),
+	})
+
+	return nil
+}
+
+func (s *Usecase) removeAPIKeyScopes(ctx context.Context, id int64) {
+	if _, err := s.enforcer.RemoveFilteredPolicy(0, apiKeySubject(id)); err != nil {
+		slog.ErrorContext(ctx, "failed to remove api key scopes", "api_key_id", id, "error", err)
+	}
+}
+
+// VerifyAPIKey resolves an API key into the claims of a request. The subject is the key's
+// own Casbin subject, so the request is limited to the key's scopes.
+func (s *Usecase) VerifyAPIKey(ctx context.Context, key string) (jwt.Claims, error) {
+	ctx, span := s.startSpan(ctx, "VerifyAPIKey")
+	defer span.End()
+
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return jwt.Claims{}, goerror.NewBusiness("invalid API key", goerror.CodeUnauthorized)
+	}
+
+	keyHash, err := s.hmac.Hash(key)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash api key", "error", err)
+		return jwt.Claims{}, goerror.NewServer(err)
+	}
+
+	ak, err := s.repoDB.GetAPIKeyByToken(ctx, string(keyHash))
+	if errors.Is(err, goerror.ErrNotFound) {
+		return jwt.Claims{}, goerror.NewBusiness("invalid API key", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get api key by token", "error", err)
+		return jwt.Claims{}, goerror.NewServer(err)
+	}
+
+	now := s.clock.Now()
+	if ak.RevokedAt != nil || (ak.ExpiresAt != nil && !now.Before(*ak.ExpiresAt)) {
+		return jwt.Claims{}, goerror.NewBusiness("invalid API key", goerror.CodeUnauthorized)
+	}
+
+	if ak.UserStatus != entity.UserStatusActive {
+		slog.WarnContext(ctx, "api key of inactive user", "api_key_id", ak.ID, "user_id", ak.UserID, "status", ak.UserStatus.String())
+		return jwt.Claims{}, goerror.NewBusiness("invalid API key", goerror.CodeUnauthorized)
+	}
+
+	interval := s.cfg.GetMinute("modules.identity.api_key.last_used_interval_minutes")
+	if ak.LastUsedAt == nil || now.Sub(*ak.LastUsedAt) >= interval {
+		if err := s.repoDB.UpdateAPIKeyLastUsedAt(ctx, ak.ID); err != nil {
+			slog.WarnContext(ctx, "failed to repo update api key last used at", "api_key_id", ak.ID, "error", err)
+		}
+	}
+
+	clm := jwt.Claims{
+		UserID:    ak.UserID,
+		UserEmail: ak.UserEmail,
+		APIKeyID:  ak.ID,
+	}
+	clm.Subject = apiKeySubject(ak.ID)
+
+	return clm, nil
+}
+
+// apiKeyOwner returns the claims of a user signed in with a token. Keys are managed with
+// a personal user token only, so neither a leaked key nor an administrator impersonating
+// the user can mint a key that outlives the session.
+func (s *Usecase) apiKeyOwner(ctx context.Context) (*jwt.Claims, error) {
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("Authentication required", goerror.CodeUnauthorized)
+	}
+
+	if clm.APIKeyID != 0 {
+		return nil, goerror.NewBusiness("API keys cannot manage API keys", goerror.CodeForbidden)
+	}
+
+	if clm.ClientID != "" {
+		return nil, goerror.NewBusiness("service accounts cannot hold API keys", goerror.CodeForbidden)
+	}
+
+	if clm.Actor != nil {
+		return nil, goerror.NewBusiness("API keys cannot be managed while impersonating", goerror.CodeForbidden)
+	}
+
+	return clm, nil
+}
+
+func newAPIKey() (string, error) {
+	key, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	return apiKeyPrefix + key, nil
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"strconv"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+// auditModule names identity as the source of the events it sends to the audit module.
+const auditModule = "identity"
+
+// recordAudit sends a security-relevant action to the audit module. actorID is the user who
+// acted and subjectID the user acted on; either may be 0. The action already happened, so
+// a failed publish is logged instead of failing the request.
+func (s *Usecase) recordAudit(ctx context.Context, action entity.AuditAction, actorID, subjectID int64, meta map[string]any) {
+	if !s.cfg.GetBool("modules.identity.publish_audit_events") {
+		return
+	}
+
+	// an action taken through an impersonation token also names the staff member behind it,
+	// and one taken by a service account or with an API key names the credential, since a
+	// service account has no user ID to record as the actor
+	actor := map[string]any{}
+	if impersonator := instrument.GetActorID(ctx); impersonator != "" {
+		actor["impersonator_id"] = impersonator
+	}
+	if clm := jwt.GetAuth(ctx); clm != nil {
+		if clm.ClientID != "" {
+			actor["client_id"] = clm.ClientID
+		}
+		if clm.APIKeyID != 0 {
+			actor["api_key_id"] = strconv.FormatInt(clm.APIKeyID, 10)
+		}
+	}
+	if len(actor) > 0 {
+		withActor := make(map[string]any, len(meta)+len(actor))
+		maps.Copy(withActor, meta)
+		maps.Copy(withActor, actor)
+		meta = withActor
+	}
+
+	client := instrument.GetClient(ctx)
+	if err := s.repoAudit.PublishAuditRecorded(ctx, contracts.AuditRecorded{
+		Module:        auditModule,
+		Action:        action.String(),
+		ActorID:       actorID,
+		SubjectID:     subjectID,
+		IP:            client.IP,
+		UserAgent:     client.UserAgent,
+		CorrelationID: instrument.CarrierFromContext(ctx).CorrelationID,
+		Metadata:      meta,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to publish audit event", "action", action.String(), "by_user_id", actorID, "error", err)
+	}
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/storage"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+type auditLogArchiveRecord struct {
+	ID           int64               `json:"id,string"`
+	ActorID      int64               `json:"actor_id,string"`
+	TargetUserID int64               `json:"target_user_id,string"`
+	Action       string              `json:"action"`
+	Metadata     valueobject.JSONMap `json:"metadata"`
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+// ArchiveAuditLogs moves audit entries older than the identity_audit_logs retention window
+// to object storage. It runs on its own schedule, so entries are archived whether or not the
+// retention scheduler is enabled, and returns how many entries it moved.
+func (s *Usecase) ArchiveAuditLogs(ctx context.Context) (int64, error) {
+	ctx, span := s.startSpan(ctx, "ArchiveAuditLogs")
+	defer span.End()
+
+	days := s.cfg.GetDay("retention.tables.identity_audit_logs.days")
+	if !s.cfg.GetBool("modules.identity.audit_archive_enabled") || days <= 0 {
+		return 0, nil
+	}
+
+	before := s.clock.Now().Add(-days)
+	batchSize := s.cfg.GetInt32("modules.identity.audit_archive_batch_size")
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	var archived int64
+	for ctx.Err() == nil {
+		n, err := s.archiveAuditLogs(ctx, before, batchSize)
+		archived += n
+		if err != nil {
+			return archived, err
+		}
+		if n < int64(batchSize) {
+			break
+		}
+	}
+
+	if archived > 0 {
+		slog.InfoContext(ctx, "audit logs archived", "count", archived, "before", before)
+	}
+
+	return archived, nil
+}
+
+// purgeAuditLogs removes up to limit audit entries older than before for the retention
+// scheduler. With the archive enabled the batch is archived instead, so no entry is deleted
+// before it reached object storage.
+func (s *Usecase) purgeAuditLogs(ctx context.Context, before time.Time, limit int32) (int64, error) {
+	if !s.cfg.GetBool("modules.identity.audit_archive_enabled") {
+		return s.repoDB.DeleteAuditLogBefore(ctx, before, limit)
+	}
+
+	return s.archiveAuditLogs(ctx, before, limit)
+}
+
+// archiveAuditLogs writes up to limit audit entries older than before to object storage as
+// gzip-compressed JSONL and deletes them only after the upload succeeded.
+func (s *Usecase) archiveAuditLogs(ctx context.Context, before time.Time, limit int32) (int64, error) {
+	bucket := s.cfg.GetString("modules.identity.audit_archive_bucket")
+	prefix := strings.Trim(s.cfg.GetString("modules.identity.audit_archive_prefix"), "/")
+
+	logs, err := s.repoDB.GetAuditLogBefore(ctx, before, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get audit logs for archive", "before", before, "error", err)
+		return 0, err
+	}
+
+	if len(logs) == 0 {
+		return 0, nil
+	}
+
+	key, err := s.uploadAuditLogArchive(ctx, bucket, prefix, logs)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to upload audit log archive", "bucket", bucket, "error", err)
+		return 0, err
+	}
+
+	ids := make([]int64, 0, len(logs))
+	for _, l := range logs {
+		ids = append(ids, l.ID)
+	}
+
+	deleted, err := s.repoDB.DeleteAuditLogByIDs(ctx, ids)
+	if err != nil {
+		// the next run re-exports the same rows under the same key, so this is safe to retry
+		slog.ErrorContext(ctx, "failed to repo delete archived audit logs", "key", key, "error", err)
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+func (s *Usecase) uploadAuditLogArchive(ctx context.Context, bucket, prefix string, logs []entity.AuditLog) (string, error) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	enc := json.NewEncoder(gz)
+
+	for _, l := range logs {
+		if err := enc.Encode(auditLogArchiveRecord{
+			ID:           l.ID,
+			ActorID:      l.ActorID,
+			TargetUserID: l.TargetUserID,
+			Action:       l.Action.String(),
+			Metadata:     l.Metadata,
+			CreatedAt:    l.CreatedAt,
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+
+	first, last := logs[0], logs[len(logs)-1]
+	key := fmt.Sprintf("%s/%d-%d.jsonl.gz", first.CreatedAt.UTC().Format("2006/01/02"), first.ID, last.ID)
+	if prefix != "" {
+		key = prefix + "/" + key
+	}
+
+	if _, err := s.storage.PutObject(ctx, bucket, key, buf, storage.PutOptions{
+		Size:        int64(buf.Len()),
+		ContentType: "application/gzip",
+		Metadata: map[string]string{
+			"format":  "jsonl",
+			"records": strconv.Itoa(len(logs)),
+		},
+	}); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
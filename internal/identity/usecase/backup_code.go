@@ -31,6 +31,10 @@ func (s *Usecase) BackupCode(ctx context.Context, in BackupCodeInput) (*BackupCo
 		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
 	}
 
+	if err := s.requireRecentAuth(ctx, clm); err != nil {
+		return nil, err
+	}
+
 	user, err := s.repoDB.GetUserCredentialInfo(ctx, clm.UserID)
 	if errors.Is(err, goerror.ErrNotFound) {
 		slog.WarnContext(ctx, "user account not found", "user_id", clm.UserID)
@@ -81,6 +85,8 @@ func (s *Usecase) BackupCode(ctx context.Context, in BackupCodeInput) (*BackupCo
 		return nil, goerror.NewServer(err)
 	}
 
+	s.recordAudit(ctx, entity.AuditActionMFABackupCodes, user.ID, user.ID, map[string]any{"count": len(codes)})
+
 	return &BackupCodeOutput{RecoveryCodes: recoveryCodes}, nil
 }
 
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+)
+
+// Endpoints that can ask for a CAPTCHA, as listed in modules.identity.captcha.endpoints.
+const (
+	CaptchaEndpointLogin          = "login"
+	CaptchaEndpointRegister       = "register"
+	CaptchaEndpointRegisterResend = "register_resend"
+	CaptchaEndpointPasswordForgot = "password_forgot"
+)
+
+// verifyCaptcha rejects the request when endpoint requires a CAPTCHA and token is missing
+// or not accepted by the configured service. It runs before any account lookup so bots
+// learn nothing about which emails exist.
+func (s *Usecase) verifyCaptcha(ctx context.Context, endpoint, token string) error {
+	if !s.repoCaptcha.Enabled() {
+		return nil
+	}
+
+	endpoints := s.cfg.GetArray("modules.identity.captcha.endpoints")
+	if !slices.ContainsFunc(endpoints, func(e string) bool { return strings.TrimSpace(e) == endpoint }) {
+		return nil
+	}
+
+	ok, err := s.repoCaptcha.Verify(ctx, token, instrument.GetClient(ctx).IP)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo verify captcha", "endpoint", endpoint, "error", err)
+		return goerror.NewServer(err)
+	}
+	if !ok {
+		slog.WarnContext(ctx, "captcha verification failed", "endpoint", endpoint)
+		return goerror.NewBusiness("captcha verification failed", goerror.CodeForbidden)
+	}
+
+	return nil
+}
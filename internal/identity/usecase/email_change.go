@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+const (
+	triggerKeyEmailChangeVerify = "email_change_verify"
+	triggerKeyEmailChanged      = "email_changed"
+)
+
+type EmailChangeInput struct {
+	NewEmail        string `validate:"required,email"`
+	CurrentPassword string `validate:"required"`
+}
+
+// EmailChange sends a confirmation link to the new address. The current address stays
+// active until EmailChangeConfirm succeeds; a newer request replaces any pending one.
+func (s *Usecase) EmailChange(ctx context.Context, in EmailChangeInput) error {
+	ctx, span := s.startSpan(ctx, "EmailChange")
+	defer span.End()
+
+	in.NewEmail = strings.TrimSpace(strings.ToLower(in.NewEmail))
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	if err := s.requireRecentAuth(ctx, clm); err != nil {
+		return err
+	}
+
+	user, err := s.repoDB.GetUserCredentialInfo(ctx, clm.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user account not found", "user_id", clm.UserID)
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user credential info", "user_id", clm.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, user.ID, user.Status); err != nil {
+		return err
+	}
+
+	if !s.bcrypt.Verify(user.Password, in.CurrentPassword) {
+		slog.WarnContext(ctx, "current password mismatch", "user_id", user.ID)
+		return goerror.NewBusiness("invalid password", goerror.CodeUnauthorized)
+	}
+
+	newEmail := s.normalizeEmail(in.NewEmail)
+	if newEmail == s.normalizeEmail(user.Email) {
+		return goerror.NewBusiness("new email must differ from the current email", goerror.CodeInvalidInput)
+	}
+
+	if _, err := s.getUserByEmail(ctx, newEmail, true); err == nil {
+		return goerror.NewBusiness("Email already registered", goerror.CodeConflict)
+	} else if !errors.Is(err, goerror.ErrNotFound) {
+		slog.ErrorContext(ctx, "failed to repo get user by email", "email", newEmail, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	return s.requestEmailChange(ctx, user.ID, newEmail)
+}
+
+// requestEmailChange replaces any pending email change of userID with one to newEmail and
+// sends the confirmation link there. The address only changes once the link is followed.
+func (s *Usecase) requestEmailChange(ctx context.Context, userID int64, newEmail string) error {
+	if _, err := s.repoDB.DeleteChallengeByUserPurpose(ctx, userID, entity.ChallengePurposeEmailChange); err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete pending email change", "user_id", userID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	cToken := s.oid.Generate()
+	cTokenHash, err := s.hmac.Hash(cToken)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token", "error", err)
+		return goerror.NewServer(err)
+	}
+
+	meta, err := entity.EncodeMetadata(&entity.EmailChangeMetadata{NewEmail: newEmail})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode email change metadata", "user_id", userID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.CreateChallenge(ctx, entity.Challenge{
+		ID:        s.uid.Generate(),
+		UserID:    userID,
+		Token:     string(cTokenHash),
+		Purpose:   entity.ChallengePurposeEmailChange,
+		ExpiresAt: s.clock.Now().Add(s.cfg.GetHour("modules.identity.email_change_ttl_hours")),
+		Metadata:  meta,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create email change challenge", "user_id", userID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.repoMessaging.PublishNotificationRequested(ctx, contracts.NotificationRequested{
+		UserID:     userID,
+		Email:      newEmail,
+		TriggerKey: triggerKeyEmailChangeVerify,
+		Channels:   []string{"email"},
+		Data: map[string]any{
+			"new_email":  newEmail,
+			"verify_url": s.cfg.GetString("app.web") + "/email-change/confirm?token=" + url.QueryEscape(cToken),
+		},
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to publish email change verification", "user_id", userID, "error", err)
+	}
+
+	return nil
+}
+
+type EmailChangeConfirmInput struct {
+	ChallengeToken string `validate:"required"`
+}
+
+// EmailChangeConfirm switches the account to the confirmed address and signs out every
+// session. The previous address is told about the change.
+func (s *Usecase) EmailChangeConfirm(ctx context.Context, in EmailChangeConfirmInput) error {
+	ctx, span := s.startSpan(ctx, "EmailChangeConfirm")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	cTokenHash, err := s.hmac.Hash(in.ChallengeToken)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token", "error", err)
+		return goerror.NewServer(err)
+	}
+
+	cu, err := s.repoDB.GetChallengeUserByTokenPurpose(ctx, string(cTokenHash), entity.ChallengePurposeEmailChange)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("invalid or expired email change token", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get challenge user by token purpose", "challenge_token", string(cTokenHash), "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, cu.UserID, cu.UserStatus); err != nil {
+		return err
+	}
+
+	meta, err := entity.DecodeMetadata[entity.EmailChangeMetadata](cu.ChallengeMetadata)
+	if err != nil {
+		slog.ErrorContext(ctx, "invalid email change challenge metadata", "challenge_id", cu.ChallengeID, "error", err)
+		return goerror.NewBusiness("invalid or expired email change token", goerror.CodeUnauthorized)
+	}
+	newEmail := meta.NewEmail
+
+	emailHash, sealedOnly, err := s.emailLookupHash(newEmail)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash email", "user_id", cu.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	err = s.repoDB.ChangeUserEmail(ctx, entity.ChangeUserEmail{
+		ChallengeID:     cu.ChallengeID,
+		UserID:          cu.UserID,
+		Email:           newEmail,
+		EmailHash:       emailHash,
+		EmailSealedOnly: sealedOnly,
+	})
+	if errors.Is(err, goerror.ErrConflict) {
+		return goerror.NewBusiness("Email already registered", goerror.CodeConflict)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo change user email", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	// access tokens still carry the old address
+	s.revokeUserAccessTokens(ctx, cu.UserID)
+
+	if err := s.repoMessaging.PublishNotificationRequested(ctx, contracts.NotificationRequested{
+		UserID:     cu.UserID,
+		Email:      cu.UserEmail,
+		TriggerKey: triggerKeyEmailChanged,
+		Channels:   []string{"email"},
+		Data: map[string]any{
+			"new_email":    newEmail,
+			"security_url": s.cfg.GetString("app.web") + "/settings/security",
+		},
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to publish email changed", "user_id", cu.UserID, "error", err)
+	}
+
+	return nil
+}
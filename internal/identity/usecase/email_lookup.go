@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+)
+
+// emailLookupEnabled reports whether users are resolved through the HMAC of their email.
+// When strict mode is off, rows written before the rollout are still found by plaintext
+// and backfilled on first access.
+func (s *Usecase) emailLookupEnabled() bool {
+	return s.cfg.GetBool("modules.identity.email_lookup_hash_enabled")
+}
+
+func (s *Usecase) emailLookupStrict() bool {
+	return s.cfg.GetBool("modules.identity.email_lookup_hash_strict")
+}
+
+func (s *Usecase) emailHash(email string) (string, error) {
+	hashed, err := s.hmac.Hash(strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashed), nil
+}
+
+// emailLookupHash returns the lookup hash stored alongside the email, or an empty value
+// when hashed email lookup is disabled. sealedOnly reports whether the email is stored only
+// sealed, as strict mode never reads the plaintext.
+func (s *Usecase) emailLookupHash(email string) (hash string, sealedOnly bool, err error) {
+	if !s.emailLookupEnabled() {
+		return "", false, nil
+	}
+
+	hash, err = s.emailHash(email)
+	if err != nil {
+		return "", false, err
+	}
+
+	return hash, s.emailLookupStrict(), nil
+}
+
+func (s *Usecase) backfillEmailLookup(ctx context.Context, userID int64, email string) {
+	hashed, _, err := s.emailLookupHash(email)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash email for backfill", "user_id", userID, "error", err)
+		return
+	}
+
+	if err := s.repoDB.UpdateUserEmailLookup(ctx, userID, email, hashed); err != nil {
+		slog.ErrorContext(ctx, "failed to repo backfill email lookup", "user_id", userID, "error", err)
+	}
+}
+
+// getUserByEmail resolves a user by any of the email's lookup candidates.
+func (s *Usecase) getUserByEmail(ctx context.Context, email string, includeDeleted bool) (*entity.User, error) {
+	var (
+		user *entity.User
+		err  error
+	)
+	for _, candidate := range s.emailCandidates(email) {
+		user, err = s.getUserByExactEmail(ctx, candidate, includeDeleted)
+		if !errors.Is(err, goerror.ErrNotFound) {
+			break
+		}
+	}
+
+	return user, err
+}
+
+func (s *Usecase) getUserByExactEmail(ctx context.Context, email string, includeDeleted bool) (*entity.User, error) {
+	if !s.emailLookupEnabled() {
+		return s.repoDB.GetUserByEmail(ctx, email, includeDeleted)
+	}
+
+	hashed, err := s.emailHash(email)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repoDB.GetUserByEmailHash(ctx, hashed, includeDeleted)
+	if !errors.Is(err, goerror.ErrNotFound) || s.emailLookupStrict() {
+		return user, err
+	}
+
+	user, err = s.repoDB.GetUserByEmail(ctx, email, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	s.backfillEmailLookup(ctx, user.ID, user.Email)
+
+	return user, nil
+}
+
+// getUserLoginInfo resolves login info by any of the email's lookup candidates.
+func (s *Usecase) getUserLoginInfo(ctx context.Context, email string) (*entity.UserLoginInfo, error) {
+	var (
+		user *entity.UserLoginInfo
+		err  error
+	)
+	for _, candidate := range s.emailCandidates(email) {
+		user, err = s.getUserLoginInfoByExactEmail(ctx, candidate)
+		if !errors.Is(err, goerror.ErrNotFound) {
+			break
+		}
+	}
+
+	return user, err
+}
+
+func (s *Usecase) getUserLoginInfoByExactEmail(ctx context.Context, email string) (*entity.UserLoginInfo, error) {
+	if !s.emailLookupEnabled() {
+		return s.repoDB.GetUserLoginInfo(ctx, email)
+	}
+
+	hashed, err := s.emailHash(email)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repoDB.GetUserLoginInfoByEmailHash(ctx, hashed)
+	if !errors.Is(err, goerror.ErrNotFound) || s.emailLookupStrict() {
+		return user, err
+	}
+
+	user, err = s.repoDB.GetUserLoginInfo(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	s.backfillEmailLookup(ctx, user.ID, user.Email)
+
+	return user, nil
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"strings"
+)
+
+// gmailDomains are the domains where Google ignores dots and plus aliases in the local part.
+var gmailDomains = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+}
+
+// normalizeEmail returns the canonical form of email used for storage and uniqueness.
+//
+// It always lowercases and trims. Depending on config it also drops "+alias" suffixes
+// for every domain and folds gmail addresses (dots, aliases, googlemail.com) so one
+// inbox cannot be registered several times.
+func (s *Usecase) normalizeEmail(email string) string {
+	email = strings.TrimSpace(strings.ToLower(email))
+
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+
+	stripPlus := s.cfg.GetBool("modules.identity.email_normalization.strip_plus_alias")
+	foldGmail := s.cfg.GetBool("modules.identity.email_normalization.fold_gmail") && gmailDomains[domain]
+
+	if stripPlus || foldGmail {
+		if i := strings.Index(local, "+"); i > 0 {
+			local = local[:i]
+		}
+	}
+
+	if foldGmail {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+
+	return local + "@" + domain
+}
+
+// emailCandidates returns the addresses a lookup should try: the canonical form first,
+// then the plain lowercased one for rows stored before a folding rule was enabled.
+func (s *Usecase) emailCandidates(email string) []string {
+	plain := strings.TrimSpace(strings.ToLower(email))
+
+	canonical := s.normalizeEmail(plain)
+	if canonical == plain {
+		return []string{canonical}
+	}
+
+	return []string{canonical, plain}
+}
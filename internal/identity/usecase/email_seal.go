@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+)
+
+// SealPlaintextEmails replaces the plaintext email of up to
+// modules.identity.email_seal_batch_size users with its sealed copy and lookup hash. It only
+// runs in strict hashed lookup, where the plaintext column is never read, and returns how
+// many emails it sealed.
+func (s *Usecase) SealPlaintextEmails(ctx context.Context) (int, error) {
+	ctx, span := s.startSpan(ctx, "SealPlaintextEmails")
+	defer span.End()
+
+	if !s.emailLookupEnabled() || !s.emailLookupStrict() {
+		return 0, nil
+	}
+
+	batchSize := s.cfg.GetInt32("modules.identity.email_seal_batch_size")
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	users, err := s.repoDB.GetUserPlaintextEmails(ctx, batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user plaintext emails", "error", err)
+		return 0, goerror.NewServer(err)
+	}
+
+	sealed := 0
+	for _, user := range users {
+		hashed, err := s.emailHash(user.Email)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to hash email for sealing", "user_id", user.UserID, "error", err)
+			continue
+		}
+
+		// a user who changed the address since it was read is sealed on the next run
+		ok, err := s.repoDB.SealUserEmail(ctx, user.UserID, user.Email, hashed)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo seal user email", "user_id", user.UserID, "error", err)
+			continue
+		}
+		if ok {
+			sealed++
+		}
+	}
+
+	if sealed > 0 {
+		slog.InfoContext(ctx, "user emails sealed", "count", sealed)
+	}
+
+	return sealed, nil
+}
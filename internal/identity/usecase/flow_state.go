@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// flowState is what a sign-in or link with an external identity provider keeps while the
+// browser is away at the provider. Only State travels through the provider; the rest goes
+// back to the browser that started the flow as its binding, an HttpOnly cookie the provider
+// callback must present. A state replayed from another browser has no matching binding, and
+// the PKCE verifier and nonce never leave the server and that browser.
+type flowState struct {
+	State string `json:"s"`
+	// Subject is the provider, or "saml", the flow was started for.
+	Subject  string `json:"p"`
+	Verifier string `json:"v"`
+	Nonce    string `json:"n"`
+	// UserID, TokenID and IssuedAt name the session a link flow was started from.
+	UserID    int64  `json:"u,omitempty"`
+	TokenID   string `json:"t,omitempty"`
+	IssuedAt  int64  `json:"i,omitempty"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// flowStateTTL is how long a started flow can be completed.
+func (s *Usecase) flowStateTTL() time.Duration {
+	ttl := s.cfg.GetMinute("modules.identity.oauth.state_ttl_minutes")
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	return ttl
+}
+
+// newFlowState starts a flow for subject with a random state, PKCE verifier and nonce.
+// statePrefix lets the callbacks tell a link from a sign-in.
+func (s *Usecase) newFlowState(subject, statePrefix string) *flowState {
+	return &flowState{
+		State:     statePrefix + s.oid.Generate() + s.oid.Generate(),
+		Subject:   subject,
+		Verifier:  oauth2.GenerateVerifier(),
+		Nonce:     s.oid.Generate() + s.oid.Generate(),
+		ExpiresAt: s.clock.Now().Add(s.flowStateTTL()).Unix(),
+	}
+}
+
+// sealFlowState returns the binding of fs, signed so the browser cannot change it.
+func (s *Usecase) sealFlowState(fs *flowState) (string, error) {
+	payload, err := json.Marshal(fs)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	sig, err := s.hmac.Hash("flow_state:" + encoded)
+	if err != nil {
+		return "", err
+	}
+
+	return encoded + "." + string(sig), nil
+}
+
+// openFlowState checks that binding is a live flow for subject started together with state.
+func (s *Usecase) openFlowState(binding, state, subject string) (*flowState, bool) {
+	encoded, sig, ok := strings.Cut(binding, ".")
+	if !ok || !s.hmac.Verify(sig, "flow_state:"+encoded) {
+		return nil, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false
+	}
+
+	var fs flowState
+	if err := json.Unmarshal(payload, &fs); err != nil {
+		return nil, false
+	}
+
+	if fs.Subject != subject || s.clock.Now().Unix() > fs.ExpiresAt ||
+		subtle.ConstantTimeCompare([]byte(fs.State), []byte(state)) != 1 {
+		return nil, false
+	}
+
+	return &fs, true
+}
@@ -0,0 +1,315 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+// linkStatePrefix marks a state issued to link an identity to the account that asked for
+// it, so the app and the assertion consumer service can tell it from a sign-in.
+const linkStatePrefix = "link."
+
+type (
+	LinkIdentityAuthorizeInput struct {
+		Provider string `validate:"required,alphanum,max=32"`
+	}
+
+	LinkOAuthInput struct {
+		Provider string `validate:"required,alphanum,max=32"`
+		Code     string `validate:"required"`
+		State    string `validate:"required"`
+		Binding  string
+	}
+
+	LinkSAMLInput struct {
+		SAMLResponse string `validate:"required"`
+		RelayState   string `validate:"required"`
+		Binding      string
+	}
+
+	ListIdentitiesOutput struct {
+		Identities []entity.UserConnection
+	}
+
+	UnlinkIdentityInput struct {
+		ID int64 `validate:"required,gt=0"`
+	}
+)
+
+// IsLinkState reports whether state was issued by LinkIdentityAuthorize rather than for a sign-in.
+func IsLinkState(state string) bool {
+	return strings.HasPrefix(state, linkStatePrefix)
+}
+
+// ListIdentities returns the external identities linked to the authenticated user, most
+// recently linked first.
+func (s *Usecase) ListIdentities(ctx context.Context) (*ListIdentitiesOutput, error) {
+	ctx, span := s.startSpan(ctx, "ListIdentities")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	conns, err := s.repoDB.GetUserConnections(ctx, clm.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user connections", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &ListIdentitiesOutput{Identities: conns}, nil
+}
+
+// LinkIdentityAuthorize starts linking an OAuth provider or SAML identity to the
+// authenticated user. The flow is bound to the browser and to the session that started it:
+// the binding names the user and the access token, and completing the link is refused once
+// that token is revoked.
+func (s *Usecase) LinkIdentityAuthorize(ctx context.Context, in LinkIdentityAuthorizeInput) (*OAuthAuthorizeOutput, error) {
+	ctx, span := s.startSpan(ctx, "LinkIdentityAuthorize")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	in.Provider = strings.ToLower(strings.TrimSpace(in.Provider))
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if err := s.requireRecentAuth(ctx, clm); err != nil {
+		return nil, err
+	}
+
+	fs := s.newFlowState(in.Provider, linkStatePrefix)
+	fs.UserID = clm.UserID
+	fs.TokenID = clm.ID
+	if clm.IssuedAt != nil {
+		fs.IssuedAt = clm.IssuedAt.Unix()
+	}
+
+	binding, err := s.sealFlowState(fs)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create link state", "provider", in.Provider, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	var authURL string
+	if in.Provider == samlProvider {
+		authURL, err = s.repoSAML.AuthnRequestURL(ctx, samlRequestID(fs.Verifier), fs.State)
+	} else {
+		authURL, err = s.repoOAuth.AuthCodeURL(ctx, in.Provider, fs.State, fs.Verifier, fs.Nonce)
+	}
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "identity provider not configured", "provider", in.Provider)
+		return nil, goerror.NewBusiness("identity provider not supported", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo build link authorization url", "provider", in.Provider, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &OAuthAuthorizeOutput{
+		AuthorizationURL: authURL,
+		State:            fs.State,
+		Binding:          binding,
+		BindingTTL:       s.flowStateTTL(),
+	}, nil
+}
+
+// LinkOAuth completes linking an OAuth identity. The app posts the code and state the
+// provider redirected with, authenticated as the user that started the link and from the
+// browser holding its binding.
+func (s *Usecase) LinkOAuth(ctx context.Context, in LinkOAuthInput) (*entity.UserConnection, error) {
+	ctx, span := s.startSpan(ctx, "LinkOAuth")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	in.Provider = strings.ToLower(strings.TrimSpace(in.Provider))
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	fs, err := s.openLinkState(ctx, in.Binding, in.State, in.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if fs.UserID != clm.UserID {
+		slog.WarnContext(ctx, "link state was started by another user", "provider", in.Provider, "user_id", clm.UserID)
+		return nil, goerror.NewBusiness("invalid or expired oauth state", goerror.CodeUnauthorized)
+	}
+
+	identity, err := s.repoOAuth.Exchange(ctx, in.Provider, in.Code, fs.Verifier, fs.Nonce)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "oauth provider not configured", "provider", in.Provider)
+		return nil, goerror.NewBusiness("oauth provider not supported", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "failed to repo exchange oauth code", "provider", in.Provider, "error", err)
+		return nil, goerror.NewBusiness("oauth sign-in failed", goerror.CodeUnauthorized)
+	}
+
+	return s.linkIdentity(ctx, fs.UserID, identity)
+}
+
+// LinkSAML completes linking a SAML identity, arriving at the assertion consumer service
+// with a relay state from LinkIdentityAuthorize. The identity provider posts there without
+// the user's token, so the binding alone names the session that started the link.
+func (s *Usecase) LinkSAML(ctx context.Context, in LinkSAMLInput) (*entity.UserConnection, error) {
+	ctx, span := s.startSpan(ctx, "LinkSAML")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	fs, err := s.openLinkState(ctx, in.Binding, in.RelayState, samlProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	assertion, err := s.parseSAMLResponse(ctx, in.SAMLResponse, fs.Verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.linkIdentity(ctx, fs.UserID, s.samlIdentity(assertion))
+}
+
+// UnlinkIdentity removes an external identity from the authenticated user. The account
+// keeps its password, so password sign-in and reset still work without the identity.
+func (s *Usecase) UnlinkIdentity(ctx context.Context, in UnlinkIdentityInput) error {
+	ctx, span := s.startSpan(ctx, "UnlinkIdentity")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	if err := s.requireRecentAuth(ctx, clm); err != nil {
+		return err
+	}
+
+	provider, err := s.repoDB.DeleteUserConnection(ctx, in.ID, clm.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("identity not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete user connection", "user_id", clm.UserID, "identity_id", in.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionIdentityUnlink, clm.UserID, clm.UserID, map[string]any{
+		"identity_id": strconv.FormatInt(in.ID, 10),
+		"provider":    provider,
+	})
+
+	return nil
+}
+
+// linkIdentity links identity to the user, refusing an identity that already belongs to an
+// account, an email registered to another account, and a second identity from a provider.
+func (s *Usecase) linkIdentity(ctx context.Context, userID int64, identity *entity.OAuthIdentity) (*entity.UserConnection, error) {
+	owner, err := s.repoDB.GetUserLoginInfoByConnection(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		if owner.ID == userID {
+			return nil, goerror.NewBusiness("identity is already linked to this account", goerror.CodeConflict)
+		}
+		slog.WarnContext(ctx, "identity is linked to another account", "user_id", userID, "provider", identity.Provider)
+		return nil, goerror.NewBusiness("identity is linked to another account", goerror.CodeConflict)
+	}
+	if !errors.Is(err, goerror.ErrNotFound) {
+		slog.ErrorContext(ctx, "failed to repo get user by connection", "provider", identity.Provider, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if identity.Email != "" {
+		other, err := s.getUserLoginInfo(ctx, identity.Email)
+		if err == nil && other.ID != userID {
+			slog.WarnContext(ctx, "identity email belongs to another account", "user_id", userID, "provider", identity.Provider)
+			return nil, goerror.NewBusiness("the identity's email belongs to another account", goerror.CodeConflict)
+		}
+		if err != nil && !errors.Is(err, goerror.ErrNotFound) {
+			slog.ErrorContext(ctx, "failed to repo get user by email", "email", identity.Email, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+	}
+
+	conns, err := s.repoDB.GetUserConnections(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user connections", "user_id", userID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	for _, c := range conns {
+		if c.Provider == identity.Provider {
+			return nil, goerror.NewBusiness("a "+identity.Provider+" identity is already linked, unlink it first", goerror.CodeConflict)
+		}
+	}
+
+	conn := entity.UserConnection{
+		ID:             s.uid.Generate(),
+		UserID:         userID,
+		Provider:       identity.Provider,
+		ProviderUserID: identity.Subject,
+		CreatedAt:      s.clock.Now(),
+	}
+
+	err = s.repoDB.CreateUserConnection(ctx, conn)
+	if errors.Is(err, goerror.ErrConflict) {
+		// the identity belongs to a deleted account, which the lookup above skips
+		return nil, goerror.NewBusiness("identity is linked to another account", goerror.CodeConflict)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo create user connection", "user_id", userID, "provider", identity.Provider, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionIdentityLink, userID, userID, map[string]any{
+		"identity_id": strconv.FormatInt(conn.ID, 10),
+		"provider":    conn.Provider,
+	})
+
+	return &conn, nil
+}
+
+// openLinkState checks that binding is a live link flow for provider started together with
+// state, and that the access token it was started with has not been revoked since, by a
+// sign-out or a password change for instance.
+func (s *Usecase) openLinkState(ctx context.Context, binding, state, provider string) (*flowState, error) {
+	fs, ok := s.openFlowState(binding, state, provider)
+	if !ok || fs.UserID == 0 || !IsLinkState(fs.State) {
+		slog.WarnContext(ctx, "link state is invalid, expired or from another browser", "provider", provider)
+		return nil, goerror.NewBusiness("invalid or expired link state", goerror.CodeUnauthorized)
+	}
+
+	revoked, err := s.denylist.IsRevoked(ctx, fs.TokenID, fs.UserID, time.Unix(fs.IssuedAt, 0))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to check token denylist", "user_id", fs.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	if revoked {
+		slog.WarnContext(ctx, "link state belongs to a revoked session", "user_id", fs.UserID, "provider", provider)
+		return nil, goerror.NewBusiness("invalid or expired link state", goerror.CodeUnauthorized)
+	}
+
+	return fs, nil
+}
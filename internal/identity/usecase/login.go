@@ -5,20 +5,38 @@ import (
 	"errors"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 )
 
 type LoginInput struct {
-	Email    string `validate:"required,email"`
-	Password string `validate:"required"`
+	Email string `validate:"required_without=Username,omitempty,email"`
+	// Username signs in by username instead of Email, when modules.identity.username.enabled is set.
+	Username   string `validate:"required_without=Email,omitempty,max=64"`
+	Password   string `validate:"required"`
+	IP         string
+	UserAgent  string
+	ClientType string
+	DeviceName string
+	// CaptchaToken is the CAPTCHA response, required when login is listed in the captcha endpoints.
+	CaptchaToken string
+	// DeviceToken is the token of a trusted device, returned by an earlier Login2FA.
+	DeviceToken string
 }
 
 type LoginOutput struct {
 	MfaRequired      bool
 	ChallengeToken   string
 	AvailableMethods []string
+	// PasswordResetRequired means an administrator requires a new password: ChallengeToken
+	// is a password reset token to use with PasswordReset, and no tokens are issued.
+	PasswordResetRequired bool
+	// MfaSetupRequired means an administrator requires MFA and the user has none yet:
+	// ChallengeToken starts LoginMFASetup, which issues the tokens once a factor is enrolled.
+	MfaSetupRequired bool
 	//
 	AccessToken  string
 	RefreshToken string
@@ -32,27 +50,96 @@ func (s *Usecase) Login(ctx context.Context, in LoginInput) (*LoginOutput, error
 		return nil, goerror.NewInvalidInput(err)
 	}
 
-	email := strings.TrimSpace(in.Email)
-	user, err := s.repoDB.GetUserLoginInfo(ctx, email)
+	if err := s.verifyCaptcha(ctx, CaptchaEndpointLogin, in.CaptchaToken); err != nil {
+		return nil, err
+	}
+
+	if in.Email == "" && !s.usernameEnabled() {
+		return nil, goerror.NewInvalidInput(nil, "username", "usernames are not enabled")
+	}
+
+	meta := sessionMetadata(in.IP, in.UserAgent, in.ClientType, in.DeviceName)
+
+	if err := s.checkLoginThrottle(ctx, in.IP, ""); err != nil {
+		s.recordLoginEvent(ctx, 0, entity.LoginMethodPassword, "throttled", meta)
+		return nil, err
+	}
+
+	var (
+		user       *entity.UserLoginInfo
+		err        error
+		invalidMsg = "invalid email or password"
+		unknownKey string
+	)
+	if in.Email != "" {
+		email := strings.TrimSpace(in.Email)
+		unknownKey = unknownLoginThrottleKey("email", s.normalizeEmail(email))
+		user, err = s.getUserLoginInfo(ctx, email)
+	} else {
+		username := normalizeUsername(in.Username)
+		invalidMsg = "invalid username or password"
+		unknownKey = unknownLoginThrottleKey("username", username)
+		user, err = s.repoDB.GetUserLoginInfoByUsername(ctx, username)
+	}
 	if errors.Is(err, goerror.ErrNotFound) {
-		slog.WarnContext(ctx, "user account not found", "email", email)
-		return nil, goerror.NewBusiness("invalid email or password", goerror.CodeUnauthorized)
+		slog.WarnContext(ctx, "user account not found", "email", in.Email, "username", in.Username)
+		// unknown accounts lock like known ones, so lockouts do not reveal which exist
+		if err := s.checkLoginThrottle(ctx, "", unknownKey); err != nil {
+			s.recordLoginEvent(ctx, 0, entity.LoginMethodPassword, "account_locked", meta)
+			return nil, err
+		}
+		s.recordLoginFailure(ctx, in.IP, unknownKey)
+		s.recordAudit(ctx, entity.AuditActionAuthLoginFailed, 0, 0, map[string]any{"reason": "unknown_account"})
+		s.recordLoginEvent(ctx, 0, entity.LoginMethodPassword, "unknown_account", meta)
+		return nil, goerror.NewBusiness(invalidMsg, goerror.CodeUnauthorized)
 	}
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to repo get user by email", "email", email, "error", err)
+		slog.ErrorContext(ctx, "failed to repo get user login info", "email", in.Email, "username", in.Username, "error", err)
 		return nil, goerror.NewServer(err)
 	}
 
+	// the lockout follows the user, whichever identifier the attempt named
+	throttleKey := loginThrottleKey(user.ID)
+	if err := s.checkLoginThrottle(ctx, "", throttleKey); err != nil {
+		s.recordLoginEvent(ctx, user.ID, entity.LoginMethodPassword, "account_locked", meta)
+		return nil, err
+	}
+
 	if err := s.ensureUserStatusAllowed(ctx, user.ID, user.Status); err != nil {
+		s.recordLoginEvent(ctx, user.ID, entity.LoginMethodPassword, "account_blocked", meta)
 		return nil, err
 	}
 
 	if !s.bcrypt.Verify(user.Password, in.Password) {
 		slog.WarnContext(ctx, "password user account not match", "user_id", user.ID)
-		return nil, goerror.NewBusiness("invalid email or password", goerror.CodeUnauthorized)
+		s.recordLoginFailure(ctx, in.IP, throttleKey)
+		s.recordAudit(ctx, entity.AuditActionAuthLoginFailed, user.ID, user.ID, map[string]any{"reason": "invalid_password"})
+		s.recordLoginEvent(ctx, user.ID, entity.LoginMethodPassword, "invalid_password", meta)
+		return nil, goerror.NewBusiness(invalidMsg, goerror.CodeUnauthorized)
 	}
 
-	if user.HasMFA {
+	s.resetLoginFailures(ctx, throttleKey)
+
+	return s.completeLogin(ctx, user, entity.LoginMethodPassword, meta, in.DeviceToken)
+}
+
+// completeLogin finishes a login for an authenticated user, either by opening an MFA
+// challenge or by issuing the access and refresh tokens. meta describes the client and is
+// stored on the refresh token so the session can be recognised later; method is the way the
+// user signed in, kept in the login history. A deviceToken of a trusted device of the user
+// skips the MFA challenge. Only once every factor passed is a user an administrator requires
+// a new password from handed a password reset token, and a user required to use MFA without
+// a factor sent to enroll one.
+func (s *Usecase) completeLogin(ctx context.Context, user *entity.UserLoginInfo, method entity.LoginMethod, meta entity.SessionMetadata, deviceToken string) (*LoginOutput, error) {
+	trusted := user.HasMFA && s.isTrustedDevice(ctx, user.ID, deviceToken)
+
+	if user.HasMFA && !trusted {
+		factors, err := s.repoDB.GetMFAFactorByUserID(ctx, user.ID, true)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo get verified mfa factor", "user_id", user.ID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+
 		cToken := s.oid.Generate()
 
 		cTokenHash, err := s.hmac.Hash(cToken)
@@ -61,12 +148,19 @@ func (s *Usecase) Login(ctx context.Context, in LoginInput) (*LoginOutput, error
 			return nil, goerror.NewServer(err)
 		}
 
+		cMeta, err := entity.EncodeMetadata(&entity.MFALoginMetadata{PasswordResetRequired: user.MustResetPassword})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to encode mfa login metadata", "user_id", user.ID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+
 		if err := s.repoDB.CreateChallenge(ctx, entity.Challenge{
 			ID:        s.uid.Generate(),
 			UserID:    user.ID,
 			Token:     string(cTokenHash),
 			Purpose:   entity.ChallengePurposeMFALogin,
 			ExpiresAt: s.clock.Now().Add(s.cfg.GetMinute("modules.identity.mfa_login_ttl_minutes")),
+			Metadata:  cMeta,
 		}); err != nil {
 			slog.ErrorContext(ctx, "failed to repo create challange", "user_id", user.ID, "error", err)
 			return nil, goerror.NewServer(err)
@@ -75,11 +169,21 @@ func (s *Usecase) Login(ctx context.Context, in LoginInput) (*LoginOutput, error
 		return &LoginOutput{
 			MfaRequired:      true,
 			ChallengeToken:   cToken,
-			AvailableMethods: []string{entity.MFATypeTOTP.String(), entity.MFATypeBackupCode.String()},
+			AvailableMethods: mfaMethods(factors),
 		}, nil
 	}
 
-	acToken, err := s.jwt.Generate(user.ID, user.Email)
+	if user.MustResetPassword {
+		return s.requirePasswordReset(ctx, user.ID)
+	}
+
+	if user.MFARequired && !user.HasMFA {
+		return s.requireMFASetup(ctx, user)
+	}
+
+	ttl := s.tokenTTLFor(ctx, user.ID, meta.Client)
+
+	acToken, err := s.jwt.Generate(jwt.WithAuthTime(jwt.WithTTL(ctx, ttl.access), s.clock.Now()), user.ID, user.Email)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to generate access jwt token", "user_id", user.ID, "error", err)
 		return nil, goerror.NewServer(err)
@@ -96,14 +200,94 @@ func (s *Usecase) Login(ctx context.Context, in LoginInput) (*LoginOutput, error
 		ID:        s.uid.Generate(),
 		UserID:    user.ID,
 		Token:     string(refTokenHash),
-		ExpiresAt: s.clock.Now().Add(s.cfg.GetDay("modules.identity.refresh_token_ttl_days")),
+		ExpiresAt: s.clock.Now().Add(ttl.refresh),
+		Metadata:  meta,
 	}); err != nil {
 		slog.ErrorContext(ctx, "failed to repo create refresh token user", "user_id", user.ID, "error", err)
 		return nil, goerror.NewServer(err)
 	}
 
+	s.enforceSessionLimit(ctx, user.ID)
+	s.detectNewSignIn(ctx, user.ID, meta)
+	audit := map[string]any{"client": meta.Client}
+	if trusted {
+		audit["trusted_device"] = true
+	}
+	s.recordAudit(ctx, entity.AuditActionAuthLogin, user.ID, user.ID, audit)
+	s.recordLoginEvent(ctx, user.ID, method, "", meta)
+
 	return &LoginOutput{
 		AccessToken:  acToken,
 		RefreshToken: refToken,
 	}, nil
 }
+
+// requirePasswordReset stops a login whose password an administrator wants replaced and
+// hands out a password reset token instead of the session tokens. The user must have passed
+// every factor of the login first: the token lets them set a password that ends every session.
+func (s *Usecase) requirePasswordReset(ctx context.Context, userID int64) (*LoginOutput, error) {
+	cToken, err := s.createLoginChallenge(ctx, userID, entity.ChallengePurposePasswordForgotReset,
+		s.cfg.GetHour("modules.identity.password_reset_ttl_hours"))
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordAudit(ctx, entity.AuditActionAuthLoginFailed, userID, userID, map[string]any{"reason": "password_reset_required"})
+
+	return &LoginOutput{
+		PasswordResetRequired: true,
+		ChallengeToken:        cToken,
+	}, nil
+}
+
+// requireMFASetup stops a login of a user an administrator requires to use MFA and who has
+// no factor yet, handing out a challenge that LoginMFASetup completes.
+func (s *Usecase) requireMFASetup(ctx context.Context, user *entity.UserLoginInfo) (*LoginOutput, error) {
+	cToken, err := s.createLoginChallenge(ctx, user.ID, entity.ChallengePurposeMFAEnroll,
+		s.cfg.GetMinute("modules.identity.mfa_setup_confirm_ttl_minutes"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginOutput{
+		MfaSetupRequired: true,
+		ChallengeToken:   cToken,
+	}, nil
+}
+
+// createLoginChallenge stores a challenge of purpose p for the user, valid for ttl, and
+// returns its token.
+func (s *Usecase) createLoginChallenge(ctx context.Context, userID int64, p entity.ChallengePurpose, ttl time.Duration) (string, error) {
+	cToken := s.oid.Generate()
+
+	cTokenHash, err := s.hmac.Hash(cToken)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token challange", "error", err)
+		return "", goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.CreateChallenge(ctx, entity.Challenge{
+		ID:        s.uid.Generate(),
+		UserID:    userID,
+		Token:     string(cTokenHash),
+		Purpose:   p,
+		ExpiresAt: s.clock.Now().Add(ttl),
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create challange", "user_id", userID, "error", err)
+		return "", goerror.NewServer(err)
+	}
+
+	return cToken, nil
+}
+
+// mfaMethods lists the second factors a login challenge can be completed with, in the
+// order clients should offer them.
+func mfaMethods(factors []entity.MFAFactor) []string {
+	methods := make([]string, 0, len(factors))
+	for _, t := range []entity.MFAType{entity.MFATypeTOTP, entity.MFATypeSMS, entity.MFATypeBackupCode} {
+		if findMFAFactor(factors, t) != nil {
+			methods = append(methods, t.String())
+		}
+	}
+	return methods
+}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 	"github.com/shandysiswandi/gobite/internal/pkg/mfa"
 )
 
@@ -15,11 +17,25 @@ type Login2FAInput struct {
 	ChallengeToken string         `validate:"required"`
 	Method         entity.MFAType `validate:"required"`
 	Code           string         `validate:"required"`
+	IP             string
+	UserAgent      string
+	ClientType     string
+	DeviceName     string
+	// RememberDevice asks for a device token that skips the MFA challenge on later logins.
+	RememberDevice bool
 }
 
 type Login2FAOutput struct {
+	// PasswordResetRequired means an administrator requires a new password: ChallengeToken
+	// is a password reset token to use with PasswordReset, and no tokens are issued.
+	PasswordResetRequired bool
+	ChallengeToken        string
+	//
 	AccessToken  string
 	RefreshToken string
+	// DeviceToken is set when the device was remembered, valid until DeviceExpiresAt.
+	DeviceToken     string
+	DeviceExpiresAt time.Time
 }
 
 func (s *Usecase) Login2FA(ctx context.Context, in Login2FAInput) (*Login2FAOutput, error) {
@@ -32,13 +48,18 @@ func (s *Usecase) Login2FA(ctx context.Context, in Login2FAInput) (*Login2FAOutp
 		return nil, goerror.NewInvalidInput(err)
 	}
 
-	if in.Method == entity.MFATypeUnknown || in.Method == entity.MFATypeSMS {
+	if err := s.checkLoginThrottle(ctx, in.IP, ""); err != nil {
+		s.recordLoginEvent(ctx, 0, entity.LoginMethodMFA, "throttled", sessionMetadata(in.IP, in.UserAgent, in.ClientType, in.DeviceName))
+		return nil, err
+	}
+
+	if in.Method == entity.MFATypeUnknown {
 		slog.WarnContext(ctx, "method not supported", "method", in.Method.String())
 		return nil, goerror.NewBusiness("method not supported", goerror.CodeUnauthorized)
 	}
 
-	if in.Method == entity.MFATypeTOTP && !s.isValidTOTPCode(in.Code) {
-		slog.WarnContext(ctx, "totp code is not valid", "code", in.Code)
+	if (in.Method == entity.MFATypeTOTP || in.Method == entity.MFATypeSMS) && !s.isValidTOTPCode(in.Code) {
+		slog.WarnContext(ctx, "otp code is not valid", "method", in.Method.String(), "code", in.Code)
 		return nil, goerror.NewBusiness("invalid challenge session or code", goerror.CodeUnauthorized)
 	}
 
@@ -48,6 +69,13 @@ func (s *Usecase) Login2FA(ctx context.Context, in Login2FAInput) (*Login2FAOutp
 	}
 
 	if err := s.ensureUserStatusAllowed(ctx, cu.UserID, cu.UserStatus); err != nil {
+		s.recordLoginEvent(ctx, cu.UserID, entity.LoginMethodMFA, "account_blocked", sessionMetadata(in.IP, in.UserAgent, in.ClientType, in.DeviceName))
+		return nil, err
+	}
+
+	throttleKey := loginThrottleKey(cu.UserID)
+	if err := s.checkLoginThrottle(ctx, "", throttleKey); err != nil {
+		s.recordLoginEvent(ctx, cu.UserID, entity.LoginMethodMFA, "account_locked", sessionMetadata(in.IP, in.UserAgent, in.ClientType, in.DeviceName))
 		return nil, err
 	}
 
@@ -56,19 +84,53 @@ func (s *Usecase) Login2FA(ctx context.Context, in Login2FAInput) (*Login2FAOutp
 		return nil, err
 	}
 
+	var verifyErr error
 	if in.Method == entity.MFATypeTOTP {
-		if err := s.verifyTOTP(ctx, cu.UserID, mfaFacs, in.Code); err != nil {
-			return nil, err
+		verifyErr = s.verifyTOTP(ctx, cu.UserID, mfaFacs, in.Code)
+	}
+	if in.Method == entity.MFATypeSMS {
+		verifyErr = s.verifySMS(ctx, cu, mfaFacs, in.Code)
+	}
+	if in.Method == entity.MFATypeBackupCode {
+		verifyErr = s.verifyBackupCode(ctx, cu.UserID, mfaFacs, in.Code)
+	}
+	if verifyErr != nil {
+		var gErr *goerror.Error
+		if errors.As(verifyErr, &gErr) && gErr.Code() == goerror.CodeUnauthorized {
+			s.recordLoginFailure(ctx, in.IP, throttleKey)
+			s.recordAudit(ctx, entity.AuditActionAuthLoginFailed, cu.UserID, cu.UserID, map[string]any{
+				"reason": "invalid_mfa_code",
+				"method": in.Method.String(),
+			})
+			s.recordLoginEvent(ctx, cu.UserID, entity.LoginMethodMFA, "invalid_mfa_code", sessionMetadata(in.IP, in.UserAgent, in.ClientType, in.DeviceName))
 		}
+		return nil, verifyErr
 	}
 
-	if in.Method == entity.MFATypeBackupCode {
-		if err := s.verifyBackupCode(ctx, cu.UserID, mfaFacs, in.Code); err != nil {
-			return nil, err
+	s.resetLoginFailures(ctx, throttleKey)
+	s.cancelMFARecovery(ctx, cu.UserID)
+
+	if cMeta, err := entity.DecodeMetadata[entity.MFALoginMetadata](cu.ChallengeMetadata); err == nil && cMeta.PasswordResetRequired {
+		return s.requirePasswordResetAfterMFA(ctx, cu)
+	}
+
+	meta := sessionMetadata(in.IP, in.UserAgent, in.ClientType, in.DeviceName)
+
+	out, err := s.issueLoginTokens(ctx, cu, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.RememberDevice && s.cfg.GetBool("modules.identity.trusted_device.enabled") {
+		// the login already succeeded, so a device that cannot be remembered only means
+		// the next login asks for MFA again
+		out.DeviceToken, out.DeviceExpiresAt, err = s.rememberDevice(ctx, cu.UserID, meta)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to remember trusted device", "user_id", cu.UserID, "error", err)
 		}
 	}
 
-	return s.issueLoginTokens(ctx, cu)
+	return out, nil
 }
 
 func (s *Usecase) isValidTOTPCode(code string) bool {
@@ -120,31 +182,39 @@ func (s *Usecase) loadVerifiedFactors(ctx context.Context, userID int64) ([]enti
 	return mfaFacs, nil
 }
 
+// verifyTOTP accepts code when it matches any TOTP factor of the user, so every enrolled
+// authenticator app can sign in. Only the factor that matched has its last_used_at updated.
 func (s *Usecase) verifyTOTP(ctx context.Context, userID int64, factors []entity.MFAFactor, code string) error {
 	var factor *entity.MFAFactor
+	found := false
 	for i := range factors {
-		if factors[i].Type == entity.MFATypeTOTP {
+		if factors[i].Type != entity.MFATypeTOTP {
+			continue
+		}
+		found = true
+
+		secretBytes, err := s.mfaEncryptor.Decrypt(factors[i].Secret, mfa.Scope{
+			UserID:  userID,
+			Purpose: mfa.PurposeOTPSeed,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to decrypt totp secret", "user_id", userID, "mfa_id", factors[i].ID, "error", err)
+			return goerror.NewServer(err)
+		}
+
+		if s.totp.Validate(code, string(secretBytes), s.clock.Now()) {
 			factor = &factors[i]
 			break
 		}
 	}
 
-	if factor == nil {
+	if !found {
 		slog.WarnContext(ctx, "mfa factor for totp not found", "user_id", userID)
 		return goerror.NewBusiness("invalid challenge session or code", goerror.CodeUnauthorized)
 	}
 
-	secretBytes, err := s.mfaEncryptor.Decrypt(factor.Secret, mfa.Scope{
-		UserID:  userID,
-		Purpose: mfa.PurposeOTPSeed,
-	})
-	if err != nil {
-		slog.ErrorContext(ctx, "failed to decrypt totp secret", "user_id", userID, "mfa_id", factor.ID, "error", err)
-		return goerror.NewServer(err)
-	}
-
-	if !s.totp.Validate(code, string(secretBytes), s.clock.Now()) {
-		slog.WarnContext(ctx, "invalid totp code", "user_id", userID, "mfa_id", factor.ID)
+	if factor == nil {
+		slog.WarnContext(ctx, "invalid totp code", "user_id", userID)
 		return goerror.NewBusiness("invalid challenge session or code", goerror.CodeUnauthorized)
 	}
 
@@ -207,8 +277,26 @@ func (s *Usecase) verifyBackupCode(ctx context.Context, userID int64, factors []
 	return nil
 }
 
-func (s *Usecase) issueLoginTokens(ctx context.Context, cu *entity.ChallengeUser) (*Login2FAOutput, error) {
-	acToken, err := s.jwt.Generate(cu.UserID, cu.UserEmail)
+// requirePasswordResetAfterMFA ends an MFA login challenge of a user an administrator requires
+// a new password from with a password reset token, using the challenge up.
+func (s *Usecase) requirePasswordResetAfterMFA(ctx context.Context, cu *entity.ChallengeUser) (*Login2FAOutput, error) {
+	if err := s.repoDB.DeleteChallenge(ctx, cu.ChallengeID); err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete challenge", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	out, err := s.requirePasswordReset(ctx, cu.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Login2FAOutput{PasswordResetRequired: true, ChallengeToken: out.ChallengeToken}, nil
+}
+
+func (s *Usecase) issueLoginTokens(ctx context.Context, cu *entity.ChallengeUser, meta entity.SessionMetadata) (*Login2FAOutput, error) {
+	ttl := s.tokenTTLFor(ctx, cu.UserID, meta.Client)
+
+	acToken, err := s.jwt.Generate(jwt.WithAuthTime(jwt.WithTTL(ctx, ttl.access), s.clock.Now()), cu.UserID, cu.UserEmail)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to generate access jwt token", "user_id", cu.UserID, "error", err)
 		return nil, goerror.NewServer(err)
@@ -225,14 +313,31 @@ func (s *Usecase) issueLoginTokens(ctx context.Context, cu *entity.ChallengeUser
 		ID:        s.uid.Generate(),
 		UserID:    cu.UserID,
 		Token:     string(refTokenHash),
-		ExpiresAt: s.clock.Now().Add(s.cfg.GetDay("modules.identity.refresh_token_ttl_days")),
+		ExpiresAt: s.clock.Now().Add(ttl.refresh),
+		Metadata:  meta,
 	}
 
-	if err := s.repoDB.NewRefreshToken(ctx, refresh, cu.ChallengeID); err != nil {
+	// the challenge is used up by the same transaction that creates the session
+	err = s.repoDB.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repoDB.CreateRefreshToken(ctx, refresh); err != nil {
+			return err
+		}
+
+		return s.repoDB.DeleteChallenge(ctx, cu.ChallengeID)
+	})
+	if err != nil {
 		slog.ErrorContext(ctx, "failed to repo new refresh token user", "user_id", cu.UserID, "error", err)
 		return nil, goerror.NewServer(err)
 	}
 
+	s.enforceSessionLimit(ctx, cu.UserID)
+	s.detectNewSignIn(ctx, cu.UserID, meta)
+	s.recordAudit(ctx, entity.AuditActionAuthLogin, cu.UserID, cu.UserID, map[string]any{
+		"client": meta.Client,
+		"mfa":    true,
+	})
+	s.recordLoginEvent(ctx, cu.UserID, entity.LoginMethodMFA, "", meta)
+
 	return &Login2FAOutput{
 		AccessToken:  acToken,
 		RefreshToken: refToken,
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+const (
+	// loginHistoryDefaultLimit is the page size of ProfileLogins when none is given.
+	loginHistoryDefaultLimit = 20
+	// loginHistoryMaxLimit caps the page size of ProfileLogins.
+	loginHistoryMaxLimit = 100
+)
+
+type (
+	ProfileLoginsInput struct {
+		Limit    int32 `validate:"gte=0,lte=100"`
+		BeforeID int64 `validate:"gte=0"`
+	}
+
+	ProfileLoginsOutput struct {
+		Logins []entity.LoginEvent
+		// NextBeforeID fetches the next (older) page; 0 when this page is the last one.
+		NextBeforeID int64
+	}
+)
+
+// ProfileLogins returns the sign-in attempts on the authenticated user's account, newest
+// first, so they can spot activity they do not recognise.
+func (s *Usecase) ProfileLogins(ctx context.Context, in ProfileLoginsInput) (*ProfileLoginsOutput, error) {
+	ctx, span := s.startSpan(ctx, "ProfileLogins")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	limit := in.Limit
+	if limit == 0 {
+		limit = loginHistoryDefaultLimit
+	}
+
+	// one extra row tells whether another page follows
+	events, err := s.repoDB.GetLoginEvents(ctx, clm.UserID, in.BeforeID, min(limit, loginHistoryMaxLimit)+1)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get login events", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	out := &ProfileLoginsOutput{Logins: events}
+	if len(events) > int(limit) {
+		out.Logins = events[:limit]
+		out.NextBeforeID = out.Logins[limit-1].ID
+	}
+
+	return out, nil
+}
+
+type EnrichLoginEventInput struct {
+	LoginEventID int64 `validate:"required,gt=0"`
+	IP           string
+}
+
+// EnrichLoginEvent fills in the location of a recorded sign-in from its IP. A failed lookup
+// is returned so the message is delivered again; addresses without a public location are
+// left empty.
+func (s *Usecase) EnrichLoginEvent(ctx context.Context, in EnrichLoginEventInput) error {
+	ctx, span := s.startSpan(ctx, "EnrichLoginEvent")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	if !s.repoGeoIP.Enabled() || in.IP == "" {
+		return nil
+	}
+
+	loc, err := s.repoGeoIP.Lookup(ctx, in.IP)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to repo lookup login ip location", "login_event_id", in.LoginEventID, "error", err)
+		return goerror.NewServer(err)
+	}
+	if loc == nil {
+		return nil
+	}
+
+	if err := s.repoDB.UpdateLoginEventLocation(ctx, in.LoginEventID, *loc); err != nil {
+		slog.ErrorContext(ctx, "failed to repo update login event location", "login_event_id", in.LoginEventID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	return nil
+}
+
+// recordLoginEvent adds a sign-in attempt to the user's login history and queues the
+// location lookup. meta is the session metadata of the attempt. Attempts that never reached
+// an account, such as unknown accounts or throttled IPs, are kept under userID 0 without a
+// lookup, so credential stuffing cannot use up the lookup quota. Like recordAudit, a failure
+// is logged instead of failing the login.
+func (s *Usecase) recordLoginEvent(ctx context.Context, userID int64, method entity.LoginMethod, failureReason string, meta entity.SessionMetadata) {
+	if !s.cfg.GetBool("modules.identity.login_history.enabled") {
+		return
+	}
+
+	ip := meta.IP
+
+	ev := entity.LoginEvent{
+		ID:            s.uid.Generate(),
+		UserID:        userID,
+		Success:       failureReason == "",
+		Method:        method,
+		FailureReason: failureReason,
+		IP:            ip,
+		UserAgent:     meta.UserAgent,
+	}
+
+	if err := s.repoDB.CreateLoginEvent(ctx, ev); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create login event", "user_id", userID, "error", err)
+		return
+	}
+
+	if !s.repoGeoIP.Enabled() || ip == "" || userID == 0 {
+		return
+	}
+
+	if err := s.repoMessaging.PublishLoginRecorded(ctx, contracts.LoginRecorded{
+		LoginEventID: ev.ID,
+		UserID:       userID,
+		IP:           ip,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to publish login recorded", "user_id", userID, "login_event_id", ev.ID, "error", err)
+	}
+}
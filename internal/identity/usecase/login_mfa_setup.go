@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/mfa"
+)
+
+type LoginMFASetupInput struct {
+	ChallengeToken string `validate:"required"`
+	FriendlyName   string `validate:"required,min=2,max=100"`
+}
+
+type LoginMFASetupOutput struct {
+	Key string
+	URI string
+}
+
+type LoginMFASetupConfirmInput struct {
+	ChallengeToken string `validate:"required"`
+	Code           string `validate:"required,len=6,numeric"`
+	IP             string
+	UserAgent      string
+	ClientType     string
+	DeviceName     string
+}
+
+// LoginMFASetup starts the TOTP enrollment of a login held by MfaSetupRequired. The secret is
+// kept on the same challenge, so LoginMFASetupConfirm takes the same challenge token; calling
+// it again replaces the secret.
+func (s *Usecase) LoginMFASetup(ctx context.Context, in LoginMFASetupInput) (*LoginMFASetupOutput, error) {
+	ctx, span := s.startSpan(ctx, "LoginMFASetup")
+	defer span.End()
+
+	in.FriendlyName = strings.TrimSpace(in.FriendlyName)
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	cu, err := s.loadMFAEnrollChallenge(ctx, in.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureTOTPFactorSlot(ctx, cu.UserID, in.FriendlyName); err != nil {
+		return nil, err
+	}
+
+	secret, uri, err := s.totp.Generate(cu.UserEmail)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate totp secret", "user_id", cu.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	encryptedSecret, err := s.mfaEncryptor.Encrypt([]byte(secret), mfa.Scope{
+		UserID:  cu.UserID,
+		Purpose: mfa.PurposeOTPSeed,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encrypt totp secret", "user_id", cu.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	meta, err := entity.EncodeMetadata(&entity.TOTPSetupMetadata{
+		Secret:       base64.StdEncoding.EncodeToString(encryptedSecret),
+		FriendlyName: in.FriendlyName,
+		KeyVersion:   1,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode totp setup metadata", "user_id", cu.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.UpdateChallengeMetadata(ctx, cu.ChallengeID, meta); err != nil {
+		slog.ErrorContext(ctx, "failed to repo update challenge metadata", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &LoginMFASetupOutput{
+		Key: secret,
+		URI: uri,
+	}, nil
+}
+
+// LoginMFASetupConfirm enrolls the TOTP factor started by LoginMFASetup once code matches it,
+// then finishes the held login by issuing the access and refresh tokens.
+func (s *Usecase) LoginMFASetupConfirm(ctx context.Context, in LoginMFASetupConfirmInput) (*LoginOutput, error) {
+	ctx, span := s.startSpan(ctx, "LoginMFASetupConfirm")
+	defer span.End()
+
+	in.Code = strings.TrimSpace(in.Code)
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if err := s.checkLoginThrottle(ctx, in.IP, ""); err != nil {
+		return nil, err
+	}
+
+	cu, err := s.loadMFAEnrollChallenge(ctx, in.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := entity.DecodeMetadata[entity.TOTPSetupMetadata](cu.ChallengeMetadata)
+	if err != nil {
+		slog.WarnContext(ctx, "mfa enroll challenge has no totp setup", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return nil, goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
+	}
+
+	if err := s.ensureTOTPFactorSlot(ctx, cu.UserID, meta.FriendlyName); err != nil {
+		return nil, err
+	}
+
+	secretCiphertext, err := s.decodeTOTPSecret(ctx, cu, meta.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	secretBytes, err := s.decryptTOTPSecret(ctx, cu, secretCiphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.totp.Validate(in.Code, string(secretBytes), s.clock.Now()) {
+		slog.WarnContext(ctx, "invalid totp code", "user_id", cu.UserID, "challenge_id", cu.ChallengeID)
+		s.recordLoginFailure(ctx, in.IP, "")
+		return nil, goerror.NewBusiness("invalid code session", goerror.CodeUnauthorized)
+	}
+
+	factorTotp := s.buildTOTPFacts(cu, strings.TrimSpace(meta.FriendlyName), meta.KeyVersion, secretCiphertext)
+
+	// the factor takes the challenge with it, so the held login cannot be replayed
+	if err := s.repoDB.NewMFAFactor(ctx, factorTotp, cu.ChallengeID); err != nil {
+		slog.ErrorContext(ctx, "failed to repo new mfa factor totp", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionMFATOTPEnable, cu.UserID, cu.UserID, map[string]any{"factor_id": factorTotp.ID})
+
+	out, err := s.issueLoginTokens(ctx, cu, sessionMetadata(in.IP, in.UserAgent, in.ClientType, in.DeviceName))
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginOutput{
+		AccessToken:  out.AccessToken,
+		RefreshToken: out.RefreshToken,
+	}, nil
+}
+
+func (s *Usecase) loadMFAEnrollChallenge(ctx context.Context, token string) (*entity.ChallengeUser, error) {
+	cTokenHash, err := s.hmac.Hash(token)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token challange", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	cu, err := s.repoDB.GetChallengeUserByTokenPurpose(ctx, string(cTokenHash), entity.ChallengePurposeMFAEnroll)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "challenge user not found", "challenge_token", string(cTokenHash))
+		return nil, goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get challange user by token purpose", "challenge_token", string(cTokenHash), "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, cu.UserID, cu.UserStatus); err != nil {
+		return nil, err
+	}
+
+	return cu, nil
+}
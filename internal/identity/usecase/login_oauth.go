@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+)
+
+type (
+	OAuthAuthorizeInput struct {
+		Provider string `validate:"required,alphanum,max=32"`
+	}
+
+	OAuthAuthorizeOutput struct {
+		AuthorizationURL string
+		State            string
+		// Binding must be kept by the browser, out of reach of scripts, and handed back
+		// with the callback; it is only good for BindingTTL.
+		Binding    string
+		BindingTTL time.Duration
+	}
+
+	LoginOAuthInput struct {
+		Provider   string `validate:"required,alphanum,max=32"`
+		Code       string `validate:"required"`
+		State      string `validate:"required"`
+		Binding    string
+		IP         string
+		UserAgent  string
+		ClientType string
+		DeviceName string
+	}
+)
+
+// OAuthAuthorize starts a sign-in with an external identity provider, returning the
+// consent page URL and the binding the callback has to come back with.
+func (s *Usecase) OAuthAuthorize(ctx context.Context, in OAuthAuthorizeInput) (*OAuthAuthorizeOutput, error) {
+	ctx, span := s.startSpan(ctx, "OAuthAuthorize")
+	defer span.End()
+
+	in.Provider = strings.ToLower(strings.TrimSpace(in.Provider))
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	fs := s.newFlowState(in.Provider, "")
+	binding, err := s.sealFlowState(fs)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create oauth state", "provider", in.Provider, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	authURL, err := s.repoOAuth.AuthCodeURL(ctx, in.Provider, fs.State, fs.Verifier, fs.Nonce)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "oauth provider not configured", "provider", in.Provider)
+		return nil, goerror.NewBusiness("oauth provider not supported", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo build oauth authorization url", "provider", in.Provider, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &OAuthAuthorizeOutput{
+		AuthorizationURL: authURL,
+		State:            fs.State,
+		Binding:          binding,
+		BindingTTL:       s.flowStateTTL(),
+	}, nil
+}
+
+// LoginOAuth completes a sign-in with an external identity provider. A returning
+// identity logs into its linked account; a new one is linked to the account with the
+// same verified email, or provisioned as a new active account on first login.
+func (s *Usecase) LoginOAuth(ctx context.Context, in LoginOAuthInput) (*LoginOutput, error) {
+	ctx, span := s.startSpan(ctx, "LoginOAuth")
+	defer span.End()
+
+	in.Provider = strings.ToLower(strings.TrimSpace(in.Provider))
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	fs, ok := s.openFlowState(in.Binding, in.State, in.Provider)
+	if !ok || fs.UserID != 0 {
+		slog.WarnContext(ctx, "oauth state is invalid, expired or from another browser", "provider", in.Provider)
+		return nil, goerror.NewBusiness("invalid or expired oauth state", goerror.CodeUnauthorized)
+	}
+
+	identity, err := s.repoOAuth.Exchange(ctx, in.Provider, in.Code, fs.Verifier, fs.Nonce)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "oauth provider not configured", "provider", in.Provider)
+		return nil, goerror.NewBusiness("oauth provider not supported", goerror.CodeNotFound)
+	}
+	if err != nil {
+		// a rejected or replayed code ends up here as well
+		slog.WarnContext(ctx, "failed to repo exchange oauth code", "provider", in.Provider, "error", err)
+		return nil, goerror.NewBusiness("oauth sign-in failed", goerror.CodeUnauthorized)
+	}
+
+	user, err := s.connectedUser(ctx, identity, "modules.identity.oauth")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, user.ID, user.Status); err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, entity.LoginMethodOAuth, sessionMetadata(in.IP, in.UserAgent, in.ClientType, in.DeviceName), "")
+}
+
+// connectedUser returns the account an external identity signs in to, linking or
+// provisioning one as the link_by_email and auto_provision settings under cfgPrefix allow.
+func (s *Usecase) connectedUser(ctx context.Context, identity *entity.OAuthIdentity, cfgPrefix string) (*entity.UserLoginInfo, error) {
+	user, err := s.repoDB.GetUserLoginInfoByConnection(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, goerror.ErrNotFound) {
+		slog.ErrorContext(ctx, "failed to repo get user by connection", "provider", identity.Provider, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	// linking or provisioning by email is only safe when the provider vouches for the address
+	if identity.Email == "" || !identity.EmailVerified {
+		slog.WarnContext(ctx, "external identity has no verified email", "provider", identity.Provider, "subject", identity.Subject)
+		return nil, goerror.NewBusiness("a verified email is required from the identity provider", goerror.CodeForbidden)
+	}
+
+	conn := entity.UserConnection{
+		ID:             s.uid.Generate(),
+		Provider:       identity.Provider,
+		ProviderUserID: identity.Subject,
+	}
+
+	user, err = s.getUserLoginInfo(ctx, identity.Email)
+	if err == nil {
+		if !s.cfg.GetBool(cfgPrefix + ".link_by_email") {
+			slog.WarnContext(ctx, "external identity matches an unlinked account", "user_id", user.ID, "provider", identity.Provider)
+			return nil, goerror.NewBusiness("account already exists, sign in with your password", goerror.CodeConflict)
+		}
+
+		conn.UserID = user.ID
+		if err := s.repoDB.CreateUserConnection(ctx, conn); err != nil {
+			slog.ErrorContext(ctx, "failed to repo create user connection", "user_id", user.ID, "provider", identity.Provider, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+
+		slog.InfoContext(ctx, "external identity linked to existing account", "user_id", user.ID, "provider", identity.Provider)
+		return user, nil
+	}
+	if !errors.Is(err, goerror.ErrNotFound) {
+		slog.ErrorContext(ctx, "failed to repo get user by email", "email", identity.Email, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if !s.cfg.GetBool(cfgPrefix + ".auto_provision") {
+		slog.WarnContext(ctx, "external identity has no account and provisioning is disabled", "provider", identity.Provider)
+		return nil, goerror.NewBusiness("no account is registered for this identity", goerror.CodeForbidden)
+	}
+
+	return s.provisionConnectedUser(ctx, identity, conn)
+}
+
+func (s *Usecase) provisionConnectedUser(ctx context.Context, identity *entity.OAuthIdentity, conn entity.UserConnection) (*entity.UserLoginInfo, error) {
+	fullName := strings.TrimSpace(identity.FullName)
+	if fullName == "" {
+		fullName, _, _ = strings.Cut(identity.Email, "@")
+	}
+	if r := []rune(fullName); len(r) > 100 {
+		fullName = string(r[:100])
+	}
+
+	avatarURL := identity.AvatarURL
+	if avatarURL == "" {
+		avatarURL = "https://ui-avatars.com/api/?name=" + url.QueryEscape(fullName)
+	}
+
+	// the account has no usable password until the user sets one through password reset
+	hashedPassword, err := s.bcrypt.Hash(s.oid.Generate() + s.uuid.Generate())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash password", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	newUserID := s.uid.Generate()
+	newUser := entity.NewUser{
+		ID:        newUserID,
+		CreatedBy: newUserID,
+		UpdatedBy: newUserID,
+		Email:     s.normalizeEmail(identity.Email),
+		FullName:  fullName,
+		AvatarURL: avatarURL,
+		Status:    entity.UserStatusActive,
+	}
+
+	newUser.EmailHash, newUser.EmailSealedOnly, err = s.emailLookupHash(newUser.Email)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash email", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	conn.UserID = newUserID
+	if err := s.repoDB.NewConnectedUser(ctx, newUser, string(hashedPassword), conn); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create connected user", "provider", identity.Provider, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	slog.InfoContext(ctx, "user provisioned from external identity", "user_id", newUserID, "provider", identity.Provider)
+
+	return &entity.UserLoginInfo{
+		ID:     newUserID,
+		Email:  newUser.Email,
+		Status: newUser.Status,
+	}, nil
+}
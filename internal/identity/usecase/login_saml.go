@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+const samlProvider = "saml"
+
+type (
+	SAMLAuthorizeOutput struct {
+		AuthorizationURL string
+		State            string
+		// Binding must come back with the response, in the flow cookie, within BindingTTL.
+		Binding    string
+		BindingTTL time.Duration
+	}
+
+	LoginSAMLInput struct {
+		SAMLResponse string `validate:"required"`
+		RelayState   string `validate:"required"`
+		Binding      string
+		IP           string
+		UserAgent    string
+		ClientType   string
+		DeviceName   string
+	}
+)
+
+// SAMLMetadata returns the service provider metadata to register with the identity provider.
+func (s *Usecase) SAMLMetadata(ctx context.Context) ([]byte, error) {
+	ctx, span := s.startSpan(ctx, "SAMLMetadata")
+	defer span.End()
+
+	metadata, err := s.repoSAML.Metadata(ctx)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return nil, goerror.NewBusiness("saml is not configured", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo build saml metadata", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return metadata, nil
+}
+
+// SAMLAuthorize starts an SP-initiated sign-in, returning the identity provider URL. Like
+// an OAuth sign-in the flow is bound to the browser that started it, and the AuthnRequest
+// ID is derived from the verifier kept in the binding, so the response can be matched to
+// its request without storing anything.
+func (s *Usecase) SAMLAuthorize(ctx context.Context) (*SAMLAuthorizeOutput, error) {
+	ctx, span := s.startSpan(ctx, "SAMLAuthorize")
+	defer span.End()
+
+	fs := s.newFlowState(samlProvider, "")
+	binding, err := s.sealFlowState(fs)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to seal saml flow state", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	authURL, err := s.repoSAML.AuthnRequestURL(ctx, samlRequestID(fs.Verifier), fs.State)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return nil, goerror.NewBusiness("saml is not configured", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo build saml authn request url", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &SAMLAuthorizeOutput{
+		AuthorizationURL: authURL,
+		State:            fs.State,
+		Binding:          binding,
+		BindingTTL:       s.flowStateTTL(),
+	}, nil
+}
+
+// LoginSAML consumes the response the identity provider posts back. The assertion is
+// mapped to an identity through modules.identity.saml.attributes and then signs in,
+// links, or provisions an account like an OAuth login; role_rules grant roles by group.
+func (s *Usecase) LoginSAML(ctx context.Context, in LoginSAMLInput) (*LoginOutput, error) {
+	ctx, span := s.startSpan(ctx, "LoginSAML")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	fs, ok := s.openFlowState(in.Binding, in.RelayState, samlProvider)
+	if !ok || fs.UserID != 0 {
+		slog.WarnContext(ctx, "saml relay state is invalid, expired, or not bound to this browser")
+		return nil, goerror.NewBusiness("invalid or expired saml relay state", goerror.CodeUnauthorized)
+	}
+
+	assertion, err := s.parseSAMLResponse(ctx, in.SAMLResponse, fs.Verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.connectedUser(ctx, s.samlIdentity(assertion), "modules.identity.saml")
+	if err != nil {
+		return nil, err
+	}
+
+	s.applySAMLRoleRules(ctx, user.ID, s.samlAttribute(assertion, "groups"))
+
+	if err := s.ensureUserStatusAllowed(ctx, user.ID, user.Status); err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, entity.LoginMethodSAML, sessionMetadata(in.IP, in.UserAgent, in.ClientType, in.DeviceName), "")
+}
+
+// parseSAMLResponse verifies a response answering the AuthnRequest of verifier and uses up
+// its assertion, so the same assertion cannot sign in twice while it is valid.
+func (s *Usecase) parseSAMLResponse(ctx context.Context, samlResponse, verifier string) (*entity.SAMLIdentity, error) {
+	assertion, err := s.repoSAML.ParseResponse(ctx, samlResponse, samlRequestID(verifier))
+	if errors.Is(err, goerror.ErrNotFound) {
+		return nil, goerror.NewBusiness("saml is not configured", goerror.CodeNotFound)
+	}
+	if err != nil {
+		// forged or expired responses end up here as well
+		slog.WarnContext(ctx, "failed to repo parse saml response", "error", err)
+		return nil, goerror.NewBusiness("saml sign-in failed", goerror.CodeUnauthorized)
+	}
+
+	if assertion.AssertionID == "" {
+		slog.WarnContext(ctx, "saml assertion has no id")
+		return nil, goerror.NewBusiness("saml sign-in failed", goerror.CodeUnauthorized)
+	}
+
+	// an assertion without a window is kept as long as the relay state it answers lives
+	ttl := s.flowStateTTL()
+	if !assertion.ExpiresAt.IsZero() {
+		ttl = assertion.ExpiresAt.Sub(s.clock.Now())
+	}
+
+	fresh, err := s.replay.Use(ctx, "saml", assertion.AssertionID, ttl)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record saml assertion", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	if !fresh {
+		slog.WarnContext(ctx, "saml assertion was already used", "assertion_id", assertion.AssertionID)
+		return nil, goerror.NewBusiness("saml sign-in failed", goerror.CodeUnauthorized)
+	}
+
+	return assertion, nil
+}
+
+// samlRequestID turns the relay state verifier into an AuthnRequest ID; IDs must not
+// start with a digit, which a hex digest may.
+func samlRequestID(verifier string) string {
+	return "id-" + verifier
+}
+
+// samlIdentity maps an assertion to an external identity. The identity provider asserted
+// the email itself, so it counts as verified; a NameID in email format stands in when the
+// email attribute is missing.
+func (s *Usecase) samlIdentity(assertion *entity.SAMLIdentity) *entity.OAuthIdentity {
+	email := firstValue(s.samlAttribute(assertion, "email"))
+	if email == "" && strings.Contains(assertion.Subject, "@") {
+		email = assertion.Subject
+	}
+
+	return &entity.OAuthIdentity{
+		Provider:      samlProvider,
+		Subject:       assertion.Subject,
+		Email:         strings.TrimSpace(email),
+		EmailVerified: email != "",
+		FullName:      firstValue(s.samlAttribute(assertion, "full_name")),
+	}
+}
+
+// samlAttribute returns the values of the assertion attribute configured for field.
+func (s *Usecase) samlAttribute(assertion *entity.SAMLIdentity, field string) []string {
+	name := strings.TrimSpace(s.cfg.GetString("modules.identity.saml.attributes." + field))
+	if name == "" {
+		return nil
+	}
+	return assertion.Attributes[name]
+}
+
+// applySAMLRoleRules grants the roles that modules.identity.saml.role_rules maps the
+// user's groups to, as "group:role" pairs. Grants are additive and protected roles are
+// never handed out; a failed grant is logged so it does not block the sign-in.
+func (s *Usecase) applySAMLRoleRules(ctx context.Context, userID int64, groups []string) {
+	if len(groups) == 0 {
+		return
+	}
+
+	subject := strconv.FormatInt(userID, 10)
+	for _, rule := range s.cfg.GetArray("modules.identity.saml.role_rules") {
+		group, role, ok := strings.Cut(rule, ":")
+		group, role = strings.TrimSpace(group), normalizeRoleName(role)
+		if !ok || group == "" || role == "" || !slices.Contains(groups, group) {
+			continue
+		}
+		if s.isProtectedRole(role) || validateRoleName(role) != nil {
+			slog.WarnContext(ctx, "saml role rule names a role that cannot be granted", "group", group, "role", role)
+			continue
+		}
+
+		added, err := s.enforcer.AddGroupingPolicy(subject, role)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to assign role", "role", role, "user_id", userID, "error", err)
+			continue
+		}
+		if !added {
+			continue
+		}
+
+		s.auditRoleChange(ctx, userID, userID, entity.AuditActionRoleMemberAdd, valueobject.JSONMap{
+			"role":   role,
+			"group":  group,
+			"source": samlProvider,
+		})
+	}
+}
+
+func firstValue(values []string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
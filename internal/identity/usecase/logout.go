@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 )
@@ -21,6 +22,8 @@ func (s *Usecase) Logout(ctx context.Context, in LogoutInput) error {
 		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
 	}
 
+	s.revokeAccessToken(ctx, clm)
+
 	if len(in.RefreshToken) != 64 {
 		return nil
 	}
@@ -36,5 +39,7 @@ func (s *Usecase) Logout(ctx context.Context, in LogoutInput) error {
 		return goerror.NewServer(err)
 	}
 
+	s.recordAudit(ctx, entity.AuditActionAuthLogout, clm.UserID, clm.UserID, nil)
+
 	return nil
 }
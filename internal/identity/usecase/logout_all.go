@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 )
@@ -24,5 +25,9 @@ func (s *Usecase) LogoutAll(ctx context.Context, in LogoutAllInput) error {
 		return goerror.NewServer(err)
 	}
 
+	s.revokeUserAccessTokens(ctx, clm.UserID)
+
+	s.recordAudit(ctx, entity.AuditActionAuthLogoutAll, clm.UserID, clm.UserID, nil)
+
 	return nil
 }
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/pkg/validator"
+)
+
+// JWKSPath serves the public keys of asymmetrically signed tokens.
+const JWKSPath = "/.well-known/jwks.json"
+
+type (
+	MetadataOutput struct {
+		Issuer                string
+		BaseURL               string
+		JWKSURI               string
+		AccessTokenTTLSeconds int64
+		RefreshTokenTTLDays   int64
+		MFAMethods            []string
+		OAuthProviders        []string
+		PasswordPolicy        MetadataPasswordPolicy
+		Features              map[string]bool
+	}
+
+	// MetadataPasswordPolicy is the password policy new and changed passwords are checked
+	// against. BreachCheck reports whether passwords are screened against breach corpora,
+	// which this deployment does not do.
+	MetadataPasswordPolicy struct {
+		MinLength     int
+		MaxLength     int
+		RequireLower  bool
+		RequireUpper  bool
+		RequireDigit  bool
+		RequireSymbol bool
+		DisallowEmail bool
+		HistorySize   int
+		BreachCheck   bool
+	}
+)
+
+// Metadata describes how this deployment is set up, so client SDKs can configure themselves
+// without shipping per-deployment settings. It is public and exposes no secret.
+func (s *Usecase) Metadata(ctx context.Context) (*MetadataOutput, error) {
+	_, span := s.startSpan(ctx, "Metadata")
+	defer span.End()
+
+	mfaMethods := []string{entity.MFATypeTOTP.String()}
+	if s.repoSMS.Enabled() {
+		mfaMethods = append(mfaMethods, entity.MFATypeSMS.String())
+	}
+	mfaMethods = append(mfaMethods, entity.MFATypeBackupCode.String())
+
+	providers := s.repoOAuth.Providers()
+
+	baseURL := strings.TrimRight(strings.TrimSpace(s.cfg.GetString("modules.identity.metadata.base_url")), "/")
+
+	jwksURI := strings.TrimSpace(s.cfg.GetString("modules.identity.metadata.jwks_uri"))
+	if _, ok := s.jwt.(jwt.JWKSProvider); ok && jwksURI == "" {
+		jwksURI = baseURL + JWKSPath
+	}
+
+	policy := s.passwordPolicy()
+
+	return &MetadataOutput{
+		Issuer:                s.cfg.GetString("jwt.issuer"),
+		BaseURL:               baseURL,
+		JWKSURI:               jwksURI,
+		AccessTokenTTLSeconds: int64(s.cfg.GetMinute("jwt.ttl_minutes").Seconds()),
+		RefreshTokenTTLDays:   s.cfg.GetInt64("modules.identity.refresh_token_ttl_days"),
+		MFAMethods:            mfaMethods,
+		OAuthProviders:        providers,
+		PasswordPolicy: MetadataPasswordPolicy{
+			MinLength:     max(validator.PasswordMinLength, policy.MinLength),
+			MaxLength:     validator.PasswordMaxLength,
+			RequireLower:  policy.RequireLower,
+			RequireUpper:  policy.RequireUpper,
+			RequireDigit:  policy.RequireDigit,
+			RequireSymbol: policy.RequireSymbol,
+			DisallowEmail: policy.DisallowEmail,
+			HistorySize:   max(s.cfg.GetInt("modules.identity.password_policy.history_size"), 0),
+		},
+		Features: map[string]bool{
+			"oauth_login":            len(providers) > 0,
+			"sms_mfa":                s.repoSMS.Enabled(),
+			"api_keys":               true,
+			"service_accounts":       true,
+			"session_limit":          s.cfg.GetBool("modules.identity.session_limit.enabled"),
+			"sliding_refresh_tokens": s.cfg.GetBool("modules.identity.refresh_token_sliding.enabled"),
+			"token_roles_claim":      s.cfg.GetBool("modules.identity.token_claims.roles"),
+			"token_perm_hash_claim":  s.cfg.GetBool("modules.identity.token_claims.permission_hash"),
+			"notifications":          s.cfg.GetBool("modules.notification.enabled"),
+			"audit":                  s.cfg.GetBool("modules.audit.enabled"),
+			"login_history":          s.cfg.GetBool("modules.identity.login_history.enabled"),
+			"new_signin_alert":       s.cfg.GetBool("modules.identity.new_signin_alert.enabled"),
+			"captcha":                s.repoCaptcha.Enabled(),
+		},
+	}, nil
+}
+
+// JWKS returns the public keys access tokens are verified with. Tokens signed with the HS512
+// secret have none to publish.
+func (s *Usecase) JWKS(ctx context.Context) (*jwt.JWKSet, error) {
+	_, span := s.startSpan(ctx, "JWKS")
+	defer span.End()
+
+	ks, ok := s.jwt.(jwt.JWKSProvider)
+	if !ok {
+		return nil, goerror.NewBusiness("tokens are not signed with a public key", goerror.CodeNotFound)
+	}
+
+	set := ks.JWKS()
+	return &set, nil
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+type (
+	ListMFAFactorsOutput struct {
+		Factors []entity.MFAFactorInfo
+	}
+
+	RenameMFAFactorInput struct {
+		ID           int64  `validate:"required,gt=0"`
+		FriendlyName string `validate:"required,min=2,max=100"`
+	}
+
+	DeleteMFAFactorInput struct {
+		ID              int64  `validate:"required,gt=0"`
+		CurrentPassword string `validate:"required"`
+		// Method and Code answer the MFA check, required while other factors remain.
+		Method entity.MFAType
+		Code   string
+		IP     string
+	}
+)
+
+// ListMFAFactors returns every MFA factor of the authenticated user, oldest first.
+func (s *Usecase) ListMFAFactors(ctx context.Context) (*ListMFAFactorsOutput, error) {
+	ctx, span := s.startSpan(ctx, "ListMFAFactors")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	factors, err := s.repoDB.GetMFAFactorAllByUserID(ctx, clm.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get mfa factors", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &ListMFAFactorsOutput{Factors: factors}, nil
+}
+
+// RenameMFAFactor changes the friendly name of a TOTP or SMS factor of the authenticated user.
+// TOTP names stay unique per user, so the login can tell the apps apart.
+func (s *Usecase) RenameMFAFactor(ctx context.Context, in RenameMFAFactorInput) error {
+	ctx, span := s.startSpan(ctx, "RenameMFAFactor")
+	defer span.End()
+
+	in.FriendlyName = strings.TrimSpace(in.FriendlyName)
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	factor, err := s.getOwnMFAFactor(ctx, in.ID, clm.UserID)
+	if err != nil {
+		return err
+	}
+
+	if factor.Type == entity.MFATypeTOTP {
+		factors, err := s.repoDB.GetMFAFactorByUserID(ctx, clm.UserID, true)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo get verified mfa factor", "user_id", clm.UserID, "error", err)
+			return goerror.NewServer(err)
+		}
+
+		for i := range factors {
+			if factors[i].ID != factor.ID && factors[i].Type == entity.MFATypeTOTP && strings.EqualFold(factors[i].FriendlyName, in.FriendlyName) {
+				return goerror.NewBusiness("A TOTP factor with this name already exists", goerror.CodeConflict)
+			}
+		}
+	}
+
+	err = s.repoDB.UpdateMFAFactorFriendlyName(ctx, factor.ID, clm.UserID, in.FriendlyName)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("mfa factor not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo update mfa factor friendly name", "user_id", clm.UserID, "mfa_id", factor.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionMFAFactorRename, clm.UserID, clm.UserID, map[string]any{
+		"factor_id": strconv.FormatInt(factor.ID, 10),
+	})
+
+	return nil
+}
+
+// DeleteMFAFactor removes a TOTP or SMS factor of the authenticated user after checking the
+// password and, while another second factor remains, an MFA code. Removing the last one turns
+// MFA off, taking the backup codes and trusted devices with it. Backup codes are managed by
+// their own endpoint and cannot be removed here. Like any sensitive change it needs a recent
+// sign-in, and wrong passwords and codes count toward the sign-in throttle.
+func (s *Usecase) DeleteMFAFactor(ctx context.Context, in DeleteMFAFactorInput) error {
+	ctx, span := s.startSpan(ctx, "DeleteMFAFactor")
+	defer span.End()
+
+	in.Code = strings.TrimSpace(in.Code)
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	if err := s.requireRecentAuth(ctx, clm); err != nil {
+		return err
+	}
+
+	user, err := s.repoDB.GetUserCredentialInfo(ctx, clm.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user account not found", "user_id", clm.UserID)
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user credential info", "user_id", clm.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	throttleKey := loginThrottleKey(user.ID)
+	if err := s.checkLoginThrottle(ctx, in.IP, throttleKey); err != nil {
+		return err
+	}
+
+	if !s.bcrypt.Verify(user.Password, in.CurrentPassword) {
+		slog.WarnContext(ctx, "password user account not match", "user_id", user.ID)
+		s.recordLoginFailure(ctx, in.IP, throttleKey)
+		return goerror.NewBusiness("invalid password", goerror.CodeUnauthorized)
+	}
+
+	factor, err := s.getOwnMFAFactor(ctx, in.ID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.verifyFactorRemoval(ctx, user.ID, factor.ID, in.Method, in.Code); err != nil {
+		var gErr *goerror.Error
+		if errors.As(err, &gErr) && gErr.Code() == goerror.CodeUnauthorized && gErr.Reason() != reasonMFARequired {
+			s.recordLoginFailure(ctx, in.IP, throttleKey)
+		}
+		return err
+	}
+
+	s.resetLoginFailures(ctx, throttleKey)
+
+	lastFactor, err := s.repoDB.DeleteMFAFactor(ctx, factor.ID, user.ID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("mfa factor not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete mfa factor", "user_id", user.ID, "mfa_id", factor.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionMFAFactorDelete, user.ID, user.ID, map[string]any{
+		"factor_id":   strconv.FormatInt(factor.ID, 10),
+		"type":        factor.Type.String(),
+		"mfa_removed": lastFactor,
+	})
+
+	return nil
+}
+
+// verifyFactorRemoval asks for an MFA code when a second factor other than factorID remains,
+// so a stolen session and password cannot strip one factor after another. Removing the only
+// factor needs the password alone.
+func (s *Usecase) verifyFactorRemoval(ctx context.Context, userID, factorID int64, method entity.MFAType, code string) error {
+	factors, err := s.repoDB.GetMFAFactorByUserID(ctx, userID, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get verified mfa factor", "user_id", userID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	othersRemain := false
+	for i := range factors {
+		if factors[i].ID != factorID && factors[i].Type != entity.MFATypeBackupCode {
+			othersRemain = true
+			break
+		}
+	}
+	if !othersRemain {
+		return nil
+	}
+
+	_, err = s.verifyStepUpFactor(ctx, userID, method, code)
+	return err
+}
+
+// getOwnMFAFactor loads a TOTP or SMS factor of userID; other users' factors and backup
+// codes are reported as not found.
+func (s *Usecase) getOwnMFAFactor(ctx context.Context, id, userID int64) (*entity.MFAFactor, error) {
+	factor, err := s.repoDB.GetMFAFactorByID(ctx, id, userID)
+	if errors.Is(err, goerror.ErrNotFound) || (err == nil && factor.Type == entity.MFATypeBackupCode) {
+		return nil, goerror.NewBusiness("mfa factor not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get mfa factor by id", "user_id", userID, "mfa_id", id, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return factor, nil
+}
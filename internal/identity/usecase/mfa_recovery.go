@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+// MFA recovery moves through challenge purposes:
+//
+//	request  -> ChallengePurposeMFARecoveryVerify  (token emailed to the account owner)
+//	verify   -> ChallengePurposeMFARecoveryPending (password + optional backup code or known device, waiting period starts)
+//	complete -> MFA factors and backup codes removed once the waiting period has elapsed
+//
+// A successful 2FA login during the waiting period cancels the pending recovery.
+
+type (
+	MFARecoveryRequestInput struct {
+		Email string `validate:"required,email"`
+	}
+
+	MFARecoveryVerifyInput struct {
+		ChallengeToken string `validate:"required"`
+		Password       string `validate:"required"`
+		BackupCode     string
+		IP             string
+		UserAgent      string
+		ClientType     string
+	}
+
+	MFARecoveryVerifyOutput struct {
+		RecoveryToken string
+		AvailableAt   time.Time
+	}
+
+	MFARecoveryCompleteInput struct {
+		RecoveryToken string `validate:"required"`
+	}
+)
+
+func (s *Usecase) MFARecoveryRequest(ctx context.Context, in MFARecoveryRequestInput) error {
+	ctx, span := s.startSpan(ctx, "MFARecoveryRequest")
+	defer span.End()
+
+	in.Email = strings.TrimSpace(strings.ToLower(in.Email))
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	// responses never reveal whether the account exists or has MFA
+	user, err := s.getUserByEmail(ctx, in.Email, false)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "mfa recovery requested for unavailable user", "email", in.Email)
+		return nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by email", "email", in.Email, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, user.ID, user.Status); err != nil {
+		slog.WarnContext(ctx, "mfa recovery requested for ineligible user", "user_id", user.ID, "status", user.Status.String(), "error", err)
+		return nil
+	}
+
+	factors, err := s.repoDB.GetMFAFactorAllByUserID(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get mfa factors", "user_id", user.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+	if len(factors) == 0 {
+		slog.WarnContext(ctx, "mfa recovery requested for user without mfa", "user_id", user.ID)
+		return nil
+	}
+
+	cToken := s.oid.Generate()
+	cTokenHash, err := s.hmac.Hash(cToken)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token", "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.CreateChallenge(ctx, entity.Challenge{
+		ID:        s.uid.Generate(),
+		UserID:    user.ID,
+		Token:     string(cTokenHash),
+		Purpose:   entity.ChallengePurposeMFARecoveryVerify,
+		ExpiresAt: s.clock.Now().Add(s.cfg.GetHour("modules.identity.mfa_recovery.request_ttl_hours")),
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create mfa recovery challenge", "user_id", user.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.publishMFARecovery(ctx, contracts.MFARecovery{
+		UserID:         user.ID,
+		Email:          user.Email,
+		FullName:       user.FullName,
+		Stage:          contracts.MFARecoveryStageRequested,
+		ChallengeToken: cToken,
+	})
+
+	return nil
+}
+
+func (s *Usecase) MFARecoveryVerify(ctx context.Context, in MFARecoveryVerifyInput) (*MFARecoveryVerifyOutput, error) {
+	ctx, span := s.startSpan(ctx, "MFARecoveryVerify")
+	defer span.End()
+
+	in.BackupCode = strings.TrimSpace(in.BackupCode)
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	cu, err := s.loadMFARecoveryChallenge(ctx, in.ChallengeToken, entity.ChallengePurposeMFARecoveryVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := s.repoDB.GetUserCredentialInfo(ctx, cu.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user credential info", "user_id", cu.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if !s.bcrypt.Verify(cred.Password, in.Password) {
+		slog.WarnContext(ctx, "mfa recovery password not match", "user_id", cu.UserID)
+		return nil, goerror.NewBusiness("invalid recovery session or credentials", goerror.CodeUnauthorized)
+	}
+
+	signals := []string{"email", "password"}
+	wait := s.cfg.GetHour("modules.identity.mfa_recovery.wait_hours")
+
+	// a still-valid backup code is strong proof of ownership and shortens the wait
+	if in.BackupCode != "" {
+		factors, err := s.loadVerifiedFactors(ctx, cu.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.verifyBackupCode(ctx, cu.UserID, factors, in.BackupCode); err != nil {
+			return nil, err
+		}
+
+		signals = append(signals, "backup_code")
+		wait = min(wait, s.cfg.GetHour("modules.identity.mfa_recovery.wait_with_backup_code_hours"))
+	}
+
+	// a device that signed in to the account before is weaker proof, with its own shorter wait
+	if s.isKnownDevice(ctx, cu.UserID, sessionMetadata(in.IP, in.UserAgent, in.ClientType, "")) {
+		signals = append(signals, "known_device")
+		wait = min(wait, s.cfg.GetHour("modules.identity.mfa_recovery.wait_with_known_device_hours"))
+	}
+
+	rToken := s.oid.Generate()
+	rTokenHash, err := s.hmac.Hash(rToken)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	availableAt := s.clock.Now().Add(wait)
+	meta, err := entity.EncodeMetadata(&entity.MFARecoveryMetadata{
+		AvailableAt: availableAt.UTC().Truncate(time.Second),
+		Signals:     signals,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode mfa recovery metadata", "user_id", cu.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.NewMFARecoveryPending(ctx, entity.Challenge{
+		ID:        s.uid.Generate(),
+		UserID:    cu.UserID,
+		Token:     string(rTokenHash),
+		Purpose:   entity.ChallengePurposeMFARecoveryPending,
+		ExpiresAt: availableAt.Add(s.cfg.GetHour("modules.identity.mfa_recovery.complete_window_hours")),
+		Metadata:  meta,
+	}, cu.ChallengeID); err != nil {
+		slog.ErrorContext(ctx, "failed to repo new mfa recovery pending", "user_id", cu.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	s.publishMFARecoveryForUser(ctx, cu.UserID, contracts.MFARecoveryStagePending, availableAt)
+
+	return &MFARecoveryVerifyOutput{
+		RecoveryToken: rToken,
+		AvailableAt:   availableAt,
+	}, nil
+}
+
+func (s *Usecase) MFARecoveryComplete(ctx context.Context, in MFARecoveryCompleteInput) error {
+	ctx, span := s.startSpan(ctx, "MFARecoveryComplete")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	cu, err := s.loadMFARecoveryChallenge(ctx, in.RecoveryToken, entity.ChallengePurposeMFARecoveryPending)
+	if err != nil {
+		return err
+	}
+
+	recovery, err := entity.DecodeMetadata[entity.MFARecoveryMetadata](cu.ChallengeMetadata)
+	if err != nil {
+		slog.ErrorContext(ctx, "invalid mfa recovery challenge metadata", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if s.clock.Now().Before(recovery.AvailableAt) {
+		slog.WarnContext(ctx, "mfa recovery completed before waiting period", "user_id", cu.UserID, "available_at", recovery.AvailableAt)
+		return goerror.NewBusiness("recovery waiting period has not elapsed", goerror.CodeForbidden)
+	}
+
+	meta := valueobject.JSONMap{
+		"signals":      recovery.Signals,
+		"available_at": recovery.AvailableAt.UTC().Format(time.RFC3339),
+	}
+
+	if err := s.repoDB.CompleteMFARecovery(ctx, cu.UserID, cu.ChallengeID, entity.AuditLog{
+		ID:           s.uid.Generate(),
+		ActorID:      cu.UserID,
+		TargetUserID: cu.UserID,
+		Action:       entity.AuditActionUserMFARecover,
+		Metadata:     meta,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to repo complete mfa recovery", "user_id", cu.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionUserMFARecover, cu.UserID, cu.UserID, meta)
+
+	s.publishMFARecoveryForUser(ctx, cu.UserID, contracts.MFARecoveryStageCompleted, time.Time{})
+
+	return nil
+}
+
+// cancelMFARecovery drops a pending recovery once the user proves they still hold a factor.
+func (s *Usecase) cancelMFARecovery(ctx context.Context, userID int64) {
+	n, err := s.repoDB.DeleteChallengeByUserPurpose(ctx, userID, entity.ChallengePurposeMFARecoveryPending)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo cancel mfa recovery", "user_id", userID, "error", err)
+		return
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "pending mfa recovery cancelled by successful 2fa login", "user_id", userID)
+	}
+}
+
+func (s *Usecase) loadMFARecoveryChallenge(ctx context.Context, token string, p entity.ChallengePurpose) (*entity.ChallengeUser, error) {
+	cTokenHash, err := s.hmac.Hash(token)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	cu, err := s.repoDB.GetChallengeUserByTokenPurpose(ctx, string(cTokenHash), p)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "mfa recovery challenge not found", "challenge_token", string(cTokenHash))
+		return nil, goerror.NewBusiness("invalid recovery session or credentials", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get challenge user by token purpose", "challenge_token", string(cTokenHash), "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, cu.UserID, cu.UserStatus); err != nil {
+		return nil, err
+	}
+
+	return cu, nil
+}
+
+func (s *Usecase) publishMFARecoveryForUser(ctx context.Context, userID int64, stage string, availableAt time.Time) {
+	user, err := s.repoDB.GetUserByID(ctx, userID, false)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", userID, "error", err)
+		return
+	}
+
+	s.publishMFARecovery(ctx, contracts.MFARecovery{
+		UserID:      user.ID,
+		Email:       user.Email,
+		FullName:    user.FullName,
+		Stage:       stage,
+		AvailableAt: availableAt,
+	})
+}
+
+func (s *Usecase) publishMFARecovery(ctx context.Context, msg contracts.MFARecovery) {
+	if err := s.repoMessaging.PublishUserMFARecovery(ctx, msg); err != nil {
+		slog.ErrorContext(ctx, "failed to publish user mfa recovery", "user_id", msg.UserID, "stage", msg.Stage, "error", err)
+	}
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+)
+
+// newSignInLookupTimeout bounds the country lookup of a sign-in.
+const newSignInLookupTimeout = 2 * time.Second
+
+// detectNewSignIn remembers the device of a successful login and publishes NewSignIn when
+// neither the device nor, once countries are known, the country was seen before on the
+// account. The first device of an account is remembered without an alert. It runs in the
+// background after the login is answered, so the country lookup never delays it, and like
+// enforceSessionLimit it never fails the login.
+func (s *Usecase) detectNewSignIn(ctx context.Context, userID int64, meta entity.SessionMetadata) {
+	if !s.cfg.GetBool("modules.identity.new_signin_alert.enabled") {
+		return
+	}
+
+	s.goroutine.Go(context.WithoutCancel(ctx), func(ctx context.Context) error {
+		s.recordSignInDevice(ctx, userID, meta)
+		return nil
+	})
+}
+
+// deviceFingerprint hashes the client type with the browser family and OS; only those identify
+// a device, so browser updates are not new devices.
+func (s *Usecase) deviceFingerprint(meta entity.SessionMetadata) (string, error) {
+	fingerprint, err := s.hmac.Hash(meta.Client + "\n" + deviceFamily(meta.UserAgent))
+	if err != nil {
+		return "", err
+	}
+
+	return string(fingerprint), nil
+}
+
+// matchesDevice reports whether a fingerprint belongs to one of the remembered devices. Devices
+// remembered by their full user agent still match on its family.
+func matchesDevice(devices []entity.UserDevice, fingerprint, family string) bool {
+	return slices.ContainsFunc(devices, func(d entity.UserDevice) bool {
+		return d.Fingerprint == fingerprint || deviceFamily(d.UserAgent) == family
+	})
+}
+
+// isKnownDevice reports whether the device of meta already signed in to the account. Lookup
+// failures are logged and count as an unknown device.
+func (s *Usecase) isKnownDevice(ctx context.Context, userID int64, meta entity.SessionMetadata) bool {
+	if meta.UserAgent == "" {
+		return false
+	}
+
+	fingerprint, err := s.deviceFingerprint(meta)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash device fingerprint", "user_id", userID, "error", err)
+		return false
+	}
+
+	devices, err := s.repoDB.GetUserDevices(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user devices", "user_id", userID, "error", err)
+		return false
+	}
+
+	return matchesDevice(devices, fingerprint, deviceFamily(meta.UserAgent))
+}
+
+func (s *Usecase) recordSignInDevice(ctx context.Context, userID int64, meta entity.SessionMetadata) {
+	ip, userAgent := meta.IP, meta.UserAgent
+
+	family := deviceFamily(userAgent)
+	fingerprint, err := s.deviceFingerprint(meta)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash device fingerprint", "user_id", userID, "error", err)
+		return
+	}
+
+	devices, err := s.repoDB.GetUserDevices(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user devices", "user_id", userID, "error", err)
+		return
+	}
+
+	device := entity.UserDevice{
+		ID:          s.uid.Generate(),
+		UserID:      userID,
+		Fingerprint: fingerprint,
+		UserAgent:   userAgent,
+		IP:          ip,
+		Country:     s.signInCountry(ctx, ip),
+	}
+
+	if err := s.repoDB.UpsertUserDevice(ctx, device); err != nil {
+		slog.ErrorContext(ctx, "failed to repo upsert user device", "user_id", userID, "error", err)
+		return
+	}
+
+	if len(devices) == 0 {
+		return
+	}
+
+	newDevice := !matchesDevice(devices, device.Fingerprint, family)
+
+	// countries are compared only once one is known, so enabling lookups later does not
+	// alert on every device
+	var knownCountries []string
+	for _, d := range devices {
+		if d.Country != "" {
+			knownCountries = append(knownCountries, d.Country)
+		}
+	}
+	newCountry := device.Country != "" && len(knownCountries) > 0 && !slices.Contains(knownCountries, device.Country)
+
+	if !newDevice && !newCountry {
+		return
+	}
+
+	user, err := s.repoDB.GetUserByID(ctx, userID, false)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", userID, "error", err)
+		return
+	}
+
+	if err := s.repoMessaging.PublishUserNewSignIn(ctx, contracts.NewSignIn{
+		UserID:     user.ID,
+		Email:      user.Email,
+		FullName:   user.FullName,
+		IP:         ip,
+		UserAgent:  userAgent,
+		DeviceName: meta.DeviceName,
+		Country:    device.Country,
+		NewDevice:  newDevice,
+		NewCountry: newCountry,
+		SignedInAt: s.clock.Now(),
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to publish user new sign in", "user_id", userID, "error", err)
+	}
+}
+
+// signInCountry looks up the country of ip, returning an empty string when lookups are off
+// or fail.
+func (s *Usecase) signInCountry(ctx context.Context, ip string) string {
+	if !s.repoGeoIP.Enabled() || ip == "" {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, newSignInLookupTimeout)
+	defer cancel()
+
+	loc, err := s.repoGeoIP.Lookup(ctx, ip)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to repo lookup sign in country", "error", err)
+		return ""
+	}
+	if loc == nil {
+		return ""
+	}
+
+	return loc.Country
+}
+
+// deviceFamily reduces a user agent to its browser family and operating system, e.g.
+// "Chrome on Windows". Clients that are not browsers are named by their first product token.
+func deviceFamily(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+
+	platform := "Other"
+	switch {
+	case strings.Contains(ua, "windows"):
+		platform = "Windows"
+	case strings.Contains(ua, "android"):
+		platform = "Android"
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"), strings.Contains(ua, "ipod"):
+		platform = "iOS"
+	case strings.Contains(ua, "cros"):
+		platform = "ChromeOS"
+	case strings.Contains(ua, "mac os"), strings.Contains(ua, "macintosh"):
+		platform = "macOS"
+	case strings.Contains(ua, "linux"):
+		platform = "Linux"
+	}
+
+	// order matters: most browsers also claim to be Chrome, Safari or Mozilla
+	browser := ""
+	switch {
+	case strings.Contains(ua, "edg/"), strings.Contains(ua, "edga/"), strings.Contains(ua, "edgios/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/"), strings.Contains(ua, "opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "samsungbrowser/"):
+		browser = "Samsung Internet"
+	case strings.Contains(ua, "firefox/"), strings.Contains(ua, "fxios/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/"), strings.Contains(ua, "crios/"), strings.Contains(ua, "chromium/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	default:
+		browser, _, _ = strings.Cut(strings.TrimSpace(userAgent), "/")
+		browser, _, _ = strings.Cut(browser, " ")
+	}
+
+	return browser + " on " + platform
+}
@@ -0,0 +1,381 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/casbin/casbin/v3"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+// orgSlugPattern keeps slugs usable in URLs and subdomains.
+var orgSlugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// orgEnforceContext selects the domain-aware r2/p2/g2/m2 sections of the Casbin model.
+var orgEnforceContext = func() casbin.EnforceContext {
+	ec := casbin.NewEnforceContext("2")
+	ec.EType = "e"
+	return ec
+}()
+
+type (
+	OrgCreateInput struct {
+		Name string `validate:"required,min=2,max=100"`
+		Slug string `validate:"required,min=2,max=64"`
+	}
+
+	OrgIDInput struct {
+		ID int64 `validate:"required,gt=0"`
+	}
+
+	OrgUpdateInput struct {
+		ID   int64  `validate:"required,gt=0"`
+		Name string `validate:"required,min=2,max=100"`
+	}
+
+	OrgListOutput struct {
+		Organizations []entity.Organization
+	}
+
+	OrgTokenInput struct {
+		ID         int64 `validate:"required,gt=0"`
+		ClientType string
+	}
+
+	OrgTokenOutput struct {
+		AccessToken string
+	}
+)
+
+// OrgCreate creates an organization owned by the authenticated user. Unless
+// modules.identity.orgs.self_service is on, creating one needs the global
+// identity:management:orgs permission.
+func (s *Usecase) OrgCreate(ctx context.Context, in OrgCreateInput) (*entity.Organization, error) {
+	ctx, span := s.startSpan(ctx, "OrgCreate")
+	defer span.End()
+
+	in.Name = strings.TrimSpace(in.Name)
+	in.Slug = strings.ToLower(strings.TrimSpace(in.Slug))
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+	if !orgSlugPattern.MatchString(in.Slug) {
+		return nil, goerror.NewBusiness("slug must contain only a-z, 0-9 and '-', and start and end with a letter or digit", goerror.CodeInvalidInput)
+	}
+
+	clm, err := s.orgUserSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.cfg.GetBool("modules.identity.orgs.self_service") {
+		if _, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtOrgs, constant.PermActCreate); err != nil {
+			return nil, err
+		}
+	}
+
+	org := entity.Organization{
+		ID:        s.uid.Generate(),
+		Name:      in.Name,
+		Slug:      in.Slug,
+		CreatedBy: clm.UserID,
+		CreatedAt: s.clock.Now(),
+		UpdatedAt: s.clock.Now(),
+		Role:      entity.OrgRoleOwner,
+	}
+
+	err = s.repoDB.CreateOrganization(ctx, org)
+	if errors.Is(err, goerror.ErrConflict) {
+		return nil, goerror.NewBusiness("organization slug already taken", goerror.CodeConflict)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo create organization", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.setOrgRole(clm.UserID, org.ID, entity.OrgRoleOwner); err != nil {
+		slog.ErrorContext(ctx, "failed to assign organization owner", "org_id", org.ID, "user_id", clm.UserID, "error", err)
+		// an organization nobody can manage is not handed out
+		if derr := s.repoDB.DeleteOrganization(ctx, org.ID); derr != nil {
+			slog.ErrorContext(ctx, "failed to repo delete organization", "org_id", org.ID, "error", derr)
+		}
+		return nil, goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionOrgCreate, clm.UserID, clm.UserID, map[string]any{
+		"org_id": strconv.FormatInt(org.ID, 10),
+		"slug":   org.Slug,
+	})
+
+	return &org, nil
+}
+
+// OrgList returns the organizations the authenticated user belongs to, with their role in each.
+func (s *Usecase) OrgList(ctx context.Context) (*OrgListOutput, error) {
+	ctx, span := s.startSpan(ctx, "OrgList")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	orgs, err := s.repoDB.GetOrganizationsByUserID(ctx, clm.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get organizations by user id", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	for i := range orgs {
+		role, err := s.orgRole(clm.UserID, orgs[i].ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get organization role", "org_id", orgs[i].ID, "user_id", clm.UserID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+		orgs[i].Role = role
+	}
+
+	return &OrgListOutput{Organizations: orgs}, nil
+}
+
+func (s *Usecase) OrgDetail(ctx context.Context, in OrgIDInput) (*entity.Organization, error) {
+	ctx, span := s.startSpan(ctx, "OrgDetail")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authorizedInOrg(ctx, in.ID, constant.PermIdentityOrg, constant.PermActRead)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.getOrganization(ctx, in.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.orgRole(clm.UserID, org.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get organization role", "org_id", org.ID, "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	org.Role = role
+
+	return org, nil
+}
+
+func (s *Usecase) OrgUpdate(ctx context.Context, in OrgUpdateInput) error {
+	ctx, span := s.startSpan(ctx, "OrgUpdate")
+	defer span.End()
+
+	in.Name = strings.TrimSpace(in.Name)
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authorizedInOrg(ctx, in.ID, constant.PermIdentityOrg, constant.PermActUpdate)
+	if err != nil {
+		return err
+	}
+
+	err = s.repoDB.UpdateOrganizationName(ctx, in.ID, in.Name)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("organization not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo update organization name", "org_id", in.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionOrgUpdate, clm.UserID, clm.UserID, map[string]any{
+		"org_id": strconv.FormatInt(in.ID, 10),
+	})
+
+	return nil
+}
+
+// OrgDelete removes an organization with its memberships and org-scoped role assignments.
+// Only owners may do it.
+func (s *Usecase) OrgDelete(ctx context.Context, in OrgIDInput) error {
+	ctx, span := s.startSpan(ctx, "OrgDelete")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authorizedInOrg(ctx, in.ID, constant.PermIdentityOrg, constant.PermActDelete)
+	if err != nil {
+		return err
+	}
+
+	err = s.repoDB.DeleteOrganization(ctx, in.ID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("organization not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete organization", "org_id", in.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if _, err := s.enforcer.RemoveFilteredNamedGroupingPolicy("g2", 2, entity.OrgDomain(in.ID)); err != nil {
+		slog.ErrorContext(ctx, "failed to delete organization roles", "org_id", in.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionOrgDelete, clm.UserID, clm.UserID, map[string]any{
+		"org_id": strconv.FormatInt(in.ID, 10),
+	})
+
+	return nil
+}
+
+// OrgToken issues an access token scoped to an organization the user belongs to, carrying
+// its id in the org_id claim. The refresh token is left as it is, so clients switch again
+// after refreshing. Its lifetime is the one of the caller's client type, so switching
+// organizations does not stretch a short-lived client token.
+func (s *Usecase) OrgToken(ctx context.Context, in OrgTokenInput) (*OrgTokenOutput, error) {
+	ctx, span := s.startSpan(ctx, "OrgToken")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.orgUserSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.repoDB.IsOrganizationMember(ctx, in.ID, clm.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo check organization member", "org_id", in.ID, "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	if !member {
+		return nil, goerror.NewBusiness("not a member of this organization", goerror.CodeForbidden)
+	}
+
+	ttl := s.tokenTTLFor(ctx, clm.UserID, normalizeClientType(in.ClientType))
+
+	tokenCtx := jwt.WithOrg(jwt.WithTTL(ctx, ttl.access), in.ID)
+	if clm.AuthTime != nil {
+		tokenCtx = jwt.WithAuthTime(tokenCtx, clm.AuthTime.Time)
+	}
+
+	acToken, err := s.jwt.Generate(tokenCtx, clm.UserID, clm.UserEmail)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate access jwt token", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &OrgTokenOutput{AccessToken: acToken}, nil
+}
+
+// orgUserSession returns the claims of a signed-in user; API keys, service accounts and
+// impersonation tokens cannot own or switch organizations.
+func (s *Usecase) orgUserSession(ctx context.Context) (*jwt.Claims, error) {
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if clm.UserID == 0 || clm.APIKeyID != 0 || clm.ClientID != "" || clm.Actor != nil {
+		return nil, goerror.NewBusiness("organizations require a user session", goerror.CodeForbidden)
+	}
+
+	return clm, nil
+}
+
+// authorizedInOrg checks act on obj inside organization orgID. Members go by their org-scoped
+// role; holders of the global identity:management:orgs permission may act in every
+// organization. A token scoped to one organization cannot act in another.
+func (s *Usecase) authorizedInOrg(ctx context.Context, orgID int64, obj, act string) (*jwt.Claims, error) {
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if clm.OrgID != 0 && clm.OrgID != orgID {
+		return nil, goerror.NewBusiness("token is scoped to another organization", goerror.CodeForbidden)
+	}
+
+	if clm.UserID != 0 && clm.APIKeyID == 0 && clm.ClientID == "" {
+		ok, err := authz.EnforceInDomain(ctx, s.enforcer, s.authzShadow, orgEnforceContext,
+			strconv.FormatInt(clm.UserID, 10), entity.OrgDomain(orgID), obj, act)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to check organization authorization", "user_id", clm.UserID, "org_id", orgID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+		if ok {
+			return clm, nil
+		}
+	}
+
+	return s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtOrgs, act)
+}
+
+func (s *Usecase) getOrganization(ctx context.Context, id int64) (*entity.Organization, error) {
+	org, err := s.repoDB.GetOrganizationByID(ctx, id)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return nil, goerror.NewBusiness("organization not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get organization by id", "org_id", id, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return org, nil
+}
+
+// orgRole returns the role of userID in orgID, empty when they hold none.
+func (s *Usecase) orgRole(userID, orgID int64) (entity.OrgRole, error) {
+	rules, err := s.enforcer.GetFilteredNamedGroupingPolicy("g2", 0, strconv.FormatInt(userID, 10), "", entity.OrgDomain(orgID))
+	if err != nil {
+		return "", err
+	}
+
+	for _, rule := range rules {
+		if len(rule) < 2 {
+			continue
+		}
+		if role, ok := entity.OrgRoleFromSubject(rule[1]); ok {
+			return role, nil
+		}
+	}
+
+	return "", nil
+}
+
+// setOrgRole replaces the role of userID in orgID; a member holds one role per organization.
+func (s *Usecase) setOrgRole(userID, orgID int64, role entity.OrgRole) error {
+	sub := strconv.FormatInt(userID, 10)
+	dom := entity.OrgDomain(orgID)
+
+	if _, err := s.enforcer.RemoveFilteredNamedGroupingPolicy("g2", 0, sub, "", dom); err != nil {
+		return err
+	}
+
+	_, err := s.enforcer.AddNamedGroupingPolicy("g2", sub, role.Subject(), dom)
+	return err
+}
+
+// countOrgOwners returns how many owners orgID has.
+func (s *Usecase) countOrgOwners(orgID int64) (int, error) {
+	rules, err := s.enforcer.GetFilteredNamedGroupingPolicy("g2", 1, entity.OrgRoleOwner.Subject(), entity.OrgDomain(orgID))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(rules), nil
+}
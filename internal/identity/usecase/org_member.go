@@ -0,0 +1,438 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+const triggerKeyOrgInvite = "org_invite"
+
+type (
+	OrgMemberListOutput struct {
+		Members []entity.OrgMember
+	}
+
+	OrgMemberAddInput struct {
+		OrgID int64  `validate:"required,gt=0"`
+		Email string `validate:"required,email"`
+		Role  string `validate:"required"`
+	}
+
+	OrgInviteAcceptInput struct {
+		ChallengeToken string `validate:"required"`
+	}
+
+	OrgMemberUpdateInput struct {
+		OrgID  int64  `validate:"required,gt=0"`
+		UserID int64  `validate:"required,gt=0"`
+		Role   string `validate:"required"`
+	}
+
+	OrgMemberRemoveInput struct {
+		OrgID  int64 `validate:"required,gt=0"`
+		UserID int64 `validate:"required,gt=0"`
+	}
+)
+
+func (s *Usecase) OrgMemberList(ctx context.Context, in OrgIDInput) (*OrgMemberListOutput, error) {
+	ctx, span := s.startSpan(ctx, "OrgMemberList")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if _, err := s.authorizedInOrg(ctx, in.ID, constant.PermIdentityOrgMembers, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repoDB.GetOrganizationMembers(ctx, in.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get organization members", "org_id", in.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	for i := range members {
+		role, err := s.orgRole(members[i].UserID, in.ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get organization role", "org_id", in.ID, "user_id", members[i].UserID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+		members[i].Role = role
+	}
+
+	return &OrgMemberListOutput{Members: members}, nil
+}
+
+// OrgMemberAdd invites a user into the organization with the given role; they join once
+// they accept the emailed invitation. The answer is the same whether or not the address
+// belongs to an active account, so inviting cannot be used to find out who has one. Only
+// owners may invite an owner.
+func (s *Usecase) OrgMemberAdd(ctx context.Context, in OrgMemberAddInput) error {
+	ctx, span := s.startSpan(ctx, "OrgMemberAdd")
+	defer span.End()
+
+	in.Email = strings.ToLower(strings.TrimSpace(in.Email))
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	role, ok := entity.OrgRoleFromString(in.Role)
+	if !ok {
+		return goerror.NewBusiness("role must be one of owner, admin or member", goerror.CodeInvalidInput)
+	}
+
+	clm, err := s.authorizedInOrg(ctx, in.OrgID, constant.PermIdentityOrgMembers, constant.PermActCreate)
+	if err != nil {
+		return err
+	}
+
+	if role == entity.OrgRoleOwner {
+		if err := s.requireOrgOwner(ctx, clm, in.OrgID); err != nil {
+			return err
+		}
+	}
+
+	org, err := s.getOrganization(ctx, in.OrgID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.getUserByEmail(ctx, in.Email, false)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "organization invitation for unknown email", "org_id", in.OrgID)
+		return nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by email", "error", err)
+		return goerror.NewServer(err)
+	}
+	if user.Status != entity.UserStatusActive {
+		slog.WarnContext(ctx, "organization invitation for inactive user", "org_id", in.OrgID, "user_id", user.ID, "status", user.Status.String())
+		return nil
+	}
+
+	member, err := s.repoDB.IsOrganizationMember(ctx, in.OrgID, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo check organization member", "org_id", in.OrgID, "user_id", user.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+	if member {
+		return goerror.NewBusiness("user is already a member", goerror.CodeConflict)
+	}
+
+	meta, err := entity.EncodeMetadata(&entity.OrgInviteMetadata{
+		OrgID:     in.OrgID,
+		Role:      role.String(),
+		InvitedBy: clm.UserID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode organization invitation metadata", "error", err)
+		return goerror.NewServer(err)
+	}
+
+	cToken := s.oid.Generate()
+	cTokenHash, err := s.hmac.Hash(cToken)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token", "error", err)
+		return goerror.NewServer(err)
+	}
+
+	challenge := entity.Challenge{
+		ID:        s.uid.Generate(),
+		UserID:    user.ID,
+		Token:     string(cTokenHash),
+		Purpose:   entity.ChallengePurposeOrgInvite,
+		ExpiresAt: s.clock.Now().Add(s.cfg.GetHour("modules.identity.invite_ttl_hours")),
+		Metadata:  meta,
+	}
+	if err := s.repoDB.CreateChallenge(ctx, challenge); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create organization invitation", "org_id", in.OrgID, "user_id", user.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.repoMessaging.PublishNotificationRequested(ctx, contracts.NotificationRequested{
+		UserID:     user.ID,
+		Email:      user.Email,
+		TriggerKey: triggerKeyOrgInvite,
+		Channels:   []string{"email"},
+		Data: map[string]any{
+			"full_name":  user.FullName,
+			"org_name":   org.Name,
+			"role":       role.String(),
+			"invite_url": s.cfg.GetString("app.web") + "/orgs/invite/accept?token=" + url.QueryEscape(cToken),
+			"expires_at": challenge.ExpiresAt.Format(time.RFC3339),
+		},
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to publish organization invitation", "org_id", in.OrgID, "user_id", user.ID, "error", err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionOrgMemberInvite, clm.UserID, user.ID, map[string]any{
+		"org_id": strconv.FormatInt(in.OrgID, 10),
+		"role":   role.String(),
+	})
+
+	return nil
+}
+
+// OrgInviteAccept makes the signed-in user a member of the organization they were invited
+// into. The invitation only works for the account it was sent to.
+func (s *Usecase) OrgInviteAccept(ctx context.Context, in OrgInviteAcceptInput) (*entity.Organization, error) {
+	ctx, span := s.startSpan(ctx, "OrgInviteAccept")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.orgUserSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cTokenHash, err := s.hmac.Hash(in.ChallengeToken)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	cu, err := s.repoDB.GetChallengeUserByTokenPurpose(ctx, string(cTokenHash), entity.ChallengePurposeOrgInvite)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return nil, goerror.NewBusiness("invalid or expired invitation token", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get challenge user by token purpose", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	if cu.UserID != clm.UserID {
+		slog.WarnContext(ctx, "organization invitation used by another user", "user_id", clm.UserID, "invited_user_id", cu.UserID)
+		return nil, goerror.NewBusiness("invalid or expired invitation token", goerror.CodeNotFound)
+	}
+
+	meta, err := entity.DecodeMetadata[entity.OrgInviteMetadata](cu.ChallengeMetadata)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode organization invitation metadata", "challenge_id", cu.ChallengeID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	role, _ := entity.OrgRoleFromString(meta.Role)
+
+	org, err := s.getOrganization(ctx, meta.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repoDB.CreateOrganizationMember(ctx, meta.OrgID, clm.UserID)
+	if errors.Is(err, goerror.ErrConflict) {
+		return nil, goerror.NewBusiness("user is already a member", goerror.CodeConflict)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo create organization member", "org_id", meta.OrgID, "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.setOrgRole(clm.UserID, meta.OrgID, role); err != nil {
+		slog.ErrorContext(ctx, "failed to assign organization role", "org_id", meta.OrgID, "user_id", clm.UserID, "error", err)
+		// a member without a role would be listed but could do nothing
+		if derr := s.repoDB.DeleteOrganizationMember(ctx, meta.OrgID, clm.UserID); derr != nil {
+			slog.ErrorContext(ctx, "failed to repo delete organization member", "org_id", meta.OrgID, "user_id", clm.UserID, "error", derr)
+		}
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.DeleteChallenge(ctx, cu.ChallengeID); err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete organization invitation", "challenge_id", cu.ChallengeID, "error", err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionOrgMemberAdd, meta.InvitedBy, clm.UserID, map[string]any{
+		"org_id": strconv.FormatInt(meta.OrgID, 10),
+		"role":   role.String(),
+	})
+
+	org.Role = role
+	return org, nil
+}
+
+// OrgMemberUpdate changes the role of a member. Granting or taking away the owner role
+// needs an owner, and the last owner cannot be demoted.
+func (s *Usecase) OrgMemberUpdate(ctx context.Context, in OrgMemberUpdateInput) error {
+	ctx, span := s.startSpan(ctx, "OrgMemberUpdate")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	role, ok := entity.OrgRoleFromString(in.Role)
+	if !ok {
+		return goerror.NewBusiness("role must be one of owner, admin or member", goerror.CodeInvalidInput)
+	}
+
+	clm, err := s.authorizedInOrg(ctx, in.OrgID, constant.PermIdentityOrgMembers, constant.PermActUpdate)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.orgMemberRole(ctx, in.OrgID, in.UserID)
+	if err != nil {
+		return err
+	}
+	if current == role {
+		return nil
+	}
+
+	if current == entity.OrgRoleOwner || role == entity.OrgRoleOwner {
+		if err := s.requireOrgOwner(ctx, clm, in.OrgID); err != nil {
+			return err
+		}
+	}
+	if current == entity.OrgRoleOwner {
+		if err := s.keepLastOrgOwner(ctx, in.OrgID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.setOrgRole(in.UserID, in.OrgID, role); err != nil {
+		slog.ErrorContext(ctx, "failed to assign organization role", "org_id", in.OrgID, "user_id", in.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionOrgMemberUpdate, clm.UserID, in.UserID, map[string]any{
+		"org_id":   strconv.FormatInt(in.OrgID, 10),
+		"role":     role.String(),
+		"previous": current.String(),
+	})
+
+	return nil
+}
+
+// OrgMemberRemove takes a user out of the organization. Members may always leave on their
+// own; removing someone else needs the members delete permission, and only owners may
+// remove an owner. The last owner cannot leave.
+func (s *Usecase) OrgMemberRemove(ctx context.Context, in OrgMemberRemoveInput) error {
+	ctx, span := s.startSpan(ctx, "OrgMemberRemove")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	if clm.UserID != in.UserID || clm.APIKeyID != 0 || clm.ClientID != "" {
+		var err error
+		clm, err = s.authorizedInOrg(ctx, in.OrgID, constant.PermIdentityOrgMembers, constant.PermActDelete)
+		if err != nil {
+			return err
+		}
+	} else if clm.OrgID != 0 && clm.OrgID != in.OrgID {
+		return goerror.NewBusiness("token is scoped to another organization", goerror.CodeForbidden)
+	}
+
+	current, err := s.orgMemberRole(ctx, in.OrgID, in.UserID)
+	if err != nil {
+		return err
+	}
+
+	if current == entity.OrgRoleOwner {
+		if clm.UserID != in.UserID {
+			if err := s.requireOrgOwner(ctx, clm, in.OrgID); err != nil {
+				return err
+			}
+		}
+		if err := s.keepLastOrgOwner(ctx, in.OrgID); err != nil {
+			return err
+		}
+	}
+
+	err = s.repoDB.DeleteOrganizationMember(ctx, in.OrgID, in.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("member not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete organization member", "org_id", in.OrgID, "user_id", in.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if _, err := s.enforcer.RemoveFilteredNamedGroupingPolicy("g2", 0, strconv.FormatInt(in.UserID, 10), "", entity.OrgDomain(in.OrgID)); err != nil {
+		slog.ErrorContext(ctx, "failed to delete organization role", "org_id", in.OrgID, "user_id", in.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionOrgMemberRemove, clm.UserID, in.UserID, map[string]any{
+		"org_id": strconv.FormatInt(in.OrgID, 10),
+		"role":   current.String(),
+	})
+
+	return nil
+}
+
+// orgMemberRole returns the role of a member, failing with not found for anyone outside
+// the organization.
+func (s *Usecase) orgMemberRole(ctx context.Context, orgID, userID int64) (entity.OrgRole, error) {
+	member, err := s.repoDB.IsOrganizationMember(ctx, orgID, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo check organization member", "org_id", orgID, "user_id", userID, "error", err)
+		return "", goerror.NewServer(err)
+	}
+	if !member {
+		return "", goerror.NewBusiness("member not found", goerror.CodeNotFound)
+	}
+
+	role, err := s.orgRole(userID, orgID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get organization role", "org_id", orgID, "user_id", userID, "error", err)
+		return "", goerror.NewServer(err)
+	}
+
+	return role, nil
+}
+
+// requireOrgOwner lets through owners of orgID and holders of the global
+// identity:management:orgs update permission.
+func (s *Usecase) requireOrgOwner(ctx context.Context, clm *jwt.Claims, orgID int64) error {
+	if clm.UserID != 0 && clm.APIKeyID == 0 && clm.ClientID == "" {
+		role, err := s.orgRole(clm.UserID, orgID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get organization role", "org_id", orgID, "user_id", clm.UserID, "error", err)
+			return goerror.NewServer(err)
+		}
+		if role == entity.OrgRoleOwner {
+			return nil
+		}
+	}
+
+	if _, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtOrgs, constant.PermActUpdate); err != nil {
+		return goerror.NewBusiness("only owners can manage owners", goerror.CodeForbidden)
+	}
+
+	return nil
+}
+
+// keepLastOrgOwner refuses to take away the only owner of orgID.
+func (s *Usecase) keepLastOrgOwner(ctx context.Context, orgID int64) error {
+	owners, err := s.countOrgOwners(orgID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count organization owners", "org_id", orgID, "error", err)
+		return goerror.NewServer(err)
+	}
+	if owners <= 1 {
+		return goerror.NewBusiness("organization needs at least one owner", goerror.CodeConflict)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 )
@@ -46,16 +47,28 @@ func (s *Usecase) PasswordChange(ctx context.Context, in PasswordChangeInput) er
 		return goerror.NewBusiness("invalid password", goerror.CodeUnauthorized)
 	}
 
+	if err := s.checkPasswordPolicy("new_password", in.NewPassword, user.Email); err != nil {
+		return err
+	}
+
+	if err := s.checkPasswordReuse(ctx, "new_password", user.ID, user.Password, in.NewPassword); err != nil {
+		return err
+	}
+
 	newHash, err := s.bcrypt.Hash(in.NewPassword)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to hash new password", "user_id", user.ID, "error", err)
 		return goerror.NewServer(err)
 	}
 
-	if err := s.repoDB.UpdateUserCredential(ctx, user.ID, string(newHash)); err != nil {
+	if err := s.repoDB.UpdateUserCredential(ctx, user.ID, string(newHash), s.passwordHistoryKeep()); err != nil {
 		slog.ErrorContext(ctx, "failed to update user password", "user_id", user.ID, "error", err)
 		return goerror.NewServer(err)
 	}
 
+	s.revokeUserAccessTokens(ctx, user.ID)
+
+	s.recordAudit(ctx, entity.AuditActionPasswordChange, user.ID, user.ID, nil)
+
 	return nil
 }
@@ -6,12 +6,15 @@ import (
 	"log/slog"
 	"strings"
 
+	"github.com/shandysiswandi/gobite/internal/contracts"
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 )
 
 type PasswordForgotInput struct {
 	Email string `validate:"required,email"`
+	// CaptchaToken is the CAPTCHA response, required when password_forgot is listed in the captcha endpoints.
+	CaptchaToken string
 }
 
 func (s *Usecase) PasswordForgot(ctx context.Context, in PasswordForgotInput) error {
@@ -24,7 +27,11 @@ func (s *Usecase) PasswordForgot(ctx context.Context, in PasswordForgotInput) er
 		return goerror.NewInvalidInput(err)
 	}
 
-	user, err := s.repoDB.GetUserByEmail(ctx, in.Email, false)
+	if err := s.verifyCaptcha(ctx, CaptchaEndpointPasswordForgot, in.CaptchaToken); err != nil {
+		return err
+	}
+
+	user, err := s.getUserByEmail(ctx, in.Email, false)
 	if errors.Is(err, goerror.ErrNotFound) {
 		slog.WarnContext(ctx, "password reset requested for unavailable user", "email", in.Email)
 		return nil
@@ -57,7 +64,7 @@ func (s *Usecase) PasswordForgot(ctx context.Context, in PasswordForgotInput) er
 		return goerror.NewServer(err)
 	}
 
-	if err := s.repoMessaging.PublishUserForgotPassword(ctx, UserForgotPasswordEvent{
+	if err := s.repoMessaging.PublishUserForgotPassword(ctx, contracts.PasswordForgot{
 		UserID:         user.ID,
 		Email:          user.Email,
 		ChallengeToken: cToken,
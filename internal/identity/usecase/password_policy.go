@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/passwordpolicy"
+)
+
+func (s *Usecase) passwordPolicy() passwordpolicy.Policy {
+	return passwordpolicy.Policy{
+		MinLength:     s.cfg.GetInt("modules.identity.password_policy.min_length"),
+		RequireLower:  s.cfg.GetBool("modules.identity.password_policy.require_lower"),
+		RequireUpper:  s.cfg.GetBool("modules.identity.password_policy.require_upper"),
+		RequireDigit:  s.cfg.GetBool("modules.identity.password_policy.require_digit"),
+		RequireSymbol: s.cfg.GetBool("modules.identity.password_policy.require_symbol"),
+		DisallowEmail: s.cfg.GetBool("modules.identity.password_policy.disallow_email"),
+	}
+}
+
+// checkPasswordPolicy returns a validation error on field when password breaks the
+// configured composition rules for the account of email.
+func (s *Usecase) checkPasswordPolicy(field, password, email string) error {
+	policy := s.passwordPolicy()
+
+	err := policy.Check(password, email)
+	if errors.Is(err, passwordpolicy.ErrTooShort) {
+		return goerror.NewInvalidInput(nil, field, fmt.Sprintf("password must be at least %d characters", policy.MinLength))
+	}
+	if err != nil {
+		return goerror.NewInvalidInput(nil, field, err.Error())
+	}
+
+	return nil
+}
+
+// passwordHistoryKeep is how many replaced hashes are kept besides the current one.
+func (s *Usecase) passwordHistoryKeep() int32 {
+	keep := s.cfg.GetInt("modules.identity.password_policy.history_size") - 1
+
+	return int32(min(max(keep, 0), math.MaxInt32))
+}
+
+// checkPasswordReuse returns a validation error on field when password is the current one,
+// currentHash, or one of the replaced passwords still in the history.
+func (s *Usecase) checkPasswordReuse(ctx context.Context, field string, userID int64, currentHash, password string) error {
+	if s.cfg.GetInt("modules.identity.password_policy.history_size") <= 0 {
+		return nil
+	}
+
+	hashes := []string{currentHash}
+	if keep := s.passwordHistoryKeep(); keep > 0 {
+		history, err := s.repoDB.GetPasswordHistory(ctx, userID, keep)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo get password history", "user_id", userID, "error", err)
+			return goerror.NewServer(err)
+		}
+		hashes = append(hashes, history...)
+	}
+
+	for _, hash := range hashes {
+		if s.bcrypt.Verify(hash, password) {
+			return goerror.NewInvalidInput(nil, field, "password was used recently, choose a different one")
+		}
+	}
+
+	return nil
+}
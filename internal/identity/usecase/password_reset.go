@@ -41,16 +41,34 @@ func (s *Usecase) PasswordReset(ctx context.Context, in PasswordResetInput) erro
 		return err
 	}
 
+	cred, err := s.repoDB.GetUserCredentialInfo(ctx, cu.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user credential info", "user_id", cu.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.checkPasswordPolicy("new_password", in.NewPassword, cred.Email); err != nil {
+		return err
+	}
+
+	if err := s.checkPasswordReuse(ctx, "new_password", cu.UserID, cred.Password, in.NewPassword); err != nil {
+		return err
+	}
+
 	newHash, err := s.bcrypt.Hash(in.NewPassword)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to hash new password", "user_id", cu.UserID, "error", err)
 		return goerror.NewServer(err)
 	}
 
-	if err := s.repoDB.ResetUserPassword(ctx, cu.UserID, cu.ChallengeID, string(newHash)); err != nil {
+	if err := s.repoDB.ResetUserPassword(ctx, cu.UserID, cu.ChallengeID, string(newHash), s.passwordHistoryKeep()); err != nil {
 		slog.ErrorContext(ctx, "failed to update user password", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
 		return goerror.NewServer(err)
 	}
 
+	s.revokeUserAccessTokens(ctx, cu.UserID)
+
+	s.recordAudit(ctx, entity.AuditActionPasswordReset, cu.UserID, cu.UserID, nil)
+
 	return nil
 }
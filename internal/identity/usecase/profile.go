@@ -15,6 +15,7 @@ type ProfileOutput struct {
 	ID        int64
 	Email     string
 	FullName  string
+	Username  string
 	AvatarURL string
 	Status    string
 }
@@ -28,7 +29,7 @@ func (s *Usecase) Profile(ctx context.Context, in ProfileInput) (*ProfileOutput,
 		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
 	}
 
-	user, err := s.repoDB.GetUserByEmail(ctx, clm.UserEmail, false)
+	user, err := s.getUserByEmail(ctx, clm.UserEmail, false)
 	if errors.Is(err, goerror.ErrNotFound) {
 		slog.WarnContext(ctx, "user account not found", "email", clm.UserEmail)
 		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
@@ -42,10 +43,17 @@ func (s *Usecase) Profile(ctx context.Context, in ProfileInput) (*ProfileOutput,
 		return nil, err
 	}
 
+	username, err := s.repoDB.GetUsername(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get username", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
 	return &ProfileOutput{
 		ID:        user.ID,
 		Email:     user.Email,
 		FullName:  user.FullName,
+		Username:  username,
 		AvatarURL: user.AvatarURL,
 		Status:    user.Status.String(),
 	}, nil
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+const (
+	defaultAttributesMaxKeys       = 50
+	defaultAttributesMaxKeyLength  = 64
+	defaultAttributesMaxValueBytes = 4096
+	defaultAttributesMaxTotalBytes = 32768
+)
+
+// attributeKeyPattern allows letters, digits, "_", "-" and ".", starting with a letter or digit.
+var attributeKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+type (
+	ProfileAttributesOutput struct {
+		Attributes valueobject.JSONMap
+	}
+
+	ProfileUpdateAttributesInput struct {
+		// Attributes are merged into the stored ones; a key with a nil value is removed.
+		Attributes map[string]any `validate:"required,min=1"`
+	}
+)
+
+type attributeLimits struct {
+	maxKeys       int
+	maxKeyLength  int
+	maxValueBytes int
+	maxTotalBytes int
+}
+
+func (s *Usecase) attributeLimits() attributeLimits {
+	get := func(key string, def int) int {
+		if v := s.cfg.GetInt("modules.identity.attributes." + key); v > 0 {
+			return v
+		}
+		return def
+	}
+
+	return attributeLimits{
+		maxKeys:       get("max_keys", defaultAttributesMaxKeys),
+		maxKeyLength:  get("max_key_length", defaultAttributesMaxKeyLength),
+		maxValueBytes: get("max_value_bytes", defaultAttributesMaxValueBytes),
+		maxTotalBytes: get("max_total_bytes", defaultAttributesMaxTotalBytes),
+	}
+}
+
+// checkAttributesPatch returns a validation error on "attributes.<key>" for the first key
+// of patch, in key order, that is malformed or whose value is too large.
+func (l attributeLimits) checkAttributesPatch(patch map[string]any) error {
+	if len(patch) > l.maxKeys {
+		return goerror.NewInvalidInput(nil, "attributes", fmt.Sprintf("at most %d attributes can be changed at once", l.maxKeys))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(patch)) {
+		field := "attributes." + key
+		if len(key) > l.maxKeyLength || !attributeKeyPattern.MatchString(key) {
+			return goerror.NewInvalidInput(nil, field, fmt.Sprintf("key must be 1 to %d letters, digits, '_', '-' or '.', starting with a letter or digit", l.maxKeyLength))
+		}
+
+		if patch[key] == nil {
+			continue
+		}
+		raw, err := json.Marshal(patch[key])
+		if err != nil {
+			return goerror.NewInvalidInput(nil, field, "value must be valid JSON")
+		}
+		if len(raw) > l.maxValueBytes {
+			return goerror.NewInvalidInput(nil, field, fmt.Sprintf("value must be at most %d bytes", l.maxValueBytes))
+		}
+	}
+
+	return nil
+}
+
+// checkAttributes returns a validation error when the merged attributes exceed the key
+// count or the total size.
+func (l attributeLimits) checkAttributes(attrs valueobject.JSONMap) error {
+	if len(attrs) > l.maxKeys {
+		return goerror.NewInvalidInput(nil, "attributes", fmt.Sprintf("at most %d attributes can be stored", l.maxKeys))
+	}
+
+	raw, err := json.Marshal(attrs)
+	if err != nil {
+		return goerror.NewInvalidInput(nil, "attributes", "attributes must be valid JSON")
+	}
+	if len(raw) > l.maxTotalBytes {
+		return goerror.NewInvalidInput(nil, "attributes", fmt.Sprintf("attributes must be at most %d bytes in total", l.maxTotalBytes))
+	}
+
+	return nil
+}
+
+// ProfileAttributes returns the custom profile attributes of the authenticated user.
+func (s *Usecase) ProfileAttributes(ctx context.Context) (*ProfileAttributesOutput, error) {
+	ctx, span := s.startSpan(ctx, "ProfileAttributes")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	attrs, err := s.repoDB.GetUserAttributes(ctx, clm.UserID, false)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user attributes", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if attrs == nil {
+		attrs = valueobject.JSONMap{}
+	}
+
+	return &ProfileAttributesOutput{Attributes: attrs}, nil
+}
+
+// ProfileUpdateAttributes merges in.Attributes into the custom profile attributes of the
+// authenticated user, removing the keys set to nil, and returns the stored result.
+func (s *Usecase) ProfileUpdateAttributes(ctx context.Context, in ProfileUpdateAttributesInput) (*ProfileAttributesOutput, error) {
+	ctx, span := s.startSpan(ctx, "ProfileUpdateAttributes")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	limits := s.attributeLimits()
+	if err := limits.checkAttributesPatch(in.Attributes); err != nil {
+		return nil, err
+	}
+
+	var merged valueobject.JSONMap
+	err := s.repoDB.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.repoDB.GetUserAttributes(ctx, clm.UserID, true)
+		if err != nil {
+			return err
+		}
+
+		merged = make(valueobject.JSONMap, len(current)+len(in.Attributes))
+		maps.Copy(merged, current)
+		for key, value := range in.Attributes {
+			if value == nil {
+				delete(merged, key)
+				continue
+			}
+			merged[key] = value
+		}
+
+		if err := limits.checkAttributes(merged); err != nil {
+			return err
+		}
+
+		return s.repoDB.UpdateUserAttributes(ctx, clm.UserID, clm.UserID, merged)
+	})
+	var gerr *goerror.Error
+	if errors.As(err, &gerr) {
+		return nil, err
+	}
+	if errors.Is(err, goerror.ErrNotFound) {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo update user attributes", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	set := make([]string, 0, len(in.Attributes))
+	removed := make([]string, 0, len(in.Attributes))
+	for key, value := range in.Attributes {
+		if value == nil {
+			removed = append(removed, key)
+		} else {
+			set = append(set, key)
+		}
+	}
+	slices.Sort(set)
+	slices.Sort(removed)
+	s.recordAudit(ctx, entity.AuditActionProfileAttributes, clm.UserID, clm.UserID, map[string]any{
+		"set":     set,
+		"removed": removed,
+	})
+
+	return &ProfileAttributesOutput{Attributes: merged}, nil
+}
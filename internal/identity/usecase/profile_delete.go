@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+type (
+	ProfileDeleteInput struct {
+		CurrentPassword string `validate:"required"`
+	}
+
+	ProfileDeleteOutput struct {
+		ScheduledAt time.Time
+	}
+)
+
+// ProfileDelete schedules the anonymization of the authenticated user's account once the
+// grace period has passed. The schedule is kept in the database and carried out by
+// ProcessDueUserDeletions; the message published here only gets it done sooner on brokers
+// with delayed delivery. Asking again keeps the original schedule.
+func (s *Usecase) ProfileDelete(ctx context.Context, in ProfileDeleteInput) (*ProfileDeleteOutput, error) {
+	ctx, span := s.startSpan(ctx, "ProfileDelete")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	user, err := s.repoDB.GetUserCredentialInfo(ctx, clm.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user account not found", "user_id", clm.UserID)
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user credential info", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, user.ID, user.Status); err != nil {
+		return nil, err
+	}
+
+	if !s.bcrypt.Verify(user.Password, in.CurrentPassword) {
+		slog.WarnContext(ctx, "current password mismatch", "user_id", user.ID)
+		return nil, goerror.NewBusiness("invalid password", goerror.CodeUnauthorized)
+	}
+
+	scheduledAt := s.clock.Now().Add(s.cfg.GetHour("modules.identity.account_deletion.grace_period_hours"))
+
+	deletion, err := s.repoDB.CreateUserDeletion(ctx, user.ID, scheduledAt)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo create user deletion", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	s.publishUserDeletion(ctx, deletion)
+
+	s.recordAudit(ctx, entity.AuditActionProfileDeleteRequest, user.ID, user.ID, map[string]any{
+		"scheduled_at": deletion.ScheduledAt,
+	})
+
+	return &ProfileDeleteOutput{ScheduledAt: deletion.ScheduledAt}, nil
+}
+
+// ProfileDeleteCancel withdraws the pending deletion of the authenticated user's account. A
+// message already in flight finds no deletion and does nothing.
+func (s *Usecase) ProfileDeleteCancel(ctx context.Context) error {
+	ctx, span := s.startSpan(ctx, "ProfileDeleteCancel")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	canceled, err := s.repoDB.DeletePendingUserDeletion(ctx, clm.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete pending user deletion", "user_id", clm.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+	if !canceled {
+		return goerror.NewBusiness("no account deletion is pending", goerror.CodeNotFound)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionProfileDeleteCancel, clm.UserID, clm.UserID, nil)
+
+	return nil
+}
+
+// ProcessDueUserDeletions anonymizes up to modules.identity.account_deletion.batch_size
+// accounts whose grace period is over. It returns how many were anonymized; a failed one
+// stays pending and is tried again on the next run.
+func (s *Usecase) ProcessDueUserDeletions(ctx context.Context) (int, error) {
+	ctx, span := s.startSpan(ctx, "ProcessDueUserDeletions")
+	defer span.End()
+
+	batchSize := s.cfg.GetInt32("modules.identity.account_deletion.batch_size")
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	due, err := s.repoDB.GetUserDeletionDue(ctx, s.clock.Now(), batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user deletion due", "error", err)
+		return 0, goerror.NewServer(err)
+	}
+
+	done := 0
+	for _, deletion := range due {
+		// AnonymizeUser logs its own failures
+		if err := s.AnonymizeUser(ctx, AnonymizeUserInput{UserID: deletion.UserID}); err != nil {
+			continue
+		}
+		done++
+	}
+
+	if done > 0 {
+		slog.InfoContext(ctx, "due user deletions processed", "count", done)
+	}
+
+	return done, nil
+}
+
+// publishUserDeletion sends the deletion message to arrive when the grace period ends. The
+// delay is capped by modules.identity.account_deletion.max_delay_minutes, since brokers
+// bound how long they defer a message; an early message is published again by its consumer.
+// A failure is only logged, since the deletion job picks the schedule up either way.
+func (s *Usecase) publishUserDeletion(ctx context.Context, deletion *entity.UserDeletion) {
+	delay := max(deletion.ScheduledAt.Sub(s.clock.Now()), 0)
+	if maxDelay := s.cfg.GetMinute("modules.identity.account_deletion.max_delay_minutes"); maxDelay > 0 {
+		delay = min(delay, maxDelay)
+	}
+
+	if err := s.repoMessaging.PublishUserDeletionScheduled(ctx, contracts.UserDeletionScheduled{
+		UserID:      deletion.UserID,
+		ScheduledAt: deletion.ScheduledAt,
+	}, delay); err != nil {
+		slog.WarnContext(ctx, "failed to publish user deletion scheduled", "user_id", deletion.UserID, "error", err)
+	}
+}
@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/pkg/signedurl"
+	"github.com/shandysiswandi/gobite/internal/pkg/storage"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+// ProfileExportPath is the download route of a profile export, followed by its ID.
+const ProfileExportPath = "/api/v1/identity/profile/export/"
+
+type (
+	ProfileExportOutput struct {
+		ID string
+		// URL is the signed download link, valid until ExpiresAt.
+		URL       string
+		ExpiresAt time.Time
+	}
+
+	ProfileExportDownloadInput struct {
+		ID string `validate:"required,uuid"`
+		// Expires and Signature come from the signed download link.
+		Expires   string
+		Signature string
+	}
+
+	ProfileExportDownloadOutput struct {
+		Body io.ReadCloser
+	}
+
+	profileExportUser struct {
+		ID        int64  `json:"id,string"`
+		Email     string `json:"email"`
+		FullName  string `json:"full_name"`
+		AvatarURL string `json:"avatar_url"`
+		Status    string `json:"status"`
+		// Attributes are the custom profile attributes kept by apps.
+		Attributes valueobject.JSONMap `json:"attributes"`
+	}
+
+	profileExportSession struct {
+		ID        int64  `json:"id,string"`
+		IP        string `json:"ip"`
+		UserAgent string `json:"user_agent"`
+		// RefreshedAt is when the session last rotated its refresh token.
+		RefreshedAt      time.Time `json:"refreshed_at"`
+		SessionStartedAt time.Time `json:"started_at"`
+		ExpiresAt        time.Time `json:"expires_at"`
+	}
+)
+
+// ProfileExport assembles the personal data of the authenticated user into a zip archive in
+// object storage and returns its ID and signed link, which only this user can download through
+// ProfileExportDownload until it expires. The archive holds one JSON file per source: the
+// profile and sessions kept here, and whatever other modules registered with the user data
+// registry.
+func (s *Usecase) ProfileExport(ctx context.Context) (*ProfileExportOutput, error) {
+	ctx, span := s.startSpan(ctx, "ProfileExport")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	user, err := s.repoDB.GetUserByID(ctx, clm.UserID, false)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user account not found", "user_id", clm.UserID)
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, user.ID, user.Status); err != nil {
+		return nil, err
+	}
+
+	attrs, err := s.repoDB.GetUserAttributes(ctx, user.ID, false)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user attributes", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	sessions, err := s.repoDB.GetActiveSessions(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get active sessions", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	exportSessions := make([]profileExportSession, 0, len(sessions))
+	for _, session := range sessions {
+		exportSessions = append(exportSessions, profileExportSession{
+			ID:               session.ID,
+			IP:               session.IP,
+			UserAgent:        session.UserAgent,
+			RefreshedAt:      session.CreatedAt,
+			SessionStartedAt: session.SessionStartedAt,
+			ExpiresAt:        session.ExpiresAt,
+		})
+	}
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+
+	if err := writeExportFile(zw, "profile", profileExportUser{
+		ID:        user.ID,
+		Email:     user.Email,
+		FullName:  user.FullName,
+		AvatarURL: user.AvatarURL,
+		Status:    user.Status.String(),
+
+		Attributes: attrs,
+	}); err != nil {
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := writeExportFile(zw, "sessions", exportSessions); err != nil {
+		return nil, goerror.NewServer(err)
+	}
+
+	for _, src := range s.userData.Sources() {
+		if src.Export == nil {
+			continue
+		}
+
+		data, err := src.Export(ctx, user.ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to export user data", "user_id", user.ID, "source", src.Name, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+
+		if err := writeExportFile(zw, src.Name, data); err != nil {
+			return nil, goerror.NewServer(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, goerror.NewServer(err)
+	}
+
+	id := s.uuid.Generate()
+	key := s.profileExportKey(user.ID, id)
+	bucket := s.cfg.GetString("modules.identity.data_export.bucket")
+
+	if _, err := s.storage.PutObject(ctx, bucket, key, buf, storage.PutOptions{
+		Size:        int64(buf.Len()),
+		ContentType: "application/zip",
+		Metadata:    map[string]string{"user_id": strconv.FormatInt(user.ID, 10)},
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to upload user data export", "user_id", user.ID, "bucket", bucket, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	ttl := s.cfg.GetMinute("modules.identity.data_export.download_ttl_minutes")
+	link, err := s.signedURL.Sign(ProfileExportPath+id, constant.SignedURLPurposeProfileExport, key, ttl)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to sign user data export url", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionProfileExport, user.ID, user.ID, nil)
+
+	return &ProfileExportOutput{
+		ID:        id,
+		URL:       link,
+		ExpiresAt: s.clock.Now().Add(ttl),
+	}, nil
+}
+
+// ProfileExportDownload opens an archive made by ProfileExport through its signed link. The
+// object key is derived from the caller, so another user's archive is never found, and an
+// archive older than modules.identity.data_export.download_ttl_minutes is treated as gone.
+// The caller closes the body.
+func (s *Usecase) ProfileExportDownload(ctx context.Context, in ProfileExportDownloadInput) (*ProfileExportDownloadOutput, error) {
+	ctx, span := s.startSpan(ctx, "ProfileExportDownload")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	bucket := s.cfg.GetString("modules.identity.data_export.bucket")
+	key := s.profileExportKey(clm.UserID, in.ID)
+
+	// the link is signed for the object key, which names the caller, so it is useless to
+	// anyone else and once it expired
+	query := url.Values{signedurl.ParamExpires: {in.Expires}, signedurl.ParamSignature: {in.Signature}}
+	err := s.signedURL.Verify(query, constant.SignedURLPurposeProfileExport, key)
+	if errors.Is(err, signedurl.ErrExpired) {
+		return nil, goerror.NewBusiness("export not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "invalid user data export link signature", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewBusiness("invalid export link", goerror.CodeForbidden)
+	}
+
+	body, info, err := s.storage.GetObject(ctx, bucket, key, storage.GetOptions{})
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, goerror.NewBusiness("export not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get user data export", "user_id", clm.UserID, "key", key, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	ttl := s.cfg.GetMinute("modules.identity.data_export.download_ttl_minutes")
+	if !s.clock.Now().Before(info.UpdatedAt.Add(ttl)) {
+		if err := body.Close(); err != nil {
+			slog.WarnContext(ctx, "failed to close user data export", "key", key, "error", err)
+		}
+		return nil, goerror.NewBusiness("export not found", goerror.CodeNotFound)
+	}
+
+	return &ProfileExportDownloadOutput{Body: body}, nil
+}
+
+func (s *Usecase) profileExportKey(userID int64, id string) string {
+	key := fmt.Sprintf("%d/%s.zip", userID, id)
+	if prefix := strings.Trim(s.cfg.GetString("modules.identity.data_export.prefix"), "/"); prefix != "" {
+		key = prefix + "/" + key
+	}
+
+	return key
+}
+
+func writeExportFile(zw *zip.Writer, name string, data any) error {
+	w, err := zw.Create(name + ".json")
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(data)
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+const (
+	OnboardingStepEmailVerified      = "email_verified"
+	OnboardingStepAvatarSet          = "avatar_set"
+	OnboardingStepMFAEnabled         = "mfa_enabled"
+	OnboardingStepRecoveryCodesSaved = "recovery_codes_saved"
+)
+
+// defaultAvatarPrefix is the generated avatar every account starts with.
+const defaultAvatarPrefix = "https://ui-avatars.com/api/"
+
+type (
+	OnboardingStep struct {
+		Key       string
+		Completed bool
+	}
+
+	ProfileOnboardingOutput struct {
+		Steps     []OnboardingStep
+		Completed int
+		Total     int
+		Percent   int
+	}
+)
+
+// ProfileOnboarding reports which onboarding steps the authenticated user has completed.
+func (s *Usecase) ProfileOnboarding(ctx context.Context) (*ProfileOnboardingOutput, error) {
+	ctx, span := s.startSpan(ctx, "ProfileOnboarding")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	user, err := s.repoDB.GetUserByID(ctx, clm.UserID, false)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user account not found", "user_id", clm.UserID)
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	factors, err := s.repoDB.GetMFAFactorByUserID(ctx, clm.UserID, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get mfa factor by user_id", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	mfaEnabled := false
+	for _, f := range factors {
+		if f.Type == entity.MFATypeTOTP {
+			mfaEnabled = true
+			break
+		}
+	}
+
+	codes, err := s.repoDB.GetMFABackupCodeByUserID(ctx, clm.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get backup codes by user_id", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	steps := []OnboardingStep{
+		{Key: OnboardingStepEmailVerified, Completed: user.Status != entity.UserStatusUnverified},
+		{Key: OnboardingStepAvatarSet, Completed: user.AvatarURL != "" && !strings.HasPrefix(user.AvatarURL, defaultAvatarPrefix)},
+		{Key: OnboardingStepMFAEnabled, Completed: mfaEnabled},
+		// codes are only shown once when generated, so having unused ones means they were saved
+		{Key: OnboardingStepRecoveryCodesSaved, Completed: len(codes) > 0},
+	}
+
+	out := &ProfileOnboardingOutput{Steps: steps, Total: len(steps)}
+	for _, step := range steps {
+		if step.Completed {
+			out.Completed++
+		}
+	}
+	out.Percent = out.Completed * 100 / out.Total
+
+	return out, nil
+}
@@ -17,7 +17,13 @@ func (s *Usecase) ProfilePermissions(ctx context.Context) (map[string][]string,
 		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
 	}
 
-	policies, err := s.enforcer.GetImplicitPermissionsForUser(strconv.FormatInt(clm.UserID, 10))
+	return s.effectivePermissions(strconv.FormatInt(clm.UserID, 10))
+}
+
+// effectivePermissions returns the object to actions matrix a Casbin subject can use,
+// including permissions inherited through roles.
+func (s *Usecase) effectivePermissions(subject string) (map[string][]string, error) {
+	policies, err := s.enforcer.GetImplicitPermissionsForUser(subject)
 	if err != nil {
 		return nil, err
 	}
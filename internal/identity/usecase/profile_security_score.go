@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+const (
+	SecurityCheckMFAEnabled           = "mfa_enabled"
+	SecurityCheckRecoveryCodes        = "recovery_codes"
+	SecurityCheckRecentPassword       = "recent_password"
+	SecurityCheckNoSuspiciousSessions = "no_suspicious_sessions"
+)
+
+type (
+	SecurityCheck struct {
+		Key    string
+		Passed bool
+		// Weight is the number of points the check adds to the score when it passes.
+		Weight int
+		// Recommendation tells the user how to pass the check; empty when it passed.
+		Recommendation string
+	}
+
+	ProfileSecurityScoreOutput struct {
+		Checks []SecurityCheck
+		Score  int // 0-100
+	}
+)
+
+// ProfileSecurityScore rates the authenticated user's account security from the MFA,
+// credential and session data already stored, and recommends how to improve it. The
+// weights of all checks add up to 100.
+func (s *Usecase) ProfileSecurityScore(ctx context.Context) (*ProfileSecurityScoreOutput, error) {
+	ctx, span := s.startSpan(ctx, "ProfileSecurityScore")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	cred, err := s.repoDB.GetUserCredentialInfo(ctx, clm.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user account not found", "user_id", clm.UserID)
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user credential info", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	factors, err := s.repoDB.GetMFAFactorByUserID(ctx, clm.UserID, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get mfa factor by user_id", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	mfaEnabled := false
+	for _, f := range factors {
+		if f.Type == entity.MFATypeTOTP || f.Type == entity.MFATypeSMS {
+			mfaEnabled = true
+			break
+		}
+	}
+
+	codes, err := s.repoDB.GetMFABackupCodeByUserID(ctx, clm.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get backup codes by user_id", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	sessions, err := s.repoDB.GetActiveSessions(ctx, clm.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get active sessions", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	now := s.clock.Now()
+	maxPasswordAge := time.Duration(s.cfg.GetInt("modules.identity.security_score.password_max_age_days")) * 24 * time.Hour
+	staleAfter := time.Duration(s.cfg.GetInt("modules.identity.security_score.stale_session_days")) * 24 * time.Hour
+
+	// a session is suspicious when it has not been refreshed for a long time: a forgotten or
+	// lost device still able to get tokens
+	suspicious := 0
+	for _, sess := range sessions {
+		if staleAfter > 0 && now.Sub(sess.CreatedAt) > staleAfter {
+			suspicious++
+		}
+	}
+
+	checks := []SecurityCheck{
+		{
+			Key:            SecurityCheckMFAEnabled,
+			Passed:         mfaEnabled,
+			Weight:         40,
+			Recommendation: "Turn on two-factor authentication with an authenticator app or SMS.",
+		},
+		{
+			Key: SecurityCheckRecoveryCodes,
+			// codes are only shown once when generated, so having unused ones means they were saved
+			Passed:         len(codes) > 0,
+			Weight:         20,
+			Recommendation: "Generate recovery codes and store them somewhere safe.",
+		},
+		{
+			Key:            SecurityCheckRecentPassword,
+			Passed:         maxPasswordAge <= 0 || now.Sub(cred.PasswordUpdatedAt) <= maxPasswordAge,
+			Weight:         20,
+			Recommendation: "Change your password; it has not been changed for a long time.",
+		},
+		{
+			Key:            SecurityCheckNoSuspiciousSessions,
+			Passed:         suspicious == 0,
+			Weight:         20,
+			Recommendation: "Review your active sessions and sign out the devices you no longer use.",
+		},
+	}
+
+	out := &ProfileSecurityScoreOutput{Checks: checks}
+	for i := range out.Checks {
+		if out.Checks[i].Passed {
+			out.Score += out.Checks[i].Weight
+			out.Checks[i].Recommendation = ""
+		}
+	}
+
+	return out, nil
+}
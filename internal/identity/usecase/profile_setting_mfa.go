@@ -35,7 +35,7 @@ func (s *Usecase) ProfileSettingMFA(ctx context.Context) (*ProfileSettingMFAOutp
 		case entity.MFATypeBackupCode:
 			resp.BackupCodeEnabled = true
 		case entity.MFATypeSMS:
-			resp.SMSEnabled = false
+			resp.SMSEnabled = true
 		case entity.MFATypeTOTP:
 			resp.TOTPEnabled = true
 		}
@@ -29,7 +29,7 @@ func (s *Usecase) ProfileUpdate(ctx context.Context, in ProfileUpdateInput) erro
 		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
 	}
 
-	user, err := s.repoDB.GetUserByEmail(ctx, clm.UserEmail, false)
+	user, err := s.getUserByEmail(ctx, clm.UserEmail, false)
 	if errors.Is(err, goerror.ErrNotFound) {
 		slog.WarnContext(ctx, "user account not found", "email", clm.UserEmail)
 		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
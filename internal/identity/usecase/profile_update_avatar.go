@@ -47,7 +47,7 @@ func (s *Usecase) ProfileUpdateAvatar(ctx context.Context, in ProfileUpdateAvata
 		return goerror.NewInvalidInput(nil, "avatar", "unsupported avatar content type")
 	}
 
-	user, err := s.repoDB.GetUserByEmail(ctx, clm.UserEmail, false)
+	user, err := s.getUserByEmail(ctx, clm.UserEmail, false)
 	if errors.Is(err, goerror.ErrNotFound) {
 		slog.WarnContext(ctx, "user account not found", "email", clm.UserEmail)
 		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
@@ -4,13 +4,19 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 )
 
 type RefreshTokenInput struct {
 	RefreshToken string `validate:"required"`
+	IP           string
+	UserAgent    string
+	DeviceName   string
 }
 
 type RefreshTokenOutput struct {
@@ -50,6 +56,7 @@ func (s *Usecase) RefreshToken(ctx context.Context, in RefreshTokenInput) (*Refr
 			if err := s.repoDB.RevokeAllRefreshToken(ctx, rt.UserID); err != nil {
 				slog.ErrorContext(ctx, "failed to repo revoke all refresh token", "user_id", rt.UserID, "error", err)
 			}
+			s.revokeUserAccessTokens(ctx, rt.UserID)
 
 			slog.WarnContext(ctx, "SECURITY: refresh token reuse detected")
 			return nil, goerror.NewBusiness("token reuse detected, please log in again", goerror.CodeForbidden)
@@ -75,18 +82,31 @@ func (s *Usecase) RefreshToken(ctx context.Context, in RefreshTokenInput) (*Refr
 		return nil, goerror.NewServer(err)
 	}
 
-	acToken, err := s.jwt.Generate(rt.UserID, rt.UserEmail)
+	// the client type is the one given at sign-in; a refresh cannot claim a longer-lived client
+	client := rt.RefreshMetadata.Client
+	ttl := s.tokenTTLFor(ctx, rt.UserID, client)
+
+	// the session keeps the time the user signed in; only a step-up makes auth_time newer
+	acToken, err := s.jwt.Generate(jwt.WithAuthTime(jwt.WithTTL(ctx, ttl.access), rt.RefreshSessionStartedAt), rt.UserID, rt.UserEmail)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to generate access jwt token", "user_id", rt.UserID, "error", err)
 		return nil, goerror.NewServer(err)
 	}
 
+	// clients name the device at sign-in; a refresh that does not name it again keeps that name
+	deviceName := in.DeviceName
+	if strings.TrimSpace(deviceName) == "" {
+		deviceName = rt.RefreshMetadata.DeviceName
+	}
+
 	err = s.repoDB.RotateRefreshToken(ctx, entity.RotateRefreshToken{
-		NewID:        s.uid.Generate(),
-		OldID:        rt.RefreshID,
-		UserID:       rt.UserID,
-		NewToken:     string(newRefreshTokenHash),
-		NewExpiresAt: s.clock.Now().Add(s.cfg.GetDay("modules.identity.refresh_token_ttl_days")),
+		NewID:            s.uid.Generate(),
+		OldID:            rt.RefreshID,
+		UserID:           rt.UserID,
+		NewToken:         string(newRefreshTokenHash),
+		NewExpiresAt:     s.rotatedRefreshTokenExpiry(rt, ttl.refresh),
+		SessionStartedAt: rt.RefreshSessionStartedAt,
+		Metadata:         sessionMetadata(in.IP, in.UserAgent, client, deviceName),
 	})
 	if errors.Is(err, goerror.ErrNotFound) {
 		slog.WarnContext(ctx, "refresh token already rotated or revoked", "refresh_token_id", rt.RefreshID)
@@ -102,3 +122,22 @@ func (s *Usecase) RefreshToken(ctx context.Context, in RefreshTokenInput) (*Refr
 		RefreshToken: newRefreshToken,
 	}, nil
 }
+
+// rotatedRefreshTokenExpiry keeps the original expiry unless sliding expiration is enabled, in which
+// case each use extends it by ttl without passing the session's absolute max lifetime.
+func (s *Usecase) rotatedRefreshTokenExpiry(rt *entity.UserRefreshToken, ttl time.Duration) time.Time {
+	if !s.cfg.GetBool("modules.identity.refresh_token_sliding.enabled") {
+		return rt.RefreshExpiresAt
+	}
+
+	expiresAt := s.clock.Now().Add(ttl)
+
+	maxLifetime := s.cfg.GetDay("modules.identity.refresh_token_sliding.max_lifetime_days")
+	if maxLifetime > 0 && !rt.RefreshSessionStartedAt.IsZero() {
+		if limit := rt.RefreshSessionStartedAt.Add(maxLifetime); expiresAt.After(limit) {
+			expiresAt = limit
+		}
+	}
+
+	return expiresAt
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/shandysiswandi/gobite/internal/contracts"
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 )
@@ -15,6 +16,10 @@ type RegisterInput struct {
 	Email    string `validate:"required,email"`
 	Password string `validate:"required,password"`
 	FullName string `validate:"required,min=5,max=100,alphaspace"`
+	// Username is optional and only accepted when modules.identity.username.enabled is set.
+	Username string `validate:"max=64"`
+	// CaptchaToken is the CAPTCHA response, required when register is listed in the captcha endpoints.
+	CaptchaToken string
 }
 
 func (s *Usecase) Register(ctx context.Context, in RegisterInput) error {
@@ -23,12 +28,30 @@ func (s *Usecase) Register(ctx context.Context, in RegisterInput) error {
 
 	in.Email = strings.TrimSpace(strings.ToLower(in.Email))
 	in.FullName = strings.TrimSpace(in.FullName)
+	in.Username = normalizeUsername(in.Username)
 
 	if err := s.validator.Validate(in); err != nil {
 		return goerror.NewInvalidInput(err)
 	}
 
-	user, err := s.repoDB.GetUserByEmail(ctx, in.Email, true)
+	if err := s.verifyCaptcha(ctx, CaptchaEndpointRegister, in.CaptchaToken); err != nil {
+		return err
+	}
+
+	if err := s.checkPasswordPolicy("password", in.Password, in.Email); err != nil {
+		return err
+	}
+
+	if in.Username != "" {
+		if !s.usernameEnabled() {
+			return goerror.NewInvalidInput(nil, "username", "usernames are not enabled")
+		}
+		if err := s.checkUsername("username", in.Username); err != nil {
+			return err
+		}
+	}
+
+	user, err := s.getUserByEmail(ctx, in.Email, true)
 	if err == nil {
 		switch user.Status {
 		case entity.UserStatusActive:
@@ -46,6 +69,17 @@ func (s *Usecase) Register(ctx context.Context, in RegisterInput) error {
 		return goerror.NewServer(err)
 	}
 
+	if in.Username != "" {
+		taken, err := s.repoDB.IsUsernameTaken(ctx, in.Username)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo check username", "error", err)
+			return goerror.NewServer(err)
+		}
+		if taken {
+			return goerror.NewBusiness("username is already taken", goerror.CodeConflict)
+		}
+	}
+
 	hashedPassword, err := s.bcrypt.Hash(in.Password)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to hash password", "error", err)
@@ -57,12 +91,19 @@ func (s *Usecase) Register(ctx context.Context, in RegisterInput) error {
 		ID:        newUserID,
 		CreatedBy: newUserID,
 		UpdatedBy: newUserID,
-		Email:     in.Email,
+		Email:     s.normalizeEmail(in.Email),
 		FullName:  in.FullName,
+		Username:  in.Username,
 		AvatarURL: "https://ui-avatars.com/api/?name=" + url.QueryEscape(in.FullName),
 		Status:    entity.UserStatusUnverified,
 	}
 
+	newUser.EmailHash, newUser.EmailSealedOnly, err = s.emailLookupHash(newUser.Email)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash email", "error", err)
+		return goerror.NewServer(err)
+	}
+
 	cToken := s.oid.Generate()
 	cTokenHash, err := s.hmac.Hash(cToken)
 	if err != nil {
@@ -78,12 +119,16 @@ func (s *Usecase) Register(ctx context.Context, in RegisterInput) error {
 		ExpiresAt: s.clock.Now().Add(s.cfg.GetHour("modules.identity.registration_ttl_hours")),
 	}
 
-	if err := s.repoDB.NewRegistration(ctx, newUser, challenge, string(hashedPassword)); err != nil {
+	err = s.repoDB.NewRegistration(ctx, newUser, challenge, string(hashedPassword))
+	if errors.Is(err, goerror.ErrConflict) && newUser.Username != "" {
+		return goerror.NewBusiness("username is already taken", goerror.CodeConflict)
+	}
+	if err != nil {
 		slog.ErrorContext(ctx, "failed to repo user registration", "email", newUser.Email, "error", err)
 		return goerror.NewServer(err)
 	}
 
-	if err := s.repoMessaging.PublishUserRegistration(ctx, UserRegistrationEvent{
+	if err := s.repoMessaging.PublishUserRegistration(ctx, contracts.UserRegistered{
 		UserID:         newUser.ID,
 		Email:          newUser.Email,
 		FullName:       newUser.FullName,
@@ -6,12 +6,15 @@ import (
 	"log/slog"
 	"strings"
 
+	"github.com/shandysiswandi/gobite/internal/contracts"
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 )
 
 type RegisterResendInput struct {
 	Email string `validate:"required,email"`
+	// CaptchaToken is the CAPTCHA response, required when register_resend is listed in the captcha endpoints.
+	CaptchaToken string
 }
 
 func (s *Usecase) RegisterResend(ctx context.Context, in RegisterResendInput) error {
@@ -24,7 +27,11 @@ func (s *Usecase) RegisterResend(ctx context.Context, in RegisterResendInput) er
 		return goerror.NewInvalidInput(err)
 	}
 
-	user, err := s.repoDB.GetUserByEmail(ctx, in.Email, false)
+	if err := s.verifyCaptcha(ctx, CaptchaEndpointRegisterResend, in.CaptchaToken); err != nil {
+		return err
+	}
+
+	user, err := s.getUserByEmail(ctx, in.Email, false)
 	if errors.Is(err, goerror.ErrNotFound) {
 		slog.WarnContext(ctx, "email not registered for resend", "email", in.Email)
 		return nil
@@ -39,6 +46,12 @@ func (s *Usecase) RegisterResend(ctx context.Context, in RegisterResendInput) er
 		return nil
 	}
 
+	return s.sendRegistrationChallenge(ctx, user)
+}
+
+// sendRegistrationChallenge issues a new verification link to an unverified user and
+// publishes it for the notification module to email.
+func (s *Usecase) sendRegistrationChallenge(ctx context.Context, user *entity.User) error {
 	cToken := s.oid.Generate()
 	cTokenHash, err := s.hmac.Hash(cToken)
 	if err != nil {
@@ -59,7 +72,7 @@ func (s *Usecase) RegisterResend(ctx context.Context, in RegisterResendInput) er
 		return goerror.NewServer(err)
 	}
 
-	if err := s.repoMessaging.PublishUserRegistration(ctx, UserRegistrationEvent{
+	if err := s.repoMessaging.PublishUserRegistration(ctx, contracts.UserRegistered{
 		UserID:         user.ID,
 		Email:          user.Email,
 		FullName:       user.FullName,
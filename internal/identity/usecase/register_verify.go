@@ -4,13 +4,19 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"net/url"
 
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/signedurl"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
 )
 
 type RegisterVerifyInput struct {
 	ChallengeToken string `validate:"required"`
+	// Expires and Signature come from the signed link in the verification email.
+	Expires   string
+	Signature string
 }
 
 func (s *Usecase) RegisterVerify(ctx context.Context, in RegisterVerifyInput) error {
@@ -21,6 +27,18 @@ func (s *Usecase) RegisterVerify(ctx context.Context, in RegisterVerifyInput) er
 		return goerror.NewInvalidInput(err)
 	}
 
+	if in.Signature != "" || s.cfg.GetBool("modules.identity.register_verify_require_signature") {
+		query := url.Values{signedurl.ParamExpires: {in.Expires}, signedurl.ParamSignature: {in.Signature}}
+		err := s.signedURL.Verify(query, constant.SignedURLPurposeEmailVerify, in.ChallengeToken)
+		if errors.Is(err, signedurl.ErrExpired) {
+			return goerror.NewBusiness("verification link has expired", goerror.CodeUnauthorized)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "invalid verification link signature", "error", err)
+			return goerror.NewBusiness("invalid verification token", goerror.CodeUnauthorized)
+		}
+	}
+
 	cTokenHash, err := s.hmac.Hash(in.ChallengeToken)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to hash token challange", "error", err)
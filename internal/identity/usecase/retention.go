@@ -0,0 +1,36 @@
+package usecase
+
+import "github.com/shandysiswandi/gobite/internal/pkg/retention"
+
+// RetentionTargets lists the identity tables cleaned up by the retention scheduler.
+// Their windows live under retention.tables. Only audit logs are archived first; login
+// events are deleted outright.
+func (s *Usecase) RetentionTargets() []retention.Target {
+	return []retention.Target{
+		{
+			Table: "identity_challenges",
+			Count: s.repoDB.CountChallengeExpiredBefore,
+			Purge: s.repoDB.DeleteChallengeExpiredBefore,
+		},
+		{
+			Table: "identity_refresh_tokens",
+			Count: s.repoDB.CountRefreshTokenExpiredBefore,
+			Purge: s.repoDB.DeleteRefreshTokenExpiredBefore,
+		},
+		{
+			Table: "identity_trusted_devices",
+			Count: s.repoDB.CountTrustedDeviceExpiredBefore,
+			Purge: s.repoDB.DeleteTrustedDeviceExpiredBefore,
+		},
+		{
+			Table: "identity_audit_logs",
+			Count: s.repoDB.CountAuditLogBefore,
+			Purge: s.purgeAuditLogs,
+		},
+		{
+			Table: "identity_login_events",
+			Count: s.repoDB.CountLoginEventBefore,
+			Purge: s.repoDB.DeleteLoginEventBefore,
+		},
+	}
+}
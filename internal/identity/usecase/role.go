@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+// roleNamePattern keeps role names apart from user subjects, which are numeric IDs.
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_:-]*$`)
+
+type RolePermission struct {
+	Object string `validate:"required,max=128"`
+	Action string `validate:"required,max=32"`
+}
+
+func normalizeRoleName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func validateRoleName(name string) error {
+	if !roleNamePattern.MatchString(name) {
+		return goerror.NewBusiness("role name must start with a letter and contain only a-z, 0-9, '_', ':' or '-'", goerror.CodeInvalidInput)
+	}
+	return nil
+}
+
+// roleExists reports whether any policy or assignment names role.
+func (s *Usecase) roleExists(role string) (bool, error) {
+	policies, err := s.enforcer.GetFilteredPolicy(0, role)
+	if err != nil {
+		return false, err
+	}
+	if len(policies) > 0 {
+		return true, nil
+	}
+
+	members, err := s.enforcer.GetFilteredGroupingPolicy(1, role)
+	if err != nil {
+		return false, err
+	}
+
+	return len(members) > 0, nil
+}
+
+func (s *Usecase) ensureRoleExists(ctx context.Context, role string) error {
+	ok, err := s.roleExists(role)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to check role", "role", role, "error", err)
+		return goerror.NewServer(err)
+	}
+	if !ok {
+		return goerror.NewBusiness("role not found", goerror.CodeNotFound)
+	}
+	return nil
+}
+
+// isProtectedRole reports whether role is listed in modules.identity.rbac.protected_roles.
+// Protected roles cannot be deleted or lose their last permission, and nobody can remove
+// themselves from one.
+func (s *Usecase) isProtectedRole(role string) bool {
+	for _, r := range s.cfg.GetArray("modules.identity.rbac.protected_roles") {
+		if normalizeRoleName(r) == role {
+			return true
+		}
+	}
+	return false
+}
+
+// rolePermissions returns the object to actions matrix granted directly to role.
+func (s *Usecase) rolePermissions(role string) (map[string][]string, error) {
+	policies, err := s.enforcer.GetFilteredPolicy(0, role)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make(map[string][]string)
+	for _, policy := range policies {
+		if len(policy) < 3 {
+			continue
+		}
+		permissions[policy[1]] = append(permissions[policy[1]], policy[2])
+	}
+
+	return permissions, nil
+}
+
+func rolePolicies(role string, perms []RolePermission) [][]string {
+	rules := make([][]string, 0, len(perms))
+	seen := make(map[[2]string]struct{}, len(perms))
+	for _, p := range perms {
+		key := [2]string{strings.TrimSpace(p.Object), strings.TrimSpace(p.Action)}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		rules = append(rules, []string{role, key[0], key[1]})
+	}
+
+	slices.SortFunc(rules, func(a, b []string) int {
+		return strings.Compare(a[1]+"\x00"+a[2], b[1]+"\x00"+b[2])
+	})
+
+	return rules
+}
+
+// auditRoleChange records an RBAC change. The change is already applied, so a failed
+// write is logged instead of failing the request.
+func (s *Usecase) auditRoleChange(ctx context.Context, actorID, targetUserID int64, action entity.AuditAction, meta valueobject.JSONMap) {
+	if err := s.repoDB.CreateAuditLog(ctx, entity.AuditLog{
+		ID:           s.uid.Generate(),
+		ActorID:      actorID,
+		TargetUserID: targetUserID,
+		Action:       action,
+		Metadata:     meta,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create audit log", "action", action.String(), "by_user_id", actorID, "error", err)
+	}
+
+	s.recordAudit(ctx, action, actorID, targetUserID, meta)
+}
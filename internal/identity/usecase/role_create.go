@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type RoleCreateInput struct {
+	Name        string           `validate:"required,min=2,max=64"`
+	Permissions []RolePermission `validate:"required,min=1,dive"`
+}
+
+// RoleCreate adds a role with its permissions. Casbin only knows a role through its
+// policies, so a role cannot be created empty.
+func (s *Usecase) RoleCreate(ctx context.Context, in RoleCreateInput) error {
+	ctx, span := s.startSpan(ctx, "RoleCreate")
+	defer span.End()
+
+	in.Name = normalizeRoleName(in.Name)
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	if err := validateRoleName(in.Name); err != nil {
+		return err
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtRoles, constant.PermActCreate)
+	if err != nil {
+		return err
+	}
+
+	exists, err := s.roleExists(in.Name)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to check role", "role", in.Name, "error", err)
+		return goerror.NewServer(err)
+	}
+	if exists {
+		return goerror.NewBusiness("role already exists", goerror.CodeConflict)
+	}
+
+	rules := rolePolicies(in.Name, in.Permissions)
+	if _, err := s.enforcer.AddPolicies(rules); err != nil {
+		slog.ErrorContext(ctx, "failed to add role policies", "role", in.Name, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.auditRoleChange(ctx, clm.UserID, 0, entity.AuditActionRoleCreate, valueobject.JSONMap{
+		"role":        in.Name,
+		"permissions": rules,
+	})
+
+	return nil
+}
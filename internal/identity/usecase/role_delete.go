@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type RoleDeleteInput struct {
+	Role string `validate:"required,max=64"`
+}
+
+// RoleDelete removes the role, its permissions, and every assignment of it.
+func (s *Usecase) RoleDelete(ctx context.Context, in RoleDeleteInput) error {
+	ctx, span := s.startSpan(ctx, "RoleDelete")
+	defer span.End()
+
+	in.Role = normalizeRoleName(in.Role)
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtRoles, constant.PermActDelete)
+	if err != nil {
+		return err
+	}
+
+	if s.isProtectedRole(in.Role) {
+		return goerror.NewBusiness("role is protected", goerror.CodeForbidden)
+	}
+
+	if err := s.ensureRoleExists(ctx, in.Role); err != nil {
+		return err
+	}
+
+	permissions, err := s.rolePermissions(in.Role)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get role permissions", "role", in.Role, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if _, err := s.enforcer.DeleteRole(in.Role); err != nil {
+		slog.ErrorContext(ctx, "failed to delete role", "role", in.Role, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.auditRoleChange(ctx, clm.UserID, 0, entity.AuditActionRoleDelete, valueobject.JSONMap{
+		"role":        in.Role,
+		"permissions": permissions,
+	})
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type (
+	RoleDetailInput struct {
+		Role string `validate:"required,max=64"`
+	}
+
+	RoleDetailOutput struct {
+		Name string
+		// Permissions are granted to the role itself.
+		Permissions map[string][]string
+		// EffectivePermissions include the permissions of roles this role inherits.
+		EffectivePermissions map[string][]string
+		MemberIDs            []int64
+	}
+)
+
+func (s *Usecase) RoleDetail(ctx context.Context, in RoleDetailInput) (*RoleDetailOutput, error) {
+	ctx, span := s.startSpan(ctx, "RoleDetail")
+	defer span.End()
+
+	in.Role = normalizeRoleName(in.Role)
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if _, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtRoles, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureRoleExists(ctx, in.Role); err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.rolePermissions(in.Role)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get role permissions", "role", in.Role, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	effective, err := s.effectivePermissions(in.Role)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get role effective permissions", "role", in.Role, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	users, err := s.enforcer.GetUsersForRole(in.Role)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get role members", "role", in.Role, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	memberIDs := make([]int64, 0, len(users))
+	for _, u := range users {
+		// roles inheriting this role are members too, but only users are listed
+		if id, err := strconv.ParseInt(u, 10, 64); err == nil {
+			memberIDs = append(memberIDs, id)
+		}
+	}
+	slices.Sort(memberIDs)
+
+	return &RoleDetailOutput{
+		Name:                 in.Role,
+		Permissions:          permissions,
+		EffectivePermissions: effective,
+		MemberIDs:            memberIDs,
+	}, nil
+}
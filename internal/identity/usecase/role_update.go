@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type RoleUpdateInput struct {
+	Role        string           `validate:"required,max=64"`
+	Permissions []RolePermission `validate:"required,min=1,dive"`
+}
+
+// RoleUpdate replaces every permission granted directly to the role in one adapter call.
+// Members keep the role.
+func (s *Usecase) RoleUpdate(ctx context.Context, in RoleUpdateInput) error {
+	ctx, span := s.startSpan(ctx, "RoleUpdate")
+	defer span.End()
+
+	in.Role = normalizeRoleName(in.Role)
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtRoles, constant.PermActUpdate)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ensureRoleExists(ctx, in.Role); err != nil {
+		return err
+	}
+
+	before, err := s.rolePermissions(in.Role)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get role permissions", "role", in.Role, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	rules := rolePolicies(in.Role, in.Permissions)
+	if len(before) == 0 {
+		_, err = s.enforcer.AddPolicies(rules)
+	} else {
+		_, err = s.enforcer.UpdateFilteredPolicies(rules, 0, in.Role)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to replace role policies", "role", in.Role, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.auditRoleChange(ctx, clm.UserID, 0, entity.AuditActionRoleUpdate, valueobject.JSONMap{
+		"role":        in.Role,
+		"before":      before,
+		"permissions": rules,
+	})
+
+	return nil
+}
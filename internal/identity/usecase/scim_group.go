@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type (
+	// SCIMGroup is a role an identity provider manages the membership of.
+	SCIMGroup struct {
+		Name    string
+		Members []int64
+	}
+
+	SCIMGroupListInput struct {
+		// DisplayName is the value of a `displayName eq "..."` filter, empty for no filter.
+		DisplayName string
+	}
+
+	SCIMGroupListOutput struct {
+		Groups []SCIMGroup
+	}
+
+	SCIMGroupIDInput struct {
+		ID string `validate:"required,max=64"`
+	}
+
+	SCIMGroupCreateInput struct {
+		DisplayName string `validate:"required,max=64"`
+		Members     []int64
+	}
+
+	// SCIMGroupUpdateInput carries a PUT or PATCH on the members of a group. With
+	// ReplaceMembers set, Members becomes the whole membership; Add and Remove apply after.
+	SCIMGroupUpdateInput struct {
+		ID             string `validate:"required,max=64"`
+		ReplaceMembers bool
+		Members        []int64
+		Add            []int64
+		Remove         []int64
+	}
+)
+
+// SCIMGroupList returns the provisionable roles, modules.identity.scim.groups, as SCIM groups.
+func (s *Usecase) SCIMGroupList(ctx context.Context, in SCIMGroupListInput) (*SCIMGroupListOutput, error) {
+	ctx, span := s.startSpan(ctx, "SCIMGroupList")
+	defer span.End()
+
+	if _, err := s.scimAuthorized(ctx, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	filter := normalizeRoleName(in.DisplayName)
+
+	groups := make([]SCIMGroup, 0)
+	for _, name := range s.scimGroups() {
+		if filter != "" && name != filter {
+			continue
+		}
+
+		group, err := s.scimGroup(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *group)
+	}
+
+	return &SCIMGroupListOutput{Groups: groups}, nil
+}
+
+func (s *Usecase) SCIMGroupGet(ctx context.Context, in SCIMGroupIDInput) (*SCIMGroup, error) {
+	ctx, span := s.startSpan(ctx, "SCIMGroupGet")
+	defer span.End()
+
+	in.ID = normalizeRoleName(in.ID)
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if _, err := s.scimAuthorized(ctx, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	if !s.isSCIMGroup(in.ID) {
+		return nil, goerror.NewBusiness("group not found", goerror.CodeNotFound)
+	}
+
+	return s.scimGroup(ctx, in.ID)
+}
+
+// SCIMGroupCreate links a provisionable role to the identity provider and sets its members.
+// Roles carry permissions only admins grant, so an IdP cannot create new ones.
+func (s *Usecase) SCIMGroupCreate(ctx context.Context, in SCIMGroupCreateInput) (*SCIMGroup, error) {
+	ctx, span := s.startSpan(ctx, "SCIMGroupCreate")
+	defer span.End()
+
+	in.DisplayName = normalizeRoleName(in.DisplayName)
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.scimAuthorized(ctx, constant.PermActCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isSCIMGroup(in.DisplayName) {
+		return nil, goerror.NewBusiness("only the roles in modules.identity.scim.groups can be provisioned", goerror.CodeForbidden)
+	}
+
+	return s.updateSCIMGroup(ctx, clm.UserID, SCIMGroupUpdateInput{
+		ID:             in.DisplayName,
+		ReplaceMembers: true,
+		Members:        in.Members,
+	})
+}
+
+// SCIMGroupUpdate changes the members of a provisionable role.
+func (s *Usecase) SCIMGroupUpdate(ctx context.Context, in SCIMGroupUpdateInput) (*SCIMGroup, error) {
+	ctx, span := s.startSpan(ctx, "SCIMGroupUpdate")
+	defer span.End()
+
+	in.ID = normalizeRoleName(in.ID)
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.scimAuthorized(ctx, constant.PermActUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isSCIMGroup(in.ID) {
+		return nil, goerror.NewBusiness("group not found", goerror.CodeNotFound)
+	}
+
+	return s.updateSCIMGroup(ctx, clm.UserID, in)
+}
+
+// SCIMGroupDelete unlinks a provisionable role from the identity provider by removing all its
+// members. The role and its permissions stay.
+func (s *Usecase) SCIMGroupDelete(ctx context.Context, in SCIMGroupIDInput) error {
+	ctx, span := s.startSpan(ctx, "SCIMGroupDelete")
+	defer span.End()
+
+	in.ID = normalizeRoleName(in.ID)
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.scimAuthorized(ctx, constant.PermActDelete)
+	if err != nil {
+		return err
+	}
+
+	if !s.isSCIMGroup(in.ID) {
+		return goerror.NewBusiness("group not found", goerror.CodeNotFound)
+	}
+
+	_, err = s.updateSCIMGroup(ctx, clm.UserID, SCIMGroupUpdateInput{ID: in.ID, ReplaceMembers: true})
+	return err
+}
+
+// updateSCIMGroup assigns and unassigns the role until its members match what in asks for.
+func (s *Usecase) updateSCIMGroup(ctx context.Context, actorID int64, in SCIMGroupUpdateInput) (*SCIMGroup, error) {
+	current, err := s.roleMemberIDs(in.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get role members", "role", in.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	want := slices.Clone(current)
+	if in.ReplaceMembers {
+		want = slices.Clone(in.Members)
+	}
+	want = append(want, in.Add...)
+	want = slices.DeleteFunc(want, func(id int64) bool { return slices.Contains(in.Remove, id) })
+	slices.Sort(want)
+	want = slices.Compact(want)
+
+	var added, removed []int64
+	for _, id := range want {
+		if !slices.Contains(current, id) {
+			added = append(added, id)
+		}
+	}
+	for _, id := range current {
+		if !slices.Contains(want, id) {
+			removed = append(removed, id)
+		}
+	}
+
+	for _, id := range added {
+		if _, err := s.repoDB.GetUserByID(ctx, id, false); errors.Is(err, goerror.ErrNotFound) {
+			return nil, goerror.NewBusiness("member "+strconv.FormatInt(id, 10)+" not found", goerror.CodeInvalidInput)
+		} else if err != nil {
+			slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", id, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+	}
+
+	for _, id := range append(slices.Clone(added), removed...) {
+		if err := s.ensureSCIMManaged(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, id := range added {
+		if _, err := s.enforcer.AddGroupingPolicy(strconv.FormatInt(id, 10), in.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to assign role", "role", in.ID, "user_id", id, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+		s.auditRoleChange(ctx, actorID, id, entity.AuditActionRoleMemberAdd, valueobject.JSONMap{
+			"role":   in.ID,
+			"source": "scim",
+		})
+	}
+
+	for _, id := range removed {
+		if _, err := s.enforcer.RemoveGroupingPolicy(strconv.FormatInt(id, 10), in.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to unassign role", "role", in.ID, "user_id", id, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+		s.auditRoleChange(ctx, actorID, id, entity.AuditActionRoleMemberRemove, valueobject.JSONMap{
+			"role":   in.ID,
+			"source": "scim",
+		})
+	}
+
+	return &SCIMGroup{Name: in.ID, Members: want}, nil
+}
+
+// scimGroups returns the roles configured as provisionable, leaving out protected roles
+// an identity provider must never hand out.
+func (s *Usecase) scimGroups() []string {
+	var names []string
+	for _, name := range s.cfg.GetArray("modules.identity.scim.groups") {
+		name = normalizeRoleName(name)
+		if validateRoleName(name) != nil || s.isProtectedRole(name) || slices.Contains(names, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func (s *Usecase) isSCIMGroup(name string) bool {
+	return slices.Contains(s.scimGroups(), name)
+}
+
+func (s *Usecase) scimGroup(ctx context.Context, name string) (*SCIMGroup, error) {
+	members, err := s.roleMemberIDs(name)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get role members", "role", name, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &SCIMGroup{Name: name, Members: members}, nil
+}
+
+// roleMemberIDs returns the users assigned role, sorted; service accounts are left out.
+func (s *Usecase) roleMemberIDs(role string) ([]int64, error) {
+	rules, err := s.enforcer.GetFilteredGroupingPolicy(1, role)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(rules))
+	for _, rule := range rules {
+		if id, err := strconv.ParseInt(rule[0], 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	return ids, nil
+}
@@ -0,0 +1,386 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+const (
+	// scimMaxCount caps a SCIM page; IdPs page with count and startIndex.
+	scimMaxCount = 100
+	// scimProvider is the connection provider marking the users SCIM provisioned, which are
+	// the only ones it may change.
+	scimProvider = "scim"
+)
+
+type (
+	SCIMUserListInput struct {
+		// UserName is the value of a `userName eq "..."` filter, empty for no filter.
+		UserName   string
+		StartIndex int32
+		Count      int32
+	}
+
+	SCIMUserListOutput struct {
+		Users      []entity.User
+		Total      int64
+		StartIndex int32
+	}
+
+	SCIMUserIDInput struct {
+		ID int64 `validate:"required,gt=0"`
+	}
+
+	SCIMUserCreateInput struct {
+		UserName string `validate:"required,email"`
+		FullName string `validate:"required,max=100"`
+		Active   bool
+	}
+
+	// SCIMUserUpdateInput carries the attributes a PUT or PATCH sets; nil leaves one as it is.
+	SCIMUserUpdateInput struct {
+		ID       int64   `validate:"required,gt=0"`
+		UserName *string `validate:"omitempty,email"`
+		FullName *string `validate:"omitempty,max=100"`
+		Active   *bool
+	}
+)
+
+// SCIMUserList lists users for an identity provider, optionally filtered to one userName.
+func (s *Usecase) SCIMUserList(ctx context.Context, in SCIMUserListInput) (*SCIMUserListOutput, error) {
+	ctx, span := s.startSpan(ctx, "SCIMUserList")
+	defer span.End()
+
+	if _, err := s.scimAuthorized(ctx, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	in.StartIndex = max(in.StartIndex, 1)
+	if in.Count < 0 || in.Count > scimMaxCount {
+		in.Count = scimMaxCount
+	}
+
+	if userName := strings.TrimSpace(strings.ToLower(in.UserName)); userName != "" {
+		out := &SCIMUserListOutput{Users: []entity.User{}, StartIndex: in.StartIndex}
+
+		user, err := s.getUserByEmail(ctx, userName, false)
+		if errors.Is(err, goerror.ErrNotFound) {
+			return out, nil
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo get user by email", "error", err)
+			return nil, goerror.NewServer(err)
+		}
+
+		out.Total = 1
+		if in.StartIndex == 1 && in.Count > 0 {
+			out.Users = append(out.Users, *user)
+		}
+		return out, nil
+	}
+
+	users, total, err := s.repoDB.GetUserList(ctx, entity.UserListFilterData{
+		Size: in.Count,
+		Page: in.StartIndex - 1,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo list users", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &SCIMUserListOutput{Users: users, Total: total, StartIndex: in.StartIndex}, nil
+}
+
+func (s *Usecase) SCIMUserGet(ctx context.Context, in SCIMUserIDInput) (*entity.User, error) {
+	ctx, span := s.startSpan(ctx, "SCIMUserGet")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if _, err := s.scimAuthorized(ctx, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	return s.scimUser(ctx, in.ID)
+}
+
+// SCIMUserCreate provisions a user. The password is random and never shared: the user
+// signs in through the identity provider, or sets one with the forgot password flow.
+func (s *Usecase) SCIMUserCreate(ctx context.Context, in SCIMUserCreateInput) (*entity.User, error) {
+	ctx, span := s.startSpan(ctx, "SCIMUserCreate")
+	defer span.End()
+
+	in.UserName = strings.TrimSpace(strings.ToLower(in.UserName))
+	in.FullName = strings.TrimSpace(in.FullName)
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.scimAuthorized(ctx, constant.PermActCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.getUserByEmail(ctx, in.UserName, true)
+	if err == nil {
+		return nil, goerror.NewBusiness("user account with that userName already exists", goerror.CodeConflict)
+	}
+	if !errors.Is(err, goerror.ErrNotFound) {
+		slog.ErrorContext(ctx, "failed to repo get user by email", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	hashedPassword, err := s.bcrypt.Hash(s.oid.Generate())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash password", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	newUser := entity.NewUser{
+		ID:        s.uid.Generate(),
+		Email:     s.normalizeEmail(in.UserName),
+		FullName:  in.FullName,
+		AvatarURL: "https://ui-avatars.com/api/?name=" + url.QueryEscape(in.FullName),
+		Status:    scimStatus(in.Active),
+		CreatedBy: clm.UserID,
+		UpdatedBy: clm.UserID,
+	}
+
+	newUser.EmailHash, newUser.EmailSealedOnly, err = s.emailLookupHash(newUser.Email)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash email", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	conn := entity.UserConnection{
+		ID:             s.uid.Generate(),
+		UserID:         newUser.ID,
+		Provider:       scimProvider,
+		ProviderUserID: strconv.FormatInt(newUser.ID, 10),
+	}
+	if err := s.repoDB.NewConnectedUser(ctx, newUser, string(hashedPassword), conn); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create connected user", "user_id", newUser.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionUserCreate, clm.UserID, newUser.ID, map[string]any{
+		"status": newUser.Status.String(),
+		"source": "scim",
+	})
+
+	return s.scimUser(ctx, newUser.ID)
+}
+
+// SCIMUserUpdate applies a SCIM PUT or PATCH to a user SCIM provisioned. Deactivating a user
+// also ends their sessions, which is how an identity provider deprovisions without deleting.
+// A new userName goes through the email change flow: the address only changes once its
+// owner confirms the link sent there.
+func (s *Usecase) SCIMUserUpdate(ctx context.Context, in SCIMUserUpdateInput) (*entity.User, error) {
+	ctx, span := s.startSpan(ctx, "SCIMUserUpdate")
+	defer span.End()
+
+	if in.UserName != nil {
+		userName := strings.TrimSpace(strings.ToLower(*in.UserName))
+		in.UserName = &userName
+	}
+	if in.FullName != nil {
+		fullName := strings.TrimSpace(*in.FullName)
+		in.FullName = &fullName
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.scimAuthorized(ctx, constant.PermActUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.scimManagedUser(ctx, in.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := entity.PatchUser{ID: user.ID, UpdatedBy: clm.UserID}
+
+	newEmail := ""
+	if in.UserName != nil && *in.UserName != "" && s.normalizeEmail(*in.UserName) != s.normalizeEmail(user.Email) {
+		newEmail = s.normalizeEmail(*in.UserName)
+		if _, err := s.getUserByEmail(ctx, newEmail, true); err == nil {
+			return nil, goerror.NewBusiness("user account with that userName already exists", goerror.CodeConflict)
+		} else if !errors.Is(err, goerror.ErrNotFound) {
+			slog.ErrorContext(ctx, "failed to repo get user by email", "error", err)
+			return nil, goerror.NewServer(err)
+		}
+	}
+
+	if in.FullName != nil && *in.FullName != "" && *in.FullName != user.FullName {
+		patch.FullName = *in.FullName
+		patch.AvatarURL = "https://ui-avatars.com/api/?name=" + url.QueryEscape(*in.FullName)
+	}
+
+	if in.Active != nil {
+		switch {
+		case !*in.Active && user.Status != entity.UserStatusInactive:
+			patch.Status = entity.UserStatusInactive
+		// reactivating only lifts a deactivation; a ban stays with the admins who set it
+		case *in.Active && user.Status == entity.UserStatusInactive:
+			patch.Status = entity.UserStatusActive
+		}
+	}
+
+	if newEmail == "" && patch.FullName == "" && patch.Status == entity.UserStatusUnknown {
+		return user, nil
+	}
+
+	if patch.FullName != "" || patch.Status != entity.UserStatusUnknown {
+		if err := s.repoDB.PatchUser(ctx, patch, ""); err != nil {
+			slog.ErrorContext(ctx, "failed to repo patch user", "user_id", user.ID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+	}
+
+	if newEmail != "" {
+		if err := s.requestEmailChange(ctx, user.ID, newEmail); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.Status == entity.UserStatusInactive {
+		if err := s.repoDB.RevokeAllRefreshToken(ctx, user.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to repo revoke all refresh token", "user_id", user.ID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+		s.revokeUserAccessTokens(ctx, user.ID)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionUserUpdate, clm.UserID, user.ID, map[string]any{
+		"email_requested": newEmail != "",
+		"name_changed":    patch.FullName != "",
+		"status_changed":  patch.Status != entity.UserStatusUnknown,
+		"source":          "scim",
+	})
+
+	return s.scimUser(ctx, user.ID)
+}
+
+// SCIMUserDelete deprovisions a user SCIM provisioned: their sessions end and the account is
+// marked deleted, which queues it for anonymization like any other deletion.
+func (s *Usecase) SCIMUserDelete(ctx context.Context, in SCIMUserIDInput) error {
+	ctx, span := s.startSpan(ctx, "SCIMUserDelete")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.scimAuthorized(ctx, constant.PermActDelete)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.scimManagedUser(ctx, in.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repoDB.RevokeAllRefreshToken(ctx, user.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to repo revoke all refresh token", "user_id", user.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.MarkUserDeleted(ctx, user.ID, clm.UserID); err != nil {
+		slog.ErrorContext(ctx, "failed to mark user deleted", "user_id", user.ID, "by_user_id", clm.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.revokeUserAccessTokens(ctx, user.ID)
+
+	s.recordAudit(ctx, entity.AuditActionUserDelete, clm.UserID, user.ID, map[string]any{"source": "scim"})
+
+	return nil
+}
+
+// scimAuthorized checks the caller holds act on identity:scim while provisioning is enabled.
+func (s *Usecase) scimAuthorized(ctx context.Context, act string) (*jwt.Claims, error) {
+	if !s.cfg.GetBool("modules.identity.scim.enabled") {
+		return nil, goerror.NewBusiness("SCIM provisioning is disabled", goerror.CodeNotFound)
+	}
+
+	return s.authenticatedAndAuthorized(ctx, constant.PermIdentitySCIM, act)
+}
+
+// scimUser returns a user that is not deleted, as SCIM only sees those.
+func (s *Usecase) scimUser(ctx context.Context, id int64) (*entity.User, error) {
+	user, err := s.repoDB.GetUserByID(ctx, id, false)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return nil, goerror.NewBusiness("user not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", id, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return user, nil
+}
+
+// scimManagedUser returns a user SCIM may change: one it provisioned, holding no protected
+// role. Accounts created any other way, admins above all, stay with the admins.
+func (s *Usecase) scimManagedUser(ctx context.Context, id int64) (*entity.User, error) {
+	user, err := s.scimUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureSCIMManaged(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *Usecase) ensureSCIMManaged(ctx context.Context, userID int64) error {
+	conns, err := s.repoDB.GetUserConnections(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user connections", "user_id", userID, "error", err)
+		return goerror.NewServer(err)
+	}
+	if !slices.ContainsFunc(conns, func(c entity.UserConnection) bool { return c.Provider == scimProvider }) {
+		slog.WarnContext(ctx, "SCIM tried to change a user it did not provision", "user_id", userID)
+		return goerror.NewBusiness("user is not managed by SCIM", goerror.CodeForbidden)
+	}
+
+	roles, err := s.enforcer.GetRolesForUser(strconv.FormatInt(userID, 10))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get user roles", "user_id", userID, "error", err)
+		return goerror.NewServer(err)
+	}
+	if slices.ContainsFunc(roles, s.isProtectedRole) {
+		slog.WarnContext(ctx, "SCIM tried to change a user holding a protected role", "user_id", userID)
+		return goerror.NewBusiness("user holds a protected role", goerror.CodeForbidden)
+	}
+
+	return nil
+}
+
+// scimStatus maps the SCIM active flag to a user status.
+func scimStatus(active bool) entity.UserStatus {
+	if active {
+		return entity.UserStatusActive
+	}
+	return entity.UserStatusInactive
+}
@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+const (
+	// grantTypeClientCredentials is the only OAuth2 grant served by ClientCredentialsToken.
+	grantTypeClientCredentials = "client_credentials"
+	// serviceAccountClientIDPrefix starts every generated client ID.
+	serviceAccountClientIDPrefix = "sa_"
+	// serviceAccountSecretPrefix starts every client secret so leaked secrets are easy to scan for.
+	serviceAccountSecretPrefix = "gbs_"
+)
+
+type (
+	ServiceAccountCreateInput struct {
+		Name  string   `validate:"required,min=3,max=100"`
+		Roles []string `validate:"dive,required,max=64"`
+	}
+
+	ServiceAccountCreateOutput struct {
+		ServiceAccount entity.ServiceAccount
+		ClientSecret   string
+	}
+)
+
+// ServiceAccountCreate registers a service account with the given roles, which may not be
+// protected ones. The client secret is returned once and only its hash is stored.
+func (s *Usecase) ServiceAccountCreate(ctx context.Context, in ServiceAccountCreateInput) (*ServiceAccountCreateOutput, error) {
+	ctx, span := s.startSpan(ctx, "ServiceAccountCreate")
+	defer span.End()
+
+	in.Name = strings.TrimSpace(in.Name)
+	for i, role := range in.Roles {
+		in.Roles[i] = normalizeRoleName(role)
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtServiceAccounts, constant.PermActCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := slices.Clone(in.Roles)
+	slices.Sort(roles)
+	roles = slices.Compact(roles)
+
+	for _, role := range roles {
+		exists, err := s.roleExists(role)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to check role", "role", role, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+		if !exists {
+			return nil, goerror.NewBusiness("role "+role+" does not exist", goerror.CodeInvalidInput)
+		}
+		// protected roles stay with people, as with SAML and SCIM grants
+		if s.isProtectedRole(role) {
+			slog.WarnContext(ctx, "service account requested with a protected role", "role", role, "by_user_id", clm.UserID)
+			return nil, goerror.NewBusiness("role "+role+" is protected", goerror.CodeForbidden)
+		}
+	}
+
+	clientID, err := randomHex(16)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate client id", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	clientID = serviceAccountClientIDPrefix + clientID
+
+	secret, err := randomHex(32)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate client secret", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	secret = serviceAccountSecretPrefix + secret
+
+	secretHash, err := s.hmac.Hash(secret)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash client secret", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	sa := entity.ServiceAccount{
+		ID:        s.uid.Generate(),
+		Name:      in.Name,
+		ClientID:  clientID,
+		Roles:     roles,
+		CreatedBy: clm.UserID,
+		CreatedAt: s.clock.Now(),
+	}
+
+	if err := s.repoDB.CreateServiceAccount(ctx, sa, string(secretHash)); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create service account", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if len(roles) > 0 {
+		rules := make([][]string, 0, len(roles))
+		for _, role := range roles {
+			rules = append(rules, []string{jwt.ClientSubject(clientID), role})
+		}
+
+		if _, err := s.enforcer.AddGroupingPolicies(rules); err != nil {
+			slog.ErrorContext(ctx, "failed to add service account roles", "service_account_id", sa.ID, "error", err)
+			// an account without its roles is useless, so it is not handed out
+			if _, derr := s.repoDB.DeleteServiceAccount(ctx, sa.ID); derr != nil {
+				slog.ErrorContext(ctx, "failed to repo delete service account", "service_account_id", sa.ID, "error", derr)
+			}
+			return nil, goerror.NewServer(err)
+		}
+	}
+
+	s.recordAudit(ctx, entity.AuditActionServiceAccountCreate, clm.UserID, sa.ID, map[string]any{
+		"name":      sa.Name,
+		"client_id": sa.ClientID,
+		"roles":     roles,
+	})
+
+	return &ServiceAccountCreateOutput{ServiceAccount: sa, ClientSecret: secret}, nil
+}
+
+type ServiceAccountListOutput struct {
+	ServiceAccounts []entity.ServiceAccount
+}
+
+// ServiceAccountList returns every service account with its roles.
+func (s *Usecase) ServiceAccountList(ctx context.Context) (*ServiceAccountListOutput, error) {
+	ctx, span := s.startSpan(ctx, "ServiceAccountList")
+	defer span.End()
+
+	if _, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtServiceAccounts, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.repoDB.GetServiceAccounts(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get service accounts", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	for i := range accounts {
+		roles, err := s.enforcer.GetRolesForUser(jwt.ClientSubject(accounts[i].ClientID))
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get service account roles", "service_account_id", accounts[i].ID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+		slices.Sort(roles)
+		accounts[i].Roles = roles
+	}
+
+	return &ServiceAccountListOutput{ServiceAccounts: accounts}, nil
+}
+
+type ServiceAccountDeleteInput struct {
+	ID int64 `validate:"required,gt=0"`
+}
+
+// ServiceAccountDelete removes a service account and its roles. Tokens already issued to it
+// keep authenticating until they expire, but are no longer allowed anything.
+func (s *Usecase) ServiceAccountDelete(ctx context.Context, in ServiceAccountDeleteInput) error {
+	ctx, span := s.startSpan(ctx, "ServiceAccountDelete")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtServiceAccounts, constant.PermActDelete)
+	if err != nil {
+		return err
+	}
+
+	clientID, err := s.repoDB.DeleteServiceAccount(ctx, in.ID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("service account not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete service account", "service_account_id", in.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if _, err := s.enforcer.DeleteUser(jwt.ClientSubject(clientID)); err != nil {
+		slog.ErrorContext(ctx, "failed to delete service account roles", "service_account_id", in.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionServiceAccountDelete, clm.UserID, in.ID, map[string]any{
+		"client_id": clientID,
+	})
+
+	return nil
+}
+
+type (
+	ClientCredentialsTokenInput struct {
+		GrantType    string `validate:"required"`
+		ClientID     string `validate:"required,max=64"`
+		ClientSecret string `validate:"required,max=128"`
+	}
+
+	ClientCredentialsTokenOutput struct {
+		AccessToken string
+		ExpiresIn   int64 // seconds
+	}
+)
+
+// ClientCredentialsToken implements the OAuth2 client_credentials grant: a service account
+// trades its client ID and secret for an access token. No refresh token is issued; the
+// client asks again when the token expires.
+func (s *Usecase) ClientCredentialsToken(ctx context.Context, in ClientCredentialsTokenInput) (*ClientCredentialsTokenOutput, error) {
+	ctx, span := s.startSpan(ctx, "ClientCredentialsToken")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if in.GrantType != grantTypeClientCredentials {
+		return nil, goerror.NewBusiness("unsupported grant_type", goerror.CodeInvalidInput)
+	}
+
+	sa, err := s.repoDB.GetServiceAccountByClientID(ctx, in.ClientID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "service account not found", "client_id", in.ClientID)
+		return nil, goerror.NewBusiness("invalid client credentials", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get service account by client id", "client_id", in.ClientID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	secretHash, err := s.hmac.Hash(in.ClientSecret)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash client secret", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if !hmac.Equal(secretHash, []byte(sa.Secret)) {
+		slog.WarnContext(ctx, "service account secret mismatch", "client_id", in.ClientID)
+		return nil, goerror.NewBusiness("invalid client credentials", goerror.CodeUnauthorized)
+	}
+
+	ttl := s.cfg.GetMinute("modules.identity.service_account.token_ttl_minutes")
+	token, err := s.jwt.Generate(jwt.WithClient(jwt.WithTTL(ctx, ttl), sa.ClientID), 0, "")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate service account token", "client_id", sa.ClientID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.UpdateServiceAccountLastUsedAt(ctx, sa.ID); err != nil {
+		slog.WarnContext(ctx, "failed to repo update service account last used at", "service_account_id", sa.ID, "error", err)
+	}
+
+	out := &ClientCredentialsTokenOutput{AccessToken: token}
+	if ttl > 0 {
+		out.ExpiresIn = int64(ttl.Seconds())
+	} else {
+		out.ExpiresIn = int64(s.cfg.GetMinute("jwt.ttl_minutes").Seconds())
+	}
+
+	return out, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
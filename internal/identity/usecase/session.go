@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+type (
+	ListSessionsOutput struct {
+		Sessions []entity.Session
+	}
+
+	RevokeSessionInput struct {
+		ID int64 `validate:"required,gt=0"`
+	}
+)
+
+// ListSessions returns the active sessions (unrevoked, unexpired refresh tokens) of the
+// authenticated user, most recently used first.
+func (s *Usecase) ListSessions(ctx context.Context) (*ListSessionsOutput, error) {
+	ctx, span := s.startSpan(ctx, "ListSessions")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	sessions, err := s.repoDB.GetActiveSessions(ctx, clm.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get active sessions", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &ListSessionsOutput{Sessions: sessions}, nil
+}
+
+// RevokeSession revokes a single session of the authenticated user. The client holding its
+// refresh token is signed out once its access token expires.
+func (s *Usecase) RevokeSession(ctx context.Context, in RevokeSessionInput) error {
+	ctx, span := s.startSpan(ctx, "RevokeSession")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	err := s.repoDB.RevokeSession(ctx, in.ID, clm.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("session not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo revoke session", "user_id", clm.UserID, "session_id", in.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	return nil
+}
+
+// sessionMetadata describes the client a refresh token is issued to.
+func sessionMetadata(ip, userAgent, client, deviceName string) entity.SessionMetadata {
+	if len(userAgent) > entity.MaxSessionUserAgentLength {
+		userAgent = userAgent[:entity.MaxSessionUserAgentLength]
+	}
+
+	deviceName = strings.TrimSpace(deviceName)
+	if utf8.RuneCountInString(deviceName) > entity.MaxSessionDeviceNameLength {
+		deviceName = string([]rune(deviceName)[:entity.MaxSessionDeviceNameLength])
+	}
+
+	return entity.SessionMetadata{IP: ip, UserAgent: userAgent, Client: normalizeClientType(client), DeviceName: deviceName}
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+)
+
+// enforceSessionLimit revokes the user's oldest active sessions once they hold more refresh
+// tokens than their limit allows. It runs after a new session is created and never fails the login.
+func (s *Usecase) enforceSessionLimit(ctx context.Context, userID int64) {
+	limit := s.sessionLimit(ctx, userID)
+	if limit <= 0 {
+		return
+	}
+
+	revoked, err := s.repoDB.RevokeRefreshTokenOverLimit(ctx, userID, int32(min(limit, math.MaxInt32)))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo revoke refresh token over limit", "user_id", userID, "limit", limit, "error", err)
+		return
+	}
+	if revoked == 0 {
+		return
+	}
+
+	slog.InfoContext(ctx, "session limit reached, oldest sessions revoked", "user_id", userID, "limit", limit, "revoked", revoked)
+
+	user, err := s.repoDB.GetUserByID(ctx, userID, false)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", userID, "error", err)
+		return
+	}
+
+	if err := s.repoMessaging.PublishUserSessionRevoked(ctx, contracts.SessionRevoked{
+		UserID:       user.ID,
+		Email:        user.Email,
+		FullName:     user.FullName,
+		RevokedCount: revoked,
+		Limit:        limit,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to publish user session revoked", "user_id", userID, "error", err)
+	}
+}
+
+// sessionLimit resolves the cap for a user: the most generous limit among their roles wins,
+// a role limit of 0 means unlimited, and users without a configured role get the default.
+func (s *Usecase) sessionLimit(ctx context.Context, userID int64) int {
+	if !s.cfg.GetBool("modules.identity.session_limit.enabled") {
+		return 0
+	}
+
+	limit := s.cfg.GetInt("modules.identity.session_limit.default")
+
+	roleLimits := s.cfg.GetMap("modules.identity.session_limit.roles")
+	if len(roleLimits) == 0 {
+		return limit
+	}
+
+	roles, err := s.enforcer.GetRolesForUser(strconv.FormatInt(userID, 10))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get roles for session limit", "user_id", userID, "error", err)
+		return limit
+	}
+
+	matched := false
+	best := 0
+	for _, role := range roles {
+		raw, ok := roleLimits[role]
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			slog.WarnContext(ctx, "invalid session limit for role", "role", role, "value", raw)
+			continue
+		}
+		if n <= 0 {
+			return 0
+		}
+
+		matched = true
+		best = max(best, n)
+	}
+
+	if matched {
+		return best
+	}
+
+	return limit
+}
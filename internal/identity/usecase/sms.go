@@ -0,0 +1,433 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/pkg/mfa"
+)
+
+// ReasonSMSRateLimited is returned when a user asks for SMS codes too often.
+const ReasonSMSRateLimited = "SMS_RATE_LIMITED"
+
+type SMSSetupInput struct {
+	PhoneNumber     string `validate:"required,e164"`
+	CurrentPassword string `validate:"required"`
+}
+
+type SMSSetupOutput struct {
+	ChallengeToken string
+	Destination    string
+}
+
+// SMSSetup texts a confirmation code to the phone number of a new SMS factor. A newer
+// request replaces any pending one.
+func (s *Usecase) SMSSetup(ctx context.Context, in SMSSetupInput) (*SMSSetupOutput, error) {
+	ctx, span := s.startSpan(ctx, "SMSSetup")
+	defer span.End()
+
+	in.PhoneNumber = strings.TrimSpace(in.PhoneNumber)
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if !s.repoSMS.Enabled() {
+		return nil, goerror.NewBusiness("SMS verification is not available", goerror.CodeInvalidInput)
+	}
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	user, err := s.repoDB.GetUserCredentialInfo(ctx, clm.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user account not found", "user_id", clm.UserID)
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if !s.bcrypt.Verify(user.Password, in.CurrentPassword) {
+		slog.WarnContext(ctx, "password user account not match", "user_id", user.ID)
+		return nil, goerror.NewBusiness("invalid password", goerror.CodeUnauthorized)
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, user.ID, user.Status); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureNoSMSFactor(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSMSThrottle(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	encryptedPhone, err := s.mfaEncryptor.Encrypt([]byte(in.PhoneNumber), mfa.Scope{
+		UserID:  user.ID,
+		Purpose: mfa.PurposePhone,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encrypt phone number", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	code, codeHash, err := s.newSMSCode()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate sms code", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if _, err := s.repoDB.DeleteChallengeByUserPurpose(ctx, user.ID, entity.ChallengePurposeMFASMSSetupConfirm); err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete pending sms setup", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	cToken := s.oid.Generate()
+	cTokenHash, err := s.hmac.Hash(cToken)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token challange", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	destination := maskPhoneNumber(in.PhoneNumber)
+	meta, err := entity.EncodeMetadata(&entity.SMSSetupMetadata{
+		Phone:        base64.StdEncoding.EncodeToString(encryptedPhone),
+		Code:         codeHash,
+		FriendlyName: destination,
+		KeyVersion:   1, // can be use config later
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode sms setup metadata", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.CreateChallenge(ctx, entity.Challenge{
+		ID:        s.uid.Generate(),
+		UserID:    user.ID,
+		Token:     string(cTokenHash),
+		Purpose:   entity.ChallengePurposeMFASMSSetupConfirm,
+		ExpiresAt: s.clock.Now().Add(s.cfg.GetMinute("mfa.sms.code_ttl_minutes")),
+		Metadata:  meta,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to create sms setup challenge", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.sendSMSCode(ctx, user.ID, in.PhoneNumber, code); err != nil {
+		return nil, err
+	}
+
+	return &SMSSetupOutput{
+		ChallengeToken: cToken,
+		Destination:    destination,
+	}, nil
+}
+
+type SMSConfirmInput struct {
+	ChallengeToken string `validate:"required"`
+	Code           string `validate:"required,len=6,numeric"`
+}
+
+// SMSConfirm activates the SMS factor once the texted code is echoed back.
+func (s *Usecase) SMSConfirm(ctx context.Context, in SMSConfirmInput) error {
+	ctx, span := s.startSpan(ctx, "SMSConfirm")
+	defer span.End()
+
+	in.Code = strings.TrimSpace(in.Code)
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	cTokenHash, err := s.hmac.Hash(in.ChallengeToken)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token challange", "error", err)
+		return goerror.NewServer(err)
+	}
+
+	cu, err := s.repoDB.GetChallengeUserByTokenPurpose(ctx, string(cTokenHash), entity.ChallengePurposeMFASMSSetupConfirm)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "challenge user not found", "challenge_token", string(cTokenHash))
+		return goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get challange user by token purpose", "challenge_token", string(cTokenHash), "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, cu.UserID, cu.UserStatus); err != nil {
+		return err
+	}
+
+	if cu.UserID != clm.UserID {
+		slog.WarnContext(ctx, "challenge user mismatch", "user_id", clm.UserID, "challenge_user_id", cu.UserID)
+		return goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
+	}
+
+	meta, err := entity.DecodeMetadata[entity.SMSSetupMetadata](cu.ChallengeMetadata)
+	if err != nil {
+		slog.WarnContext(ctx, "invalid sms setup challenge metadata", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
+	}
+
+	phoneCiphertext, err := base64.StdEncoding.DecodeString(meta.Phone)
+	if err != nil || len(phoneCiphertext) == 0 {
+		slog.WarnContext(ctx, "challenge missing sms phone", "user_id", cu.UserID, "challenge_id", cu.ChallengeID)
+		return goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
+	}
+
+	// a wrong guess burns the challenge once the attempts run out, so six digits cannot be enumerated
+	wait, err := s.throttle.Hit(ctx, "mfa:sms:confirm:"+strconv.FormatInt(cu.ChallengeID, 10),
+		s.cfg.GetInt("mfa.sms.max_attempts"), s.cfg.GetMinute("mfa.sms.code_ttl_minutes"))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to check sms confirm throttle", "user_id", cu.UserID, "error", err)
+	}
+	if wait > 0 {
+		slog.WarnContext(ctx, "sms confirm attempts exhausted", "user_id", cu.UserID, "challenge_id", cu.ChallengeID)
+		return goerror.NewBusiness("invalid code session", goerror.CodeUnauthorized)
+	}
+
+	if !s.hmac.Verify(meta.Code, in.Code) {
+		slog.WarnContext(ctx, "invalid sms code", "user_id", cu.UserID, "challenge_id", cu.ChallengeID)
+		return goerror.NewBusiness("invalid code session", goerror.CodeUnauthorized)
+	}
+
+	if err := s.ensureNoSMSFactor(ctx, cu.UserID); err != nil {
+		return err
+	}
+
+	factor := entity.MFAFactor{
+		ID:           s.uid.Generate(),
+		UserID:       cu.UserID,
+		Type:         entity.MFATypeSMS,
+		FriendlyName: meta.FriendlyName,
+		Secret:       phoneCiphertext,
+		KeyVersion:   int16(meta.KeyVersion),
+		IsVerified:   true,
+	}
+
+	if err := s.repoDB.NewMFAFactor(ctx, factor, cu.ChallengeID); err != nil {
+		slog.ErrorContext(ctx, "failed to repo new mfa factor sms", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionMFASMSEnable, clm.UserID, cu.UserID, map[string]any{"factor_id": factor.ID})
+
+	return nil
+}
+
+type Login2FASMSInput struct {
+	ChallengeToken string `validate:"required"`
+	IP             string
+}
+
+type Login2FASMSOutput struct {
+	Destination string
+}
+
+// Login2FASMS texts a login code to the SMS factor of an MFA login challenge. A newer
+// code replaces the previous one and stays valid for mfa.sms.code_ttl_minutes.
+func (s *Usecase) Login2FASMS(ctx context.Context, in Login2FASMSInput) (*Login2FASMSOutput, error) {
+	ctx, span := s.startSpan(ctx, "Login2FASMS")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if err := s.checkLoginThrottle(ctx, in.IP, ""); err != nil {
+		return nil, err
+	}
+
+	cu, err := s.loadChallengeUser(ctx, in.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, cu.UserID, cu.UserStatus); err != nil {
+		return nil, err
+	}
+
+	mfaFacs, err := s.loadVerifiedFactors(ctx, cu.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	factor := findMFAFactor(mfaFacs, entity.MFATypeSMS)
+	if factor == nil {
+		slog.WarnContext(ctx, "mfa factor for sms not found", "user_id", cu.UserID)
+		return nil, goerror.NewBusiness("invalid challenge session or code", goerror.CodeUnauthorized)
+	}
+
+	if err := s.checkSMSThrottle(ctx, cu.UserID); err != nil {
+		return nil, err
+	}
+
+	phone, err := s.mfaEncryptor.Decrypt(factor.Secret, mfa.Scope{
+		UserID:  cu.UserID,
+		Purpose: mfa.PurposePhone,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decrypt sms phone", "user_id", cu.UserID, "mfa_id", factor.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	code, codeHash, err := s.newSMSCode()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate sms code", "user_id", cu.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	loginMeta, err := entity.DecodeMetadata[entity.MFALoginMetadata](cu.ChallengeMetadata)
+	if err != nil {
+		slog.WarnContext(ctx, "invalid mfa login challenge metadata", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return nil, goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
+	}
+	loginMeta.SMSCode = codeHash
+	loginMeta.SMSExpiresAt = s.clock.Now().Add(s.cfg.GetMinute("mfa.sms.code_ttl_minutes")).Unix()
+
+	meta, err := entity.EncodeMetadata(&loginMeta)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode mfa login metadata", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.UpdateChallengeMetadata(ctx, cu.ChallengeID, meta); err != nil {
+		slog.ErrorContext(ctx, "failed to repo update challenge metadata", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.sendSMSCode(ctx, cu.UserID, string(phone), code); err != nil {
+		return nil, err
+	}
+
+	return &Login2FASMSOutput{Destination: factor.FriendlyName}, nil
+}
+
+func (s *Usecase) verifySMS(ctx context.Context, cu *entity.ChallengeUser, factors []entity.MFAFactor, code string) error {
+	factor := findMFAFactor(factors, entity.MFATypeSMS)
+	if factor == nil {
+		slog.WarnContext(ctx, "mfa factor for sms not found", "user_id", cu.UserID)
+		return goerror.NewBusiness("invalid challenge session or code", goerror.CodeUnauthorized)
+	}
+
+	meta, err := entity.DecodeMetadata[entity.MFALoginMetadata](cu.ChallengeMetadata)
+	if err != nil {
+		slog.WarnContext(ctx, "invalid mfa login challenge metadata", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return goerror.NewBusiness("invalid challenge session or code", goerror.CodeUnauthorized)
+	}
+
+	codeHash := meta.SMSCode
+	if codeHash == "" || s.clock.Now().Unix() > meta.SMSExpiresAt {
+		slog.WarnContext(ctx, "sms code not sent or expired", "user_id", cu.UserID, "challenge_id", cu.ChallengeID)
+		return goerror.NewBusiness("invalid challenge session or code", goerror.CodeUnauthorized)
+	}
+
+	if !s.hmac.Verify(codeHash, code) {
+		slog.WarnContext(ctx, "invalid sms code", "user_id", cu.UserID, "mfa_id", factor.ID)
+		return goerror.NewBusiness("invalid challenge session or code", goerror.CodeUnauthorized)
+	}
+
+	if err := s.repoDB.UpdateMFALastUsedAt(ctx, factor.ID, cu.UserID); err != nil {
+		slog.ErrorContext(ctx, "failed to update mfa last_used_at", "user_id", cu.UserID, "mfa_id", factor.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	return nil
+}
+
+func (s *Usecase) ensureNoSMSFactor(ctx context.Context, userID int64) error {
+	verifiedFactors, err := s.repoDB.GetMFAFactorByUserID(ctx, userID, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get verified mfa factor", "user_id", userID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if findMFAFactor(verifiedFactors, entity.MFATypeSMS) != nil {
+		return goerror.NewBusiness("A verified SMS factor already exists", goerror.CodeConflict)
+	}
+
+	return nil
+}
+
+// checkSMSThrottle caps how many codes a user can have texted per window, which bounds
+// both the SMS bill and the number of live codes an attacker can guess against.
+func (s *Usecase) checkSMSThrottle(ctx context.Context, userID int64) error {
+	wait, err := s.throttle.Hit(ctx, "mfa:sms:send:"+strconv.FormatInt(userID, 10),
+		s.cfg.GetInt("mfa.sms.send_limit"),
+		s.cfg.GetSecond("mfa.sms.send_window_seconds"))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to check sms throttle", "user_id", userID, "error", err)
+	}
+	if wait > 0 {
+		slog.WarnContext(ctx, "sms rate limited", "user_id", userID, "retry_after", wait)
+		return goerror.NewTooManyRequests("too many SMS codes requested, try again later", ReasonSMSRateLimited, wait)
+	}
+
+	return nil
+}
+
+func (s *Usecase) sendSMSCode(ctx context.Context, userID int64, phone, code string) error {
+	body := fmt.Sprintf("%s is your verification code. It expires in %d minutes.",
+		code, int(s.cfg.GetMinute("mfa.sms.code_ttl_minutes").Minutes()))
+
+	if err := s.repoSMS.Send(ctx, phone, body); err != nil {
+		slog.ErrorContext(ctx, "failed to send sms code", "user_id", userID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	return nil
+}
+
+// newSMSCode returns a random six digit code and its HMAC for storage.
+func (s *Usecase) newSMSCode() (code, codeHash string, err error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", "", err
+	}
+
+	code = fmt.Sprintf("%06d", n.Int64())
+	hashed, err := s.hmac.Hash(code)
+	if err != nil {
+		return "", "", err
+	}
+
+	return code, string(hashed), nil
+}
+
+func findMFAFactor(factors []entity.MFAFactor, t entity.MFAType) *entity.MFAFactor {
+	for i := range factors {
+		if factors[i].Type == t {
+			return &factors[i]
+		}
+	}
+	return nil
+}
+
+// maskPhoneNumber keeps the last two digits, e.g. "+62*******89".
+func maskPhoneNumber(phone string) string {
+	const visible = 2
+	if len(phone) <= visible+1 {
+		return phone
+	}
+	return phone[:1] + strings.Repeat("*", len(phone)-visible-1) + phone[len(phone)-visible:]
+}
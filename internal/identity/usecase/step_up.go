@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+const (
+	// reasonStepUpRequired tells clients to call POST /api/v1/identity/reauth and retry.
+	reasonStepUpRequired = "step_up_required"
+	// reasonMFARequired tells clients the re-authentication also needs a second factor.
+	reasonMFARequired = "mfa_required"
+)
+
+type (
+	ReauthenticateInput struct {
+		Password string `validate:"required"`
+		// Method and Code answer the MFA re-challenge, required when the user has MFA.
+		Method     entity.MFAType
+		Code       string
+		IP         string
+		ClientType string
+	}
+
+	ReauthenticateOutput struct {
+		AccessToken string
+	}
+)
+
+// Reauthenticate re-checks the password of the signed-in user, and their second factor when
+// MFA is enabled, then issues an access token with a fresh auth_time so operations guarded by
+// requireRecentAuth succeed. The refresh token is left as it is.
+func (s *Usecase) Reauthenticate(ctx context.Context, in ReauthenticateInput) (*ReauthenticateOutput, error) {
+	ctx, span := s.startSpan(ctx, "Reauthenticate")
+	defer span.End()
+
+	in.Code = strings.TrimSpace(in.Code)
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if clm.APIKeyID != 0 || clm.ClientID != "" || clm.Actor != nil {
+		return nil, goerror.NewBusiness("re-authentication requires a user session", goerror.CodeForbidden)
+	}
+
+	user, err := s.repoDB.GetUserCredentialInfo(ctx, clm.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user account not found", "user_id", clm.UserID)
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user credential info", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.ensureUserStatusAllowed(ctx, user.ID, user.Status); err != nil {
+		return nil, err
+	}
+
+	throttleKey := loginThrottleKey(user.ID)
+	if err := s.checkLoginThrottle(ctx, in.IP, throttleKey); err != nil {
+		return nil, err
+	}
+
+	if !s.bcrypt.Verify(user.Password, in.Password) {
+		slog.WarnContext(ctx, "password user account not match", "user_id", user.ID)
+		s.recordLoginFailure(ctx, in.IP, throttleKey)
+		return nil, goerror.NewBusiness("invalid password", goerror.CodeUnauthorized)
+	}
+
+	usedMFA, err := s.verifyStepUpFactor(ctx, user.ID, in.Method, in.Code)
+	if err != nil {
+		var gErr *goerror.Error
+		if errors.As(err, &gErr) && gErr.Code() == goerror.CodeUnauthorized && gErr.Reason() != reasonMFARequired {
+			s.recordLoginFailure(ctx, in.IP, throttleKey)
+		}
+		return nil, err
+	}
+
+	s.resetLoginFailures(ctx, throttleKey)
+
+	ttl := s.tokenTTLFor(ctx, user.ID, normalizeClientType(in.ClientType))
+
+	acToken, err := s.jwt.Generate(jwt.WithAuthTime(jwt.WithTTL(ctx, ttl.access), s.clock.Now()), user.ID, user.Email)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate access jwt token", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionAuthReauth, user.ID, user.ID, map[string]any{
+		"mfa": usedMFA,
+	})
+
+	return &ReauthenticateOutput{AccessToken: acToken}, nil
+}
+
+// verifyStepUpFactor checks the second factor of a re-authentication and reports whether one
+// was used. Users without MFA pass with the password alone. SMS is not offered since no login
+// challenge carries the code.
+func (s *Usecase) verifyStepUpFactor(ctx context.Context, userID int64, method entity.MFAType, code string) (bool, error) {
+	factors, err := s.repoDB.GetMFAFactorByUserID(ctx, userID, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get verified mfa factor", "user_id", userID, "error", err)
+		return false, goerror.NewServer(err)
+	}
+	if len(factors) == 0 {
+		return false, nil
+	}
+
+	if code == "" {
+		return false, goerror.NewBusinessReason("mfa code required", goerror.CodeUnauthorized, reasonMFARequired)
+	}
+
+	switch method {
+	case entity.MFATypeTOTP:
+		if !s.isValidTOTPCode(code) {
+			return false, goerror.NewBusiness("invalid mfa code", goerror.CodeUnauthorized)
+		}
+		return true, s.verifyTOTP(ctx, userID, factors, code)
+	case entity.MFATypeBackupCode:
+		return true, s.verifyBackupCode(ctx, userID, factors, code)
+	default:
+		slog.WarnContext(ctx, "method not supported for re-authentication", "method", method.String())
+		return false, goerror.NewBusiness("method not supported", goerror.CodeUnauthorized)
+	}
+}
+
+// requireRecentAuth guards sensitive operations: the access token must come from a sign-in or
+// a Reauthenticate within modules.identity.step_up.max_age_minutes. A zero window disables
+// the check. Tokens without auth_time (API keys, service accounts, impersonation) never pass.
+func (s *Usecase) requireRecentAuth(ctx context.Context, clm *jwt.Claims) error {
+	maxAge := s.cfg.GetMinute("modules.identity.step_up.max_age_minutes")
+	if maxAge <= 0 {
+		return nil
+	}
+
+	if clm.AuthTime == nil || s.clock.Now().Sub(clm.AuthTime.Time) > maxAge {
+		slog.WarnContext(ctx, "recent authentication required", "user_id", clm.UserID)
+		return goerror.NewBusinessReason("recent authentication required", goerror.CodeForbidden, reasonStepUpRequired)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+// defaultTokenClaimsMaxPermissions caps the permissions embedded in the scope claim.
+const defaultTokenClaimsMaxPermissions = 50
+
+// EnrichClaims implements jwt.ClaimsEnricher. It embeds the user's Casbin roles, their
+// effective permissions or a fingerprint of them, and their role in the organization the token
+// is scoped to, each toggled by config so tokens stay small.
+func (s *Usecase) EnrichClaims(ctx context.Context, clm *jwt.Claims) error {
+	if s.cfg.GetBool("modules.identity.token_claims.roles") {
+		roles, err := s.enforcer.GetRolesForUser(clm.Subject)
+		if err != nil {
+			return err
+		}
+		slices.Sort(roles)
+		clm.Roles = roles
+	}
+
+	withHash := s.cfg.GetBool("modules.identity.token_claims.permission_hash")
+	withScope := s.cfg.GetBool("modules.identity.token_claims.permissions")
+	if withHash || withScope {
+		policies, err := s.enforcer.GetImplicitPermissionsForUser(clm.Subject)
+		if err != nil {
+			return err
+		}
+		if withHash {
+			clm.PermissionHash = permissionHash(policies)
+		}
+		if withScope {
+			clm.Scope = s.permissionScope(ctx, clm.Subject, policies)
+		}
+	}
+
+	if s.cfg.GetBool("modules.identity.token_claims.org_role") && clm.OrgID != 0 && clm.UserID != 0 {
+		role, err := s.orgRole(clm.UserID, clm.OrgID)
+		if err != nil {
+			return err
+		}
+		clm.OrgRole = string(role)
+	}
+
+	return nil
+}
+
+// permissionScope returns the permissions as space-separated "object:action" entries, or an
+// empty scope when there are more than modules.identity.token_claims.max_permissions, so a
+// broad role cannot bloat every token; clients then read the permissions endpoint instead.
+func (s *Usecase) permissionScope(ctx context.Context, subject string, policies [][]string) string {
+	entries := permissionEntries(policies)
+
+	limit := s.cfg.GetInt("modules.identity.token_claims.max_permissions")
+	if limit <= 0 {
+		limit = defaultTokenClaimsMaxPermissions
+	}
+	if len(entries) > limit {
+		slog.WarnContext(ctx, "too many permissions for the scope claim", "subject", subject, "permissions", len(entries), "limit", limit)
+		return ""
+	}
+
+	return strings.Join(entries, " ")
+}
+
+// permissionHash is order-independent so the same permission set always yields the same value.
+func permissionHash(policies [][]string) string {
+	sum := sha256.Sum256([]byte(strings.Join(permissionEntries(policies), "\n")))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// permissionEntries returns the sorted, distinct "object:action" entries of policies.
+func permissionEntries(policies [][]string) []string {
+	entries := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		if len(policy) < 3 {
+			continue
+		}
+		entries = append(entries, policy[1]+":"+policy[2])
+	}
+	slices.Sort(entries)
+
+	return slices.Compact(entries)
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+// revokeAccessToken denies the access token clm was read from for the rest of its lifetime.
+// A failure is logged only: the token still expires on its own.
+func (s *Usecase) revokeAccessToken(ctx context.Context, clm *jwt.Claims) {
+	if s.denylist == nil || clm == nil || clm.ID == "" || clm.ExpiresAt == nil {
+		return
+	}
+
+	if err := s.denylist.Revoke(ctx, clm.ID, clm.ExpiresAt.Time); err != nil {
+		slog.ErrorContext(ctx, "failed to revoke access token", "user_id", clm.UserID, "error", err)
+	}
+}
+
+// revokeUserAccessTokens denies every access token of userID issued so far. A failure is
+// logged only: the tokens still expire on their own.
+func (s *Usecase) revokeUserAccessTokens(ctx context.Context, userID int64) {
+	if s.denylist == nil {
+		return
+	}
+
+	if err := s.denylist.RevokeUser(ctx, userID, s.clock.Now(), s.maxAccessTTL()); err != nil {
+		slog.ErrorContext(ctx, "failed to revoke user access tokens", "user_id", userID, "error", err)
+	}
+}
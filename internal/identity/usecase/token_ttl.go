@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxClientTypeLength bounds the client type stored on a session.
+const maxClientTypeLength = 32
+
+// tokenTTL holds the lifetimes applied to one token issuance. A zero access TTL keeps the
+// JWT default (jwt.ttl_minutes).
+type tokenTTL struct {
+	access  time.Duration
+	refresh time.Duration
+}
+
+func normalizeClientType(client string) string {
+	client = strings.ToLower(strings.TrimSpace(client))
+	if len(client) > maxClientTypeLength {
+		client = client[:maxClientTypeLength]
+	}
+	return client
+}
+
+// tokenTTLFor resolves the access and refresh lifetimes for a user signing in from client.
+// A role override beats a client override, and when several of the user's roles are
+// configured the shortest lifetime wins.
+func (s *Usecase) tokenTTLFor(ctx context.Context, userID int64, client string) tokenTTL {
+	ttl := tokenTTL{refresh: s.cfg.GetDay("modules.identity.refresh_token_ttl_days")}
+
+	accessRoles := s.cfg.GetMap("modules.identity.token_ttl.access_roles")
+	refreshRoles := s.cfg.GetMap("modules.identity.token_ttl.refresh_roles")
+
+	var roles []string
+	if len(accessRoles) > 0 || len(refreshRoles) > 0 {
+		var err error
+		roles, err = s.enforcer.GetRolesForUser(strconv.FormatInt(userID, 10))
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get roles for token ttl", "user_id", userID, "error", err)
+		}
+	}
+
+	if d := ttlOverride(ctx, accessRoles, roles, time.Minute); d > 0 {
+		ttl.access = d
+	} else if d := ttlOverride(ctx, s.cfg.GetMap("modules.identity.token_ttl.access_clients"), []string{client}, time.Minute); d > 0 {
+		ttl.access = d
+	}
+
+	if d := ttlOverride(ctx, refreshRoles, roles, 24*time.Hour); d > 0 {
+		ttl.refresh = d
+	} else if d := ttlOverride(ctx, s.cfg.GetMap("modules.identity.token_ttl.refresh_clients"), []string{client}, 24*time.Hour); d > 0 {
+		ttl.refresh = d
+	}
+
+	return ttl
+}
+
+// ttlOverride returns the shortest configured lifetime among keys, or 0 when none is set.
+func ttlOverride(ctx context.Context, overrides map[string]string, keys []string, unit time.Duration) time.Duration {
+	var best time.Duration
+	for _, key := range keys {
+		raw, ok := overrides[key]
+		if !ok || key == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || n <= 0 {
+			slog.WarnContext(ctx, "invalid token ttl override", "key", key, "value", raw)
+			continue
+		}
+
+		if d := time.Duration(n) * unit; best == 0 || d < best {
+			best = d
+		}
+	}
+
+	return best
+}
+
+// maxAccessTTL returns the longest lifetime an access token can be issued with, the time a
+// revocation of all of a user's tokens has to be remembered.
+func (s *Usecase) maxAccessTTL() time.Duration {
+	longest := max(s.cfg.GetMinute("jwt.ttl_minutes"), s.cfg.GetMinute("modules.identity.impersonation.token_ttl_minutes"))
+
+	for _, key := range []string{"modules.identity.token_ttl.access_roles", "modules.identity.token_ttl.access_clients"} {
+		for _, raw := range s.cfg.GetMap(key) {
+			if n, err := strconv.Atoi(strings.TrimSpace(raw)); err == nil && n > 0 {
+				longest = max(longest, time.Duration(n)*time.Minute)
+			}
+		}
+	}
+
+	return longest
+}
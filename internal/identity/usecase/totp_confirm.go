@@ -42,16 +42,16 @@ func (s *Usecase) TOTPConfirm(ctx context.Context, in TOTPConfirmInput) error {
 		return err
 	}
 
-	friendlyName, keyVersion, err := s.validateChallengeUser(ctx, cu, clm.UserID)
+	meta, err := s.validateChallengeUser(ctx, cu, clm.UserID)
 	if err != nil {
 		return err
 	}
 
-	if err := s.ensureNoTOTPFactor(ctx, cu.UserID); err != nil {
+	if err := s.ensureTOTPFactorSlot(ctx, cu.UserID, meta.FriendlyName); err != nil {
 		return err
 	}
 
-	secretCiphertext, err := s.decodeTOTPSecret(ctx, cu)
+	secretCiphertext, err := s.decodeTOTPSecret(ctx, cu, meta.Secret)
 	if err != nil {
 		return err
 	}
@@ -66,13 +66,15 @@ func (s *Usecase) TOTPConfirm(ctx context.Context, in TOTPConfirmInput) error {
 		return goerror.NewBusiness("invalid code session", goerror.CodeUnauthorized)
 	}
 
-	factorTotp := s.buildTOTPFacts(cu, friendlyName, keyVersion, secretCiphertext)
+	factorTotp := s.buildTOTPFacts(cu, strings.TrimSpace(meta.FriendlyName), meta.KeyVersion, secretCiphertext)
 
-	if err := s.repoDB.NewMFAFactorTOTP(ctx, factorTotp, cu.ChallengeID); err != nil {
+	if err := s.repoDB.NewMFAFactor(ctx, factorTotp, cu.ChallengeID); err != nil {
 		slog.ErrorContext(ctx, "failed to repo new mfa factor totp", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
 		return goerror.NewServer(err)
 	}
 
+	s.recordAudit(ctx, entity.AuditActionMFATOTPEnable, clm.UserID, cu.UserID, map[string]any{"factor_id": factorTotp.ID})
+
 	return nil
 }
 
@@ -89,54 +91,59 @@ func (s *Usecase) getChallengeUserByToken(ctx context.Context, tokenHash string)
 	return cu, nil
 }
 
-func (s *Usecase) validateChallengeUser(ctx context.Context, cu *entity.ChallengeUser, userID int64) (string, int, error) {
+func (s *Usecase) validateChallengeUser(ctx context.Context, cu *entity.ChallengeUser, userID int64) (*entity.TOTPSetupMetadata, error) {
 	if err := s.ensureUserStatusAllowed(ctx, cu.UserID, cu.UserStatus); err != nil {
-		return "", 0, err
+		return nil, err
 	}
 
 	if cu.UserID != userID {
 		slog.WarnContext(ctx, "challenge user mismatch", "user_id", userID, "challenge_user_id", cu.UserID)
-		return "", 0, goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
-	}
-
-	secretEncoded := cu.ChallengeMetadata.GetString("secret")
-	if secretEncoded == "" {
-		slog.WarnContext(ctx, "challenge missing totp secret", "user_id", cu.UserID, "challenge_id", cu.ChallengeID)
-		return "", 0, goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
-	}
-
-	friendlyName := strings.TrimSpace(cu.ChallengeMetadata.GetString("friendly_name"))
-	if friendlyName == "" {
-		slog.WarnContext(ctx, "challenge missing totp friendly name", "user_id", cu.UserID, "challenge_id", cu.ChallengeID)
-		return "", 0, goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
+		return nil, goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
 	}
 
-	keyVersion := cu.ChallengeMetadata.GetInt("key_version")
-	if keyVersion == 0 {
-		keyVersion = 1
+	meta, err := entity.DecodeMetadata[entity.TOTPSetupMetadata](cu.ChallengeMetadata)
+	if err != nil {
+		slog.WarnContext(ctx, "invalid totp setup challenge metadata", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return nil, goerror.NewBusiness("invalid challenge session", goerror.CodeUnauthorized)
 	}
 
-	return friendlyName, keyVersion, nil
+	return &meta, nil
 }
 
-func (s *Usecase) ensureNoTOTPFactor(ctx context.Context, userID int64) error {
+// ensureTOTPFactorSlot checks that the user may enroll one more TOTP factor under friendlyName:
+// names are unique among the user's TOTP factors, ignoring case, and the count is capped by
+// modules.identity.mfa_max_totp_factors.
+func (s *Usecase) ensureTOTPFactorSlot(ctx context.Context, userID int64, friendlyName string) error {
 	verifiedFactors, err := s.repoDB.GetMFAFactorByUserID(ctx, userID, true)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to repo get verified mfa factor", "user_id", userID, "error", err)
 		return goerror.NewServer(err)
 	}
 
+	maxFactors := s.cfg.GetInt("modules.identity.mfa_max_totp_factors")
+	if maxFactors <= 0 {
+		maxFactors = 1
+	}
+
+	count := 0
 	for i := range verifiedFactors {
-		if verifiedFactors[i].Type == entity.MFATypeTOTP {
-			return goerror.NewBusiness("A verified TOTP factor already exists", goerror.CodeConflict)
+		if verifiedFactors[i].Type != entity.MFATypeTOTP {
+			continue
 		}
+		if strings.EqualFold(verifiedFactors[i].FriendlyName, friendlyName) {
+			return goerror.NewBusiness("A TOTP factor with this name already exists", goerror.CodeConflict)
+		}
+		count++
+	}
+
+	if count >= maxFactors {
+		return goerror.NewBusiness("the maximum number of TOTP factors is reached", goerror.CodeConflict)
 	}
 
 	return nil
 }
 
-func (s *Usecase) decodeTOTPSecret(ctx context.Context, cu *entity.ChallengeUser) ([]byte, error) {
-	secretEncoded := cu.ChallengeMetadata.GetString("secret")
+func (s *Usecase) decodeTOTPSecret(ctx context.Context, cu *entity.ChallengeUser, secretEncoded string) ([]byte, error) {
 	secretCiphertext, err := base64.StdEncoding.DecodeString(secretEncoded)
 	if err != nil {
 		slog.WarnContext(ctx, "challenge totp secret decode failed", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
@@ -11,7 +11,6 @@ import (
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 	"github.com/shandysiswandi/gobite/internal/pkg/mfa"
-	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 )
 
 type TOTPSetupInput struct {
@@ -58,22 +57,8 @@ func (s *Usecase) TOTPSetup(ctx context.Context, in TOTPSetupInput) (*TOTPSetupO
 		return nil, err
 	}
 
-	verifiedFactors, err := s.repoDB.GetMFAFactorByUserID(ctx, user.ID, true)
-	if err != nil {
-		slog.ErrorContext(ctx, "failed to repo get verified mfa factor", "user_id", user.ID, "error", err)
-		return nil, goerror.NewServer(err)
-	}
-
-	isMFATOTPVerifiedExist := false
-	for i := range verifiedFactors {
-		if verifiedFactors[i].Type == entity.MFATypeTOTP {
-			isMFATOTPVerifiedExist = true
-			break
-		}
-	}
-
-	if isMFATOTPVerifiedExist {
-		return nil, goerror.NewBusiness("A verified TOTP factor already exists", goerror.CodeConflict)
+	if err := s.ensureTOTPFactorSlot(ctx, user.ID, in.FriendlyName); err != nil {
+		return nil, err
 	}
 
 	secret, uri, err := s.totp.Generate(user.Email)
@@ -98,17 +83,23 @@ func (s *Usecase) TOTPSetup(ctx context.Context, in TOTPSetupInput) (*TOTPSetupO
 		return nil, goerror.NewServer(err)
 	}
 
+	meta, err := entity.EncodeMetadata(&entity.TOTPSetupMetadata{
+		Secret:       base64.StdEncoding.EncodeToString(encryptedSecret),
+		FriendlyName: in.FriendlyName,
+		KeyVersion:   1, // can be use config later
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode totp setup metadata", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
 	challenge := entity.Challenge{
 		ID:        s.uid.Generate(),
 		UserID:    user.ID,
 		Token:     string(cTokenHash),
 		Purpose:   entity.ChallengePurposeMFASetupConfirm,
 		ExpiresAt: s.clock.Now().Add(s.cfg.GetMinute("modules.identity.mfa_setup_confirm_ttl_minutes")),
-		Metadata: valueobject.JSONMap{
-			"secret":        base64.StdEncoding.EncodeToString(encryptedSecret),
-			"friendly_name": in.FriendlyName,
-			"key_version":   1, // can be use config later
-		},
+		Metadata:  meta,
 	}
 
 	if err := s.repoDB.CreateChallenge(ctx, challenge); err != nil {
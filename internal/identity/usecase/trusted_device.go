@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+type (
+	ListTrustedDevicesOutput struct {
+		Devices []entity.TrustedDevice
+	}
+
+	RevokeTrustedDeviceInput struct {
+		ID int64 `validate:"required,gt=0"`
+	}
+)
+
+// ListTrustedDevices returns the devices of the authenticated user that currently skip the
+// MFA challenge, most recently remembered first.
+func (s *Usecase) ListTrustedDevices(ctx context.Context) (*ListTrustedDevicesOutput, error) {
+	ctx, span := s.startSpan(ctx, "ListTrustedDevices")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	devices, err := s.repoDB.GetActiveTrustedDevices(ctx, clm.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get active trusted devices", "user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &ListTrustedDevicesOutput{Devices: devices}, nil
+}
+
+// RevokeTrustedDevice forgets a trusted device of the authenticated user, so its next login
+// asks for MFA again. Sessions already open on the device are left alone.
+func (s *Usecase) RevokeTrustedDevice(ctx context.Context, in RevokeTrustedDeviceInput) error {
+	ctx, span := s.startSpan(ctx, "RevokeTrustedDevice")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	err := s.repoDB.RevokeTrustedDevice(ctx, in.ID, clm.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("trusted device not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo revoke trusted device", "user_id", clm.UserID, "trusted_device_id", in.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionTrustedDeviceRevoke, clm.UserID, clm.UserID, map[string]any{
+		"trusted_device_id": strconv.FormatInt(in.ID, 10),
+	})
+
+	return nil
+}
+
+// rememberDevice trusts the device described by meta for the configured period and returns
+// the device token to present on later logins. Only the hash of the token is stored.
+func (s *Usecase) rememberDevice(ctx context.Context, userID int64, meta entity.SessionMetadata) (string, time.Time, error) {
+	token := s.oid.Generate()
+	tokenHash, err := s.hmac.Hash(token)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	device := entity.TrustedDevice{
+		ID:        s.uid.Generate(),
+		UserID:    userID,
+		UserAgent: meta.UserAgent,
+		IP:        meta.IP,
+		ExpiresAt: s.clock.Now().Add(s.cfg.GetDay("modules.identity.trusted_device.ttl_days")),
+	}
+
+	if err := s.repoDB.CreateTrustedDevice(ctx, device, string(tokenHash)); err != nil {
+		return "", time.Time{}, err
+	}
+
+	s.recordAudit(ctx, entity.AuditActionTrustedDeviceCreate, userID, userID, map[string]any{
+		"trusted_device_id": strconv.FormatInt(device.ID, 10),
+		"client":            meta.Client,
+	})
+
+	return token, device.ExpiresAt, nil
+}
+
+// isTrustedDevice reports whether token is an active device token of the user. Any failure
+// counts as untrusted, so the login falls back to the MFA challenge.
+func (s *Usecase) isTrustedDevice(ctx context.Context, userID int64, token string) bool {
+	if token == "" || !s.cfg.GetBool("modules.identity.trusted_device.enabled") {
+		return false
+	}
+
+	tokenHash, err := s.hmac.Hash(token)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash trusted device token", "user_id", userID, "error", err)
+		return false
+	}
+
+	device, err := s.repoDB.GetTrustedDeviceByToken(ctx, string(tokenHash))
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "trusted device not found", "user_id", userID)
+		return false
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get trusted device by token", "user_id", userID, "error", err)
+		return false
+	}
+
+	if device.UserID != userID || device.RevokedAt != nil || !s.clock.Now().Before(device.ExpiresAt) {
+		slog.WarnContext(ctx, "trusted device is not valid for user", "user_id", userID, "trusted_device_id", device.ID)
+		return false
+	}
+
+	if err := s.repoDB.UpdateTrustedDeviceLastUsedAt(ctx, device.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to repo update trusted device last_used_at", "trusted_device_id", device.ID, "error", err)
+	}
+
+	return true
+}
@@ -2,12 +2,17 @@ package usecase
 
 import (
 	"context"
+	"iter"
 	"log/slog"
+	"time"
 
 	"github.com/casbin/casbin/v3"
+	"github.com/shandysiswandi/gobite/internal/contracts"
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
 	"github.com/shandysiswandi/gobite/internal/pkg/clock"
 	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/denylist"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/goroutine"
 	"github.com/shandysiswandi/gobite/internal/pkg/hash"
@@ -16,74 +21,205 @@ import (
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 	"github.com/shandysiswandi/gobite/internal/pkg/mfa"
 	"github.com/shandysiswandi/gobite/internal/pkg/otp"
+	"github.com/shandysiswandi/gobite/internal/pkg/replay"
+	"github.com/shandysiswandi/gobite/internal/pkg/signedurl"
 	"github.com/shandysiswandi/gobite/internal/pkg/storage"
+	"github.com/shandysiswandi/gobite/internal/pkg/throttle"
 	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"github.com/shandysiswandi/gobite/internal/pkg/userdata"
 	"github.com/shandysiswandi/gobite/internal/pkg/validator"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 	"go.opentelemetry.io/otel/trace"
 )
 
-type UserRegistrationEvent struct {
-	UserID         int64
-	Email          string
-	FullName       string
-	ChallengeToken string
+type repoMessaging interface {
+	PublishUserRegistration(ctx context.Context, msg contracts.UserRegistered) error
+	PublishUserForgotPassword(ctx context.Context, msg contracts.PasswordForgot) error
+	PublishUserMFARevoked(ctx context.Context, msg contracts.MFARevoked) error
+	PublishUserMFARecovery(ctx context.Context, msg contracts.MFARecovery) error
+	PublishUserSessionRevoked(ctx context.Context, msg contracts.SessionRevoked) error
+	PublishUserNewSignIn(ctx context.Context, msg contracts.NewSignIn) error
+	PublishNotificationRequested(ctx context.Context, msg contracts.NotificationRequested) error
+	PublishUserDeletionScheduled(ctx context.Context, msg contracts.UserDeletionScheduled, delay time.Duration) error
+	PublishLoginRecorded(ctx context.Context, msg contracts.LoginRecorded) error
+	PublishUserExportRequested(ctx context.Context, msg contracts.UserExportRequested) error
+	PublishVerificationReminderDue(ctx context.Context, msg contracts.VerificationReminderDue) error
 }
 
-type UserForgotPasswordEvent struct {
-	UserID         int64
-	Email          string
-	ChallengeToken string
+type repoAudit interface {
+	PublishAuditRecorded(ctx context.Context, msg contracts.AuditRecorded) error
 }
 
-type repoMessaging interface {
-	PublishUserRegistration(ctx context.Context, msg UserRegistrationEvent) error
-	PublishUserForgotPassword(ctx context.Context, msg UserForgotPasswordEvent) error
+type repoOAuth interface {
+	AuthCodeURL(ctx context.Context, provider, state, verifier, nonce string) (string, error)
+	Exchange(ctx context.Context, provider, code, verifier, nonce string) (*entity.OAuthIdentity, error)
+	Providers() []string
+}
+
+type repoSAML interface {
+	Metadata(ctx context.Context) ([]byte, error)
+	AuthnRequestURL(ctx context.Context, requestID, relayState string) (string, error)
+	ParseResponse(ctx context.Context, samlResponse, requestID string) (*entity.SAMLIdentity, error)
+}
+
+type repoSMS interface {
+	Enabled() bool
+	Send(ctx context.Context, to, body string) error
+}
+
+type repoGeoIP interface {
+	Enabled() bool
+	Lookup(ctx context.Context, ip string) (*entity.GeoLocation, error)
+}
+
+type repoCaptcha interface {
+	Enabled() bool
+	Verify(ctx context.Context, token, remoteIP string) (bool, error)
 }
 
 type repoDB interface {
+	// WithinTx runs fn in one transaction, committed when fn returns nil. Repository calls
+	// made with the context passed to fn join it.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+
 	GetUserLoginInfo(ctx context.Context, email string) (*entity.UserLoginInfo, error)
+	GetUserLoginInfoByEmailHash(ctx context.Context, emailHash string) (*entity.UserLoginInfo, error)
+	GetUserLoginInfoByUsername(ctx context.Context, username string) (*entity.UserLoginInfo, error)
+	GetUsername(ctx context.Context, userID int64) (string, error)
+	GetUserAttributes(ctx context.Context, userID int64, lock bool) (valueobject.JSONMap, error)
+	IsUsernameTaken(ctx context.Context, username string) (bool, error)
+	GetUserLoginInfoByConnection(ctx context.Context, provider, providerUserID string) (*entity.UserLoginInfo, error)
 	GetUserCredentialInfo(ctx context.Context, id int64) (*entity.UserCredentialInfo, error)
 	GetChallengeUserByTokenPurpose(ctx context.Context, token string, p entity.ChallengePurpose) (*entity.ChallengeUser, error)
 	GetUserRefreshToken(ctx context.Context, token string) (*entity.UserRefreshToken, error)
+	GetActiveSessions(ctx context.Context, userID int64) ([]entity.Session, error)
 	GetUserByEmail(ctx context.Context, email string, includeDeleted bool) (*entity.User, error)
+	GetUserByEmailHash(ctx context.Context, emailHash string, includeDeleted bool) (*entity.User, error)
 	GetUserList(ctx context.Context, filter entity.UserListFilterData) ([]entity.User, int64, error)
+	StreamUsers(ctx context.Context, filter entity.UserListFilterData) iter.Seq2[entity.User, error]
 	GetUserByID(ctx context.Context, id int64, includeDeleted bool) (*entity.User, error)
 	GetMFAFactorByUserID(ctx context.Context, userID int64, isVerified bool) ([]entity.MFAFactor, error)
 	GetMFAFactorByID(ctx context.Context, id int64, userID int64) (*entity.MFAFactor, error)
+	GetMFAFactorAllByUserID(ctx context.Context, userID int64) ([]entity.MFAFactorInfo, error)
 	GetMFABackupCodeByUserID(ctx context.Context, userID int64) ([]entity.MFABackupCode, error)
+	GetAuditLogBefore(ctx context.Context, before time.Time, limit int32) ([]entity.AuditLog, error)
+	CountAuditLogBefore(ctx context.Context, before time.Time) (int64, error)
+	CountChallengeExpiredBefore(ctx context.Context, before time.Time) (int64, error)
+	CountRefreshTokenExpiredBefore(ctx context.Context, before time.Time) (int64, error)
+	CountTrustedDeviceExpiredBefore(ctx context.Context, before time.Time) (int64, error)
+	GetUserDeletion(ctx context.Context, userID int64) (*entity.UserDeletion, error)
+	GetUserDeletionDue(ctx context.Context, dueBefore time.Time, limit int32) ([]entity.UserDeletion, error)
+	GetUserPlaintextEmails(ctx context.Context, limit int32) ([]entity.UserEmail, error)
+	GetExportJob(ctx context.Context, id int64) (*entity.ExportJob, error)
+	GetVerificationReminderDue(ctx context.Context, registeredBefore, lastSentBefore time.Time, maxReminders, limit int32) ([]entity.VerificationReminder, error)
+	GetAPIKeyByToken(ctx context.Context, token string) (*entity.APIKeyUser, error)
+	GetActiveAPIKeys(ctx context.Context, userID int64) ([]entity.APIKey, error)
+	GetTrustedDeviceByToken(ctx context.Context, token string) (*entity.TrustedDevice, error)
+	GetActiveTrustedDevices(ctx context.Context, userID int64) ([]entity.TrustedDevice, error)
+	GetUserConnections(ctx context.Context, userID int64) ([]entity.UserConnection, error)
+	CountActiveAPIKeys(ctx context.Context, userID int64) (int64, error)
+	GetServiceAccountByClientID(ctx context.Context, clientID string) (*entity.ServiceAccountCredential, error)
+	GetServiceAccounts(ctx context.Context) ([]entity.ServiceAccount, error)
+	GetLoginEvents(ctx context.Context, userID, beforeID int64, limit int32) ([]entity.LoginEvent, error)
+	CountLoginEventBefore(ctx context.Context, before time.Time) (int64, error)
+	GetUserDevices(ctx context.Context, userID int64) ([]entity.UserDevice, error)
+	GetPasswordHistory(ctx context.Context, userID int64, limit int32) ([]string, error)
+	GetOrganizationByID(ctx context.Context, id int64) (*entity.Organization, error)
+	GetOrganizationsByUserID(ctx context.Context, userID int64) ([]entity.Organization, error)
+	GetOrganizationMembers(ctx context.Context, orgID int64) ([]entity.OrgMember, error)
+	IsOrganizationMember(ctx context.Context, orgID, userID int64) (bool, error)
 
 	CreateRefreshToken(ctx context.Context, in entity.RefreshToken) error
 	CreateChallenge(ctx context.Context, in entity.Challenge) error
+	CreateAuditLog(ctx context.Context, in entity.AuditLog) error
+	CreateUserConnection(ctx context.Context, in entity.UserConnection) error
+	CreateUserDeletion(ctx context.Context, userID int64, scheduledAt time.Time) (*entity.UserDeletion, error)
+	CreateExportJob(ctx context.Context, in entity.ExportJob) (*entity.ExportJob, error)
+	CreateAPIKey(ctx context.Context, in entity.APIKey, tokenHash string) error
+	CreateTrustedDevice(ctx context.Context, in entity.TrustedDevice, tokenHash string) error
+	CreateServiceAccount(ctx context.Context, in entity.ServiceAccount, secretHash string) error
+	CreateLoginEvent(ctx context.Context, in entity.LoginEvent) error
+	UpsertUserDevice(ctx context.Context, in entity.UserDevice) error
+	CreateOrganizationMember(ctx context.Context, orgID, userID int64) error
 
 	RevokeRefreshToken(ctx context.Context, token string) error
 	RevokeAllRefreshToken(ctx context.Context, userID int64) error
+	RevokeSession(ctx context.Context, id, userID int64) error
+	RevokeRefreshTokenOverLimit(ctx context.Context, userID int64, keep int32) (int64, error)
+	RevokeAPIKey(ctx context.Context, id, userID int64) error
+	RevokeTrustedDevice(ctx context.Context, id, userID int64) error
+	UpdateTrustedDeviceLastUsedAt(ctx context.Context, id int64) error
+	UpdateAPIKeyLastUsedAt(ctx context.Context, id int64) error
+	UpdateServiceAccountLastUsedAt(ctx context.Context, id int64) error
+	UpdateLoginEventLocation(ctx context.Context, id int64, loc entity.GeoLocation) error
+	ClaimVerificationReminder(ctx context.Context, userID int64, sentCount int32, sentAt time.Time) (bool, error)
+	StartExportJob(ctx context.Context, id, rowsTotal int64) (bool, error)
+	UpdateExportJobProgress(ctx context.Context, id, rowsWritten int64) error
+	CompleteExportJob(ctx context.Context, id, rowsWritten int64, objectKey string) error
+	FailExportJob(ctx context.Context, id int64, reason string) error
 	MarkMFABackupCodeUsed(ctx context.Context, bcID, userID int64) (bool, error)
 	UpdateMFALastUsedAt(ctx context.Context, factorID, userID int64) error
+	UpdateMFAFactorFriendlyName(ctx context.Context, factorID, userID int64, friendlyName string) error
+	UpdateChallengeMetadata(ctx context.Context, id int64, meta valueobject.JSONMap) error
 	UpdateUserProfile(ctx context.Context, id int64, fullName string) error
+	UpdateUsername(ctx context.Context, id int64, username string) error
+	UpdateUserAttributes(ctx context.Context, id, updatedBy int64, attrs valueobject.JSONMap) error
 	UpdateUserAvatar(ctx context.Context, id int64, avatarURL string) error
 	UpdateUserStatus(ctx context.Context, id int64, oldStatus, newStatus entity.UserStatus) error
-	UpdateUserCredential(ctx context.Context, userID int64, hash string) error
+	UpdateUserCredential(ctx context.Context, userID int64, hash string, keepHistory int32) error
+	UpdateUserEmailLookup(ctx context.Context, id int64, email, emailHash string) error
+	SealUserEmail(ctx context.Context, id int64, email, emailHash string) (bool, error)
 	MarkUserDeleted(ctx context.Context, id, byID int64) error
+	UpdateOrganizationName(ctx context.Context, id int64, name string) error
 
-	NewMFAFactorTOTP(ctx context.Context, fTOTP entity.MFAFactor, challengeID int64) error
-	NewRefreshToken(ctx context.Context, ref entity.RefreshToken, challengeID int64) error
+	NewMFAFactor(ctx context.Context, factor entity.MFAFactor, challengeID int64) error
 	NewRegistration(ctx context.Context, user entity.NewUser, chal entity.Challenge, hash string) error
 	NewBackupCodes(ctx context.Context, userID int64, codes []entity.MFABackupCode, factor *entity.MFAFactor) error
 	NewUser(ctx context.Context, user entity.NewUser, hash string) error
+	NewConnectedUser(ctx context.Context, user entity.NewUser, hash string, conn entity.UserConnection) error
 	UpsertUsers(ctx context.Context, users []entity.UpsertUser, hashes map[string]string) (created, updated int, err error)
 	PatchUser(ctx context.Context, user entity.PatchUser, hash string) error
 	VerifyUserRegistration(ctx context.Context, data entity.VerifyUserRegistration) error
-	ResetUserPassword(ctx context.Context, userID, challengeID int64, newHash string) error
+	ResetUserPassword(ctx context.Context, userID, challengeID int64, newHash string, keepHistory int32) error
 	VerifyUserMFAFactor(ctx context.Context, userID, challengeID, factorID int64) error
 	RotateRefreshToken(ctx context.Context, ro entity.RotateRefreshToken) error
+	RevokeUserMFA(ctx context.Context, userID int64, audit entity.AuditLog) error
+	DeleteMFAFactor(ctx context.Context, factorID, userID int64) (bool, error)
+	NewMFARecoveryPending(ctx context.Context, chal entity.Challenge, challengeID int64) error
+	CompleteMFARecovery(ctx context.Context, userID, challengeID int64, audit entity.AuditLog) error
+	ChangeUserEmail(ctx context.Context, ce entity.ChangeUserEmail) error
+	AcceptUserInvite(ctx context.Context, ai entity.AcceptUserInvite) error
+	AnonymizeUser(ctx context.Context, au entity.AnonymizeUser) error
+	CreateOrganization(ctx context.Context, org entity.Organization) error
 
 	DeleteChallenge(ctx context.Context, id int64) error
+	DeleteChallengeByUserPurpose(ctx context.Context, userID int64, p entity.ChallengePurpose) (int64, error)
+	DeletePendingUserDeletion(ctx context.Context, userID int64) (bool, error)
+	DeleteAuditLogByIDs(ctx context.Context, ids []int64) (int64, error)
+	DeleteAuditLogBefore(ctx context.Context, before time.Time, limit int32) (int64, error)
+	DeleteChallengeExpiredBefore(ctx context.Context, before time.Time, limit int32) (int64, error)
+	DeleteRefreshTokenExpiredBefore(ctx context.Context, before time.Time, limit int32) (int64, error)
+	DeleteTrustedDeviceExpiredBefore(ctx context.Context, before time.Time, limit int32) (int64, error)
+	DeleteServiceAccount(ctx context.Context, id int64) (string, error)
+	DeleteUserConnection(ctx context.Context, id, userID int64) (string, error)
+	DeleteLoginEventBefore(ctx context.Context, before time.Time, limit int32) (int64, error)
+	DeleteOrganization(ctx context.Context, id int64) error
+	DeleteOrganizationMember(ctx context.Context, orgID, userID int64) error
 }
 
 type Usecase struct {
 	repoDB          repoDB
 	repoMessaging   repoMessaging
+	repoAudit       repoAudit
+	repoOAuth       repoOAuth
+	repoSAML        repoSAML
+	repoSMS         repoSMS
+	repoGeoIP       repoGeoIP
+	repoCaptcha     repoCaptcha
 	idemp           idempotency.Idempotency
+	throttle        throttle.Throttle
+	denylist        denylist.Denylist
+	replay          replay.Guard
 	validator       validator.Validator
 	cfg             config.Config
 	storage         storage.Storage
@@ -92,6 +228,7 @@ type Usecase struct {
 	argon2id        hash.Hash
 	mfaEncryptor    mfa.Encryptor
 	mfaRecoveryCode mfa.RecoveryCodeGenerator
+	signedURL       signedurl.Signer
 	uid             uid.NumberID
 	uuid            uid.StringID
 	oid             uid.StringID
@@ -100,13 +237,24 @@ type Usecase struct {
 	jwt             jwt.JWT
 	ins             instrument.Instrumentation
 	enforcer        *casbin.Enforcer
+	authzShadow     *authz.Shadow
 	goroutine       *goroutine.Manager
+	userData        *userdata.Registry
 }
 
 type Dependency struct {
 	RepoDB          repoDB
 	Idempotency     idempotency.Idempotency
+	Throttle        throttle.Throttle
+	Denylist        denylist.Denylist
+	Replay          replay.Guard
 	RepoMessaging   repoMessaging
+	RepoAudit       repoAudit
+	RepoOAuth       repoOAuth
+	RepoSAML        repoSAML
+	RepoSMS         repoSMS
+	RepoGeoIP       repoGeoIP
+	RepoCaptcha     repoCaptcha
 	Validator       validator.Validator
 	Config          config.Config
 	Storage         storage.Storage
@@ -115,6 +263,7 @@ type Dependency struct {
 	Argon2ID        hash.Hash
 	MFAEncryptor    mfa.Encryptor
 	MFARecoveryCode mfa.RecoveryCodeGenerator
+	SignedURL       signedurl.Signer
 	UID             uid.NumberID
 	UUID            uid.StringID
 	OID             uid.StringID
@@ -123,20 +272,32 @@ type Dependency struct {
 	JWT             jwt.JWT
 	Instrument      instrument.Instrumentation
 	Enforcer        *casbin.Enforcer
+	AuthzShadow     *authz.Shadow
 	Goroutine       *goroutine.Manager
+	UserData        *userdata.Registry
 }
 
 func New(dep Dependency) *Usecase {
 	return &Usecase{
 		repoDB:          dep.RepoDB,
 		repoMessaging:   dep.RepoMessaging,
+		repoAudit:       dep.RepoAudit,
+		repoOAuth:       dep.RepoOAuth,
+		repoSAML:        dep.RepoSAML,
+		repoSMS:         dep.RepoSMS,
+		repoGeoIP:       dep.RepoGeoIP,
+		repoCaptcha:     dep.RepoCaptcha,
 		idemp:           dep.Idempotency,
+		throttle:        dep.Throttle,
+		denylist:        dep.Denylist,
+		replay:          dep.Replay,
 		validator:       dep.Validator,
 		bcrypt:          dep.Bcrypt,
 		hmac:            dep.HMAC,
 		argon2id:        dep.Argon2ID,
 		mfaEncryptor:    dep.MFAEncryptor,
 		mfaRecoveryCode: dep.MFARecoveryCode,
+		signedURL:       dep.SignedURL,
 		cfg:             dep.Config,
 		storage:         dep.Storage,
 		uid:             dep.UID,
@@ -147,7 +308,9 @@ func New(dep Dependency) *Usecase {
 		jwt:             dep.JWT,
 		ins:             dep.Instrument,
 		enforcer:        dep.Enforcer,
+		authzShadow:     dep.AuthzShadow,
 		goroutine:       dep.Goroutine,
+		userData:        dep.UserData,
 	}
 }
 
@@ -185,7 +348,7 @@ func (s *Usecase) authenticatedAndAuthorized(ctx context.Context, obj, act strin
 		return nil, goerror.NewBusiness("Authentication required", goerror.CodeUnauthorized)
 	}
 
-	ok, err := s.enforcer.Enforce(clm.Subject, obj, act)
+	ok, err := authz.Enforce(ctx, s.enforcer, s.authzShadow, clm, obj, act)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to check authorization", "user_id", clm.Subject, "error", err)
 		return nil, goerror.NewServer(err)
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+)
+
+// anonymizedFullName replaces the name of an anonymized user.
+const anonymizedFullName = "Deleted user"
+
+type AnonymizeUserInput struct {
+	UserID int64 `validate:"required,gt=0"`
+}
+
+// AnonymizeUser carries out a deletion requested through ProfileDelete. Every module that
+// registered user data erases its part first, then the account keeps only its ID: the
+// profile is replaced with placeholders and credentials, sign-in methods, sessions and
+// roles are removed. A message that arrives before the grace period ends is published
+// again for the remaining time.
+func (s *Usecase) AnonymizeUser(ctx context.Context, in AnonymizeUserInput) error {
+	ctx, span := s.startSpan(ctx, "AnonymizeUser")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	deletion, err := s.repoDB.GetUserDeletion(ctx, in.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user deletion not found", "user_id", in.UserID)
+		return nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user deletion", "user_id", in.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if deletion.CompletedAt != nil {
+		return nil
+	}
+
+	if s.clock.Now().Before(deletion.ScheduledAt) {
+		s.publishUserDeletion(ctx, deletion)
+		return nil
+	}
+
+	user, err := s.repoDB.GetUserByID(ctx, in.UserID, true)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", in.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	for _, src := range s.userData.Sources() {
+		if src.Erase == nil {
+			continue
+		}
+
+		if err := src.Erase(ctx, user.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to erase user data", "user_id", user.ID, "source", src.Name, "error", err)
+			return goerror.NewServer(err)
+		}
+	}
+
+	if _, err := s.enforcer.DeleteUser(strconv.FormatInt(user.ID, 10)); err != nil {
+		slog.ErrorContext(ctx, "failed to delete user roles", "user_id", user.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if _, err := s.enforcer.RemoveFilteredNamedGroupingPolicy("g2", 0, strconv.FormatInt(user.ID, 10)); err != nil {
+		slog.ErrorContext(ctx, "failed to delete user organization roles", "user_id", user.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	keys, err := s.repoDB.GetActiveAPIKeys(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get active api keys", "user_id", user.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	for _, key := range keys {
+		s.removeAPIKeyScopes(ctx, key.ID)
+	}
+
+	if err := s.repoDB.AnonymizeUser(ctx, entity.AnonymizeUser{
+		ID:       user.ID,
+		Email:    fmt.Sprintf("deleted-%d@anonymized.invalid", user.ID),
+		FullName: anonymizedFullName,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to repo anonymize user", "user_id", user.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.revokeUserAccessTokens(ctx, user.ID)
+	s.deleteAvatarObject(ctx, user.ID, user.AvatarURL)
+
+	s.recordAudit(ctx, entity.AuditActionUserAnonymize, 0, user.ID, nil)
+
+	return nil
+}
+
+// deleteAvatarObject removes an uploaded avatar from storage. Avatars hosted elsewhere are
+// left alone, and a failure is only logged since the URL is already gone from the account.
+func (s *Usecase) deleteAvatarObject(ctx context.Context, userID int64, avatarURL string) {
+	baseURL := strings.TrimSpace(s.cfg.GetString("modules.identity.avatar_base_url"))
+	key, ok := strings.CutPrefix(avatarURL, baseURL+"/")
+	if baseURL == "" || !ok || key == "" {
+		return
+	}
+
+	bucket := strings.TrimSpace(s.cfg.GetString("modules.identity.avatar_bucket"))
+	if err := s.storage.DeleteObject(ctx, bucket, key); err != nil {
+		slog.WarnContext(ctx, "failed to delete avatar of anonymized user", "user_id", userID, "key", key, "error", err)
+	}
+}
@@ -32,12 +32,16 @@ func (s *Usecase) UserCreate(ctx context.Context, in UserCreateInput) error {
 		return goerror.NewInvalidInput(err)
 	}
 
+	if err := s.checkPasswordPolicy("password", in.Password, in.Email); err != nil {
+		return err
+	}
+
 	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtUsers, constant.PermActCreate)
 	if err != nil {
 		return err
 	}
 
-	user, err := s.repoDB.GetUserByEmail(ctx, in.Email, true)
+	user, err := s.getUserByEmail(ctx, in.Email, true)
 	if err == nil && user != nil {
 		slog.WarnContext(ctx, "user account is already exists", "email", in.Email)
 		return goerror.NewBusiness("user account with that email already exists", goerror.CodeConflict)
@@ -55,7 +59,7 @@ func (s *Usecase) UserCreate(ctx context.Context, in UserCreateInput) error {
 
 	newUser := entity.NewUser{
 		ID:        s.uid.Generate(),
-		Email:     in.Email,
+		Email:     s.normalizeEmail(in.Email),
 		FullName:  in.FullName,
 		AvatarURL: "https://ui-avatars.com/api/?name=" + url.QueryEscape(in.FullName),
 		Status:    in.Status,
@@ -63,10 +67,18 @@ func (s *Usecase) UserCreate(ctx context.Context, in UserCreateInput) error {
 		UpdatedBy: clm.UserID,
 	}
 
+	newUser.EmailHash, newUser.EmailSealedOnly, err = s.emailLookupHash(newUser.Email)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash email", "error", err)
+		return goerror.NewServer(err)
+	}
+
 	if err := s.repoDB.NewUser(ctx, newUser, string(hashedPassword)); err != nil {
 		slog.ErrorContext(ctx, "failed to repo create new user", "new_user", newUser, "error", err)
 		return goerror.NewServer(err)
 	}
 
+	s.recordAudit(ctx, entity.AuditActionUserCreate, clm.UserID, newUser.ID, map[string]any{"status": newUser.Status.String()})
+
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/shared/constant"
 )
@@ -28,6 +29,10 @@ func (s *Usecase) UserDelete(ctx context.Context, in UserDeleteInput) error {
 		return err
 	}
 
+	if err := s.requireRecentAuth(ctx, clm); err != nil {
+		return err
+	}
+
 	user, err := s.repoDB.GetUserByID(ctx, in.ID, true)
 	if errors.Is(err, goerror.ErrNotFound) {
 		slog.WarnContext(ctx, "user not found", "user_id", in.ID)
@@ -42,10 +47,19 @@ func (s *Usecase) UserDelete(ctx context.Context, in UserDeleteInput) error {
 		return nil
 	}
 
+	if err := s.repoDB.RevokeAllRefreshToken(ctx, user.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to repo revoke all refresh token", "user_id", user.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
 	if err := s.repoDB.MarkUserDeleted(ctx, user.ID, clm.UserID); err != nil {
 		slog.ErrorContext(ctx, "failed to mark user deleted", "user_id", user.ID, "by_user_id", clm.UserID, "error", err)
 		return goerror.NewServer(err)
 	}
 
+	s.revokeUserAccessTokens(ctx, user.ID)
+
+	s.recordAudit(ctx, entity.AuditActionUserDelete, clm.UserID, user.ID, nil)
+
 	return nil
 }
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"iter"
 	"log/slog"
 	"time"
 
@@ -36,22 +37,7 @@ func (s *Usecase) UserExport(ctx context.Context, in UserExportInput) (*UserExpo
 		return nil, err
 	}
 
-	filterData := entity.UserListFilterData{
-		OrderBy:        in.SortBy,
-		OrderDirection: in.SortOrder,
-		Search:         in.Search,
-		Statuses:       entity.ToInt16Slice(entity.ParseSafeUserStatuses(in.Statuses)),
-		DateFrom:       in.DateFrom,
-		DateTo:         in.DateTo,
-		Size:           userExportPageSize,
-		Page:           0,
-	}
-	if in.Search != "" {
-		filterData.IsFilterBySearch = true
-	}
-	if len(filterData.Statuses) > 0 {
-		filterData.IsFilterByStatus = true
-	}
+	filterData := userExportFilter(in)
 
 	var (
 		users []entity.User
@@ -87,3 +73,60 @@ func (s *Usecase) UserExport(ctx context.Context, in UserExportInput) (*UserExpo
 
 	return &UserExportOutput{Users: users}, nil
 }
+
+// UserExportStream authorizes an export up front, then returns every matching user from a
+// single database cursor while the caller writes them out, so exports of any size run in
+// constant memory.
+func (s *Usecase) UserExportStream(ctx context.Context, in UserExportInput) (iter.Seq2[entity.User, error], error) {
+	ctx, span := s.startSpan(ctx, "UserExportStream")
+	defer span.End()
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtUsers, constant.PermActCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "users export started",
+		"actor_id", clm.UserID,
+		"search", in.Search,
+		"statuses", in.Statuses,
+		"date_from", in.DateFrom,
+		"date_to", in.DateTo,
+	)
+
+	users := s.repoDB.StreamUsers(ctx, userExportFilter(in))
+
+	return func(yield func(entity.User, error) bool) {
+		for user, err := range users {
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to repo stream users", "error", err)
+				yield(entity.User{}, goerror.NewServer(err))
+				return
+			}
+			if !yield(user, nil) {
+				return
+			}
+		}
+	}, nil
+}
+
+func userExportFilter(in UserExportInput) entity.UserListFilterData {
+	filterData := entity.UserListFilterData{
+		OrderBy:        in.SortBy,
+		OrderDirection: in.SortOrder,
+		Search:         in.Search,
+		Statuses:       entity.ToInt16Slice(entity.ParseSafeUserStatuses(in.Statuses)),
+		DateFrom:       in.DateFrom,
+		DateTo:         in.DateTo,
+		Size:           userExportPageSize,
+		Page:           0,
+	}
+	if in.Search != "" {
+		filterData.IsFilterBySearch = true
+	}
+	if len(filterData.Statuses) > 0 {
+		filterData.IsFilterByStatus = true
+	}
+
+	return filterData
+}
@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/storage"
+	"github.com/shandysiswandi/gobite/internal/pkg/tabular"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type (
+	UserExportJobCreateInput struct {
+		Format tabular.Format `validate:"required,oneof=csv xlsx"`
+		Filter UserExportInput
+	}
+
+	UserExportJobGetInput struct {
+		ID int64 `validate:"required,gt=0"`
+	}
+
+	UserExportJobOutput struct {
+		Job entity.ExportJob
+		// URL is a presigned download link, set once the job completed.
+		URL       string
+		ExpiresAt time.Time
+	}
+
+	RunUserExportJobInput struct {
+		JobID int64
+	}
+)
+
+// UserExportHeader is the header row of a user export file.
+var UserExportHeader = []string{"id", "email", "full_name", "avatar_url", "status", "updated_at"}
+
+// UserExportRecord is the row of user in a user export file, in UserExportHeader order.
+func UserExportRecord(user entity.User) []string {
+	updatedAt := ""
+	if !user.UpdatedAt.IsZero() {
+		updatedAt = user.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+
+	return []string{
+		strconv.FormatInt(user.ID, 10),
+		user.Email,
+		user.FullName,
+		user.AvatarURL,
+		strings.ToLower(user.Status.String()),
+		updatedAt,
+	}
+}
+
+// UserExportJobCreate queues an export of the users matching in.Filter. The file is written
+// by the user_export_requested_identity consumer; the caller polls UserExportJobGet.
+func (s *Usecase) UserExportJobCreate(ctx context.Context, in UserExportJobCreateInput) (*UserExportJobOutput, error) {
+	ctx, span := s.startSpan(ctx, "UserExportJobCreate")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtUsers, constant.PermActCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.repoDB.CreateExportJob(ctx, entity.ExportJob{
+		ID:          s.uid.Generate(),
+		RequestedBy: clm.UserID,
+		Format:      string(in.Format),
+		Filters:     userExportJobFilters(in.Filter),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo create export job", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if err := s.repoMessaging.PublishUserExportRequested(ctx, contracts.UserExportRequested{JobID: job.ID}); err != nil {
+		slog.ErrorContext(ctx, "failed to publish user export requested", "job_id", job.ID, "error", err)
+		if err := s.repoDB.FailExportJob(context.WithoutCancel(ctx), job.ID, "failed to queue the export"); err != nil {
+			slog.ErrorContext(ctx, "failed to repo fail export job", "job_id", job.ID, "error", err)
+		}
+		return nil, goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionUserExport, clm.UserID, 0, map[string]any{
+		"job_id": job.ID,
+		"format": job.Format,
+	})
+
+	return &UserExportJobOutput{Job: *job}, nil
+}
+
+// UserExportJobGet returns an export job of the caller, with a download link once it completed.
+// Jobs of other users are reported as not found.
+func (s *Usecase) UserExportJobGet(ctx context.Context, in UserExportJobGetInput) (*UserExportJobOutput, error) {
+	ctx, span := s.startSpan(ctx, "UserExportJobGet")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtUsers, constant.PermActCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.repoDB.GetExportJob(ctx, in.ID)
+	if errors.Is(err, goerror.ErrNotFound) || (err == nil && job.RequestedBy != clm.UserID) {
+		return nil, goerror.NewBusiness("export job not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get export job", "job_id", in.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	out := &UserExportJobOutput{Job: *job}
+	if job.Status != entity.ExportJobStatusCompleted {
+		return out, nil
+	}
+
+	bucket := s.cfg.GetString("modules.identity.user_export_job.bucket")
+	ttl := s.cfg.GetMinute("modules.identity.user_export_job.url_ttl_minutes")
+
+	out.URL, err = s.storage.PresignGet(ctx, bucket, job.ObjectKey, ttl)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to presign user export", "job_id", job.ID, "key", job.ObjectKey, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	out.ExpiresAt = s.clock.Now().Add(ttl)
+
+	return out, nil
+}
+
+// RunUserExportJob writes the file of a pending export job to object storage. A job that is
+// no longer pending is skipped, so a redelivered message does nothing. Failures are recorded
+// on the job rather than returned, since retrying would find the job already running.
+func (s *Usecase) RunUserExportJob(ctx context.Context, in RunUserExportJobInput) error {
+	ctx, span := s.startSpan(ctx, "RunUserExportJob")
+	defer span.End()
+
+	job, err := s.repoDB.GetExportJob(ctx, in.JobID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "export job not found", "job_id", in.JobID)
+		return nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get export job", "job_id", in.JobID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	filter := userExportFilter(userExportJobInput(job.Filters))
+
+	countFilter := filter
+	countFilter.Size = 1
+	_, total, err := s.repoDB.GetUserList(ctx, countFilter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo count export users", "job_id", job.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	started, err := s.repoDB.StartExportJob(ctx, job.ID, total)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo start export job", "job_id", job.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+	if !started {
+		slog.InfoContext(ctx, "export job is no longer pending", "job_id", job.ID, "status", job.Status.String())
+		return nil
+	}
+
+	key, written, reason, err := s.writeUserExport(ctx, job, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to run export job", "job_id", job.ID, "reason", reason, "error", err)
+		if err := s.repoDB.FailExportJob(context.WithoutCancel(ctx), job.ID, reason); err != nil {
+			slog.ErrorContext(ctx, "failed to repo fail export job", "job_id", job.ID, "error", err)
+		}
+		return nil
+	}
+
+	if err := s.repoDB.CompleteExportJob(ctx, job.ID, written, key); err != nil {
+		slog.ErrorContext(ctx, "failed to repo complete export job", "job_id", job.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	slog.InfoContext(ctx, "export job completed", "job_id", job.ID, "rows", written, "key", key)
+
+	return nil
+}
+
+// writeUserExport streams the users into a temporary file, so the upload has a known size
+// on every storage backend, then stores it. reason is the failure shown to the requester.
+func (s *Usecase) writeUserExport(ctx context.Context, job *entity.ExportJob, filter entity.UserListFilterData) (key string, written int64, reason string, err error) {
+	format := tabular.Format(job.Format)
+
+	f, err := os.CreateTemp("", "gobite-user-export-*")
+	if err != nil {
+		return "", 0, "failed to write the export", err
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	tw, err := tabular.NewWriter(f, format)
+	if err != nil {
+		return "", 0, "failed to write the export", err
+	}
+	if err := tw.WriteRow(UserExportHeader); err != nil {
+		return "", 0, "failed to write the export", err
+	}
+
+	progressRows := int64(s.cfg.GetInt("modules.identity.user_export_job.progress_rows"))
+	if progressRows <= 0 {
+		progressRows = 1_000
+	}
+
+	for user, err := range s.repoDB.StreamUsers(ctx, filter) {
+		if err != nil {
+			return "", written, "failed to read the users", err
+		}
+		if err := tw.WriteRow(UserExportRecord(user)); err != nil {
+			return "", written, "failed to write the export", err
+		}
+
+		written++
+		if written%progressRows == 0 {
+			if err := s.repoDB.UpdateExportJobProgress(ctx, job.ID, written); err != nil {
+				// progress is informational, the export carries on
+				slog.WarnContext(ctx, "failed to repo update export job progress", "job_id", job.ID, "error", err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return "", written, "failed to write the export", err
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", written, "failed to write the export", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", written, "failed to write the export", err
+	}
+
+	bucket := s.cfg.GetString("modules.identity.user_export_job.bucket")
+	prefix := strings.Trim(s.cfg.GetString("modules.identity.user_export_job.prefix"), "/")
+
+	key = fmt.Sprintf("%d.%s", job.ID, format)
+	if prefix != "" {
+		key = prefix + "/" + key
+	}
+
+	if _, err := s.storage.PutObject(ctx, bucket, key, f, storage.PutOptions{
+		Size:        size,
+		ContentType: tabular.ContentType(format),
+		Metadata:    map[string]string{"job_id": strconv.FormatInt(job.ID, 10)},
+	}); err != nil {
+		return "", written, "failed to upload the export", err
+	}
+
+	return key, written, "", nil
+}
+
+// userExportJobFilters stores the filters of an export job; userExportJobInput reads them back.
+func userExportJobFilters(in UserExportInput) valueobject.JSONMap {
+	filters := valueobject.JSONMap{}
+	filters.Set("search", in.Search)
+	filters.Set("statuses", strings.Join(in.Statuses, ","))
+	filters.Set("sort_by", in.SortBy)
+	filters.Set("sort_order", in.SortOrder)
+	if !in.DateFrom.IsZero() {
+		filters.Set("date_from", in.DateFrom.UTC().Format(time.RFC3339Nano))
+	}
+	if !in.DateTo.IsZero() {
+		filters.Set("date_to", in.DateTo.UTC().Format(time.RFC3339Nano))
+	}
+
+	return filters
+}
+
+func userExportJobInput(filters valueobject.JSONMap) UserExportInput {
+	in := UserExportInput{
+		Search:    filters.GetString("search"),
+		SortBy:    filters.GetString("sort_by"),
+		SortOrder: filters.GetString("sort_order"),
+	}
+	if statuses := filters.GetString("statuses"); statuses != "" {
+		in.Statuses = strings.Split(statuses, ",")
+	}
+	in.DateFrom, _ = time.Parse(time.RFC3339Nano, filters.GetString("date_from"))
+	in.DateTo, _ = time.Parse(time.RFC3339Nano, filters.GetString("date_to"))
+
+	return in
+}
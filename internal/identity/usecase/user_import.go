@@ -44,29 +44,20 @@ func (s *Usecase) UserImport(ctx context.Context, in UserImportInput) (*UserImpo
 
 	users := make([]entity.UpsertUser, 0, len(in.Users))
 	hashes := make(map[string]string, len(in.Users))
+	seen := make(map[string]bool, len(in.Users))
 	for _, item := range in.Users {
-		email := strings.TrimSpace(strings.ToLower(item.Email))
-		fullName := strings.TrimSpace(item.FullName)
-
-		if item.Password != "" {
-			hash, err := s.bcrypt.Hash(item.Password)
-			if err != nil {
-				slog.ErrorContext(ctx, "failed to hash new password", "email", item.Email, "error", err)
-				return nil, goerror.NewServer(err)
-			}
-			hashes[email] = string(hash)
+		email := s.normalizeEmail(item.Email)
+		if seen[email] {
+			return nil, goerror.NewInvalidInput(nil, "users", "duplicate email after normalization: "+item.Email)
 		}
+		seen[email] = true
 
-		upsertUser := entity.UpsertUser{
-			ID:        s.uid.Generate(),
-			CreatedBy: clm.UserID,
-			UpdatedBy: clm.UserID,
-			Email:     email,
-			FullName:  fullName,
-			Status:    item.Status,
+		upsertUser, hash, err := s.importUser(ctx, clm.UserID, email, item)
+		if err != nil {
+			return nil, err
 		}
-		if fullName != "" {
-			upsertUser.AvatarURL = "https://ui-avatars.com/api/?name=" + url.QueryEscape(fullName)
+		if hash != "" {
+			hashes[email] = hash
 		}
 
 		users = append(users, upsertUser)
@@ -78,5 +69,47 @@ func (s *Usecase) UserImport(ctx context.Context, in UserImportInput) (*UserImpo
 		return nil, goerror.NewServer(err)
 	}
 
+	s.recordAudit(ctx, entity.AuditActionUserImport, clm.UserID, 0, map[string]any{
+		"created": created,
+		"updated": updated,
+	})
+
 	return &UserImportOutput{Created: created, Updated: updated}, nil
 }
+
+// importUser prepares one imported user for UpsertUsers. email must already be normalized.
+// It returns the password hash, empty when the row sets no password.
+func (s *Usecase) importUser(ctx context.Context, actorID int64, email string, item UserImportUserInput) (entity.UpsertUser, string, error) {
+	fullName := strings.TrimSpace(item.FullName)
+
+	var hash string
+	if item.Password != "" {
+		raw, err := s.bcrypt.Hash(item.Password)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to hash new password", "email", item.Email, "error", err)
+			return entity.UpsertUser{}, "", goerror.NewServer(err)
+		}
+		hash = string(raw)
+	}
+
+	upsertUser := entity.UpsertUser{
+		ID:        s.uid.Generate(),
+		CreatedBy: actorID,
+		UpdatedBy: actorID,
+		Email:     email,
+		FullName:  fullName,
+		Status:    item.Status,
+	}
+	if fullName != "" {
+		upsertUser.AvatarURL = "https://ui-avatars.com/api/?name=" + url.QueryEscape(fullName)
+	}
+
+	var err error
+	upsertUser.EmailHash, upsertUser.EmailSealedOnly, err = s.emailLookupHash(email)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash email", "email", email, "error", err)
+		return entity.UpsertUser{}, "", goerror.NewServer(err)
+	}
+
+	return upsertUser, hash, nil
+}
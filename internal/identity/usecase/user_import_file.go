@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/tabular"
+	"github.com/shandysiswandi/gobite/internal/pkg/validator"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type (
+	UserImportFileInput struct {
+		Format tabular.Format `validate:"required,oneof=csv xlsx"`
+		File   io.Reader      `validate:"required"`
+	}
+
+	UserImportRowError struct {
+		// Row is the 1-based row in the file; the header is row 1.
+		Row     int
+		Field   string
+		Message string
+	}
+
+	UserImportFileOutput struct {
+		Created int
+		Updated int
+		Failed  int
+		// Errors lists the first modules.identity.user_import.max_errors rejected rows.
+		Errors []UserImportRowError
+		// Truncated is set when the file had more than modules.identity.user_import.max_rows
+		// rows; the rows after the limit were not read.
+		Truncated bool
+	}
+)
+
+// userImportColumns maps the accepted header names to UserImportUserInput fields.
+var userImportColumns = map[string]string{
+	"email":     "email",
+	"password":  "password",
+	"full_name": "full_name",
+	"fullname":  "full_name",
+	"name":      "full_name",
+	"status":    "status",
+}
+
+// UserImportFile imports users from an uploaded CSV or XLSX file. The first row is a header
+// naming the email, password, full_name and status columns. Rows are read one at a time,
+// invalid rows are reported and skipped, and valid rows are saved in batches of
+// modules.identity.user_import.batch_size, so a batch already saved stays saved when a
+// later one fails.
+func (s *Usecase) UserImportFile(ctx context.Context, in UserImportFileInput) (*UserImportFileOutput, error) {
+	ctx, span := s.startSpan(ctx, "UserImportFile")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtUsers, constant.PermActCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := s.cfg.GetInt("modules.identity.user_import.batch_size")
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	maxRows := s.cfg.GetInt("modules.identity.user_import.max_rows")
+	maxErrors := s.cfg.GetInt("modules.identity.user_import.max_errors")
+	maxFileMB := s.cfg.GetInt("modules.identity.user_import.max_file_mb")
+
+	rows, err := tabular.NewReader(in.File, in.Format, int64(maxFileMB)<<20)
+	if err != nil {
+		return nil, s.userImportFileError(ctx, err, maxFileMB)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.ErrorContext(ctx, "failed to close import file", "error", err)
+		}
+	}()
+
+	header, err := rows.Next()
+	if errors.Is(err, io.EOF) {
+		return nil, goerror.NewInvalidInput(nil, "file", "file is empty")
+	}
+	if err != nil {
+		return nil, s.userImportFileError(ctx, err, maxFileMB)
+	}
+
+	columns, err := userImportHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &UserImportFileOutput{Errors: make([]UserImportRowError, 0)}
+	reject := func(row int, field, msg string) {
+		out.Failed++
+		if maxErrors <= 0 || len(out.Errors) < maxErrors {
+			out.Errors = append(out.Errors, UserImportRowError{Row: row, Field: field, Message: msg})
+		}
+	}
+
+	users := make([]entity.UpsertUser, 0, batchSize)
+	hashes := make(map[string]string, batchSize)
+	flush := func() error {
+		created, updated, err := s.repoDB.UpsertUsers(ctx, users, hashes)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo upsert users", "error", err)
+			return goerror.NewServer(err)
+		}
+		out.Created += created
+		out.Updated += updated
+		users = users[:0]
+		clear(hashes)
+
+		return nil
+	}
+
+	seen := make(map[string]int)
+	read := 0
+	for rowNum := 2; ; rowNum++ {
+		record, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, s.userImportFileError(ctx, err, maxFileMB)
+		}
+		if isBlankRow(record) {
+			continue
+		}
+
+		if maxRows > 0 && read >= maxRows {
+			out.Truncated = true
+			break
+		}
+		read++
+
+		item, field, msg := userImportRow(columns, record)
+		if field != "" {
+			reject(rowNum, field, msg)
+			continue
+		}
+
+		if err := s.validator.Validate(item); err != nil {
+			var errV10 validator.V10ValidationError
+			if !errors.As(err, &errV10) {
+				return nil, goerror.NewServer(err)
+			}
+			// one entry per row keeps the error list readable
+			fields := errV10.Values()
+			for _, field := range []string{"email", "password", "full_name", "status"} {
+				if msg, ok := fields[field]; ok {
+					reject(rowNum, field, msg)
+					break
+				}
+			}
+			continue
+		}
+
+		email := s.normalizeEmail(item.Email)
+		if first, ok := seen[email]; ok {
+			reject(rowNum, "email", "duplicate of row "+strconv.Itoa(first))
+			continue
+		}
+		seen[email] = rowNum
+
+		upsertUser, hash, err := s.importUser(ctx, clm.UserID, email, item)
+		if err != nil {
+			return nil, err
+		}
+		if hash != "" {
+			hashes[email] = hash
+		}
+		users = append(users, upsertUser)
+
+		if len(users) >= batchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	s.recordAudit(ctx, entity.AuditActionUserImport, clm.UserID, 0, map[string]any{
+		"source":    string(in.Format),
+		"created":   out.Created,
+		"updated":   out.Updated,
+		"failed":    out.Failed,
+		"truncated": out.Truncated,
+	})
+
+	return out, nil
+}
+
+func (s *Usecase) userImportFileError(ctx context.Context, err error, maxFileMB int) error {
+	switch {
+	case errors.Is(err, tabular.ErrFileTooLarge):
+		return goerror.NewInvalidInput(nil, "file", "file exceeds "+strconv.Itoa(maxFileMB)+" MB")
+	case errors.Is(err, tabular.ErrInvalidFile), errors.Is(err, tabular.ErrUnsupportedFormat):
+		slog.WarnContext(ctx, "invalid import file", "error", err)
+		return goerror.NewInvalidInput(nil, "file", "file cannot be read as CSV or XLSX")
+	default:
+		slog.ErrorContext(ctx, "failed to read import file", "error", err)
+		return goerror.NewServer(err)
+	}
+}
+
+// userImportHeader maps each known column name to its index in the file.
+func userImportHeader(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		key = strings.ReplaceAll(key, " ", "_")
+
+		field, ok := userImportColumns[key]
+		if !ok {
+			continue
+		}
+		if _, dup := columns[field]; dup {
+			return nil, goerror.NewInvalidInput(nil, "file", "duplicate column: "+name)
+		}
+		columns[field] = i
+	}
+
+	if _, ok := columns["email"]; !ok {
+		return nil, goerror.NewInvalidInput(nil, "file", "header row must have an email column")
+	}
+
+	return columns, nil
+}
+
+// userImportRow reads one record through the header columns. A non-empty field reports a
+// value that cannot be converted.
+func userImportRow(columns map[string]int, record []string) (item UserImportUserInput, field, msg string) {
+	cell := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	item = UserImportUserInput{
+		Email:    cell("email"),
+		Password: cell("password"),
+		FullName: cell("full_name"),
+	}
+
+	if raw := cell("status"); raw != "" {
+		status, ok := parseImportStatus(raw)
+		if !ok {
+			return item, "status", "status must be one of unverified, active, banned, inactive or 1-4"
+		}
+		item.Status = status
+	}
+
+	return item, "", ""
+}
+
+// parseImportStatus accepts a status number or its name, e.g. "2" or "active".
+func parseImportStatus(raw string) (entity.UserStatus, bool) {
+	if n, err := strconv.ParseInt(raw, 10, 16); err == nil {
+		status := entity.UserStatus(n)
+		return status, !status.IsUnknown()
+	}
+
+	for _, status := range []entity.UserStatus{
+		entity.UserStatusUnverified,
+		entity.UserStatusActive,
+		entity.UserStatusBanned,
+		entity.UserStatusInactive,
+	} {
+		if strings.EqualFold(raw, status.String()) {
+			return status, true
+		}
+	}
+
+	return entity.UserStatusUnknown, false
+}
+
+func isBlankRow(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+const triggerKeyUserInvite = "user_invite"
+
+type UserInviteInput struct {
+	Email    string `validate:"required,email"`
+	FullName string `validate:"required,min=5,max=100,alphaspace"`
+}
+
+// UserInvite creates a pending account and emails its owner a link to choose a password.
+// Inviting an address whose account is still unverified sends a new link and invalidates
+// the previous one, which is how an expired invitation is renewed.
+func (s *Usecase) UserInvite(ctx context.Context, in UserInviteInput) error {
+	ctx, span := s.startSpan(ctx, "UserInvite")
+	defer span.End()
+
+	in.Email = strings.TrimSpace(strings.ToLower(in.Email))
+	in.FullName = strings.TrimSpace(in.FullName)
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtUsers, constant.PermActCreate)
+	if err != nil {
+		return err
+	}
+
+	cToken := s.oid.Generate()
+	cTokenHash, err := s.hmac.Hash(cToken)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token", "error", err)
+		return goerror.NewServer(err)
+	}
+
+	challenge := entity.Challenge{
+		ID:        s.uid.Generate(),
+		Token:     string(cTokenHash),
+		Purpose:   entity.ChallengePurposeUserInvite,
+		ExpiresAt: s.clock.Now().Add(s.cfg.GetHour("modules.identity.invite_ttl_hours")),
+	}
+
+	user, err := s.getUserByEmail(ctx, in.Email, true)
+	switch {
+	case err == nil:
+		if user.DeletedAt != nil || user.Status != entity.UserStatusUnverified {
+			slog.WarnContext(ctx, "user account is already exists", "email", in.Email)
+			return goerror.NewBusiness("user account with that email already exists", goerror.CodeConflict)
+		}
+
+		challenge.UserID = user.ID
+		if err := s.renewUserInvite(ctx, challenge); err != nil {
+			return err
+		}
+
+	case errors.Is(err, goerror.ErrNotFound):
+		user, err = s.newInvitedUser(ctx, in, clm.UserID, challenge)
+		if err != nil {
+			return err
+		}
+
+	default:
+		slog.ErrorContext(ctx, "failed to repo get user by email", "email", in.Email, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.repoMessaging.PublishNotificationRequested(ctx, contracts.NotificationRequested{
+		UserID:     user.ID,
+		Email:      user.Email,
+		TriggerKey: triggerKeyUserInvite,
+		Channels:   []string{"email"},
+		Data: map[string]any{
+			"full_name":  user.FullName,
+			"invite_url": s.cfg.GetString("app.web") + "/invite/accept?token=" + url.QueryEscape(cToken),
+			"expires_at": challenge.ExpiresAt.Format(time.RFC3339),
+		},
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to publish user invitation", "user_id", user.ID, "error", err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionUserInvite, clm.UserID, user.ID, nil)
+
+	return nil
+}
+
+// newInvitedUser creates the pending account of an invitation. Its password is random and
+// never shared, so the account can only be entered through the invitation link.
+func (s *Usecase) newInvitedUser(ctx context.Context, in UserInviteInput, actorID int64, challenge entity.Challenge) (*entity.User, error) {
+	hashedPassword, err := s.bcrypt.Hash(s.oid.Generate())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash password", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	newUser := entity.NewUser{
+		ID:        s.uid.Generate(),
+		Email:     s.normalizeEmail(in.Email),
+		FullName:  in.FullName,
+		AvatarURL: "https://ui-avatars.com/api/?name=" + url.QueryEscape(in.FullName),
+		Status:    entity.UserStatusUnverified,
+		CreatedBy: actorID,
+		UpdatedBy: actorID,
+	}
+
+	newUser.EmailHash, newUser.EmailSealedOnly, err = s.emailLookupHash(newUser.Email)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash email", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	challenge.UserID = newUser.ID
+	if err := s.repoDB.NewRegistration(ctx, newUser, challenge, string(hashedPassword)); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create invited user", "email", newUser.Email, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &entity.User{
+		ID:       newUser.ID,
+		Email:    newUser.Email,
+		FullName: newUser.FullName,
+		Status:   newUser.Status,
+	}, nil
+}
+
+func (s *Usecase) renewUserInvite(ctx context.Context, challenge entity.Challenge) error {
+	if _, err := s.repoDB.DeleteChallengeByUserPurpose(ctx, challenge.UserID, entity.ChallengePurposeUserInvite); err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete pending invitation", "user_id", challenge.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.CreateChallenge(ctx, challenge); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create invitation challenge", "user_id", challenge.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	return nil
+}
+
+type UserInviteAcceptInput struct {
+	ChallengeToken string `validate:"required"`
+	Password       string `validate:"required,password"`
+}
+
+// UserInviteAccept sets the password of an invited user and activates the account.
+func (s *Usecase) UserInviteAccept(ctx context.Context, in UserInviteAcceptInput) error {
+	ctx, span := s.startSpan(ctx, "UserInviteAccept")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	cTokenHash, err := s.hmac.Hash(in.ChallengeToken)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash token", "error", err)
+		return goerror.NewServer(err)
+	}
+
+	cu, err := s.repoDB.GetChallengeUserByTokenPurpose(ctx, string(cTokenHash), entity.ChallengePurposeUserInvite)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("invalid or expired invitation token", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get challenge user by token purpose", "challenge_token", string(cTokenHash), "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if cu.UserStatus != entity.UserStatusUnverified {
+		slog.WarnContext(ctx, "invitation for user that is not pending", "user_id", cu.UserID, "status", cu.UserStatus.String())
+		return goerror.NewBusiness("invalid or expired invitation token", goerror.CodeUnauthorized)
+	}
+
+	if err := s.checkPasswordPolicy("password", in.Password, cu.UserEmail); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.bcrypt.Hash(in.Password)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to hash password", "user_id", cu.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if err := s.repoDB.AcceptUserInvite(ctx, entity.AcceptUserInvite{
+		ChallengeID: cu.ChallengeID,
+		UserID:      cu.UserID,
+		Hash:        string(hashedPassword),
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to repo accept user invite", "user_id", cu.UserID, "challenge_id", cu.ChallengeID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionUserInviteAccept, cu.UserID, cu.UserID, nil)
+
+	return nil
+}
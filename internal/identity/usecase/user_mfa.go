@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type (
+	UserMFAInput struct {
+		ID int64 `validate:"required,gt=0"`
+	}
+
+	UserMFAOutput struct {
+		Factors              []entity.MFAFactorInfo
+		BackupCodesRemaining int
+	}
+)
+
+func (s *Usecase) UserMFA(ctx context.Context, in UserMFAInput) (*UserMFAOutput, error) {
+	ctx, span := s.startSpan(ctx, "UserMFA")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtUsers, constant.PermActRead)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repoDB.GetUserByID(ctx, in.ID, false)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user not found", "user_id", in.ID)
+		return nil, goerror.NewBusiness("user not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", in.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	factors, err := s.repoDB.GetMFAFactorAllByUserID(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get mfa factors", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	codes, err := s.repoDB.GetMFABackupCodeByUserID(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get mfa backup codes", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	// inspecting another user's factors is a privileged action, so it is not served without an audit entry
+	if err := s.repoDB.CreateAuditLog(ctx, entity.AuditLog{
+		ID:           s.uid.Generate(),
+		ActorID:      clm.UserID,
+		TargetUserID: user.ID,
+		Action:       entity.AuditActionUserMFAInspect,
+		Metadata:     valueobject.JSONMap{"factor_count": len(factors)},
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create audit log", "user_id", user.ID, "by_user_id", clm.UserID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &UserMFAOutput{Factors: factors, BackupCodesRemaining: len(codes)}, nil
+}
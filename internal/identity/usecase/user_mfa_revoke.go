@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type (
+	UserMFARevokeInput struct {
+		ID     int64  `validate:"required,gt=0"`
+		Reason string `validate:"required,min=10,max=500"`
+	}
+)
+
+func (s *Usecase) UserMFARevoke(ctx context.Context, in UserMFARevokeInput) error {
+	ctx, span := s.startSpan(ctx, "UserMFARevoke")
+	defer span.End()
+
+	in.Reason = strings.TrimSpace(in.Reason)
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtUsers, constant.PermActDelete)
+	if err != nil {
+		return err
+	}
+
+	if clm.UserID == in.ID {
+		slog.WarnContext(ctx, "user tried to revoke own mfa through management endpoint", "user_id", in.ID)
+		return goerror.NewBusiness("cannot revoke your own MFA", goerror.CodeForbidden)
+	}
+
+	user, err := s.repoDB.GetUserByID(ctx, in.ID, false)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user not found", "user_id", in.ID)
+		return goerror.NewBusiness("user not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", in.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	factors, err := s.repoDB.GetMFAFactorAllByUserID(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get mfa factors", "user_id", user.ID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if len(factors) == 0 {
+		return nil
+	}
+
+	factorIDs := make([]int64, 0, len(factors))
+	factorTypes := make([]string, 0, len(factors))
+	for _, f := range factors {
+		factorIDs = append(factorIDs, f.ID)
+		factorTypes = append(factorTypes, f.Type.String())
+	}
+
+	meta := valueobject.JSONMap{
+		"reason":       in.Reason,
+		"factor_ids":   factorIDs,
+		"factor_types": factorTypes,
+	}
+
+	if err := s.repoDB.RevokeUserMFA(ctx, user.ID, entity.AuditLog{
+		ID:           s.uid.Generate(),
+		ActorID:      clm.UserID,
+		TargetUserID: user.ID,
+		Action:       entity.AuditActionUserMFARevoke,
+		Metadata:     meta,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to repo revoke user mfa", "user_id", user.ID, "by_user_id", clm.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionUserMFARevoke, clm.UserID, user.ID, meta)
+
+	if err := s.repoMessaging.PublishUserMFARevoked(ctx, contracts.MFARevoked{
+		UserID:   user.ID,
+		Email:    user.Email,
+		FullName: user.FullName,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to publish user mfa revoked", "user_id", user.ID, "error", err)
+	}
+
+	return nil
+}
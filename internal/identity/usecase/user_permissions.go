@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type (
+	UserPermissionsInput struct {
+		ID int64 `validate:"required,gt=0"`
+	}
+
+	UserPermissionsOutput struct {
+		Roles       []string
+		Permissions map[string][]string
+	}
+)
+
+func (s *Usecase) UserPermissions(ctx context.Context, in UserPermissionsInput) (*UserPermissionsOutput, error) {
+	ctx, span := s.startSpan(ctx, "UserPermissions")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if _, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtUsers, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repoDB.GetUserByID(ctx, in.ID, false)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user not found", "user_id", in.ID)
+		return nil, goerror.NewBusiness("user not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", in.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	subject := strconv.FormatInt(user.ID, 10)
+
+	roles, err := s.enforcer.GetImplicitRolesForUser(subject)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get roles for user", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	slices.Sort(roles)
+
+	permissions, err := s.effectivePermissions(subject)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get permissions for user", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return &UserPermissionsOutput{
+		Roles:       roles,
+		Permissions: permissions,
+	}, nil
+}
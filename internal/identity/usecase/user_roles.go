@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type (
+	UserRolesUpdateInput struct {
+		ID    int64    `validate:"required,gt=0"`
+		Roles []string `validate:"dive,required,max=64"`
+	}
+
+	UserRolesUpdateOutput struct {
+		Roles []string
+	}
+)
+
+// UserRolesUpdate replaces the roles assigned directly to a user. The enforcer saves the
+// grouping rules through the adapter and notifies the watcher, so other instances reload.
+// An empty list removes every role.
+func (s *Usecase) UserRolesUpdate(ctx context.Context, in UserRolesUpdateInput) (*UserRolesUpdateOutput, error) {
+	ctx, span := s.startSpan(ctx, "UserRolesUpdate")
+	defer span.End()
+
+	for i, role := range in.Roles {
+		in.Roles[i] = normalizeRoleName(role)
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	clm, err := s.authenticatedAndAuthorized(ctx, constant.PermIdentityMgmtRoles, constant.PermActUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repoDB.GetUserByID(ctx, in.ID, false)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user not found", "user_id", in.ID)
+		return nil, goerror.NewBusiness("user not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", in.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	wanted := slices.Clone(in.Roles)
+	slices.Sort(wanted)
+	wanted = slices.Compact(wanted)
+
+	for _, role := range wanted {
+		exists, err := s.roleExists(role)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to check role", "role", role, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+		if !exists {
+			return nil, goerror.NewBusiness("role "+role+" does not exist", goerror.CodeInvalidInput)
+		}
+	}
+
+	subject := strconv.FormatInt(user.ID, 10)
+
+	current, err := s.enforcer.GetRolesForUser(subject)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get roles for user", "user_id", user.ID, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	slices.Sort(current)
+
+	var added, removed [][]string
+	for _, role := range wanted {
+		if !slices.Contains(current, role) {
+			added = append(added, []string{subject, role})
+		}
+	}
+	for _, role := range current {
+		if slices.Contains(wanted, role) {
+			continue
+		}
+		if clm.UserID == user.ID && s.isProtectedRole(role) {
+			slog.WarnContext(ctx, "user tried to remove own protected role", "user_id", user.ID, "role", role)
+			return nil, goerror.NewBusiness("cannot remove yourself from a protected role", goerror.CodeForbidden)
+		}
+		removed = append(removed, []string{subject, role})
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		return &UserRolesUpdateOutput{Roles: wanted}, nil
+	}
+
+	if len(removed) > 0 {
+		if _, err := s.enforcer.RemoveGroupingPolicies(removed); err != nil {
+			slog.ErrorContext(ctx, "failed to remove user roles", "user_id", user.ID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+	}
+
+	if len(added) > 0 {
+		if _, err := s.enforcer.AddGroupingPolicies(added); err != nil {
+			slog.ErrorContext(ctx, "failed to add user roles", "user_id", user.ID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+	}
+
+	s.auditRoleChange(ctx, clm.UserID, user.ID, entity.AuditActionUserRoles, valueobject.JSONMap{
+		"before":  current,
+		"after":   wanted,
+		"added":   groupingRoles(added),
+		"removed": groupingRoles(removed),
+	})
+
+	return &UserRolesUpdateOutput{Roles: wanted}, nil
+}
+
+func groupingRoles(rules [][]string) []string {
+	roles := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		roles = append(roles, rule[1])
+	}
+	return roles
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/shandysiswandi/gobite/internal/identity/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 	"github.com/shandysiswandi/gobite/internal/shared/constant"
 )
 
@@ -18,6 +19,12 @@ type UserUpdateInput struct {
 	Password string            `validate:"omitempty,password"`
 	FullName string            `validate:"omitempty,min=5,max=100,alphaspace"`
 	Status   entity.UserStatus `validate:"omitempty,gt=0"`
+	// MustResetPassword and MFARequired, when set, force the user through a password reset
+	// or an MFA enrollment at their next login.
+	MustResetPassword *bool
+	MFARequired       *bool
+	// ExpectedVersion, when set, refuses the update if the user changed since that version.
+	ExpectedVersion valueobject.Version
 }
 
 func (s *Usecase) UserUpdate(ctx context.Context, in UserUpdateInput) error {
@@ -48,8 +55,11 @@ func (s *Usecase) UserUpdate(ctx context.Context, in UserUpdateInput) error {
 		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", in.ID, "error", err)
 		return goerror.NewServer(err)
 	}
+	if !in.ExpectedVersion.Matches(user.UpdatedAt) {
+		return goerror.NewVersionConflict("user")
+	}
 
-	checkEmail, err := s.repoDB.GetUserByEmail(ctx, in.Email, true)
+	checkEmail, err := s.getUserByEmail(ctx, in.Email, true)
 	if err == nil && checkEmail != nil && user.Email != checkEmail.Email {
 		slog.WarnContext(ctx, "user account is already exists", "email", in.Email)
 		return goerror.NewBusiness("user account with that email already exists", goerror.CodeConflict)
@@ -61,6 +71,10 @@ func (s *Usecase) UserUpdate(ctx context.Context, in UserUpdateInput) error {
 
 	var newHash string
 	if in.Password != "" {
+		if err := s.checkPasswordPolicy("password", in.Password, in.Email); err != nil {
+			return err
+		}
+
 		hash, err := s.bcrypt.Hash(in.Password)
 		if err != nil {
 			slog.ErrorContext(ctx, "failed to hash new password", "user_id", user.ID, "error", err)
@@ -72,17 +86,51 @@ func (s *Usecase) UserUpdate(ctx context.Context, in UserUpdateInput) error {
 	patchUser := entity.PatchUser{
 		ID:        user.ID,
 		UpdatedBy: clm.UserID,
-		Email:     in.Email,
+		Email:     s.normalizeEmail(in.Email),
 		FullName:  in.FullName,
 		Status:    in.Status.Ensure(),
+
+		MustResetPassword: in.MustResetPassword,
+		MFARequired:       in.MFARequired,
+		ExpectedVersion:   in.ExpectedVersion,
 	}
 	if in.FullName != "" {
 		patchUser.AvatarURL = "https://ui-avatars.com/api/?name=" + url.QueryEscape(in.FullName)
 	}
-	if err := s.repoDB.PatchUser(ctx, patchUser, newHash); err != nil {
+	if in.Email != "" {
+		patchUser.EmailHash, patchUser.EmailSealedOnly, err = s.emailLookupHash(patchUser.Email)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to hash email", "user_id", user.ID, "error", err)
+			return goerror.NewServer(err)
+		}
+	}
+	err = s.repoDB.PatchUser(ctx, patchUser, newHash)
+	if errors.Is(err, goerror.ErrVersionMismatch) {
+		return goerror.NewVersionConflict("user")
+	}
+	if err != nil {
 		slog.ErrorContext(ctx, "failed to repo patch user", "user_id", user.ID, "error", err)
 		return goerror.NewServer(err)
 	}
 
+	banned := patchUser.Status == entity.UserStatusBanned && user.Status != entity.UserStatusBanned
+	if banned || newHash != "" {
+		s.revokeUserAccessTokens(ctx, user.ID)
+	}
+
+	meta := map[string]any{
+		"email_changed":    in.Email != "" && patchUser.Email != user.Email,
+		"name_changed":     in.FullName != "" && in.FullName != user.FullName,
+		"status_changed":   patchUser.Status != entity.UserStatusUnknown && patchUser.Status != user.Status,
+		"password_changed": newHash != "",
+	}
+	if in.MustResetPassword != nil {
+		meta["must_reset_password"] = *in.MustResetPassword
+	}
+	if in.MFARequired != nil {
+		meta["mfa_required"] = *in.MFARequired
+	}
+	s.recordAudit(ctx, entity.AuditActionUserUpdate, clm.UserID, user.ID, meta)
+
 	return nil
 }
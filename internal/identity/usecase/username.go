@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+const (
+	defaultUsernameMinLength = 3
+	defaultUsernameMaxLength = 30
+)
+
+// usernamePattern allows lowercase letters, digits, ".", "_" and "-", starting and ending
+// with a letter or digit. It never matches an email, so the two cannot be confused.
+var usernamePattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9._-]*[a-z0-9])?$`)
+
+type (
+	UsernameAvailabilityInput struct {
+		Username string `validate:"required,max=64"`
+	}
+
+	UsernameAvailabilityOutput struct {
+		Username  string
+		Available bool
+		// Reason tells why an unavailable username cannot be taken.
+		Reason string
+	}
+
+	ProfileUpdateUsernameInput struct {
+		// Username is the new username; empty removes it.
+		Username string `validate:"max=64"`
+	}
+)
+
+func (s *Usecase) usernameEnabled() bool {
+	return s.cfg.GetBool("modules.identity.username.enabled")
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// checkUsername returns a validation error on field when username breaks the length or
+// character rules or is one of modules.identity.username.reserved.
+func (s *Usecase) checkUsername(field, username string) error {
+	minLen := s.cfg.GetInt("modules.identity.username.min_length")
+	if minLen <= 0 {
+		minLen = defaultUsernameMinLength
+	}
+	maxLen := s.cfg.GetInt("modules.identity.username.max_length")
+	if maxLen <= 0 {
+		maxLen = defaultUsernameMaxLength
+	}
+
+	switch {
+	case len(username) < minLen || len(username) > maxLen:
+		return goerror.NewInvalidInput(nil, field, fmt.Sprintf("username must be %d to %d characters", minLen, maxLen))
+	case !usernamePattern.MatchString(username):
+		return goerror.NewInvalidInput(nil, field, "username may only contain lowercase letters, digits, '.', '_' and '-', and must start and end with a letter or digit")
+	case slices.ContainsFunc(s.cfg.GetArray("modules.identity.username.reserved"), func(r string) bool {
+		return normalizeUsername(r) == username
+	}):
+		return goerror.NewInvalidInput(nil, field, "username is reserved")
+	}
+
+	return nil
+}
+
+// UsernameAvailability reports whether the authenticated user can take username.
+func (s *Usecase) UsernameAvailability(ctx context.Context, in UsernameAvailabilityInput) (*UsernameAvailabilityOutput, error) {
+	ctx, span := s.startSpan(ctx, "UsernameAvailability")
+	defer span.End()
+
+	if jwt.GetAuth(ctx) == nil {
+		return nil, goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	in.Username = normalizeUsername(in.Username)
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	if !s.usernameEnabled() {
+		return nil, goerror.NewBusiness("usernames are not enabled", goerror.CodeNotFound)
+	}
+
+	out := &UsernameAvailabilityOutput{Username: in.Username}
+
+	var gerr *goerror.Error
+	if err := s.checkUsername("username", in.Username); errors.As(err, &gerr) {
+		out.Reason = gerr.Fields()["username"]
+		return out, nil
+	}
+
+	taken, err := s.repoDB.IsUsernameTaken(ctx, in.Username)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo check username", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+	if taken {
+		out.Reason = "username is already taken"
+		return out, nil
+	}
+
+	out.Available = true
+
+	return out, nil
+}
+
+// ProfileUpdateUsername sets, changes, or with an empty username removes the username of
+// the authenticated user.
+func (s *Usecase) ProfileUpdateUsername(ctx context.Context, in ProfileUpdateUsernameInput) error {
+	ctx, span := s.startSpan(ctx, "ProfileUpdateUsername")
+	defer span.End()
+
+	clm := jwt.GetAuth(ctx)
+	if clm == nil {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+
+	in.Username = normalizeUsername(in.Username)
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	if !s.usernameEnabled() {
+		return goerror.NewBusiness("usernames are not enabled", goerror.CodeNotFound)
+	}
+
+	if in.Username != "" {
+		if err := s.checkUsername("username", in.Username); err != nil {
+			return err
+		}
+	}
+
+	current, err := s.repoDB.GetUsername(ctx, clm.UserID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get username", "user_id", clm.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+	if current == in.Username {
+		return nil
+	}
+
+	err = s.repoDB.UpdateUsername(ctx, clm.UserID, in.Username)
+	if errors.Is(err, goerror.ErrConflict) {
+		return goerror.NewBusiness("username is already taken", goerror.CodeConflict)
+	}
+	if errors.Is(err, goerror.ErrNotFound) {
+		return goerror.NewBusiness("authentication required", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo update username", "user_id", clm.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	s.recordAudit(ctx, entity.AuditActionProfileUsername, clm.UserID, clm.UserID, map[string]any{
+		"old_username": current,
+		"new_username": in.Username,
+	})
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/identity/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+)
+
+type SendVerificationReminderInput struct {
+	UserID   int64 `validate:"required,gt=0"`
+	Reminder int32
+}
+
+// ScheduleVerificationReminders finds users still unverified
+// modules.identity.verification_reminder.after_days after registering, and then every
+// interval_days, up to max_reminders each. A reminder is counted before it is published, so
+// a user is never reminded more often than that even when several instances run this.
+// It returns how many reminders were published.
+func (s *Usecase) ScheduleVerificationReminders(ctx context.Context) (int, error) {
+	ctx, span := s.startSpan(ctx, "ScheduleVerificationReminders")
+	defer span.End()
+
+	maxReminders := s.cfg.GetInt32("modules.identity.verification_reminder.max_reminders")
+	if maxReminders <= 0 {
+		return 0, nil
+	}
+	batchSize := s.cfg.GetInt32("modules.identity.verification_reminder.batch_size")
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+	afterDays := s.cfg.GetDay("modules.identity.verification_reminder.after_days")
+	intervalDays := s.cfg.GetDay("modules.identity.verification_reminder.interval_days")
+	if intervalDays <= 0 {
+		intervalDays = afterDays
+	}
+
+	now := s.clock.Now()
+	due, err := s.repoDB.GetVerificationReminderDue(ctx, now.Add(-afterDays), now.Add(-intervalDays), maxReminders, batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get verification reminder due", "error", err)
+		return 0, goerror.NewServer(err)
+	}
+
+	published := 0
+	for _, item := range due {
+		claimed, err := s.repoDB.ClaimVerificationReminder(ctx, item.UserID, item.SentCount, now)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo claim verification reminder", "user_id", item.UserID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.repoMessaging.PublishVerificationReminderDue(ctx, contracts.VerificationReminderDue{
+			UserID:   item.UserID,
+			Reminder: item.SentCount + 1,
+		}); err != nil {
+			// the reminder stays counted; the next one is due after interval_days
+			slog.ErrorContext(ctx, "failed to publish verification reminder due", "user_id", item.UserID, "error", err)
+			continue
+		}
+		published++
+	}
+
+	if published > 0 {
+		slog.InfoContext(ctx, "verification reminders scheduled", "count", published)
+	}
+
+	return published, nil
+}
+
+// SendVerificationReminder emails a new verification link to a user who is still unverified.
+// Users verified, banned or deleted since the reminder was scheduled are skipped.
+func (s *Usecase) SendVerificationReminder(ctx context.Context, in SendVerificationReminderInput) error {
+	ctx, span := s.startSpan(ctx, "SendVerificationReminder")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	user, err := s.repoDB.GetUserByID(ctx, in.UserID, false)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "user for verification reminder not found", "user_id", in.UserID)
+		return nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get user by id", "user_id", in.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	if user.Status != entity.UserStatusUnverified {
+		return nil
+	}
+
+	slog.InfoContext(ctx, "sending verification reminder", "user_id", user.ID, "reminder", in.Reminder)
+
+	return s.sendRegistrationChallenge(ctx, user)
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// HeaderArchiveReplay marks a message re-injected from the archive so it is not archived twice.
+const HeaderArchiveReplay = "archiveReplay"
+
+type ArchivedHeader struct {
+	Key   string
+	Value []byte
+}
+
+type ArchivedMessage struct {
+	Destination string
+	ID          string
+	Key         []byte
+	Body        []byte
+	Headers     []ArchivedHeader
+	Attributes  map[string]string
+	Timestamp   time.Time
+	ArchivedAt  time.Time
+}
@@ -72,9 +72,19 @@ func (s DeliveryStatus) String() string {
 type TriggerKey string
 
 const (
-	TriggerKeyEmailVerify   TriggerKey = "email_verify"
-	TriggerKeyPasswordReset TriggerKey = "password_reset"
-	TriggerKeyUserWelcome   TriggerKey = "user_welcome"
+	TriggerKeyEmailVerify          TriggerKey = "email_verify"
+	TriggerKeyPasswordReset        TriggerKey = "password_reset"
+	TriggerKeyUserWelcome          TriggerKey = "user_welcome"
+	TriggerKeyMFARevoked           TriggerKey = "mfa_revoked"
+	TriggerKeyMFARecoveryRequested TriggerKey = "mfa_recovery_requested"
+	TriggerKeyMFARecoveryPending   TriggerKey = "mfa_recovery_pending"
+	TriggerKeyMFARecoveryCompleted TriggerKey = "mfa_recovery_completed"
+	TriggerKeySessionRevoked       TriggerKey = "session_revoked"
+	TriggerKeyEmailChangeVerify    TriggerKey = "email_change_verify"
+	TriggerKeyEmailChanged         TriggerKey = "email_changed"
+	TriggerKeyUserInvite           TriggerKey = "user_invite"
+	TriggerKeyNewSignIn            TriggerKey = "new_sign_in"
+	TriggerKeyOrgInvite            TriggerKey = "org_invite"
 )
 
 func (tk TriggerKey) String() string {
@@ -0,0 +1,149 @@
+package entity
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ErrFixtureInvalid indicates fixture data that cannot be seeded.
+var ErrFixtureInvalid = errors.New("notification fixture invalid")
+
+//go:embed fixtures
+var fixtureFS embed.FS
+
+// Fixtures is the default notification data of a new environment: the categories with their
+// default settings matrix, and the templates of each trigger and channel.
+type Fixtures struct {
+	Categories []FixtureCategory
+	Templates  []Template
+}
+
+// FixtureCategory is a category and whether each channel of it is on for users who have not
+// changed their settings.
+type FixtureCategory struct {
+	Category
+	Channels map[Channel]bool
+}
+
+// FixtureDrift is a fixture row the database is missing or holds with other content.
+type FixtureDrift struct {
+	Kind    string // category, category_default, or template
+	Key     string
+	Missing bool
+}
+
+func (d FixtureDrift) String() string {
+	if d.Missing {
+		return d.Kind + " " + d.Key + " is missing"
+	}
+	return d.Kind + " " + d.Key + " differs from the fixture"
+}
+
+type fixtureFile struct {
+	Categories []struct {
+		ID          int64           `yaml:"id"`
+		Name        string          `yaml:"name"`
+		Description string          `yaml:"description"`
+		Mandatory   bool            `yaml:"mandatory"`
+		Channels    map[string]bool `yaml:"channels"`
+	} `yaml:"categories"`
+	Templates []struct {
+		ID         int64  `yaml:"id"`
+		TriggerKey string `yaml:"trigger_key"`
+		CategoryID int64  `yaml:"category_id"`
+		Channel    string `yaml:"channel"`
+		Subject    string `yaml:"subject"`
+		BodyFile   string `yaml:"body_file"`
+	} `yaml:"templates"`
+}
+
+// LoadFixtures reads the embedded fixtures, checking that every category and template is
+// unique and that templates name a registered trigger and a known category and channel.
+func LoadFixtures() (*Fixtures, error) {
+	const dir = "fixtures"
+
+	raw, err := fixtureFS.ReadFile(path.Join(dir, "notification.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var file fixtureFile
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFixtureInvalid, err)
+	}
+
+	f := &Fixtures{
+		Categories: make([]FixtureCategory, 0, len(file.Categories)),
+		Templates:  make([]Template, 0, len(file.Templates)),
+	}
+
+	categories := make(map[int64]struct{}, len(file.Categories))
+	for _, c := range file.Categories {
+		if _, ok := categories[c.ID]; ok || c.ID <= 0 || c.Name == "" {
+			return nil, fmt.Errorf("%w: category %d is duplicated or incomplete", ErrFixtureInvalid, c.ID)
+		}
+		categories[c.ID] = struct{}{}
+
+		channels := make(map[Channel]bool, len(c.Channels))
+		for name, on := range c.Channels {
+			ch := ChannelFromString(name)
+			if ch == ChannelUnknown {
+				return nil, fmt.Errorf("%w: category %s has unknown channel %q", ErrFixtureInvalid, c.Name, name)
+			}
+			channels[ch] = on
+		}
+
+		f.Categories = append(f.Categories, FixtureCategory{
+			Category: Category{
+				ID:          c.ID,
+				Name:        c.Name,
+				Description: c.Description,
+				IsMandatory: c.Mandatory,
+			},
+			Channels: channels,
+		})
+	}
+
+	seen := make(map[string]struct{}, len(file.Templates))
+	ids := make(map[int64]struct{}, len(file.Templates))
+	for _, t := range file.Templates {
+		key := TriggerKey(t.TriggerKey)
+		ch := ChannelFromString(t.Channel)
+		name := t.TriggerKey + "/" + t.Channel
+
+		if _, ok := LookupTrigger(key); !ok {
+			return nil, fmt.Errorf("%w: template %s: %w", ErrFixtureInvalid, name, ErrTriggerUnknown)
+		}
+		if _, ok := categories[t.CategoryID]; !ok || ch == ChannelUnknown {
+			return nil, fmt.Errorf("%w: template %s has an unknown category or channel", ErrFixtureInvalid, name)
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("%w: template %s is duplicated", ErrFixtureInvalid, name)
+		}
+		if _, ok := ids[t.ID]; ok || t.ID <= 0 {
+			return nil, fmt.Errorf("%w: template %s has a duplicated or missing id", ErrFixtureInvalid, name)
+		}
+		seen[name], ids[t.ID] = struct{}{}, struct{}{}
+
+		body, err := fixtureFS.ReadFile(path.Join(dir, t.BodyFile))
+		if err != nil {
+			return nil, fmt.Errorf("%w: template %s: %w", ErrFixtureInvalid, name, err)
+		}
+
+		f.Templates = append(f.Templates, Template{
+			ID:         t.ID,
+			TriggerKey: key,
+			CategoryID: t.CategoryID,
+			Channel:    ch,
+			Subject:    t.Subject,
+			Body:       strings.TrimSuffix(string(body), "\n"),
+		})
+	}
+
+	return f, nil
+}
@@ -35,6 +35,7 @@ type Template struct {
 	Channel    Channel
 	Subject    string
 	Body       string
+	UpdatedAt  time.Time
 }
 
 type Category struct {
@@ -48,6 +49,27 @@ type UserSetting struct {
 	CategoryID int64
 	Channel    Channel
 	IsEnabled  bool
+	UpdatedAt  time.Time // zero for a setting the user never stored
+}
+
+// SettingsVersionEmpty is the version of a user who stored no settings yet. It is not zero,
+// which means no precondition, so concurrent first writes still conflict.
+const SettingsVersionEmpty valueobject.Version = 1
+
+// SettingsVersion is the version of a user's stored settings, the latest update among
+// them; SettingsVersionEmpty when the user stored none.
+func SettingsVersion(settings []UserSetting) valueobject.Version {
+	var latest time.Time
+	for _, setting := range settings {
+		if setting.UpdatedAt.After(latest) {
+			latest = setting.UpdatedAt
+		}
+	}
+	if latest.IsZero() {
+		return SettingsVersionEmpty
+	}
+
+	return valueobject.VersionOf(latest)
 }
 
 type NotificationItem struct {
@@ -59,3 +81,16 @@ type NotificationItem struct {
 	ReadAt     *time.Time
 	CreatedAt  time.Time
 }
+
+// NotificationExport is a notification as it appears in a user's data export, soft-deleted
+// ones included.
+type NotificationExport struct {
+	ID         int64
+	CategoryID int64
+	TriggerKey TriggerKey
+	Data       valueobject.JSONMap
+	Metadata   valueobject.JSONMap
+	ReadAt     *time.Time
+	DeletedAt  *time.Time
+	CreatedAt  time.Time
+}
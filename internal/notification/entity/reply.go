@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+var (
+	// ErrSNSSignatureInvalid indicates an SNS message that SNS did not sign.
+	ErrSNSSignatureInvalid = errors.New("sns message signature invalid")
+	// ErrSendGridSignatureInvalid indicates a webhook post that SendGrid did not sign.
+	ErrSendGridSignatureInvalid = errors.New("sendgrid webhook signature invalid")
+)
+
+// NotificationRef is the part of a notification an inbound reply is routed by.
+type NotificationRef struct {
+	ID         int64
+	UserID     int64
+	CategoryID int64
+	TriggerKey TriggerKey
+}
+
+// Reply is an email reply to a notification, received through the inbound mail webhook.
+type Reply struct {
+	ID             int64
+	NotificationID int64
+	UserID         int64
+	Provider       string
+	MessageID      string
+	FromAddress    string
+	Subject        string
+	Body           string
+	Metadata       valueobject.JSONMap
+	ReceivedAt     time.Time
+}
+
+// SNSMessage is the envelope SNS posts to an HTTPS subscription, with the fields its
+// signature covers.
+type SNSMessage struct {
+	Type             string
+	MessageID        string
+	TopicArn         string
+	Subject          string
+	Message          string
+	Timestamp        string
+	Token            string
+	SubscribeURL     string
+	SignatureVersion string
+	Signature        string
+	SigningCertURL   string
+}
+
+// SendGridSignedPayload is a signed SendGrid webhook post: the signature and timestamp
+// headers and the raw body they cover.
+type SendGridSignedPayload struct {
+	Signature string
+	Timestamp string
+	Body      []byte
+}
@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"sort"
+	"text/template/parse"
+)
+
+// ErrTemplateInvalid indicates a template that cannot be rendered for its trigger.
+var ErrTemplateInvalid = errors.New("notification template invalid")
+
+// inAppPlaceholder matches the mustache-style {{name}} placeholders rendered by clients.
+//
+//nolint:gochecknoglobals // compiled once
+var inAppPlaceholder = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// LintTemplate checks a template against the data its trigger registers, so a
+// broken placeholder is rejected when the template is saved instead of at send
+// time. extra lists the keys the service adds to every render of the channel,
+// such as the company footer of emails.
+//
+// Email bodies are Go templates reading {{.name}}; email subjects are sent as
+// is and must not contain placeholders. In-app subjects and bodies use
+// {{name}} and are rendered by clients.
+func LintTemplate(key TriggerKey, ch Channel, subject, body string, extra []string) (field string, err error) {
+	t, ok := LookupTrigger(key)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrTriggerUnknown, key)
+	}
+
+	allowed := make(map[string]struct{}, len(t.Fields)+len(extra))
+	for name := range t.Fields {
+		allowed[name] = struct{}{}
+	}
+	for _, name := range extra {
+		allowed[name] = struct{}{}
+	}
+
+	switch ch {
+	case ChannelEmail:
+		if inAppPlaceholder.MatchString(subject) {
+			return "subject", fmt.Errorf("%w: email subject does not support placeholders", ErrTemplateInvalid)
+		}
+
+		names, err := emailPlaceholders(body)
+		if err != nil {
+			return "body", fmt.Errorf("%w: %w", ErrTemplateInvalid, err)
+		}
+		if err := checkPlaceholders(names, allowed); err != nil {
+			return "body", err
+		}
+	case ChannelInApp:
+		if err := checkPlaceholders(inAppPlaceholders(subject), allowed); err != nil {
+			return "subject", err
+		}
+		if err := checkPlaceholders(inAppPlaceholders(body), allowed); err != nil {
+			return "body", err
+		}
+	default:
+		return "channel", fmt.Errorf("%w: channel %s has no templates", ErrTemplateInvalid, ch)
+	}
+
+	return "", nil
+}
+
+func checkPlaceholders(names []string, allowed map[string]struct{}) error {
+	var unknown []string
+	for _, name := range names {
+		if _, ok := allowed[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return fmt.Errorf("%w: unknown placeholders %v", ErrTemplateInvalid, unknown)
+}
+
+func inAppPlaceholders(text string) []string {
+	var names []string
+	for _, m := range inAppPlaceholder.FindAllStringSubmatch(text, -1) {
+		names = append(names, m[1])
+	}
+
+	return names
+}
+
+// emailPlaceholders parses body with the html/template engine it is rendered with and
+// returns every top-level field it reads. Fields read inside range or with blocks are
+// relative to another value and are not checked. The body is also escaped, since
+// html/template only reports escaping-context errors on the first execution.
+func emailPlaceholders(body string) ([]string, error) {
+	t, err := template.New("lint").Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	if t.Tree == nil {
+		return nil, nil
+	}
+
+	seen := map[string]struct{}{}
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Nodes {
+				walk(c)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.TemplateNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.FieldNode:
+			seen[n.Ident[0]] = struct{}{}
+		case *parse.ChainNode:
+			walk(n.Node)
+		}
+	}
+	walk(t.Tree.Root)
+
+	var escErr *template.Error
+	if err := t.Execute(io.Discard, map[string]any{}); errors.As(err, &escErr) {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,217 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrTriggerUnknown indicates a trigger key that is not in the registry.
+	ErrTriggerUnknown = errors.New("unknown notification trigger")
+	// ErrTriggerDataInvalid indicates template data that does not match the trigger schema.
+	ErrTriggerDataInvalid = errors.New("notification trigger data invalid")
+)
+
+// TriggerField describes one key a trigger expects in its template data.
+type TriggerField struct {
+	Type        string // JSON type: string, number, boolean, object, array
+	Required    bool
+	Description string
+}
+
+// Trigger is a registered notification event and the data its templates can render.
+type Trigger struct {
+	Key         TriggerKey
+	Description string
+	Fields      map[string]TriggerField
+}
+
+// triggers is the source of truth for trigger keys; it is synced to notification_triggers on startup.
+//
+//nolint:gochecknoglobals // static registry
+var triggers = []Trigger{
+	{
+		Key:         TriggerKeyEmailVerify,
+		Description: "Asks a new user to confirm their email address",
+		Fields: map[string]TriggerField{
+			"verify_url": {Type: "string", Required: true, Description: "Signed link to the email verification page"},
+		},
+	},
+	{
+		Key:         TriggerKeyPasswordReset,
+		Description: "Sends a password reset link",
+		Fields: map[string]TriggerField{
+			"reset_url": {Type: "string", Required: true, Description: "Link to the password reset page"},
+		},
+	},
+	{
+		Key:         TriggerKeyUserWelcome,
+		Description: "Welcomes a newly registered user in the inbox",
+		Fields: map[string]TriggerField{
+			"full_name": {Type: "string", Required: true, Description: "Full name of the user"},
+		},
+	},
+	{
+		Key:         TriggerKeyMFARevoked,
+		Description: "Tells a user an administrator removed their two-factor authentication",
+		Fields: map[string]TriggerField{
+			"full_name":    {Type: "string", Required: true, Description: "Full name of the user"},
+			"security_url": {Type: "string", Required: true, Description: "Link to the security settings page"},
+		},
+	},
+	{
+		Key:         TriggerKeyMFARecoveryRequested,
+		Description: "Sends the link that continues a lost two-factor authentication recovery",
+		Fields: map[string]TriggerField{
+			"full_name":    {Type: "string", Required: true, Description: "Full name of the user"},
+			"recovery_url": {Type: "string", Required: true, Description: "Link to the MFA recovery page"},
+		},
+	},
+	{
+		Key:         TriggerKeyMFARecoveryPending,
+		Description: "Warns a user that their two-factor authentication will be removed after a waiting period",
+		Fields: map[string]TriggerField{
+			"full_name":    {Type: "string", Required: true, Description: "Full name of the user"},
+			"available_at": {Type: "string", Required: true, Description: "When the recovery can be completed (RFC 3339)"},
+			"security_url": {Type: "string", Required: true, Description: "Link to the security settings page"},
+		},
+	},
+	{
+		Key:         TriggerKeyMFARecoveryCompleted,
+		Description: "Tells a user their two-factor authentication was removed by a recovery",
+		Fields: map[string]TriggerField{
+			"full_name":    {Type: "string", Required: true, Description: "Full name of the user"},
+			"security_url": {Type: "string", Required: true, Description: "Link to the security settings page"},
+		},
+	},
+	{
+		Key:         TriggerKeySessionRevoked,
+		Description: "Tells a user their oldest sessions were signed out after reaching the device limit",
+		Fields: map[string]TriggerField{
+			"full_name":     {Type: "string", Required: true, Description: "Full name of the user"},
+			"revoked_count": {Type: "number", Required: true, Description: "Number of sessions signed out"},
+			"limit":         {Type: "number", Required: true, Description: "Maximum number of active sessions"},
+			"security_url":  {Type: "string", Required: true, Description: "Link to the security settings page"},
+		},
+	},
+	{
+		Key:         TriggerKeyEmailChangeVerify,
+		Description: "Asks a user to confirm the new address of an email change",
+		Fields: map[string]TriggerField{
+			"new_email":  {Type: "string", Required: true, Description: "Address the account is moving to"},
+			"verify_url": {Type: "string", Required: true, Description: "Link that confirms the email change"},
+		},
+	},
+	{
+		Key:         TriggerKeyEmailChanged,
+		Description: "Tells a user at their previous address that the account email was changed",
+		Fields: map[string]TriggerField{
+			"new_email":    {Type: "string", Required: true, Description: "Address the account moved to"},
+			"security_url": {Type: "string", Required: true, Description: "Link to the security settings page"},
+		},
+	},
+	{
+		Key:         TriggerKeyUserInvite,
+		Description: "Invites a user created by an administrator to choose a password",
+		Fields: map[string]TriggerField{
+			"full_name":  {Type: "string", Required: true, Description: "Full name of the invited user"},
+			"invite_url": {Type: "string", Required: true, Description: "Link to the invitation acceptance page"},
+			"expires_at": {Type: "string", Required: true, Description: "When the invitation link expires (RFC 3339)"},
+		},
+	},
+	{
+		Key:         TriggerKeyNewSignIn,
+		Description: "Warns a user about a sign-in from a device or country not seen before",
+		Fields: map[string]TriggerField{
+			"full_name":    {Type: "string", Required: true, Description: "Full name of the user"},
+			"device":       {Type: "string", Required: true, Description: "Name the client gave the signing-in device, with its user agent"},
+			"location":     {Type: "string", Required: true, Description: "Country the sign-in came from"},
+			"ip":           {Type: "string", Description: "IP address of the sign-in"},
+			"signed_in_at": {Type: "string", Required: true, Description: "When the sign-in happened (RFC 3339)"},
+			"security_url": {Type: "string", Required: true, Description: "Link to the security settings page"},
+		},
+	},
+	{
+		Key:         TriggerKeyOrgInvite,
+		Description: "Invites an existing user to join an organization",
+		Fields: map[string]TriggerField{
+			"full_name":  {Type: "string", Required: true, Description: "Full name of the invited user"},
+			"org_name":   {Type: "string", Required: true, Description: "Name of the organization"},
+			"role":       {Type: "string", Required: true, Description: "Role the user joins with (owner, admin or member)"},
+			"invite_url": {Type: "string", Required: true, Description: "Link to the invitation acceptance page"},
+			"expires_at": {Type: "string", Required: true, Description: "When the invitation link expires (RFC 3339)"},
+		},
+	},
+}
+
+// Triggers returns every registered trigger.
+func Triggers() []Trigger {
+	return triggers
+}
+
+// LookupTrigger returns the registered trigger for key.
+func LookupTrigger(key TriggerKey) (Trigger, bool) {
+	for _, t := range triggers {
+		if t.Key == key {
+			return t, true
+		}
+	}
+
+	return Trigger{}, false
+}
+
+// ValidateTriggerData checks that key is registered, data carries every required field and
+// each field of the schema holds a value of its declared type.
+func ValidateTriggerData(key TriggerKey, data map[string]any) error {
+	t, ok := LookupTrigger(key)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrTriggerUnknown, key)
+	}
+
+	for name, field := range t.Fields {
+		v, ok := data[name]
+		if field.Required && (!ok || v == nil || v == "") {
+			return fmt.Errorf("%w: %s requires %q", ErrTriggerDataInvalid, key, name)
+		}
+		if v != nil && !field.accepts(v) {
+			return fmt.Errorf("%w: %s expects %q to be a %s", ErrTriggerDataInvalid, key, name, field.Type)
+		}
+	}
+
+	return nil
+}
+
+// accepts reports whether v encodes to the JSON type of the field.
+func (f TriggerField) accepts(v any) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.String:
+		return f.Type == "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return f.Type == "number"
+	case reflect.Bool:
+		return f.Type == "boolean"
+	case reflect.Map:
+		return f.Type == "object"
+	case reflect.Slice, reflect.Array:
+		return f.Type == "array"
+	default:
+		return false
+	}
+}
+
+// Schema returns the fields as a JSON-friendly map.
+func (t Trigger) Schema() map[string]any {
+	schema := make(map[string]any, len(t.Fields))
+	for name, field := range t.Fields {
+		schema[name] = map[string]any{
+			"type":        field.Type,
+			"required":    field.Required,
+			"description": field.Description,
+		}
+	}
+
+	return schema
+}
@@ -0,0 +1,90 @@
+package inbound
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/notification/usecase"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	notificationv1 "github.com/shandysiswandi/gobite/internal/pkg/pb/notification/v1"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCService serves NotificationService, authenticated by the gRPC server's interceptors.
+type GRPCService struct {
+	notificationv1.UnimplementedNotificationServiceServer
+
+	uc uc
+}
+
+func RegisterGRPCService(s grpc.ServiceRegistrar, uc uc) {
+	notificationv1.RegisterNotificationServiceServer(s, &GRPCService{uc: uc})
+}
+
+func (g *GRPCService) ListInbox(ctx context.Context, req *notificationv1.ListInboxRequest) (*notificationv1.ListInboxResponse, error) {
+	items, err := g.uc.ListInbox(ctx, usecase.ListInboxInput{
+		Status: req.GetStatus(),
+		Search: req.GetSearch(),
+		Limit:  req.GetLimit(),
+		Offset: req.GetOffset(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*notificationv1.Notification, 0, len(items))
+	for _, item := range items {
+		data, err := toStruct(item.Data)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to convert notification data", "id", item.ID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+		metadata, err := toStruct(item.Metadata)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to convert notification metadata", "id", item.ID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+
+		n := &notificationv1.Notification{
+			Id:         item.ID,
+			CategoryId: item.CategoryID,
+			TriggerKey: item.TriggerKey.String(),
+			Data:       data,
+			Metadata:   metadata,
+			CreatedAt:  timestamppb.New(item.CreatedAt),
+		}
+		if item.ReadAt != nil {
+			n.ReadAt = timestamppb.New(*item.ReadAt)
+		}
+		resp = append(resp, n)
+	}
+
+	return &notificationv1.ListInboxResponse{Notifications: resp}, nil
+}
+
+func (g *GRPCService) MarkInboxRead(ctx context.Context, req *notificationv1.MarkInboxReadRequest) (*notificationv1.MarkInboxReadResponse, error) {
+	if err := g.uc.MarkInboxRead(ctx, usecase.MarkInboxReadInput{ID: req.GetId()}); err != nil {
+		return nil, err
+	}
+
+	return &notificationv1.MarkInboxReadResponse{}, nil
+}
+
+func (g *GRPCService) MarkAllInboxRead(ctx context.Context, _ *notificationv1.MarkAllInboxReadRequest) (*notificationv1.MarkAllInboxReadResponse, error) {
+	if err := g.uc.MarkAllInboxRead(ctx); err != nil {
+		return nil, err
+	}
+
+	return &notificationv1.MarkAllInboxReadResponse{}, nil
+}
+
+func toStruct(m valueobject.JSONMap) (*structpb.Struct, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	return structpb.NewStruct(m)
+}
@@ -2,24 +2,44 @@ package inbound
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/shandysiswandi/gobite/internal/pkg/router"
 )
 
-func RegisterHTTPEndpoint(r *router.Router, uc uc) {
-	end := &HTTPEndpoint{uc: uc}
+// InboundWebhooks configures the provider signature checks of the inbound mail webhooks.
+// A config without a verifier leaves its webhook to the shared token.
+type InboundWebhooks struct {
+	SendGrid router.WebhookConfig
+	SES      router.WebhookConfig
+}
+
+func RegisterHTTPEndpoint(r *router.Router, uc uc, streamWriteTimeout time.Duration, webhooks InboundWebhooks) {
+	end := &HTTPEndpoint{uc: uc, streamWriteTimeout: streamWriteTimeout}
 
-	r.POST("/api/v1/notification/device", end.DeviceRegister)
-	r.DELETE("/api/v1/notification/device", end.DeviceRemove)
+	r.POST("/api/v1/notification/device", end.DeviceRegister, router.PersonalOnly)
+	r.DELETE("/api/v1/notification/device", end.DeviceRemove, router.PersonalOnly)
 
 	r.GET("/api/v1/notification/categories", end.ListCategories)
-	r.GET("/api/v1/notification/settings", end.ListSettings)
-	r.PUT("/api/v1/notification/settings", end.UpdateSettings)
+	r.GET("/api/v1/notification/triggers", end.ListTriggers)
+	r.GET("/api/v1/notification/templates/:trigger_key/:channel", end.TemplateDetail)
+	r.PUT("/api/v1/notification/templates/:trigger_key/:channel", end.TemplateSave)
+	r.GET("/api/v1/notification/settings", end.ListSettings, router.PersonalOnly)
+	r.PUT("/api/v1/notification/settings", end.UpdateSettings, router.PersonalOnly)
+	r.POST("/api/v1/notification/unsubscribe", end.Unsubscribe)
+
+	webhooks.SendGrid.MaxBodyBytes = maxSendGridBodyBytes
+	webhooks.SES.MaxBodyBytes = maxInboundBodyBytes
+	r.POST("/api/v1/notification/inbound/sendgrid", end.InboundSendGrid, router.Webhook(webhooks.SendGrid))
+	r.POST("/api/v1/notification/inbound/ses", end.InboundSES, router.Webhook(webhooks.SES))
+
+	r.GET("/api/v1/notification/inbox", end.ListInbox, router.PersonalOnly)
+	r.PATCH("/api/v1/notification/inbox/:id/read", end.MarkInboxRead, router.PersonalOnly)
+	r.PUT("/api/v1/notification/inbox/read-all", end.MarkAllInboxRead, router.PersonalOnly)
+	r.DELETE("/api/v1/notification/inbox/:id", end.DeleteInbox, router.PersonalOnly)
 
-	r.GET("/api/v1/notification/inbox", end.ListInbox)
-	r.PATCH("/api/v1/notification/inbox/:id/read", end.MarkInboxRead)
-	r.PUT("/api/v1/notification/inbox/read-all", end.MarkAllInboxRead)
-	r.DELETE("/api/v1/notification/inbox/:id", end.DeleteInbox)
+	r.POST("/api/v1/notification/archives/replay", end.ArchiveReplay)
 
-	r.GETRaw("/api/v1/notification/stream", http.HandlerFunc(end.StreamNotifications))
+	r.GETRaw("/api/v1/notification/stream", http.HandlerFunc(end.StreamNotifications), router.PersonalOnly)
+	r.WS("/api/v1/notification/ws", end.SocketNotifications, router.PersonalOnly)
 }
@@ -2,15 +2,19 @@ package inbound
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/shandysiswandi/gobite/internal/notification/entity"
 	"github.com/shandysiswandi/gobite/internal/notification/usecase"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/router"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 )
 
 type HTTPEndpoint struct {
 	uc uc
+	// streamWriteTimeout bounds each SSE write; zero leaves writes unbounded.
+	streamWriteTimeout time.Duration
 }
 
 // DeviceRegister registers a device for push notifications.
@@ -89,6 +93,107 @@ func (h *HTTPEndpoint) ListCategories(r *router.Request) (any, error) {
 	return NotificationCategoriesResponse{Categories: resp}, nil
 }
 
+// ListTriggers returns the registered notification triggers.
+// @Summary List notification triggers
+// @Description Returns every trigger key with the template data it expects.
+// @Tags Notification
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} router.successResponse{data=NotificationTriggersResponse} "Trigger list"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/notification/triggers [get]
+func (h *HTTPEndpoint) ListTriggers(r *router.Request) (any, error) {
+	items, err := h.uc.ListTriggers(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]NotificationTriggerResponse, 0, len(items))
+	for _, item := range items {
+		resp = append(resp, NotificationTriggerResponse{
+			Key:         item.Key.String(),
+			Description: item.Description,
+			DataSchema:  item.Schema(),
+		})
+	}
+
+	return NotificationTriggersResponse{Triggers: resp}, nil
+}
+
+// TemplateDetail returns a notification template.
+// @Summary Get notification template
+// @Description Returns the subject and body of a trigger's template on a channel, with the version a save of it expects.
+// @Tags Notification, Management Templates
+// @Security BearerAuth
+// @Produce json
+// @Param trigger_key path string true "Trigger key"
+// @Param channel path string true "Channel (email, in_app)"
+// @Success 200 {object} router.successResponse{data=TemplateResponse} "Template"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Template not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/notification/templates/{trigger_key}/{channel} [get]
+func (h *HTTPEndpoint) TemplateDetail(r *router.Request) (any, error) {
+	tpl, err := h.uc.TemplateDetail(r.Context(), usecase.TemplateDetailInput{
+		TriggerKey: r.GetParam("trigger_key"),
+		Channel:    r.GetParam("channel"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return TemplateResponse{
+		TriggerKey: tpl.TriggerKey.String(),
+		Channel:    channelToString(tpl.Channel),
+		Subject:    tpl.Subject,
+		Body:       tpl.Body,
+		UpdatedAt:  tpl.UpdatedAt,
+		Version:    valueobject.VersionOf(tpl.UpdatedAt),
+	}, nil
+}
+
+// TemplateSave replaces a notification template.
+// @Summary Save notification template
+// @Description Replaces the subject and body of a trigger's template on a channel. Placeholders are checked against the trigger data schema and unknown ones are rejected. Send the version read with the template as If-Match or expected_version to refuse the save when someone else changed the template meanwhile.
+// @Tags Notification, Management Templates
+// @Security BearerAuth
+// @Accept json
+// @Param trigger_key path string true "Trigger key"
+// @Param channel path string true "Channel (email, in_app)"
+// @Param If-Match header string false "Expected template version"
+// @Param request body TemplateSaveRequest true "Template payload"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Trigger or template not found"
+// @Failure 409 {object} router.errorResponse "Template changed since the expected version (reason version_mismatch)"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/notification/templates/{trigger_key}/{channel} [put]
+func (h *HTTPEndpoint) TemplateSave(r *router.Request) (any, error) {
+	var req TemplateSaveRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	version, err := r.ExpectedVersion(req.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.TemplateSave(r.Context(), usecase.TemplateSaveInput{
+		TriggerKey:      r.GetParam("trigger_key"),
+		Channel:         r.GetParam("channel"),
+		Subject:         req.Subject,
+		Body:            req.Body,
+		ExpectedVersion: version,
+	})
+}
+
 // ListSettings returns user notification settings.
 // @Summary List notification settings
 // @Description Returns notification settings for the authenticated user.
@@ -100,13 +205,13 @@ func (h *HTTPEndpoint) ListCategories(r *router.Request) (any, error) {
 // @Failure 500 {object} router.errorResponse "Internal server error"
 // @Router /api/v1/notification/settings [get]
 func (h *HTTPEndpoint) ListSettings(r *router.Request) (any, error) {
-	items, err := h.uc.ListSettings(r.Context())
+	out, err := h.uc.ListSettings(r.Context())
 	if err != nil {
 		return nil, err
 	}
 
-	resp := make([]NotificationSettingResponse, 0, len(items))
-	for _, item := range items {
+	resp := make([]NotificationSettingResponse, 0, len(out.Settings))
+	for _, item := range out.Settings {
 		resp = append(resp, NotificationSettingResponse{
 			CategoryID: item.CategoryID,
 			Channel:    channelToString(item.Channel),
@@ -114,19 +219,21 @@ func (h *HTTPEndpoint) ListSettings(r *router.Request) (any, error) {
 		})
 	}
 
-	return NotificationSettingsResponse{Settings: resp}, nil
+	return NotificationSettingsResponse{Settings: resp, Version: out.Version}, nil
 }
 
 // UpdateSettings updates user notification settings.
 // @Summary Update notification settings
-// @Description Updates notification settings for the authenticated user.
+// @Description Updates notification settings for the authenticated user. Send the version read with the settings as If-Match or expected_version to refuse the update when the settings changed meanwhile, e.g. from another device.
 // @Tags Notification
 // @Security BearerAuth
 // @Accept json
+// @Param If-Match header string false "Expected settings version"
 // @Param request body NotificationSettingsUpdateRequest true "Settings payload"
 // @Success 204 "No Content"
 // @Failure 400 {object} router.errorResponse "Invalid request body"
 // @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 409 {object} router.errorResponse "Settings changed since the expected version (reason version_mismatch)"
 // @Failure 422 {object} router.errorResponse "Validation error"
 // @Failure 500 {object} router.errorResponse "Internal server error"
 // @Router /api/v1/notification/settings [put]
@@ -145,7 +252,49 @@ func (h *HTTPEndpoint) UpdateSettings(r *router.Request) (any, error) {
 		})
 	}
 
-	return nil, h.uc.UpdateSettings(r.Context(), usecase.UpdateSettingsInput{Settings: inputs})
+	version, err := r.ExpectedVersion(req.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.uc.UpdateSettings(r.Context(), usecase.UpdateSettingsInput{
+		Settings:        inputs,
+		ExpectedVersion: version,
+	})
+}
+
+// Unsubscribe disables email delivery for a category using a signed link.
+// @Summary Unsubscribe from category emails
+// @Description Disables the email channel of a category using the signed link embedded in notification emails. Supports RFC 8058 one-click unsubscribe, so no session is required.
+// @Tags Notification
+// @Param user_id query int true "User ID"
+// @Param category_id query int true "Category ID"
+// @Param exp query string true "Link expiry (unix seconds)"
+// @Param sig query string true "Link signature"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid query parameters"
+// @Failure 401 {object} router.errorResponse "Invalid or expired link"
+// @Failure 404 {object} router.errorResponse "Category not found"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/notification/unsubscribe [post]
+func (h *HTTPEndpoint) Unsubscribe(r *router.Request) (any, error) {
+	query := r.URL.Query()
+	userID, err := strconv.ParseInt(query.Get("user_id"), 10, 64)
+	if err != nil {
+		return nil, goerror.NewInvalidFormat()
+	}
+	categoryID, err := strconv.ParseInt(query.Get("category_id"), 10, 64)
+	if err != nil {
+		return nil, goerror.NewInvalidFormat()
+	}
+
+	return nil, h.uc.Unsubscribe(r.Context(), usecase.UnsubscribeInput{
+		UserID:     userID,
+		CategoryID: categoryID,
+		Expires:    query.Get("exp"),
+		Signature:  query.Get("sig"),
+	})
 }
 
 // ListInbox returns user notifications.
@@ -155,6 +304,7 @@ func (h *HTTPEndpoint) UpdateSettings(r *router.Request) (any, error) {
 // @Security BearerAuth
 // @Produce json
 // @Param status query string false "Filter by status (all|read|unread)"
+// @Param search query string false "Full-text search over notification content"
 // @Param limit query int false "Pagination limit"
 // @Param offset query int false "Pagination offset"
 // @Success 200 {object} router.successResponse{data=NotificationsResponse} "Notification list"
@@ -176,6 +326,7 @@ func (h *HTTPEndpoint) ListInbox(r *router.Request) (any, error) {
 
 	items, err := h.uc.ListInbox(r.Context(), usecase.ListInboxInput{
 		Status: query.Get("status"),
+		Search: query.Get("search"),
 		Limit:  limit,
 		Offset: offset,
 	})
@@ -285,3 +436,40 @@ func channelToString(ch entity.Channel) string {
 		return "unknown"
 	}
 }
+
+// ArchiveReplay re-injects archived messages into their destination.
+// @Summary Replay archived messages
+// @Description Re-publishes the archived messages of a destination whose publish time falls in [from, to).
+// @Tags Notification, Management Archives
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body ArchiveReplayRequest true "Replay range payload"
+// @Success 200 {object} router.successResponse{data=ArchiveReplayResponse} "Replay result"
+// @Failure 400 {object} router.errorResponse "Invalid request body"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Forbidden"
+// @Failure 404 {object} router.errorResponse "Archive disabled"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/notification/archives/replay [post]
+func (h *HTTPEndpoint) ArchiveReplay(r *router.Request) (any, error) {
+	var req ArchiveReplayRequest
+	if err := r.DecodeBody(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.uc.ArchiveReplay(r.Context(), usecase.ArchiveReplayInput{
+		Destination: req.Destination,
+		From:        req.From,
+		To:          req.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ArchiveReplayResponse{
+		Published: resp.Published,
+		Failed:    resp.Failed,
+	}, nil
+}
@@ -26,6 +26,16 @@ type NotificationCategoriesResponse struct {
 	Categories []NotificationCategoryResponse `json:"categories"`
 }
 
+type NotificationTriggerResponse struct {
+	Key         string         `json:"key"`
+	Description string         `json:"description"`
+	DataSchema  map[string]any `json:"data_schema"`
+}
+
+type NotificationTriggersResponse struct {
+	Triggers []NotificationTriggerResponse `json:"triggers"`
+}
+
 type NotificationSettingResponse struct {
 	CategoryID int64  `json:"category_id"`
 	Channel    string `json:"channel"`
@@ -34,6 +44,8 @@ type NotificationSettingResponse struct {
 
 type NotificationSettingsResponse struct {
 	Settings []NotificationSettingResponse `json:"settings"`
+	// Version is sent back as If-Match or expected_version when updating the settings.
+	Version valueobject.Version `json:"version,string"`
 }
 
 type NotificationSettingRequest struct {
@@ -44,6 +56,8 @@ type NotificationSettingRequest struct {
 
 type NotificationSettingsUpdateRequest struct {
 	Settings []NotificationSettingRequest `json:"settings"`
+	// ExpectedVersion is the version read with the settings; the If-Match header takes precedence.
+	ExpectedVersion int64 `json:"expected_version,omitempty,string"`
 }
 
 type NotificationResponse struct {
@@ -59,3 +73,30 @@ type NotificationResponse struct {
 type NotificationsResponse struct {
 	Notifications []NotificationResponse `json:"notifications"`
 }
+
+type TemplateSaveRequest struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	// ExpectedVersion is the version read with the template; the If-Match header takes precedence.
+	ExpectedVersion int64 `json:"expected_version,omitempty,string"`
+}
+
+type TemplateResponse struct {
+	TriggerKey string              `json:"trigger_key"`
+	Channel    string              `json:"channel"`
+	Subject    string              `json:"subject"`
+	Body       string              `json:"body"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+	Version    valueobject.Version `json:"version,string"`
+}
+
+type ArchiveReplayRequest struct {
+	Destination string    `json:"destination"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+}
+
+type ArchiveReplayResponse struct {
+	Published int `json:"published"`
+	Failed    int `json:"failed"`
+}
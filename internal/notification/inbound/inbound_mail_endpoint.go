@@ -0,0 +1,315 @@
+package inbound
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/notification/usecase"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/router"
+)
+
+const (
+	providerSendGrid = "sendgrid"
+	providerSES      = "ses"
+
+	// maxInboundFieldBytes bounds every text field read from an inbound mail payload.
+	maxInboundFieldBytes = 1 << 20
+	// maxInboundBodyBytes bounds a whole SNS notification, raw message included.
+	maxInboundBodyBytes = 10 << 20
+	// maxSendGridBodyBytes bounds a SendGrid post; SendGrid caps inbound messages at 30 MB.
+	maxSendGridBodyBytes = 30 << 20
+)
+
+// InboundSendGrid receives replies to notification emails from SendGrid Inbound Parse.
+// @Summary Receive inbound mail from SendGrid
+// @Description Webhook for SendGrid Inbound Parse (multipart form). Replies sent to a signed notification reply address are stored and published as notification.replied, but only when sent from the address the notification was emailed to. Posts are authenticated by their ECDSA signature once modules.notification.inbound.sendgrid.verification_key is set, and a signature seen before or signed outside modules.notification.inbound.sendgrid.tolerance_seconds is rejected; without a key the shared token in the query is checked instead, so no session is required.
+// @Tags Notification
+// @Accept multipart/form-data
+// @Param token query string false "Webhook token while no verification key is set (modules.notification.inbound.webhook_token)"
+// @Param X-Twilio-Email-Event-Webhook-Signature header string false "Base64 ECDSA signature of the post (required when modules.notification.inbound.sendgrid.verification_key is set)"
+// @Param X-Twilio-Email-Event-Webhook-Timestamp header string false "Unix seconds the post was signed at (required with the signature)"
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid payload"
+// @Failure 401 {object} router.errorResponse "Invalid webhook token, signature or timestamp"
+// @Failure 404 {object} router.errorResponse "Inbound mail disabled"
+// @Failure 409 {object} router.errorResponse "Post already received"
+// @Failure 413 {object} router.errorResponse "Post too large"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/notification/inbound/sendgrid [post]
+func (h *HTTPEndpoint) InboundSendGrid(r *router.Request) (any, error) {
+	in, err := parseSendGridInbound(r)
+	if err != nil {
+		return nil, err
+	}
+	in.Token = r.GetQuery("token")
+	in.Verified = router.WebhookVerified(r.Context())
+
+	return nil, h.uc.ReceiveReply(r.Context(), *in)
+}
+
+// InboundSES receives replies to notification emails from an SES receipt rule publishing
+// to SNS.
+// @Summary Receive inbound mail from SES
+// @Description Webhook for SES receipt rules delivered through SNS (JSON). Replies sent to a signed notification reply address are stored and published as notification.replied, but only when sent from the address the notification was emailed to. Messages are authenticated by their SNS signature and an allowlisted topic, and a message ID seen before or a message published outside modules.notification.inbound.ses.tolerance_seconds is rejected, so no session is required.
+// @Tags Notification
+// @Accept json
+// @Success 204 "No Content"
+// @Failure 400 {object} router.errorResponse "Invalid payload"
+// @Failure 401 {object} router.errorResponse "Invalid signature, topic or timestamp"
+// @Failure 404 {object} router.errorResponse "Inbound mail disabled"
+// @Failure 409 {object} router.errorResponse "Message already received"
+// @Failure 413 {object} router.errorResponse "Message too large"
+// @Failure 422 {object} router.errorResponse "Validation error"
+// @Failure 500 {object} router.errorResponse "Internal server error"
+// @Router /api/v1/notification/inbound/ses [post]
+func (h *HTTPEndpoint) InboundSES(r *router.Request) (any, error) {
+	return nil, h.receiveSNS(r)
+}
+
+// parseSendGridInbound reads the text fields of a SendGrid Inbound Parse post. Attachments
+// are skipped without being buffered.
+func parseSendGridInbound(r *router.Request) (*usecase.ReceiveReplyInput, error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return nil, goerror.NewInvalidFormat("Invalid request content-type")
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, goerror.NewInvalidFormat()
+	}
+
+	fields := map[string]string{}
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, goerror.NewInvalidFormat()
+		}
+
+		switch name := part.FormName(); name {
+		case "from", "to", "subject", "text", "html", "headers", "envelope":
+			value, err := io.ReadAll(io.LimitReader(part, maxInboundFieldBytes))
+			if err != nil {
+				return nil, goerror.NewInvalidFormat()
+			}
+			fields[name] = string(value)
+		default:
+			if _, err := io.Copy(io.Discard, part); err != nil {
+				return nil, goerror.NewInvalidFormat()
+			}
+		}
+		if err := part.Close(); err != nil {
+			return nil, goerror.NewInvalidFormat()
+		}
+	}
+
+	// the envelope holds the actual recipients, including Bcc, which the To header lacks
+	var envelope struct {
+		To []string `json:"to"`
+	}
+	to := []string(nil)
+	if err := json.Unmarshal([]byte(fields["envelope"]), &envelope); err == nil {
+		to = envelope.To
+	}
+	if len(to) == 0 {
+		if list, err := mail.ParseAddressList(fields["to"]); err == nil {
+			for _, addr := range list {
+				to = append(to, addr.Address)
+			}
+		}
+	}
+
+	messageID := ""
+	if msg, err := mail.ReadMessage(strings.NewReader(strings.TrimRight(fields["headers"], "\r\n") + "\r\n\r\n")); err == nil {
+		messageID = strings.Trim(msg.Header.Get("Message-Id"), "<> ")
+	}
+	if messageID == "" {
+		// without a Message-ID, identical posts still map to the same reply
+		sum := sha256.Sum256([]byte(fields["headers"] + fields["from"] + fields["subject"] + fields["text"]))
+		messageID = hex.EncodeToString(sum[:])
+	}
+
+	return &usecase.ReceiveReplyInput{
+		Provider:  providerSendGrid,
+		MessageID: messageID,
+		From:      fields["from"],
+		To:        to,
+		Subject:   fields["subject"],
+		Text:      fields["text"],
+		HTML:      fields["html"],
+	}, nil
+}
+
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	Token            string `json:"Token"`
+	SubscribeURL     string `json:"SubscribeURL"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+func (m snsMessage) entity() entity.SNSMessage {
+	return entity.SNSMessage{
+		Type:             m.Type,
+		MessageID:        m.MessageID,
+		TopicArn:         m.TopicArn,
+		Subject:          m.Subject,
+		Message:          m.Message,
+		Timestamp:        m.Timestamp,
+		Token:            m.Token,
+		SubscribeURL:     m.SubscribeURL,
+		SignatureVersion: m.SignatureVersion,
+		Signature:        m.Signature,
+		SigningCertURL:   m.SigningCertURL,
+	}
+}
+
+type sesReceived struct {
+	NotificationType string `json:"notificationType"`
+	Receipt          struct {
+		Timestamp time.Time `json:"timestamp"`
+		Action    struct {
+			Encoding string `json:"encoding"`
+		} `json:"action"`
+	} `json:"receipt"`
+	Mail struct {
+		MessageID     string   `json:"messageId"`
+		Source        string   `json:"source"`
+		Destination   []string `json:"destination"`
+		CommonHeaders struct {
+			From    []string `json:"from"`
+			Subject string   `json:"subject"`
+		} `json:"commonHeaders"`
+	} `json:"mail"`
+	Content string `json:"content"`
+}
+
+// receiveSNS handles an SNS delivery of an SES receipt rule. SNS posts JSON with a
+// text/plain content type, so the body is decoded here instead of through DecodeBody.
+// Deliveries are authenticated by their SNS signature in router.Webhook rather than the
+// webhook token.
+func (h *HTTPEndpoint) receiveSNS(r *router.Request) error {
+	var msg snsMessage
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxInboundBodyBytes)).Decode(&msg); err != nil {
+		return goerror.NewInvalidFormat()
+	}
+
+	switch msg.Type {
+	case "SubscriptionConfirmation":
+		return h.uc.ConfirmReplySubscription(r.Context(), usecase.ConfirmReplySubscriptionInput{
+			Provider: providerSES,
+			Verified: router.WebhookVerified(r.Context()),
+			SNS:      msg.entity(),
+		})
+
+	case "Notification":
+		var ses sesReceived
+		if err := json.Unmarshal([]byte(msg.Message), &ses); err != nil {
+			return goerror.NewInvalidFormat()
+		}
+		if ses.NotificationType != "Received" {
+			return nil
+		}
+
+		in := usecase.ReceiveReplyInput{
+			Verified:   router.WebhookVerified(r.Context()),
+			Provider:   providerSES,
+			MessageID:  ses.Mail.MessageID,
+			From:       ses.Mail.Source,
+			To:         ses.Mail.Destination,
+			Subject:    ses.Mail.CommonHeaders.Subject,
+			ReceivedAt: ses.Receipt.Timestamp,
+		}
+		if len(ses.Mail.CommonHeaders.From) > 0 {
+			in.From = ses.Mail.CommonHeaders.From[0]
+		}
+
+		content := []byte(ses.Content)
+		if strings.EqualFold(ses.Receipt.Action.Encoding, "BASE64") {
+			decoded, err := base64.StdEncoding.DecodeString(ses.Content)
+			if err != nil {
+				return goerror.NewInvalidFormat()
+			}
+			content = decoded
+		}
+		if raw, err := mail.ReadMessage(bytes.NewReader(content)); err == nil {
+			in.Text, in.HTML = mimeBodies(raw.Header.Get("Content-Type"), raw.Header.Get("Content-Transfer-Encoding"), raw.Body)
+		}
+
+		return h.uc.ReceiveReply(r.Context(), in)
+
+	default:
+		// UnsubscribeConfirmation and future message types need no action
+		return nil
+	}
+}
+
+// mimeBodies returns the first text/plain and text/html parts of a MIME body, walking
+// nested multiparts.
+func mimeBodies(contentType, encoding string, body io.Reader) (text, html string) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				return text, html
+			}
+
+			partText, partHTML := mimeBodies(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if text == "" {
+				text = partText
+			}
+			if html == "" {
+				html = partHTML
+			}
+		}
+	}
+
+	switch strings.ToLower(encoding) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	value, err := io.ReadAll(io.LimitReader(body, maxInboundFieldBytes))
+	if err != nil {
+		return "", ""
+	}
+
+	switch mediaType {
+	case "text/plain":
+		return string(value), ""
+	case "text/html":
+		return "", string(value)
+	default:
+		return "", ""
+	}
+}
@@ -0,0 +1,15 @@
+package inbound
+
+import "github.com/shandysiswandi/gobite/internal/pkg/retention"
+
+type ucJob interface {
+	RetentionTargets() []retention.Target
+}
+
+func RegisterJob(sched *retention.Scheduler, uc ucJob) {
+	if sched == nil {
+		return
+	}
+
+	sched.Register(uc.RetentionTargets()...)
+}
@@ -5,18 +5,18 @@ import (
 	"log/slog"
 	"slices"
 
+	"github.com/shandysiswandi/gobite/internal/contracts"
 	"github.com/shandysiswandi/gobite/internal/pkg/config"
-	"github.com/shandysiswandi/gobite/internal/pkg/goroutine"
 	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jobs"
 	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
 	"github.com/shandysiswandi/gobite/internal/pkg/uid"
-	"github.com/shandysiswandi/gobite/internal/shared/event"
 )
 
 func RegisterMQConsumer(
 	ctx context.Context,
 	cfg config.Config,
-	routine *goroutine.Manager,
+	workers *jobs.Registry,
 	messenger messaging.Messaging,
 	uuid uid.StringID,
 	uc uc,
@@ -36,32 +36,93 @@ func RegisterMQConsumer(
 		handler            messaging.Handler
 	}{
 		{
-			name:               event.UserRegistrationDestinationConsumerNotification,
-			topic:              event.UserRegistrationDestination,
-			nsqConsumerName:    event.UserRegistrationDestinationConsumerNotification,
-			natsConsumerName:   event.UserRegistrationDestinationConsumerNotification,
-			kafkaConsumerName:  event.UserRegistrationDestinationConsumerNotification,
-			pubsubConsumerName: event.UserRegistrationDestinationConsumerNotification,
+			name:               contracts.UserRegisteredConsumerNotification,
+			topic:              contracts.UserRegisteredDestination,
+			nsqConsumerName:    contracts.UserRegisteredConsumerNotification,
+			natsConsumerName:   contracts.UserRegisteredConsumerNotification,
+			kafkaConsumerName:  contracts.UserRegisteredConsumerNotification,
+			pubsubConsumerName: contracts.UserRegisteredConsumerNotification,
 			handler:            mqHanlder.UserRegistrationNotification,
 		},
 		{
-			name:               event.UserForgotPasswordConsumerNotification,
-			topic:              event.UserForgotPasswordDestination,
-			nsqConsumerName:    event.UserForgotPasswordConsumerNotification,
-			natsConsumerName:   event.UserForgotPasswordConsumerNotification,
-			kafkaConsumerName:  event.UserForgotPasswordConsumerNotification,
-			pubsubConsumerName: event.UserForgotPasswordConsumerNotification,
+			name:               contracts.PasswordForgotConsumerNotification,
+			topic:              contracts.PasswordForgotDestination,
+			nsqConsumerName:    contracts.PasswordForgotConsumerNotification,
+			natsConsumerName:   contracts.PasswordForgotConsumerNotification,
+			kafkaConsumerName:  contracts.PasswordForgotConsumerNotification,
+			pubsubConsumerName: contracts.PasswordForgotConsumerNotification,
 			handler:            mqHanlder.UserForgotPasswordNotification,
 		},
+		{
+			name:               contracts.MFARevokedConsumerNotification,
+			topic:              contracts.MFARevokedDestination,
+			nsqConsumerName:    contracts.MFARevokedConsumerNotification,
+			natsConsumerName:   contracts.MFARevokedConsumerNotification,
+			kafkaConsumerName:  contracts.MFARevokedConsumerNotification,
+			pubsubConsumerName: contracts.MFARevokedConsumerNotification,
+			handler:            mqHanlder.UserMFARevokedNotification,
+		},
+		{
+			name:               contracts.MFARecoveryConsumerNotification,
+			topic:              contracts.MFARecoveryDestination,
+			nsqConsumerName:    contracts.MFARecoveryConsumerNotification,
+			natsConsumerName:   contracts.MFARecoveryConsumerNotification,
+			kafkaConsumerName:  contracts.MFARecoveryConsumerNotification,
+			pubsubConsumerName: contracts.MFARecoveryConsumerNotification,
+			handler:            mqHanlder.UserMFARecoveryNotification,
+		},
+		{
+			name:               contracts.SessionRevokedConsumerNotification,
+			topic:              contracts.SessionRevokedDestination,
+			nsqConsumerName:    contracts.SessionRevokedConsumerNotification,
+			natsConsumerName:   contracts.SessionRevokedConsumerNotification,
+			kafkaConsumerName:  contracts.SessionRevokedConsumerNotification,
+			pubsubConsumerName: contracts.SessionRevokedConsumerNotification,
+			handler:            mqHanlder.UserSessionRevokedNotification,
+		},
+		{
+			name:               contracts.NewSignInConsumerNotification,
+			topic:              contracts.NewSignInDestination,
+			nsqConsumerName:    contracts.NewSignInConsumerNotification,
+			natsConsumerName:   contracts.NewSignInConsumerNotification,
+			kafkaConsumerName:  contracts.NewSignInConsumerNotification,
+			pubsubConsumerName: contracts.NewSignInConsumerNotification,
+			handler:            mqHanlder.UserNewSignInNotification,
+		},
+		{
+			name:               contracts.NotificationRequestedConsumerNotification,
+			topic:              contracts.NotificationRequestedDestination,
+			nsqConsumerName:    contracts.NotificationRequestedConsumerNotification,
+			natsConsumerName:   contracts.NotificationRequestedConsumerNotification,
+			kafkaConsumerName:  contracts.NotificationRequestedConsumerNotification,
+			pubsubConsumerName: contracts.NotificationRequestedConsumerNotification,
+			handler:            mqHanlder.NotificationRequested,
+		},
+		{
+			name:               contracts.PushTokensRejectedConsumerNotification,
+			topic:              contracts.PushTokensRejectedDestination,
+			nsqConsumerName:    contracts.PushTokensRejectedConsumerNotification,
+			natsConsumerName:   contracts.PushTokensRejectedConsumerNotification,
+			kafkaConsumerName:  contracts.PushTokensRejectedConsumerNotification,
+			pubsubConsumerName: contracts.PushTokensRejectedConsumerNotification,
+			handler:            mqHanlder.PushTokensRejected,
+		},
 	}
 
+	archiveEnabled := cfg.GetBool("modules.notification.archive.enabled")
+
 	for _, consumer := range consumers {
 		if len(enableConsumerNames) > 0 && slices.Contains(enableConsumerNames, consumer.name) {
-			routine.Go(ctx, func(pCtx context.Context) error {
+			handler := consumer.handler
+			if archiveEnabled {
+				handler = mqHanlder.archived(consumer.topic, handler)
+			}
+
+			workers.Go(ctx, consumer.name, func(pCtx context.Context) error {
 				slog.InfoContext(ctx, "Running job for handling consumer", "consumer", consumer.name)
 				return messenger.Consume(pCtx,
 					consumer.topic,
-					consumer.handler,
+					handler,
 					messaging.WithChannel(consumer.nsqConsumerName),
 					messaging.WithQueueGroup(consumer.natsConsumerName),
 					messaging.WithGroup(consumer.kafkaConsumerName),
@@ -2,16 +2,17 @@ package inbound
 
 import (
 	"context"
-	"encoding/json"
 	"log/slog"
 
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
 	"github.com/shandysiswandi/gobite/internal/notification/usecase"
 	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
 	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
 	"github.com/shandysiswandi/gobite/internal/pkg/uid"
-	"github.com/shandysiswandi/gobite/internal/shared/event"
 )
 
+// keyOfCorrelationID is the header older publishers used for the correlation ID.
 const keyOfCorrelationID string = "cID"
 
 type MQHandler struct {
@@ -20,17 +21,67 @@ type MQHandler struct {
 	ins  instrument.Instrumentation
 }
 
-func (h *MQHandler) ensureCorrelationID(ctx context.Context, headers []messaging.Header) context.Context {
-	for i := range headers {
-		if headers[i].Key == keyOfCorrelationID {
-			return instrument.SetCorrelationID(ctx, string(headers[i].Value))
+// ensureCorrelationID makes sure ctx carries the publisher's carrier. Headers restored by
+// the messaging layer win; brokers without headers deliver it inside the envelope.
+func (h *MQHandler) ensureCorrelationID(ctx context.Context, msg messaging.Message) context.Context {
+	if instrument.CarrierFromContext(ctx).CorrelationID != "" {
+		return ctx
+	}
+
+	if carrier := contracts.CarrierOf(msg.Body()); len(carrier) > 0 {
+		ctx = instrument.ExtractCarrier(func(key string) string { return carrier[key] }).Context(ctx)
+		if instrument.CarrierFromContext(ctx).CorrelationID != "" {
+			return ctx
+		}
+	}
+
+	for _, hd := range msg.Headers() {
+		if hd.Key == keyOfCorrelationID {
+			return instrument.SetCorrelationID(ctx, string(hd.Value))
 		}
 	}
 	return instrument.SetCorrelationID(ctx, h.uuid.Generate())
 }
 
+// archived tees every message consumed from destination into the archive before handling it.
+// Messages re-injected from the archive are already stored and are not archived again.
+func (h *MQHandler) archived(destination string, next messaging.Handler) messaging.Handler {
+	return func(ctx context.Context, msg messaging.Message) error {
+		replayed := false
+		headers := make([]entity.ArchivedHeader, 0, len(msg.Headers()))
+		for _, hd := range msg.Headers() {
+			if hd.Key == entity.HeaderArchiveReplay {
+				replayed = true
+			}
+			headers = append(headers, entity.ArchivedHeader{Key: hd.Key, Value: hd.Value})
+		}
+		if _, ok := msg.Attributes()[entity.HeaderArchiveReplay]; ok {
+			replayed = true
+		}
+
+		if !replayed {
+			id := msg.ID()
+			if id == "" {
+				id = h.uuid.Generate()
+			}
+
+			h.uc.ArchiveMessage(h.ensureCorrelationID(ctx, msg), usecase.ArchiveMessageInput{
+				Destination: destination,
+				ID:          id,
+				Key:         msg.Key(),
+				Body:        msg.Body(),
+				Headers:     headers,
+				Attributes:  msg.Attributes(),
+				Timestamp:   msg.Timestamp(),
+			})
+		}
+
+		return next(ctx, msg)
+	}
+}
+
 func (h *MQHandler) UserRegistrationNotification(ctx context.Context, msg messaging.Message) error {
-	ctx = h.ensureCorrelationID(ctx, msg.Headers())
+	ctx = h.ensureCorrelationID(ctx, msg)
 
 	ctx, span := h.ins.Tracer("notification.inbound.mq").Start(ctx, "UserRegistrationNotification")
 	defer span.End()
@@ -38,8 +89,8 @@ func (h *MQHandler) UserRegistrationNotification(ctx context.Context, msg messag
 	body := msg.Body()
 	slog.InfoContext(ctx, "consume: user registration notification", "msg_body", string(body))
 
-	var payload event.UserRegistrationMessage
-	if err := json.Unmarshal(body, &payload); err != nil {
+	var payload contracts.UserRegistered
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
 		slog.ErrorContext(ctx, "failed to parse message body of user registration notification", "msg_body", string(body), "error", err)
 		return nil
 	}
@@ -58,7 +109,7 @@ func (h *MQHandler) UserRegistrationNotification(ctx context.Context, msg messag
 }
 
 func (h *MQHandler) UserForgotPasswordNotification(ctx context.Context, msg messaging.Message) error {
-	ctx = h.ensureCorrelationID(ctx, msg.Headers())
+	ctx = h.ensureCorrelationID(ctx, msg)
 
 	ctx, span := h.ins.Tracer("notification.inbound.mq").Start(ctx, "UserForgotPasswordNotification")
 	defer span.End()
@@ -66,8 +117,8 @@ func (h *MQHandler) UserForgotPasswordNotification(ctx context.Context, msg mess
 	body := msg.Body()
 	slog.InfoContext(ctx, "consume: user forgot password notification", "msg_body", string(body))
 
-	var payload event.UserForgotPasswordMessage
-	if err := json.Unmarshal(body, &payload); err != nil {
+	var payload contracts.PasswordForgot
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
 		slog.ErrorContext(ctx, "failed to parse message body of user forgot password notification", "msg_body", string(body), "error", err)
 		return nil
 	}
@@ -83,3 +134,176 @@ func (h *MQHandler) UserForgotPasswordNotification(ctx context.Context, msg mess
 
 	return nil
 }
+
+func (h *MQHandler) UserMFARevokedNotification(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("notification.inbound.mq").Start(ctx, "UserMFARevokedNotification")
+	defer span.End()
+
+	body := msg.Body()
+	slog.InfoContext(ctx, "consume: user mfa revoked notification", "msg_body", string(body))
+
+	var payload contracts.MFARevoked
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
+		slog.ErrorContext(ctx, "failed to parse message body of user mfa revoked notification", "msg_body", string(body), "error", err)
+		return nil
+	}
+
+	if err := h.uc.ConsumeUserMFARevoked(ctx, usecase.ConsumeUserMFARevokedInput{
+		UserID:   payload.UserID,
+		Email:    payload.Email,
+		FullName: payload.FullName,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to consume user mfa revoked", "msg_body", string(body), "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (h *MQHandler) UserMFARecoveryNotification(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("notification.inbound.mq").Start(ctx, "UserMFARecoveryNotification")
+	defer span.End()
+
+	body := msg.Body()
+	slog.InfoContext(ctx, "consume: user mfa recovery notification", "msg_body", string(body))
+
+	var payload contracts.MFARecovery
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
+		slog.ErrorContext(ctx, "failed to parse message body of user mfa recovery notification", "msg_body", string(body), "error", err)
+		return nil
+	}
+
+	if err := h.uc.ConsumeUserMFARecovery(ctx, usecase.ConsumeUserMFARecoveryInput{
+		UserID:      payload.UserID,
+		Email:       payload.Email,
+		FullName:    payload.FullName,
+		Stage:       payload.Stage,
+		Token:       payload.ChallengeToken,
+		AvailableAt: payload.AvailableAt,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to consume user mfa recovery", "msg_body", string(body), "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (h *MQHandler) UserSessionRevokedNotification(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("notification.inbound.mq").Start(ctx, "UserSessionRevokedNotification")
+	defer span.End()
+
+	body := msg.Body()
+	slog.InfoContext(ctx, "consume: user session revoked notification", "msg_body", string(body))
+
+	var payload contracts.SessionRevoked
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
+		slog.ErrorContext(ctx, "failed to parse message body of user session revoked notification", "msg_body", string(body), "error", err)
+		return nil
+	}
+
+	if err := h.uc.ConsumeUserSessionRevoked(ctx, usecase.ConsumeUserSessionRevokedInput{
+		UserID:       payload.UserID,
+		Email:        payload.Email,
+		FullName:     payload.FullName,
+		RevokedCount: payload.RevokedCount,
+		Limit:        payload.Limit,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to consume user session revoked", "msg_body", string(body), "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (h *MQHandler) UserNewSignInNotification(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("notification.inbound.mq").Start(ctx, "UserNewSignInNotification")
+	defer span.End()
+
+	body := msg.Body()
+	slog.InfoContext(ctx, "consume: user new sign in notification", "msg_body", string(body))
+
+	var payload contracts.NewSignIn
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
+		slog.ErrorContext(ctx, "failed to parse message body of user new sign in notification", "msg_body", string(body), "error", err)
+		return nil
+	}
+
+	if err := h.uc.ConsumeUserNewSignIn(ctx, usecase.ConsumeUserNewSignInInput{
+		UserID:     payload.UserID,
+		Email:      payload.Email,
+		FullName:   payload.FullName,
+		IP:         payload.IP,
+		UserAgent:  payload.UserAgent,
+		DeviceName: payload.DeviceName,
+		Country:    payload.Country,
+		SignedInAt: payload.SignedInAt,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to consume user new sign in", "msg_body", string(body), "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (h *MQHandler) NotificationRequested(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("notification.inbound.mq").Start(ctx, "NotificationRequested")
+	defer span.End()
+
+	body := msg.Body()
+	slog.InfoContext(ctx, "consume: notification requested", "msg_body", string(body))
+
+	var payload contracts.NotificationRequested
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
+		slog.ErrorContext(ctx, "failed to parse message body of notification requested", "msg_body", string(body), "error", err)
+		return nil
+	}
+
+	if err := h.uc.ConsumeNotificationRequested(ctx, usecase.ConsumeNotificationRequestedInput{
+		UserID:     payload.UserID,
+		Email:      payload.Email,
+		TriggerKey: payload.TriggerKey,
+		Channels:   payload.Channels,
+		Data:       payload.Data,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to consume notification requested", "msg_body", string(body), "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (h *MQHandler) PushTokensRejected(ctx context.Context, msg messaging.Message) error {
+	ctx = h.ensureCorrelationID(ctx, msg)
+
+	ctx, span := h.ins.Tracer("notification.inbound.mq").Start(ctx, "PushTokensRejected")
+	defer span.End()
+
+	body := msg.Body()
+	slog.InfoContext(ctx, "consume: push tokens rejected", "msg_body", string(body))
+
+	var payload contracts.PushTokensRejected
+	if _, err := contracts.Unmarshal(body, &payload); err != nil {
+		slog.ErrorContext(ctx, "failed to parse message body of push tokens rejected", "msg_body", string(body), "error", err)
+		return nil
+	}
+
+	if err := h.uc.ConsumePushTokensRejected(ctx, usecase.ConsumePushTokensRejectedInput{
+		Tokens: payload.Tokens,
+		Reason: payload.Reason,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to consume push tokens rejected", "msg_body", string(body), "error", err)
+		return err
+	}
+
+	return nil
+}
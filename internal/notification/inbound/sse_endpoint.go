@@ -2,6 +2,7 @@ package inbound
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -12,7 +13,7 @@ import (
 
 // StreamNotifications streams notification updates to the client using SSE.
 // @Summary Stream notifications
-// @Description Streams notification updates using Server-Sent Events (SSE).
+// @Description Streams notification updates using Server-Sent Events (SSE). Emits "notification" for new items and "read" when any session marks items read. A client that falls too far behind is disconnected and should reload the inbox when it reconnects.
 // @Tags Notification
 // @Security BearerAuth
 // @Produce text/event-stream
@@ -33,7 +34,20 @@ func (h *HTTPEndpoint) StreamNotifications(w http.ResponseWriter, r *http.Reques
 	}
 	ctx := r.Context()
 
+	// a write deadline per message ends the handler of a stalled client, instead of leaving it
+	// blocked on a full TCP window while its events pile up.
+	rc := http.NewResponseController(w)
+	setDeadline := func() {
+		if h.streamWriteTimeout <= 0 {
+			return
+		}
+		if err := rc.SetWriteDeadline(time.Now().Add(h.streamWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			slog.WarnContext(ctx, "failed to set stream write deadline", "error", err)
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
+	setDeadline()
 	if _, err := fmt.Fprint(w, ": connected\n\n"); err != nil {
 		slog.ErrorContext(ctx, "failed to send response connected", "error", err)
 		return
@@ -59,6 +73,7 @@ func (h *HTTPEndpoint) StreamNotifications(w http.ResponseWriter, r *http.Reques
 
 		// heartbeat ping, so proxies won’t drop idle connections.
 		case <-ticker.C:
+			setDeadline()
 			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
 				return
 			}
@@ -66,14 +81,16 @@ func (h *HTTPEndpoint) StreamNotifications(w http.ResponseWriter, r *http.Reques
 
 		case evt, ok := <-stream:
 			if !ok {
+				// closed by the server, possibly for being too slow; the client reconnects
 				return
 			}
-			payload, err := json.Marshal(evt)
+			payload, err := json.Marshal(evt.Data)
 			if err != nil {
 				slog.ErrorContext(ctx, "failed to marshal data", "error", err)
 				continue
 			}
-			if _, err := fmt.Fprintf(w, "event: notification\ndata: %s\n\n", payload); err != nil {
+			setDeadline()
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", evt.Name, payload); err != nil {
 				slog.ErrorContext(ctx, "failed to send response data", "error", err)
 				return
 			}
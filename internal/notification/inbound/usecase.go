@@ -10,6 +10,13 @@ import (
 type ucConsumer interface {
 	ConsumeUserRegistration(ctx context.Context, in usecase.ConsumeUserRegistrationInput) error
 	ConsumeUserForgotPassword(ctx context.Context, msg usecase.ConsumeUserForgotPasswordInput) error
+	ConsumeUserMFARevoked(ctx context.Context, in usecase.ConsumeUserMFARevokedInput) error
+	ConsumeUserMFARecovery(ctx context.Context, in usecase.ConsumeUserMFARecoveryInput) error
+	ConsumeUserSessionRevoked(ctx context.Context, in usecase.ConsumeUserSessionRevokedInput) error
+	ConsumeUserNewSignIn(ctx context.Context, in usecase.ConsumeUserNewSignInInput) error
+	ConsumeNotificationRequested(ctx context.Context, in usecase.ConsumeNotificationRequestedInput) error
+	ConsumePushTokensRejected(ctx context.Context, in usecase.ConsumePushTokensRejectedInput) error
+	ArchiveMessage(ctx context.Context, in usecase.ArchiveMessageInput)
 }
 
 type ucStream interface {
@@ -23,10 +30,17 @@ type uc interface {
 	DeviceRegister(ctx context.Context, in usecase.DeviceRegisterInput) error
 	DeviceRemove(ctx context.Context, in usecase.DeviceRemoveInput) error
 	ListCategories(ctx context.Context) ([]entity.Category, error)
-	ListSettings(ctx context.Context) ([]entity.UserSetting, error)
+	ListTriggers(ctx context.Context) ([]entity.Trigger, error)
+	TemplateSave(ctx context.Context, in usecase.TemplateSaveInput) error
+	TemplateDetail(ctx context.Context, in usecase.TemplateDetailInput) (*entity.Template, error)
+	ListSettings(ctx context.Context) (*usecase.ListSettingsOutput, error)
 	UpdateSettings(ctx context.Context, in usecase.UpdateSettingsInput) error
+	Unsubscribe(ctx context.Context, in usecase.UnsubscribeInput) error
+	ReceiveReply(ctx context.Context, in usecase.ReceiveReplyInput) error
+	ConfirmReplySubscription(ctx context.Context, in usecase.ConfirmReplySubscriptionInput) error
 	ListInbox(ctx context.Context, in usecase.ListInboxInput) ([]entity.NotificationItem, error)
 	MarkInboxRead(ctx context.Context, in usecase.MarkInboxReadInput) error
 	MarkAllInboxRead(ctx context.Context) error
 	DeleteInbox(ctx context.Context, in usecase.DeleteInboxInput) error
+	ArchiveReplay(ctx context.Context, in usecase.ArchiveReplayInput) (*usecase.ArchiveReplayOutput, error)
 }
@@ -0,0 +1,15 @@
+package inbound
+
+import "github.com/shandysiswandi/gobite/internal/pkg/userdata"
+
+type ucUserData interface {
+	UserDataSources() []userdata.Source
+}
+
+func RegisterUserData(reg *userdata.Registry, uc ucUserData) {
+	if reg == nil {
+		return
+	}
+
+	reg.Register(uc.UserDataSources()...)
+}
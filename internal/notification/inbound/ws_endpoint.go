@@ -0,0 +1,34 @@
+package inbound
+
+import (
+	"github.com/shandysiswandi/gobite/internal/pkg/router"
+)
+
+// SocketNotifications pushes notification updates to the client over a WebSocket.
+// @Summary Notifications WebSocket
+// @Description Upgrades to a WebSocket that pushes the same updates as the SSE stream, as JSON text frames of the form {"event":"notification"|"read","data":{...}}. Browsers authenticate with the subprotocols ["bearer", "<access token>"]. The server pings the client, which must answer with pongs. A client that falls too far behind is disconnected and should reload the inbox when it reconnects. The connection closes with code 1008 (policy violation) when the access token expires or is revoked; sign in or refresh before reconnecting.
+// @Tags Notification
+// @Security BearerAuth
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 401 {object} router.errorResponse "Unauthorized"
+// @Failure 403 {object} router.errorResponse "Origin not allowed or not a user token"
+// @Router /api/v1/notification/ws [get]
+func (h *HTTPEndpoint) SocketNotifications(c *router.WSConn) {
+	stream := h.uc.StreamNotifications(c.Context(), c.UserID())
+
+	for {
+		select {
+		case <-c.Context().Done():
+			return
+
+		case evt, ok := <-stream:
+			if !ok {
+				// closed by the server, possibly for being too slow; the client reconnects
+				return
+			}
+			if !c.Send(router.WSMessage{Event: evt.Name, Data: evt.Data}) {
+				return
+			}
+		}
+	}
+}
@@ -2,59 +2,149 @@ package notification
 
 import (
 	"context"
+	"net/http"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/casbin/casbin/v3"
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
 	"github.com/shandysiswandi/gobite/internal/notification/inbound"
+	"github.com/shandysiswandi/gobite/internal/notification/outbound/archive"
 	"github.com/shandysiswandi/gobite/internal/notification/outbound/db"
 	"github.com/shandysiswandi/gobite/internal/notification/outbound/email"
+	"github.com/shandysiswandi/gobite/internal/notification/outbound/mq"
+	"github.com/shandysiswandi/gobite/internal/notification/outbound/sendgrid"
+	"github.com/shandysiswandi/gobite/internal/notification/outbound/sns"
 	"github.com/shandysiswandi/gobite/internal/notification/usecase"
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
 	"github.com/shandysiswandi/gobite/internal/pkg/clock"
 	"github.com/shandysiswandi/gobite/internal/pkg/config"
-	"github.com/shandysiswandi/gobite/internal/pkg/goroutine"
 	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jobs"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 	"github.com/shandysiswandi/gobite/internal/pkg/mail"
 	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
+	"github.com/shandysiswandi/gobite/internal/pkg/pgxguard"
+	"github.com/shandysiswandi/gobite/internal/pkg/replay"
+	"github.com/shandysiswandi/gobite/internal/pkg/retention"
 	"github.com/shandysiswandi/gobite/internal/pkg/router"
+	"github.com/shandysiswandi/gobite/internal/pkg/signedurl"
+	"github.com/shandysiswandi/gobite/internal/pkg/storage"
 	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"github.com/shandysiswandi/gobite/internal/pkg/userdata"
 	"github.com/shandysiswandi/gobite/internal/pkg/validator"
+	"google.golang.org/grpc"
 )
 
 type Dependency struct {
-	Ctx        context.Context
-	DBConn     *pgxpool.Pool
-	Messaging  messaging.Messaging
-	Config     config.Config
-	Instrument instrument.Instrumentation
-	UID        uid.NumberID
-	UUID       uid.StringID
-	Clock      clock.Clocker
-	Goroutine  *goroutine.Manager
-	Validator  validator.Validator
-	Router     *router.Router
-	Mail       mail.Mail
-	JWT        jwt.JWT
+	Ctx         context.Context
+	DBConn      *pgxguard.Pool
+	HTTPClient  *http.Client
+	Messaging   messaging.Messaging
+	Config      config.Config
+	Instrument  instrument.Instrumentation
+	UID         uid.NumberID
+	UUID        uid.StringID
+	Clock       clock.Clocker
+	Validator   validator.Validator
+	Router      *router.Router
+	GRPC        *grpc.Server
+	Mail        mail.Mail
+	SignedURL   signedurl.Signer
+	JWT         jwt.JWT
+	Storage     storage.Storage
+	Enforcer    *casbin.Enforcer
+	AuthzShadow *authz.Shadow
+	Retention   *retention.Scheduler
+	Jobs        *jobs.Registry
+	UserData    *userdata.Registry
+	Replay      replay.Guard
 }
 
 func New(dep Dependency) error {
 	dbNotif := db.NewDB(dep.DBConn, dep.Instrument)
 	repoMail := email.New(dep.Mail, dep.Instrument)
+	repoArchive := archive.New(
+		dep.Storage,
+		dep.Config.GetString("modules.notification.archive.bucket"),
+		dep.Config.GetString("modules.notification.archive.prefix"),
+		dep.Instrument,
+	)
+	repoMessaging := mq.NewMessaging(dep.Messaging, dep.UUID, dep.Clock, dep.Instrument)
 
 	uc := usecase.NewNotification(usecase.Dependency{
-		RepoDB:     dbNotif,
-		Config:     dep.Config,
-		UID:        dep.UID,
-		Clock:      dep.Clock,
-		Validator:  dep.Validator,
-		JWT:        dep.JWT,
-		RepoMail:   repoMail,
-		Instrument: dep.Instrument,
+		RepoDB:        dbNotif,
+		Config:        dep.Config,
+		UID:           dep.UID,
+		Clock:         dep.Clock,
+		Validator:     dep.Validator,
+		JWT:           dep.JWT,
+		RepoMail:      repoMail,
+		SignedURL:     dep.SignedURL,
+		Instrument:    dep.Instrument,
+		Enforcer:      dep.Enforcer,
+		AuthzShadow:   dep.AuthzShadow,
+		RepoArchive:   repoArchive,
+		RepoMessaging: repoMessaging,
 	})
 
-	inbound.RegisterHTTPEndpoint(dep.Router, uc)
+	inbound.RegisterHTTPEndpoint(dep.Router, uc, dep.Config.GetSecond("modules.notification.stream.write_timeout_seconds"), inboundWebhooks(dep))
+	if dep.GRPC != nil {
+		inbound.RegisterGRPCService(dep.GRPC, uc)
+	}
+	inbound.RegisterJob(dep.Retention, uc)
+	inbound.RegisterUserData(dep.UserData, uc)
 	if dep.Ctx != nil {
-		inbound.RegisterMQConsumer(dep.Ctx, dep.Config, dep.Goroutine, dep.Messaging, dep.UUID, uc, dep.Instrument)
+		if err := uc.SyncTriggers(dep.Ctx); err != nil {
+			return err
+		}
+
+		inbound.RegisterMQConsumer(dep.Ctx, dep.Config, dep.Jobs, dep.Messaging, dep.UUID, uc, dep.Instrument)
 	}
 
 	return nil
 }
+
+// inboundWebhooks plugs the SNS and SendGrid signatures into the inbound mail webhooks.
+// SendGrid posts are only verified once a verification key is set, and are checked against
+// the webhook token until then.
+func inboundWebhooks(dep Dependency) inbound.InboundWebhooks {
+	webhooks := inbound.InboundWebhooks{
+		SendGrid: router.WebhookConfig{
+			Name:      "notification_inbound_sendgrid",
+			Tolerance: dep.Config.GetSecond("modules.notification.inbound.sendgrid.tolerance_seconds"),
+			Replay:    dep.Replay,
+			Clock:     dep.Clock,
+		},
+		SES: router.WebhookConfig{
+			Name:      "notification_inbound_ses",
+			Verifier:  sns.New(dep.HTTPClient, dep.Instrument).Webhook(dep.Config.GetArray("modules.notification.inbound.ses.topic_arns")),
+			Tolerance: dep.Config.GetSecond("modules.notification.inbound.ses.tolerance_seconds"),
+			Replay:    dep.Replay,
+			Clock:     dep.Clock,
+		},
+	}
+	if key := dep.Config.GetString("modules.notification.inbound.sendgrid.verification_key"); key != "" {
+		webhooks.SendGrid.Verifier = sendgrid.New(dep.Instrument).Webhook(key)
+	}
+
+	return webhooks
+}
+
+// ValidateRequested checks a notification request against the schema of its trigger, so
+// publishers can reject bad data before it is queued.
+func ValidateRequested(msg contracts.NotificationRequested) error {
+	return entity.ValidateTriggerData(entity.TriggerKey(msg.TriggerKey), msg.Data)
+}
+
+// Seed inserts the notification fixtures the database is missing, or with verify only
+// compares them, and returns the rows that differ from the fixtures.
+func Seed(ctx context.Context, conn *pgxguard.Pool, cfg config.Config, ins instrument.Instrumentation, verify bool) ([]entity.FixtureDrift, error) {
+	uc := usecase.NewNotification(usecase.Dependency{
+		RepoDB:     db.NewDB(conn, ins),
+		Config:     cfg,
+		Clock:      clock.New(),
+		Instrument: ins,
+	})
+
+	return uc.SeedFixtures(ctx, verify)
+}
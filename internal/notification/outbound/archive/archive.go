@@ -0,0 +1,229 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/storage"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type header struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type record struct {
+	Destination string            `json:"destination"`
+	ID          string            `json:"id"`
+	Key         []byte            `json:"key,omitempty"`
+	Body        []byte            `json:"body"`
+	Headers     []header          `json:"headers,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	Timestamp   time.Time         `json:"timestamp"`
+	ArchivedAt  time.Time         `json:"archived_at"`
+}
+
+// Archive stores consumed messages as one JSON object per message, partitioned by
+// destination and the hour the message was published.
+type Archive struct {
+	client storage.Storage
+	bucket string
+	prefix string
+	ins    instrument.Instrumentation
+}
+
+func New(client storage.Storage, bucket, prefix string, ins instrument.Instrumentation) *Archive {
+	return &Archive{client: client, bucket: bucket, prefix: strings.Trim(prefix, "/"), ins: ins}
+}
+
+func (a *Archive) Put(ctx context.Context, msg entity.ArchivedMessage) (err error) {
+	ctx, span := a.ins.Tracer("notification.outbound.archive").Start(ctx, "Put")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	rec := record{
+		Destination: msg.Destination,
+		ID:          msg.ID,
+		Key:         msg.Key,
+		Body:        msg.Body,
+		Attributes:  msg.Attributes,
+		Timestamp:   msg.Timestamp.UTC(),
+		ArchivedAt:  msg.ArchivedAt.UTC(),
+	}
+	for _, h := range msg.Headers {
+		rec.Headers = append(rec.Headers, header(h))
+	}
+
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	// the nanosecond timestamp keeps keys sortable; the id keeps them unique
+	key := fmt.Sprintf("%s/%d-%s.json", a.partition(msg.Destination, rec.Timestamp), rec.Timestamp.UnixNano(), msg.ID)
+
+	_, err = a.client.PutObject(ctx, a.bucket, key, bytes.NewReader(body), storage.PutOptions{
+		Size:        int64(len(body)),
+		ContentType: "application/json",
+		Metadata:    map[string]string{"destination": msg.Destination},
+	})
+
+	return err
+}
+
+// List returns the archived messages of destination published in [from, to), oldest first.
+func (a *Archive) List(ctx context.Context, destination string, from, to time.Time) (msgs []entity.ArchivedMessage, err error) {
+	ctx, span := a.ins.Tracer("notification.outbound.archive").Start(ctx, "List")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	from, to = from.UTC(), to.UTC()
+	for hour := from.Truncate(time.Hour); hour.Before(to); hour = hour.Add(time.Hour) {
+		objects, err := a.client.ListObjects(ctx, a.bucket, a.partition(destination, hour)+"/", storage.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		slices.SortFunc(objects, func(x, y storage.ObjectInfo) int { return strings.Compare(x.Key, y.Key) })
+
+		for _, obj := range objects {
+			msg, err := a.get(ctx, obj.Key)
+			if err != nil {
+				return nil, err
+			}
+			if msg.Timestamp.Before(from) || !msg.Timestamp.Before(to) {
+				continue
+			}
+			msgs = append(msgs, *msg)
+		}
+	}
+
+	return msgs, nil
+}
+
+// CountBefore returns how many archived messages were stored before the cutoff.
+func (a *Archive) CountBefore(ctx context.Context, before time.Time) (n int64, err error) {
+	ctx, span := a.ins.Tracer("notification.outbound.archive").Start(ctx, "CountBefore")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	objects, err := a.client.ListObjects(ctx, a.bucket, a.root(), storage.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, obj := range objects {
+		if obj.UpdatedAt.Before(before) {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// DeleteBefore removes at most limit archived messages stored before the cutoff.
+func (a *Archive) DeleteBefore(ctx context.Context, before time.Time, limit int32) (n int64, err error) {
+	ctx, span := a.ins.Tracer("notification.outbound.archive").Start(ctx, "DeleteBefore")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	objects, err := a.client.ListObjects(ctx, a.bucket, a.root(), storage.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, obj := range objects {
+		if n >= int64(limit) {
+			break
+		}
+		if !obj.UpdatedAt.Before(before) {
+			continue
+		}
+		if err := a.client.DeleteObject(ctx, a.bucket, obj.Key); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+func (a *Archive) get(ctx context.Context, key string) (*entity.ArchivedMessage, error) {
+	rc, _, err := a.client.GetObject(ctx, a.bucket, key, storage.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	body, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	var rec record
+	if err := json.Unmarshal(body, &rec); err != nil {
+		return nil, fmt.Errorf("decode archived message %s: %w", key, err)
+	}
+
+	msg := &entity.ArchivedMessage{
+		Destination: rec.Destination,
+		ID:          rec.ID,
+		Key:         rec.Key,
+		Body:        rec.Body,
+		Attributes:  rec.Attributes,
+		Timestamp:   rec.Timestamp,
+		ArchivedAt:  rec.ArchivedAt,
+	}
+	for _, h := range rec.Headers {
+		msg.Headers = append(msg.Headers, entity.ArchivedHeader(h))
+	}
+
+	return msg, nil
+}
+
+// root is the listing prefix covering every archived message.
+func (a *Archive) root() string {
+	if a.prefix == "" {
+		return ""
+	}
+
+	return a.prefix + "/"
+}
+
+func (a *Archive) partition(destination string, t time.Time) string {
+	p := destination + "/" + t.UTC().Format("2006/01/02/15")
+	if a.prefix != "" {
+		p = a.prefix + "/" + p
+	}
+
+	return p
+}
@@ -6,21 +6,21 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shandysiswandi/gobite/internal/notification/outbound/db/sqlc"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
-	"github.com/shandysiswandi/gobite/internal/pkg/sqlc"
+	"github.com/shandysiswandi/gobite/internal/pkg/pgxguard"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type DB struct {
-	conn  *pgxpool.Pool
+	conn  *pgxguard.Pool
 	query *sqlc.Queries
 	ins   instrument.Instrumentation
 }
 
-func NewDB(conn *pgxpool.Pool, ins instrument.Instrumentation) *DB {
+func NewDB(conn *pgxguard.Pool, ins instrument.Instrumentation) *DB {
 	return &DB{
 		conn:  conn,
 		query: sqlc.New(conn),
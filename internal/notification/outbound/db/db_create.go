@@ -6,8 +6,9 @@ import (
 	"log/slog"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shandysiswandi/gobite/internal/notification/entity"
-	"github.com/shandysiswandi/gobite/internal/pkg/sqlc"
+	"github.com/shandysiswandi/gobite/internal/notification/outbound/db/sqlc"
 )
 
 func (s *DB) RegisterUserDevice(ctx context.Context, userID int64, deviceToken, platform string) (err error) {
@@ -37,6 +38,31 @@ func (s *DB) CreateNotification(ctx context.Context, data entity.CreateNotificat
 	return s.mapError(err)
 }
 
+// CreateReply stores an inbound reply. It reports false when the provider already
+// delivered the same message.
+func (s *DB) CreateReply(ctx context.Context, r entity.Reply) (_ bool, err error) {
+	ctx, span := s.startSpan(ctx, "CreateReply")
+	defer func() { s.endSpan(span, err) }()
+
+	n, err := s.query.CreateNotificationReply(ctx, sqlc.CreateNotificationReplyParams{
+		ID:             r.ID,
+		NotificationID: r.NotificationID,
+		UserID:         r.UserID,
+		Provider:       r.Provider,
+		MessageID:      r.MessageID,
+		FromAddress:    r.FromAddress,
+		Subject:        r.Subject,
+		Body:           r.Body,
+		Metadata:       r.Metadata,
+		ReceivedAt:     pgtype.Timestamptz{Time: r.ReceivedAt, Valid: true},
+	})
+	if err != nil {
+		return false, s.mapError(err)
+	}
+
+	return n > 0, nil
+}
+
 func (s *DB) CreateNotificationWithDeliveryLog(ctx context.Context, n entity.CreateNotification, dl entity.CreateDeliveryLog) (_ int64, err error) {
 	ctx, span := s.startSpan(ctx, "CreateNotificationWithDeliveryLog")
 	defer func() { s.endSpan(span, err) }()
@@ -1,6 +1,12 @@
 package db
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shandysiswandi/gobite/internal/notification/outbound/db/sqlc"
+)
 
 func (s *DB) RemoveUserDevice(ctx context.Context, deviceToken string) (err error) {
 	ctx, span := s.startSpan(ctx, "RemoveUserDevice")
@@ -9,3 +15,45 @@ func (s *DB) RemoveUserDevice(ctx context.Context, deviceToken string) (err erro
 	err = s.query.RemoveNotificationUserDevice(ctx, deviceToken)
 	return s.mapError(err)
 }
+
+func (s *DB) RemoveUserDevices(ctx context.Context, deviceTokens []string) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "RemoveUserDevices")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.query.RemoveNotificationUserDevicesByTokens(ctx, deviceTokens)
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
+}
+
+func (s *DB) DeleteUserDevicesInactiveBefore(ctx context.Context, before time.Time, limit int32) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteUserDevicesInactiveBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.query.DeleteNotificationUserDevicesInactiveBefore(ctx, sqlc.DeleteNotificationUserDevicesInactiveBeforeParams{
+		Before:    pgtype.Timestamptz{Valid: true, Time: before},
+		PageLimit: limit,
+	})
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
+}
+
+func (s *DB) DeleteNotificationsBefore(ctx context.Context, before time.Time, limit int32) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteNotificationsBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	affected, err := s.query.DeleteNotificationsBefore(ctx, sqlc.DeleteNotificationsBeforeParams{
+		Before:    pgtype.Timestamptz{Valid: true, Time: before},
+		PageLimit: limit,
+	})
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return affected, nil
+}
@@ -6,7 +6,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shandysiswandi/gobite/internal/notification/entity"
-	"github.com/shandysiswandi/gobite/internal/pkg/sqlc"
+	"github.com/shandysiswandi/gobite/internal/notification/outbound/db/sqlc"
 )
 
 func (s *DB) GetTemplateByTriggerChannel(ctx context.Context, tk entity.TriggerKey, ch entity.Channel) (_ *entity.Template, err error) {
@@ -28,6 +28,24 @@ func (s *DB) GetTemplateByTriggerChannel(ctx context.Context, tk entity.TriggerK
 		Channel:    row.Channel,
 		Subject:    row.Subject,
 		Body:       row.Body,
+		UpdatedAt:  row.UpdatedAt.Time,
+	}, nil
+}
+
+func (s *DB) GetNotificationByID(ctx context.Context, id int64) (_ *entity.NotificationRef, err error) {
+	ctx, span := s.startSpan(ctx, "GetNotificationByID")
+	defer func() { s.endSpan(span, err) }()
+
+	row, err := s.query.GetNotificationByID(ctx, id)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	return &entity.NotificationRef{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		CategoryID: row.CategoryID,
+		TriggerKey: entity.TriggerKey(row.TriggerKey),
 	}, nil
 }
 
@@ -53,6 +71,53 @@ func (s *DB) ListCategories(ctx context.Context) (_ []entity.Category, err error
 	return items, nil
 }
 
+// ListCategoryDefaults returns the default settings matrix, which applies to users without a
+// setting of their own.
+func (s *DB) ListCategoryDefaults(ctx context.Context) (_ []entity.UserSetting, err error) {
+	ctx, span := s.startSpan(ctx, "ListCategoryDefaults")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.query.ListNotificationCategoryDefaults(ctx)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	items := make([]entity.UserSetting, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, entity.UserSetting{
+			CategoryID: row.CategoryID,
+			Channel:    row.Channel,
+			IsEnabled:  row.IsEnabled,
+		})
+	}
+
+	return items, nil
+}
+
+func (s *DB) ListTemplates(ctx context.Context) (_ []entity.Template, err error) {
+	ctx, span := s.startSpan(ctx, "ListTemplates")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.query.ListNotificationTemplates(ctx)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	items := make([]entity.Template, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, entity.Template{
+			ID:         row.ID,
+			TriggerKey: entity.TriggerKey(row.TriggerKey),
+			CategoryID: row.CategoryID,
+			Channel:    row.Channel,
+			Subject:    row.Subject,
+			Body:       row.Body,
+		})
+	}
+
+	return items, nil
+}
+
 func (s *DB) ListUserSettings(ctx context.Context, userID int64) (_ []entity.UserSetting, err error) {
 	ctx, span := s.startSpan(ctx, "ListUserSettings")
 	defer func() { s.endSpan(span, err) }()
@@ -68,6 +133,7 @@ func (s *DB) ListUserSettings(ctx context.Context, userID int64) (_ []entity.Use
 			CategoryID: row.CategoryID,
 			Channel:    row.Channel,
 			IsEnabled:  row.IsEnabled,
+			UpdatedAt:  row.UpdatedAt.Time,
 		})
 	}
 
@@ -134,6 +200,67 @@ func (s *DB) ListNotifications(ctx context.Context, userID int64, status entity.
 	return items, nil
 }
 
+func (s *DB) ListNotificationsForExport(ctx context.Context, userID, afterID int64, limit int32) (_ []entity.NotificationExport, err error) {
+	ctx, span := s.startSpan(ctx, "ListNotificationsForExport")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.query.ListNotificationsByUserExport(ctx, sqlc.ListNotificationsByUserExportParams{
+		UserID:    userID,
+		AfterID:   afterID,
+		PageLimit: limit,
+	})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	items := make([]entity.NotificationExport, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, entity.NotificationExport{
+			ID:         row.ID,
+			CategoryID: row.CategoryID,
+			TriggerKey: entity.TriggerKey(row.TriggerKey),
+			Data:       row.Data,
+			Metadata:   row.Metadata,
+			ReadAt:     timePtrFromPgTimestamptz(row.ReadAt),
+			DeletedAt:  timePtrFromPgTimestamptz(row.DeletedAt),
+			CreatedAt:  timeFromPgTimestamptz(row.CreatedAt),
+		})
+	}
+
+	return items, nil
+}
+
+func (s *DB) SearchNotifications(ctx context.Context, userID int64, status entity.NotificationStatus, query string, limit, offset int32) (_ []entity.NotificationItem, err error) {
+	ctx, span := s.startSpan(ctx, "SearchNotifications")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.query.ListNotificationsByUserSearch(ctx, sqlc.ListNotificationsByUserSearchParams{
+		UserID:     userID,
+		Query:      query,
+		Status:     string(status),
+		PageLimit:  limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	items := make([]entity.NotificationItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, entity.NotificationItem{
+			ID:         row.ID,
+			CategoryID: row.CategoryID,
+			TriggerKey: entity.TriggerKey(row.TriggerKey),
+			Data:       row.Data,
+			Metadata:   row.Metadata,
+			ReadAt:     timePtrFromPgTimestamptz(row.ReadAt),
+			CreatedAt:  timeFromPgTimestamptz(row.CreatedAt),
+		})
+	}
+
+	return items, nil
+}
+
 func (s *DB) CountUnreadNotifications(ctx context.Context, userID int64) (_ int64, err error) {
 	ctx, span := s.startSpan(ctx, "CountUnreadNotifications")
 	defer func() { s.endSpan(span, err) }()
@@ -142,6 +269,22 @@ func (s *DB) CountUnreadNotifications(ctx context.Context, userID int64) (_ int6
 	return count, s.mapError(err)
 }
 
+func (s *DB) CountNotificationsBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "CountNotificationsBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	count, err := s.query.CountNotificationsBefore(ctx, pgtype.Timestamptz{Valid: true, Time: before})
+	return count, s.mapError(err)
+}
+
+func (s *DB) CountUserDevicesInactiveBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "CountUserDevicesInactiveBefore")
+	defer func() { s.endSpan(span, err) }()
+
+	count, err := s.query.CountNotificationUserDevicesInactiveBefore(ctx, pgtype.Timestamptz{Valid: true, Time: before})
+	return count, s.mapError(err)
+}
+
 func timePtrFromPgTimestamptz(t pgtype.Timestamptz) *time.Time {
 	if !t.Valid {
 		return nil
@@ -4,10 +4,14 @@ import (
 	"context"
 
 	"github.com/shandysiswandi/gobite/internal/notification/entity"
-	"github.com/shandysiswandi/gobite/internal/pkg/sqlc"
+	"github.com/shandysiswandi/gobite/internal/notification/outbound/db/sqlc"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 )
 
-func (s *DB) UpsertUserSettings(ctx context.Context, userID int64, settings []entity.UserSetting) (err error) {
+// UpsertUserSettings stores settings of the user. A non-zero expected refuses the
+// upsert with goerror.ErrVersionMismatch when the stored settings changed since that version.
+func (s *DB) UpsertUserSettings(ctx context.Context, userID int64, settings []entity.UserSetting, expected valueobject.Version) (err error) {
 	ctx, span := s.startSpan(ctx, "UpsertUserSettings")
 	defer func() { s.endSpan(span, err) }()
 
@@ -26,6 +30,28 @@ func (s *DB) UpsertUserSettings(ctx context.Context, userID int64, settings []en
 	}()
 
 	qtx := s.query.WithTx(tx)
+
+	if !expected.IsZero() {
+		// the row locks below cover nothing before the first write, so checks of the same user
+		// are also serialized on a lock of their own
+		if err := qtx.LockNotificationUserSettingsOwner(ctx, userID); err != nil {
+			return s.mapError(err)
+		}
+
+		updated, err := qtx.LockNotificationUserSettings(ctx, userID)
+		if err != nil {
+			return s.mapError(err)
+		}
+
+		stored := make([]entity.UserSetting, 0, len(updated))
+		for _, at := range updated {
+			stored = append(stored, entity.UserSetting{UpdatedAt: at.Time})
+		}
+		if entity.SettingsVersion(stored) != expected {
+			return goerror.ErrVersionMismatch
+		}
+	}
+
 	for _, setting := range settings {
 		err = qtx.UpsertNotificationUserSetting(ctx, sqlc.UpsertNotificationUserSettingParams{
 			UserID:     userID,
@@ -44,3 +70,139 @@ func (s *DB) UpsertUserSettings(ctx context.Context, userID int64, settings []en
 
 	return nil
 }
+
+func (s *DB) SyncTriggers(ctx context.Context, triggers []entity.Trigger) (err error) {
+	ctx, span := s.startSpan(ctx, "SyncTriggers")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return s.mapError(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	qtx := s.query.WithTx(tx)
+	for _, t := range triggers {
+		err = qtx.UpsertNotificationTrigger(ctx, sqlc.UpsertNotificationTriggerParams{
+			Key:         t.Key.String(),
+			Description: t.Description,
+			DataSchema:  t.Schema(),
+		})
+		if err != nil {
+			return s.mapError(err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return s.mapError(err)
+	}
+
+	return nil
+}
+
+// SeedFixtures inserts the fixture rows the database is missing and returns how many it
+// inserted. Rows that exist, even with other content, are left as they are.
+func (s *DB) SeedFixtures(ctx context.Context, f entity.Fixtures) (_ int64, err error) {
+	ctx, span := s.startSpan(ctx, "SeedFixtures")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return 0, s.mapError(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	qtx := s.query.WithTx(tx)
+
+	var inserted int64
+	for _, c := range f.Categories {
+		n, err := qtx.SeedNotificationCategory(ctx, sqlc.SeedNotificationCategoryParams{
+			ID:          c.ID,
+			Name:        c.Name,
+			Description: c.Description,
+			IsMandatory: c.IsMandatory,
+		})
+		if err != nil {
+			return 0, s.mapError(err)
+		}
+		inserted += n
+
+		for ch, on := range c.Channels {
+			n, err := qtx.SeedNotificationCategoryDefault(ctx, sqlc.SeedNotificationCategoryDefaultParams{
+				CategoryID: c.ID,
+				Channel:    ch,
+				IsEnabled:  on,
+			})
+			if err != nil {
+				return 0, s.mapError(err)
+			}
+			inserted += n
+		}
+	}
+
+	for _, t := range f.Templates {
+		n, err := qtx.SeedNotificationTemplate(ctx, sqlc.SeedNotificationTemplateParams{
+			ID:         t.ID,
+			TriggerKey: t.TriggerKey.String(),
+			CategoryID: t.CategoryID,
+			Channel:    t.Channel,
+			Subject:    t.Subject,
+			Body:       t.Body,
+		})
+		if err != nil {
+			return 0, s.mapError(err)
+		}
+		inserted += n
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, s.mapError(err)
+	}
+
+	return inserted, nil
+}
+
+// DeleteUserData removes every notification, device and setting of a user. Delivery logs
+// and replies go with their notification.
+func (s *DB) DeleteUserData(ctx context.Context, userID int64) (err error) {
+	ctx, span := s.startSpan(ctx, "DeleteUserData")
+	defer func() { s.endSpan(span, err) }()
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return s.mapError(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	qtx := s.query.WithTx(tx)
+
+	if _, err = qtx.DeleteNotificationsByUserID(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err = qtx.DeleteNotificationUserDevicesByUserID(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err = qtx.DeleteNotificationUserSettingsByUserID(ctx, userID); err != nil {
+		return s.mapError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return s.mapError(err)
+	}
+
+	return nil
+}
@@ -5,7 +5,8 @@ import (
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shandysiswandi/gobite/internal/notification/entity"
-	"github.com/shandysiswandi/gobite/internal/pkg/sqlc"
+	"github.com/shandysiswandi/gobite/internal/notification/outbound/db/sqlc"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 )
 
 func (s *DB) MarkNotificationRead(ctx context.Context, userID, notificationID int64) (_ bool, err error) {
@@ -67,3 +68,23 @@ func (s *DB) UpdateDeliveryLogStatus(ctx context.Context, u entity.UpdateDeliver
 	})
 	return s.mapError(err)
 }
+
+// UpdateTemplateContent reports false when no template matches, or when expected is set
+// and the template changed since that version.
+func (s *DB) UpdateTemplateContent(ctx context.Context, tk entity.TriggerKey, ch entity.Channel, subject, body string, expected valueobject.Version) (_ bool, err error) {
+	ctx, span := s.startSpan(ctx, "UpdateTemplateContent")
+	defer func() { s.endSpan(span, err) }()
+
+	rows, err := s.query.UpdateNotificationTemplateContent(ctx, sqlc.UpdateNotificationTemplateContentParams{
+		Subject:           subject,
+		Body:              body,
+		TriggerKey:        tk.String(),
+		Channel:           ch,
+		ExpectedUpdatedAt: pgtype.Timestamptz{Valid: !expected.IsZero(), Time: expected.Time()},
+	})
+	if err != nil {
+		return false, s.mapError(err)
+	}
+
+	return rows == 1, nil
+}
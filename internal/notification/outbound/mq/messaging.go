@@ -0,0 +1,82 @@
+package mq
+
+import (
+	"context"
+	"maps"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/clock"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/messaging"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type Messaging struct {
+	client messaging.Messaging
+	uuid   uid.StringID
+	clock  clock.Clocker
+	ins    instrument.Instrumentation
+}
+
+func NewMessaging(client messaging.Messaging, uuid uid.StringID, clock clock.Clocker, ins instrument.Instrumentation) *Messaging {
+	return &Messaging{client: client, uuid: uuid, clock: clock, ins: ins}
+}
+
+// PublishNotificationReplied wraps msg in a contracts envelope and sends it to its
+// destination. The caller's carrier rides in the envelope too, since not every broker
+// delivers headers.
+func (m *Messaging) PublishNotificationReplied(ctx context.Context, msg contracts.NotificationReplied) error {
+	ctx, span := m.ins.Tracer("notification.outbound.mq").Start(ctx, "PublishNotificationReplied")
+	defer span.End()
+
+	body, err := contracts.Marshal(m.uuid.Generate(), m.clock.Now(), instrument.CarrierFromContext(ctx).Headers(), msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	if _, err := m.client.Publish(ctx, contracts.NotificationRepliedDestination, messaging.OutgoingMessage{Body: body}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Republish re-injects an archived message into its original destination, marked as a replay.
+func (m *Messaging) Republish(ctx context.Context, msg entity.ArchivedMessage) error {
+	ctx, span := m.ins.Tracer("notification.outbound.mq").Start(ctx, "Republish")
+	defer span.End()
+
+	headers := make([]messaging.Header, 0, len(msg.Headers)+1)
+	for _, h := range msg.Headers {
+		if h.Key == entity.HeaderArchiveReplay {
+			continue
+		}
+		headers = append(headers, messaging.Header{Key: h.Key, Value: h.Value})
+	}
+	headers = append(headers, messaging.Header{Key: entity.HeaderArchiveReplay, Value: []byte(msg.ID)})
+
+	attributes := maps.Clone(msg.Attributes)
+	if attributes == nil {
+		attributes = make(map[string]string, 1)
+	}
+	attributes[entity.HeaderArchiveReplay] = msg.ID
+
+	if _, err := m.client.Publish(ctx, msg.Destination, messaging.OutgoingMessage{
+		Body:       msg.Body,
+		Key:        msg.Key,
+		Headers:    headers,
+		Attributes: attributes,
+	}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package sendgrid
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/router"
+)
+
+const (
+	headerSignature = "X-Twilio-Email-Event-Webhook-Signature"
+	headerTimestamp = "X-Twilio-Email-Event-Webhook-Timestamp"
+)
+
+// Webhook plugs the SendGrid signature into router.Webhook.
+type Webhook struct {
+	verifier  *Verifier
+	publicKey string
+}
+
+// Webhook returns the router.WebhookVerifier of posts signed for publicKey.
+func (v *Verifier) Webhook(publicKey string) *Webhook {
+	return &Webhook{verifier: v, publicKey: publicKey}
+}
+
+// VerifyWebhook checks the ECDSA signature of a SendGrid post. SendGrid sends no delivery ID,
+// so the signature itself tells deliveries apart.
+func (w *Webhook) VerifyWebhook(r *http.Request, body []byte) (router.WebhookDelivery, error) {
+	payload := entity.SendGridSignedPayload{
+		Signature: r.Header.Get(headerSignature),
+		Timestamp: r.Header.Get(headerTimestamp),
+		Body:      body,
+	}
+	if payload.Signature == "" {
+		return router.WebhookDelivery{}, fmt.Errorf("%w: unsigned sendgrid post", router.ErrWebhookSignature)
+	}
+
+	sec, err := strconv.ParseInt(payload.Timestamp, 10, 64)
+	if err != nil {
+		return router.WebhookDelivery{}, fmt.Errorf("%w: invalid sendgrid timestamp", router.ErrWebhookSignature)
+	}
+
+	err = w.verifier.Verify(r.Context(), w.publicKey, payload)
+	if errors.Is(err, entity.ErrSendGridSignatureInvalid) {
+		return router.WebhookDelivery{}, fmt.Errorf("%w: %w", router.ErrWebhookSignature, err)
+	}
+	if err != nil {
+		return router.WebhookDelivery{}, err
+	}
+
+	sum := sha256.Sum256([]byte(payload.Signature))
+	return router.WebhookDelivery{SignedAt: time.Unix(sec, 0), ID: hex.EncodeToString(sum[:])}, nil
+}
@@ -0,0 +1,183 @@
+package sns
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // SNS signature version 1 is SHA1 with RSA
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// maxCertificateBytes bounds a downloaded signing certificate.
+const maxCertificateBytes = 64 << 10
+
+// certHost matches the SNS endpoints of every region and partition.
+//
+//nolint:gochecknoglobals // compiled once
+var certHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// Verifier checks the signature SNS puts on every message it delivers. Signing
+// certificates are downloaded from SNS once and kept in memory.
+type Verifier struct {
+	client *http.Client
+	ins    instrument.Instrumentation
+
+	mu    sync.RWMutex
+	certs map[string]*x509.Certificate
+}
+
+// New creates a verifier that downloads certificates with client.
+func New(client *http.Client, ins instrument.Instrumentation) *Verifier {
+	return &Verifier{client: client, ins: ins, certs: map[string]*x509.Certificate{}}
+}
+
+// Verify checks that msg carries a valid signature from SNS, failing with
+// entity.ErrSNSSignatureInvalid when it does not. Other errors come from downloading the
+// certificate. It does not check which topic the message came from; anyone can sign
+// messages of their own topics.
+func (v *Verifier) Verify(ctx context.Context, msg entity.SNSMessage) (err error) {
+	ctx, span := v.ins.Tracer("notification.outbound.sns").Start(ctx, "Verify")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	var hash crypto.Hash
+	switch msg.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("%w: unsupported signature version %q", entity.ErrSNSSignatureInvalid, msg.SignatureVersion)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %w", entity.ErrSNSSignatureInvalid, err)
+	}
+
+	cert, err := v.certificate(ctx, msg.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("%w: certificate key is not RSA", entity.ErrSNSSignatureInvalid)
+	}
+
+	if err := rsa.VerifyPKCS1v15(key, hash, digest(hash, stringToSign(msg)), signature); err != nil {
+		return fmt.Errorf("%w: %w", entity.ErrSNSSignatureInvalid, err)
+	}
+
+	return nil
+}
+
+func (v *Verifier) certificate(ctx context.Context, rawURL string) (*x509.Certificate, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || !certHost.MatchString(u.Hostname()) || u.Port() != "" || !strings.HasSuffix(u.Path, ".pem") {
+		return nil, fmt.Errorf("%w: untrusted signing certificate url %q", entity.ErrSNSSignatureInvalid, rawURL)
+	}
+
+	v.mu.RLock()
+	cert, ok := v.certs[rawURL]
+	v.mu.RUnlock()
+	if ok {
+		return cert, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sns: download certificate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sns: download certificate: unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCertificateBytes))
+	if err != nil {
+		return nil, fmt.Errorf("sns: download certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, errors.New("sns: certificate is not PEM")
+	}
+
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("sns: parse certificate: %w", err)
+	}
+
+	v.mu.Lock()
+	v.certs[rawURL] = cert
+	v.mu.Unlock()
+
+	return cert, nil
+}
+
+// stringToSign builds the canonical form SNS signs: selected fields as "Name\nvalue\n"
+// pairs in a fixed order, with Subject only when the message has one.
+func stringToSign(msg entity.SNSMessage) string {
+	fields := [][2]string{{"Message", msg.Message}, {"MessageId", msg.MessageID}}
+	switch msg.Type {
+	case "SubscriptionConfirmation", "UnsubscribeConfirmation":
+		fields = append(fields,
+			[2]string{"SubscribeURL", msg.SubscribeURL},
+			[2]string{"Timestamp", msg.Timestamp},
+			[2]string{"Token", msg.Token},
+		)
+	default:
+		if msg.Subject != "" {
+			fields = append(fields, [2]string{"Subject", msg.Subject})
+		}
+		fields = append(fields, [2]string{"Timestamp", msg.Timestamp})
+	}
+	fields = append(fields, [2]string{"TopicArn", msg.TopicArn}, [2]string{"Type", msg.Type})
+
+	var b strings.Builder
+	for _, f := range fields {
+		b.WriteString(f[0])
+		b.WriteByte('\n')
+		b.WriteString(f[1])
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+func digest(hash crypto.Hash, s string) []byte {
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(s)) //nolint:gosec // required by signature version 1
+		return sum[:]
+	}
+
+	sum := sha256.Sum256([]byte(s))
+	return sum[:]
+}
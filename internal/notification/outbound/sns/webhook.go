@@ -0,0 +1,79 @@
+package sns
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/router"
+)
+
+// Webhook plugs the SNS signature into router.Webhook for messages of a set of topics.
+type Webhook struct {
+	verifier  *Verifier
+	topicARNs []string
+}
+
+// Webhook returns the router.WebhookVerifier of messages published to one of topicARNs.
+// Anyone can sign messages of their own topics, so the topic is checked as well.
+func (v *Verifier) Webhook(topicARNs []string) *Webhook {
+	return &Webhook{verifier: v, topicARNs: topicARNs}
+}
+
+type message struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	Token            string `json:"Token"`
+	SubscribeURL     string `json:"SubscribeURL"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+// VerifyWebhook checks the topic and signature of an SNS message. SNS keeps the publish
+// time and message ID on every retry of a delivery.
+func (w *Webhook) VerifyWebhook(r *http.Request, body []byte) (router.WebhookDelivery, error) {
+	var m message
+	if err := json.Unmarshal(body, &m); err != nil {
+		return router.WebhookDelivery{}, fmt.Errorf("%w: %w", router.ErrWebhookSignature, err)
+	}
+
+	if !slices.Contains(w.topicARNs, m.TopicArn) {
+		return router.WebhookDelivery{}, fmt.Errorf("%w: unexpected sns topic %q", router.ErrWebhookSignature, m.TopicArn)
+	}
+
+	signedAt, err := time.Parse(time.RFC3339, m.Timestamp)
+	if err != nil {
+		return router.WebhookDelivery{}, fmt.Errorf("%w: invalid sns timestamp", router.ErrWebhookSignature)
+	}
+
+	err = w.verifier.Verify(r.Context(), entity.SNSMessage{
+		Type:             m.Type,
+		MessageID:        m.MessageID,
+		TopicArn:         m.TopicArn,
+		Subject:          m.Subject,
+		Message:          m.Message,
+		Timestamp:        m.Timestamp,
+		Token:            m.Token,
+		SubscribeURL:     m.SubscribeURL,
+		SignatureVersion: m.SignatureVersion,
+		Signature:        m.Signature,
+		SigningCertURL:   m.SigningCertURL,
+	})
+	if errors.Is(err, entity.ErrSNSSignatureInvalid) {
+		return router.WebhookDelivery{}, fmt.Errorf("%w: %w", router.ErrWebhookSignature, err)
+	}
+	if err != nil {
+		return router.WebhookDelivery{}, err
+	}
+
+	return router.WebhookDelivery{SignedAt: signedAt, ID: m.MessageID}, nil
+}
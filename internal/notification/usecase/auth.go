@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"log/slog"
 
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 )
@@ -15,3 +17,22 @@ func (s *Usecase) requireAuth(ctx context.Context) (*jwt.Claims, error) {
 
 	return clm, nil
 }
+
+func (s *Usecase) requireAuthorized(ctx context.Context, obj, act string) (*jwt.Claims, error) {
+	clm, err := s.requireAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := authz.Enforce(ctx, s.enforcer, s.authzShadow, clm, obj, act)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to check authorization", "user_id", clm.Subject, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	if !ok {
+		return nil, goerror.NewBusiness("account not allowed", goerror.CodeForbidden)
+	}
+
+	return clm, nil
+}
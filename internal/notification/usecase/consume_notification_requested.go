@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+type (
+	ConsumeNotificationRequestedInput struct {
+		UserID     int64    `validate:"required,gt=0"`
+		Email      string   `validate:"omitempty,email"`
+		TriggerKey string   `validate:"required"`
+		Channels   []string `validate:"required,min=1,dive,oneof=in_app email"`
+		Data       map[string]any
+	}
+)
+
+// ConsumeNotificationRequested delivers a registered trigger on the requested channels. It lets
+// other modules send a notification without a dedicated destination and consumer of their own.
+func (s *Usecase) ConsumeNotificationRequested(ctx context.Context, in ConsumeNotificationRequestedInput) error {
+	ctx, span := s.startSpan(ctx, "ConsumeNotificationRequested")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		slog.ErrorContext(ctx, "Validation failed", "error", err)
+		return nil
+	}
+
+	if in.Data == nil {
+		in.Data = map[string]any{}
+	}
+
+	tk := entity.TriggerKey(in.TriggerKey)
+	if _, ok := entity.LookupTrigger(tk); !ok {
+		slog.ErrorContext(ctx, "notification requested for unknown trigger", "trigger_key", in.TriggerKey)
+		return nil
+	}
+
+	for _, channel := range in.Channels {
+		switch entity.ChannelFromString(channel) {
+		case entity.ChannelEmail:
+			if in.Email == "" {
+				slog.ErrorContext(ctx, "notification requested on email without an address", "user_id", in.UserID, "trigger_key", in.TriggerKey)
+				continue
+			}
+
+			data := s.baseEmailTemplateData()
+			for k, v := range in.Data {
+				data[k] = v
+			}
+
+			s.sendEmailNotification(ctx, emailNotificationInput{
+				UserID:           in.UserID,
+				Email:            in.Email,
+				TriggerKey:       tk,
+				TemplateData:     data,
+				NotificationData: valueobject.JSONMap(in.Data),
+			})
+		case entity.ChannelInApp:
+			s.createInAppNotification(ctx, in.UserID, tk, valueobject.JSONMap(in.Data))
+		case entity.ChannelSMS, entity.ChannelPush, entity.ChannelUnknown:
+			slog.WarnContext(ctx, "notification requested on unsupported channel", "channel", channel, "trigger_key", in.TriggerKey)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+type (
+	ConsumePushTokensRejectedInput struct {
+		Tokens []string `validate:"required,min=1"`
+		Reason string
+	}
+)
+
+// ConsumePushTokensRejected removes the device tokens the push provider rejected, so later
+// fan-outs stop targeting them. Tokens that are already gone are ignored.
+func (s *Usecase) ConsumePushTokensRejected(ctx context.Context, in ConsumePushTokensRejectedInput) error {
+	ctx, span := s.startSpan(ctx, "ConsumePushTokensRejected")
+	defer span.End()
+
+	tokens := make([]string, 0, len(in.Tokens))
+	for _, token := range in.Tokens {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	slices.Sort(tokens)
+	in.Tokens = slices.Compact(tokens)
+
+	if err := s.validator.Validate(in); err != nil {
+		slog.ErrorContext(ctx, "Validation failed", "error", err)
+		return nil
+	}
+
+	removed, err := s.repoDB.RemoveUserDevices(ctx, in.Tokens)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo remove rejected device tokens", "tokens", len(in.Tokens), "error", err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "removed rejected device tokens", "reason", in.Reason, "tokens", len(in.Tokens), "removed", removed)
+
+	return nil
+}
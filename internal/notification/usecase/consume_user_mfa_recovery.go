@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+type (
+	ConsumeUserMFARecoveryInput struct {
+		UserID      int64  `validate:"required,gt=0"`
+		Email       string `validate:"required,email"`
+		FullName    string
+		Stage       string `validate:"required,oneof=requested pending completed"`
+		Token       string `validate:"required_if=Stage requested"`
+		AvailableAt time.Time
+	}
+)
+
+// ConsumeUserMFARecovery notifies the account owner at each recovery stage. The pending and
+// completed stages go to every channel so a legitimate owner notices a hostile recovery in time.
+func (s *Usecase) ConsumeUserMFARecovery(ctx context.Context, in ConsumeUserMFARecoveryInput) error {
+	ctx, span := s.startSpan(ctx, "ConsumeUserMFARecovery")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		slog.ErrorContext(ctx, "Validation failed", "error", err)
+		return nil
+	}
+
+	data := s.baseEmailTemplateData()
+	data["full_name"] = in.FullName
+
+	var tk entity.TriggerKey
+	switch in.Stage {
+	case contracts.MFARecoveryStageRequested:
+		tk = entity.TriggerKeyMFARecoveryRequested
+		data["recovery_url"] = s.cfg.GetString("app.web") + "/mfa-recovery?token=" + url.QueryEscape(in.Token)
+
+	case contracts.MFARecoveryStagePending:
+		tk = entity.TriggerKeyMFARecoveryPending
+		data["available_at"] = in.AvailableAt.UTC().Format(time.RFC3339)
+		data["security_url"] = s.cfg.GetString("app.web") + "/settings/security"
+
+	case contracts.MFARecoveryStageCompleted:
+		tk = entity.TriggerKeyMFARecoveryCompleted
+		data["security_url"] = s.cfg.GetString("app.web") + "/settings/security"
+	}
+
+	s.sendEmailNotification(ctx, emailNotificationInput{
+		UserID:       in.UserID,
+		Email:        in.Email,
+		TriggerKey:   tk,
+		TemplateData: data,
+		NotificationData: valueobject.JSONMap{
+			"user_id": in.UserID,
+			"email":   in.Email,
+		},
+	})
+
+	if in.Stage != contracts.MFARecoveryStageRequested {
+		inApp := valueobject.JSONMap{"full_name": in.FullName, "security_url": data["security_url"]}
+		if v, ok := data["available_at"]; ok {
+			inApp["available_at"] = v
+		}
+		s.createInAppNotification(ctx, in.UserID, tk, inApp)
+	}
+
+	return nil
+}
+
+func (s *Usecase) createInAppNotification(ctx context.Context, userID int64, tk entity.TriggerKey, data valueobject.JSONMap) {
+	if !s.validTriggerData(ctx, tk, data) {
+		return
+	}
+
+	tpl := s.getTemplate(ctx, tk, entity.ChannelInApp)
+	if tpl == nil {
+		return
+	}
+
+	n := entity.CreateNotification{
+		ID:         s.uid.Generate(),
+		UserID:     userID,
+		CategoryID: tpl.CategoryID,
+		TriggerKey: tpl.TriggerKey,
+		Data:       data,
+		Metadata:   valueobject.JSONMap{},
+	}
+	if err := s.repoDB.CreateNotification(ctx, n); err != nil {
+		slog.ErrorContext(ctx, "failed to repo create notification", "user_id", userID, "trigger_key", tk, "error", err)
+		return
+	}
+
+	s.publishStreamEvent(n.UserID, s.buildStreamEvent(n))
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+type (
+	ConsumeUserMFARevokedInput struct {
+		UserID   int64  `validate:"required,gt=0"`
+		Email    string `validate:"required,email"`
+		FullName string
+	}
+)
+
+func (s *Usecase) ConsumeUserMFARevoked(ctx context.Context, in ConsumeUserMFARevokedInput) error {
+	ctx, span := s.startSpan(ctx, "ConsumeUserMFARevoked")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		slog.ErrorContext(ctx, "Validation failed", "error", err)
+		return nil
+	}
+
+	data := s.baseEmailTemplateData()
+	data["full_name"] = in.FullName
+	data["security_url"] = s.cfg.GetString("app.web") + "/settings/security"
+
+	s.sendEmailNotification(ctx, emailNotificationInput{
+		UserID:       in.UserID,
+		Email:        in.Email,
+		TriggerKey:   entity.TriggerKeyMFARevoked,
+		TemplateData: data,
+		NotificationData: valueobject.JSONMap{
+			"user_id": in.UserID,
+			"email":   in.Email,
+		},
+	})
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+type (
+	ConsumeUserNewSignInInput struct {
+		UserID     int64  `validate:"required,gt=0"`
+		Email      string `validate:"required,email"`
+		FullName   string
+		IP         string
+		UserAgent  string
+		DeviceName string
+		Country    string
+		SignedInAt time.Time `validate:"required"`
+	}
+)
+
+func (s *Usecase) ConsumeUserNewSignIn(ctx context.Context, in ConsumeUserNewSignInInput) error {
+	ctx, span := s.startSpan(ctx, "ConsumeUserNewSignIn")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		slog.ErrorContext(ctx, "Validation failed", "error", err)
+		return nil
+	}
+
+	// the name the client gave its device reads better than a user agent, which is kept
+	// alongside it since the name is whatever the client chose
+	device := in.UserAgent
+	switch {
+	case in.DeviceName != "" && device != "":
+		device = in.DeviceName + " (" + device + ")"
+	case in.DeviceName != "":
+		device = in.DeviceName
+	case device == "":
+		device = "Unknown device"
+	}
+
+	location := in.Country
+	if location == "" {
+		location = "Unknown location"
+	}
+
+	securityURL := s.cfg.GetString("app.web") + "/settings/security"
+	signedInAt := in.SignedInAt.UTC().Format(time.RFC3339)
+
+	data := s.baseEmailTemplateData()
+	data["full_name"] = in.FullName
+	data["device"] = device
+	data["location"] = location
+	data["ip"] = in.IP
+	data["signed_in_at"] = signedInAt
+	data["security_url"] = securityURL
+
+	s.sendEmailNotification(ctx, emailNotificationInput{
+		UserID:       in.UserID,
+		Email:        in.Email,
+		TriggerKey:   entity.TriggerKeyNewSignIn,
+		TemplateData: data,
+		NotificationData: valueobject.JSONMap{
+			"user_id": in.UserID,
+			"email":   in.Email,
+		},
+	})
+
+	s.createInAppNotification(ctx, in.UserID, entity.TriggerKeyNewSignIn, valueobject.JSONMap{
+		"full_name":    in.FullName,
+		"device":       device,
+		"location":     location,
+		"ip":           in.IP,
+		"signed_in_at": signedInAt,
+		"security_url": securityURL,
+	})
+
+	return nil
+}
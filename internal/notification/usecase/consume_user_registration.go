@@ -7,6 +7,7 @@ import (
 
 	"github.com/shandysiswandi/gobite/internal/notification/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
 )
 
 type (
@@ -27,8 +28,19 @@ func (s *Usecase) ConsumeUserRegistration(ctx context.Context, in ConsumeUserReg
 		return nil
 	}
 
+	verifyURL, err := s.signedURL.Sign(
+		s.cfg.GetString("app.web")+"/verify-email?token="+url.QueryEscape(in.Token),
+		constant.SignedURLPurposeEmailVerify,
+		in.Token,
+		s.cfg.GetHour("signed_url.email_verify_ttl_hours"),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to sign verify url", "user_id", in.UserID, "error", err)
+		return nil
+	}
+
 	data := s.baseEmailTemplateData()
-	data["verify_url"] = s.cfg.GetString("app.web") + "/verify-email?token=" + url.QueryEscape(in.Token)
+	data["verify_url"] = verifyURL
 
 	s.sendEmailNotification(ctx, emailNotificationInput{
 		UserID:       in.UserID,
@@ -47,6 +59,11 @@ func (s *Usecase) ConsumeUserRegistration(ctx context.Context, in ConsumeUserReg
 }
 
 func (s *Usecase) createWelcomeNotification(ctx context.Context, in ConsumeUserRegistrationInput) {
+	data := valueobject.JSONMap{"full_name": in.FullName}
+	if !s.validTriggerData(ctx, entity.TriggerKeyUserWelcome, data) {
+		return
+	}
+
 	tpl := s.getTemplate(ctx, entity.TriggerKeyUserWelcome, entity.ChannelInApp)
 	if tpl == nil {
 		return
@@ -57,7 +74,7 @@ func (s *Usecase) createWelcomeNotification(ctx context.Context, in ConsumeUserR
 		UserID:     in.UserID,
 		CategoryID: tpl.CategoryID,
 		TriggerKey: tpl.TriggerKey,
-		Data:       valueobject.JSONMap{"full_name": in.FullName},
+		Data:       data,
 		Metadata:   valueobject.JSONMap{},
 	}
 	if err := s.repoDB.CreateNotification(ctx, n); err != nil {
@@ -65,5 +82,5 @@ func (s *Usecase) createWelcomeNotification(ctx context.Context, in ConsumeUserR
 		return
 	}
 
-	s.publishNotification(s.buildStreamEvent(n))
+	s.publishStreamEvent(n.UserID, s.buildStreamEvent(n))
 }
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+type (
+	ConsumeUserSessionRevokedInput struct {
+		UserID       int64  `validate:"required,gt=0"`
+		Email        string `validate:"required,email"`
+		FullName     string
+		RevokedCount int64 `validate:"required,gt=0"`
+		Limit        int   `validate:"required,gt=0"`
+	}
+)
+
+func (s *Usecase) ConsumeUserSessionRevoked(ctx context.Context, in ConsumeUserSessionRevokedInput) error {
+	ctx, span := s.startSpan(ctx, "ConsumeUserSessionRevoked")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		slog.ErrorContext(ctx, "Validation failed", "error", err)
+		return nil
+	}
+
+	securityURL := s.cfg.GetString("app.web") + "/settings/security"
+
+	data := s.baseEmailTemplateData()
+	data["full_name"] = in.FullName
+	data["revoked_count"] = in.RevokedCount
+	data["limit"] = in.Limit
+	data["security_url"] = securityURL
+
+	s.sendEmailNotification(ctx, emailNotificationInput{
+		UserID:       in.UserID,
+		Email:        in.Email,
+		TriggerKey:   entity.TriggerKeySessionRevoked,
+		TemplateData: data,
+		NotificationData: valueobject.JSONMap{
+			"user_id": in.UserID,
+			"email":   in.Email,
+		},
+	})
+
+	s.createInAppNotification(ctx, in.UserID, entity.TriggerKeySessionRevoked, valueobject.JSONMap{
+		"full_name":     in.FullName,
+		"revoked_count": in.RevokedCount,
+		"limit":         in.Limit,
+		"security_url":  securityURL,
+	})
+
+	return nil
+}
@@ -19,11 +19,29 @@ type emailNotificationInput struct {
 }
 
 func (s *Usecase) sendEmailNotification(ctx context.Context, in emailNotificationInput) {
+	if !s.validTriggerData(ctx, in.TriggerKey, in.TemplateData) {
+		return
+	}
+
 	tpl := s.getTemplate(ctx, in.TriggerKey, entity.ChannelEmail)
 	if tpl == nil {
 		return
 	}
 
+	notificationID := s.uid.Generate()
+
+	headers := map[string]string{}
+	if replyTo := s.replyAddress(notificationID, in.Email); replyTo != "" {
+		headers["Reply-To"] = replyTo
+	}
+	if category, err := s.findCategory(ctx, tpl.CategoryID); err == nil && !category.IsMandatory {
+		if link := s.unsubscribeURL(ctx, in.UserID, tpl.CategoryID); link != "" {
+			in.TemplateData["unsubscribe_url"] = link
+			headers["List-Unsubscribe"] = "<" + link + ">"
+			headers["List-Unsubscribe-Post"] = "List-Unsubscribe=One-Click"
+		}
+	}
+
 	body, err := s.renderTemplate("body", tpl.Body, in.TemplateData)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to render email body", "user_id", in.UserID, "trigger_key", in.TriggerKey.String(), "error", err)
@@ -31,7 +49,7 @@ func (s *Usecase) sendEmailNotification(ctx context.Context, in emailNotificatio
 	}
 
 	n := entity.CreateNotification{
-		ID:         s.uid.Generate(),
+		ID:         notificationID,
 		UserID:     in.UserID,
 		CategoryID: tpl.CategoryID,
 		TriggerKey: in.TriggerKey,
@@ -55,6 +73,7 @@ func (s *Usecase) sendEmailNotification(ctx context.Context, in emailNotificatio
 		To:       []string{in.Email},
 		Subject:  tpl.Subject,
 		HTMLBody: body,
+		Headers:  headers,
 	})
 	if mailErr == nil {
 		up := entity.UpdateDeliveryLog{
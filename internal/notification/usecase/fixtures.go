@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+)
+
+// SeedFixtures brings the database up to the embedded fixtures and reports the rows that
+// still differ from them. Missing rows are inserted, but rows that exist are never
+// overwritten, so templates edited through the API survive a re-seed. With verify set
+// nothing is written and the drift is only reported.
+func (s *Usecase) SeedFixtures(ctx context.Context, verify bool) ([]entity.FixtureDrift, error) {
+	ctx, span := s.startSpan(ctx, "SeedFixtures")
+	defer span.End()
+
+	f, err := entity.LoadFixtures()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load notification fixtures", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	extra := s.emailTemplateKeys()
+	for _, t := range f.Templates {
+		var keys []string
+		if t.Channel == entity.ChannelEmail {
+			keys = extra
+		}
+		if field, err := entity.LintTemplate(t.TriggerKey, t.Channel, t.Subject, t.Body, keys); err != nil {
+			err = fmt.Errorf("%w: template %s/%s %s: %w", entity.ErrFixtureInvalid, t.TriggerKey, t.Channel, field, err)
+			slog.ErrorContext(ctx, "notification fixture template is broken", "error", err)
+			return nil, goerror.NewServer(err)
+		}
+	}
+
+	if !verify {
+		if err := s.SyncTriggers(ctx); err != nil {
+			return nil, err
+		}
+
+		inserted, err := s.repoDB.SeedFixtures(ctx, *f)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo seed notification fixtures", "error", err)
+			return nil, goerror.NewServer(err)
+		}
+
+		slog.InfoContext(ctx, "notification fixtures seeded", "inserted", inserted)
+	}
+
+	return s.fixtureDrift(ctx, f)
+}
+
+// fixtureDrift compares the database with the fixtures.
+func (s *Usecase) fixtureDrift(ctx context.Context, f *entity.Fixtures) ([]entity.FixtureDrift, error) {
+	categories, err := s.repoDB.ListCategories(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo list notification categories", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	defaults, err := s.repoDB.ListCategoryDefaults(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo list notification category defaults", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	templates, err := s.repoDB.ListTemplates(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo list notification templates", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	categoryMap := make(map[int64]entity.Category, len(categories))
+	for _, c := range categories {
+		categoryMap[c.ID] = c
+	}
+
+	defaultMap := make(map[string]bool, len(defaults))
+	for _, d := range defaults {
+		defaultMap[strconv.FormatInt(d.CategoryID, 10)+"/"+d.Channel.String()] = d.IsEnabled
+	}
+
+	templateMap := make(map[string]entity.Template, len(templates))
+	for _, t := range templates {
+		templateMap[t.TriggerKey.String()+"/"+t.Channel.String()] = t
+	}
+
+	var drift []entity.FixtureDrift
+	for _, want := range f.Categories {
+		got, ok := categoryMap[want.ID]
+		if !ok || got.Name != want.Name || got.Description != want.Description || got.IsMandatory != want.IsMandatory {
+			drift = append(drift, entity.FixtureDrift{Kind: "category", Key: want.Name, Missing: !ok})
+		}
+
+		for _, ch := range slices.Sorted(maps.Keys(want.Channels)) {
+			on := want.Channels[ch]
+			enabled, ok := defaultMap[strconv.FormatInt(want.ID, 10)+"/"+ch.String()]
+			if !ok || enabled != on {
+				drift = append(drift, entity.FixtureDrift{Kind: "category_default", Key: want.Name + "/" + ch.String(), Missing: !ok})
+			}
+		}
+	}
+
+	for _, want := range f.Templates {
+		key := want.TriggerKey.String() + "/" + want.Channel.String()
+		got, ok := templateMap[key]
+		if !ok || got.CategoryID != want.CategoryID || got.Subject != want.Subject || got.Body != want.Body {
+			drift = append(drift, entity.FixtureDrift{Kind: "template", Key: key, Missing: !ok})
+		}
+	}
+
+	return drift, nil
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"log/slog"
+	"strings"
 
 	"github.com/shandysiswandi/gobite/internal/notification/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
@@ -10,6 +11,7 @@ import (
 
 type ListInboxInput struct {
 	Status string `validate:"omitempty,oneof=all unread read"`
+	Search string `validate:"omitempty,max=200"`
 	Limit  int32  `validate:"omitempty,gte=1,lte=100"`
 	Offset int32  `validate:"omitempty,gte=0"`
 }
@@ -34,6 +36,17 @@ func (s *Usecase) ListInbox(ctx context.Context, in ListInboxInput) (_ []entity.
 		return nil, goerror.NewInvalidInput(err)
 	}
 
+	in.Search = strings.TrimSpace(in.Search)
+	if in.Search != "" {
+		items, err := s.repoDB.SearchNotifications(ctx, clm.UserID, entity.NotificationStatus(in.Status), in.Search, in.Limit, in.Offset)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo search notifications", "user_id", clm.UserID, "error", err)
+			return nil, goerror.NewServer(err)
+		}
+
+		return items, nil
+	}
+
 	items, err := s.repoDB.ListNotifications(ctx, clm.UserID, entity.NotificationStatus(in.Status), in.Limit, in.Offset)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to repo list notifications", "user_id", clm.UserID, "error", err)
@@ -6,9 +6,16 @@ import (
 
 	"github.com/shandysiswandi/gobite/internal/notification/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 )
 
-func (s *Usecase) ListSettings(ctx context.Context) (_ []entity.UserSetting, err error) {
+type ListSettingsOutput struct {
+	Settings []entity.UserSetting
+	// Version is the version of the stored settings an UpdateSettings expects.
+	Version valueobject.Version
+}
+
+func (s *Usecase) ListSettings(ctx context.Context) (_ *ListSettingsOutput, err error) {
 	ctx, span := s.startSpan(ctx, "ListSettings")
 	defer span.End()
 
@@ -23,12 +30,26 @@ func (s *Usecase) ListSettings(ctx context.Context) (_ []entity.UserSetting, err
 		return nil, goerror.NewServer(err)
 	}
 
+	defaults, err := s.repoDB.ListCategoryDefaults(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo list notification category defaults", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
 	settings, err := s.repoDB.ListUserSettings(ctx, clm.UserID)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to repo list notification settings", "user_id", clm.UserID, "error", err)
 		return nil, goerror.NewServer(err)
 	}
 
+	defaultMap := make(map[int64]map[entity.Channel]bool, len(categories))
+	for _, d := range defaults {
+		if _, ok := defaultMap[d.CategoryID]; !ok {
+			defaultMap[d.CategoryID] = map[entity.Channel]bool{}
+		}
+		defaultMap[d.CategoryID][d.Channel] = d.IsEnabled
+	}
+
 	settingMap := make(map[int64]map[entity.Channel]bool, len(categories))
 	for _, setting := range settings {
 		ch := setting.Channel
@@ -51,7 +72,11 @@ func (s *Usecase) ListSettings(ctx context.Context) (_ []entity.UserSetting, err
 	items := make([]entity.UserSetting, 0, len(categories)*len(channels))
 	for _, category := range categories {
 		for _, ch := range channels {
+			// the user's own setting wins, then the category default, then on
 			isEnabled := true
+			if v, ok := defaultMap[category.ID][ch]; ok {
+				isEnabled = v
+			}
 			if v, ok := settingMap[category.ID][ch]; ok {
 				isEnabled = v
 			}
@@ -66,5 +91,8 @@ func (s *Usecase) ListSettings(ctx context.Context) (_ []entity.UserSetting, err
 		}
 	}
 
-	return items, nil
+	return &ListSettingsOutput{
+		Settings: items,
+		Version:  entity.SettingsVersion(settings),
+	}, nil
 }
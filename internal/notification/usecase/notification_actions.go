@@ -33,6 +33,8 @@ func (s *Usecase) MarkInboxRead(ctx context.Context, in MarkInboxReadInput) erro
 		return goerror.NewBusiness("inbox notification not found", goerror.CodeNotFound)
 	}
 
+	s.publishRead(ctx, clm.UserID, in.ID)
+
 	return nil
 }
 
@@ -50,6 +52,8 @@ func (s *Usecase) MarkAllInboxRead(ctx context.Context) error {
 		return goerror.NewServer(err)
 	}
 
+	s.publishRead(ctx, clm.UserID, 0)
+
 	return nil
 }
 
@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+const (
+	// providerSendGrid is the only inbound provider that may authenticate with the webhook token.
+	providerSendGrid = "sendgrid"
+	// replyLocalPrefix starts the local part of every reply address.
+	replyLocalPrefix = "reply+"
+	// replySignatureLength is the number of hex characters of the HMAC kept in the address.
+	// Some mail systems lowercase local parts, so the signature is hex rather than base64.
+	replySignatureLength = 20
+	// maxReplyBodyLength bounds the stored reply text.
+	maxReplyBodyLength = 16 * 1024
+)
+
+type ReceiveReplyInput struct {
+	Token string
+	// Verified is set when router.Webhook checked the provider signature of the delivery;
+	// Token is only checked otherwise.
+	Verified   bool
+	Provider   string   `validate:"required,max=32"`
+	MessageID  string   `validate:"required,max=255"`
+	From       string   `validate:"required,max=320"`
+	To         []string `validate:"required,min=1"`
+	Subject    string
+	Text       string
+	HTML       string
+	ReceivedAt time.Time
+}
+
+// ReceiveReply stores an email reply to a notification and publishes it. The notification
+// is found through the signed reply address among the recipients, and the signature also
+// binds the address to the recipient the notification was emailed to, so only mail from
+// that address is taken. Mail that cannot be routed is logged and acknowledged, so
+// providers do not keep retrying it; only server errors are returned for a retry.
+func (s *Usecase) ReceiveReply(ctx context.Context, in ReceiveReplyInput) error {
+	ctx, span := s.startSpan(ctx, "ReceiveReply")
+	defer span.End()
+
+	if err := s.verifyInboundWebhook(ctx, in); err != nil {
+		return err
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	from := in.From
+	if addr, err := mail.ParseAddress(in.From); err == nil {
+		from = addr.Address
+	}
+
+	notificationID := int64(0)
+	for _, to := range in.To {
+		if id, ok := s.parseReplyAddress(to, from); ok {
+			notificationID = id
+			break
+		}
+	}
+	if notificationID == 0 {
+		slog.WarnContext(ctx, "inbound mail has no reply address valid for its sender", "provider", in.Provider, "message_id", in.MessageID, "to", in.To)
+		return nil
+	}
+
+	ref, err := s.repoDB.GetNotificationByID(ctx, notificationID)
+	if errors.Is(err, goerror.ErrNotFound) {
+		slog.WarnContext(ctx, "inbound mail replies to unknown notification", "notification_id", notificationID, "message_id", in.MessageID)
+		return nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get notification by id", "notification_id", notificationID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	body := in.Text
+	if strings.TrimSpace(body) == "" {
+		body = htmlToText(in.HTML)
+	}
+	body = truncateUTF8(stripQuotedReply(body), maxReplyBodyLength)
+
+	receivedAt := in.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = s.clock.Now()
+	}
+
+	reply := entity.Reply{
+		ID:             s.uid.Generate(),
+		NotificationID: ref.ID,
+		UserID:         ref.UserID,
+		Provider:       in.Provider,
+		MessageID:      in.MessageID,
+		FromAddress:    from,
+		Subject:        in.Subject,
+		Body:           body,
+		Metadata:       valueobject.JSONMap{"to": in.To},
+		ReceivedAt:     receivedAt,
+	}
+
+	created, err := s.repoDB.CreateReply(ctx, reply)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo create notification reply", "notification_id", ref.ID, "message_id", in.MessageID, "error", err)
+		return goerror.NewServer(err)
+	}
+	if !created {
+		slog.InfoContext(ctx, "notification reply already received", "provider", in.Provider, "message_id", in.MessageID)
+		return nil
+	}
+
+	if err := s.repoMessaging.PublishNotificationReplied(ctx, contracts.NotificationReplied{
+		ReplyID:        reply.ID,
+		NotificationID: ref.ID,
+		UserID:         ref.UserID,
+		TriggerKey:     ref.TriggerKey.String(),
+		From:           from,
+		Subject:        in.Subject,
+		Body:           body,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to publish notification replied", "reply_id", reply.ID, "notification_id", ref.ID, "error", err)
+	}
+
+	return nil
+}
+
+type ConfirmReplySubscriptionInput struct {
+	Provider string
+	Verified bool
+	SNS      entity.SNSMessage
+}
+
+// ConfirmReplySubscription handles the subscription handshake of providers that deliver
+// through a topic (SES via SNS). The subscription is confirmed by an operator opening
+// SubscribeURL, so the webhook never fetches provider-supplied URLs itself.
+func (s *Usecase) ConfirmReplySubscription(ctx context.Context, in ConfirmReplySubscriptionInput) error {
+	ctx, span := s.startSpan(ctx, "ConfirmReplySubscription")
+	defer span.End()
+
+	if err := s.verifyInboundWebhook(ctx, ReceiveReplyInput{Provider: in.Provider, Verified: in.Verified}); err != nil {
+		return err
+	}
+
+	slog.WarnContext(ctx, "inbound mail subscription awaits confirmation", "provider", in.Provider, "topic_arn", in.SNS.TopicArn, "subscribe_url", in.SNS.SubscribeURL)
+
+	return nil
+}
+
+// verifyInboundWebhook authenticates an inbound mail delivery. Provider signatures, the
+// SNS signature of SES deliveries and the ECDSA signature of SendGrid posts once
+// modules.notification.inbound.sendgrid.verification_key is set, are checked by
+// router.Webhook before the delivery gets here. SendGrid posts fall back to the shared
+// webhook token while no key is configured.
+func (s *Usecase) verifyInboundWebhook(ctx context.Context, in ReceiveReplyInput) error {
+	if !s.cfg.GetBool("modules.notification.inbound.enabled") {
+		return goerror.NewBusiness("inbound mail is not enabled", goerror.CodeNotFound)
+	}
+
+	if in.Verified {
+		return nil
+	}
+
+	provider := in.Provider
+	if provider != providerSendGrid || s.cfg.GetString("modules.notification.inbound.sendgrid.verification_key") != "" {
+		slog.WarnContext(ctx, "unsigned inbound mail delivery", "provider", provider)
+		return goerror.NewBusiness("invalid webhook signature", goerror.CodeUnauthorized)
+	}
+
+	expected := s.cfg.GetString("modules.notification.inbound.webhook_token")
+	if expected == "" || subtle.ConstantTimeCompare([]byte(in.Token), []byte(expected)) != 1 {
+		slog.WarnContext(ctx, "invalid inbound mail webhook token", "provider", provider)
+		return goerror.NewBusiness("invalid webhook token", goerror.CodeUnauthorized)
+	}
+
+	return nil
+}
+
+// replyAddress returns the signed address replies to notificationID, emailed to recipient,
+// are sent to, or an empty string when inbound mail is disabled.
+func (s *Usecase) replyAddress(notificationID int64, recipient string) string {
+	domain := s.cfg.GetString("modules.notification.inbound.domain")
+	if !s.cfg.GetBool("modules.notification.inbound.enabled") || domain == "" {
+		return ""
+	}
+
+	id := strconv.FormatInt(notificationID, 10)
+	return replyLocalPrefix + id + "-" + s.replySignature(id, recipient) + "@" + domain
+}
+
+// parseReplyAddress returns the notification ID of a reply address built by replyAddress
+// for the recipient from. The signature keeps senders from attaching replies to
+// notifications of other users, or to ones forwarded to them.
+func (s *Usecase) parseReplyAddress(raw, from string) (int64, bool) {
+	addr, err := mail.ParseAddress(raw)
+	if err != nil {
+		return 0, false
+	}
+
+	local, domain, ok := strings.Cut(strings.ToLower(addr.Address), "@")
+	if !ok || domain != strings.ToLower(s.cfg.GetString("modules.notification.inbound.domain")) {
+		return 0, false
+	}
+
+	rest, ok := strings.CutPrefix(local, replyLocalPrefix)
+	if !ok {
+		return 0, false
+	}
+
+	id, sig, ok := strings.Cut(rest, "-")
+	if !ok {
+		return 0, false
+	}
+
+	notificationID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || notificationID <= 0 {
+		return 0, false
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(s.replySignature(id, from))) {
+		return 0, false
+	}
+
+	return notificationID, true
+}
+
+func (s *Usecase) replySignature(id, recipient string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.GetString("modules.notification.inbound.secret")))
+	mac.Write([]byte("notification_reply:" + id + ":" + strings.ToLower(strings.TrimSpace(recipient))))
+	return hex.EncodeToString(mac.Sum(nil))[:replySignatureLength]
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a multi-byte character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
+
+// stripQuotedReply drops the quoted original most mail clients append below a reply: lines
+// starting with ">" and everything after an "On ... wrote:" attribution or a "-----Original
+// Message-----" separator.
+func stripQuotedReply(body string) string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "-----Original Message-----") {
+			break
+		}
+		if strings.HasPrefix(trimmed, "On ") && strings.HasSuffix(trimmed, "wrote:") {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// htmlToText is a fallback for replies without a plain text part. It drops tags and keeps
+// line breaks; it is not meant to render HTML faithfully.
+func htmlToText(html string) string {
+	replacer := strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n", "</p>", "\n", "</div>", "\n")
+	html = replacer.Replace(html)
+
+	var b strings.Builder
+	inTag := false
+	for _, r := range html {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+
+	return strings.NewReplacer("&nbsp;", " ", "&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'").Replace(b.String())
+}
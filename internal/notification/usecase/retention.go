@@ -0,0 +1,32 @@
+package usecase
+
+import "github.com/shandysiswandi/gobite/internal/pkg/retention"
+
+// RetentionTargets lists the notification tables cleaned up by the retention scheduler.
+// Deleting a notification also removes its delivery logs. Device tokens are pruned once their
+// device has not registered for the window, so push fan-out skips devices that are gone.
+// Archived messages are pruned by the time they were archived while the archive is enabled.
+func (s *Usecase) RetentionTargets() []retention.Target {
+	targets := []retention.Target{
+		{
+			Table: "notifications",
+			Count: s.repoDB.CountNotificationsBefore,
+			Purge: s.repoDB.DeleteNotificationsBefore,
+		},
+		{
+			Table: "notification_user_devices",
+			Count: s.repoDB.CountUserDevicesInactiveBefore,
+			Purge: s.repoDB.DeleteUserDevicesInactiveBefore,
+		},
+	}
+
+	if s.cfg.GetBool("modules.notification.archive.enabled") {
+		targets = append(targets, retention.Target{
+			Table: "notification_archive",
+			Count: s.repoArchive.CountBefore,
+			Purge: s.repoArchive.DeleteBefore,
+		})
+	}
+
+	return targets
+}
@@ -2,15 +2,31 @@ package usecase
 
 import (
 	"context"
+	"log/slog"
 	"sync/atomic"
 	"time"
 
 	"github.com/shandysiswandi/gobite/internal/notification/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
-// StreamEvent represents a notification update sent over SSE.
+const (
+	// StreamEventNotification is sent when a new notification lands in the inbox.
+	StreamEventNotification = "notification"
+	// StreamEventRead is sent when notifications are marked read from any session.
+	StreamEventRead = "read"
+)
+
+// StreamEvent is an update pushed to every open stream of a user; Name is the SSE event name.
 type StreamEvent struct {
+	Name string
+	Data any
+}
+
+// NotificationEvent is the payload of a StreamEventNotification.
+type NotificationEvent struct {
 	ID         int64               `json:"id"`
 	UserID     int64               `json:"user_id"`
 	CategoryID int64               `json:"category_id"`
@@ -20,14 +36,37 @@ type StreamEvent struct {
 	CreatedAt  time.Time           `json:"created_at"`
 }
 
+// ReadEvent is the payload of a StreamEventRead, letting other sessions sync their unread badge.
+type ReadEvent struct {
+	NotificationID int64     `json:"notification_id,omitempty"`
+	AllRead        bool      `json:"all_read"`
+	UnreadCount    int64     `json:"unread_count"`
+	ReadAt         time.Time `json:"read_at"`
+}
+
+const (
+	// streamPolicyDrop discards events for a subscriber whose buffer is full.
+	streamPolicyDrop = "drop"
+	// streamPolicyClose disconnects a subscriber whose buffer is full, so the client
+	// reconnects and reloads the inbox instead of silently missing events.
+	streamPolicyClose = "close"
+)
+
 type subscriber struct {
 	ch     chan StreamEvent
 	closed atomic.Bool
+	// dropped counts events discarded in a row, reset by every delivered event.
+	dropped atomic.Int64
 }
 
-// StreamNotifications registers a stream for a user and closes it when ctx is done.
+// StreamNotifications registers a stream for a user and closes it when ctx is done, or
+// earlier when the slow-client policy disconnects it.
 func (s *Usecase) StreamNotifications(ctx context.Context, userID int64) <-chan StreamEvent {
-	sub := &subscriber{ch: make(chan StreamEvent, 10)}
+	size := s.cfg.GetInt("modules.notification.stream.buffer_size")
+	if size <= 0 {
+		size = 32
+	}
+	sub := &subscriber{ch: make(chan StreamEvent, size)}
 
 	s.streamMu.Lock()
 	if s.streams[userID] == nil {
@@ -36,47 +75,132 @@ func (s *Usecase) StreamNotifications(ctx context.Context, userID int64) <-chan
 	s.streams[userID][sub] = struct{}{}
 	s.streamMu.Unlock()
 
+	s.streamGauge(ctx, 1)
+
 	go func() {
 		<-ctx.Done()
-		s.streamMu.Lock()
-		if subs := s.streams[userID]; subs != nil {
-			delete(subs, sub)
-			if len(subs) == 0 {
-				delete(s.streams, userID)
-			}
-		}
-		s.streamMu.Unlock()
-		close(sub.ch)
+		s.unsubscribe(userID, sub)
+		s.streamGauge(context.WithoutCancel(ctx), -1)
 	}()
 
 	return sub.ch
 }
 
-func (s *Usecase) publishNotification(evt StreamEvent) {
-	s.streamMu.RLock()
-	subs := s.streams[evt.UserID]
-	s.streamMu.RUnlock()
+// unsubscribe removes sub and closes its channel, once. Publishers send while holding the
+// read lock, so closing under the write lock never races a send.
+func (s *Usecase) unsubscribe(userID int64, sub *subscriber) {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+
+	if !sub.closed.CompareAndSwap(false, true) {
+		return
+	}
+
+	if subs := s.streams[userID]; subs != nil {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(s.streams, userID)
+		}
+	}
+	close(sub.ch)
+}
+
+// publishStreamEvent fans evt out to every open stream of the user without blocking: a
+// subscriber with a full buffer is handled by modules.notification.stream.slow_policy, so one
+// stalled client never holds up the others or the consumer that published the event.
+func (s *Usecase) publishStreamEvent(userID int64, evt StreamEvent) {
+	policy := s.cfg.GetString("modules.notification.stream.slow_policy")
+	maxDrops := int64(s.cfg.GetInt("modules.notification.stream.max_drops"))
 
-	for sub := range subs {
+	var (
+		dropped int64
+		slow    []*subscriber
+	)
+
+	s.streamMu.RLock()
+	for sub := range s.streams[userID] {
 		if sub.closed.Load() {
 			continue
 		}
 
 		select {
 		case sub.ch <- evt:
+			sub.dropped.Store(0)
 		default:
+			dropped++
+			if n := sub.dropped.Add(1); policy == streamPolicyClose || (maxDrops > 0 && n >= maxDrops) {
+				slow = append(slow, sub)
+			}
 		}
 	}
+	s.streamMu.RUnlock()
+
+	if dropped == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	s.streamMetric(ctx, "notification.stream.dropped", "Number of stream events dropped because the client buffer was full", dropped, evt.Name)
+
+	for _, sub := range slow {
+		slog.WarnContext(ctx, "disconnecting slow notification stream", "user_id", userID, "dropped", sub.dropped.Load())
+		s.unsubscribe(userID, sub)
+	}
+	if len(slow) > 0 {
+		s.streamMetric(ctx, "notification.stream.disconnected", "Number of streams disconnected for being too slow", int64(len(slow)), evt.Name)
+	}
+}
+
+// streamMetric adds n to the stream counter name, labelled with the event that overflowed.
+func (s *Usecase) streamMetric(ctx context.Context, name, description string, n int64, event string) {
+	counter, err := s.ins.Meter("notification.usecase").Int64Counter(name, metric.WithDescription(description))
+	if err != nil {
+		return
+	}
+
+	counter.Add(ctx, n, metric.WithAttributes(attribute.String("event", event)))
+}
+
+// streamGauge tracks the number of open streams.
+func (s *Usecase) streamGauge(ctx context.Context, delta int64) {
+	gauge, err := s.ins.Meter("notification.usecase").Int64UpDownCounter("notification.stream.subscribers", metric.WithDescription("Number of open notification streams"))
+	if err != nil {
+		return
+	}
+
+	gauge.Add(ctx, delta)
 }
 
 func (s *Usecase) buildStreamEvent(n entity.CreateNotification) StreamEvent {
 	return StreamEvent{
-		ID:         n.ID,
-		UserID:     n.UserID,
-		CategoryID: n.CategoryID,
-		TriggerKey: n.TriggerKey,
-		Data:       n.Data,
-		Metadata:   n.Metadata,
-		CreatedAt:  s.clock.Now(),
+		Name: StreamEventNotification,
+		Data: NotificationEvent{
+			ID:         n.ID,
+			UserID:     n.UserID,
+			CategoryID: n.CategoryID,
+			TriggerKey: n.TriggerKey,
+			Data:       n.Data,
+			Metadata:   n.Metadata,
+			CreatedAt:  s.clock.Now(),
+		},
 	}
 }
+
+// publishRead tells the user's other sessions about a read, with the fresh unread count.
+func (s *Usecase) publishRead(ctx context.Context, userID, notificationID int64) {
+	unread, err := s.repoDB.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo count unread notifications", "user_id", userID, "error", err)
+		return
+	}
+
+	s.publishStreamEvent(userID, StreamEvent{
+		Name: StreamEventRead,
+		Data: ReadEvent{
+			NotificationID: notificationID,
+			AllRead:        notificationID == 0,
+			UnreadCount:    unread,
+			ReadAt:         s.clock.Now(),
+		},
+	})
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type TemplateSaveInput struct {
+	TriggerKey string `validate:"required,max=100"`
+	Channel    string `validate:"required,lowercase,oneof=in_app email"`
+	Subject    string `validate:"required,max=255"`
+	Body       string `validate:"required"`
+	// ExpectedVersion, when set, refuses the save if the template changed since that version.
+	ExpectedVersion valueobject.Version
+}
+
+type TemplateDetailInput struct {
+	TriggerKey string `validate:"required,max=100"`
+	Channel    string `validate:"required,lowercase,oneof=in_app email"`
+}
+
+// TemplateSave replaces the subject and body of a trigger's template on one channel.
+// The placeholders are checked against the trigger's registered data first, so a
+// template that would render broken is rejected here and not found at send time.
+func (s *Usecase) TemplateSave(ctx context.Context, in TemplateSaveInput) error {
+	ctx, span := s.startSpan(ctx, "TemplateSave")
+	defer span.End()
+
+	clm, err := s.requireAuthorized(ctx, constant.PermNotificationMgmtTemplates, constant.PermActUpdate)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	key := entity.TriggerKey(in.TriggerKey)
+	ch := entity.ChannelFromString(in.Channel)
+
+	var extra []string
+	if ch == entity.ChannelEmail {
+		extra = s.emailTemplateKeys()
+	}
+
+	field, err := entity.LintTemplate(key, ch, in.Subject, in.Body, extra)
+	if errors.Is(err, entity.ErrTriggerUnknown) {
+		return goerror.NewBusiness("notification trigger not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		return goerror.NewInvalidInput(nil, field, err.Error())
+	}
+
+	ok, err := s.repoDB.UpdateTemplateContent(ctx, key, ch, in.Subject, in.Body, in.ExpectedVersion)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo update template content", "trigger_key", in.TriggerKey, "channel", in.Channel, "error", err)
+		return goerror.NewServer(err)
+	}
+	if !ok && !in.ExpectedVersion.IsZero() {
+		// nothing matched: the template is missing or was changed meanwhile
+		_, err = s.repoDB.GetTemplateByTriggerChannel(ctx, key, ch)
+		if err == nil {
+			return goerror.NewVersionConflict("notification template")
+		}
+		if !errors.Is(err, goerror.ErrNotFound) {
+			slog.ErrorContext(ctx, "failed to repo get template", "trigger_key", in.TriggerKey, "channel", in.Channel, "error", err)
+			return goerror.NewServer(err)
+		}
+	}
+	if !ok {
+		return goerror.NewBusiness("notification template not found", goerror.CodeNotFound)
+	}
+
+	slog.InfoContext(ctx, "notification template saved", "user_id", clm.UserID, "trigger_key", in.TriggerKey, "channel", in.Channel)
+
+	return nil
+}
+
+// TemplateDetail returns a trigger's template on one channel, with the version a
+// TemplateSave of it expects.
+func (s *Usecase) TemplateDetail(ctx context.Context, in TemplateDetailInput) (*entity.Template, error) {
+	ctx, span := s.startSpan(ctx, "TemplateDetail")
+	defer span.End()
+
+	if _, err := s.requireAuthorized(ctx, constant.PermNotificationMgmtTemplates, constant.PermActRead); err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(in); err != nil {
+		return nil, goerror.NewInvalidInput(err)
+	}
+
+	tpl, err := s.repoDB.GetTemplateByTriggerChannel(ctx, entity.TriggerKey(in.TriggerKey), entity.ChannelFromString(in.Channel))
+	if errors.Is(err, goerror.ErrNotFound) {
+		return nil, goerror.NewBusiness("notification template not found", goerror.CodeNotFound)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo get template", "trigger_key", in.TriggerKey, "channel", in.Channel, "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	return tpl, nil
+}
+
+// emailTemplateKeys lists the data keys every email render adds besides the trigger's own.
+func (s *Usecase) emailTemplateKeys() []string {
+	base := s.baseEmailTemplateData()
+	keys := make([]string, 0, len(base)+1)
+	for k := range base {
+		keys = append(keys, k)
+	}
+
+	return append(keys, "unsubscribe_url")
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+)
+
+// SyncTriggers writes the code-defined trigger registry to the database.
+func (s *Usecase) SyncTriggers(ctx context.Context) error {
+	ctx, span := s.startSpan(ctx, "SyncTriggers")
+	defer span.End()
+
+	if err := s.repoDB.SyncTriggers(ctx, entity.Triggers()); err != nil {
+		slog.ErrorContext(ctx, "failed to repo sync notification triggers", "error", err)
+		return goerror.NewServer(err)
+	}
+
+	return nil
+}
+
+func (s *Usecase) ListTriggers(ctx context.Context) ([]entity.Trigger, error) {
+	_, span := s.startSpan(ctx, "ListTriggers")
+	defer span.End()
+
+	if _, err := s.requireAuth(ctx); err != nil {
+		return nil, err
+	}
+
+	return entity.Triggers(), nil
+}
+
+// validTriggerData reports whether data matches the trigger schema, logging why when it does not.
+func (s *Usecase) validTriggerData(ctx context.Context, key entity.TriggerKey, data map[string]any) bool {
+	if err := entity.ValidateTriggerData(key, data); err != nil {
+		slog.ErrorContext(ctx, "notification data does not match trigger schema", "trigger_key", key.String(), "error", err)
+		return false
+	}
+
+	return true
+}
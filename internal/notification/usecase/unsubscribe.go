@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strconv"
+
+	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/signedurl"
+	"github.com/shandysiswandi/gobite/internal/shared/constant"
+)
+
+type UnsubscribeInput struct {
+	UserID     int64  `validate:"required,gt=0"`
+	CategoryID int64  `validate:"required,gt=0"`
+	Expires    string `validate:"required"`
+	Signature  string `validate:"required"`
+}
+
+// Unsubscribe disables the email channel of a category for the user encoded
+// in a signed unsubscribe link. It requires no session so mail clients can
+// perform one-click unsubscribe (RFC 8058).
+func (s *Usecase) Unsubscribe(ctx context.Context, in UnsubscribeInput) error {
+	ctx, span := s.startSpan(ctx, "Unsubscribe")
+	defer span.End()
+
+	if err := s.validator.Validate(in); err != nil {
+		return goerror.NewInvalidInput(err)
+	}
+
+	query := url.Values{signedurl.ParamExpires: {in.Expires}, signedurl.ParamSignature: {in.Signature}}
+	err := s.signedURL.Verify(query, constant.SignedURLPurposeUnsubscribe, unsubscribeResource(in.UserID, in.CategoryID))
+	if errors.Is(err, signedurl.ErrExpired) {
+		return goerror.NewBusiness("unsubscribe link has expired", goerror.CodeUnauthorized)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "invalid unsubscribe link signature", "user_id", in.UserID, "category_id", in.CategoryID, "error", err)
+		return goerror.NewBusiness("invalid unsubscribe link", goerror.CodeUnauthorized)
+	}
+
+	category, err := s.findCategory(ctx, in.CategoryID)
+	if err != nil {
+		return err
+	}
+	if category.IsMandatory {
+		return goerror.NewBusiness("mandatory category cannot be disabled", goerror.CodeInvalidFormat)
+	}
+
+	settings := []entity.UserSetting{{
+		CategoryID: in.CategoryID,
+		Channel:    entity.ChannelEmail,
+		IsEnabled:  false,
+	}}
+	if err := s.repoDB.UpsertUserSettings(ctx, in.UserID, settings, 0); err != nil {
+		slog.ErrorContext(ctx, "failed to repo upsert notification settings", "user_id", in.UserID, "error", err)
+		return goerror.NewServer(err)
+	}
+
+	return nil
+}
+
+// unsubscribeURL builds a signed one-click unsubscribe link for the given user
+// and category. An empty string is returned when signing fails.
+func (s *Usecase) unsubscribeURL(ctx context.Context, userID, categoryID int64) string {
+	query := url.Values{}
+	query.Set("user_id", strconv.FormatInt(userID, 10))
+	query.Set("category_id", strconv.FormatInt(categoryID, 10))
+
+	link, err := s.signedURL.Sign(
+		s.cfg.GetString("modules.notification.unsubscribe.url")+"?"+query.Encode(),
+		constant.SignedURLPurposeUnsubscribe,
+		unsubscribeResource(userID, categoryID),
+		s.cfg.GetDay("signed_url.unsubscribe_ttl_days"),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to sign unsubscribe url", "user_id", userID, "category_id", categoryID, "error", err)
+		return ""
+	}
+
+	return link
+}
+
+func (s *Usecase) findCategory(ctx context.Context, categoryID int64) (*entity.Category, error) {
+	categories, err := s.repoDB.ListCategories(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to repo list notification categories", "error", err)
+		return nil, goerror.NewServer(err)
+	}
+
+	for _, category := range categories {
+		if category.ID == categoryID {
+			return &category, nil
+		}
+	}
+
+	return nil, goerror.NewBusiness("category not found", goerror.CodeNotFound)
+}
+
+func unsubscribeResource(userID, categoryID int64) string {
+	return strconv.FormatInt(userID, 10) + ":" + strconv.FormatInt(categoryID, 10)
+}
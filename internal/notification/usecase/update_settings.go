@@ -2,15 +2,19 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"strconv"
 
 	"github.com/shandysiswandi/gobite/internal/notification/entity"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 )
 
 type UpdateSettingsInput struct {
 	Settings []UpdateSettingInput `validate:"required,min=1,dive"`
+	// ExpectedVersion, when set, refuses the update if the settings changed since that version.
+	ExpectedVersion valueobject.Version
 }
 
 type UpdateSettingInput struct {
@@ -66,7 +70,11 @@ func (s *Usecase) UpdateSettings(ctx context.Context, in UpdateSettingsInput) er
 		})
 	}
 
-	if err := s.repoDB.UpsertUserSettings(ctx, clm.UserID, settings); err != nil {
+	err = s.repoDB.UpsertUserSettings(ctx, clm.UserID, settings, in.ExpectedVersion)
+	if errors.Is(err, goerror.ErrVersionMismatch) {
+		return goerror.NewVersionConflict("notification settings")
+	}
+	if err != nil {
 		slog.ErrorContext(ctx, "failed to repo upsert notification settings", "user_id", clm.UserID, "error", err)
 		return goerror.NewServer(err)
 	}
@@ -7,60 +7,105 @@ import (
 	"html/template"
 	"log/slog"
 	"sync"
+	"time"
 
+	"github.com/casbin/casbin/v3"
+
+	"github.com/shandysiswandi/gobite/internal/contracts"
 	"github.com/shandysiswandi/gobite/internal/notification/entity"
+	"github.com/shandysiswandi/gobite/internal/pkg/authz"
 	"github.com/shandysiswandi/gobite/internal/pkg/clock"
 	"github.com/shandysiswandi/gobite/internal/pkg/config"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 	"github.com/shandysiswandi/gobite/internal/pkg/mail"
+	"github.com/shandysiswandi/gobite/internal/pkg/signedurl"
 	"github.com/shandysiswandi/gobite/internal/pkg/uid"
 	"github.com/shandysiswandi/gobite/internal/pkg/validator"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type repoDB interface {
 	RegisterUserDevice(ctx context.Context, userID int64, deviceToken, platform string) error
 	RemoveUserDevice(ctx context.Context, deviceToken string) error
+	RemoveUserDevices(ctx context.Context, deviceTokens []string) (int64, error)
+	CountUserDevicesInactiveBefore(ctx context.Context, before time.Time) (int64, error)
+	DeleteUserDevicesInactiveBefore(ctx context.Context, before time.Time, limit int32) (int64, error)
 
 	GetTemplateByTriggerChannel(ctx context.Context, tk entity.TriggerKey, ch entity.Channel) (*entity.Template, error)
+	UpdateTemplateContent(ctx context.Context, tk entity.TriggerKey, ch entity.Channel, subject, body string, expected valueobject.Version) (bool, error)
+	SyncTriggers(ctx context.Context, triggers []entity.Trigger) error
+	ListTemplates(ctx context.Context) ([]entity.Template, error)
+	SeedFixtures(ctx context.Context, f entity.Fixtures) (int64, error)
 	CreateNotification(ctx context.Context, data entity.CreateNotification) error
 	CreateNotificationWithDeliveryLog(ctx context.Context, n entity.CreateNotification, dl entity.CreateDeliveryLog) (int64, error)
+	GetNotificationByID(ctx context.Context, id int64) (*entity.NotificationRef, error)
+	CreateReply(ctx context.Context, r entity.Reply) (bool, error)
 	UpdateDeliveryLogStatus(ctx context.Context, u entity.UpdateDeliveryLog) error
 
 	ListCategories(ctx context.Context) ([]entity.Category, error)
+	ListCategoryDefaults(ctx context.Context) ([]entity.UserSetting, error)
 	ListUserSettings(ctx context.Context, userID int64) ([]entity.UserSetting, error)
-	UpsertUserSettings(ctx context.Context, userID int64, settings []entity.UserSetting) error
+	UpsertUserSettings(ctx context.Context, userID int64, settings []entity.UserSetting, expected valueobject.Version) error
 	ListNotifications(ctx context.Context, userID int64, status entity.NotificationStatus, limit, offset int32) ([]entity.NotificationItem, error)
+	SearchNotifications(ctx context.Context, userID int64, status entity.NotificationStatus, query string, limit, offset int32) ([]entity.NotificationItem, error)
 	CountUnreadNotifications(ctx context.Context, userID int64) (int64, error)
 	MarkNotificationRead(ctx context.Context, userID, notificationID int64) (bool, error)
 	MarkNotificationsReadAll(ctx context.Context, userID int64) (int64, error)
 	SoftDeleteNotification(ctx context.Context, userID, notificationID int64) (bool, error)
+	CountNotificationsBefore(ctx context.Context, before time.Time) (int64, error)
+	DeleteNotificationsBefore(ctx context.Context, before time.Time, limit int32) (int64, error)
+
+	ListNotificationsForExport(ctx context.Context, userID, afterID int64, limit int32) ([]entity.NotificationExport, error)
+	DeleteUserData(ctx context.Context, userID int64) error
+}
+
+type repoArchive interface {
+	Put(ctx context.Context, msg entity.ArchivedMessage) error
+	List(ctx context.Context, destination string, from, to time.Time) ([]entity.ArchivedMessage, error)
+	CountBefore(ctx context.Context, before time.Time) (int64, error)
+	DeleteBefore(ctx context.Context, before time.Time, limit int32) (int64, error)
+}
+
+type repoMessaging interface {
+	Republish(ctx context.Context, msg entity.ArchivedMessage) error
+	PublishNotificationReplied(ctx context.Context, msg contracts.NotificationReplied) error
 }
 
 type Usecase struct {
-	repoDB    repoDB
-	cfg       config.Config
-	uid       uid.NumberID
-	clock     clock.Clocker
-	validator validator.Validator
-	jwt       jwt.JWT
-	repoMail  repoMail
-	ins       instrument.Instrumentation
-	streamMu  sync.RWMutex
-	streams   map[int64]map[*subscriber]struct{}
+	repoDB        repoDB
+	cfg           config.Config
+	uid           uid.NumberID
+	clock         clock.Clocker
+	validator     validator.Validator
+	jwt           jwt.JWT
+	repoMail      repoMail
+	signedURL     signedurl.Signer
+	ins           instrument.Instrumentation
+	enforcer      *casbin.Enforcer
+	authzShadow   *authz.Shadow
+	repoArchive   repoArchive
+	repoMessaging repoMessaging
+	streamMu      sync.RWMutex
+	streams       map[int64]map[*subscriber]struct{}
 }
 
 type Dependency struct {
-	RepoDB     repoDB
-	Config     config.Config
-	UID        uid.NumberID
-	Clock      clock.Clocker
-	Validator  validator.Validator
-	JWT        jwt.JWT
-	RepoMail   repoMail
-	Instrument instrument.Instrumentation
+	RepoDB        repoDB
+	Config        config.Config
+	UID           uid.NumberID
+	Clock         clock.Clocker
+	Validator     validator.Validator
+	JWT           jwt.JWT
+	RepoMail      repoMail
+	SignedURL     signedurl.Signer
+	Instrument    instrument.Instrumentation
+	Enforcer      *casbin.Enforcer
+	AuthzShadow   *authz.Shadow
+	RepoArchive   repoArchive
+	RepoMessaging repoMessaging
 }
 
 type repoMail interface {
@@ -69,15 +114,20 @@ type repoMail interface {
 
 func NewNotification(dep Dependency) *Usecase {
 	return &Usecase{
-		repoDB:    dep.RepoDB,
-		cfg:       dep.Config,
-		uid:       dep.UID,
-		clock:     dep.Clock,
-		validator: dep.Validator,
-		jwt:       dep.JWT,
-		repoMail:  dep.RepoMail,
-		ins:       dep.Instrument,
-		streams:   make(map[int64]map[*subscriber]struct{}),
+		repoDB:        dep.RepoDB,
+		cfg:           dep.Config,
+		uid:           dep.UID,
+		clock:         dep.Clock,
+		validator:     dep.Validator,
+		jwt:           dep.JWT,
+		repoMail:      dep.RepoMail,
+		signedURL:     dep.SignedURL,
+		ins:           dep.Instrument,
+		enforcer:      dep.Enforcer,
+		authzShadow:   dep.AuthzShadow,
+		repoArchive:   dep.RepoArchive,
+		repoMessaging: dep.RepoMessaging,
+		streams:       make(map[int64]map[*subscriber]struct{}),
 	}
 }
 
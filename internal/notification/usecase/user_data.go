@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/userdata"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+const userDataExportPageSize int32 = 1_000
+
+type userDataNotification struct {
+	ID         int64               `json:"id,string"`
+	CategoryID int64               `json:"category_id,string"`
+	TriggerKey string              `json:"trigger_key"`
+	Data       valueobject.JSONMap `json:"data"`
+	Metadata   valueobject.JSONMap `json:"metadata"`
+	ReadAt     *time.Time          `json:"read_at"`
+	DeletedAt  *time.Time          `json:"deleted_at"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// UserDataSources lists the personal data the notification module adds to a user's data
+// export and removes when the account is erased.
+func (s *Usecase) UserDataSources() []userdata.Source {
+	return []userdata.Source{
+		{
+			Name:   "notifications",
+			Export: s.exportUserNotifications,
+			Erase:  s.eraseUserNotifications,
+		},
+	}
+}
+
+func (s *Usecase) exportUserNotifications(ctx context.Context, userID int64) (any, error) {
+	ctx, span := s.startSpan(ctx, "exportUserNotifications")
+	defer span.End()
+
+	out := []userDataNotification{}
+	afterID := int64(0)
+
+	for {
+		page, err := s.repoDB.ListNotificationsForExport(ctx, userID, afterID, userDataExportPageSize)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to repo list notifications for export", "user_id", userID, "error", err)
+			return nil, err
+		}
+
+		for _, n := range page {
+			out = append(out, userDataNotification{
+				ID:         n.ID,
+				CategoryID: n.CategoryID,
+				TriggerKey: n.TriggerKey.String(),
+				Data:       n.Data,
+				Metadata:   n.Metadata,
+				ReadAt:     n.ReadAt,
+				DeletedAt:  n.DeletedAt,
+				CreatedAt:  n.CreatedAt,
+			})
+		}
+
+		if int32(len(page)) < userDataExportPageSize {
+			return out, nil
+		}
+
+		afterID = page[len(page)-1].ID
+	}
+}
+
+func (s *Usecase) eraseUserNotifications(ctx context.Context, userID int64) error {
+	ctx, span := s.startSpan(ctx, "eraseUserNotifications")
+	defer span.End()
+
+	if err := s.repoDB.DeleteUserData(ctx, userID); err != nil {
+		slog.ErrorContext(ctx, "failed to repo delete user notification data", "user_id", userID, "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+// Package authz adds a shadow mode on top of Casbin authorization.
+//
+// A Shadow holds a candidate policy set loaded next to the active one. Every
+// decision the active enforcer makes is replayed against the candidate and the
+// requests where the two disagree are logged and counted, but only the active
+// decision is enforced. This lets an RBAC change be validated on production
+// traffic before it is copied into the active policy table.
+package authz
@@ -0,0 +1,91 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/casbin/casbin/v3"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Shadow evaluates a candidate policy set without enforcing it.
+//
+// A nil *Shadow is valid and does nothing, so callers can observe decisions
+// unconditionally whether or not shadow mode is enabled.
+type Shadow struct {
+	candidate *casbin.SyncedEnforcer
+	ins       instrument.Instrumentation
+}
+
+// NewShadow wraps candidate, which should use the same model as the active enforcer.
+func NewShadow(candidate *casbin.SyncedEnforcer, ins instrument.Instrumentation) *Shadow {
+	return &Shadow{candidate: candidate, ins: ins}
+}
+
+// Observe evaluates the candidate policy for the request the active enforcer just decided
+// and reports a divergence when the decisions differ. It never changes the active decision.
+func (s *Shadow) Observe(ctx context.Context, active bool, sub, obj, act string) {
+	if s == nil || s.candidate == nil {
+		return
+	}
+
+	candidate, err := s.candidate.Enforce(sub, obj, act)
+	s.compare(ctx, active, candidate, err, obj, act, "sub", sub)
+}
+
+// ObserveInDomain is Observe for a request decided inside dom with the sections ec selects.
+func (s *Shadow) ObserveInDomain(ctx context.Context, active bool, ec casbin.EnforceContext, sub, dom, obj, act string) {
+	if s == nil || s.candidate == nil {
+		return
+	}
+
+	candidate, err := s.candidate.Enforce(ec, sub, dom, obj, act)
+	s.compare(ctx, active, candidate, err, obj, act, "sub", sub, "dom", dom)
+}
+
+// compare logs and counts a candidate decision that differs from the active one. args
+// identify the request in the logs.
+func (s *Shadow) compare(ctx context.Context, active, candidate bool, err error, obj, act string, args ...any) {
+	args = append(args, "obj", obj, "act", act)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to evaluate shadow authorization", append(args, "error", err)...)
+		return
+	}
+
+	if candidate == active {
+		return
+	}
+
+	slog.WarnContext(ctx, "shadow authorization diverged", append(args, "active", active, "candidate", candidate)...)
+
+	counter, err := s.ins.Meter("authz.shadow").Int64Counter("authz.shadow.divergences",
+		metric.WithDescription("Number of authorization decisions where the candidate policy disagrees with the active one"))
+	if err != nil {
+		return
+	}
+
+	counter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("obj", obj),
+		attribute.String("act", act),
+		attribute.String("active", strconv.FormatBool(active)),
+		attribute.String("candidate", strconv.FormatBool(candidate)),
+	))
+}
+
+// Reload re-reads the candidate policy, so edits to the shadow policy table are picked up
+// without a restart.
+func (s *Shadow) Reload(context.Context) error {
+	if s == nil || s.candidate == nil {
+		return nil
+	}
+
+	if err := s.candidate.LoadPolicy(); err != nil {
+		return fmt.Errorf("reload shadow authorization policy: %w", err)
+	}
+
+	return nil
+}
@@ -2,5 +2,10 @@
 //
 // Production code should depend on the Clocker interface instead of calling
 // time.Now() directly. This makes business logic easier to test because you can
-// swap in a fake clock that returns a deterministic time.
+// swap in a Fake clock that returns a deterministic time.
+//
+// The package also carries the calendar helpers used to schedule work in a
+// user's time zone: the next occurrence of a local wall clock time, DST-safe
+// day arithmetic, business days, and windows such as quiet hours that may wrap
+// past midnight.
 package clock
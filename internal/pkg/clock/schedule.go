@@ -0,0 +1,156 @@
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTimeOfDay is returned when a wall clock time cannot be parsed.
+var ErrInvalidTimeOfDay = errors.New("clock: invalid time of day")
+
+// TimeOfDay is a wall clock time without a date, such as the start of quiet hours.
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+}
+
+// ParseTimeOfDay parses "HH:MM" in 24-hour form.
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return TimeOfDay{}, fmt.Errorf("%w: %q", ErrInvalidTimeOfDay, s)
+	}
+
+	return TimeOfDay{Hour: t.Hour(), Minute: t.Minute()}, nil
+}
+
+// String formats the time as "HH:MM".
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+}
+
+func (t TimeOfDay) minutes() int {
+	return t.Hour*60 + t.Minute
+}
+
+// On returns the instant t happens on the calendar day of day in loc.
+func (t TimeOfDay) On(day time.Time, loc *time.Location) time.Time {
+	y, m, d := day.In(loc).Date()
+	return date(y, m, d, t.Hour, t.Minute, 0, 0, loc)
+}
+
+// Location loads the IANA zone name, falling back to UTC when it is empty or unknown,
+// so a bad user preference never breaks scheduling.
+func Location(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// NextAt returns the first instant strictly after now when the wall clock in loc shows at.
+//
+// Days are stepped on the calendar rather than by 24 hours, so the result keeps the same
+// local time across DST changes. When at falls in a spring-forward gap, that day's
+// occurrence is moved forward by the length of the gap, so 02:30 becomes 03:30.
+func NextAt(now time.Time, loc *time.Location, at TimeOfDay) time.Time {
+	next := at.On(now, loc)
+	for !next.After(now) {
+		next = AddDays(next, 1, loc)
+	}
+
+	return next
+}
+
+// AddDays moves t by n calendar days in loc, keeping its local wall clock time.
+// Unlike t.Add(n * 24 * time.Hour), the result does not drift by an hour across DST.
+func AddDays(t time.Time, n int, loc *time.Location) time.Time {
+	lt := t.In(loc)
+	y, m, d := lt.Date()
+
+	return date(y, m, d+n, lt.Hour(), lt.Minute(), lt.Second(), lt.Nanosecond(), loc)
+}
+
+// date is time.Date, except that a wall clock time skipped by a spring-forward gap is moved
+// forward by the length of the gap instead of resolving before it, so schedules never run early.
+func date(y int, m time.Month, d, hour, minute, sec, nsec int, loc *time.Location) time.Time {
+	t := time.Date(y, m, d, hour, minute, sec, nsec, loc)
+	if t.Hour() == hour && t.Minute() == minute {
+		return t
+	}
+
+	_, before := t.Zone()
+	_, after := t.Add(12 * time.Hour).Zone()
+
+	return t.Add(time.Duration(after-before) * time.Second)
+}
+
+// StartOfDay returns local midnight of the day t falls on in loc.
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// IsBusinessDay reports whether t falls on Monday to Friday in loc.
+func IsBusinessDay(t time.Time, loc *time.Location) bool {
+	wd := t.In(loc).Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
+
+// AddBusinessDays moves t by n business days in loc, skipping weekends and keeping the
+// local wall clock time. A negative n moves backwards. With n == 0, a weekend t is moved
+// forward to the next business day.
+func AddBusinessDays(t time.Time, n int, loc *time.Location) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	for !IsBusinessDay(t, loc) {
+		t = AddDays(t, step, loc)
+	}
+
+	for n > 0 {
+		t = AddDays(t, step, loc)
+		if IsBusinessDay(t, loc) {
+			n--
+		}
+	}
+
+	return t
+}
+
+// InWindow reports whether the local time of t in loc is within [start, end). A window
+// whose end is not after its start wraps past midnight, as quiet hours like 22:00-07:00 do.
+// An empty window (start == end) contains nothing.
+func InWindow(t time.Time, loc *time.Location, start, end TimeOfDay) bool {
+	lt := t.In(loc)
+	now := lt.Hour()*60 + lt.Minute()
+	from, to := start.minutes(), end.minutes()
+
+	if from == to {
+		return false
+	}
+	if from < to {
+		return now >= from && now < to
+	}
+
+	return now >= from || now < to
+}
+
+// WindowEnd returns the first instant at or after t when the window [start, end) in loc is
+// over, or t itself when t is outside the window. Useful to defer work until quiet hours end.
+func WindowEnd(t time.Time, loc *time.Location, start, end TimeOfDay) time.Time {
+	if !InWindow(t, loc, start, end) {
+		return t
+	}
+
+	return NextAt(t, loc, end)
+}
@@ -0,0 +1,194 @@
+package clock
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s unavailable: %v", name, err)
+	}
+
+	return loc
+}
+
+func TestNextAtFollowsTheFakeClock(t *testing.T) {
+	// Arrange
+	loc := mustLocation(t, "Asia/Jakarta")
+	clk := NewFake(time.Date(2026, 5, 4, 7, 0, 0, 0, loc))
+	at := TimeOfDay{Hour: 8, Minute: 30}
+
+	// Act
+	today := NextAt(clk.Now(), loc, at)
+	clk.Set(today)
+	onTheDot := NextAt(clk.Now(), loc, at)
+	clk.Advance(time.Minute)
+	justAfter := NextAt(clk.Now(), loc, at)
+
+	// Assert
+	if want := time.Date(2026, 5, 4, 8, 30, 0, 0, loc); !today.Equal(want) {
+		t.Fatalf("expected %v before the time of day, got %v", want, today)
+	}
+	if want := time.Date(2026, 5, 5, 8, 30, 0, 0, loc); !onTheDot.Equal(want) || !justAfter.Equal(want) {
+		t.Fatalf("expected the next day %v at and after the time of day, got %v and %v", want, onTheDot, justAfter)
+	}
+}
+
+func TestNextAtKeepsLocalTimeAcrossDST(t *testing.T) {
+	// Arrange
+	loc := mustLocation(t, "America/New_York")
+	clk := NewFake(time.Date(2026, 3, 7, 10, 0, 0, 0, loc))
+	at := TimeOfDay{Hour: 9, Minute: 0}
+
+	// Act
+	next := NextAt(clk.Now(), loc, at)
+
+	// Assert
+	// 2026-03-08 is 23 hours long; a 24-hour step would land on 10:00
+	if want := time.Date(2026, 3, 8, 9, 0, 0, 0, loc); !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+	if got := next.Sub(clk.Now()); got != 22*time.Hour {
+		t.Fatalf("expected 22h until the next occurrence, got %v", got)
+	}
+}
+
+func TestNextAtInSpringForwardGapNeverRunsEarly(t *testing.T) {
+	// Arrange
+	loc := mustLocation(t, "America/New_York")
+	clk := NewFake(time.Date(2026, 3, 7, 12, 0, 0, 0, loc))
+
+	// Act
+	next := NextAt(clk.Now(), loc, TimeOfDay{Hour: 2, Minute: 30})
+
+	// Assert
+	// 02:00-03:00 does not exist on 2026-03-08
+	gapEnd := time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC)
+	if next.Before(gapEnd) {
+		t.Fatalf("expected the occurrence after the gap, got %v", next)
+	}
+	if want := time.Date(2026, 3, 8, 3, 30, 0, 0, loc); !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+}
+
+func TestAddDaysAcrossFallBack(t *testing.T) {
+	// Arrange
+	loc := mustLocation(t, "Europe/Berlin")
+	clk := NewFake(time.Date(2026, 10, 24, 18, 0, 0, 0, loc))
+
+	// Act
+	next := AddDays(clk.Now(), 1, loc)
+	back := AddDays(next, -1, loc)
+
+	// Assert
+	if want := time.Date(2026, 10, 25, 18, 0, 0, 0, loc); !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+	if got := next.Sub(clk.Now()); got != 25*time.Hour {
+		t.Fatalf("expected a 25h day, got %v", got)
+	}
+	if !back.Equal(clk.Now()) {
+		t.Fatalf("expected to move back to %v, got %v", clk.Now(), back)
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	loc := mustLocation(t, "Asia/Jakarta")
+	friday := time.Date(2026, 5, 8, 9, 0, 0, 0, loc)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		n    int
+		want time.Time
+	}{
+		{name: "friday plus one is monday", now: friday, n: 1, want: time.Date(2026, 5, 11, 9, 0, 0, 0, loc)},
+		{name: "friday plus five is next friday", now: friday, n: 5, want: time.Date(2026, 5, 15, 9, 0, 0, 0, loc)},
+		{name: "monday minus one is friday", now: time.Date(2026, 5, 11, 9, 0, 0, 0, loc), n: -1, want: friday},
+		{name: "saturday plus zero is monday", now: time.Date(2026, 5, 9, 9, 0, 0, 0, loc), n: 0, want: time.Date(2026, 5, 11, 9, 0, 0, 0, loc)},
+		{name: "sunday plus one is tuesday", now: time.Date(2026, 5, 10, 9, 0, 0, 0, loc), n: 1, want: time.Date(2026, 5, 12, 9, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			clk := NewFake(tt.now)
+
+			// Act
+			got := AddBusinessDays(clk.Now(), tt.n, loc)
+
+			// Assert
+			if !got.Equal(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBusinessDayUsesTheLocalCalendar(t *testing.T) {
+	// Arrange
+	// Friday 20:00 in New York is already Saturday in UTC
+	loc := mustLocation(t, "America/New_York")
+	clk := NewFake(time.Date(2026, 5, 9, 0, 0, 0, 0, time.UTC))
+
+	// Act
+	local := IsBusinessDay(clk.Now(), loc)
+	utc := IsBusinessDay(clk.Now(), time.UTC)
+
+	// Assert
+	if !local || utc {
+		t.Fatalf("expected a business day in New York only, got local=%v utc=%v", local, utc)
+	}
+}
+
+func TestQuietHoursWindowWrapsPastMidnight(t *testing.T) {
+	// Arrange
+	loc := mustLocation(t, "Asia/Jakarta")
+	start, end := TimeOfDay{Hour: 22, Minute: 0}, TimeOfDay{Hour: 7, Minute: 0}
+	clk := NewFake(time.Date(2026, 5, 4, 21, 59, 0, 0, loc))
+
+	// Act
+	before := InWindow(clk.Now(), loc, start, end)
+	clk.Advance(time.Minute)
+	atStart := InWindow(clk.Now(), loc, start, end)
+	deferred := WindowEnd(clk.Now(), loc, start, end)
+	clk.Set(time.Date(2026, 5, 5, 7, 0, 0, 0, loc))
+	atEnd := InWindow(clk.Now(), loc, start, end)
+
+	// Assert
+	if before || !atStart || atEnd {
+		t.Fatalf("expected only 22:00-07:00 inside the window, got before=%v start=%v end=%v", before, atStart, atEnd)
+	}
+	if want := time.Date(2026, 5, 5, 7, 0, 0, 0, loc); !deferred.Equal(want) {
+		t.Fatalf("expected the window to end at %v, got %v", want, deferred)
+	}
+	if got := WindowEnd(clk.Now(), loc, start, end); !got.Equal(clk.Now()) {
+		t.Fatalf("expected a time outside the window to stay put, got %v", got)
+	}
+	if InWindow(clk.Now(), loc, start, start) {
+		t.Fatal("expected an empty window to contain nothing")
+	}
+}
+
+func TestParseTimeOfDayAndLocation(t *testing.T) {
+	// Act
+	at, err := ParseTimeOfDay("07:05")
+	_, errBad := ParseTimeOfDay("7pm")
+
+	// Assert
+	if err != nil || at != (TimeOfDay{Hour: 7, Minute: 5}) || at.String() != "07:05" {
+		t.Fatalf("expected 07:05, got %v, %v", at, err)
+	}
+	if !errors.Is(errBad, ErrInvalidTimeOfDay) {
+		t.Fatalf("expected ErrInvalidTimeOfDay, got %v", errBad)
+	}
+	if Location("") != time.UTC || Location("Not/AZone") != time.UTC {
+		t.Fatal("expected an empty or unknown zone to fall back to UTC")
+	}
+}
@@ -0,0 +1,245 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Purpose names what an encrypted column holds. Ciphertext of one purpose never decrypts as another.
+type Purpose string
+
+const (
+	// PurposeOTPSeed scopes encryption to TOTP seeds.
+	PurposeOTPSeed Purpose = "otp_seed"
+	// PurposeRecoveryKey scopes encryption to recovery keys.
+	PurposeRecoveryKey Purpose = "recovery_key"
+	// PurposePhone scopes encryption to phone numbers.
+	PurposePhone Purpose = "phone"
+	// PurposeEmail scopes encryption to user email addresses.
+	PurposeEmail Purpose = "email"
+)
+
+// Scope binds a ciphertext to the row it belongs to. It is used as AES-GCM AAD, so a value
+// copied to another owner or column fails to decrypt.
+type Scope struct {
+	// OwnerID is the ID of the owning row, usually the user ID; 0 for rows without an owner.
+	OwnerID int64
+	// Purpose is what the column holds.
+	Purpose Purpose
+}
+
+var (
+	// ErrNotConfigured indicates a cipher without a key ring.
+	ErrNotConfigured = errors.New("crypto: cipher not configured")
+	// ErrPlaintextEmpty indicates an empty plaintext input.
+	ErrPlaintextEmpty = errors.New("crypto: plaintext is empty")
+	// ErrInvalidKeyLength indicates a key that is not 32 bytes.
+	ErrInvalidKeyLength = errors.New("crypto: invalid key length")
+	// ErrUnknownKeyVersion indicates a ciphertext sealed with a key the ring does not hold.
+	ErrUnknownKeyVersion = errors.New("crypto: unknown key version")
+	// ErrCiphertextTooShort indicates a truncated ciphertext.
+	ErrCiphertextTooShort = errors.New("crypto: ciphertext too short")
+	// ErrUnsupportedFormat indicates an unsupported ciphertext format.
+	ErrUnsupportedFormat = errors.New("crypto: unsupported ciphertext format")
+	// ErrDecryptFailed indicates decryption failure.
+	ErrDecryptFailed = errors.New("crypto: decrypt failed")
+)
+
+// Ciphertext formats (binary):
+//
+// format 1, written before key versions existed and always sealed with key version 1:
+// [0..1] uint16 format, [2..13] nonce, [14..] gcm.Seal output
+//
+// format 2:
+// [0..1] uint16 format, [2..3] uint16 key version, [4..15] nonce, [16..] gcm.Seal output
+const (
+	formatLegacy  uint16 = 1
+	formatVersion uint16 = 2
+
+	gcmNonceSize = 12
+	aesKeyLen    = 32
+)
+
+// KeyRing holds every key a column may have been sealed with.
+type KeyRing struct {
+	keys    map[uint16][]byte
+	current uint16
+}
+
+// NewKeyRing builds a ring from version -> 32-byte key. New values are sealed with current.
+func NewKeyRing(keys map[uint16][]byte, current uint16) (*KeyRing, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("%w: current version %d", ErrUnknownKeyVersion, current)
+	}
+
+	ring := &KeyRing{keys: make(map[uint16][]byte, len(keys)), current: current}
+	for version, key := range keys {
+		if len(key) != aesKeyLen {
+			return nil, fmt.Errorf("%w: version %d has %d bytes (want %d for AES-256)", ErrInvalidKeyLength, version, len(key), aesKeyLen)
+		}
+		// defensive copy so callers can't mutate the ring's keys
+		ring.keys[version] = append([]byte(nil), key...)
+	}
+
+	return ring, nil
+}
+
+// Current returns the version new values are sealed with.
+func (r *KeyRing) Current() uint16 {
+	return r.current
+}
+
+func (r *KeyRing) gcm(version uint16) (cipher.AEAD, error) {
+	key, ok := r.keys[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownKeyVersion, version)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: aes init failed: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Cipher encrypts column values with AES-256-GCM.
+type Cipher struct {
+	ring *KeyRing
+}
+
+// NewCipher constructs a Cipher over ring.
+func NewCipher(ring *KeyRing) *Cipher {
+	return &Cipher{ring: ring}
+}
+
+// Encrypt seals plaintext with the current key, binding it to scope.
+func (c *Cipher) Encrypt(plaintext []byte, scope Scope) ([]byte, error) {
+	if c == nil || c.ring == nil {
+		return nil, ErrNotConfigured
+	}
+	if len(plaintext) == 0 {
+		return nil, ErrPlaintextEmpty
+	}
+
+	version := c.ring.Current()
+	gcm, err := c.ring.gcm(version)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcmNonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("crypto: nonce generation failed: %w", err)
+	}
+
+	sealed := gcm.Seal(nil, nonce, plaintext, scopeAAD(scope))
+
+	out := make([]byte, 4+gcmNonceSize+len(sealed))
+	binary.BigEndian.PutUint16(out[0:2], formatVersion)
+	binary.BigEndian.PutUint16(out[2:4], version)
+	copy(out[4:4+gcmNonceSize], nonce)
+	copy(out[4+gcmNonceSize:], sealed)
+
+	return out, nil
+}
+
+// Decrypt opens ciphertext sealed by Encrypt with any key still in the ring. The scope must
+// match the one used to seal it.
+func (c *Cipher) Decrypt(ciphertext []byte, scope Scope) ([]byte, error) {
+	if c == nil || c.ring == nil {
+		return nil, ErrNotConfigured
+	}
+	if len(ciphertext) < 2 {
+		return nil, ErrCiphertextTooShort
+	}
+
+	var version uint16
+	var rest []byte
+	switch format := binary.BigEndian.Uint16(ciphertext[0:2]); format {
+	case formatLegacy:
+		version, rest = 1, ciphertext[2:]
+	case formatVersion:
+		if len(ciphertext) < 4 {
+			return nil, ErrCiphertextTooShort
+		}
+		version, rest = binary.BigEndian.Uint16(ciphertext[2:4]), ciphertext[4:]
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedFormat, format)
+	}
+	if len(rest) < gcmNonceSize+1 {
+		return nil, ErrCiphertextTooShort
+	}
+
+	gcm, err := c.ring.gcm(version)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := gcm.Open(nil, rest[:gcmNonceSize], rest[gcmNonceSize:], scopeAAD(scope))
+	if err != nil {
+		// do not leak whether it was the wrong scope, the wrong key or tampering
+		return nil, ErrDecryptFailed
+	}
+
+	return plain, nil
+}
+
+// KeyVersion reports which key sealed ciphertext, so rows can be found and re-encrypted
+// after a rotation.
+func KeyVersion(ciphertext []byte) (uint16, error) {
+	if len(ciphertext) < 2 {
+		return 0, ErrCiphertextTooShort
+	}
+
+	switch format := binary.BigEndian.Uint16(ciphertext[0:2]); format {
+	case formatLegacy:
+		return 1, nil
+	case formatVersion:
+		if len(ciphertext) < 4 {
+			return 0, ErrCiphertextTooShort
+		}
+		return binary.BigEndian.Uint16(ciphertext[2:4]), nil
+	default:
+		return 0, fmt.Errorf("%w: %d", ErrUnsupportedFormat, format)
+	}
+}
+
+// EncryptText seals a nullable text value for a bytea column. NULL and empty text stay NULL.
+func (c *Cipher) EncryptText(v pgtype.Text, scope Scope) ([]byte, error) {
+	if !v.Valid || v.String == "" {
+		return nil, nil
+	}
+
+	return c.Encrypt([]byte(v.String), scope)
+}
+
+// DecryptText opens a nullable bytea column back into text. A NULL column is an invalid Text.
+func (c *Cipher) DecryptText(ciphertext []byte, scope Scope) (pgtype.Text, error) {
+	if len(ciphertext) == 0 {
+		return pgtype.Text{}, nil
+	}
+
+	plain, err := c.Decrypt(ciphertext, scope)
+	if err != nil {
+		return pgtype.Text{}, err
+	}
+
+	return pgtype.Text{String: string(plain), Valid: true}, nil
+}
+
+// scopeAAD encodes the scope into a fixed-length byte slice for GCM AAD. The canonical form
+// is the one the MFA encryptor used, so values sealed before this package existed still open.
+func scopeAAD(s Scope) []byte {
+	canonical := fmt.Sprintf("uid=%d\npurpose=%s\n", s.OwnerID, s.Purpose)
+	sum := sha256.Sum256([]byte(canonical))
+	return sum[:]
+}
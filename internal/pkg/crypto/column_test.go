@@ -0,0 +1,157 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, aesKeyLen)
+}
+
+func newTestCipher(t *testing.T, keys map[uint16][]byte, current uint16) *Cipher {
+	t.Helper()
+
+	ring, err := NewKeyRing(keys, current)
+	if err != nil {
+		t.Fatalf("new key ring: %v", err)
+	}
+
+	return NewCipher(ring)
+}
+
+func TestCipherReadsValuesSealedBeforeRotation(t *testing.T) {
+	// Arrange
+	scope := Scope{OwnerID: 42, Purpose: PurposeOTPSeed}
+	before := newTestCipher(t, map[uint16][]byte{1: testKey(1)}, 1)
+	old, err := before.Encrypt([]byte("seed"), scope)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	// Act
+	after := newTestCipher(t, map[uint16][]byte{1: testKey(1), 2: testKey(2)}, 2)
+	plain, errOld := after.Decrypt(old, scope)
+	fresh, errNew := after.Encrypt([]byte("seed"), scope)
+
+	// Assert
+	if errOld != nil || string(plain) != "seed" {
+		t.Fatalf("expected the old value to open after rotation, got %q, %v", plain, errOld)
+	}
+	if errNew != nil {
+		t.Fatalf("encrypt after rotation: %v", errNew)
+	}
+	if v, err := KeyVersion(fresh); err != nil || v != 2 {
+		t.Fatalf("expected new values sealed with version 2, got %d, %v", v, err)
+	}
+	if v, err := KeyVersion(old); err != nil || v != 1 {
+		t.Fatalf("expected the old value to report version 1, got %d, %v", v, err)
+	}
+}
+
+func TestCipherRejectsRetiredKey(t *testing.T) {
+	// Arrange
+	scope := Scope{OwnerID: 42, Purpose: PurposeOTPSeed}
+	old, err := newTestCipher(t, map[uint16][]byte{1: testKey(1)}, 1).Encrypt([]byte("seed"), scope)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	// Act
+	_, err = newTestCipher(t, map[uint16][]byte{2: testKey(2)}, 2).Decrypt(old, scope)
+
+	// Assert
+	if !errors.Is(err, ErrUnknownKeyVersion) {
+		t.Fatalf("expected ErrUnknownKeyVersion, got %v", err)
+	}
+}
+
+func TestCipherBindsScope(t *testing.T) {
+	// Arrange
+	c := newTestCipher(t, map[uint16][]byte{1: testKey(1)}, 1)
+	sealed, err := c.Encrypt([]byte("+15550100"), Scope{OwnerID: 1, Purpose: PurposePhone})
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		scope Scope
+	}{
+		{name: "OtherOwner", scope: Scope{OwnerID: 2, Purpose: PurposePhone}},
+		{name: "OtherPurpose", scope: Scope{OwnerID: 1, Purpose: PurposeOTPSeed}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			_, err := c.Decrypt(sealed, tc.scope)
+
+			// Assert
+			if !errors.Is(err, ErrDecryptFailed) {
+				t.Fatalf("expected ErrDecryptFailed, got %v", err)
+			}
+		})
+	}
+}
+
+// TestCipherOpensLegacyMFAFormat seals a value the way the MFA encryptor did before this
+// package existed: format 1, no key version, and the sha256 of the uid/purpose lines as AAD.
+func TestCipherOpensLegacyMFAFormat(t *testing.T) {
+	// Arrange
+	key := testKey(7)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("aes: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("gcm: %v", err)
+	}
+
+	aad := sha256.Sum256([]byte("uid=42\npurpose=otp_seed\n"))
+	nonce := bytes.Repeat([]byte{9}, gcmNonceSize)
+	legacy := binary.BigEndian.AppendUint16(nil, formatLegacy)
+	legacy = append(legacy, nonce...)
+	legacy = gcm.Seal(legacy, nonce, []byte("JBSWY3DPEHPK3PXP"), aad[:])
+
+	c := newTestCipher(t, map[uint16][]byte{1: key, 2: testKey(8)}, 2)
+
+	// Act
+	plain, err := c.Decrypt(legacy, Scope{OwnerID: 42, Purpose: PurposeOTPSeed})
+
+	// Assert
+	if err != nil || string(plain) != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("expected the legacy MFA value to open, got %q, %v", plain, err)
+	}
+}
+
+func TestCipherTextKeepsNull(t *testing.T) {
+	// Arrange
+	c := newTestCipher(t, map[uint16][]byte{1: testKey(1)}, 1)
+	scope := Scope{OwnerID: 1, Purpose: PurposePhone}
+
+	// Act
+	null, errNull := c.EncryptText(pgtype.Text{}, scope)
+	sealed, errSeal := c.EncryptText(pgtype.Text{String: "+15550100", Valid: true}, scope)
+	opened, errOpen := c.DecryptText(sealed, scope)
+	empty, errEmpty := c.DecryptText(nil, scope)
+
+	// Assert
+	if errNull != nil || null != nil {
+		t.Fatalf("expected NULL text to stay NULL, got %v, %v", null, errNull)
+	}
+	if errSeal != nil || errOpen != nil || opened != (pgtype.Text{String: "+15550100", Valid: true}) {
+		t.Fatalf("expected text to round trip, got %+v, %v, %v", opened, errSeal, errOpen)
+	}
+	if errEmpty != nil || empty.Valid {
+		t.Fatalf("expected a NULL column to be invalid text, got %+v, %v", empty, errEmpty)
+	}
+}
@@ -0,0 +1,7 @@
+// Package crypto encrypts database columns at rest.
+//
+// It includes:
+//   - An AES-256-GCM Cipher whose output is bound to a Scope (owner + purpose) via AAD.
+//   - A versioned KeyRing so keys can be rotated while old rows stay readable.
+//   - pgtype helpers that keep NULL columns NULL.
+package crypto
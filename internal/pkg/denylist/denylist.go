@@ -0,0 +1,90 @@
+// Package denylist provides a Redis-backed list of access tokens revoked before they
+// expire, so a stolen or logged-out token stops working without waiting out its TTL.
+package denylist
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Denylist records revoked access tokens and answers whether a token is still usable.
+type Denylist interface {
+	// Revoke denies the token with id jti until expiresAt, after which it is expired anyway.
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeUser denies every token of userID issued before at. The cutoff is kept for ttl,
+	// which must cover the longest access token lifetime.
+	RevokeUser(ctx context.Context, userID int64, at time.Time, ttl time.Duration) error
+	// IsRevoked reports whether the token with id jti, issued to userID at issuedAt, is denied.
+	IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error)
+}
+
+type Store struct {
+	client *redis.Client
+	prefix string
+}
+
+func New(client *redis.Client) *Store {
+	return &Store{
+		client: client,
+		prefix: "denylist:",
+	}
+}
+
+func (s *Store) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	if jti == "" {
+		return nil
+	}
+
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return s.client.Set(ctx, s.prefix+"jti:"+jti, 1, ttl).Err()
+}
+
+func (s *Store) RevokeUser(ctx context.Context, userID int64, at time.Time, ttl time.Duration) error {
+	if userID == 0 || ttl <= 0 {
+		return nil
+	}
+
+	// JWT times have whole-second precision, so a token issued in the same second as the
+	// cutoff is kept; the new tokens of a password change are issued in that second
+	return s.client.Set(ctx, s.userKey(userID), at.Unix(), ttl).Err()
+}
+
+func (s *Store) IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error) {
+	keys := []string{s.prefix + "jti:" + jti, s.userKey(userID)}
+
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return false, err
+	}
+
+	if jti != "" && values[0] != nil {
+		return true, nil
+	}
+
+	if userID == 0 || values[1] == nil {
+		return false, nil
+	}
+
+	raw, ok := values[1].(string)
+	if !ok {
+		return false, errors.New("denylist: unexpected user cutoff value")
+	}
+	cutoff, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return false, err
+	}
+
+	return issuedAt.Unix() < cutoff, nil
+}
+
+func (s *Store) userKey(userID int64) string {
+	return s.prefix + "user:" + strconv.FormatInt(userID, 10)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 var (
@@ -12,8 +13,14 @@ var (
 
 	// ErrConflict indicates that the request could not be completed due to a conflict.
 	ErrConflict = errors.New("resource conflict")
+
+	// ErrVersionMismatch indicates that the resource changed since the version the caller expected.
+	ErrVersionMismatch = errors.New("resource version mismatch")
 )
 
+// ReasonVersionMismatch is the reason of the conflict returned for a stale expected version.
+const ReasonVersionMismatch = "version_mismatch"
+
 // Type classifies errors into high-level buckets used by the application.
 type Type int
 
@@ -81,6 +88,8 @@ func (c Code) String() string {
 		return "ERROR_CODE_UNAUTHORIZED"
 	case CodeForbidden:
 		return "ERROR_CODE_FORBIDDEN"
+	case CodeTimeout:
+		return "ERROR_CODE_TIMEOUT"
 	case CodeInternal:
 		return "ERROR_CODE_INTERNAL"
 	default:
@@ -98,6 +107,9 @@ type Error struct {
 	errType Type
 	code    Code
 	fields  map[string]string
+	// reason is a machine-readable detail for clients, e.g. why a request was throttled.
+	reason     string
+	retryAfter time.Duration
 }
 
 // Error implements the error interface.
@@ -156,6 +168,16 @@ func (e *Error) Fields() map[string]string {
 	return e.fields
 }
 
+// Reason returns the machine-readable reason code, if set.
+func (e *Error) Reason() string {
+	return e.reason
+}
+
+// RetryAfter returns how long the client should wait before retrying, if set.
+func (e *Error) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
 // Unwrap returns the underlying error.
 func (e *Error) Unwrap() error {
 	return e.err
@@ -192,7 +214,24 @@ func new(err error, msg string, et Type, code Code) error {
 }
 
 // NewServer creates a server-type error with the provided error.
+//
+// The result always wraps err. If err already carries an *Error (e.g. a timeout raised deep
+// in an adapter), its message, type, code and retry hints are kept so the original
+// classification reaches the client.
 func NewServer(err error) error {
+	var gerr *Error
+	if errors.As(err, &gerr) {
+		return &Error{
+			err:        err,
+			msg:        gerr.msg,
+			errType:    gerr.errType,
+			code:       gerr.code,
+			fields:     gerr.fields,
+			reason:     gerr.reason,
+			retryAfter: gerr.retryAfter,
+		}
+	}
+
 	return new(err, "Internal server error", TypeServer, CodeInternal)
 }
 
@@ -201,6 +240,29 @@ func NewBusiness(msg string, code Code) error {
 	return new(nil, msg, TypeBusiness, code)
 }
 
+// NewBusinessReason creates a business-type error carrying a reason code clients can act on,
+// e.g. to prompt for a step-up authentication.
+func NewBusinessReason(msg string, code Code, reason string) error {
+	return &Error{msg: msg, errType: TypeBusiness, code: code, reason: reason}
+}
+
+// NewVersionConflict creates the conflict error for a mutation whose expected version of
+// resource is stale, so the client reloads the record instead of overwriting a newer one.
+func NewVersionConflict(resource string) error {
+	return NewBusinessReason(resource+" was modified by someone else, reload it and try again", CodeConflict, ReasonVersionMismatch)
+}
+
+// NewTooManyRequests creates a rate limiting error carrying a reason code and the wait before retrying.
+func NewTooManyRequests(msg, reason string, retryAfter time.Duration) error {
+	return &Error{
+		msg:        msg,
+		errType:    TypeBusiness,
+		code:       CodeTooManyRequest,
+		reason:     reason,
+		retryAfter: retryAfter,
+	}
+}
+
 // NewInvalidInput creates a validation error for invalid input with a message and underlying error.
 func NewInvalidInput(err error, kv ...string) error {
 	if err != nil {
@@ -0,0 +1,93 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/denylist"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func unaryAuthentication(verifier jwt.JWT, deny denylist.Denylist, publicMethods map[string]struct{}) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, skip := publicMethods[info.FullMethod]; skip {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, verifier, deny)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func streamAuthentication(verifier jwt.JWT, deny denylist.Denylist, publicMethods map[string]struct{}) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, skip := publicMethods[info.FullMethod]; skip {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), verifier, deny)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, withStreamContext(ss, ctx))
+	}
+}
+
+// authenticate verifies the bearer token of the "authorization" metadata, JWT or PASETO, the
+// same way the HTTP API does, and stores its claims into the context.
+func authenticate(ctx context.Context, verifier jwt.JWT, deny denylist.Denylist) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "Authentication required")
+	}
+
+	p := strings.Fields(values[0])
+	if len(p) != 2 || !strings.EqualFold(p[0], "Bearer") {
+		return nil, status.Error(codes.Unauthenticated, "Authentication required")
+	}
+
+	claims, err := verifier.Verify(p[1])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "Invalid or expired token")
+	}
+
+	if deny != nil && claims.IssuedAt != nil {
+		revoked, err := deny.IsRevoked(ctx, claims.ID, claims.UserID, claims.IssuedAt.Time)
+		if err != nil {
+			// an unreachable denylist must not sign everyone out; tokens still expire
+			slog.ErrorContext(ctx, "grpc: failed to check token denylist", "error", err)
+		}
+		if revoked {
+			return nil, status.Error(codes.Unauthenticated, "Invalid or expired token")
+		}
+	}
+
+	userID := strconv.FormatInt(claims.UserID, 10)
+	if claims.ClientID != "" {
+		userID = claims.Subject
+	}
+
+	ctx = jwt.SetAuth(ctx, claims)
+	ctx = instrument.SetUserID(ctx, userID)
+	if claims.Tenant != "" {
+		ctx = instrument.SetTenant(ctx, claims.Tenant)
+	}
+	if claims.Actor != nil {
+		ctx = instrument.SetActorID(ctx, strconv.FormatInt(claims.Actor.UserID, 10))
+	}
+
+	return ctx, nil
+}
@@ -0,0 +1,74 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataRequestID is an accepted alternative to the correlation ID key.
+const metadataRequestID = "x-request-id"
+
+func unaryCorrelationID(uid uid.StringID) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cid := correlationContext(ctx, uid)
+		if cid != "" {
+			//nolint:errcheck // best effort, the call proceeds without the echoed header
+			grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(instrument.HeaderCorrelationID), cid))
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func streamCorrelationID(uid uid.StringID) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cid := correlationContext(ss.Context(), uid)
+		if cid != "" {
+			//nolint:errcheck // best effort, the call proceeds without the echoed header
+			ss.SetHeader(metadata.Pairs(strings.ToLower(instrument.HeaderCorrelationID), cid))
+		}
+
+		return handler(srv, withStreamContext(ss, ctx))
+	}
+}
+
+func normalizeCID(v string) string {
+	if strings.ContainsAny(v, "\r\n") {
+		return ""
+	}
+	v = strings.TrimSpace(v)
+	const maxLen = 128
+	if len(v) > maxLen {
+		v = v[:maxLen]
+	}
+	return v
+}
+
+// correlationContext reads the correlation ID from the incoming metadata, generating one
+// when the caller sent none.
+func correlationContext(ctx context.Context, uid uid.StringID) (context.Context, string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	get := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	carrier := instrument.ExtractCarrier(get)
+	if carrier.CorrelationID == "" {
+		carrier.CorrelationID = normalizeCID(get(metadataRequestID))
+	}
+	if carrier.CorrelationID == "" && uid != nil {
+		carrier.CorrelationID = uid.Generate()
+	}
+	// the user and tenant are whatever the token says, set by authentication, never the caller's claim
+	carrier.UserID, carrier.Tenant, carrier.ActorID = "", "", ""
+
+	return carrier.Context(ctx), carrier.CorrelationID
+}
@@ -0,0 +1,74 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// unaryError turns the errors services return, usecase errors as they are, into gRPC
+// statuses carrying the same message an HTTP client would see.
+func unaryError(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return resp, nil
+}
+
+func streamError(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return toStatus(ss.Context(), err)
+	}
+
+	return nil
+}
+
+func toStatus(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, "request canceled")
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, "request timed out")
+	}
+
+	var gerr *goerror.Error
+	if !errors.As(err, &gerr) {
+		slog.ErrorContext(ctx, "grpc handler returned an unclassified error", "error", err)
+		return status.Error(codes.Internal, "Internal server error")
+	}
+
+	return status.Error(grpcCode(gerr.Code()), gerr.Msg())
+}
+
+func grpcCode(code goerror.Code) codes.Code {
+	switch code {
+	case goerror.CodeInvalidFormat, goerror.CodeInvalidInput:
+		return codes.InvalidArgument
+	case goerror.CodeNotFound:
+		return codes.NotFound
+	case goerror.CodeConflict:
+		return codes.AlreadyExists
+	case goerror.CodeTooManyRequest:
+		return codes.ResourceExhausted
+	case goerror.CodeUnauthorized:
+		return codes.Unauthenticated
+	case goerror.CodeForbidden:
+		return codes.PermissionDenied
+	case goerror.CodeTimeout:
+		return codes.DeadlineExceeded
+	case goerror.CodeInternal:
+		return codes.Internal
+	default:
+		return codes.Internal
+	}
+}
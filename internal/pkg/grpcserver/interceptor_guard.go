@@ -0,0 +1,131 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// guard decides whether a call to method may go on, the way an HTTP middleware that only
+// rejects requests does. A nil guard lets every call through.
+type guard func(ctx context.Context, method string) error
+
+// readMethodPrefixes name the methods that change nothing, by the naming the services
+// follow; every other method is a mutation.
+//
+//nolint:gochecknoglobals // static list
+var readMethodPrefixes = []string{"Get", "List", "Watch"}
+
+// adminServiceSuffix ends the name of every service that manages other accounts or the
+// system, the gRPC counterpart of the HTTP admin route prefixes.
+const adminServiceSuffix = "AdminService"
+
+func unaryGuard(guards []guard, skip map[string]struct{}) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := runGuards(ctx, guards, skip, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func streamGuard(guards []guard, skip map[string]struct{}) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := runGuards(ss.Context(), guards, skip, info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func runGuards(ctx context.Context, guards []guard, skip map[string]struct{}, method string) error {
+	if _, ok := skip[method]; ok {
+		return nil
+	}
+
+	for _, g := range guards {
+		if g == nil {
+			continue
+		}
+		if err := g(ctx, method); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// maintenance rejects the methods listed in app.maintenance.endpoints by their full name,
+// e.g. /gobite.notification.v1.NotificationService/MarkInboxRead, next to the HTTP routes
+// of the same list. The list is read at startup, like for HTTP.
+func maintenance(cfg config.Config) guard {
+	if cfg == nil {
+		return nil
+	}
+
+	methods := make(map[string]struct{})
+	for _, method := range cfg.GetArray("app.maintenance.endpoints") {
+		if method = strings.TrimSpace(method); method != "" {
+			methods[method] = struct{}{}
+		}
+	}
+
+	return func(_ context.Context, method string) error {
+		if _, blocked := methods[method]; blocked {
+			return status.Error(codes.Unavailable, "service is under maintenance")
+		}
+		return nil
+	}
+}
+
+// readOnly rejects the mutations of admin services while app.maintenance.admin_read_only is
+// set. As on HTTP, users keep their own flows during the freeze, so the self-service methods,
+// such as marking the inbox read, stay callable. The flag is read on every call.
+func readOnly(cfg config.Config) guard {
+	if cfg == nil {
+		return nil
+	}
+
+	return func(_ context.Context, method string) error {
+		if !cfg.GetBool("app.maintenance.admin_read_only") || !isAdminMethod(method) || isReadMethod(method) {
+			return nil
+		}
+		return status.Error(codes.PermissionDenied, "read-only mode")
+	}
+}
+
+func isAdminMethod(fullMethod string) bool {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return strings.HasSuffix(service, adminServiceSuffix)
+}
+
+func isReadMethod(fullMethod string) bool {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range readMethodPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// personalOnly limits the services to users signed in with a token, as router.PersonalOnly
+// does for the HTTP self-service endpoints they mirror: they act on the caller's own account
+// without a permission check, so a service account or API key must not reach them. Every
+// method served today is such a method; an internal service calls them on behalf of a user
+// by forwarding that user's access token, not with its own client credentials.
+func personalOnly(ctx context.Context, _ string) error {
+	if clm := jwt.GetAuth(ctx); clm != nil && (clm.APIKeyID != 0 || clm.ClientID != "") {
+		return status.Error(codes.PermissionDenied, "this method requires a user session")
+	}
+
+	return nil
+}
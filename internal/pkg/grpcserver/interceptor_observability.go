@@ -0,0 +1,125 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// observer traces, counts, times and logs every call. Messages are protobuf, so unlike the
+// HTTP middleware it logs no bodies.
+type observer struct {
+	tracer   trace.Tracer
+	requests metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+func newObserver(ins instrument.Instrumentation) *observer {
+	meter := ins.Meter("grpc.server")
+
+	requests, err := meter.Int64Counter("rpc.server.requests", metric.WithDescription("Number of gRPC calls received"))
+	if err != nil {
+		slog.Error("failed to create grpc request counter", "error", err)
+	}
+
+	duration, err := meter.Float64Histogram("rpc.server.duration", metric.WithDescription("gRPC call duration in milliseconds"))
+	if err != nil {
+		slog.Error("failed to create grpc duration histogram", "error", err)
+	}
+
+	return &observer{
+		tracer:   ins.Tracer("grpc.server"),
+		requests: requests,
+		duration: duration,
+	}
+}
+
+func unaryObservability(ins instrument.Instrumentation) grpc.UnaryServerInterceptor {
+	o := newObserver(ins)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, done := o.start(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(err)
+
+		return resp, err
+	}
+}
+
+func streamObservability(ins instrument.Instrumentation) grpc.StreamServerInterceptor {
+	o := newObserver(ins)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, done := o.start(ss.Context(), info.FullMethod)
+		err := handler(srv, withStreamContext(ss, ctx))
+		done(err)
+
+		return err
+	}
+}
+
+// start opens the span of a call to method and returns the function that ends it with the
+// error the call returned, a status error once the error interceptor ran.
+func (o *observer) start(ctx context.Context, method string) (context.Context, func(err error)) {
+	start := time.Now()
+	ctx, span := o.tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.RPCSystemGRPC, attribute.String("rpc.method", method)),
+	)
+
+	slog.InfoContext(ctx, "request received", "method", method)
+
+	return ctx, func(err error) {
+		defer span.End()
+
+		code := status.Code(err)
+		attrs := []attribute.KeyValue{
+			semconv.RPCSystemGRPC,
+			attribute.String("rpc.method", method),
+			semconv.RPCGRPCStatusCodeKey.Int(int(code)),
+		}
+
+		if err != nil {
+			span.RecordError(err)
+		}
+		if serverFault(code) {
+			span.SetStatus(otelcodes.Error, err.Error())
+		} else {
+			span.SetStatus(otelcodes.Ok, "")
+		}
+
+		span.SetAttributes(attrs...)
+		if o.requests != nil {
+			o.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+		}
+		if o.duration != nil {
+			o.duration.Record(ctx, float64(time.Since(start).Milliseconds()), metric.WithAttributes(attrs...))
+		}
+
+		slog.InfoContext(ctx, "response sent",
+			"method", method,
+			"code", code.String(),
+			"latency_ms", time.Since(start).Milliseconds(),
+		)
+	}
+}
+
+// serverFault reports whether code blames the server, as a 5xx status would.
+func serverFault(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,44 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/stacktrace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func unaryRecoverer(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			logPanic(ctx, rvr)
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func streamRecoverer(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			logPanic(ss.Context(), rvr)
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+func logPanic(ctx context.Context, rvr any) {
+	paths := stacktrace.InternalPaths(debug.Stack())
+	if len(paths) == 0 {
+		slog.ErrorContext(ctx, "panic on the grpc server trace debug", "because", rvr, "stack", string(debug.Stack()))
+		return
+	}
+
+	slog.ErrorContext(ctx, "panic on the grpc server", "because", rvr, "stack", paths)
+}
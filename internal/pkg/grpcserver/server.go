@@ -0,0 +1,98 @@
+// Package grpcserver builds the gRPC server internal services call gobite through, next to
+// the HTTP API.
+//
+// Every call passes the same shared concerns as an HTTP request, as interceptors: panic
+// recovery, correlation ID propagation, tracing, metrics and logging, maintenance and
+// read-only mode for admin services, rate limits, JWT authentication limited to user sessions
+// (internal services forward the token of the user they call for), and the mapping
+// of goerror errors to gRPC status codes. Services are registered on the
+// returned server by the modules, from the protobuf definitions in api/proto.
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/denylist"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/pkg/throttle"
+	"github.com/shandysiswandi/gobite/internal/pkg/uid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Config contains dependencies required to build the gRPC server.
+type Config struct {
+	// UUID generates correlation IDs for calls that do not carry one.
+	UUID uid.StringID
+	// JWT validates and parses authentication tokens.
+	JWT jwt.JWT
+	// Instrument provides tracing and metrics helpers.
+	Instrument instrument.Instrumentation
+	// Denylist rejects access tokens revoked before they expire. Nil accepts every valid token.
+	Denylist denylist.Denylist
+	// Config provides the maintenance, read-only and rate limit settings shared with HTTP.
+	Config config.Config
+	// RateLimiter holds the token buckets of app.server.grpc.rate_limit. Nil disables it.
+	RateLimiter throttle.Limiter
+}
+
+// New returns a gRPC server with the shared interceptors and the standard health service,
+// which is the only method callable without a token.
+func New(cfg Config) *grpc.Server {
+	publicMethods := map[string]struct{}{
+		grpc_health_v1.Health_Check_FullMethodName: {},
+		grpc_health_v1.Health_Watch_FullMethodName: {},
+		grpc_health_v1.Health_List_FullMethodName:  {},
+	}
+
+	guards := []guard{
+		maintenance(cfg.Config),
+		readOnly(cfg.Config),
+		rateLimit(cfg.Config, cfg.RateLimiter, false),
+	}
+	authGuards := []guard{
+		personalOnly,
+		rateLimit(cfg.Config, cfg.RateLimiter, true),
+	}
+
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			unaryRecoverer,
+			unaryCorrelationID(cfg.UUID),
+			unaryObservability(cfg.Instrument),
+			unaryError,
+			unaryGuard(guards, publicMethods),
+			unaryAuthentication(cfg.JWT, cfg.Denylist, publicMethods),
+			unaryGuard(authGuards, publicMethods),
+		),
+		grpc.ChainStreamInterceptor(
+			streamRecoverer,
+			streamCorrelationID(cfg.UUID),
+			streamObservability(cfg.Instrument),
+			streamError,
+			streamGuard(guards, publicMethods),
+			streamAuthentication(cfg.JWT, cfg.Denylist, publicMethods),
+			streamGuard(authGuards, publicMethods),
+		),
+	)
+	grpc_health_v1.RegisterHealthServer(srv, health.NewServer())
+
+	return srv
+}
+
+// serverStream replaces the context of a stream, the way r.WithContext does for a request.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func withStreamContext(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &serverStream{ServerStream: ss, ctx: ctx}
+}
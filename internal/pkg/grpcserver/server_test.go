@@ -0,0 +1,224 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	libJWT "github.com/golang-jwt/jwt/v5"
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	notificationv1 "github.com/shandysiswandi/gobite/internal/pkg/pb/notification/v1"
+	"github.com/shandysiswandi/gobite/internal/pkg/throttle"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeJWT struct{}
+
+// Verify accepts "user" and "client" as tokens of a user and of a service account, and
+// "member-a" and "member-b" as tokens of two users scoped to the same organization.
+func (fakeJWT) Verify(token string) (jwt.Claims, error) {
+	clm := jwt.Claims{RegisteredClaims: libJWT.RegisteredClaims{ID: token}}
+	switch token {
+	case "user":
+		clm.UserID = 1
+	case "client":
+		clm.ClientID = "billing"
+		clm.Subject = "client/billing"
+	case "member-a", "member-b":
+		clm.UserID = int64(token[len(token)-1])
+		clm.OrgID = 7
+	default:
+		return jwt.Claims{}, errors.New("invalid token")
+	}
+
+	return clm, nil
+}
+
+func (fakeJWT) Generate(context.Context, int64, string) (string, error) { return "", nil }
+
+func (fakeJWT) Use(...jwt.ClaimsEnricher) {}
+
+type inboxService struct {
+	notificationv1.UnimplementedNotificationServiceServer
+}
+
+func (inboxService) ListInbox(context.Context, *notificationv1.ListInboxRequest) (*notificationv1.ListInboxResponse, error) {
+	return &notificationv1.ListInboxResponse{}, nil
+}
+
+func (inboxService) MarkAllInboxRead(context.Context, *notificationv1.MarkAllInboxReadRequest) (*notificationv1.MarkAllInboxReadResponse, error) {
+	return &notificationv1.MarkAllInboxReadResponse{}, nil
+}
+
+func newTestClient(t *testing.T, yaml string) (notificationv1.NotificationServiceClient, grpc_health_v1.HealthClient) {
+	t.Helper()
+
+	cfg, err := config.NewViperFromBytes("yaml", []byte(yaml))
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	srv := New(Config{JWT: fakeJWT{}, Instrument: instrument.NewNoop(), Config: cfg, RateLimiter: throttle.NewMemoryBucket(), UUID: fixedID{}})
+	notificationv1.RegisterNotificationServiceServer(srv, inboxService{})
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis) //nolint:errcheck // stopped by the cleanup
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return notificationv1.NewNotificationServiceClient(conn), grpc_health_v1.NewHealthClient(conn)
+}
+
+type fixedID struct{}
+
+func (fixedID) Generate() string { return "test-correlation-id" }
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+
+	if got := status.Code(err); got != want {
+		t.Fatalf("expected %s, got %s (%v)", want, got, err)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	client, _ := newTestClient(t, "")
+
+	_, err := client.ListInbox(context.Background(), &notificationv1.ListInboxRequest{})
+	assertCode(t, err, codes.Unauthenticated)
+
+	_, err = client.ListInbox(withToken("forged"), &notificationv1.ListInboxRequest{})
+	assertCode(t, err, codes.Unauthenticated)
+
+	_, err = client.ListInbox(withToken("user"), &notificationv1.ListInboxRequest{})
+	assertCode(t, err, codes.OK)
+}
+
+func TestPersonalOnly(t *testing.T) {
+	client, _ := newTestClient(t, "")
+
+	_, err := client.ListInbox(withToken("client"), &notificationv1.ListInboxRequest{})
+	assertCode(t, err, codes.PermissionDenied)
+}
+
+func TestMaintenance(t *testing.T) {
+	client, health := newTestClient(t, `
+app:
+  maintenance:
+    endpoints: "/gobite.notification.v1.NotificationService/ListInbox"
+`)
+
+	_, err := client.ListInbox(withToken("user"), &notificationv1.ListInboxRequest{})
+	assertCode(t, err, codes.Unavailable)
+
+	_, err = client.MarkAllInboxRead(withToken("user"), &notificationv1.MarkAllInboxReadRequest{})
+	assertCode(t, err, codes.OK)
+
+	_, err = health.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assertCode(t, err, codes.OK)
+}
+
+func TestReadOnly(t *testing.T) {
+	client, _ := newTestClient(t, `
+app:
+  maintenance:
+    admin_read_only: true
+`)
+
+	_, err := client.MarkAllInboxRead(withToken("user"), &notificationv1.MarkAllInboxReadRequest{})
+	assertCode(t, err, codes.OK)
+
+	_, err = client.ListInbox(withToken("user"), &notificationv1.ListInboxRequest{})
+	assertCode(t, err, codes.OK)
+}
+
+func TestReadOnlyAdminMethods(t *testing.T) {
+	cfg, err := config.NewViperFromBytes("yaml", []byte(`
+app:
+  maintenance:
+    admin_read_only: true
+`))
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	g := readOnly(cfg)
+
+	err = g(context.Background(), "/gobite.identity.v1.IdentityAdminService/UpdateUser")
+	assertCode(t, err, codes.PermissionDenied)
+
+	err = g(context.Background(), "/gobite.identity.v1.IdentityAdminService/ListUsers")
+	assertCode(t, err, codes.OK)
+
+	err = g(context.Background(), "/gobite.notification.v1.NotificationService/MarkInboxRead")
+	assertCode(t, err, codes.OK)
+}
+
+func TestRateLimit(t *testing.T) {
+	client, health := newTestClient(t, `
+app:
+  server:
+    grpc:
+      rate_limit:
+        enabled: true
+        limit: 2
+        period_seconds: 60
+`)
+
+	for range 2 {
+		_, err := client.ListInbox(withToken("user"), &notificationv1.ListInboxRequest{})
+		assertCode(t, err, codes.OK)
+	}
+
+	_, err := client.ListInbox(withToken("user"), &notificationv1.ListInboxRequest{})
+	assertCode(t, err, codes.ResourceExhausted)
+
+	_, err = health.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assertCode(t, err, codes.OK)
+}
+
+func TestRateLimitPerOrganization(t *testing.T) {
+	// Arrange
+	client, _ := newTestClient(t, `
+app:
+  server:
+    grpc:
+      rate_limit:
+        enabled: true
+        limit: 5
+        period_seconds: 60
+        org_limit: 2
+        org_period_seconds: 60
+`)
+
+	// Act
+	_, errA := client.ListInbox(withToken("member-a"), &notificationv1.ListInboxRequest{})
+	_, errB := client.ListInbox(withToken("member-b"), &notificationv1.ListInboxRequest{})
+	_, errExhausted := client.ListInbox(withToken("member-a"), &notificationv1.ListInboxRequest{})
+	_, errNoOrg := client.ListInbox(withToken("user"), &notificationv1.ListInboxRequest{})
+
+	// Assert
+	assertCode(t, errA, codes.OK)
+	assertCode(t, errB, codes.OK)
+	assertCode(t, errExhausted, codes.ResourceExhausted)
+	assertCode(t, errNoOrg, codes.OK)
+}
@@ -0,0 +1,7 @@
+// Package httpclient builds the *http.Client shared by outbound integrations.
+//
+// Every request sent through the client is traced with OpenTelemetry, carries the
+// caller's correlation ID (plus the user and tenant when the host is internal), and is
+// retried with backoff when it is safe to do so, so third-party calls behave the same way
+// regardless of which module makes them.
+package httpclient
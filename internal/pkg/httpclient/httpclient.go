@@ -0,0 +1,204 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/resilience"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	// HeaderCorrelationID is the header carrying the caller's correlation ID downstream.
+	HeaderCorrelationID = instrument.HeaderCorrelationID
+	// HeaderIdempotencyKey marks a non-idempotent request as safe to retry.
+	HeaderIdempotencyKey = "Idempotency-Key"
+)
+
+// errRetryableStatus signals a response whose status code is worth another attempt.
+var errRetryableStatus = errors.New("httpclient: retryable status")
+
+// Config tunes the shared client.
+type Config struct {
+	// Timeout bounds a whole call, retries included; zero disables it.
+	Timeout time.Duration
+	// DialTimeout bounds establishing a TCP connection.
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout bounds the TLS handshake.
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout bounds the wait for response headers of one attempt.
+	ResponseHeaderTimeout time.Duration
+	// MaxIdleConnsPerHost caps idle keep-alive connections per host.
+	MaxIdleConnsPerHost int
+	// Retry bounds retries of failed idempotent requests.
+	Retry resilience.RetryConfig
+	// InternalHosts are the hosts that also receive the user, tenant, and actor of the
+	// calling context; every other host only gets the correlation ID. An entry starting
+	// with "." matches every subdomain.
+	InternalHosts []string
+}
+
+// New returns a client that traces, propagates correlation IDs, and retries
+// idempotent requests on network errors and 429, 502, 503, and 504 responses.
+func New(cfg Config) *http.Client {
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = 5 * time.Second
+	}
+	if cfg.TLSHandshakeTimeout <= 0 {
+		cfg.TLSHandshakeTimeout = 5 * time.Second
+	}
+	if cfg.MaxIdleConnsPerHost <= 0 {
+		cfg.MaxIdleConnsPerHost = 10
+	}
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.DialContext = (&net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	base.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	base.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout
+	base.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+
+	// every attempt gets its own span, so retries are visible in the trace
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &retryTransport{
+			cfg:  cfg.Retry,
+			next: otelhttp.NewTransport(&carrierTransport{next: base, internal: cfg.InternalHosts}),
+		},
+	}
+}
+
+// carrierTransport copies the correlation ID from the request context into a header the
+// caller has not set, and the user, tenant, and actor as well for internal hosts.
+type carrierTransport struct {
+	next     http.RoundTripper
+	internal []string
+}
+
+func (t *carrierTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := instrument.CarrierFromContext(req.Context())
+	if !t.isInternal(req.URL.Hostname()) {
+		c = c.Anonymous()
+	}
+
+	carrier := c.Headers()
+	for key := range carrier {
+		if req.Header.Get(key) != "" {
+			delete(carrier, key)
+		}
+	}
+	if len(carrier) == 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	// a RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	for key, value := range carrier {
+		req.Header.Set(key, value)
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+func (t *carrierTransport) isInternal(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range t.internal {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "" {
+			continue
+		}
+		if host == h || (strings.HasPrefix(h, ".") && strings.HasSuffix(host, h)) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryTransport retries requests that can be sent again without side effects.
+type retryTransport struct {
+	cfg  resilience.RetryConfig
+	next http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !retryable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	var resp *http.Response
+	attempt := 0
+	err := resilience.Retry(req.Context(), t.cfg, func(ctx context.Context) error {
+		attempt++
+		if resp != nil {
+			discard(resp)
+			resp = nil
+		}
+
+		r := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return resilience.Permanent(err)
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		res, err := t.next.RoundTrip(r)
+		if err != nil {
+			if ctx.Err() != nil {
+				return resilience.Permanent(err)
+			}
+			return err
+		}
+
+		resp = res
+		if retryableStatus(res.StatusCode) {
+			return errRetryableStatus
+		}
+
+		return nil
+	})
+
+	// the last response is returned as-is, even when its status asked for a retry
+	if resp != nil {
+		return resp, nil
+	}
+
+	return nil, err
+}
+
+// retryable reports whether req can be sent more than once.
+func retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return req.Header.Get(HeaderIdempotencyKey) != ""
+	}
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// discard drains and closes a response body so its connection can be reused.
+func discard(resp *http.Response) {
+	const maxDrain = 4 << 10
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrain)
+	_ = resp.Body.Close()
+}
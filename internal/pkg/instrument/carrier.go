@@ -0,0 +1,159 @@
+package instrument
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	// HeaderCorrelationID carries the correlation ID across process boundaries.
+	HeaderCorrelationID = "X-Correlation-ID"
+	// HeaderUserID carries the ID of the user the work is done for.
+	HeaderUserID = "X-User-ID"
+	// HeaderTenant carries the tenant the work belongs to.
+	HeaderTenant = "X-Tenant-ID"
+	// HeaderActorID carries the ID of the staff member impersonating the user, if any.
+	HeaderActorID = "X-Actor-ID"
+)
+
+type (
+	userIDContextKey  struct{}
+	tenantContextKey  struct{}
+	actorIDContextKey struct{}
+)
+
+// GetUserID returns the user ID stored in the context, or an empty string.
+func GetUserID(ctx context.Context) string {
+	v, _ := ctx.Value(userIDContextKey{}).(string)
+	return v
+}
+
+// SetUserID stores the ID of the acting user into the context.
+func SetUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userIDContextKey{}, id)
+}
+
+// GetTenant returns the tenant stored in the context, or an empty string.
+func GetTenant(ctx context.Context) string {
+	v, _ := ctx.Value(tenantContextKey{}).(string)
+	return v
+}
+
+// SetTenant stores the tenant into the context.
+func SetTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// GetActorID returns the impersonating actor ID stored in the context, or an empty string.
+func GetActorID(ctx context.Context) string {
+	v, _ := ctx.Value(actorIDContextKey{}).(string)
+	return v
+}
+
+// SetActorID stores the ID of the user impersonating the acting user into the context.
+func SetActorID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, actorIDContextKey{}, id)
+}
+
+// Carrier is the request identity that travels with outbound calls so logs on
+// both sides of a broker, mail provider, HTTP hop, or object store line up.
+type Carrier struct {
+	CorrelationID string
+	UserID        string
+	Tenant        string
+	ActorID       string
+}
+
+// CarrierFromContext captures the carrier of ctx. Unset values stay empty.
+func CarrierFromContext(ctx context.Context) Carrier {
+	cid := GetCorrelationID(ctx)
+	if cid == "[invalid_chain_id]" {
+		cid = ""
+	}
+
+	return Carrier{
+		CorrelationID: cid,
+		UserID:        GetUserID(ctx),
+		Tenant:        GetTenant(ctx),
+		ActorID:       GetActorID(ctx),
+	}
+}
+
+// Anonymous returns c without the user, tenant and actor, for calls that leave the
+// platform: only the correlation ID is safe to hand to a third party.
+func (c Carrier) Anonymous() Carrier {
+	return Carrier{CorrelationID: c.CorrelationID}
+}
+
+// ExtractCarrier reads a carrier back using get, which looks a header up by name.
+// Values are untrusted input, so multi-line values are dropped and long ones cut.
+func ExtractCarrier(get func(key string) string) Carrier {
+	return Carrier{
+		CorrelationID: sanitizeCarrierValue(get(HeaderCorrelationID)),
+		UserID:        sanitizeCarrierValue(get(HeaderUserID)),
+		Tenant:        sanitizeCarrierValue(get(HeaderTenant)),
+		ActorID:       sanitizeCarrierValue(get(HeaderActorID)),
+	}
+}
+
+func sanitizeCarrierValue(v string) string {
+	if strings.ContainsAny(v, "\r\n") {
+		return ""
+	}
+
+	const maxLen = 128
+	v = strings.TrimSpace(v)
+	if len(v) > maxLen {
+		v = v[:maxLen]
+	}
+	return v
+}
+
+// Context returns ctx carrying the non-empty values of c.
+func (c Carrier) Context(ctx context.Context) context.Context {
+	if c.CorrelationID != "" {
+		ctx = SetCorrelationID(ctx, c.CorrelationID)
+	}
+	if c.UserID != "" {
+		ctx = SetUserID(ctx, c.UserID)
+	}
+	if c.Tenant != "" {
+		ctx = SetTenant(ctx, c.Tenant)
+	}
+	if c.ActorID != "" {
+		ctx = SetActorID(ctx, c.ActorID)
+	}
+	return ctx
+}
+
+// Headers returns the non-empty values keyed by header name.
+func (c Carrier) Headers() map[string]string {
+	out := make(map[string]string, 4)
+	c.each(func(key, value string) { out[key] = value })
+	return out
+}
+
+// Metadata returns the non-empty values keyed by lowercase names without the
+// "X-" prefix, the form object stores accept for user metadata.
+func (c Carrier) Metadata() map[string]string {
+	out := make(map[string]string, 4)
+	c.each(func(key, value string) {
+		out[strings.ToLower(strings.TrimPrefix(key, "X-"))] = value
+	})
+	return out
+}
+
+func (c Carrier) each(fn func(key, value string)) {
+	if c.CorrelationID != "" {
+		fn(HeaderCorrelationID, c.CorrelationID)
+	}
+	if c.UserID != "" {
+		fn(HeaderUserID, c.UserID)
+	}
+	if c.Tenant != "" {
+		fn(HeaderTenant, c.Tenant)
+	}
+	if c.ActorID != "" {
+		fn(HeaderActorID, c.ActorID)
+	}
+}
@@ -0,0 +1,22 @@
+package instrument
+
+import "context"
+
+type clientContextKey struct{}
+
+// Client describes the caller of the current request, for audit trails.
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// GetClient returns the client stored in the context, or a zero Client.
+func GetClient(ctx context.Context) Client {
+	v, _ := ctx.Value(clientContextKey{}).(Client)
+	return v
+}
+
+// SetClient stores the caller's IP and user agent into the context.
+func SetClient(ctx context.Context, ip, userAgent string) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, Client{IP: ip, UserAgent: userAgent})
+}
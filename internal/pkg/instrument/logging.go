@@ -69,6 +69,15 @@ func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
 	if cID := GetCorrelationID(ctx); cID != "" && cID != "[invalid_chain_id]" {
 		r.AddAttrs(slog.String("_cID", cID))
 	}
+	if uID := GetUserID(ctx); uID != "" {
+		r.AddAttrs(slog.String("_uID", uID))
+	}
+	if tenant := GetTenant(ctx); tenant != "" {
+		r.AddAttrs(slog.String("_tenant", tenant))
+	}
+	if aID := GetActorID(ctx); aID != "" {
+		r.AddAttrs(slog.String("_aID", aID))
+	}
 	r.AddAttrs(slog.String("service", h.serviceName))
 
 	return h.Handler.Handle(ctx, r)
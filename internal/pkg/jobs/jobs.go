@@ -0,0 +1,267 @@
+// Package jobs runs the background work modules register, periodic jobs on their own
+// interval and long-running workers such as consumers, and keeps the outcome of every
+// run so operators can see what runs in the background and start a job by hand.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/clock"
+	"github.com/shandysiswandi/gobite/internal/pkg/goroutine"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	// ErrNotFound means no job is registered under the name.
+	ErrNotFound = errors.New("job not found")
+	// ErrRunning means the job is still in a run, manual or scheduled.
+	ErrRunning = errors.New("job is already running")
+	// ErrNotRunnable means the job cannot be started by hand: it is a worker, or the
+	// registry has not been started.
+	ErrNotRunnable = errors.New("job cannot be run manually")
+)
+
+// Kind tells a periodic job from a long-running worker.
+type Kind string
+
+const (
+	KindScheduled Kind = "scheduled"
+	KindWorker    Kind = "worker"
+)
+
+// Job is a periodic job.
+type Job struct {
+	// Name identifies the job, e.g. "retention".
+	Name string
+	// Interval is the time between runs. A job without a positive interval is listed
+	// and can be run by hand, but is never scheduled.
+	Interval time.Duration
+	// Run does one run; a failure is recorded and the job runs again on the next tick.
+	Run func(ctx context.Context) error
+}
+
+// Status is the state of one job or worker.
+type Status struct {
+	Name     string
+	Kind     Kind
+	Interval time.Duration
+	Running  bool
+	Runs     int64
+	Failures int64
+	// LastRunAt is when the last run started; for a worker, when it started.
+	LastRunAt time.Time
+	// LastSucceeded, LastDuration, and LastError describe the last finished run.
+	LastSucceeded bool
+	LastDuration  time.Duration
+	LastError     string
+	// NextRunAt is zero for workers and for jobs that are not scheduled.
+	NextRunAt time.Time
+}
+
+type entry struct {
+	job    Job
+	kind   Kind
+	status Status
+}
+
+// Registry collects the jobs and workers registered by modules.
+type Registry struct {
+	routine *goroutine.Manager
+	clock   clock.Clocker
+	ins     instrument.Instrumentation
+
+	mu      sync.Mutex
+	ctx     context.Context
+	entries map[string]*entry
+}
+
+// NewRegistry returns a Registry without jobs.
+func NewRegistry(routine *goroutine.Manager, clk clock.Clocker, ins instrument.Instrumentation) *Registry {
+	return &Registry{
+		routine: routine,
+		clock:   clk,
+		ins:     ins,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Schedule adds periodic jobs; they start running with Start. A job registered under a
+// taken name replaces the earlier one.
+func (r *Registry) Schedule(jobs ...Job) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, j := range jobs {
+		r.entries[j.Name] = &entry{
+			job:    j,
+			kind:   KindScheduled,
+			status: Status{Name: j.Name, Kind: KindScheduled, Interval: j.Interval},
+		}
+	}
+}
+
+// Go starts a long-running worker at once and tracks it until run returns.
+func (r *Registry) Go(ctx context.Context, name string, run func(ctx context.Context) error) {
+	r.mu.Lock()
+	e := &entry{
+		job:    Job{Name: name, Run: run},
+		kind:   KindWorker,
+		status: Status{Name: name, Kind: KindWorker},
+	}
+	r.entries[name] = e
+	r.mu.Unlock()
+
+	r.routine.Go(ctx, func(ctx context.Context) error {
+		return r.run(ctx, e)
+	})
+}
+
+// Start schedules every registered job with a positive interval and keeps ctx for
+// manual runs. Jobs scheduled after Start can only be run by hand.
+func (r *Registry) Start(ctx context.Context) {
+	r.mu.Lock()
+	r.ctx = ctx
+	scheduled := make([]*entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		if e.kind == KindScheduled && e.job.Interval > 0 {
+			e.status.NextRunAt = r.clock.Now().Add(e.job.Interval)
+			scheduled = append(scheduled, e)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, e := range scheduled {
+		r.routine.Go(ctx, func(ctx context.Context) error {
+			slog.InfoContext(ctx, "Running scheduled job", "job", e.job.Name, "interval", e.job.Interval.String())
+			r.loop(ctx, e)
+			return nil
+		})
+	}
+}
+
+// List returns the state of every job and worker, ordered by name.
+func (r *Registry) List() []Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]Status, 0, len(r.entries))
+	for _, e := range r.entries {
+		out = append(out, e.status)
+	}
+	slices.SortFunc(out, func(a, b Status) int { return strings.Compare(a.Name, b.Name) })
+
+	return out
+}
+
+// Trigger starts a run of the scheduled job name in the background and returns its state
+// with the run under way. The schedule is not moved.
+func (r *Registry) Trigger(name string) (Status, error) {
+	r.mu.Lock()
+	e, ok := r.entries[name]
+	switch {
+	case !ok:
+		r.mu.Unlock()
+		return Status{}, ErrNotFound
+	case e.kind != KindScheduled || r.ctx == nil:
+		r.mu.Unlock()
+		return Status{}, ErrNotRunnable
+	case e.status.Running:
+		r.mu.Unlock()
+		return Status{}, ErrRunning
+	}
+	ctx := r.ctx
+	r.begin(e)
+	status := e.status
+	r.mu.Unlock()
+
+	r.routine.Go(ctx, func(ctx context.Context) error {
+		slog.InfoContext(ctx, "Running job manually", "job", name)
+		r.finish(ctx, e, e.job.Run(ctx))
+		return nil
+	})
+
+	return status, nil
+}
+
+func (r *Registry) loop(ctx context.Context, e *entry) {
+	ticker := time.NewTicker(e.job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			e.status.NextRunAt = r.clock.Now().Add(e.job.Interval)
+			if e.status.Running {
+				// a manual run is still going, this tick is skipped
+				r.mu.Unlock()
+				continue
+			}
+			r.begin(e)
+			r.mu.Unlock()
+
+			r.finish(ctx, e, e.job.Run(ctx))
+		}
+	}
+}
+
+// run runs a worker to its end.
+func (r *Registry) run(ctx context.Context, e *entry) error {
+	r.mu.Lock()
+	r.begin(e)
+	r.mu.Unlock()
+
+	err := e.job.Run(ctx)
+	r.finish(ctx, e, err)
+
+	return err
+}
+
+// begin marks e as running; the caller holds r.mu.
+func (r *Registry) begin(e *entry) {
+	e.status.Running = true
+	e.status.LastRunAt = r.clock.Now()
+}
+
+func (r *Registry) finish(ctx context.Context, e *entry, err error) {
+	end := r.clock.Now()
+
+	r.mu.Lock()
+	e.status.Running = false
+	e.status.Runs++
+	e.status.LastDuration = end.Sub(e.status.LastRunAt)
+	e.status.LastSucceeded = err == nil
+	e.status.LastError = ""
+	if err != nil {
+		e.status.Failures++
+		e.status.LastError = err.Error()
+	}
+	status := e.status
+	r.mu.Unlock()
+
+	if err != nil {
+		slog.ErrorContext(ctx, "job run failed", "job", status.Name, "duration", status.LastDuration.String(), "error", err)
+	}
+
+	meter := r.ins.Meter("jobs")
+	attrs := metric.WithAttributes(
+		attribute.String("job", status.Name),
+		attribute.Bool("success", err == nil),
+	)
+
+	if hist, herr := meter.Float64Histogram("jobs.run.duration",
+		metric.WithDescription("Duration of background job runs, by job and outcome"),
+		metric.WithUnit("s")); herr == nil {
+		hist.Record(ctx, status.LastDuration.Seconds(), attrs)
+	}
+}
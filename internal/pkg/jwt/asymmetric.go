@@ -0,0 +1,162 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	libJWT "github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA modulus accepted for RS256.
+const minRSABits = 2048
+
+// Asymmetric implements JWT signing with a private key and verification with the public keys
+// published as a JWKS, so other services can verify tokens without sharing a secret. Tokens
+// carry the kid of the key that signed them.
+type Asymmetric struct {
+	method    libJWT.SigningMethod
+	keys      *Keyset
+	issuer    string
+	audiences []string
+	ttl       time.Duration
+	clock     clocker
+	uuid      generator
+	enrichers []ClaimsEnricher
+}
+
+// NewRS256 constructs an Asymmetric JWT implementation signing with RSA keys of at least 2048 bits.
+func NewRS256(cfg Config) (*Asymmetric, error) {
+	return newAsymmetric(cfg, libJWT.SigningMethodRS256)
+}
+
+// NewES256 constructs an Asymmetric JWT implementation signing with ECDSA P-256 keys.
+func NewES256(cfg Config) (*Asymmetric, error) {
+	return newAsymmetric(cfg, libJWT.SigningMethodES256)
+}
+
+func newAsymmetric(cfg Config, method libJWT.SigningMethod) (*Asymmetric, error) {
+	if cfg.Keys == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	keys, err := NewKeyset(context.Background(), cfg.Keys, func(key Key) error {
+		return checkKey(method, key.Public)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Asymmetric{
+		method:    method,
+		keys:      keys,
+		issuer:    cfg.Issuer,
+		audiences: cfg.Audiences,
+		ttl:       cfg.TTLMinutes,
+		clock:     cfg.Clock,
+		uuid:      cfg.UUID,
+	}, nil
+}
+
+// checkKey reports whether pub suits method.
+func checkKey(method libJWT.SigningMethod, pub crypto.PublicKey) error {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if method != libJWT.SigningMethodRS256 {
+			return ErrInvalidSigningMethod
+		}
+		if k.N.BitLen() < minRSABits {
+			return fmt.Errorf("RSA key must be at least %d bits", minRSABits)
+		}
+	case *ecdsa.PublicKey:
+		if method != libJWT.SigningMethodES256 {
+			return ErrInvalidSigningMethod
+		}
+		if k.Curve != elliptic.P256() {
+			return errors.New("ECDSA key must use the P-256 curve")
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", pub)
+	}
+
+	return nil
+}
+
+// Use registers enrichers invoked by Generate in the order they were added.
+func (a *Asymmetric) Use(enrichers ...ClaimsEnricher) {
+	a.enrichers = append(a.enrichers, enrichers...)
+}
+
+// Generate creates a JWT signed with the active key, with the same claims as Symmetric.Generate.
+func (a *Asymmetric) Generate(ctx context.Context, uid int64, email string) (string, error) {
+	clm, err := newClaims(ctx, a.clock.Now(), a.issuer, a.audiences, ttlFromContext(ctx, a.ttl), a.uuid.Generate(), uid, email, a.enrichers)
+	if err != nil {
+		return "", err
+	}
+
+	active := a.keys.Active()
+	token := libJWT.NewWithClaims(a.method, clm)
+	token.Header["kid"] = active.ID
+
+	return token.SignedString(active.Private)
+}
+
+// Verify parses and validates a JWT string signed by any of the configured keys.
+func (a *Asymmetric) Verify(tokenStr string) (Claims, error) {
+	var claims Claims
+
+	token, err := libJWT.ParseWithClaims(tokenStr, &claims,
+		func(t *libJWT.Token) (any, error) {
+			if t.Method != a.method {
+				return nil, ErrInvalidSigningMethod
+			}
+			kid, _ := t.Header["kid"].(string)
+			key, ok := a.keys.Lookup(kid)
+			if !ok {
+				return nil, ErrUnknownKey
+			}
+			return key.Public, nil
+		},
+		libJWT.WithIssuer(a.issuer),
+		libJWT.WithAudience(a.audiences...),
+		libJWT.WithValidMethods([]string{a.method.Alg()}),
+		libJWT.WithIssuedAt(),
+		libJWT.WithExpirationRequired(),
+		libJWT.WithTimeFunc(a.clock.Now),
+	)
+
+	if err != nil {
+		if errors.Is(err, libJWT.ErrTokenExpired) {
+			return Claims{}, ErrTokenExpired
+		}
+		return Claims{}, err
+	}
+
+	if !token.Valid {
+		return Claims{}, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// Reload loads the keys again, picking up a rotation without a restart.
+func (a *Asymmetric) Reload(ctx context.Context) error {
+	return a.keys.Reload(ctx)
+}
+
+// JWKS returns the public keys tokens are verified with, the active one first.
+func (a *Asymmetric) JWKS() JWKSet {
+	keys := a.keys.Keys()
+
+	set := JWKSet{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		set.Keys = append(set.Keys, newJWK(key.ID, a.method.Alg(), key.Public))
+	}
+
+	return set
+}
@@ -3,5 +3,8 @@
 // It includes:
 //   - A typed Claims wrapper (registered claims + strongly-typed payload).
 //   - A symmetric HS512 implementation for generating and verifying tokens.
+//   - Asymmetric RS256 and ES256 implementations publishing their public keys as a JWKS.
+//   - PASETO v4.local and v4.public implementations of the same interface.
+//   - A reloadable Keyset that signs with the active key and verifies by kid, for rotation.
 //   - Context helpers for storing and retrieving authenticated claims.
 package jwt
@@ -3,6 +3,8 @@ package jwt
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -20,14 +22,36 @@ var (
 
 	// ErrInvalidToken is returned when the token is malformed or fails validation.
 	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrNoSigningKey is returned when an asymmetric implementation has no private key to sign with.
+	ErrNoSigningKey = errors.New("no JWT signing key configured")
+
+	// ErrUnknownKey is returned when a token names a kid that is not configured.
+	ErrUnknownKey = errors.New("unknown JWT key id")
 )
 
 // JWT defines the minimal operations needed by the app: generate and verify a token.
 type JWT interface {
-	// Generate creates a signed token for the user.
-	Generate(uid int64, email string) (string, error)
+	// Generate creates a signed token for the user, running every registered enricher first.
+	Generate(ctx context.Context, uid int64, email string) (string, error)
 	// Verify parses and validates the token and returns claims.
 	Verify(tokenStr string) (Claims, error)
+	// Use registers enrichers invoked by Generate. It must be called during startup only.
+	Use(enrichers ...ClaimsEnricher)
+}
+
+// ClaimsEnricher adds application-specific claims (roles, tenant, permission hash, ...) to a
+// token before it is signed, so this package does not depend on the systems that own that data.
+type ClaimsEnricher interface {
+	EnrichClaims(ctx context.Context, clm *Claims) error
+}
+
+// ClaimsEnricherFunc adapts a function to ClaimsEnricher.
+type ClaimsEnricherFunc func(ctx context.Context, clm *Claims) error
+
+// EnrichClaims calls f(ctx, clm).
+func (f ClaimsEnricherFunc) EnrichClaims(ctx context.Context, clm *Claims) error {
+	return f(ctx, clm)
 }
 
 type clocker interface {
@@ -40,10 +64,27 @@ type generator interface {
 
 type jwtContextKey struct{}
 
+type ttlContextKey struct{}
+
+type clientContextKey struct{}
+
+type actorContextKey struct{}
+
+type authTimeContextKey struct{}
+
+type orgContextKey struct{}
+
 // Config defines the inputs for building a JWT implementation.
 type Config struct {
 	// Secret is the HMAC signing key.
 	Secret []byte
+	// Keys supplies the keyset tokens are signed and verified with, required by RS256 and
+	// ES256. With HS512 it is optional: tokens then carry the kid of a keyset secret, and
+	// tokens without a kid are still verified with Secret unless RejectKeyless is set.
+	Keys KeySource
+	// RejectKeyless makes HS512 refuse tokens without a kid, once every token issued before
+	// the keyset was adopted has expired. It needs Keys.
+	RejectKeyless bool
 	// Issuer is the token issuer value.
 	Issuer string
 	// Audiences are the accepted token audiences.
@@ -64,6 +105,52 @@ type Claims struct {
 	UserID int64 `json:"user_id,string"`
 	// UserEmail is the authenticated user email.
 	UserEmail string `json:"user_email"`
+	// Roles are the user's roles at issue time, set by an enricher.
+	Roles []string `json:"roles,omitempty"`
+	// Tenant identifies the tenant the token is scoped to, set by an enricher.
+	Tenant string `json:"tenant,omitempty"`
+	// PermissionHash fingerprints the user's permissions so clients can detect changes.
+	PermissionHash string `json:"perm_hash,omitempty"`
+	// Scope lists the user's effective permissions as space-separated "object:action"
+	// entries, set by an enricher.
+	Scope string `json:"scope,omitempty"`
+	// APIKeyID is set when the request authenticated with an API key instead of a token. The
+	// subject is then the key's own Casbin subject, while UserID is the user it acts for.
+	APIKeyID int64 `json:"-"`
+	// ClientID is set on tokens issued to a service account. The subject is then
+	// ClientSubject(ClientID) and UserID is zero.
+	ClientID string `json:"client_id,omitempty"`
+	// Actor is set on impersonation tokens and names who is really acting (RFC 8693 "act").
+	// The subject and UserID stay those of the impersonated user.
+	Actor *Actor `json:"act,omitempty"`
+	// Impersonated lets clients show an impersonation banner without inspecting Actor.
+	Impersonated bool `json:"impersonated,omitempty"`
+	// AuthTime is when the user last presented their credentials (OIDC "auth_time"), set
+	// with WithAuthTime. Sensitive operations compare it to require a recent sign-in.
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	// OrgID is the organization the token is scoped to, set with WithOrg. Zero means the
+	// token acts outside any organization.
+	OrgID int64 `json:"org_id,string,omitempty"`
+	// OrgRole is the user's role in OrgID, set by an enricher.
+	OrgRole string `json:"org_role,omitempty"`
+}
+
+// Actor identifies the user acting on behalf of the token's subject.
+type Actor struct {
+	// Subject is the actor's own token subject.
+	Subject string `json:"sub"`
+	// UserID is the actor's user identifier.
+	UserID int64 `json:"user_id,string"`
+	// UserEmail is the actor's email.
+	UserEmail string `json:"user_email,omitempty"`
+}
+
+// ClientSubjectPrefix starts the subject of a service account token.
+const ClientSubjectPrefix = "client/"
+
+// ClientSubject returns the subject of tokens issued to the service account clientID.
+func ClientSubject(clientID string) string {
+	return ClientSubjectPrefix + clientID
 }
 
 // GetAuth returns the JWT claims stored in the context, if any.
@@ -80,3 +167,112 @@ func GetAuth(ctx context.Context) *Claims {
 func SetAuth(ctx context.Context, clm Claims) context.Context {
 	return context.WithValue(ctx, jwtContextKey{}, clm)
 }
+
+// WithTTL overrides the token time-to-live for Generate calls made with the returned context.
+// A non-positive ttl keeps the configured default.
+func WithTTL(ctx context.Context, ttl time.Duration) context.Context {
+	return context.WithValue(ctx, ttlContextKey{}, ttl)
+}
+
+// WithClient makes Generate calls made with the returned context issue a token for the
+// service account clientID instead of a user.
+func WithClient(ctx context.Context, clientID string) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, clientID)
+}
+
+// WithActor makes Generate calls made with the returned context issue an impersonation
+// token carrying act as the actor.
+func WithActor(ctx context.Context, act Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, act)
+}
+
+// WithAuthTime makes Generate calls made with the returned context record t as the time the
+// user authenticated.
+func WithAuthTime(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, authTimeContextKey{}, t)
+}
+
+// WithOrg makes Generate calls made with the returned context scope the token to the
+// organization orgID.
+func WithOrg(ctx context.Context, orgID int64) context.Context {
+	return context.WithValue(ctx, orgContextKey{}, orgID)
+}
+
+func orgFromContext(ctx context.Context) int64 {
+	orgID, _ := ctx.Value(orgContextKey{}).(int64)
+	return orgID
+}
+
+func authTimeFromContext(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(authTimeContextKey{}).(time.Time)
+	return t, ok && !t.IsZero()
+}
+
+func actorFromContext(ctx context.Context) *Actor {
+	act, ok := ctx.Value(actorContextKey{}).(Actor)
+	if !ok {
+		return nil
+	}
+
+	return &act
+}
+
+func clientFromContext(ctx context.Context) string {
+	clientID, _ := ctx.Value(clientContextKey{}).(string)
+	return clientID
+}
+
+func ttlFromContext(ctx context.Context, fallback time.Duration) time.Duration {
+	if ttl, ok := ctx.Value(ttlContextKey{}).(time.Duration); ok && ttl > 0 {
+		return ttl
+	}
+
+	return fallback
+}
+
+// newClaims builds the claims of a token issued at now for the user, or for the service account
+// set with WithClient, then runs the enrichers. The actor, auth_time and organization set on ctx
+// are carried over.
+func newClaims(ctx context.Context, now time.Time, issuer string, audiences []string, ttl time.Duration, id string, uid int64, email string, enrichers []ClaimsEnricher) (Claims, error) {
+	clm := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Subject:   strconv.FormatInt(uid, 10),
+			Issuer:    issuer,
+			Audience:  audiences,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		UserID:    uid,
+		UserEmail: email,
+	}
+
+	if clientID := clientFromContext(ctx); clientID != "" {
+		clm.Subject = ClientSubject(clientID)
+		clm.ClientID = clientID
+		clm.UserID = 0
+		clm.UserEmail = ""
+	}
+
+	if act := actorFromContext(ctx); act != nil {
+		clm.Actor = act
+		clm.Impersonated = true
+	}
+
+	if at, ok := authTimeFromContext(ctx); ok {
+		clm.AuthTime = jwt.NewNumericDate(at)
+	}
+
+	if orgID := orgFromContext(ctx); orgID != 0 {
+		clm.OrgID = orgID
+	}
+
+	for _, e := range enrichers {
+		if err := e.EnrichClaims(ctx, &clm); err != nil {
+			return Claims{}, fmt.Errorf("jwt: enrich claims: %w", err)
+		}
+	}
+
+	return clm, nil
+}
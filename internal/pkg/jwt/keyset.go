@@ -0,0 +1,143 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// KeySource loads the keys of a Keyset, from config files, a secrets manager or anything
+// else holding them. It is asked again on every Reload.
+type KeySource interface {
+	// LoadKeys returns every key tokens may be verified with and the kid of the one that
+	// signs; an empty activeID keeps the key signing so far, or at first load picks the
+	// first key able to sign.
+	LoadKeys(ctx context.Context) (keys []Key, activeID string, err error)
+}
+
+// StaticKeys is a KeySource returning a fixed set of keys.
+type StaticKeys struct {
+	Keys     []Key
+	ActiveID string
+}
+
+// LoadKeys returns the fixed keys.
+func (s StaticKeys) LoadKeys(context.Context) ([]Key, string, error) {
+	return s.Keys, s.ActiveID, nil
+}
+
+// Keyset signs with its active key and verifies against every key it holds, looked up by the
+// kid of the token. Rotation keeps existing tokens valid: a new key is added and made active
+// while the old one stays until the tokens it signed have expired, and Reload swaps the set
+// in without a restart.
+type Keyset struct {
+	source  KeySource
+	check   func(Key) error
+	current atomic.Pointer[keysetSnapshot]
+}
+
+type keysetSnapshot struct {
+	active Key
+	byID   map[string]Key
+	keys   []Key
+}
+
+// NewKeyset loads the keys of source, each one accepted by check.
+func NewKeyset(ctx context.Context, source KeySource, check func(Key) error) (*Keyset, error) {
+	ks := &Keyset{source: source, check: check}
+
+	snap, err := ks.load(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	ks.current.Store(snap)
+
+	return ks, nil
+}
+
+// Reload loads the keys again and swaps them in. On failure the current keys stay in use.
+// Without an active kid the signing key stays the same as long as it is still loaded, so a
+// new kid that sorts first does not take over before every instance verifies it.
+func (ks *Keyset) Reload(ctx context.Context) error {
+	snap, err := ks.load(ctx, ks.Active().ID)
+	if err != nil {
+		return err
+	}
+
+	prev := ks.current.Swap(snap)
+	if prev.active.ID != snap.active.ID || len(prev.keys) != len(snap.keys) {
+		slog.InfoContext(ctx, "jwt keys reloaded", "active_kid", snap.active.ID, "keys", len(snap.keys))
+	}
+
+	return nil
+}
+
+// Active returns the key new tokens are signed with.
+func (ks *Keyset) Active() Key {
+	return ks.current.Load().active
+}
+
+// Lookup returns the key with id kid.
+func (ks *Keyset) Lookup(kid string) (Key, bool) {
+	key, ok := ks.current.Load().byID[kid]
+	return key, ok
+}
+
+// Keys returns every key, the active one first.
+func (ks *Keyset) Keys() []Key {
+	return ks.current.Load().keys
+}
+
+// load reads the keys of the source. current is the kid signing so far, kept when the source
+// names no active key.
+func (ks *Keyset) load(ctx context.Context, current string) (*keysetSnapshot, error) {
+	keys, activeID, err := ks.source.LoadKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: load keys: %w", err)
+	}
+
+	snap := &keysetSnapshot{byID: make(map[string]Key, len(keys))}
+	loaded := make([]Key, 0, len(keys))
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("jwt: key id is required")
+		}
+		if _, dup := snap.byID[key.ID]; dup {
+			return nil, fmt.Errorf("jwt: duplicate key id %q", key.ID)
+		}
+
+		if key.Public == nil && key.Private != nil {
+			key.Public = key.Private.Public()
+		}
+		if err := ks.check(key); err != nil {
+			return nil, fmt.Errorf("jwt: key %q: %w", key.ID, err)
+		}
+
+		snap.byID[key.ID] = key
+		loaded = append(loaded, key)
+	}
+
+	if activeID == "" {
+		if key, ok := snap.byID[current]; ok && key.canSign() {
+			activeID = current
+		}
+	}
+
+	rest := make([]Key, 0, len(loaded))
+	for _, key := range loaded {
+		if snap.active.ID == "" && key.canSign() && (activeID == "" || key.ID == activeID) {
+			snap.active = key
+			continue
+		}
+		rest = append(rest, key)
+	}
+
+	if snap.active.ID == "" {
+		return nil, ErrNoSigningKey
+	}
+	snap.keys = append([]Key{snap.active}, rest...)
+
+	return snap, nil
+}
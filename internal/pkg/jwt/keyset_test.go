@@ -0,0 +1,38 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+type keysFunc func() ([]Key, string)
+
+func (f keysFunc) LoadKeys(context.Context) ([]Key, string, error) {
+	keys, active := f()
+	return keys, active, nil
+}
+
+func TestKeysetReloadKeepsActiveKey(t *testing.T) {
+	// Arrange
+	secret := bytes.Repeat([]byte("s"), 64)
+	keys := []Key{{ID: "2026-02", Secret: secret}}
+	ks, err := NewKeyset(context.Background(), keysFunc(func() ([]Key, string) { return keys, "" }), func(Key) error { return nil })
+	if err != nil {
+		t.Fatalf("new keyset: %v", err)
+	}
+
+	// Act
+	keys = []Key{{ID: "2026-01", Secret: secret}, {ID: "2026-02", Secret: secret}}
+	if err := ks.Reload(context.Background()); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	// Assert
+	if got := ks.Active().ID; got != "2026-02" {
+		t.Fatalf("expected the signing key to stay 2026-02, got %q", got)
+	}
+	if _, ok := ks.Lookup("2026-01"); !ok {
+		t.Fatal("expected the new key to be loaded for verification")
+	}
+}
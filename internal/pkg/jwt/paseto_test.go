@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// The vectors below are 4-E-1 and 4-S-1 of the official PASETO v4 test vectors
+// (github.com/paseto-standard/test-vectors, v4.json).
+
+func TestPasetoLocalVector(t *testing.T) {
+	// Arrange
+	key, _ := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	nonce := make([]byte, pasetoNonceSize)
+	payload := `{"data":"this is a secret message","exp":"2022-01-01T00:00:00+00:00"}`
+	token := "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvSwscFlAl1pk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XJ5hOb_4v9RmDkneN0S92dx0OW4pgy7omxgf3S8c3LlQg"
+
+	// Act
+	body, err := pasetoSeal(key, nonce, []byte(payload), nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if got := pasetoLocalHeader + base64.RawURLEncoding.EncodeToString(body); got != token {
+		t.Fatalf("unexpected token:\n got %s\nwant %s", got, token)
+	}
+
+	raw, _ := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, pasetoLocalHeader))
+	opened, err := pasetoDecrypt(key, raw, nil)
+	if err != nil || string(opened) != payload {
+		t.Fatalf("decrypt: payload=%q err=%v", opened, err)
+	}
+
+	raw[len(raw)-1] ^= 1
+	if _, err := pasetoDecrypt(key, raw, nil); err == nil {
+		t.Fatal("expected a tampered token to be rejected")
+	}
+}
+
+func TestPasetoPublicVector(t *testing.T) {
+	// Arrange
+	secret, _ := hex.DecodeString("b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a3774" +
+		"1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2")
+	key := ed25519.PrivateKey(secret)
+	payload := `{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`
+	token := "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA"
+
+	// Act
+	body, err := pasetoSign(key, []byte(payload), nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if got := pasetoPublicHeader + base64.RawURLEncoding.EncodeToString(body); got != token {
+		t.Fatalf("unexpected token:\n got %s\nwant %s", got, token)
+	}
+
+	opened, err := pasetoOpen(key.Public().(ed25519.PublicKey), body, nil)
+	if err != nil || string(opened) != payload {
+		t.Fatalf("open: payload=%q err=%v", opened, err)
+	}
+
+	if _, err := pasetoOpen(key.Public().(ed25519.PublicKey), body, []byte(`{"kid":"other"}`)); err == nil {
+		t.Fatal("expected a token with another footer to be rejected")
+	}
+}
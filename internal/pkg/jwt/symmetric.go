@@ -1,21 +1,27 @@
 package jwt
 
 import (
+	"context"
 	"errors"
-	"strconv"
 	"time"
 
 	libJWT "github.com/golang-jwt/jwt/v5"
 )
 
-// Symmetric implements JWT signing and verification using an HMAC secret.
+// Symmetric implements JWT signing and verification using an HMAC secret. With a keyset it
+// signs with the active keyset secret and names it in the kid header, while tokens without a
+// kid, issued before the keyset was configured, are still verified with the base secret
+// until rejectKeyless is set.
 type Symmetric struct {
-	secret    []byte
-	issuer    string
-	audiences []string
-	ttl       time.Duration
-	clock     clocker
-	uuid      generator
+	secret        []byte
+	keys          *Keyset
+	rejectKeyless bool
+	issuer        string
+	audiences     []string
+	ttl           time.Duration
+	clock         clocker
+	uuid          generator
+	enrichers     []ClaimsEnricher
 }
 
 // NewHS512 constructs a Symmetric JWT implementation using HS512.
@@ -24,39 +30,74 @@ func NewHS512(cfg Config) (*Symmetric, error) {
 		return nil, ErrSigningKeyTooShort
 	}
 
-	return &Symmetric{
-		secret:    cfg.Secret,
-		issuer:    cfg.Issuer,
-		audiences: cfg.Audiences,
-		ttl:       cfg.TTLMinutes,
-		clock:     cfg.Clock,
-		uuid:      cfg.UUID,
-	}, nil
+	if cfg.RejectKeyless && cfg.Keys == nil {
+		return nil, errors.New("rejecting tokens without a kid needs a keyset")
+	}
+
+	s := &Symmetric{
+		secret:        cfg.Secret,
+		rejectKeyless: cfg.RejectKeyless,
+		issuer:        cfg.Issuer,
+		audiences:     cfg.Audiences,
+		ttl:           cfg.TTLMinutes,
+		clock:         cfg.Clock,
+		uuid:          cfg.UUID,
+	}
+
+	if cfg.Keys != nil {
+		keys, err := NewKeyset(context.Background(), cfg.Keys, func(key Key) error {
+			if len(key.Secret) < 64 {
+				return ErrSigningKeyTooShort
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.keys = keys
+	}
+
+	return s, nil
+}
+
+// Use registers enrichers invoked by Generate in the order they were added.
+func (s *Symmetric) Use(enrichers ...ClaimsEnricher) {
+	s.enrichers = append(s.enrichers, enrichers...)
 }
 
-// Generate creates a signed JWT for the user.
-func (s *Symmetric) Generate(uid int64, email string) (string, error) {
+// Generate creates a signed JWT for the user, or for the service account set with WithClient.
+// An actor set with WithActor marks it as an impersonation token. The expiry honours a TTL set
+// with WithTTL, and auth_time is the time set with WithAuthTime.
+func (s *Symmetric) Generate(ctx context.Context, uid int64, email string) (string, error) {
 	now := s.clock.Now()
 
 	if len(s.secret) < 64 {
 		return "", ErrSigningKeyTooShort
 	}
 
-	return libJWT.
-		NewWithClaims(libJWT.SigningMethodHS512, Claims{
-			RegisteredClaims: libJWT.RegisteredClaims{
-				ID:        s.uuid.Generate(),
-				Subject:   strconv.FormatInt(uid, 10),
-				Issuer:    s.issuer,
-				Audience:  s.audiences,
-				IssuedAt:  libJWT.NewNumericDate(now),
-				NotBefore: libJWT.NewNumericDate(now),
-				ExpiresAt: libJWT.NewNumericDate(now.Add(s.ttl)),
-			},
-			UserID:    uid,
-			UserEmail: email,
-		}).
-		SignedString(s.secret)
+	clm, err := newClaims(ctx, now, s.issuer, s.audiences, ttlFromContext(ctx, s.ttl), s.uuid.Generate(), uid, email, s.enrichers)
+	if err != nil {
+		return "", err
+	}
+
+	token := libJWT.NewWithClaims(libJWT.SigningMethodHS512, clm)
+	if s.keys == nil {
+		return token.SignedString(s.secret)
+	}
+
+	active := s.keys.Active()
+	token.Header["kid"] = active.ID
+
+	return token.SignedString(active.Secret)
+}
+
+// Reload loads the keyset again, picking up a rotation without a restart.
+func (s *Symmetric) Reload(ctx context.Context) error {
+	if s.keys == nil {
+		return nil
+	}
+
+	return s.keys.Reload(ctx)
 }
 
 // Verify parses and validates a JWT string.
@@ -72,13 +113,28 @@ func (s *Symmetric) Verify(tokenStr string) (Claims, error) {
 			if t.Method != libJWT.SigningMethodHS512 {
 				return nil, ErrInvalidSigningMethod
 			}
-			return s.secret, nil
+			kid, _ := t.Header["kid"].(string)
+			if kid == "" {
+				if s.rejectKeyless {
+					return nil, ErrUnknownKey
+				}
+				return s.secret, nil
+			}
+			if s.keys == nil {
+				return nil, ErrUnknownKey
+			}
+			key, ok := s.keys.Lookup(kid)
+			if !ok {
+				return nil, ErrUnknownKey
+			}
+			return key.Secret, nil
 		},
 		libJWT.WithIssuer(s.issuer),
 		libJWT.WithAudience(s.audiences...),
 		libJWT.WithValidMethods([]string{libJWT.SigningMethodHS512.Alg()}),
 		libJWT.WithIssuedAt(),
 		libJWT.WithExpirationRequired(),
+		libJWT.WithTimeFunc(s.clock.Now),
 	)
 
 	if err != nil {
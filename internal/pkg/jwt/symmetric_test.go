@@ -0,0 +1,50 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/clock"
+)
+
+type staticID string
+
+func (s staticID) Generate() string { return string(s) }
+
+func TestHS512ExpiresOnInjectedClock(t *testing.T) {
+	// Arrange
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	s, err := NewHS512(Config{
+		Secret:     bytes.Repeat([]byte("s"), 64),
+		Issuer:     "gobite",
+		Audiences:  []string{"gobite"},
+		TTLMinutes: 15 * time.Minute,
+		Clock:      clk,
+		UUID:       staticID("jti"),
+	})
+	if err != nil {
+		t.Fatalf("new hs512: %v", err)
+	}
+
+	token, err := s.Generate(context.Background(), 1, "user@example.com")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	// Act
+	clk.Advance(14 * time.Minute)
+	_, errBefore := s.Verify(token)
+	clk.Advance(2 * time.Minute)
+	_, errAfter := s.Verify(token)
+
+	// Assert
+	if errBefore != nil {
+		t.Fatalf("expected the token to be valid before its expiry, got %v", errBefore)
+	}
+	if !errors.Is(errAfter, ErrTokenExpired) {
+		t.Fatalf("expected ErrTokenExpired after the expiry, got %v", errAfter)
+	}
+}
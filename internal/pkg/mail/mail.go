@@ -24,6 +24,8 @@ type Message struct {
 	TextBody string
 	// HTMLBody is the optional HTML body.
 	HTMLBody string
+	// Headers holds extra message headers such as List-Unsubscribe.
+	Headers map[string]string
 }
 
 // Mail abstracts an email provider (SMTP, third-party API, etc).
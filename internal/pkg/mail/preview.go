@@ -0,0 +1,143 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// PreviewOff sends mail unchanged.
+	PreviewOff = "off"
+	// PreviewRedirect rewrites recipients that are not allowlisted to a single address.
+	PreviewRedirect = "redirect"
+	// PreviewCapture writes messages to disk instead of sending them.
+	PreviewCapture = "capture"
+)
+
+var (
+	// ErrPreviewMode is returned for an unknown preview mode.
+	ErrPreviewMode = errors.New("mail: unknown preview mode")
+	// ErrPreviewRedirectRequired is returned when redirect mode has no target address.
+	ErrPreviewRedirectRequired = errors.New("mail: preview redirect address is required")
+	// ErrPreviewCaptureDirRequired is returned when capture mode has no directory.
+	ErrPreviewCaptureDirRequired = errors.New("mail: preview capture dir is required")
+)
+
+// PreviewConfig configures the preview mode used in non-production environments.
+type PreviewConfig struct {
+	// Mode is one of PreviewOff, PreviewRedirect, or PreviewCapture.
+	Mode string
+	// RedirectTo receives every message addressed to a non-allowlisted recipient.
+	RedirectTo string
+	// Allowlist holds addresses ("qa@example.com") or domains ("@example.com") delivered as-is.
+	Allowlist []string
+	// CaptureDir is where captured messages are written as JSON files.
+	CaptureDir string
+}
+
+// Preview decorates a Mail so staging environments never reach real users.
+type Preview struct {
+	next Mail
+	cfg  PreviewConfig
+	now  func() time.Time
+}
+
+// NewPreview wraps next according to cfg. With PreviewOff it returns next unchanged.
+func NewPreview(next Mail, cfg PreviewConfig) (Mail, error) {
+	cfg.Mode = strings.ToLower(strings.TrimSpace(cfg.Mode))
+	switch cfg.Mode {
+	case "", PreviewOff:
+		return next, nil
+	case PreviewRedirect:
+		if strings.TrimSpace(cfg.RedirectTo) == "" {
+			return nil, ErrPreviewRedirectRequired
+		}
+	case PreviewCapture:
+		if strings.TrimSpace(cfg.CaptureDir) == "" {
+			return nil, ErrPreviewCaptureDirRequired
+		}
+		if err := os.MkdirAll(cfg.CaptureDir, 0o750); err != nil {
+			return nil, fmt.Errorf("mail: create capture dir: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrPreviewMode, cfg.Mode)
+	}
+
+	for i, entry := range cfg.Allowlist {
+		cfg.Allowlist[i] = strings.ToLower(strings.TrimSpace(entry))
+	}
+
+	return &Preview{next: next, cfg: cfg, now: time.Now}, nil
+}
+
+// Send redirects or captures msg depending on the configured mode.
+func (p *Preview) Send(ctx context.Context, msg Message) error {
+	if p.cfg.Mode == PreviewCapture {
+		return p.capture(msg)
+	}
+
+	// the subject only flags the redirect: listing the original recipients would copy real
+	// users' addresses into the shared staging inbox
+	redirected := false
+	msg.To, redirected = p.redirect(msg.To, redirected)
+	msg.Cc, redirected = p.redirect(msg.Cc, redirected)
+	msg.Bcc, redirected = p.redirect(msg.Bcc, redirected)
+	if redirected {
+		msg.Subject = "[preview] " + msg.Subject
+	}
+
+	return p.next.Send(ctx, msg)
+}
+
+// Close closes the wrapped provider.
+func (p *Preview) Close() error {
+	return p.next.Close()
+}
+
+// redirect keeps allowlisted addresses and collapses the rest into RedirectTo.
+func (p *Preview) redirect(addrs []string, redirected bool) ([]string, bool) {
+	out := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		if p.allowed(addr) {
+			out = append(out, addr)
+			continue
+		}
+		if !redirected {
+			out = append(out, p.cfg.RedirectTo)
+		}
+		redirected = true
+	}
+
+	return out, redirected
+}
+
+func (p *Preview) allowed(addr string) bool {
+	addr = strings.ToLower(strings.TrimSpace(addr))
+	for _, entry := range p.cfg.Allowlist {
+		if entry == "" {
+			continue
+		}
+		if addr == entry || (strings.HasPrefix(entry, "@") && strings.HasSuffix(addr, entry)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Preview) capture(msg Message) error {
+	data, err := json.MarshalIndent(msg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s.json", p.now().UTC().Format("20060102T150405.000000000Z"))
+
+	return os.WriteFile(filepath.Join(p.cfg.CaptureDir, name), data, 0o600)
+}
@@ -0,0 +1,36 @@
+package mail
+
+import (
+	"context"
+	"maps"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+)
+
+// Propagating decorates a Mail so every message carries the correlation ID of the
+// sending context as an extra header. The user and tenant are left out: they would reach
+// the provider and every recipient's inbox.
+type Propagating struct {
+	next Mail
+}
+
+// NewPropagating wraps next.
+func NewPropagating(next Mail) *Propagating {
+	return &Propagating{next: next}
+}
+
+// Send adds the carrier of ctx to msg. Headers already set on msg win.
+func (p *Propagating) Send(ctx context.Context, msg Message) error {
+	carrier := instrument.CarrierFromContext(ctx).Anonymous().Headers()
+	if len(carrier) > 0 {
+		maps.Copy(carrier, msg.Headers)
+		msg.Headers = carrier
+	}
+
+	return p.next.Send(ctx, msg)
+}
+
+// Close closes the wrapped provider.
+func (p *Propagating) Close() error {
+	return p.next.Close()
+}
@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/resilience"
+)
+
+// Resilient decorates a Mail with a timeout, bounded retry, and circuit breaker
+// so a degraded provider fails fast instead of stalling its callers.
+type Resilient struct {
+	next   Mail
+	policy *resilience.Policy
+}
+
+// NewResilient wraps next with the given policy.
+func NewResilient(next Mail, policy *resilience.Policy) *Resilient {
+	return &Resilient{next: next, policy: policy}
+}
+
+// Send dispatches msg through the policy. Message errors are not retried.
+func (r *Resilient) Send(ctx context.Context, msg Message) error {
+	return r.policy.Do(ctx, func(ctx context.Context) error {
+		err := r.next.Send(ctx, msg)
+		if errors.Is(err, ErrSMTPNoRecipients) || errors.Is(err, ErrSMTPNoSender) {
+			return resilience.Permanent(err)
+		}
+
+		return err
+	})
+}
+
+// Close closes the wrapped provider.
+func (r *Resilient) Close() error {
+	return r.next.Close()
+}
@@ -6,7 +6,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
 	"net/smtp"
+	"slices"
 	"strings"
 )
 
@@ -91,6 +93,9 @@ func (s *SMTP) Send(ctx context.Context, msg Message) error {
 		headers = append(headers, fmt.Sprintf("Cc: %s", strings.Join(msg.Cc, ", ")))
 	}
 	headers = append(headers, fmt.Sprintf("Subject: %s", msg.Subject))
+	for _, key := range slices.Sorted(maps.Keys(msg.Headers)) {
+		headers = append(headers, fmt.Sprintf("%s: %s", key, msg.Headers[key]))
+	}
 	headers = append(headers, "MIME-Version: 1.0")
 	headers = append(headers, fmt.Sprintf("Content-Type: %s", contentType))
 
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
 )
 
 var (
@@ -20,16 +23,42 @@ var (
 	ErrKafkaBrokersRequired = errors.New("pkgmessage: kafka brokers are required")
 	// ErrKafkaGroupRequired is returned when a consumer group is required but not provided.
 	ErrKafkaGroupRequired = errors.New("pkgmessage: kafka consumer group is required")
+	// ErrKafkaSASLMechanism is returned for an unsupported SASL mechanism.
+	ErrKafkaSASLMechanism = errors.New("pkgmessage: unsupported kafka sasl mechanism")
 )
 
+const (
+	// KafkaSASLPlain selects SASL/PLAIN.
+	KafkaSASLPlain = "plain"
+	// KafkaSASLSCRAMSHA256 selects SASL/SCRAM-SHA-256.
+	KafkaSASLSCRAMSHA256 = "scram-sha-256"
+	// KafkaSASLSCRAMSHA512 selects SASL/SCRAM-SHA-512.
+	KafkaSASLSCRAMSHA512 = "scram-sha-512"
+)
+
+// KafkaSASLConfig configures SASL authentication; an empty Mechanism disables it.
+type KafkaSASLConfig struct {
+	Mechanism string
+	Username  string
+	Password  string
+}
+
 // KafkaConfig configures the Kafka implementation.
 type KafkaConfig struct {
 	// Brokers lists Kafka broker addresses.
 	Brokers []string
 
-	// Dialer configures broker connections.
+	// Dialer configures broker connections. When nil, one is built from
+	// DialTimeout, SASL, and TLS.
 	Dialer *kafka.Dialer
 
+	// DialTimeout bounds connection attempts to a broker.
+	DialTimeout time.Duration
+	// SASL configures broker authentication.
+	SASL KafkaSASLConfig
+	// TLS configures encrypted broker connections.
+	TLS TLSConfig
+
 	// WriterConfig overrides the default writer configuration.
 	WriterConfig *kafka.WriterConfig
 	// ReaderConfig overrides the default reader configuration.
@@ -56,9 +85,17 @@ func NewKafka(cfg KafkaConfig) (*Kafka, error) {
 		return nil, ErrKafkaBrokersRequired
 	}
 
+	dialer := cfg.Dialer
+	if dialer == nil {
+		var err error
+		if dialer, err = newKafkaDialer(cfg); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Kafka{
 		brokers: append([]string{}, cfg.Brokers...),
-		dialer:  cfg.Dialer,
+		dialer:  dialer,
 
 		writerConfig: cfg.WriterConfig,
 		readerConfig: cfg.ReaderConfig,
@@ -388,3 +425,37 @@ func waitKafkaConsume(ctx context.Context, errCh <-chan error, wg *sync.WaitGrou
 		return ctx.Err()
 	}
 }
+
+// newKafkaDialer builds a dialer from the connection settings in cfg.
+func newKafkaDialer(cfg KafkaConfig) (*kafka.Dialer, error) {
+	dialer := &kafka.Dialer{
+		Timeout:   cfg.DialTimeout,
+		DualStack: true,
+	}
+	if dialer.Timeout <= 0 {
+		dialer.Timeout = 10 * time.Second
+	}
+
+	tlsCfg, err := cfg.TLS.Build()
+	if err != nil {
+		return nil, err
+	}
+	dialer.TLS = tlsCfg
+
+	switch strings.ToLower(strings.TrimSpace(cfg.SASL.Mechanism)) {
+	case "":
+	case KafkaSASLPlain:
+		dialer.SASLMechanism = plain.Mechanism{Username: cfg.SASL.Username, Password: cfg.SASL.Password}
+	case KafkaSASLSCRAMSHA256:
+		dialer.SASLMechanism, err = scram.Mechanism(scram.SHA256, cfg.SASL.Username, cfg.SASL.Password)
+	case KafkaSASLSCRAMSHA512:
+		dialer.SASLMechanism, err = scram.Mechanism(scram.SHA512, cfg.SASL.Username, cfg.SASL.Password)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrKafkaSASLMechanism, cfg.SASL.Mechanism)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pkgmessage: kafka sasl: %w", err)
+	}
+
+	return dialer, nil
+}
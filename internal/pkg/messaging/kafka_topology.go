@@ -0,0 +1,90 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// EnsureTopology creates the missing topics on the cluster controller. Consumer groups
+// are created by the brokers when a reader joins them.
+func (k *Kafka) EnsureTopology(ctx context.Context, t Topology) (ProvisionResult, error) {
+	conn, err := k.dialAnyBroker(ctx)
+	if err != nil {
+		return ProvisionResult{}, err
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions()
+	if err != nil {
+		return ProvisionResult{}, fmt.Errorf("pkgmessage: kafka read partitions: %w", err)
+	}
+
+	existing := make(map[string]bool, len(partitions))
+	for _, p := range partitions {
+		existing[p.Topic] = true
+	}
+
+	var (
+		configs []kafka.TopicConfig
+		result  ProvisionResult
+	)
+	for _, topic := range t.Topics {
+		if existing[topic.Name] {
+			continue
+		}
+		existing[topic.Name] = true
+
+		cfg := kafka.TopicConfig{
+			Topic:             topic.Name,
+			NumPartitions:     max(t.KafkaPartitions, 1),
+			ReplicationFactor: max(t.KafkaReplicationFactor, 1),
+		}
+		if topic.Retention > 0 {
+			cfg.ConfigEntries = []kafka.ConfigEntry{{
+				ConfigName:  "retention.ms",
+				ConfigValue: strconv.FormatInt(topic.Retention.Milliseconds(), 10),
+			}}
+		}
+		configs = append(configs, cfg)
+		result.Created = append(result.Created, "topic "+topic.Name)
+	}
+	if len(configs) == 0 {
+		return result, nil
+	}
+
+	controller, err := conn.Controller()
+	if err != nil {
+		return ProvisionResult{}, fmt.Errorf("pkgmessage: kafka controller: %w", err)
+	}
+
+	cconn, err := k.dialer.DialContext(ctx, "tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		return ProvisionResult{}, fmt.Errorf("pkgmessage: kafka dial controller: %w", err)
+	}
+	defer cconn.Close()
+
+	// another instance may create the same topics concurrently
+	if err := cconn.CreateTopics(configs...); err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+		return ProvisionResult{}, fmt.Errorf("pkgmessage: kafka create topics: %w", err)
+	}
+
+	return result, nil
+}
+
+func (k *Kafka) dialAnyBroker(ctx context.Context) (*kafka.Conn, error) {
+	var errs error
+	for _, broker := range k.brokers {
+		conn, err := k.dialer.DialContext(ctx, "tcp", broker)
+		if err == nil {
+			return conn, nil
+		}
+		errs = errors.Join(errs, err)
+	}
+
+	return nil, fmt.Errorf("pkgmessage: kafka dial: %w", errs)
+}
@@ -18,6 +18,8 @@ var (
 	ErrNATSURLRequired = errors.New("pkgmessage: nats url is required")
 	// ErrNATSHandlerRequired is returned when Consume is called with a nil handler.
 	ErrNATSHandlerRequired = errors.New("pkgmessage: nats handler is required")
+	// ErrNATSAuthConflict is returned when more than one auth method is configured.
+	ErrNATSAuthConflict = errors.New("pkgmessage: nats accepts only one of credentials file, nkey seed file, or user/password")
 )
 
 // NATSConfig configures the NATS implementation.
@@ -27,6 +29,16 @@ type NATSConfig struct {
 
 	// Options are passed to the NATS client.
 	Options []nats.Option
+
+	// TLS configures encrypted server connections.
+	TLS TLSConfig
+	// CredentialsFile is a decentralized auth .creds file (JWT plus nkey seed).
+	CredentialsFile string
+	// NKeySeedFile is a file holding an nkey seed used to sign the server nonce.
+	NKeySeedFile string
+	// Username and Password enable basic auth.
+	Username string
+	Password string
 }
 
 // NATS is a messaging implementation backed by NATS.
@@ -44,7 +56,12 @@ func NewNATS(cfg NATSConfig) (*NATS, error) {
 		return nil, ErrNATSURLRequired
 	}
 
-	conn, err := nats.Connect(cfg.URL, cfg.Options...)
+	secOpts, err := natsSecurityOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := nats.Connect(cfg.URL, append(append([]nats.Option{}, cfg.Options...), secOpts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("pkgmessage: nats connect: %w", err)
 	}
@@ -240,3 +257,39 @@ func autoAckNATS(ctx context.Context, msg interface {
 	}
 	return msg.Nack(ctx)
 }
+
+// natsSecurityOptions converts the TLS and auth settings in cfg into client options.
+func natsSecurityOptions(cfg NATSConfig) ([]nats.Option, error) {
+	var opts []nats.Option
+
+	tlsCfg, err := cfg.TLS.Build()
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, nats.Secure(tlsCfg))
+	}
+
+	methods := 0
+	if cfg.CredentialsFile != "" {
+		methods++
+		opts = append(opts, nats.UserCredentials(cfg.CredentialsFile))
+	}
+	if cfg.NKeySeedFile != "" {
+		methods++
+		opt, err := nats.NkeyOptionFromSeed(cfg.NKeySeedFile)
+		if err != nil {
+			return nil, fmt.Errorf("pkgmessage: nats nkey seed: %w", err)
+		}
+		opts = append(opts, opt)
+	}
+	if cfg.Username != "" {
+		methods++
+		opts = append(opts, nats.UserInfo(cfg.Username, cfg.Password))
+	}
+	if methods > 1 {
+		return nil, ErrNATSAuthConflict
+	}
+
+	return opts, nil
+}
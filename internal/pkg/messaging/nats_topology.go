@@ -0,0 +1,42 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// EnsureTopology creates a JetStream stream, named after the subject, for every missing
+// topic so published messages are kept for the retention period. Consumers keep reading
+// through queue groups, which need no provisioning.
+func (n *NATS) EnsureTopology(ctx context.Context, t Topology) (ProvisionResult, error) {
+	js, err := n.conn.JetStream()
+	if err != nil {
+		return ProvisionResult{}, fmt.Errorf("pkgmessage: nats jetstream: %w", err)
+	}
+
+	var result ProvisionResult
+	for _, topic := range t.Topics {
+		_, err := js.StreamInfo(topic.Name, nats.Context(ctx))
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, nats.ErrStreamNotFound) {
+			return result, fmt.Errorf("pkgmessage: nats stream info %s: %w", topic.Name, err)
+		}
+
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:     topic.Name,
+			Subjects: []string{topic.Name},
+			MaxAge:   topic.Retention,
+		}, nats.Context(ctx))
+		if err != nil && !errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+			return result, fmt.Errorf("pkgmessage: nats add stream %s: %w", topic.Name, err)
+		}
+		result.Created = append(result.Created, "stream "+topic.Name)
+	}
+
+	return result, nil
+}
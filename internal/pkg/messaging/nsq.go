@@ -35,6 +35,9 @@ type NSQConfig struct {
 	// ConsumerLookupdAddrs lists lookupd addresses for consumers.
 	ConsumerLookupdAddrs []string
 
+	// HTTPAddr is the NSQD HTTP address, used to provision topics and channels.
+	HTTPAddr string
+
 	// ProducerConfig overrides the default producer config.
 	ProducerConfig *nsq.Config
 	// ConsumerConfig overrides the default consumer config.
@@ -49,6 +52,8 @@ type NSQ struct {
 	consumerLookupdAddrs []string
 	consumerConfig       *nsq.Config
 
+	httpAddr string
+
 	mu        sync.Mutex
 	consumers []*nsq.Consumer
 	closed    bool
@@ -83,6 +88,8 @@ func NewNSQ(cfg NSQConfig) (*NSQ, error) {
 		consumerNSQDAddrs:    append([]string{}, cfg.ConsumerNSQDAddrs...),
 		consumerLookupdAddrs: append([]string{}, cfg.ConsumerLookupdAddrs...),
 		consumerConfig:       ccfg,
+
+		httpAddr: cfg.HTTPAddr,
 	}, nil
 }
 
@@ -0,0 +1,121 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrNSQHTTPAddrRequired is returned when provisioning without the NSQD HTTP address.
+var ErrNSQHTTPAddrRequired = errors.New("pkgmessage: nsq http address is required")
+
+const nsqHTTPTimeout = 10 * time.Second
+
+// EnsureTopology creates the missing topics and channels through the NSQD HTTP API. NSQ
+// keeps no message history, so retention is ignored.
+func (n *NSQ) EnsureTopology(ctx context.Context, t Topology) (ProvisionResult, error) {
+	if n.httpAddr == "" {
+		return ProvisionResult{}, ErrNSQHTTPAddrRequired
+	}
+
+	client := &http.Client{Timeout: nsqHTTPTimeout}
+
+	existing, err := n.nsqdChannels(ctx, client)
+	if err != nil {
+		return ProvisionResult{}, err
+	}
+
+	var result ProvisionResult
+	for _, topic := range t.Topics {
+		channels, ok := existing[topic.Name]
+		if !ok {
+			if err := n.nsqdPost(ctx, client, "/topic/create", url.Values{"topic": {topic.Name}}); err != nil {
+				return result, err
+			}
+			result.Created = append(result.Created, "topic "+topic.Name)
+		}
+
+		for _, sub := range topic.Subscriptions {
+			if channels[sub] {
+				continue
+			}
+			if err := n.nsqdPost(ctx, client, "/channel/create", url.Values{"topic": {topic.Name}, "channel": {sub}}); err != nil {
+				return result, err
+			}
+			result.Created = append(result.Created, "channel "+topic.Name+"/"+sub)
+		}
+	}
+
+	return result, nil
+}
+
+// nsqdChannels returns the channels of every topic known to NSQD.
+func (n *NSQ) nsqdChannels(ctx context.Context, client *http.Client) (map[string]map[string]bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.nsqdURL("/stats", url.Values{"format": {"json"}}), nil)
+	if err != nil {
+		return nil, fmt.Errorf("pkgmessage: nsq stats request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("pkgmessage: nsq stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pkgmessage: nsq stats: unexpected status %d", resp.StatusCode)
+	}
+
+	var stats struct {
+		Topics []struct {
+			TopicName string `json:"topic_name"`
+			Channels  []struct {
+				ChannelName string `json:"channel_name"`
+			} `json:"channels"`
+		} `json:"topics"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("pkgmessage: nsq decode stats: %w", err)
+	}
+
+	out := make(map[string]map[string]bool, len(stats.Topics))
+	for _, topic := range stats.Topics {
+		channels := make(map[string]bool, len(topic.Channels))
+		for _, ch := range topic.Channels {
+			channels[ch.ChannelName] = true
+		}
+		out[topic.TopicName] = channels
+	}
+
+	return out, nil
+}
+
+func (n *NSQ) nsqdPost(ctx context.Context, client *http.Client, path string, query url.Values) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.nsqdURL(path, query), nil)
+	if err != nil {
+		return fmt.Errorf("pkgmessage: nsq %s request: %w", path, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("pkgmessage: nsq %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("pkgmessage: nsq %s: unexpected status %d", path, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (n *NSQ) nsqdURL(path string, query url.Values) string {
+	u := url.URL{Scheme: "http", Host: n.httpAddr, Path: path, RawQuery: query.Encode()}
+	return u.String()
+}
@@ -0,0 +1,72 @@
+package messaging
+
+import (
+	"context"
+	"maps"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+)
+
+// Propagating decorates a Messaging so the carrier of the publishing context
+// (correlation ID, user, tenant) travels as headers and attributes, and is put
+// back into the context handed to consumers.
+type Propagating struct {
+	Messaging
+}
+
+// NewPropagating wraps next.
+func NewPropagating(next Messaging) *Propagating {
+	return &Propagating{Messaging: next}
+}
+
+// Publish adds the carrier of ctx to msg. Headers and attributes already set by the
+// caller win.
+func (p *Propagating) Publish(ctx context.Context, destination string, msg OutgoingMessage) (PublishResult, error) {
+	carrier := instrument.CarrierFromContext(ctx).Headers()
+	if len(carrier) == 0 {
+		return p.Messaging.Publish(ctx, destination, msg)
+	}
+
+	present := make(map[string]struct{}, len(msg.Headers))
+	for _, h := range msg.Headers {
+		present[h.Key] = struct{}{}
+	}
+
+	msg.Headers = append([]Header(nil), msg.Headers...)
+	msg.Attributes = maps.Clone(msg.Attributes)
+	if msg.Attributes == nil {
+		msg.Attributes = make(map[string]string, len(carrier))
+	}
+
+	for key, value := range carrier {
+		if _, ok := present[key]; !ok {
+			msg.Headers = append(msg.Headers, Header{Key: key, Value: []byte(value)})
+		}
+		if _, ok := msg.Attributes[key]; !ok {
+			msg.Attributes[key] = value
+		}
+	}
+
+	return p.Messaging.Publish(ctx, destination, msg)
+}
+
+// Consume restores the carrier of every message into the context passed to handler.
+func (p *Propagating) Consume(ctx context.Context, source string, handler Handler, opts ...ConsumeOption) error {
+	return p.Messaging.Consume(ctx, source, func(ctx context.Context, msg Message) error {
+		return handler(carrierOf(msg).Context(ctx), msg)
+	}, opts...)
+}
+
+func carrierOf(msg Message) instrument.Carrier {
+	headers := msg.Headers()
+	attrs := msg.Attributes()
+
+	return instrument.ExtractCarrier(func(key string) string {
+		for i := range headers {
+			if headers[i].Key == key {
+				return string(headers[i].Value)
+			}
+		}
+		return attrs[key]
+	})
+}
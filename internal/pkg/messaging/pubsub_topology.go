@@ -0,0 +1,71 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/pubsub/v2/apiv1/pubsubpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// pubSubMaxSubscriptionRetention is the longest retention Pub/Sub accepts on a subscription.
+const pubSubMaxSubscriptionRetention = 7 * 24 * time.Hour
+
+// EnsureTopology creates the missing topics and subscriptions in the client's project.
+// Subscription retention is capped by Pub/Sub at seven days; longer retention is kept on
+// the topic only.
+func (p *PubSub) EnsureTopology(ctx context.Context, t Topology) (ProvisionResult, error) {
+	if err := p.ensurePubSubOpen(); err != nil {
+		return ProvisionResult{}, err
+	}
+
+	project := p.client.Project()
+
+	var result ProvisionResult
+	for _, topic := range t.Topics {
+		topicName := fmt.Sprintf("projects/%s/topics/%s", project, topic.Name)
+
+		_, err := p.client.TopicAdminClient.GetTopic(ctx, &pubsubpb.GetTopicRequest{Topic: topicName})
+		if status.Code(err) == codes.NotFound {
+			req := &pubsubpb.Topic{Name: topicName}
+			if topic.Retention > 0 {
+				req.MessageRetentionDuration = durationpb.New(topic.Retention)
+			}
+			_, err = p.client.TopicAdminClient.CreateTopic(ctx, req)
+			if status.Code(err) == codes.AlreadyExists {
+				err = nil
+			} else if err == nil {
+				result.Created = append(result.Created, "topic "+topic.Name)
+			}
+		}
+		if err != nil {
+			return result, fmt.Errorf("pkgmessage: pubsub ensure topic %s: %w", topic.Name, err)
+		}
+
+		for _, sub := range topic.Subscriptions {
+			subName := fmt.Sprintf("projects/%s/subscriptions/%s", project, sub)
+
+			_, err := p.client.SubscriptionAdminClient.GetSubscription(ctx, &pubsubpb.GetSubscriptionRequest{Subscription: subName})
+			if status.Code(err) == codes.NotFound {
+				req := &pubsubpb.Subscription{Name: subName, Topic: topicName}
+				if topic.Retention > 0 {
+					req.MessageRetentionDuration = durationpb.New(min(topic.Retention, pubSubMaxSubscriptionRetention))
+				}
+				_, err = p.client.SubscriptionAdminClient.CreateSubscription(ctx, req)
+				if status.Code(err) == codes.AlreadyExists {
+					err = nil
+				} else if err == nil {
+					result.Created = append(result.Created, "subscription "+sub)
+				}
+			}
+			if err != nil {
+				return result, fmt.Errorf("pkgmessage: pubsub ensure subscription %s: %w", sub, err)
+			}
+		}
+	}
+
+	return result, nil
+}
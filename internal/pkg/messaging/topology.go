@@ -0,0 +1,58 @@
+package messaging
+
+import (
+	"context"
+	"time"
+)
+
+// Topic declares a destination and the subscriptions reading it, for brokers that need
+// them to exist before messages are published.
+type Topic struct {
+	// Name is the destination messages are published to.
+	Name string
+
+	// Subscriptions are the consumer names reading Name. They become NSQ channels and
+	// Pub/Sub subscriptions; Kafka groups and NATS queue groups need no provisioning.
+	Subscriptions []string
+
+	// Retention is how long the broker keeps messages; 0 keeps the broker default.
+	Retention time.Duration
+}
+
+// Topology is the declared set of topics of an application.
+type Topology struct {
+	Topics []Topic
+
+	// KafkaPartitions is the partition count of new Kafka topics; 0 means 1.
+	KafkaPartitions int
+	// KafkaReplicationFactor is the replication factor of new Kafka topics; 0 means 1.
+	KafkaReplicationFactor int
+}
+
+// ProvisionResult describes what a provisioning run created.
+type ProvisionResult struct {
+	// Created names every resource that was missing, e.g. "topic user_registration".
+	Created []string
+}
+
+// Provisioner is implemented by backends that can create topics and subscriptions ahead
+// of use. Missing resources are created and existing ones are left untouched, so running
+// it again is safe.
+type Provisioner interface {
+	EnsureTopology(ctx context.Context, t Topology) (ProvisionResult, error)
+}
+
+// EnsureTopology provisions t on m, returning ErrUnsupported when m cannot do it.
+func EnsureTopology(ctx context.Context, m Messaging, t Topology) (ProvisionResult, error) {
+	p, ok := m.(Provisioner)
+	if !ok {
+		return ProvisionResult{}, ErrUnsupported
+	}
+
+	return p.EnsureTopology(ctx, t)
+}
+
+// EnsureTopology provisions t on the wrapped backend.
+func (p *Propagating) EnsureTopology(ctx context.Context, t Topology) (ProvisionResult, error) {
+	return EnsureTopology(ctx, p.Messaging, t)
+}
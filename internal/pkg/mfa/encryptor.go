@@ -7,11 +7,3 @@ type Encryptor interface {
 	// Decrypt returns plaintext for the given ciphertext and scope.
 	Decrypt(ciphertext []byte, scope Scope) (plaintext []byte, err error)
 }
-
-// KeyProvider provides raw AES keys.
-// For AES-256-GCM, keys must be 32 bytes.
-type KeyProvider interface {
-	// Key returns the raw AES key to use for this scope.
-	// You may choose to return per-tenant keys, per-environment keys, etc.
-	Key(scope Scope) ([]byte, error)
-}
@@ -1,13 +1,17 @@
 package mfa
 
+import "github.com/shandysiswandi/gobite/internal/pkg/crypto"
+
 // Purpose identifies the MFA encryption purpose.
-type Purpose string
+type Purpose = crypto.Purpose
 
 const (
 	// PurposeOTPSeed scopes encryption to OTP seeds.
-	PurposeOTPSeed Purpose = "otp_seed"
+	PurposeOTPSeed = crypto.PurposeOTPSeed
 	// PurposeRecoveryKey scopes encryption to recovery keys.
-	PurposeRecoveryKey Purpose = "recovery_key"
+	PurposeRecoveryKey = crypto.PurposeRecoveryKey
+	// PurposePhone scopes encryption to phone numbers of SMS factors.
+	PurposePhone = crypto.PurposePhone
 )
 
 // Scope binds encryption to MFA-specific identifiers.
@@ -0,0 +1,102 @@
+// Package passwordpolicy checks chosen passwords against configurable composition rules.
+// Reuse of earlier passwords needs their hashes and is left to the caller.
+package passwordpolicy
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrTooShort is returned when the password has fewer than MinLength characters.
+	ErrTooShort = errors.New("password is too short")
+	// ErrMissingLower is returned when a lowercase letter is required but missing.
+	ErrMissingLower = errors.New("password must contain a lowercase letter")
+	// ErrMissingUpper is returned when an uppercase letter is required but missing.
+	ErrMissingUpper = errors.New("password must contain an uppercase letter")
+	// ErrMissingDigit is returned when a digit is required but missing.
+	ErrMissingDigit = errors.New("password must contain a digit")
+	// ErrMissingSymbol is returned when a symbol is required but missing.
+	ErrMissingSymbol = errors.New("password must contain a symbol")
+	// ErrContainsEmail is returned when the password contains a part of the email address.
+	ErrContainsEmail = errors.New("password must not contain your email address")
+)
+
+// minEmailPart is the shortest part of an email local part that is looked for in the
+// password; shorter parts match too many passwords by chance.
+const minEmailPart = 4
+
+// Policy is a set of composition rules. The zero value accepts every password.
+type Policy struct {
+	// MinLength is the minimum number of characters.
+	MinLength int
+
+	RequireLower  bool
+	RequireUpper  bool
+	RequireDigit  bool
+	RequireSymbol bool
+
+	// DisallowEmail rejects passwords containing the email local part or one of its
+	// dot, dash, underscore or plus separated parts, ignoring case.
+	DisallowEmail bool
+}
+
+// Check returns the first rule password breaks, or nil when it satisfies the policy.
+func (p Policy) Check(password, email string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return ErrTooShort
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	switch {
+	case p.RequireLower && !lower:
+		return ErrMissingLower
+	case p.RequireUpper && !upper:
+		return ErrMissingUpper
+	case p.RequireDigit && !digit:
+		return ErrMissingDigit
+	case p.RequireSymbol && !symbol:
+		return ErrMissingSymbol
+	}
+
+	if p.DisallowEmail && containsEmail(password, email) {
+		return ErrContainsEmail
+	}
+
+	return nil
+}
+
+func containsEmail(password, email string) bool {
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+
+	password = strings.ToLower(password)
+	if utf8.RuneCountInString(local) >= minEmailPart && strings.Contains(password, local) {
+		return true
+	}
+
+	parts := strings.FieldsFunc(local, func(r rune) bool {
+		return r == '.' || r == '-' || r == '_' || r == '+'
+	})
+	for _, part := range parts {
+		if utf8.RuneCountInString(part) >= minEmailPart && strings.Contains(password, part) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package pgxguard
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shandysiswandi/gobite/internal/pkg/stmtdeadline"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// statementTimeoutKey marks, in the connection's custom data, a session whose
+// statement_timeout was changed from the server default.
+const statementTimeoutKey = "pgxguard.statement_timeout"
+
+// deadline returns the earliest of the context deadline and the statement deadline of ctx,
+// see stmtdeadline.With.
+func deadline(ctx context.Context) (time.Time, bool) {
+	dl, ok := ctx.Deadline()
+
+	if at, found := stmtdeadline.From(ctx); found && (!ok || at.Before(dl)) {
+		return at, true
+	}
+
+	return dl, ok
+}
+
+// applyStatementTimeout sets statement_timeout on conn to the time left before the deadline
+// of ctx. Without a deadline it puts back the server default, but only on a connection an
+// earlier caller changed, so calls without deadlines cost no extra round trip.
+func (p *Pool) applyStatementTimeout(ctx context.Context, conn *pgxpool.Conn) error {
+	if !p.propagateDeadline {
+		return nil
+	}
+
+	data := conn.Conn().PgConn().CustomData()
+
+	dl, ok := deadline(ctx)
+	if !ok {
+		if _, changed := data[statementTimeoutKey]; !changed {
+			return nil
+		}
+		if _, err := conn.Exec(ctx, "RESET statement_timeout"); err != nil {
+			return err
+		}
+		delete(data, statementTimeoutKey)
+		return nil
+	}
+
+	left := time.Until(dl)
+	if left < time.Millisecond {
+		p.rejected.Add(ctx, 1, p.attrs, metric.WithAttributes(attribute.String("reason", "deadline")))
+		return ErrDeadlineExceeded
+	}
+
+	// the setting is in whole milliseconds and 0 would disable it, so round up
+	ms := (left + time.Millisecond - 1) / time.Millisecond
+	if _, err := conn.Exec(ctx, "SET statement_timeout = "+strconv.FormatInt(int64(ms), 10)); err != nil {
+		return err
+	}
+	data[statementTimeoutKey] = struct{}{}
+
+	return nil
+}
+
+// CancelOnContextDone makes connections built from cfg ask Postgres to cancel the running
+// query when its context ends, instead of only dropping the connection. The default leaves
+// the server executing a query whose caller is gone until it tries to send the result.
+func CancelOnContextDone(cfg *pgxpool.Config) {
+	cfg.ConnConfig.BuildContextWatcherHandler = func(pgConn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{
+			Conn: pgConn,
+			// give the server time to answer the cancel before the connection is given up on
+			DeadlineDelay: time.Second,
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package pgxguard wraps a pgx pool with connection pool metrics and a circuit breaker
+// that fails fast when the pool is saturated. It can also bound each call to the
+// caller's deadline through the Postgres statement_timeout.
+package pgxguard
@@ -0,0 +1,14 @@
+package pgxguard
+
+import "github.com/shandysiswandi/gobite/internal/pkg/goerror"
+
+var (
+	// ErrPoolSaturated indicates no connection became free within the acquire timeout.
+	ErrPoolSaturated = goerror.NewBusiness("Database is busy, please try again later", goerror.CodeTimeout)
+
+	// ErrCircuitOpen indicates calls are failing fast because the pool recently kept saturating.
+	ErrCircuitOpen = goerror.NewBusiness("Database is temporarily unavailable, please try again later", goerror.CodeTimeout)
+
+	// ErrDeadlineExceeded indicates the caller's deadline passed before the call could be sent.
+	ErrDeadlineExceeded = goerror.NewBusiness("Request took too long, please try again later", goerror.CodeTimeout)
+)
@@ -0,0 +1,282 @@
+package pgxguard
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shandysiswandi/gobite/internal/pkg/resilience"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Config tunes how a guarded pool reacts to saturation.
+type Config struct {
+	// AcquireTimeout bounds how long a call waits for a free connection.
+	AcquireTimeout time.Duration
+	// Breaker configures the circuit breaker fed by acquire failures.
+	Breaker resilience.BreakerConfig
+	// PropagateDeadline sets statement_timeout to the time left before the caller's
+	// deadline on every call; see stmtdeadline.With.
+	PropagateDeadline bool
+}
+
+// Pool is a pgx pool guarded by a circuit breaker.
+//
+// It satisfies the sqlc DBTX interface and exposes Begin/BeginTx, so it can be
+// dropped in wherever a *pgxpool.Pool was used for queries and transactions.
+type Pool struct {
+	pool              *pgxpool.Pool
+	breaker           *resilience.CircuitBreaker
+	acquireTimeout    time.Duration
+	propagateDeadline bool
+	rejected          metric.Int64Counter
+	attrs             metric.MeasurementOption
+}
+
+// New wraps pool and registers its stats as metrics on meter under the given name.
+func New(name string, pool *pgxpool.Pool, meter metric.Meter, cfg Config) (*Pool, error) {
+	if cfg.AcquireTimeout <= 0 {
+		cfg.AcquireTimeout = time.Second
+	}
+
+	p := &Pool{
+		pool:              pool,
+		breaker:           resilience.NewCircuitBreaker(cfg.Breaker),
+		acquireTimeout:    cfg.AcquireTimeout,
+		propagateDeadline: cfg.PropagateDeadline,
+		attrs:             metric.WithAttributes(attribute.String("db.pool.name", name)),
+	}
+
+	if err := p.registerMetrics(meter); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Unwrap returns the underlying pool for callers that need the raw driver.
+func (p *Pool) Unwrap() *pgxpool.Pool {
+	return p.pool
+}
+
+// Breaker returns the circuit breaker guarding the pool.
+func (p *Pool) Breaker() *resilience.CircuitBreaker {
+	return p.breaker
+}
+
+// acquire takes a connection, waiting at most the acquire timeout, and bounds it to the
+// deadline of ctx.
+//
+// Only acquire failures feed the breaker: query errors mean the database answered,
+// while failing to get a connection means it is saturated or unreachable.
+func (p *Pool) acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	if err := p.breaker.Allow(); err != nil {
+		p.rejected.Add(ctx, 1, p.attrs, metric.WithAttributes(attribute.String("reason", "circuit_open")))
+		return nil, ErrCircuitOpen
+	}
+
+	actx, cancel := context.WithTimeout(ctx, p.acquireTimeout)
+	defer cancel()
+
+	conn, err := p.pool.Acquire(actx)
+	if err == nil {
+		p.breaker.Success()
+		if err := p.applyStatementTimeout(ctx, conn); err != nil {
+			conn.Release()
+			return nil, err
+		}
+		return conn, nil
+	}
+
+	// the caller gave up on its own, which says nothing about the pool either way
+	if ctx.Err() != nil {
+		p.breaker.Cancel()
+		return nil, err
+	}
+
+	p.breaker.Failure()
+	if errors.Is(err, context.DeadlineExceeded) {
+		p.rejected.Add(ctx, 1, p.attrs, metric.WithAttributes(attribute.String("reason", "saturated")))
+		return nil, ErrPoolSaturated
+	}
+
+	return nil, err
+}
+
+// Exec acquires a connection and executes sql on it.
+func (p *Pool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	defer conn.Release()
+
+	return conn.Exec(ctx, sql, args...)
+}
+
+// Query acquires a connection and runs sql on it; the connection is released when the rows are closed.
+func (p *Pool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+
+	return &releaseRows{Rows: rows, conn: conn}, nil
+}
+
+// QueryRow acquires a connection and runs sql on it; the connection is released on Scan.
+func (p *Pool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return errRow{err: err}
+	}
+
+	return &releaseRow{row: conn.QueryRow(ctx, sql, args...), conn: conn}
+}
+
+// CopyFrom acquires a connection and copies rows into tableName.
+func (p *Pool) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	return conn.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+// Begin starts a transaction with default options.
+func (p *Pool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return p.BeginTx(ctx, pgx.TxOptions{})
+}
+
+// BeginTx acquires a connection and starts a transaction; the connection is released when the transaction ends.
+func (p *Pool) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.BeginTx(ctx, txOptions)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+
+	return &releaseTx{Tx: tx, conn: conn}, nil
+}
+
+func (p *Pool) registerMetrics(meter metric.Meter) error {
+	var err error
+	p.rejected, err = meter.Int64Counter("db.pool.rejected",
+		metric.WithDescription("Database calls rejected by the pool guard"))
+	if err != nil {
+		return err
+	}
+
+	gauge := func(name, desc string) metric.Int64ObservableGauge {
+		if err != nil {
+			return nil
+		}
+		var g metric.Int64ObservableGauge
+		g, err = meter.Int64ObservableGauge(name, metric.WithDescription(desc))
+		return g
+	}
+	counter := func(name, desc string) metric.Int64ObservableCounter {
+		if err != nil {
+			return nil
+		}
+		var c metric.Int64ObservableCounter
+		c, err = meter.Int64ObservableCounter(name, metric.WithDescription(desc))
+		return c
+	}
+
+	acquired := gauge("db.pool.acquired_conns", "Connections currently checked out")
+	idle := gauge("db.pool.idle_conns", "Idle connections in the pool")
+	total := gauge("db.pool.total_conns", "Open connections in the pool")
+	maxConns := gauge("db.pool.max_conns", "Maximum size of the pool")
+	constructing := gauge("db.pool.constructing_conns", "Connections being established")
+	breakerState := gauge("db.pool.breaker_state", "Circuit breaker state (0 closed, 1 open, 2 half-open)")
+	acquires := counter("db.pool.acquires", "Successful connection acquires")
+	emptyAcquires := counter("db.pool.empty_acquires", "Acquires that had to wait for a connection")
+	canceledAcquires := counter("db.pool.canceled_acquires", "Acquires canceled before a connection was available")
+	acquireDuration := counter("db.pool.acquire_duration_ms", "Total time spent acquiring connections")
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stat := p.pool.Stat()
+		o.ObserveInt64(acquired, int64(stat.AcquiredConns()), p.attrs)
+		o.ObserveInt64(idle, int64(stat.IdleConns()), p.attrs)
+		o.ObserveInt64(total, int64(stat.TotalConns()), p.attrs)
+		o.ObserveInt64(maxConns, int64(stat.MaxConns()), p.attrs)
+		o.ObserveInt64(constructing, int64(stat.ConstructingConns()), p.attrs)
+		o.ObserveInt64(breakerState, int64(p.breaker.State()), p.attrs)
+		o.ObserveInt64(acquires, stat.AcquireCount(), p.attrs)
+		o.ObserveInt64(emptyAcquires, stat.EmptyAcquireCount(), p.attrs)
+		o.ObserveInt64(canceledAcquires, stat.CanceledAcquireCount(), p.attrs)
+		o.ObserveInt64(acquireDuration, stat.AcquireDuration().Milliseconds(), p.attrs)
+		return nil
+	}, acquired, idle, total, maxConns, constructing, breakerState, acquires, emptyAcquires, canceledAcquires, acquireDuration)
+
+	return err
+}
+
+type releaseRows struct {
+	pgx.Rows
+	conn *pgxpool.Conn
+	once sync.Once
+}
+
+func (r *releaseRows) Close() {
+	r.Rows.Close()
+	r.once.Do(r.conn.Release)
+}
+
+type releaseRow struct {
+	row  pgx.Row
+	conn *pgxpool.Conn
+}
+
+func (r *releaseRow) Scan(dest ...any) error {
+	defer r.conn.Release()
+	return r.row.Scan(dest...)
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
+
+type releaseTx struct {
+	pgx.Tx
+	conn *pgxpool.Conn
+	once sync.Once
+}
+
+func (t *releaseTx) Commit(ctx context.Context) error {
+	err := t.Tx.Commit(ctx)
+	t.once.Do(t.conn.Release)
+	return err
+}
+
+func (t *releaseTx) Rollback(ctx context.Context) error {
+	err := t.Tx.Rollback(ctx)
+	t.once.Do(t.conn.Release)
+	return err
+}
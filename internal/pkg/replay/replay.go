@@ -0,0 +1,39 @@
+// Package replay provides a Redis-backed record of values that may be used once, such as
+// SAML assertion IDs and request signature nonces, so a captured message cannot be played
+// back while it is still valid.
+package replay
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Guard remembers values already used.
+type Guard interface {
+	// Use records value in scope until ttl passes and reports whether it was unused. A value
+	// whose ttl has already passed is reported unused without being recorded; the message
+	// carrying it is rejected as expired anyway.
+	Use(ctx context.Context, scope, value string, ttl time.Duration) (bool, error)
+}
+
+type Store struct {
+	client *redis.Client
+	prefix string
+}
+
+func New(client *redis.Client) *Store {
+	return &Store{
+		client: client,
+		prefix: "replay:",
+	}
+}
+
+func (s *Store) Use(ctx context.Context, scope, value string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		return true, nil
+	}
+
+	return s.client.SetNX(ctx, s.prefix+scope+":"+value, 1, ttl).Result()
+}
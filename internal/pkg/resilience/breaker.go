@@ -0,0 +1,144 @@
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen indicates the breaker is rejecting calls without trying the dependency.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// State is the current position of a circuit breaker.
+type State int
+
+const (
+	// StateClosed lets every call through.
+	StateClosed State = iota
+	// StateOpen rejects every call until the open timeout elapses.
+	StateOpen
+	// StateHalfOpen lets a single probe through to decide whether to close again.
+	StateHalfOpen
+)
+
+// String returns the string representation of the state.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig tunes when a breaker opens and how long it stays open.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before letting a probe through.
+	OpenTimeout time.Duration
+}
+
+// CircuitBreaker is a consecutive-failure circuit breaker safe for concurrent use.
+type CircuitBreaker struct {
+	cfg BreakerConfig
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a closed breaker, applying defaults to zero config values.
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 10 * time.Second
+	}
+
+	return &CircuitBreaker{cfg: cfg, now: time.Now}
+}
+
+// Allow reports whether a call may proceed, returning ErrCircuitOpen when it may not.
+//
+// Every allowed call must be followed by Success, Failure or Cancel.
+func (cb *CircuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case StateOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cfg.OpenTimeout {
+			return ErrCircuitOpen
+		}
+		cb.state = StateHalfOpen
+		cb.probing = true
+		return nil
+	case StateHalfOpen:
+		if cb.probing {
+			return ErrCircuitOpen
+		}
+		cb.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success records a successful call and closes the breaker.
+func (cb *CircuitBreaker) Success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = StateClosed
+	cb.failures = 0
+	cb.probing = false
+}
+
+// Failure records a failed call, opening the breaker once the threshold is reached
+// or immediately when the failed call was a half-open probe.
+func (cb *CircuitBreaker) Failure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+	if cb.state == StateHalfOpen || cb.failures >= cb.cfg.FailureThreshold {
+		cb.state = StateOpen
+		cb.openedAt = cb.now()
+		cb.probing = false
+	}
+}
+
+// Cancel releases an allowed call that was abandoned by its caller without recording an
+// outcome, so a canceled half-open probe neither closes nor reopens the breaker.
+func (cb *CircuitBreaker) Cancel() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+}
+
+// Done records the outcome of a call from its error.
+func (cb *CircuitBreaker) Done(err error) {
+	if err != nil {
+		cb.Failure()
+		return
+	}
+	cb.Success()
+}
+
+// State returns the current state of the breaker.
+func (cb *CircuitBreaker) State() State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return cb.state
+}
@@ -0,0 +1,3 @@
+// Package resilience provides circuit breaker, bounded retry, and timeout primitives
+// that keep a degraded dependency from stalling or cascading into its callers.
+package resilience
@@ -0,0 +1,62 @@
+package resilience
+
+import (
+	"context"
+	"time"
+)
+
+// Policy combines a per-attempt timeout, bounded retry, and a circuit breaker
+// around calls to a single outbound dependency.
+type Policy struct {
+	timeout time.Duration
+	retry   RetryConfig
+	breaker *CircuitBreaker
+}
+
+// PolicyConfig configures a Policy.
+type PolicyConfig struct {
+	// Timeout bounds a single attempt; zero disables it.
+	Timeout time.Duration
+	Retry   RetryConfig
+	Breaker BreakerConfig
+}
+
+// NewPolicy creates a Policy with its own circuit breaker.
+func NewPolicy(cfg PolicyConfig) *Policy {
+	return &Policy{
+		timeout: cfg.Timeout,
+		retry:   cfg.Retry,
+		breaker: NewCircuitBreaker(cfg.Breaker),
+	}
+}
+
+// Breaker returns the circuit breaker shared by every call made through the policy.
+func (p *Policy) Breaker() *CircuitBreaker {
+	return p.breaker
+}
+
+// Do runs fn under the policy. Permanent errors and errors caused by the caller's
+// own context are returned without counting against the breaker.
+func (p *Policy) Do(ctx context.Context, fn func(context.Context) error) error {
+	return Retry(ctx, p.retry, func(ctx context.Context) error {
+		if err := p.breaker.Allow(); err != nil {
+			return err
+		}
+
+		err := Timeout(ctx, p.timeout, fn)
+		switch {
+		case err == nil:
+			p.breaker.Success()
+		case ctx.Err() != nil:
+			// the caller gave up, which says nothing about the dependency either way
+			p.breaker.Cancel()
+		case IsPermanent(err):
+			// the dependency answered, so it is reachable even though it refused the call
+			p.breaker.Success()
+		default:
+			p.breaker.Failure()
+		}
+
+		return err
+	})
+}
@@ -0,0 +1,79 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryConfig bounds how often and how fast a failed call is retried.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry; it doubles on every retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between two attempts.
+	MaxDelay time.Duration
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying, e.g. a rejected recipient.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var perr *permanentError
+	return errors.As(err, &perr)
+}
+
+// Retry calls fn until it succeeds, returns a permanent error, the breaker opens,
+// ctx is done, or the attempts run out. The last error is returned.
+func Retry(ctx context.Context, cfg RetryConfig, fn func(context.Context) error) error {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn(ctx)
+		if err == nil || IsPermanent(err) || errors.Is(err, ErrCircuitOpen) || attempt >= cfg.MaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff(cfg, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the exponential delay before the next attempt with up to 20% jitter.
+func backoff(cfg RetryConfig, attempt int) time.Duration {
+	if cfg.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := cfg.BaseDelay << (attempt - 1)
+	if delay <= 0 || (cfg.MaxDelay > 0 && delay > cfg.MaxDelay) {
+		delay = cfg.MaxDelay
+	}
+
+	// #nosec G404 -- jitter does not need a cryptographic source.
+	return delay - time.Duration(rand.Int64N(int64(delay)/5+1))
+}
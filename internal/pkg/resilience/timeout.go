@@ -0,0 +1,37 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTimeout indicates a call did not finish within its timeout.
+var ErrTimeout = errors.New("call timed out")
+
+// Timeout runs fn with a deadline and stops waiting once it passes, even when fn
+// ignores its context (e.g. net/smtp). Such a call keeps running in the background
+// but no longer blocks the caller.
+func Timeout(ctx context.Context, d time.Duration, fn func(context.Context) error) error {
+	if d <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return ErrTimeout
+		}
+		return ctx.Err()
+	}
+}
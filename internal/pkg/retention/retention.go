@@ -0,0 +1,188 @@
+// Package retention runs the periodic cleanup of expired rows for every module
+// from one schedule, driven by the per-table windows under the retention config.
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/clock"
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+	"github.com/shandysiswandi/gobite/internal/pkg/jobs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	defaultBatchSize  = 1000
+	defaultMaxBatches = 10
+)
+
+// Target is one table a module keeps under a retention window.
+type Target struct {
+	// Table names the table and is the key of its window under retention.tables.
+	Table string
+	// Count returns how many rows are older than before.
+	Count func(ctx context.Context, before time.Time) (int64, error)
+	// Purge removes at most limit rows older than before and returns how many it removed.
+	Purge func(ctx context.Context, before time.Time, limit int32) (int64, error)
+}
+
+// Report describes one target in one run.
+type Report struct {
+	Table    string
+	Before   time.Time
+	DryRun   bool
+	Matched  int64
+	Purged   int64
+	Duration time.Duration
+	Err      error
+}
+
+// Scheduler collects the targets registered by modules and purges them on an interval.
+type Scheduler struct {
+	cfg   config.Config
+	clock clock.Clocker
+	ins   instrument.Instrumentation
+
+	mu      sync.Mutex
+	targets []Target
+}
+
+// NewScheduler returns a Scheduler without targets.
+func NewScheduler(cfg config.Config, clk clock.Clocker, ins instrument.Instrumentation) *Scheduler {
+	return &Scheduler{cfg: cfg, clock: clk, ins: ins}
+}
+
+// Register adds targets to every following run.
+func (s *Scheduler) Register(targets ...Target) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.targets = append(s.targets, targets...)
+}
+
+// Job returns the retention run as a job of retention.interval_minutes, unscheduled when
+// retention is disabled. A run fails when any target failed; the others still ran.
+func (s *Scheduler) Job() jobs.Job {
+	interval := s.cfg.GetMinute("retention.interval_minutes")
+	if !s.cfg.GetBool("retention.enabled") {
+		interval = 0
+	}
+
+	return jobs.Job{
+		Name:     "retention",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			var errs []error
+			for _, r := range s.Run(ctx) {
+				if r.Err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", r.Table, r.Err))
+				}
+			}
+
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// Run applies the retention window of every registered target once. Targets without
+// a positive window are skipped, and a failing target does not stop the others.
+func (s *Scheduler) Run(ctx context.Context) []Report {
+	s.mu.Lock()
+	targets := append([]Target(nil), s.targets...)
+	s.mu.Unlock()
+
+	reports := make([]Report, 0, len(targets))
+	for _, t := range targets {
+		days := s.cfg.GetDay("retention.tables." + t.Table + ".days")
+		if days <= 0 {
+			continue
+		}
+
+		report := s.run(ctx, t, s.clock.Now().Add(-days))
+		s.record(ctx, report)
+		reports = append(reports, report)
+	}
+
+	return reports
+}
+
+func (s *Scheduler) run(ctx context.Context, t Target, before time.Time) Report {
+	start := s.clock.Now()
+	report := Report{
+		Table:  t.Table,
+		Before: before,
+		DryRun: s.cfg.GetBool("retention.dry_run") || s.cfg.GetBool("retention.tables."+t.Table+".dry_run"),
+	}
+
+	defer func() { report.Duration = s.clock.Now().Sub(start) }()
+
+	report.Matched, report.Err = t.Count(ctx, before)
+	if report.Err != nil || report.DryRun || report.Matched == 0 {
+		return report
+	}
+
+	batchSize := s.cfg.GetInt32("retention.batch_size")
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	maxBatches := s.cfg.GetInt("retention.max_batches")
+	if maxBatches <= 0 {
+		maxBatches = defaultMaxBatches
+	}
+
+	for range maxBatches {
+		purged, err := t.Purge(ctx, before, batchSize)
+		report.Purged += purged
+		if err != nil {
+			report.Err = err
+			break
+		}
+
+		if purged < int64(batchSize) {
+			break
+		}
+	}
+
+	return report
+}
+
+func (s *Scheduler) record(ctx context.Context, r Report) {
+	args := []any{"table", r.Table, "before", r.Before, "matched", r.Matched, "duration", r.Duration.String()}
+
+	switch {
+	case r.Err != nil:
+		slog.ErrorContext(ctx, "retention purge failed", append(args, "purged", r.Purged, "error", r.Err)...)
+	case r.DryRun:
+		slog.InfoContext(ctx, "retention dry run", args...)
+	case r.Purged > 0:
+		slog.InfoContext(ctx, "retention purged rows", append(args, "purged", r.Purged)...)
+	}
+
+	meter := s.ins.Meter("retention")
+	table := metric.WithAttributes(attribute.String("table", r.Table))
+
+	if counter, err := meter.Int64Counter("retention.rows.matched",
+		metric.WithDescription("Number of rows found past their retention window, by table")); err == nil {
+		counter.Add(ctx, r.Matched, table)
+	}
+
+	if counter, err := meter.Int64Counter("retention.rows.purged",
+		metric.WithDescription("Number of rows removed by the retention scheduler, by table")); err == nil {
+		counter.Add(ctx, r.Purged, table)
+	}
+
+	if r.Err != nil {
+		if counter, err := meter.Int64Counter("retention.failures",
+			metric.WithDescription("Number of failed retention runs, by table")); err == nil {
+			counter.Add(ctx, 1, table)
+		}
+	}
+}
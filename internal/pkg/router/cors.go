@@ -0,0 +1,277 @@
+package router
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rs/cors"
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+)
+
+// CORS applies the cross-origin policy of app.server.cors. Each route uses the policy of its
+// longest matching prefix in app.server.cors.routes, or the default origins. The policies are
+// rebuilt by Watch when the config changes, without a restart.
+type CORS struct {
+	cfg     config.Config
+	current atomic.Pointer[corsSnapshot]
+}
+
+type corsSnapshot struct {
+	fingerprint string
+	fallback    *cors.Cors
+	routes      []corsRoute
+}
+
+type corsRoute struct {
+	prefix string
+	policy *cors.Cors
+}
+
+// NewCORS builds the CORS policies from cfg.
+func NewCORS(cfg config.Config) *CORS {
+	c := &CORS{cfg: cfg}
+	c.reload()
+
+	return c
+}
+
+// Handler wraps next with the policy of each request path.
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.current.Load().policyFor(r.URL.Path).ServeHTTP(w, r, next.ServeHTTP)
+	})
+}
+
+// Reload re-reads the CORS config and swaps in new policies when it changed.
+func (c *CORS) Reload(ctx context.Context) error {
+	if c.reload() {
+		slog.InfoContext(ctx, "cors policies reloaded")
+	}
+
+	return nil
+}
+
+// reload rebuilds the snapshot when the config differs from the loaded one.
+func (c *CORS) reload() bool {
+	origins := c.cfg.GetString("app.server.cors.origins")
+	if origins == "" {
+		// the single origin list app.server.cors held before policies existed
+		origins = c.cfg.GetString("app.server.cors")
+	}
+	routes := c.cfg.GetMap("app.server.cors.routes")
+
+	policies := map[string]string{}
+	for _, name := range routes {
+		name = strings.TrimSpace(name)
+		policies[name] = c.cfg.GetString("app.server.cors." + name + "_origins")
+	}
+
+	var fp strings.Builder
+	fp.WriteString(origins)
+	for _, prefix := range sortedKeys(routes) {
+		fp.WriteString("\x00" + prefix + "=" + routes[prefix])
+	}
+	for _, name := range sortedKeys(policies) {
+		fp.WriteString("\x00" + name + "=" + policies[name])
+	}
+
+	if old := c.current.Load(); old != nil && old.fingerprint == fp.String() {
+		return false
+	}
+
+	fallback := newCORSPolicy(splitOrigins(origins))
+	snap := &corsSnapshot{fingerprint: fp.String(), fallback: fallback}
+	built := map[string]*cors.Cors{}
+	for prefix, name := range routes {
+		prefix, name = strings.TrimSpace(prefix), strings.TrimSpace(name)
+		if prefix == "" || name == "" {
+			continue
+		}
+
+		policy, ok := built[name]
+		if !ok {
+			policy = fallback
+			// a policy without its own origins keeps the default ones
+			if list := splitOrigins(policies[name]); len(list) > 0 {
+				policy = newCORSPolicy(list)
+			}
+			built[name] = policy
+		}
+		snap.routes = append(snap.routes, corsRoute{prefix: prefix, policy: policy})
+	}
+	sort.Slice(snap.routes, func(i, j int) bool { return len(snap.routes[i].prefix) > len(snap.routes[j].prefix) })
+
+	c.current.Store(snap)
+
+	return true
+}
+
+func (s *corsSnapshot) policyFor(path string) *cors.Cors {
+	for _, route := range s.routes {
+		if path == route.prefix || strings.HasPrefix(path, strings.TrimSuffix(route.prefix, "/")+"/") {
+			return route.policy
+		}
+	}
+
+	return s.fallback
+}
+
+// newCORSPolicy allows origins to send credentials, unless one of them is "*": a policy open
+// to every site would let any page read responses made with the user's cookies, so such a
+// policy allows requests without credentials only.
+func newCORSPolicy(origins []string) *cors.Cors {
+	matcher := newOriginMatcher(origins)
+	if matcher.all && len(origins) > 1 {
+		slog.Warn("cors: \"*\" allows every origin, so credentials are refused for the whole list", "origins", origins)
+	}
+
+	return cors.New(cors.Options{
+		AllowOriginFunc: matcher.allowed,
+		AllowedMethods: []string{
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+			http.MethodOptions,
+		},
+		AllowedHeaders:   []string{"*"},
+		AllowCredentials: !matcher.all,
+	})
+}
+
+// originMatcher matches exact origins, "*", and wildcard subdomains such as
+// "https://*.example.com", which allows a.example.com and a.b.example.com but not
+// example.com itself.
+type originMatcher struct {
+	all       bool
+	exact     map[string]struct{}
+	wildcards []originWildcard
+}
+
+type originWildcard struct {
+	scheme string
+	// suffix is ".example.com" for "https://*.example.com".
+	suffix string
+	port   string
+}
+
+func newOriginMatcher(origins []string) *originMatcher {
+	m := &originMatcher{exact: make(map[string]struct{}, len(origins))}
+	for _, origin := range origins {
+		origin = strings.ToLower(origin)
+		if origin == "*" {
+			m.all = true
+			continue
+		}
+
+		scheme, host, port, ok := splitOrigin(origin)
+		if !ok {
+			slog.Warn("cors: ignoring invalid origin", "origin", origin)
+			continue
+		}
+		if strings.HasPrefix(host, "*.") && !strings.Contains(host[2:], "*") {
+			m.wildcards = append(m.wildcards, originWildcard{scheme: scheme, suffix: host[1:], port: port})
+			continue
+		}
+		if strings.Contains(host, "*") {
+			slog.Warn("cors: ignoring origin, only a leading *. label is supported", "origin", origin)
+			continue
+		}
+		m.exact[origin] = struct{}{}
+	}
+
+	return m
+}
+
+func (m *originMatcher) allowed(origin string) bool {
+	if m.all {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if _, ok := m.exact[origin]; ok {
+		return true
+	}
+
+	scheme, host, port, ok := splitOrigin(origin)
+	if !ok {
+		return false
+	}
+	for _, w := range m.wildcards {
+		if w.scheme != scheme || w.port != port || !strings.HasSuffix(host, w.suffix) {
+			continue
+		}
+		if validSubdomain(strings.TrimSuffix(host, w.suffix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitOrigin splits "scheme://host[:port]" and rejects anything with a path, query or user info.
+func splitOrigin(origin string) (scheme, host, port string, ok bool) {
+	scheme, rest, found := strings.Cut(origin, "://")
+	if !found || scheme == "" || rest == "" || strings.ContainsAny(rest, "/?#@") {
+		return "", "", "", false
+	}
+
+	host = rest
+	if i := strings.LastIndexByte(rest, ':'); i >= 0 && !strings.HasSuffix(rest, "]") {
+		host, port = rest[:i], rest[i+1:]
+		if port == "" {
+			return "", "", "", false
+		}
+	}
+	if host == "" {
+		return "", "", "", false
+	}
+
+	return scheme, host, port, true
+}
+
+// validSubdomain reports whether s is one or more DNS labels, such as "a" or "a.b".
+func validSubdomain(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func splitOrigins(raw string) []string {
+	var out []string
+	for _, origin := range strings.Split(raw, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			out = append(out, origin)
+		}
+	}
+
+	return out
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -2,5 +2,7 @@
 //
 // It provides a small router abstraction over httprouter plus shared concerns
 // like JSON encoding, error mapping, logging, recovery, authentication, and
-// correlation ID propagation.
+// correlation ID propagation. Response envelopes are versioned with the Accept header,
+// see MediaTypeV2, and their key casing and time format with HeaderResponseCasing and
+// HeaderResponseTimeFormat.
 package router
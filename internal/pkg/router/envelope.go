@@ -0,0 +1,274 @@
+package router
+
+import (
+	"context"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Response envelopes are versioned through the Accept header so the API can change its
+// shape without breaking existing clients. Requests that do not ask for a version, including
+// plain application/json, keep getting v1.
+//
+// v1: {"message", "data", "meta"} and {"message", "reason", "error"}.
+//
+// v2: {"data", "meta"} and {"error": {"code", "message", "reason", "fields"}}, where code is
+// a stable ERROR_CODE_* value, pagination meta always sits under meta.pagination with
+// has_more and a nullable next_cursor, and every timestamp is RFC3339 in UTC.
+const (
+	// MediaTypeV1 asks for the v1 envelope explicitly.
+	MediaTypeV1 = "application/vnd.gobite.v1+json"
+	// MediaTypeV2 asks for the v2 envelope.
+	MediaTypeV2 = "application/vnd.gobite.v2+json"
+
+	// mediaTypeVendor asks for a version with a parameter, e.g. "application/vnd.gobite+json; version=2".
+	mediaTypeVendor = "application/vnd.gobite+json"
+)
+
+type envelopeVersion int
+
+const (
+	envelopeV1 envelopeVersion = 1
+	envelopeV2 envelopeVersion = 2
+)
+
+type envelopeKey struct{}
+
+type errorResponseV2 struct {
+	Error errorBodyV2 `json:"error"`
+}
+
+type errorBodyV2 struct {
+	Code    string            `json:"code" example:"ERROR_CODE_INVALID_INPUT"`
+	Message string            `json:"message" example:"example string message"`
+	Reason  string            `json:"reason,omitempty" example:"LOGIN_ACCOUNT_LOCKED"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+type successResponseV2 struct {
+	Data any     `json:"data" swaggertype:"object"`
+	Meta metaMap `json:"meta,omitempty" swaggertype:"object"`
+}
+
+// paginationKeys are the Meta() keys v2 moves under meta.pagination.
+var paginationKeys = []string{"page", "size", "total", "next_cursor"}
+
+// negotiateEnvelope picks the envelope version from an Accept header. ok is false when the
+// client only accepts gobite versions this server does not serve.
+func negotiateEnvelope(accept string) (_ envelopeVersion, ok bool) {
+	if strings.TrimSpace(accept) == "" {
+		return envelopeV1, true
+	}
+
+	best, bestQ, bestExplicit := envelopeVersion(0), -1.0, false
+	vendorOnly := true
+	for _, item := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(item))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if raw, has := params["q"]; has {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil || q <= 0 {
+				continue
+			}
+		}
+
+		var version envelopeVersion
+		explicit := true
+		switch mediaType {
+		case MediaTypeV1:
+			version = envelopeV1
+		case MediaTypeV2:
+			version = envelopeV2
+		case mediaTypeVendor:
+			switch params["version"] {
+			case "", "1":
+				version = envelopeV1
+			case "2":
+				version = envelopeV2
+			}
+		default:
+			if strings.HasPrefix(mediaType, "application/vnd.gobite") {
+				continue
+			}
+			// application/json, */* and anything else the API has always answered with JSON
+			version, explicit, vendorOnly = envelopeV1, false, false
+		}
+		if version == 0 {
+			continue
+		}
+
+		// an explicit version beats a generic type of the same quality
+		if q > bestQ || (q == bestQ && explicit && !bestExplicit) {
+			best, bestQ, bestExplicit = version, q, explicit
+		}
+	}
+
+	if best == 0 {
+		return envelopeV1, !vendorOnly
+	}
+
+	return best, true
+}
+
+// middlewareEnvelope stores the negotiated envelope version for the codecs and answers 406
+// to clients that only accept a version this server does not have.
+func middlewareEnvelope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		version, ok := negotiateEnvelope(r.Header.Get("Accept"))
+		if !ok {
+			writeJSON(w, errorResponse{Message: "unsupported response version, accept " + MediaTypeV1 + " or " + MediaTypeV2}, http.StatusNotAcceptable)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), envelopeKey{}, version)))
+	})
+}
+
+// requestEnvelope returns the envelope version of r, negotiating it for requests that did
+// not pass through middlewareEnvelope, such as unknown routes.
+func requestEnvelope(r *http.Request) envelopeVersion {
+	if version, ok := r.Context().Value(envelopeKey{}).(envelopeVersion); ok {
+		return version
+	}
+
+	version, _ := negotiateEnvelope(r.Header.Get("Accept"))
+	return version
+}
+
+func contextEnvelope(ctx context.Context) envelopeVersion {
+	if version, ok := ctx.Value(envelopeKey{}).(envelopeVersion); ok {
+		return version
+	}
+	return envelopeV1
+}
+
+// writeError writes an error produced outside a handler, e.g. by a middleware, in the
+// envelope the client asked for.
+func writeError(w http.ResponseWriter, r *http.Request, resp errorResponse, status int) {
+	if requestEnvelope(r) == envelopeV2 {
+		writeErrorV2(w, errorBodyV2{
+			Code:    codeForStatus(status),
+			Message: resp.Message,
+			Reason:  resp.Reason,
+			Fields:  resp.Error,
+		}, status)
+		return
+	}
+
+	writeJSON(w, resp, status)
+}
+
+func writeErrorV2(w http.ResponseWriter, body errorBodyV2, status int) {
+	writeJSONAs(w, MediaTypeV2, errorResponseV2{Error: body}, status)
+}
+
+func writeSuccessV2(ctx context.Context, w http.ResponseWriter, data any, meta map[string]any, status int) {
+	// handlers encode time.Time in the server's zone with nanoseconds; v2 clients get one format
+	format := contextFormat(ctx)
+	if format.time == timeRFC3339 {
+		format.time = timeRFC3339UTC
+	}
+
+	body, err := applyFormat(successResponseV2{Data: data, Meta: metaV2(meta)}, format)
+	if err != nil {
+		slog.ErrorContext(ctx, "server: failed to encode data to json", "error", err)
+		writeErrorV2(w, errorBodyV2{Code: codeForStatus(http.StatusInternalServerError), Message: "Internal server error"}, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONAs(w, MediaTypeV2, body, status)
+}
+
+// metaV2 moves pagination keys under "pagination" and fills in has_more, so clients page
+// the same way whether an endpoint uses page numbers or cursors.
+func metaV2(meta map[string]any) metaMap {
+	if len(meta) == 0 {
+		return nil
+	}
+
+	out := make(metaMap, len(meta))
+	pagination := make(metaMap)
+	for k, v := range meta {
+		out[k] = v
+	}
+	for _, k := range paginationKeys {
+		if v, ok := out[k]; ok {
+			pagination[k] = v
+			delete(out, k)
+		}
+	}
+	if len(pagination) == 0 {
+		return out
+	}
+
+	if cursor, ok := pagination["next_cursor"].(string); ok {
+		pagination["has_more"] = cursor != ""
+		if cursor == "" {
+			pagination["next_cursor"] = nil
+		}
+	} else {
+		page, okPage := toInt64(pagination["page"])
+		size, okSize := toInt64(pagination["size"])
+		total, okTotal := toInt64(pagination["total"])
+		if okPage && okSize && okTotal {
+			pagination["has_more"] = page*size < total
+		}
+		pagination["next_cursor"] = nil
+	}
+	out["pagination"] = pagination
+
+	return out
+}
+
+func toInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// codeForStatus names the error code of a response that has no goerror.Error behind it.
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "ERROR_CODE_INVALID_FORMAT"
+	case http.StatusUnauthorized:
+		return "ERROR_CODE_UNAUTHORIZED"
+	case http.StatusForbidden:
+		return "ERROR_CODE_FORBIDDEN"
+	case http.StatusNotFound:
+		return "ERROR_CODE_NOT_FOUND"
+	case http.StatusMethodNotAllowed:
+		return "ERROR_CODE_METHOD_NOT_ALLOWED"
+	case http.StatusNotAcceptable:
+		return "ERROR_CODE_NOT_ACCEPTABLE"
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return "ERROR_CODE_TIMEOUT"
+	case http.StatusConflict:
+		return "ERROR_CODE_CONFLICT"
+	case http.StatusRequestEntityTooLarge:
+		return "ERROR_CODE_PAYLOAD_TOO_LARGE"
+	case http.StatusUnprocessableEntity:
+		return "ERROR_CODE_INVALID_INPUT"
+	case http.StatusTooManyRequests:
+		return "ERROR_CODE_TOO_MANY_REQUESTS"
+	case http.StatusServiceUnavailable:
+		return "ERROR_CODE_UNAVAILABLE"
+	default:
+		return "ERROR_CODE_INTERNAL"
+	}
+}
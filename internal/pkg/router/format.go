@@ -0,0 +1,348 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Success bodies are written with snake_case keys and RFC3339 timestamps. A deployment picks
+// another default under app.server.http.response, and a client overrides it per request with
+// the X-Response-Casing and X-Response-Time-Format headers, so web and mobile apps with
+// different conventions read the same API.
+//
+// The conversion runs on the encoded body, envelope included, so handlers never see it. It
+// follows the Go type the body was encoded from: only keys of struct fields are recased and
+// only time.Time values become numbers, so keys of maps and strings that merely look like
+// timestamps, both user data, come through as they were. Error bodies are left alone; their
+// keys are single words and the fields they name are request attributes, which stay
+// snake_case.
+const (
+	// HeaderResponseCasing picks the key casing of a response: snake or camel.
+	HeaderResponseCasing = "X-Response-Casing"
+	// HeaderResponseTimeFormat picks how timestamps are written: rfc3339, epoch (Unix
+	// seconds) or epoch_ms (Unix milliseconds).
+	HeaderResponseTimeFormat = "X-Response-Time-Format"
+)
+
+type timeFormat int
+
+const (
+	timeRFC3339 timeFormat = iota
+	timeEpoch
+	timeEpochMillis
+	// timeRFC3339UTC rewrites timestamps as RFC3339 in UTC, the v2 envelope default.
+	timeRFC3339UTC
+)
+
+// metaMap holds response metadata. Its keys are part of the API, unlike those of other maps,
+// so they are recased like struct fields.
+type metaMap map[string]any
+
+type responseFormat struct {
+	camel bool
+	time  timeFormat
+}
+
+type formatKey struct{}
+
+func parseCasing(v string) (camel, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "snake":
+		return false, true
+	case "camel":
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+func parseTimeFormat(v string) (_ timeFormat, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "rfc3339":
+		return timeRFC3339, true
+	case "epoch":
+		return timeEpoch, true
+	case "epoch_ms":
+		return timeEpochMillis, true
+	default:
+		return timeRFC3339, false
+	}
+}
+
+// defaultResponseFormat reads the deployment default, falling back to snake_case and
+// RFC3339 for values it does not know.
+func defaultResponseFormat(casing, timestamps string) responseFormat {
+	var format responseFormat
+	var ok bool
+
+	if format.camel, ok = parseCasing(casing); !ok {
+		slog.Warn("server: unknown response casing, using snake", "casing", casing)
+	}
+	if format.time, ok = parseTimeFormat(timestamps); !ok {
+		slog.Warn("server: unknown response time format, using rfc3339", "time_format", timestamps)
+	}
+
+	return format
+}
+
+// middlewareResponseFormat stores the response format of the request for the codecs,
+// answering 400 for header values it does not know.
+func middlewareResponseFormat(def responseFormat) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", HeaderResponseCasing+", "+HeaderResponseTimeFormat)
+
+			format := def
+			if v := r.Header.Get(HeaderResponseCasing); v != "" {
+				camel, ok := parseCasing(v)
+				if !ok {
+					writeError(w, r, errorResponse{Message: "unsupported " + HeaderResponseCasing + ", use snake or camel"}, http.StatusBadRequest)
+					return
+				}
+				format.camel = camel
+			}
+			if v := r.Header.Get(HeaderResponseTimeFormat); v != "" {
+				tf, ok := parseTimeFormat(v)
+				if !ok {
+					writeError(w, r, errorResponse{Message: "unsupported " + HeaderResponseTimeFormat + ", use rfc3339, epoch or epoch_ms"}, http.StatusBadRequest)
+					return
+				}
+				format.time = tf
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), formatKey{}, format)))
+		})
+	}
+}
+
+func contextFormat(ctx context.Context) responseFormat {
+	format, _ := ctx.Value(formatKey{}).(responseFormat)
+	return format
+}
+
+// writeSuccess writes a success body in the response format of ctx.
+func writeSuccess(ctx context.Context, w http.ResponseWriter, contentType string, body any, code int) {
+	if format := contextFormat(ctx); format != (responseFormat{}) {
+		formatted, err := applyFormat(body, format)
+		if err != nil {
+			slog.ErrorContext(ctx, "server: failed to format response", "error", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		body = formatted
+	}
+
+	writeJSONAs(w, contentType, body, code)
+}
+
+func applyFormat(body any, format responseFormat) (any, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return walkFormat(v, reflect.ValueOf(body), format), nil
+}
+
+//nolint:gochecknoglobals // compared on every value
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	metaMapType   = reflect.TypeFor[metaMap]()
+	marshalerType = reflect.TypeFor[json.Marshaler]()
+)
+
+// walkFormat formats the decoded value v alongside src, the Go value it was encoded from.
+// Values encoded by their own MarshalJSON are opaque and kept as they are.
+func walkFormat(v any, src reflect.Value, format responseFormat) any {
+	for src.IsValid() && (src.Kind() == reflect.Pointer || src.Kind() == reflect.Interface) {
+		if src.IsNil() {
+			return v
+		}
+		src = src.Elem()
+	}
+	if !src.IsValid() {
+		return v
+	}
+
+	if src.Type() == timeType {
+		s, ok := v.(string)
+		if !ok {
+			return v
+		}
+		return formatTime(s, format)
+	}
+	if src.Type().Implements(marshalerType) || reflect.PointerTo(src.Type()).Implements(marshalerType) {
+		return v
+	}
+
+	switch t := v.(type) {
+	case map[string]any:
+		switch src.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(src)
+			out := make(map[string]any, len(t))
+			for k, item := range t {
+				item = walkFormat(item, fields[k], format)
+				if format.camel {
+					k = camelCase(k)
+				}
+				out[k] = item
+			}
+			return out
+		case reflect.Map:
+			out := make(map[string]any, len(t))
+			for k, item := range t {
+				item = walkFormat(item, mapValue(src, k), format)
+				if format.camel && src.Type() == metaMapType {
+					k = camelCase(k)
+				}
+				out[k] = item
+			}
+			return out
+		default:
+			return t
+		}
+	case []any:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			return t
+		}
+		for i, item := range t {
+			if i < src.Len() {
+				t[i] = walkFormat(item, src.Index(i), format)
+			}
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// jsonFields maps the JSON names of the fields of struct value v to their values, promoting
+// the fields of embedded structs the way encoding/json does.
+func jsonFields(v reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value, v.NumField())
+	var embedded []reflect.Value
+
+	for i := range v.NumField() {
+		sf := v.Type().Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && name == "" {
+			fv := v.Field(i)
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				embedded = append(embedded, fv)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields[name] = v.Field(i)
+	}
+
+	for _, e := range embedded {
+		for name, fv := range jsonFields(e) {
+			if _, ok := fields[name]; !ok {
+				fields[name] = fv
+			}
+		}
+	}
+
+	return fields
+}
+
+// mapValue returns the value under the encoded key k of map m, or an invalid value when the
+// key type is not a string.
+func mapValue(m reflect.Value, k string) reflect.Value {
+	if m.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}
+	}
+
+	return m.MapIndex(reflect.ValueOf(k).Convert(m.Type().Key()))
+}
+
+func formatTime(s string, format responseFormat) any {
+	if format.time == timeRFC3339 {
+		return s
+	}
+	ts, ok := parseTimestamp(s)
+	if !ok {
+		return s
+	}
+
+	switch format.time {
+	case timeEpochMillis:
+		return json.Number(strconv.FormatInt(ts.UnixMilli(), 10))
+	case timeRFC3339UTC:
+		return ts.UTC().Format(time.RFC3339)
+	default:
+		return json.Number(strconv.FormatInt(ts.Unix(), 10))
+	}
+}
+
+// parseTimestamp reads s as an RFC3339 time; the cheap length and separator checks skip the
+// parse for ordinary strings.
+func parseTimestamp(s string) (time.Time, bool) {
+	if len(s) < len("2006-01-02T15:04:05Z") || s[4] != '-' || s[10] != 'T' {
+		return time.Time{}, false
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return ts, true
+}
+
+// camelCase turns a snake_case key into camelCase, e.g. next_cursor into nextCursor.
+func camelCase(s string) string {
+	if !strings.Contains(s, "_") {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	upper := false
+	for i, r := range s {
+		switch {
+		case r == '_' && i > 0:
+			upper = true
+		case upper:
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -1,13 +1,26 @@
 package router
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/shandysiswandi/gobite/internal/pkg/denylist"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
 )
 
-func middlewareAuthentication(verifier jwt.JWT, publicEndpoints map[string]map[string]struct{}) func(next http.Handler) http.Handler {
+// HeaderAPIKey carries an API key, accepted in place of a bearer token.
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeyVerifier resolves an API key to the claims the request acts with.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (jwt.Claims, error)
+}
+
+func middlewareAuthentication(verifier jwt.JWT, deny denylist.Denylist, apiKeys func() APIKeyVerifier, publicEndpoints map[string]map[string]struct{}) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			path := matchedRoutePath(r)
@@ -19,19 +32,80 @@ func middlewareAuthentication(verifier jwt.JWT, publicEndpoints map[string]map[s
 				}
 			}
 
-			p := strings.Fields(r.Header.Get("Authorization"))
+			serveAPIKey := func(key string) {
+				apiKey := apiKeys()
+				if apiKey == nil {
+					writeError(w, r, errorResponse{Message: "API keys are not supported"}, http.StatusUnauthorized)
+					return
+				}
+
+				claims, err := apiKey.VerifyAPIKey(r.Context(), key)
+				if err != nil {
+					writeError(w, r, errorResponse{Message: "Invalid or expired API key"}, http.StatusUnauthorized)
+					return
+				}
+
+				ctx := jwt.SetAuth(r.Context(), claims)
+				ctx = instrument.SetUserID(ctx, strconv.FormatInt(claims.UserID, 10))
+				next.ServeHTTP(w, r.WithContext(ctx))
+			}
+
+			if key := r.Header.Get(HeaderAPIKey); key != "" && r.Header.Get("Authorization") == "" {
+				serveAPIKey(key)
+				return
+			}
+
+			authorization := r.Header.Get("Authorization")
+			if token := wsBearerToken(r); authorization == "" && token != "" {
+				authorization = "Bearer " + token
+			}
+
+			p := strings.Fields(authorization)
 			if len(p) != 2 || !strings.EqualFold(p[0], "Bearer") {
-				writeJSON(w, map[string]string{"message": "Authentication required"}, http.StatusUnauthorized)
+				writeError(w, r, errorResponse{Message: "Authentication required"}, http.StatusUnauthorized)
+				return
+			}
+
+			// clients that only speak bearer auth, such as SCIM provisioning from an IdP, send
+			// their API key as the token; a JWT always has three dot-separated parts and a
+			// PASETO token starts with its version and purpose
+			if strings.Count(p[1], ".") != 2 && !jwt.IsPaseto(p[1]) {
+				serveAPIKey(p[1])
 				return
 			}
 
 			claims, err := verifier.Verify(p[1])
 			if err != nil {
-				writeJSON(w, map[string]string{"message": "Invalid or expired token"}, http.StatusUnauthorized)
+				writeError(w, r, errorResponse{Message: "Invalid or expired token"}, http.StatusUnauthorized)
 				return
 			}
 
+			if deny != nil && claims.IssuedAt != nil {
+				revoked, err := deny.IsRevoked(r.Context(), claims.ID, claims.UserID, claims.IssuedAt.Time)
+				if err != nil {
+					// an unreachable denylist must not sign everyone out; tokens still expire
+					slog.ErrorContext(r.Context(), "server: failed to check token denylist", "error", err)
+				}
+				if revoked {
+					writeError(w, r, errorResponse{Message: "Invalid or expired token"}, http.StatusUnauthorized)
+					return
+				}
+			}
+
+			userID := strconv.FormatInt(claims.UserID, 10)
+			if claims.ClientID != "" {
+				userID = claims.Subject
+			}
+
+			// the tenant comes from the verified token only, never from a client header
 			ctx := jwt.SetAuth(r.Context(), claims)
+			ctx = instrument.SetUserID(ctx, userID)
+			if claims.Tenant != "" {
+				ctx = instrument.SetTenant(ctx, claims.Tenant)
+			}
+			if claims.Actor != nil {
+				ctx = instrument.SetActorID(ctx, strconv.FormatInt(claims.Actor.UserID, 10))
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
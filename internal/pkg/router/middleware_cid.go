@@ -10,7 +10,7 @@ import (
 
 const (
 	// HeaderCorrelationID is the canonical header used to track requests end-to-end.
-	HeaderCorrelationID = "X-Correlation-ID"
+	HeaderCorrelationID = instrument.HeaderCorrelationID
 	// HeaderRequestID is an accepted alternative header name used by some proxies.
 	HeaderRequestID = "X-Request-ID"
 )
@@ -0,0 +1,70 @@
+package router
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ConcurrencyLimitConfig configures ConcurrencyLimit.
+type ConcurrencyLimitConfig struct {
+	// Name identifies the limit in logs.
+	Name string
+	// Limit is the number of requests served at once. Zero or less disables the limit.
+	Limit int
+	// QueueTimeout is how long a request waits for a free slot before it is shed. Zero sheds
+	// it at once.
+	QueueTimeout time.Duration
+}
+
+// ConcurrencyLimit caps the requests of the routes it wraps that are in flight at once.
+// A request over the cap waits up to the queue timeout for a slot and is otherwise
+// answered with 503 and Retry-After, so a burst on an expensive endpoint cannot take every
+// database connection. Every route wrapped by the returned middleware shares its slots.
+func ConcurrencyLimit(cfg ConcurrencyLimitConfig) Middleware {
+	if cfg.Limit <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	slots := make(chan struct{}, cfg.Limit)
+	retryAfter := strconv.Itoa(max(int((cfg.QueueTimeout+time.Second-1)/time.Second), 1))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !acquireSlot(r, slots, cfg.QueueTimeout) {
+				slog.WarnContext(r.Context(), "request shed by concurrency limit", "limit", cfg.Name, "route", matchedRoutePath(r))
+				w.Header().Set("Retry-After", retryAfter)
+				writeError(w, r, errorResponse{Message: "server is busy, try again later"}, http.StatusServiceUnavailable)
+				return
+			}
+			defer func() { <-slots }()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func acquireSlot(r *http.Request, slots chan struct{}, wait time.Duration) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if wait <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
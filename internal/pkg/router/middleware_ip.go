@@ -1,36 +1,103 @@
 package router
 
 import (
+	"log/slog"
 	"net"
 	"net/http"
+	"net/netip"
 	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
 )
 
-func middlewareIP(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if rip := realIP(r); rip != "" {
-			r.RemoteAddr = rip
-		}
-		next.ServeHTTP(w, r)
-	})
+// middlewareIP replaces RemoteAddr with the client IP. Forwarding headers are only honoured
+// when the direct peer is one of app.server.http.trusted_proxies, since any other client
+// could set them to rotate the IP that rate limits and login lockouts are keyed on.
+func middlewareIP(cfg config.Config) Middleware {
+	var proxies []netip.Prefix
+	if cfg != nil {
+		proxies = parseTrustedProxies(cfg.GetArray("app.server.http.trusted_proxies"))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rip := realIP(r, proxies); rip != "" {
+				r.RemoteAddr = rip
+			}
+
+			ctx := instrument.SetClient(r.Context(), r.RemoteAddr, r.UserAgent())
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
-func realIP(r *http.Request) string {
-	var ip string
+func parseTrustedProxies(entries []string) []netip.Prefix {
+	proxies := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
 
-	if tcip := r.Header.Get("True-Client-IP"); tcip != "" {
-		ip = tcip
-	} else if xrip := r.Header.Get("X-Real-IP"); xrip != "" {
-		ip = xrip
-	} else if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		ip, _, _ = strings.Cut(xff, ",")
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(entry)
+			if addrErr != nil {
+				slog.Warn("ignoring invalid trusted proxy", "entry", entry, "error", err)
+				continue
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		proxies = append(proxies, prefix.Masked())
 	}
-	if ip == "" || net.ParseIP(ip) == nil {
-		host, _, err := net.SplitHostPort(r.RemoteAddr)
-		if err == nil && net.ParseIP(host) != nil {
-			return host
+
+	return proxies
+}
+
+func isTrustedProxy(addr netip.Addr, proxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range proxies {
+		if prefix.Contains(addr) {
+			return true
 		}
+	}
+
+	return false
+}
+
+// realIP returns the IP of the direct peer, or the client IP a trusted proxy forwarded. In
+// X-Forwarded-For the rightmost entry that is not a trusted proxy is the client, as entries
+// left of it were supplied by the client itself.
+func realIP(r *http.Request, proxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return ""
+	}
+	peer, err := netip.ParseAddr(host)
+	if err != nil {
 		return ""
 	}
-	return ip
+	if !isTrustedProxy(peer, proxies) {
+		return peer.Unmap().String()
+	}
+
+	for _, header := range []string{"True-Client-IP", "X-Real-IP"} {
+		if ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(header))); err == nil {
+			return ip.Unmap().String()
+		}
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		if !isTrustedProxy(ip, proxies) || i == 0 {
+			return ip.Unmap().String()
+		}
+	}
+
+	return peer.Unmap().String()
 }
@@ -23,7 +23,7 @@ func middlewareMaintenance(cfg config.Config) Middleware {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			route := matchedRoutePath(r)
 			if _, blocked := endpoints[route]; blocked {
-				writeJSON(w, errorResponse{Message: "service is under maintenance"}, http.StatusServiceUnavailable)
+				writeError(w, r, errorResponse{Message: "service is under maintenance"}, http.StatusServiceUnavailable)
 				return
 			}
 			next.ServeHTTP(w, r)
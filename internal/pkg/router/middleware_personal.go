@@ -0,0 +1,22 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+// PersonalOnly limits an endpoint to users signed in with a token. Self-service endpoints act
+// on the caller's own account without a permission check, so an API key, which holds only
+// the scopes it was given, or a service account, which is no user, must not reach them.
+// Register it on such endpoints; it runs after authentication.
+func PersonalOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if clm := jwt.GetAuth(r.Context()); clm != nil && (clm.APIKeyID != 0 || clm.ClientID != "") {
+			writeError(w, r, errorResponse{Message: "this endpoint requires a user session", Reason: "USER_SESSION_REQUIRED"}, http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,223 @@
+package router
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/pkg/throttle"
+)
+
+const (
+	// HeaderRateLimitLimit carries the number of requests the bucket of a request holds.
+	HeaderRateLimitLimit = "RateLimit-Limit"
+	// HeaderRateLimitRemaining carries the number of requests left in the bucket.
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	// HeaderRateLimitReset carries the seconds until the bucket is full again.
+	HeaderRateLimitReset = "RateLimit-Reset"
+	// HeaderRateLimitPolicy carries "<limit>;w=<period seconds>".
+	HeaderRateLimitPolicy = "RateLimit-Policy"
+
+	rateLimitKeyIP    = "ip"
+	rateLimitKeyUser  = "user"
+	rateLimitKeyRoute = "route"
+	rateLimitKeyOrg   = "org"
+)
+
+type rateLimitGroup struct {
+	name   string
+	key    string
+	rate   throttle.Rate
+	policy string
+	// ip is the per-IP bucket a user-keyed group takes from before authentication, so
+	// requests with bad tokens or API keys are limited before they are verified.
+	ip *rateLimitGroup
+	// org is the per-organization bucket a user-keyed group also takes from when the token
+	// is scoped to an organization, so the members of one organization share a limit.
+	org *rateLimitGroup
+}
+
+type rateLimitRoute struct {
+	prefix string
+	group  *rateLimitGroup
+}
+
+// middlewareRateLimit holds every client to the token bucket of the endpoint group its route
+// belongs to, by the longest matching prefix in app.server.http.rate_limit.routes. A bucket
+// is kept per client IP, per authenticated caller (IP when anonymous), or per route, as the
+// group's key says. It runs twice: before authentication (afterAuth false) with the IP and
+// route buckets, plus an IP bucket for user-keyed groups, and after it with the user
+// buckets and, for tokens scoped to an organization, the organization bucket. Limits are
+// read at startup; a limiter error lets the request through.
+func middlewareRateLimit(cfg config.Config, limiter throttle.Limiter, afterAuth bool) Middleware {
+	if cfg == nil || limiter == nil || !cfg.GetBool("app.server.http.rate_limit.enabled") {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	routes := rateLimitRoutes(cfg)
+	if len(routes) == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			route := matchedRoutePath(r)
+			group := rateLimitGroupFor(routes, route)
+			if group != nil && (group.key == rateLimitKeyUser) != afterAuth {
+				group = group.ip
+			}
+			// anonymous callers of a user-keyed group were held to its IP bucket already
+			if group == nil || (afterAuth && jwt.GetAuth(ctx) == nil) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			d, err := limiter.Take(ctx, group.name+":"+rateLimitKey(r, group.key, route), group.rate)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to take rate limit token", "group", group.name, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// the organization bucket is shared by every member, so its headers are the ones
+			// shown once it holds fewer requests than the caller's own
+			if clm := jwt.GetAuth(ctx); d.Allowed && afterAuth && group.org != nil && clm.OrgID != 0 {
+				od, err := limiter.Take(ctx, group.org.name+":"+rateLimitKeyOrg+":"+strconv.FormatInt(clm.OrgID, 10), group.org.rate)
+				if err != nil {
+					slog.ErrorContext(ctx, "failed to take rate limit token", "group", group.org.name, "error", err)
+				} else if !od.Allowed || od.Remaining < d.Remaining {
+					group, d = group.org, od
+				}
+			}
+
+			h := w.Header()
+			h.Set(HeaderRateLimitLimit, strconv.Itoa(group.rate.Capacity()))
+			h.Set(HeaderRateLimitRemaining, strconv.Itoa(d.Remaining))
+			h.Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(d.Reset)))
+			h.Set(HeaderRateLimitPolicy, group.policy)
+
+			if !d.Allowed {
+				slog.WarnContext(ctx, "request rejected by rate limit", "group", group.name, "route", route)
+				h.Set("Retry-After", strconv.Itoa(max(ceilSeconds(d.RetryAfter), 1)))
+				writeError(w, r, errorResponse{Message: "too many requests, try again later", Reason: "RATE_LIMITED"}, http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitRoutes reads the "path_prefix:group" pairs of app.server.http.rate_limit.routes,
+// longest prefix first. A group without a positive limit and period is skipped.
+func rateLimitRoutes(cfg config.Config) []rateLimitRoute {
+	const base = "app.server.http.rate_limit."
+
+	groups := map[string]*rateLimitGroup{}
+	var routes []rateLimitRoute
+	for prefix, name := range cfg.GetMap(base + "routes") {
+		prefix, name = strings.TrimSpace(prefix), strings.TrimSpace(name)
+		if prefix == "" || name == "" {
+			continue
+		}
+
+		group, ok := groups[name]
+		if !ok {
+			rate := throttle.Rate{
+				Limit:  cfg.GetInt(base + name + "_limit"),
+				Period: cfg.GetSecond(base + name + "_period_seconds"),
+				Burst:  cfg.GetInt(base + name + "_burst"),
+			}
+			if rate.Limit > 0 && rate.Period > 0 {
+				key := strings.ToLower(strings.TrimSpace(cfg.GetString(base + name + "_key")))
+				if key != rateLimitKeyUser && key != rateLimitKeyRoute {
+					key = rateLimitKeyIP
+				}
+				group = newRateLimitGroup(name, key, rate)
+
+				if key == rateLimitKeyUser {
+					// without its own limit the IP bucket matches the user bucket
+					ipRate := throttle.Rate{
+						Limit:  cfg.GetInt(base + name + "_ip_limit"),
+						Period: cfg.GetSecond(base + name + "_ip_period_seconds"),
+						Burst:  cfg.GetInt(base + name + "_ip_burst"),
+					}
+					if ipRate.Limit <= 0 || ipRate.Period <= 0 {
+						ipRate = rate
+					}
+					group.ip = newRateLimitGroup(name+"_ip", rateLimitKeyIP, ipRate)
+
+					// without its own limit the organization bucket is not kept
+					orgRate := throttle.Rate{
+						Limit:  cfg.GetInt(base + name + "_org_limit"),
+						Period: cfg.GetSecond(base + name + "_org_period_seconds"),
+						Burst:  cfg.GetInt(base + name + "_org_burst"),
+					}
+					if orgRate.Limit > 0 && orgRate.Period > 0 {
+						group.org = newRateLimitGroup(name+"_org", rateLimitKeyOrg, orgRate)
+					}
+				}
+			}
+			groups[name] = group
+		}
+		if group == nil {
+			slog.Warn("rate limit group has no limit, its routes are not limited", "group", name)
+			continue
+		}
+
+		routes = append(routes, rateLimitRoute{prefix: prefix, group: group})
+	}
+	sort.Slice(routes, func(i, j int) bool { return len(routes[i].prefix) > len(routes[j].prefix) })
+
+	return routes
+}
+
+func newRateLimitGroup(name, key string, rate throttle.Rate) *rateLimitGroup {
+	return &rateLimitGroup{
+		name:   name,
+		key:    key,
+		rate:   rate,
+		policy: strconv.Itoa(rate.Capacity()) + ";w=" + strconv.Itoa(ceilSeconds(rate.Period)),
+	}
+}
+
+func rateLimitGroupFor(routes []rateLimitRoute, path string) *rateLimitGroup {
+	for _, route := range routes {
+		if path == route.prefix || strings.HasPrefix(path, strings.TrimSuffix(route.prefix, "/")+"/") {
+			return route.group
+		}
+	}
+
+	return nil
+}
+
+func rateLimitKey(r *http.Request, key, route string) string {
+	switch key {
+	case rateLimitKeyRoute:
+		return "route:" + r.Method + " " + route
+	case rateLimitKeyUser:
+		if clm := jwt.GetAuth(r.Context()); clm != nil {
+			switch {
+			case clm.APIKeyID != 0:
+				return "apikey:" + strconv.FormatInt(clm.APIKeyID, 10)
+			case clm.ClientID != "":
+				return "client:" + clm.ClientID
+			case clm.UserID != 0:
+				return "user:" + strconv.FormatInt(clm.UserID, 10)
+			}
+		}
+	}
+
+	return "ip:" + r.RemoteAddr
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,54 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+)
+
+// middlewareReadOnly rejects every mutation of an admin route while app.maintenance.admin_read_only
+// is set, freezing administrative changes during an incident while end-user flows keep
+// working. A request is an admin mutation when its method is not a safe one and its route sits
+// under one of adminPrefixes, so routes added under those prefixes are covered without being
+// listed; exempt names the "METHOD route" pairs that stay open. The flag is read on every
+// request, so a config reload applies it without a restart.
+func middlewareReadOnly(cfg config.Config, adminPrefixes []string, exempt map[string]struct{}) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg == nil || !cfg.GetBool("app.maintenance.admin_read_only") || isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			route := matchedRoutePath(r)
+			if _, ok := exempt[r.Method+" "+route]; !ok && hasRoutePrefix(route, adminPrefixes) {
+				writeError(w, r, errorResponse{Message: "read-only mode", Reason: "ADMIN_READ_ONLY"}, http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasRoutePrefix reports whether path is one of prefixes or sits below one of them. Prefixes
+// match whole segments, so "/api/v1/identity/users" does not cover "/api/v1/identity/users-import".
+func hasRoutePrefix(path string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -19,7 +19,13 @@ func middlewareRecoverer(next http.Handler) http.Handler {
 					panic(rvr)
 				}
 
-				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				var body any = map[string]string{"message": "Internal server error"}
+				contentType := "application/json; charset=utf-8"
+				if requestEnvelope(r) == envelopeV2 {
+					body = errorResponseV2{Error: errorBodyV2{Code: codeForStatus(http.StatusInternalServerError), Message: "Internal server error"}}
+					contentType = MediaTypeV2
+				}
+				w.Header().Set("Content-Type", contentType)
 
 				if r.Header.Get("Connection") != "Upgrade" {
 					w.WriteHeader(http.StatusInternalServerError)
@@ -32,9 +38,7 @@ func middlewareRecoverer(next http.Handler) http.Handler {
 					slog.ErrorContext(r.Context(), "panic on the server", "because", rvr, "stack", paths)
 				}
 
-				json.NewEncoder(w).Encode(map[string]string{
-					"message": "Internal server error",
-				})
+				json.NewEncoder(w).Encode(body)
 			}
 		}()
 
@@ -0,0 +1,247 @@
+package router
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/pkg/replay"
+)
+
+const (
+	// HeaderSignatureKeyID names the shared key a request was signed with.
+	HeaderSignatureKeyID = "X-Signature-Key-Id"
+	// HeaderSignatureTimestamp carries the Unix time, in seconds, a request was signed at.
+	HeaderSignatureTimestamp = "X-Signature-Timestamp"
+	// HeaderSignatureNonce carries a value unique to each signed request, so a captured
+	// request cannot be sent again while its timestamp is still accepted.
+	HeaderSignatureNonce = "X-Signature-Nonce"
+	// HeaderContentDigest carries "sha-256=" followed by the base64 SHA-256 of the raw body.
+	HeaderContentDigest = "X-Content-Digest"
+	// HeaderSignature carries "sha256=" followed by the hex HMAC-SHA256 of
+	// "<method>\n<request uri>\n<timestamp>\n<nonce>\n<content digest>" keyed with the shared key.
+	HeaderSignature = "X-Signature"
+
+	defaultSignatureTolerance    = 5 * time.Minute
+	defaultSignatureMaxBodyBytes = 10 << 20
+	maxSignatureNonceLength      = 128
+)
+
+// signingKey is a shared key and the subject of the only principal allowed to sign with it.
+type signingKey struct {
+	owner  string
+	secret string
+}
+
+// SignRequest signs req with the shared key secret named keyID, as verified by the request
+// signing middleware. nonce must not repeat for the key, and body must be the exact bytes
+// sent as the request body, nil for none.
+func SignRequest(req *http.Request, keyID, secret, nonce string, body []byte, now time.Time) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	digest := contentDigest(body)
+
+	req.Header.Set(HeaderSignatureKeyID, keyID)
+	req.Header.Set(HeaderSignatureTimestamp, timestamp)
+	req.Header.Set(HeaderSignatureNonce, nonce)
+	req.Header.Set(HeaderContentDigest, digest)
+	req.Header.Set(HeaderSignature, "sha256="+hex.EncodeToString(requestSignature(secret, req.Method, req.URL.RequestURI(), timestamp, nonce, digest)))
+}
+
+// middlewareRequestSigning adds message-level integrity on top of the bearer token for
+// service-to-service calls, configured by app.server.request_signing. When enabled, a service
+// caller (client credentials or API key) must sign its requests to the configured routes, the
+// principals listed in subjects must sign every request, and a request carrying a signature is
+// verified whoever sends it. Other callers, such as SCIM identity providers and personal API
+// keys, are left alone. The signature covers the method, the path with query, the timestamp,
+// the nonce and the body digest, so a proxy or a stolen token alone cannot alter a call; on
+// the streamed routes the body is passed through unread and its digest is not checked. Each
+// key belongs to one principal and only signs its requests, and each nonce is accepted once,
+// recorded in guard for as long as the timestamp would be. Settings are read once at startup.
+func middlewareRequestSigning(cfg config.Config, guard replay.Guard) Middleware {
+	if cfg == nil || !cfg.GetBool("app.server.request_signing.enabled") {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	keys := make(map[string]signingKey)
+	for _, entry := range cfg.GetArray("app.server.request_signing.keys") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			slog.Warn("ignoring request signing key that is not key_id:owner:secret")
+			continue
+		}
+		keys[parts[0]] = signingKey{owner: parts[1], secret: parts[2]}
+	}
+
+	routes := trimmedEntries(cfg.GetArray("app.server.request_signing.routes"))
+	streamed := trimmedEntries(cfg.GetArray("app.server.request_signing.streamed_routes"))
+	subjects := make(map[string]struct{})
+	for _, subject := range trimmedEntries(cfg.GetArray("app.server.request_signing.subjects")) {
+		subjects[subject] = struct{}{}
+	}
+
+	tolerance := cfg.GetSecond("app.server.request_signing.tolerance_seconds")
+	if tolerance <= 0 {
+		tolerance = defaultSignatureTolerance
+	}
+	maxBody := cfg.GetInt64("app.server.request_signing.max_body_bytes")
+	if maxBody <= 0 {
+		maxBody = defaultSignatureMaxBodyBytes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			if r.Header.Get(HeaderSignature) == "" {
+				if clm := jwt.GetAuth(ctx); clm != nil && signatureRequired(clm, r.URL.Path, routes, subjects) {
+					slog.WarnContext(ctx, "unsigned service request rejected", "client_id", clm.ClientID, "api_key_id", clm.APIKeyID)
+					writeError(w, r, errorResponse{Message: "request signature required"}, http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			keyID := r.Header.Get(HeaderSignatureKeyID)
+			key, ok := keys[keyID]
+			if !ok {
+				slog.WarnContext(ctx, "unknown request signature key", "key_id", keyID)
+				writeError(w, r, errorResponse{Message: "invalid request signature"}, http.StatusUnauthorized)
+				return
+			}
+
+			// a key signs for its owner only; another caller holding it cannot vouch for itself
+			if clm := jwt.GetAuth(ctx); clm == nil || clm.Subject != key.owner {
+				slog.WarnContext(ctx, "request signature key does not belong to the caller", "key_id", keyID)
+				writeError(w, r, errorResponse{Message: "invalid request signature"}, http.StatusUnauthorized)
+				return
+			}
+
+			nonce := r.Header.Get(HeaderSignatureNonce)
+			if nonce == "" || len(nonce) > maxSignatureNonceLength {
+				writeError(w, r, errorResponse{Message: "invalid signature nonce"}, http.StatusUnauthorized)
+				return
+			}
+
+			timestamp := r.Header.Get(HeaderSignatureTimestamp)
+			sec, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				writeError(w, r, errorResponse{Message: "invalid signature timestamp"}, http.StatusUnauthorized)
+				return
+			}
+
+			signedAt := time.Unix(sec, 0)
+			drift := time.Since(signedAt)
+			if drift > tolerance || drift < -tolerance {
+				slog.WarnContext(ctx, "request signature timestamp outside tolerance", "key_id", keyID, "drift", drift)
+				writeError(w, r, errorResponse{Message: "invalid signature timestamp"}, http.StatusUnauthorized)
+				return
+			}
+
+			digest := r.Header.Get(HeaderContentDigest)
+			stream := hasRoutePrefix(r.URL.Path, streamed)
+
+			var body []byte
+			if !stream {
+				body, err = io.ReadAll(io.LimitReader(r.Body, maxBody+1))
+				if err != nil {
+					writeError(w, r, errorResponse{Message: "invalid request body"}, http.StatusBadRequest)
+					return
+				}
+				if int64(len(body)) > maxBody {
+					writeError(w, r, errorResponse{Message: "request body too large"}, http.StatusRequestEntityTooLarge)
+					return
+				}
+
+				if !hmac.Equal([]byte(digest), []byte(contentDigest(body))) {
+					slog.WarnContext(ctx, "request body does not match its digest", "key_id", keyID)
+					writeError(w, r, errorResponse{Message: "invalid content digest"}, http.StatusUnauthorized)
+					return
+				}
+			}
+
+			if !validRequestSignature(key.secret, r.Method, r.URL.RequestURI(), timestamp, nonce, digest, r.Header.Get(HeaderSignature)) {
+				slog.WarnContext(ctx, "invalid request signature", "key_id", keyID)
+				writeError(w, r, errorResponse{Message: "invalid request signature"}, http.StatusUnauthorized)
+				return
+			}
+
+			// checked last, so only genuine signatures use up their nonce
+			fresh, err := guard.Use(ctx, "signature", keyID+":"+nonce, time.Until(signedAt.Add(tolerance)))
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to record request signature nonce", "key_id", keyID, "error", err)
+				writeError(w, r, errorResponse{Message: "Internal server error"}, http.StatusInternalServerError)
+				return
+			}
+			if !fresh {
+				slog.WarnContext(ctx, "request signature nonce reused", "key_id", keyID)
+				writeError(w, r, errorResponse{Message: "invalid signature nonce"}, http.StatusUnauthorized)
+				return
+			}
+
+			if !stream {
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				r.ContentLength = int64(len(body))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// signatureRequired reports whether an unsigned request from clm is refused: a service
+// caller on one of routes, or any caller whose subject is listed in subjects.
+func signatureRequired(clm *jwt.Claims, path string, routes []string, subjects map[string]struct{}) bool {
+	if _, ok := subjects[clm.Subject]; ok {
+		return true
+	}
+
+	return (clm.ClientID != "" || clm.APIKeyID != 0) && hasRoutePrefix(path, routes)
+}
+
+func trimmedEntries(entries []string) []string {
+	out := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			out = append(out, entry)
+		}
+	}
+
+	return out
+}
+
+func contentDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "sha-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func requestSignature(secret, method, uri, timestamp, nonce, digest string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + uri + "\n" + timestamp + "\n" + nonce + "\n" + digest))
+
+	return mac.Sum(nil)
+}
+
+func validRequestSignature(secret, method, uri, timestamp, nonce, digest, signature string) bool {
+	got, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+
+	sig, err := hex.DecodeString(got)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(sig, requestSignature(secret, method, uri, timestamp, nonce, digest))
+}
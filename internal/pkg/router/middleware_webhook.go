@@ -0,0 +1,145 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/clock"
+	"github.com/shandysiswandi/gobite/internal/pkg/replay"
+)
+
+const (
+	defaultWebhookTolerance    = 5 * time.Minute
+	defaultWebhookMaxBodyBytes = 10 << 20
+	maxWebhookDeliveryIDLen    = 256
+)
+
+// ErrWebhookSignature is returned by a WebhookVerifier for a delivery the provider did not sign.
+var ErrWebhookSignature = errors.New("router: invalid webhook signature")
+
+// WebhookDelivery describes a delivery a WebhookVerifier found authentic.
+type WebhookDelivery struct {
+	// SignedAt is when the provider signed the delivery.
+	SignedAt time.Time
+	// ID is unique to the delivery, such as a message ID or the signature itself.
+	ID string
+}
+
+// WebhookVerifier checks deliveries with the signature scheme of one provider.
+type WebhookVerifier interface {
+	// VerifyWebhook checks the signature of r, whose raw body is given. It fails with
+	// ErrWebhookSignature when the delivery is not authentic; other errors are server errors.
+	VerifyWebhook(r *http.Request, body []byte) (WebhookDelivery, error)
+}
+
+// WebhookConfig configures the verification done by Webhook.
+type WebhookConfig struct {
+	// Name separates the delivery IDs of one webhook from those of others.
+	Name string
+	// Verifier checks the provider signature. A nil verifier disables verification.
+	Verifier WebhookVerifier
+	// Tolerance bounds how far the signed time may drift from now. Defaults to 5 minutes.
+	Tolerance time.Duration
+	// MaxBodyBytes bounds the body read for the signature. Defaults to 10 MiB.
+	MaxBodyBytes int64
+	// Replay records the delivery IDs seen within the tolerance window.
+	Replay replay.Guard
+	// Clock reads the current time. Defaults to the system clock.
+	Clock clock.Clocker
+}
+
+type webhookVerifiedKey struct{}
+
+// WebhookVerified reports whether the request of ctx passed a Webhook verifier.
+func WebhookVerified(ctx context.Context) bool {
+	verified, _ := ctx.Value(webhookVerifiedKey{}).(bool)
+	return verified
+}
+
+// Webhook verifies that a request was signed by a provider and has not been seen before.
+// The provider's own scheme is checked by cfg.Verifier; a delivery signed outside the
+// tolerance, or whose ID was already used within it, is rejected, so a captured delivery
+// cannot be sent again. The body is buffered and handed on unchanged, and handlers learn
+// that it was verified through WebhookVerified.
+func Webhook(cfg WebhookConfig) Middleware {
+	if cfg.Tolerance <= 0 {
+		cfg.Tolerance = defaultWebhookTolerance
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = defaultWebhookMaxBodyBytes
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = clock.New()
+	}
+
+	return func(next http.Handler) http.Handler {
+		if cfg.Verifier == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, cfg.MaxBodyBytes+1))
+			if err != nil {
+				writeError(w, r, errorResponse{Message: "invalid request body"}, http.StatusBadRequest)
+				return
+			}
+			if int64(len(body)) > cfg.MaxBodyBytes {
+				writeError(w, r, errorResponse{Message: "request body too large"}, http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			delivery, err := cfg.Verifier.VerifyWebhook(r, body)
+			if errors.Is(err, ErrWebhookSignature) {
+				slog.WarnContext(ctx, "invalid webhook signature", "webhook", cfg.Name, "error", err)
+				writeError(w, r, errorResponse{Message: "invalid webhook signature"}, http.StatusUnauthorized)
+				return
+			}
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to verify webhook signature", "webhook", cfg.Name, "error", err)
+				writeError(w, r, errorResponse{Message: "Internal server error"}, http.StatusInternalServerError)
+				return
+			}
+
+			drift := cfg.Clock.Now().Sub(delivery.SignedAt)
+			if drift > cfg.Tolerance || drift < -cfg.Tolerance {
+				slog.WarnContext(ctx, "webhook timestamp outside tolerance", "webhook", cfg.Name, "drift", drift)
+				writeError(w, r, errorResponse{Message: "invalid webhook timestamp"}, http.StatusUnauthorized)
+				return
+			}
+
+			if delivery.ID == "" || len(delivery.ID) > maxWebhookDeliveryIDLen {
+				writeError(w, r, errorResponse{Message: "invalid webhook delivery"}, http.StatusUnauthorized)
+				return
+			}
+
+			// the ID is claimed only after the signature holds, so forged requests cannot use
+			// up the IDs of real deliveries; past twice the tolerance the timestamp check alone
+			// rejects a replay
+			if cfg.Replay != nil {
+				fresh, err := cfg.Replay.Use(ctx, "webhook:"+cfg.Name, delivery.ID, 2*cfg.Tolerance)
+				if err != nil {
+					slog.ErrorContext(ctx, "failed to record webhook delivery", "webhook", cfg.Name, "error", err)
+					writeError(w, r, errorResponse{Message: "Internal server error"}, http.StatusInternalServerError)
+					return
+				}
+				if !fresh {
+					slog.WarnContext(ctx, "webhook replay rejected", "webhook", cfg.Name, "delivery_id", delivery.ID)
+					writeError(w, r, errorResponse{Message: "webhook already received"}, http.StatusConflict)
+					return
+				}
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, webhookVerifiedKey{}, true)))
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,6 +12,14 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
+	"github.com/shandysiswandi/gobite/internal/pkg/valueobject"
+)
+
+const (
+	// HeaderCaptchaToken carries the response token of a CAPTCHA widget.
+	HeaderCaptchaToken = "X-Captcha-Token"
+	// HeaderIfMatch carries the version a mutation expects the resource to still have.
+	HeaderIfMatch = "If-Match"
 )
 
 // Request wraps http.Request with helpers for inbound handlers.
@@ -41,6 +50,20 @@ func (r *Request) GetQueries(key string) []string {
 	return r.URL.Query()[key]
 }
 
+func (r *Request) GetQueryInt64(key string) (int64, error) {
+	queryValue := r.GetQuery(key)
+	if queryValue == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(queryValue, 10, 64)
+	if err != nil {
+		return 0, goerror.NewInvalidFormat()
+	}
+
+	return value, nil
+}
+
 func (r *Request) GetQueryInt32(key string) (int32, error) {
 	queryValue := r.GetQuery(key)
 	if queryValue == "" {
@@ -83,6 +106,37 @@ func (r *Request) GetQueryDate(key, format string) (time.Time, error) {
 	return value, nil
 }
 
+// CaptchaToken returns the CAPTCHA response token sent in the X-Captcha-Token header, or
+// bodyToken, the captcha_token field of the decoded body, when the header is absent.
+func (r *Request) CaptchaToken(bodyToken string) string {
+	if token := strings.TrimSpace(r.Header.Get(HeaderCaptchaToken)); token != "" {
+		return token
+	}
+
+	return strings.TrimSpace(bodyToken)
+}
+
+// ExpectedVersion returns the version sent in the If-Match header, quoted or not, or
+// bodyVersion, the expected_version field of the decoded body, when the header is absent.
+// Zero, and an If-Match of "*", mean no precondition.
+func (r *Request) ExpectedVersion(bodyVersion int64) (valueobject.Version, error) {
+	header := strings.TrimSpace(r.Header.Get(HeaderIfMatch))
+	if header == "" {
+		return valueobject.Version(bodyVersion), nil
+	}
+	if header == "*" {
+		return 0, nil
+	}
+
+	header = strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	value, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || value < 0 {
+		return 0, goerror.NewInvalidFormat("Invalid header " + HeaderIfMatch)
+	}
+
+	return valueobject.Version(value), nil
+}
+
 // DecodeBody decodes the JSON body into dst.
 func (r *Request) DecodeBody(dst any) error {
 	if r == nil || r.Body == nil {
@@ -105,6 +159,17 @@ func (r *Request) DecodeBody(dst any) error {
 
 // StreamSingleFile returns the first multipart file matching the form field name.
 func (r *Request) StreamSingleFile(name string) (io.ReadCloser, error) {
+	part, err := r.StreamSingleFilePart(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return part, nil
+}
+
+// StreamSingleFilePart is StreamSingleFile for handlers that also need the part headers,
+// such as the uploaded filename.
+func (r *Request) StreamSingleFilePart(name string) (*multipart.Part, error) {
 	ct := r.Header.Get("Content-Type")
 	if !strings.HasPrefix(ct, "multipart/form-data") {
 		return nil, goerror.NewInvalidFormat("Invalid request content-type")
@@ -115,7 +180,7 @@ func (r *Request) StreamSingleFile(name string) (io.ReadCloser, error) {
 		return nil, goerror.NewInvalidFormat()
 	}
 
-	var file io.ReadCloser
+	var file *multipart.Part
 	for {
 		part, err := mr.NextPart()
 		if errors.Is(err, io.EOF) {
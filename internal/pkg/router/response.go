@@ -0,0 +1,45 @@
+package router
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// File is a handler response streamed as a download instead of the JSON envelope. Errors
+// meant for the client must be returned by the handler before it returns the File: once
+// Write runs the status line is already sent.
+type File struct {
+	// Name is the suggested download filename.
+	Name string
+	// ContentType is the media type of the body, e.g. "text/csv; charset=utf-8".
+	ContentType string
+	// Write streams the body.
+	Write func(w io.Writer) error
+}
+
+func (f *File) serve(w http.ResponseWriter) error {
+	// a large export outlives the server write timeout, which is sized for JSON responses
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	w.Header().Set("Content-Type", f.ContentType)
+	if f.Name != "" {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": f.Name}))
+	}
+	w.WriteHeader(http.StatusOK)
+
+	return f.Write(w)
+}
+
+// WithCookies is a handler response that sets Cookies and then writes Data as any other
+// response, so a nil Data answers 204.
+type WithCookies struct {
+	// Data is the response payload.
+	Data any
+	// Cookies are set on the response.
+	Cookies []*http.Cookie
+}
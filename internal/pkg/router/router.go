@@ -6,26 +6,33 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/casbin/casbin/v3"
 	"github.com/julienschmidt/httprouter"
 	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/denylist"
 	"github.com/shandysiswandi/gobite/internal/pkg/goerror"
 	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
 	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+	"github.com/shandysiswandi/gobite/internal/pkg/replay"
+	"github.com/shandysiswandi/gobite/internal/pkg/stmtdeadline"
+	"github.com/shandysiswandi/gobite/internal/pkg/throttle"
 	"github.com/shandysiswandi/gobite/internal/pkg/uid"
 	"github.com/shandysiswandi/gobite/internal/pkg/validator"
 )
 
 type errorResponse struct {
 	Message string            `json:"message" example:"example string message"`
+	Reason  string            `json:"reason,omitempty" example:"LOGIN_ACCOUNT_LOCKED"`
 	Error   map[string]string `json:"error,omitempty"`
 }
 
 type successResponse struct {
-	Message string         `json:"message" example:"example string message"`
-	Data    any            `json:"data" swaggertype:"object"`
-	Meta    map[string]any `json:"meta,omitempty" swaggertype:"object"`
+	Message string  `json:"message" example:"example string message"`
+	Data    any     `json:"data" swaggertype:"object"`
+	Meta    metaMap `json:"meta,omitempty" swaggertype:"object"`
 }
 
 // Handler is the application-style handler used by this router.
@@ -45,6 +52,12 @@ type Config struct {
 	Instrument instrument.Instrumentation
 	// Enforcer applies authorization policies.
 	Enforcer *casbin.Enforcer
+	// Denylist rejects access tokens revoked before they expire. Nil accepts every valid token.
+	Denylist denylist.Denylist
+	// RateLimiter backs the per-group rate limits of app.server.http.rate_limit. Nil disables them.
+	RateLimiter throttle.Limiter
+	// Replay records the nonces of signed requests when app.server.request_signing is enabled.
+	Replay replay.Guard
 }
 
 // Router is an http.Handler that wraps httprouter and a middleware chain.
@@ -53,6 +66,10 @@ type Router struct {
 	errorCodec func(ctx context.Context, w http.ResponseWriter, err error)
 	encoder    func(ctx context.Context, w http.ResponseWriter, resp any)
 	mws        []Middleware
+	apiKey     APIKeyVerifier
+	ws         *wsManager
+	// stmtBudget bounds the queries a handler runs, see stmtdeadline.With.
+	stmtBudget time.Duration
 }
 
 // NewRouter builds the default application router with standard middleware.
@@ -64,10 +81,10 @@ func NewRouter(cfg Config) *Router {
 		HandleOPTIONS:          true,
 		SaveMatchedRoutePath:   true,
 		NotFound: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			writeJSON(w, map[string]string{"message": "endpoint not found"}, http.StatusNotFound)
+			writeError(w, r, errorResponse{Message: "endpoint not found"}, http.StatusNotFound)
 		}),
 		MethodNotAllowed: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			writeJSON(w, map[string]string{"message": "method not allowed"}, http.StatusMethodNotAllowed)
+			writeError(w, r, errorResponse{Message: "method not allowed"}, http.StatusMethodNotAllowed)
 		}),
 	}
 
@@ -78,11 +95,20 @@ func NewRouter(cfg Config) *Router {
 	errorCodec := func(ctx context.Context, w http.ResponseWriter, err error) {
 		var gerr *goerror.Error
 		if !errors.As(err, &gerr) {
+			if contextEnvelope(ctx) == envelopeV2 {
+				writeErrorV2(w, errorBodyV2{Code: goerror.CodeInternal.String(), Message: "Internal server error"}, http.StatusInternalServerError)
+				return
+			}
 			writeJSON(w, errorResponse{Message: "Internal server error"}, http.StatusInternalServerError)
 			return
 		}
 
-		errResp := errorResponse{Message: gerr.Msg()}
+		errResp := errorResponse{Message: gerr.Msg(), Reason: gerr.Reason()}
+
+		if ra := gerr.RetryAfter(); ra > 0 {
+			// Retry-After takes whole seconds; round up so clients never retry too early
+			w.Header().Set("Retry-After", strconv.FormatInt(int64((ra+time.Second-1)/time.Second), 10))
+		}
 
 		var errValidate validator.V10ValidationError
 		if errors.As(err, &errValidate) {
@@ -91,10 +117,37 @@ func NewRouter(cfg Config) *Router {
 			errResp.Error = gerr.Fields()
 		}
 
+		if contextEnvelope(ctx) == envelopeV2 {
+			writeErrorV2(w, errorBodyV2{
+				Code:    gerr.Code().String(),
+				Message: errResp.Message,
+				Reason:  errResp.Reason,
+				Fields:  errResp.Error,
+			}, gerr.StatusCode())
+			return
+		}
+
 		writeJSON(w, errResp, gerr.StatusCode())
 	}
 
 	okCodec := func(ctx context.Context, w http.ResponseWriter, resp any) {
+		if wc, ok := resp.(*WithCookies); ok {
+			for _, c := range wc.Cookies {
+				http.SetCookie(w, c)
+			}
+			resp = wc.Data
+		}
+
+		if f, ok := resp.(*File); ok {
+			// a download streams its rows while it is written, well past the handler's budget
+			stmtdeadline.Lift(ctx)
+			if err := f.serve(w); err != nil {
+				// the status line is already sent, so the truncated download is all the client sees
+				slog.ErrorContext(ctx, "server: failed to stream file response", "file", f.Name, "error", err)
+			}
+			return
+		}
+
 		code := http.StatusOK
 		if sc, ok := resp.(interface {
 			StatusCode() int
@@ -121,10 +174,15 @@ func NewRouter(cfg Config) *Router {
 			meta = m.Meta()
 		}
 
-		writeJSON(w, successResponse{
+		if contextEnvelope(ctx) == envelopeV2 {
+			writeSuccessV2(ctx, w, resp, meta, code)
+			return
+		}
+
+		writeSuccess(ctx, w, contentTypeJSON, successResponse{
 			Message: msg,
 			Data:    resp,
-			Meta:    meta,
+			Meta:    metaMap(meta),
 		}, code)
 	}
 
@@ -132,35 +190,94 @@ func NewRouter(cfg Config) *Router {
 		http.MethodGet: {
 			"/":       {},
 			"/health": {},
+			//
+			"/.well-known/gobite-configuration": {},
+			"/.well-known/jwks.json":            {},
+			//
+			"/api/v1/identity/oauth/:provider/authorize": {},
+			"/api/v1/identity/saml/metadata":             {},
+			"/api/v1/identity/saml/login":                {},
 		},
 		http.MethodPost: {
-			"/api/v1/identity/login":           {},
-			"/api/v1/identity/login/2fa":       {},
-			"/api/v1/identity/refresh":         {},
-			"/api/v1/identity/register":        {},
-			"/api/v1/identity/register/resend": {},
-			"/api/v1/identity/register/verify": {},
-			"/api/v1/identity/password/forgot": {},
-			"/api/v1/identity/password/reset":  {},
+			"/api/v1/identity/login":                   {},
+			"/api/v1/identity/login/2fa":               {},
+			"/api/v1/identity/login/2fa/sms":           {},
+			"/api/v1/identity/login/mfa-setup":         {},
+			"/api/v1/identity/login/mfa-setup/confirm": {},
+			"/api/v1/identity/refresh":                 {},
+			"/api/v1/identity/token":                   {},
+			"/api/v1/identity/saml/acs":                {},
+			"/api/v1/identity/register":                {},
+			"/api/v1/identity/register/resend":         {},
+			"/api/v1/identity/register/verify":         {},
+			"/api/v1/identity/password/forgot":         {},
+			"/api/v1/identity/password/reset":          {},
+			"/api/v1/identity/email/change/confirm":    {},
+			"/api/v1/identity/invite/accept":           {},
+			//
+			"/api/v1/identity/oauth/:provider/callback": {},
+			//
+			"/api/v1/identity/mfa/recovery":          {},
+			"/api/v1/identity/mfa/recovery/verify":   {},
+			"/api/v1/identity/mfa/recovery/complete": {},
+			//
+			"/api/v1/notification/unsubscribe":      {},
+			"/api/v1/notification/inbound/sendgrid": {},
+			"/api/v1/notification/inbound/ses":      {},
 		},
 	}
+	// mutations under these prefixes are frozen by app.maintenance.admin_read_only
+	adminRoutePrefixes := []string{
+		"/api/v1/admin",
+		"/api/v1/identity/users",
+		"/api/v1/identity/users-import",
+		"/api/v1/identity/users-export",
+		"/api/v1/identity/exports",
+		"/api/v1/identity/roles",
+		"/api/v1/identity/service-accounts",
+		"/api/v1/identity/orgs",
+		"/api/v1/notification/templates",
+		"/api/v1/notification/archives",
+		"/scim/v2",
+	}
+	// end-user flows under an admin prefix that change no administrative state
+	readOnlyExempt := map[string]struct{}{
+		http.MethodPost + " /api/v1/identity/orgs/:id/token": {},
+	}
 	ro := &Router{
 		hr:         hr,
 		errorCodec: errorCodec,
 		encoder:    okCodec,
-		mws: []Middleware{
-			middlewareRecoverer,
-			middlewareIP,
-			middlewareCorrelationID(cfg.UUID),
-			middlewareObservability(cfg.Config, cfg.Instrument),
-			middlewareMaintenance(cfg.Config),
-			middlewareAuthentication(cfg.JWT, publicEndpoints),
-		},
+		ws:         newWSManager(cfg.Config, cfg.Denylist),
+		stmtBudget: cfg.Config.GetSecond("app.server.http.statement_deadline_seconds"),
+	}
+	ro.mws = []Middleware{
+		middlewareRecoverer,
+		middlewareIP(cfg.Config),
+		middlewareCorrelationID(cfg.UUID),
+		middlewareObservability(cfg.Config, cfg.Instrument),
+		middlewareEnvelope,
+		middlewareResponseFormat(defaultResponseFormat(
+			cfg.Config.GetString("app.server.http.response.casing"),
+			cfg.Config.GetString("app.server.http.response.time_format"),
+		)),
+		middlewareMaintenance(cfg.Config),
+		middlewareReadOnly(cfg.Config, adminRoutePrefixes, readOnlyExempt),
+		middlewareRateLimit(cfg.Config, cfg.RateLimiter, false),
+		middlewareAuthentication(cfg.JWT, cfg.Denylist, func() APIKeyVerifier { return ro.apiKey }, publicEndpoints),
+		middlewareRequestSigning(cfg.Config, cfg.Replay),
+		middlewareRateLimit(cfg.Config, cfg.RateLimiter, true),
 	}
 
 	return ro
 }
 
+// UseAPIKey accepts the X-API-Key header, resolved by v, on every authenticated endpoint.
+// It must be called during startup only.
+func (r *Router) UseAPIKey(v APIKeyVerifier) {
+	r.apiKey = v
+}
+
 // GET registers a GET endpoint using the application Handler signature.
 func (r *Router) GET(path string, h Handler, mws ...Middleware) {
 	r.endpoint(http.MethodGet, path, h, mws...)
@@ -168,7 +285,13 @@ func (r *Router) GET(path string, h Handler, mws ...Middleware) {
 
 // GETRaw registers a GET endpoint that writes directly to the response writer.
 func (r *Router) GETRaw(path string, h http.Handler, mws ...Middleware) {
-	r.hr.Handler(http.MethodGet, path, Chain(h, append(r.mws, mws...)...))
+	r.HandleRaw(http.MethodGet, path, h, mws...)
+}
+
+// HandleRaw registers an endpoint that writes directly to the response writer, for
+// protocols with their own body format such as SCIM.
+func (r *Router) HandleRaw(method, path string, h http.Handler, mws ...Middleware) {
+	r.hr.Handler(method, path, Chain(h, append(r.mws, mws...)...))
 }
 
 // POST registers a POST endpoint using the application Handler signature.
@@ -193,6 +316,10 @@ func (r *Router) DELETE(path string, h Handler, mws ...Middleware) {
 
 func (r *Router) endpoint(method, path string, h Handler, mws ...Middleware) {
 	r.hr.Handler(method, path, Chain(http.HandlerFunc(func(w http.ResponseWriter, re *http.Request) {
+		if r.stmtBudget > 0 {
+			re = re.WithContext(stmtdeadline.With(re.Context(), time.Now().Add(r.stmtBudget)))
+		}
+
 		resp, err := h(&Request{Request: re})
 		if err != nil {
 			if setter, ok := w.(interface{ SetError(error) }); ok {
@@ -210,8 +337,14 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	r.hr.ServeHTTP(w, req)
 }
 
+const contentTypeJSON = "application/json; charset=utf-8"
+
 func writeJSON(w http.ResponseWriter, data any, code int) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writeJSONAs(w, contentTypeJSON, data, code)
+}
+
+func writeJSONAs(w http.ResponseWriter, contentType string, data any, code int) {
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(code)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -0,0 +1,328 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shandysiswandi/gobite/internal/pkg/config"
+	"github.com/shandysiswandi/gobite/internal/pkg/denylist"
+	"github.com/shandysiswandi/gobite/internal/pkg/jwt"
+)
+
+const (
+	// WSSubprotocolBearer lets browsers, which cannot set headers on a WebSocket handshake,
+	// authenticate the upgrade with the access token as the second subprotocol:
+	// new WebSocket(url, ["bearer", token]). The server selects "bearer".
+	WSSubprotocolBearer = "bearer"
+
+	defaultWSPingInterval   = 25 * time.Second
+	defaultWSWriteTimeout   = 10 * time.Second
+	defaultWSSendBuffer     = 32
+	defaultWSMaxMessageSize = 4096
+)
+
+var (
+	errWSTokenExpired = errors.New("token expired")
+	errWSTokenRevoked = errors.New("token revoked")
+)
+
+// WSMessage is a message pushed to a WebSocket client, sent as a JSON text frame.
+type WSMessage struct {
+	Event string `json:"event"`
+	Data  any    `json:"data,omitempty"`
+}
+
+// WSHandler serves an upgraded connection of an authenticated user. It runs for as long
+// as the connection is open and must return once c.Context() is done; the connection is
+// closed when it returns.
+type WSHandler func(c *WSConn)
+
+// WSConn is an open WebSocket connection. Messages are queued and written by the
+// connection's own writer, so Send is safe from any goroutine.
+type WSConn struct {
+	userID int64
+	claims *jwt.Claims
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	send   chan WSMessage
+}
+
+// Context is done once the connection is closed.
+func (c *WSConn) Context() context.Context {
+	return c.ctx
+}
+
+// UserID is the ID of the user the connection authenticated as.
+func (c *WSConn) UserID() int64 {
+	return c.userID
+}
+
+// Send queues msg and reports whether it was queued. A client that lets its queue fill up
+// is too slow to keep up and is disconnected; it should reload its state when it reconnects.
+func (c *WSConn) Send(msg WSMessage) bool {
+	select {
+	case <-c.ctx.Done():
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		slog.WarnContext(c.ctx, "websocket client too slow, disconnecting", "user_id", c.userID)
+		c.cancel(nil)
+		return false
+	}
+}
+
+// wsManager upgrades authenticated requests and keeps the open connections, so they can be
+// closed on shutdown. A connection lives no longer than the access token it was opened with,
+// and each ping checks the token has not been revoked meanwhile.
+type wsManager struct {
+	upgrader     websocket.Upgrader
+	deny         denylist.Denylist
+	pingInterval time.Duration
+	writeTimeout time.Duration
+	sendBuffer   int
+	maxMessage   int64
+
+	mu    sync.Mutex
+	conns map[*WSConn]struct{}
+}
+
+func newWSManager(cfg config.Config, deny denylist.Denylist) *wsManager {
+	m := &wsManager{
+		deny:         deny,
+		pingInterval: defaultWSPingInterval,
+		writeTimeout: defaultWSWriteTimeout,
+		sendBuffer:   defaultWSSendBuffer,
+		maxMessage:   defaultWSMaxMessageSize,
+		conns:        make(map[*WSConn]struct{}),
+	}
+
+	origins := ""
+	if cfg != nil {
+		if v := cfg.GetSecond("app.server.websocket.ping_interval_seconds"); v > 0 {
+			m.pingInterval = v
+		}
+		if v := cfg.GetSecond("app.server.websocket.write_timeout_seconds"); v > 0 {
+			m.writeTimeout = v
+		}
+		if v := cfg.GetInt("app.server.websocket.send_buffer"); v > 0 {
+			m.sendBuffer = v
+		}
+		if v := cfg.GetInt64("app.server.websocket.max_message_bytes"); v > 0 {
+			m.maxMessage = v
+		}
+		origins = cfg.GetString("app.server.websocket.origins")
+		if origins == "" {
+			origins = cfg.GetString("app.server.cors.origins")
+		}
+	}
+
+	allowed := newOriginMatcher(splitOrigins(origins))
+	m.upgrader = websocket.Upgrader{
+		HandshakeTimeout: m.writeTimeout,
+		Subprotocols:     []string{WSSubprotocolBearer},
+		// browsers always send Origin, so a missing one is a server-side client
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || allowed.allowed(origin)
+		},
+		Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+			writeError(w, r, errorResponse{Message: reason.Error()}, status)
+		},
+	}
+
+	return m
+}
+
+func (m *wsManager) handler(h WSHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := jwt.GetAuth(r.Context())
+		if claims == nil {
+			writeError(w, r, errorResponse{Message: "Authentication required"}, http.StatusUnauthorized)
+			return
+		}
+		if claims.UserID == 0 {
+			// connections push a user's own updates, which a service token does not act for
+			writeError(w, r, errorResponse{Message: "a user token is required"}, http.StatusForbidden)
+			return
+		}
+
+		conn, err := m.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// the upgrader already answered the request
+			slog.WarnContext(r.Context(), "failed to upgrade websocket", "error", err)
+			return
+		}
+
+		ctx, cancel := context.WithCancelCause(r.Context())
+		if claims.ExpiresAt != nil {
+			var stop context.CancelFunc
+			ctx, stop = context.WithDeadlineCause(ctx, claims.ExpiresAt.Time, errWSTokenExpired)
+			defer stop()
+		}
+
+		c := &WSConn{
+			userID: claims.UserID,
+			claims: claims,
+			ctx:    ctx,
+			cancel: cancel,
+			send:   make(chan WSMessage, m.sendBuffer),
+		}
+
+		m.add(c)
+		defer m.remove(c)
+
+		var wg sync.WaitGroup
+		wg.Go(func() { m.write(conn, c) })
+		wg.Go(func() {
+			h(c)
+			cancel(nil)
+		})
+
+		m.read(conn)
+		cancel(nil)
+		wg.Wait()
+	})
+}
+
+// read consumes the frames of the client until it leaves or stops answering pings, so
+// pongs and close frames are processed. Messages from the client are ignored.
+func (m *wsManager) read(conn *websocket.Conn) {
+	pongWait := 2 * m.pingInterval
+
+	conn.SetReadLimit(m.maxMessage)
+	//nolint:errcheck // a failed deadline surfaces as a read error
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			return
+		}
+	}
+}
+
+// write sends the queued messages and a ping every interval, and closes the connection once
+// c is done, which also ends read. A connection closed because its token expired or was
+// revoked says so with a policy violation close frame, so the client signs in again before
+// reconnecting.
+func (m *wsManager) write(conn *websocket.Conn, c *WSConn) {
+	ticker := time.NewTicker(m.pingInterval)
+	defer ticker.Stop()
+
+	//nolint:errcheck // the connection is going away either way
+	defer conn.Close()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			code, reason := websocket.CloseGoingAway, ""
+			if cause := context.Cause(c.ctx); errors.Is(cause, errWSTokenExpired) || errors.Is(cause, errWSTokenRevoked) {
+				code, reason = websocket.ClosePolicyViolation, cause.Error()
+			}
+			//nolint:errcheck // best effort, the client may already be gone
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(code, reason),
+				time.Now().Add(m.writeTimeout))
+			return
+
+		case <-ticker.C:
+			if m.revoked(c) {
+				c.cancel(errWSTokenRevoked)
+				continue
+			}
+			//nolint:errcheck // a failed deadline surfaces as a write error
+			conn.SetWriteDeadline(time.Now().Add(m.writeTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.cancel(nil)
+			}
+
+		case msg := <-c.send:
+			//nolint:errcheck // a failed deadline surfaces as a write error
+			conn.SetWriteDeadline(time.Now().Add(m.writeTimeout))
+			if err := conn.WriteJSON(msg); err != nil {
+				slog.WarnContext(c.ctx, "failed to write websocket message", "user_id", c.userID, "error", err)
+				c.cancel(nil)
+			}
+		}
+	}
+}
+
+// revoked reports whether the token c was opened with has been revoked since. Like the
+// authentication middleware, an unreachable denylist keeps the connection open.
+func (m *wsManager) revoked(c *WSConn) bool {
+	if m.deny == nil || c.claims.IssuedAt == nil {
+		return false
+	}
+
+	revoked, err := m.deny.IsRevoked(c.ctx, c.claims.ID, c.claims.UserID, c.claims.IssuedAt.Time)
+	if err != nil {
+		slog.ErrorContext(c.ctx, "failed to check token denylist for websocket", "user_id", c.userID, "error", err)
+		return false
+	}
+
+	return revoked
+}
+
+func (m *wsManager) add(c *WSConn) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.conns[c] = struct{}{}
+}
+
+func (m *wsManager) remove(c *WSConn) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.conns, c)
+}
+
+// closeAll disconnects every client, for a shutdown: the HTTP server does not track the
+// connections it handed over.
+func (m *wsManager) closeAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for c := range m.conns {
+		c.cancel(nil)
+	}
+}
+
+// wsBearerToken returns the access token a WebSocket upgrade carries as subprotocol, see
+// WSSubprotocolBearer, or "".
+func wsBearerToken(r *http.Request) string {
+	if !websocket.IsWebSocketUpgrade(r) {
+		return ""
+	}
+
+	protocols := websocket.Subprotocols(r)
+	if len(protocols) != 2 || protocols[0] != WSSubprotocolBearer {
+		return ""
+	}
+
+	return protocols[1]
+}
+
+// WS registers a WebSocket endpoint on GET path. The upgrade is authenticated like any other
+// request, with the Authorization header or, from browsers, the bearer subprotocol.
+func (r *Router) WS(path string, h WSHandler, mws ...Middleware) {
+	r.HandleRaw(http.MethodGet, path, r.ws.handler(h), mws...)
+}
+
+// CloseWebSockets disconnects every WebSocket client. Register it with
+// http.Server.RegisterOnShutdown, as Shutdown leaves upgraded connections open.
+func (r *Router) CloseWebSockets() {
+	r.ws.closeAll()
+}
@@ -0,0 +1,6 @@
+// Package signedurl issues and verifies expiring, HMAC-signed URLs.
+//
+// A signature binds a purpose (what the link is for) and a resource (what it
+// points at) to an expiry, so a link cannot be edited to reach another resource,
+// reused for another purpose, or used after it expires.
+package signedurl
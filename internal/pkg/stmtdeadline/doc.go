@@ -0,0 +1,4 @@
+// Package stmtdeadline carries, in a context, the time by which the database statements
+// run under it should finish. The HTTP layer sets it and the database pool reads it, so
+// neither depends on the other.
+package stmtdeadline
@@ -0,0 +1,47 @@
+package stmtdeadline
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type contextKey struct{}
+
+// deadline is shared by pointer so it can be lifted after the context carrying it was
+// handed out.
+type deadline struct {
+	at atomic.Int64 // unix nanoseconds, 0 when lifted
+}
+
+// With bounds the statements run under ctx to finish by at. Unlike context.WithDeadline,
+// ctx itself is not canceled, so the caller decides what happens once the deadline passes.
+func With(ctx context.Context, at time.Time) context.Context {
+	d := &deadline{}
+	d.at.Store(at.UnixNano())
+
+	return context.WithValue(ctx, contextKey{}, d)
+}
+
+// Lift removes the bound With put on ctx, for work that may outlive it such as a streamed
+// download. The deadline of ctx itself still applies.
+func Lift(ctx context.Context) {
+	if d, ok := ctx.Value(contextKey{}).(*deadline); ok {
+		d.at.Store(0)
+	}
+}
+
+// From returns the statement deadline of ctx, if one is set and not lifted.
+func From(ctx context.Context) (time.Time, bool) {
+	d, ok := ctx.Value(contextKey{}).(*deadline)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	at := d.at.Load()
+	if at == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, at), true
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 )
 
 const (
@@ -27,18 +29,71 @@ type FactoryOptions struct {
 	GCS GCSOptions
 	// MinIO configures the MinIO backend.
 	MinIO MinIOOptions
+	// Params carries free-form settings for drivers added with Register.
+	Params map[string]string
+}
+
+// Constructor builds a Storage from the factory options.
+type Constructor func(ctx context.Context, opts FactoryOptions) (Storage, error)
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[string]Constructor{
+		DriverS3: func(ctx context.Context, opts FactoryOptions) (Storage, error) {
+			return NewS3(ctx, opts.S3)
+		},
+		DriverGCS: func(ctx context.Context, opts FactoryOptions) (Storage, error) {
+			return NewGCS(ctx, opts.GCS)
+		},
+		DriverMinIO: func(_ context.Context, opts FactoryOptions) (Storage, error) {
+			return NewMinIO(opts.MinIO)
+		},
+	}
+)
+
+// Register makes a storage backend available to NewFromDriver under driver.
+//
+// It is meant to be called from an init function of the package providing the
+// adapter. Like database/sql, it panics if the constructor is nil or the driver
+// name is already taken, since either is a programming error.
+func Register(driver string, c Constructor) {
+	driver = strings.ToLower(strings.TrimSpace(driver))
+
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	if c == nil {
+		panic("storage: Register constructor is nil")
+	}
+	if _, dup := drivers[driver]; dup {
+		panic("storage: Register called twice for driver " + driver)
+	}
+	drivers[driver] = c
+}
+
+// Drivers returns the sorted names of the registered drivers.
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
 }
 
 // NewFromDriver constructs a Storage implementation by driver name.
 func NewFromDriver(ctx context.Context, driver string, opts FactoryOptions) (Storage, error) {
-	switch strings.ToLower(driver) {
-	case DriverS3:
-		return NewS3(ctx, opts.S3)
-	case DriverGCS:
-		return NewGCS(ctx, opts.GCS)
-	case DriverMinIO:
-		return NewMinIO(opts.MinIO)
-	default:
+	driversMu.RLock()
+	c, ok := drivers[strings.ToLower(strings.TrimSpace(driver))]
+	driversMu.RUnlock()
+
+	if !ok {
 		return nil, fmt.Errorf("%w: %s", ErrUnknownDriver, driver)
 	}
+
+	return c(ctx, opts)
 }
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"time"
 
@@ -106,7 +107,7 @@ func (g *GCSAdapter) GetObject(ctx context.Context, bucket, key string, opts Get
 		reader, err = obj.NewReader(ctx)
 	}
 	if err != nil {
-		return nil, ObjectInfo{}, err
+		return nil, ObjectInfo{}, gcsError(err)
 	}
 	attrs, err := obj.Attrs(ctx)
 	if err != nil {
@@ -123,11 +124,20 @@ func (g *GCSAdapter) GetObject(ctx context.Context, bucket, key string, opts Get
 func (g *GCSAdapter) StatObject(ctx context.Context, bucket, key string) (ObjectInfo, error) {
 	attrs, err := g.client.Bucket(bucket).Object(key).Attrs(ctx)
 	if err != nil {
-		return ObjectInfo{}, err
+		return ObjectInfo{}, gcsError(err)
 	}
 	return gcsAttrsToInfo(attrs), nil
 }
 
+// gcsError wraps a missing object or bucket in ErrNotFound.
+func gcsError(err error) error {
+	if errors.Is(err, gcs.ErrObjectNotExist) || errors.Is(err, gcs.ErrBucketNotExist) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+
+	return err
+}
+
 // DeleteObject removes an object from GCS.
 func (g *GCSAdapter) DeleteObject(ctx context.Context, bucket, key string) error {
 	return g.client.Bucket(bucket).Object(key).Delete(ctx)
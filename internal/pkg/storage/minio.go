@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"time"
 
@@ -91,7 +92,7 @@ func (m *MinIOAdapter) GetObject(ctx context.Context, bucket, key string, opts G
 		if closeErr != nil {
 			return nil, ObjectInfo{}, closeErr
 		}
-		return nil, ObjectInfo{}, err
+		return nil, ObjectInfo{}, minioError(err)
 	}
 	return obj, minioStatToInfo(bucket, key, stat), nil
 }
@@ -100,7 +101,7 @@ func (m *MinIOAdapter) GetObject(ctx context.Context, bucket, key string, opts G
 func (m *MinIOAdapter) StatObject(ctx context.Context, bucket, key string) (ObjectInfo, error) {
 	stat, err := m.client.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
 	if err != nil {
-		return ObjectInfo{}, err
+		return ObjectInfo{}, minioError(err)
 	}
 	return minioStatToInfo(bucket, key, stat), nil
 }
@@ -158,6 +159,16 @@ func (m *MinIOAdapter) Close() error {
 	return nil
 }
 
+// minioError wraps a missing object or bucket in ErrNotFound.
+func minioError(err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NoSuchBucket":
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	default:
+		return err
+	}
+}
+
 func minioStatToInfo(bucket, key string, stat minio.ObjectInfo) ObjectInfo {
 	return ObjectInfo{
 		Bucket:      bucket,
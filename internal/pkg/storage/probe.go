@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProbeStep names the check a Probe was running when it failed.
+type ProbeStep string
+
+const (
+	// ProbeStepList lists the bucket, which needs valid credentials and an existing bucket.
+	ProbeStepList ProbeStep = "list"
+	// ProbeStepWrite uploads the canary object.
+	ProbeStepWrite ProbeStep = "write"
+	// ProbeStepRead reads the canary object's metadata back.
+	ProbeStepRead ProbeStep = "read"
+	// ProbeStepDelete removes the canary object.
+	ProbeStepDelete ProbeStep = "delete"
+)
+
+// ProbeError reports which check failed for which bucket, with a hint on how to fix it.
+type ProbeError struct {
+	Bucket string
+	Step   ProbeStep
+	Err    error
+}
+
+func (e *ProbeError) Error() string {
+	return fmt.Sprintf("storage: bucket %q failed %s check: %v (%s)", e.Bucket, e.Step, e.Err, e.Hint())
+}
+
+func (e *ProbeError) Unwrap() error {
+	return e.Err
+}
+
+// Hint suggests the most likely cause of the failure.
+func (e *ProbeError) Hint() string {
+	switch e.Step {
+	case ProbeStepList:
+		return "check the storage endpoint and credentials, and that the bucket exists in the configured region"
+	case ProbeStepWrite:
+		return "the credentials can see the bucket but need permission to put objects"
+	case ProbeStepRead:
+		return "the canary was written but its metadata cannot be read; grant permission to get objects"
+	case ProbeStepDelete:
+		return "the credentials need permission to delete objects; a canary object may be left behind"
+	default:
+		return "unexpected storage failure"
+	}
+}
+
+// Probe checks that bucket is reachable and writable by listing it, then writing, reading
+// back, and deleting a small canary object under prefix. The first failing check is
+// returned as a *ProbeError.
+func Probe(ctx context.Context, s Storage, bucket, prefix string) error {
+	if _, err := s.ListObjects(ctx, bucket, prefix, ListOptions{Limit: 1}); err != nil {
+		return &ProbeError{Bucket: bucket, Step: ProbeStepList, Err: err}
+	}
+
+	key := strings.Trim(prefix, "/")
+	if key != "" {
+		key += "/"
+	}
+	key += ".probe-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	body := []byte("ok")
+	if _, err := s.PutObject(ctx, bucket, key, bytes.NewReader(body), PutOptions{
+		Size:        int64(len(body)),
+		ContentType: "text/plain",
+	}); err != nil {
+		return &ProbeError{Bucket: bucket, Step: ProbeStepWrite, Err: err}
+	}
+
+	if _, err := s.StatObject(ctx, bucket, key); err != nil {
+		// still try to clean up; the read failure is the one worth reporting
+		_ = s.DeleteObject(ctx, bucket, key)
+		return &ProbeError{Bucket: bucket, Step: ProbeStepRead, Err: err}
+	}
+
+	if err := s.DeleteObject(ctx, bucket, key); err != nil {
+		return &ProbeError{Bucket: bucket, Step: ProbeStepDelete, Err: err}
+	}
+
+	return nil
+}
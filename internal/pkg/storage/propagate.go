@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"maps"
+
+	"github.com/shandysiswandi/gobite/internal/pkg/instrument"
+)
+
+// Propagating decorates a Storage so uploaded objects record the correlation ID,
+// user, and tenant of the uploading context in their metadata.
+type Propagating struct {
+	Storage
+}
+
+// NewPropagating wraps next.
+func NewPropagating(next Storage) *Propagating {
+	return &Propagating{Storage: next}
+}
+
+// PutObject adds the carrier of ctx to opts.Metadata. Keys already set by the caller win.
+func (p *Propagating) PutObject(ctx context.Context, bucket, key string, r io.Reader, opts PutOptions) (ObjectInfo, error) {
+	carrier := instrument.CarrierFromContext(ctx).Metadata()
+	if len(carrier) > 0 {
+		maps.Copy(carrier, opts.Metadata)
+		opts.Metadata = carrier
+	}
+
+	return p.Storage.PutObject(ctx, bucket, key, r, opts)
+}
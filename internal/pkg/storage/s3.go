@@ -2,6 +2,8 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"strconv"
 	"time"
@@ -10,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 // S3Adapter implements Storage using AWS S3.
@@ -107,7 +110,7 @@ func (s *S3Adapter) GetObject(ctx context.Context, bucket, key string, opts GetO
 	}
 	out, err := s.client.GetObject(ctx, input)
 	if err != nil {
-		return nil, ObjectInfo{}, err
+		return nil, ObjectInfo{}, s3Error(err)
 	}
 	info := ObjectInfo{
 		Bucket:      bucket,
@@ -130,7 +133,7 @@ func (s *S3Adapter) StatObject(ctx context.Context, bucket, key string) (ObjectI
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		return ObjectInfo{}, err
+		return ObjectInfo{}, s3Error(err)
 	}
 	info := ObjectInfo{
 		Bucket:      bucket,
@@ -146,6 +149,21 @@ func (s *S3Adapter) StatObject(ctx context.Context, bucket, key string) (ObjectI
 	return info, nil
 }
 
+// s3Error wraps a missing object or bucket in ErrNotFound. HeadObject has no body to carry
+// the error code, so a missing object there is the generic NotFound.
+func s3Error(err error) error {
+	var (
+		noKey    *types.NoSuchKey
+		noBucket *types.NoSuchBucket
+		notFound *types.NotFound
+	)
+	if errors.As(err, &noKey) || errors.As(err, &noBucket) || errors.As(err, &notFound) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+
+	return err
+}
+
 // DeleteObject removes an object from S3.
 func (s *S3Adapter) DeleteObject(ctx context.Context, bucket, key string) error {
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
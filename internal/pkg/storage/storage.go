@@ -7,8 +7,12 @@ import (
 	"time"
 )
 
-// ErrMissingSigner indicates signed URL support is not configured.
-var ErrMissingSigner = errors.New("storage: signed url signer not configured")
+var (
+	// ErrMissingSigner indicates signed URL support is not configured.
+	ErrMissingSigner = errors.New("storage: signed url signer not configured")
+	// ErrNotFound indicates the object does not exist.
+	ErrNotFound = errors.New("storage: object not found")
+)
 
 // Storage defines object storage operations.
 type Storage interface {
@@ -16,9 +20,11 @@ type Storage interface {
 
 	// PutObject stores data and returns object metadata.
 	PutObject(ctx context.Context, bucket, key string, r io.Reader, opts PutOptions) (ObjectInfo, error)
-	// GetObject retrieves data and metadata for the object.
+	// GetObject retrieves data and metadata for the object. A missing object is
+	// ErrNotFound.
 	GetObject(ctx context.Context, bucket, key string, opts GetOptions) (io.ReadCloser, ObjectInfo, error)
-	// StatObject returns object metadata without reading its contents.
+	// StatObject returns object metadata without reading its contents. A missing object
+	// is ErrNotFound.
 	StatObject(ctx context.Context, bucket, key string) (ObjectInfo, error)
 	// DeleteObject removes the object.
 	DeleteObject(ctx context.Context, bucket, key string) error
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrUnavailable indicates the storage backend failed to start and the app runs without it.
+var ErrUnavailable = errors.New("storage: unavailable")
+
+// Unavailable stands in for a backend that failed to start. Every call fails with
+// ErrUnavailable wrapping the startup error, so features that need storage report it per
+// request while the rest of the app keeps serving.
+type Unavailable struct {
+	err error
+}
+
+// NewUnavailable returns a Storage whose calls fail because of cause.
+func NewUnavailable(cause error) *Unavailable {
+	return &Unavailable{err: fmt.Errorf("%w: %w", ErrUnavailable, cause)}
+}
+
+// Close is a no-op.
+func (u *Unavailable) Close() error {
+	return nil
+}
+
+// PutObject returns the startup error.
+func (u *Unavailable) PutObject(context.Context, string, string, io.Reader, PutOptions) (ObjectInfo, error) {
+	return ObjectInfo{}, u.err
+}
+
+// GetObject returns the startup error.
+func (u *Unavailable) GetObject(context.Context, string, string, GetOptions) (io.ReadCloser, ObjectInfo, error) {
+	return nil, ObjectInfo{}, u.err
+}
+
+// StatObject returns the startup error.
+func (u *Unavailable) StatObject(context.Context, string, string) (ObjectInfo, error) {
+	return ObjectInfo{}, u.err
+}
+
+// DeleteObject returns the startup error.
+func (u *Unavailable) DeleteObject(context.Context, string, string) error {
+	return u.err
+}
+
+// ListObjects returns the startup error.
+func (u *Unavailable) ListObjects(context.Context, string, string, ListOptions) ([]ObjectInfo, error) {
+	return nil, u.err
+}
+
+// PresignGet returns the startup error.
+func (u *Unavailable) PresignGet(context.Context, string, string, time.Duration) (string, error) {
+	return "", u.err
+}
+
+// PresignPut returns the startup error.
+func (u *Unavailable) PresignPut(context.Context, string, string, PutOptions, time.Duration) (string, error) {
+	return "", u.err
+}
@@ -0,0 +1,156 @@
+// Package tabular reads and writes spreadsheet rows one at a time, so large files are never
+// held in memory as a whole.
+//
+// It supports:
+//   - CSV, read straight from the upload stream.
+//   - XLSX (first worksheet), spooled to a temporary file because a zip archive needs random access.
+//   - Writing either format straight to a response stream.
+package tabular
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a supported file format.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+var (
+	// ErrUnsupportedFormat indicates a file that is neither CSV nor XLSX.
+	ErrUnsupportedFormat = errors.New("tabular: unsupported file format")
+	// ErrFileTooLarge indicates an upload over the size limit.
+	ErrFileTooLarge = errors.New("tabular: file too large")
+	// ErrInvalidFile indicates a file that cannot be parsed in its format.
+	ErrInvalidFile = errors.New("tabular: invalid file")
+)
+
+// Reader returns the rows of a sheet in order.
+type Reader interface {
+	// Next returns the next row, or io.EOF after the last one. Rows may differ in length.
+	Next() ([]string, error)
+	// Close releases the temporary resources of the reader.
+	Close() error
+}
+
+// DetectFormat picks the format from a filename extension, falling back to the content type.
+func DetectFormat(filename, contentType string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return FormatCSV, nil
+	case strings.HasPrefix(contentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"):
+		return FormatXLSX, nil
+	}
+
+	return "", ErrUnsupportedFormat
+}
+
+// NewReader reads src as format. maxBytes caps how much of src is read (0 = no limit); a
+// larger file fails with ErrFileTooLarge.
+func NewReader(src io.Reader, format Format, maxBytes int64) (Reader, error) {
+	if maxBytes > 0 {
+		src = &limitedReader{r: src, left: maxBytes}
+	}
+
+	switch format {
+	case FormatCSV:
+		return newCSVReader(src), nil
+	case FormatXLSX:
+		return newXLSXReader(src)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+type csvReader struct {
+	r *csv.Reader
+}
+
+func newCSVReader(src io.Reader) *csvReader {
+	r := csv.NewReader(src)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.ReuseRecord = false
+
+	return &csvReader{r: r}
+}
+
+func (c *csvReader) Next() ([]string, error) {
+	row, err := c.r.Read()
+	if errors.Is(err, io.EOF) || errors.Is(err, ErrFileTooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+
+	// a UTF-8 BOM written by spreadsheet apps would otherwise stick to the first header
+	if line, _ := c.r.FieldPos(0); line == 1 && len(row) > 0 {
+		row[0] = strings.TrimPrefix(row[0], "\ufeff")
+	}
+
+	return row, nil
+}
+
+func (c *csvReader) Close() error {
+	return nil
+}
+
+// spool copies src to a temporary file the caller must remove.
+func spool(src io.Reader) (*os.File, int64, error) {
+	f, err := os.CreateTemp("", "tabular-*.xlsx")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	n, err := io.Copy(f, src)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, 0, err
+	}
+
+	return f, n, nil
+}
+
+type limitedReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.left <= 0 {
+		// probe one byte: a file of exactly the limit is fine
+		var one [1]byte
+		if n, err := l.r.Read(one[:]); n > 0 {
+			return 0, ErrFileTooLarge
+		} else if err != nil {
+			return 0, err
+		}
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > l.left {
+		p = p[:l.left]
+	}
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+
+	return n, err
+}
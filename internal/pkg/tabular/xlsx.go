@@ -0,0 +1,324 @@
+package tabular
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Caps that keep a small, highly compressed workbook (a zip bomb) from exhausting memory or
+// CPU. The row and column caps are the limits of Excel itself.
+const (
+	// maxSharedStrings caps the shared string table, the only part of a workbook held in memory.
+	maxSharedStrings = 1 << 20
+	// maxEntryBytes caps the uncompressed bytes read from any one zip entry.
+	maxEntryBytes = 256 << 20
+	// maxCellBytes caps the text of a single cell or shared string.
+	maxCellBytes = 64 << 10
+	// maxRows caps the rows read from the worksheet.
+	maxRows = 1 << 20
+	// maxColumns caps the cells of a row, counting the empty ones before the last value.
+	maxColumns = 1 << 14
+)
+
+type xlsxReader struct {
+	file    *os.File
+	sheet   io.ReadCloser
+	dec     *xml.Decoder
+	strings []string
+	rows    int
+}
+
+func newXLSXReader(src io.Reader) (_ *xlsxReader, err error) {
+	f, size, err := spool(src)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+
+	shared, err := readSharedStrings(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet, err := firstSheet(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := sheet.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+
+	return &xlsxReader{file: f, sheet: rc, dec: xml.NewDecoder(limitEntry(rc)), strings: shared}, nil
+}
+
+// limitEntry bounds the uncompressed size of a zip entry; reading past it fails with
+// ErrFileTooLarge.
+func limitEntry(rc io.Reader) io.Reader {
+	return &limitedReader{r: rc, left: maxEntryBytes}
+}
+
+// firstSheet returns the worksheet with the lowest number, which is the first one in a
+// workbook written by common spreadsheet apps.
+func firstSheet(zr *zip.Reader) (*zip.File, error) {
+	var sheets []*zip.File
+	for _, f := range zr.File {
+		if path.Dir(f.Name) == "xl/worksheets" && strings.HasSuffix(f.Name, ".xml") {
+			sheets = append(sheets, f)
+		}
+	}
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("%w: no worksheet", ErrInvalidFile)
+	}
+
+	sheetNumber := func(f *zip.File) int {
+		n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path.Base(f.Name), "sheet"), ".xml"))
+		return n
+	}
+	sort.Slice(sheets, func(i, j int) bool { return sheetNumber(sheets[i]) < sheetNumber(sheets[j]) })
+
+	return sheets[0], nil
+}
+
+func readSharedStrings(zr *zip.Reader) ([]string, error) {
+	var file *zip.File
+	for _, f := range zr.File {
+		if f.Name == "xl/sharedStrings.xml" {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return nil, nil
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	defer rc.Close()
+
+	var out []string
+	dec := xml.NewDecoder(limitEntry(rc))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "si" {
+			text, err := innerText(dec, "si")
+			if err != nil {
+				return nil, err
+			}
+			if len(out) >= maxSharedStrings {
+				return nil, ErrFileTooLarge
+			}
+			out = append(out, text)
+		}
+	}
+}
+
+// innerText concatenates the <t> runs inside the element named end, e.g. a rich text string.
+func innerText(dec *xml.Decoder, end string) (string, error) {
+	var b strings.Builder
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			// rPh holds phonetic hints, not cell text
+			if t.Name.Local == "rPh" {
+				if err := dec.Skip(); err != nil {
+					return "", fmt.Errorf("%w: %w", ErrInvalidFile, err)
+				}
+				continue
+			}
+			inText = t.Name.Local == "t"
+		case xml.EndElement:
+			if t.Name.Local == end {
+				return b.String(), nil
+			}
+			inText = false
+		case xml.CharData:
+			if inText {
+				if b.Len()+len(t) > maxCellBytes {
+					return "", fmt.Errorf("%w: cell text over %d bytes", ErrFileTooLarge, maxCellBytes)
+				}
+				b.Write(t)
+			}
+		}
+	}
+}
+
+func (x *xlsxReader) Next() ([]string, error) {
+	for {
+		tok, err := x.dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "row" {
+			if x.rows >= maxRows {
+				return nil, fmt.Errorf("%w: more than %d rows", ErrFileTooLarge, maxRows)
+			}
+			x.rows++
+			return x.readRow()
+		}
+	}
+}
+
+func (x *xlsxReader) readRow() ([]string, error) {
+	var row []string
+	next := 0
+	for {
+		tok, err := x.dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "c" {
+				continue
+			}
+
+			col := next
+			cellType := ""
+			for _, a := range t.Attr {
+				switch a.Name.Local {
+				case "r":
+					if c, ok := columnIndex(a.Value); ok {
+						col = c
+					}
+				case "t":
+					cellType = a.Value
+				}
+			}
+
+			if col >= maxColumns {
+				return nil, fmt.Errorf("%w: more than %d columns", ErrFileTooLarge, maxColumns)
+			}
+
+			value, err := x.readCell(cellType)
+			if err != nil {
+				return nil, err
+			}
+
+			// empty cells are usually omitted, so place the value by its reference
+			for len(row) < col {
+				row = append(row, "")
+			}
+			row = append(row, value)
+			next = col + 1
+		case xml.EndElement:
+			if t.Name.Local == "row" {
+				return row, nil
+			}
+		}
+	}
+}
+
+func (x *xlsxReader) readCell(cellType string) (string, error) {
+	if cellType == "inlineStr" {
+		return innerText(x.dec, "c")
+	}
+
+	var raw strings.Builder
+	inValue := false
+	for {
+		tok, err := x.dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			inValue = t.Name.Local == "v"
+		case xml.CharData:
+			if inValue {
+				if raw.Len()+len(t) > maxCellBytes {
+					return "", fmt.Errorf("%w: cell value over %d bytes", ErrFileTooLarge, maxCellBytes)
+				}
+				raw.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Local != "c" {
+				inValue = false
+				continue
+			}
+
+			value := raw.String()
+			switch cellType {
+			case "s":
+				i, err := strconv.Atoi(strings.TrimSpace(value))
+				if err != nil || i < 0 || i >= len(x.strings) {
+					return "", fmt.Errorf("%w: bad shared string index %q", ErrInvalidFile, value)
+				}
+				return x.strings[i], nil
+			case "b":
+				if value == "1" {
+					return "TRUE", nil
+				}
+				return "FALSE", nil
+			default:
+				return value, nil
+			}
+		}
+	}
+}
+
+// columnIndex converts the letters of a cell reference like "C5" to a zero-based column.
+func columnIndex(ref string) (int, bool) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 || n > 3 {
+		return 0, false
+	}
+
+	return col - 1, true
+}
+
+func (x *xlsxReader) Close() error {
+	errSheet := x.sheet.Close()
+	errFile := x.file.Close()
+	errRemove := os.Remove(x.file.Name())
+
+	return errors.Join(errSheet, errFile, errRemove)
+}
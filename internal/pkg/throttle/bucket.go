@@ -0,0 +1,233 @@
+package throttle
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limiter takes tokens from token buckets, so a client can burst up to the bucket size and
+// is then held to the refill rate.
+type Limiter interface {
+	// Take removes one token from the bucket of key, created full on first use.
+	Take(ctx context.Context, key string, rate Rate) (Decision, error)
+}
+
+// Rate is the shape of a token bucket: Limit tokens refill every Period, and the bucket holds
+// at most Burst tokens, Limit when Burst is not positive.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+
+// Capacity is the number of tokens a full bucket holds.
+func (r Rate) Capacity() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+
+	return r.Limit
+}
+
+// interval is the time one token takes to refill.
+func (r Rate) interval() time.Duration {
+	return r.Period / time.Duration(r.Limit)
+}
+
+func (r Rate) valid() bool {
+	return r.Limit > 0 && r.Period >= time.Duration(r.Limit)
+}
+
+// Decision is the outcome of a Take.
+type Decision struct {
+	// Allowed reports whether a token was taken.
+	Allowed bool
+	// Remaining is the number of whole tokens left.
+	Remaining int
+	// RetryAfter is how long until a token is available, zero when Allowed.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// takeScript refills the bucket for the time since its last use, taken from the Redis clock
+// so every instance agrees, and takes a token when one is there. It returns whether a token
+// was taken, the whole tokens left, and the retry and reset waits in ms.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local clock = redis.call("TIME")
+local now = clock[1] * 1000 + math.floor(clock[2] / 1000)
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+tokens = math.min(capacity, tokens + math.max(0, now - ts) / interval)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) * interval)
+end
+
+local reset = math.ceil((capacity - tokens) * interval)
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.max(reset, 1))
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// Bucket is a Limiter keeping its buckets in Redis, shared by every instance.
+type Bucket struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewBucket(client *redis.Client) *Bucket {
+	return &Bucket{
+		client: client,
+		prefix: "ratelimit:",
+	}
+}
+
+func (b *Bucket) Take(ctx context.Context, key string, rate Rate) (Decision, error) {
+	if !rate.valid() {
+		return Decision{Allowed: true}, nil
+	}
+
+	res, err := takeScript.Run(ctx, b.client, []string{b.prefix + key},
+		rate.Capacity(),
+		rate.interval().Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+
+	return Decision{
+		Allowed:    res[0] == 1,
+		Remaining:  int(res[1]),
+		RetryAfter: time.Duration(res[2]) * time.Millisecond,
+		Reset:      time.Duration(res[3]) * time.Millisecond,
+	}, nil
+}
+
+// memorySweepEvery is how many takes a MemoryBucket serves between sweeps of full buckets.
+const memorySweepEvery = 1024
+
+// MemoryBucket is a Limiter keeping its buckets in process memory. Each instance counts on
+// its own, so a limit applies per instance.
+type MemoryBucket struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryState
+	takes   int
+	now     func() time.Time
+}
+
+type memoryState struct {
+	tokens   float64
+	ts       time.Time
+	capacity float64
+	interval time.Duration
+}
+
+func NewMemoryBucket() *MemoryBucket {
+	return &MemoryBucket{
+		buckets: make(map[string]*memoryState),
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryBucket) Take(_ context.Context, key string, rate Rate) (Decision, error) {
+	if !rate.valid() {
+		return Decision{Allowed: true}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.takes++
+	if m.takes%memorySweepEvery == 0 {
+		m.sweep(now)
+	}
+
+	capacity := float64(rate.Capacity())
+	interval := rate.interval()
+
+	state, ok := m.buckets[key]
+	if !ok {
+		state = &memoryState{tokens: capacity, ts: now}
+		m.buckets[key] = state
+	}
+	state.capacity, state.interval = capacity, interval
+	state.refill(now)
+
+	var d Decision
+	if state.tokens >= 1 {
+		state.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = tokenWait(1-state.tokens, interval)
+	}
+	d.Remaining = int(state.tokens)
+	d.Reset = tokenWait(capacity-state.tokens, interval)
+
+	return d, nil
+}
+
+// sweep drops the buckets that have refilled completely, which a new bucket equals.
+func (m *MemoryBucket) sweep(now time.Time) {
+	for key, state := range m.buckets {
+		state.refill(now)
+		if state.tokens >= state.capacity {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+func (s *memoryState) refill(now time.Time) {
+	if elapsed := now.Sub(s.ts); elapsed > 0 {
+		s.tokens = math.Min(s.capacity, s.tokens+float64(elapsed)/float64(s.interval))
+	}
+	s.ts = now
+}
+
+func tokenWait(tokens float64, interval time.Duration) time.Duration {
+	return time.Duration(math.Ceil(tokens * float64(interval)))
+}
+
+// Fallback is a Limiter taking from primary, and from secondary while primary fails, so
+// limits keep applying during a Redis outage.
+type Fallback struct {
+	primary   Limiter
+	secondary Limiter
+}
+
+func NewFallback(primary, secondary Limiter) *Fallback {
+	return &Fallback{
+		primary:   primary,
+		secondary: secondary,
+	}
+}
+
+func (f *Fallback) Take(ctx context.Context, key string, rate Rate) (Decision, error) {
+	d, err := f.primary.Take(ctx, key, rate)
+	if err == nil {
+		return d, nil
+	}
+
+	slog.WarnContext(ctx, "rate limiter unavailable, using the fallback", "key", key, "error", err)
+
+	return f.secondary.Take(ctx, key, rate)
+}
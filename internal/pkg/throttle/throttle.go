@@ -0,0 +1,163 @@
+// Package throttle provides Redis-backed fixed-window counters used to rate
+// limit requests and lock out repeated failures with exponential backoff.
+package throttle
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Throttle counts events per key inside a fixed window.
+type Throttle interface {
+	// Hit records one event for key and returns how long the caller must wait
+	// when the count exceeds limit within window. A zero duration means allowed.
+	Hit(ctx context.Context, key string, limit int, window time.Duration) (time.Duration, error)
+	// Check reports the remaining block time once key has reached limit, without recording an event.
+	Check(ctx context.Context, key string, limit int) (time.Duration, error)
+	// Fail records one failure for key. The failure that reaches the policy limit locks key,
+	// so Check blocks it, and the returned duration is that lock. Zero means no lock started.
+	Fail(ctx context.Context, key string, policy LockPolicy) (time.Duration, error)
+	// Reset clears the counter and lock history for key.
+	Reset(ctx context.Context, key string) error
+}
+
+// LockPolicy controls how failures turn into locks.
+type LockPolicy struct {
+	// Limit is the number of failures within Window that locks the key.
+	Limit int
+	// Window is how long failures are counted before the counter starts over.
+	Window time.Duration
+	// Base is the first lock duration; every further lock within Memory doubles it.
+	Base time.Duration
+	// Max caps the lock duration. Values below Base disable the backoff.
+	Max time.Duration
+	// Memory is how long earlier locks keep counting toward the backoff.
+	Memory time.Duration
+}
+
+// hitScript increments the counter, starts the window on the first hit, and
+// returns the new count with the remaining window in milliseconds.
+var hitScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+// failScript counts a failure and, when it is the one that reaches the limit, locks the
+// key by stretching its expiry to base * 2^(locks-1) capped at max. It returns the lock in ms.
+var failScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if count ~= tonumber(ARGV[1]) then
+	return 0
+end
+local locks = redis.call("INCR", KEYS[2])
+redis.call("PEXPIRE", KEYS[2], ARGV[5])
+local lock = math.min(tonumber(ARGV[3]) * (2 ^ (locks - 1)), tonumber(ARGV[4]))
+redis.call("PEXPIRE", KEYS[1], lock)
+return lock
+`)
+
+type Counter struct {
+	client *redis.Client
+	prefix string
+}
+
+func New(client *redis.Client) *Counter {
+	return &Counter{
+		client: client,
+		prefix: "throttle:",
+	}
+}
+
+func (c *Counter) Hit(ctx context.Context, key string, limit int, window time.Duration) (time.Duration, error) {
+	if limit <= 0 || window <= 0 {
+		return 0, nil
+	}
+
+	res, err := hitScript.Run(ctx, c.client, []string{c.key(key)}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, err
+	}
+
+	if res[0] <= int64(limit) {
+		return 0, nil
+	}
+
+	return ttlOrWindow(res[1], window), nil
+}
+
+func (c *Counter) Check(ctx context.Context, key string, limit int) (time.Duration, error) {
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	fk := c.key(key)
+
+	count, err := c.client.Get(ctx, fk).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if count < int64(limit) {
+		return 0, nil
+	}
+
+	ttl, err := c.client.PTTL(ctx, fk).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return ttlOrWindow(ttl.Milliseconds(), time.Second), nil
+}
+
+func (c *Counter) Fail(ctx context.Context, key string, policy LockPolicy) (time.Duration, error) {
+	if policy.Limit <= 0 || policy.Window <= 0 || policy.Base <= 0 {
+		return 0, nil
+	}
+
+	maxLock := max(policy.Max, policy.Base)
+	memory := max(policy.Memory, maxLock)
+
+	ms, err := failScript.Run(ctx, c.client, []string{c.key(key), c.key(key) + ":locks"},
+		policy.Limit,
+		policy.Window.Milliseconds(),
+		policy.Base.Milliseconds(),
+		maxLock.Milliseconds(),
+		memory.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+func (c *Counter) Reset(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.key(key), c.key(key)+":locks").Err()
+}
+
+// key wraps key in a hash tag, so the counter and its lock history land in one
+// Redis Cluster slot and the scripts touching both do not fail with CROSSSLOT.
+func (c *Counter) key(key string) string {
+	return c.prefix + "{" + key + "}"
+}
+
+// ttlOrWindow guards against keys without an expiry so callers always get a positive wait.
+func ttlOrWindow(ms int64, fallback time.Duration) time.Duration {
+	if ms <= 0 {
+		return fallback
+	}
+
+	return time.Duration(ms) * time.Millisecond
+}
@@ -0,0 +1,48 @@
+// Package userdata collects what every module holds about a single user, so the module
+// owning accounts can export or erase it without reaching into other modules' tables.
+package userdata
+
+import (
+	"context"
+	"sync"
+)
+
+// Source is the personal data one module keeps about a user.
+type Source struct {
+	// Name identifies the source and names its file in an export archive.
+	Name string
+	// Export returns the data held about userID; the result is encoded as JSON.
+	Export func(ctx context.Context, userID int64) (any, error)
+	// Erase removes the data held about userID. It must be safe to call more than once.
+	Erase func(ctx context.Context, userID int64) error
+}
+
+// Registry collects the sources registered by modules.
+type Registry struct {
+	mu      sync.Mutex
+	sources []Source
+}
+
+// NewRegistry returns a Registry without sources.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds sources to every following export and erasure.
+func (r *Registry) Register(sources ...Source) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sources = append(r.sources, sources...)
+}
+
+// Sources returns the registered sources in registration order.
+func (r *Registry) Sources() []Source {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]Source, len(r.sources))
+	copy(out, r.sources)
+
+	return out
+}
@@ -14,11 +14,15 @@ import (
 	"github.com/shandysiswandi/gobite/internal/pkg/strcase"
 )
 
-var (
-	// Based on NIST 800-63B Guidelines
-	rePassword = regexp.MustCompile(`^.{8,72}$`)
+// Password length bounds enforced by the "password" tag, based on NIST 800-63B guidelines.
+// The upper bound is the input limit of bcrypt.
+const (
+	PasswordMinLength = 8
+	PasswordMaxLength = 72
 )
 
+var rePassword = regexp.MustCompile(fmt.Sprintf(`^.{%d,%d}$`, PasswordMinLength, PasswordMaxLength))
+
 // ErrTranslatorNotFound indicates the requested translator is unavailable.
 var ErrTranslatorNotFound = errors.New("translator not found")
 
@@ -106,7 +110,7 @@ func v10CustomValidation(validate *validator.Validate, enTrans ut.Translator) {
 
 	validate.RegisterTranslation("password", enTrans,
 		func(ut ut.Translator) error {
-			return ut.Add("password", "{0} must be 8-72 characters", false)
+			return ut.Add("password", fmt.Sprintf("{0} must be %d-%d characters", PasswordMinLength, PasswordMaxLength), false)
 		},
 		func(ut ut.Translator, fe validator.FieldError) string {
 			t, _ := ut.T(fe.Tag(), fe.Field())
@@ -0,0 +1,37 @@
+package valueobject
+
+import "time"
+
+// Version is the optimistic concurrency token of a record: its updated_at in
+// microseconds, the precision PostgreSQL keeps. Clients read it with the record and send
+// it back with a mutation, which is refused when the record changed in between.
+type Version int64
+
+// VersionOf returns the version of a record last updated at t; zero for a zero t.
+func VersionOf(t time.Time) Version {
+	if t.IsZero() {
+		return 0
+	}
+
+	return Version(t.UnixMicro())
+}
+
+// IsZero reports whether no version was given, meaning the caller sets no precondition.
+func (v Version) IsZero() bool {
+	return v == 0
+}
+
+// Time returns the updated_at the version stands for.
+func (v Version) Time() time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+
+	return time.UnixMicro(int64(v)).UTC()
+}
+
+// Matches reports whether a record last updated at t still has version v. A zero v
+// matches any record.
+func (v Version) Matches(t time.Time) bool {
+	return v == 0 || VersionOf(t) == v
+}
@@ -9,4 +9,23 @@ const (
 
 const (
 	PermIdentityMgmtUsers = "identity:management:users"
+	PermIdentityMgmtRoles = "identity:management:roles"
+
+	PermIdentityMgmtServiceAccounts = "identity:management:service_accounts"
+	PermIdentityMgmtImpersonation   = "identity:management:impersonation"
+	PermIdentityMgmtOrgs            = "identity:management:orgs"
+
+	// Held by the owner of the API key an identity provider provisions through SCIM with.
+	PermIdentitySCIM = "identity:scim"
+
+	// Checked per organization against the org-scoped roles (Casbin p2/g2).
+	PermIdentityOrg        = "identity:org"
+	PermIdentityOrgMembers = "identity:org:members"
+
+	PermNotificationMgmtArchives  = "notification:management:archives"
+	PermNotificationMgmtTemplates = "notification:management:templates"
+
+	PermAuditEvents = "audit:events"
+
+	PermAdminJobs = "admin:jobs"
 )
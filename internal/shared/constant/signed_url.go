@@ -0,0 +1,7 @@
+package constant
+
+const (
+	SignedURLPurposeEmailVerify   = "email_verify"
+	SignedURLPurposeUnsubscribe   = "notification_unsubscribe"
+	SignedURLPurposeProfileExport = "profile_export"
+)
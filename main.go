@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"flag"
+	"log/slog"
+	"os"
 	"time"
 
 	"github.com/shandysiswandi/gobite/internal/app"
@@ -23,7 +26,44 @@ import (
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT.
 func main() {
-	application := app.New()    // Initialize the application
+	check := flag.Bool("check", false, "validate the config and every connection, then exit")
+	bootstrapMessaging := flag.Bool("bootstrap-messaging", false, "create the missing message bus topics and subscriptions, then exit")
+	seedNotifications := flag.Bool("seed-notifications", false, "insert the missing notification categories, default settings, and templates, then exit")
+	verifyNotifications := flag.Bool("verify-notifications", false, "report notification rows that differ from the fixtures and fail on any, then exit")
+	flag.Parse()
+
+	if *bootstrapMessaging {
+		if err := app.BootstrapMessaging(); err != nil {
+			slog.Error("messaging bootstrap failed", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("messaging bootstrap finished")
+		return
+	}
+
+	if *seedNotifications || *verifyNotifications {
+		if err := app.SeedNotifications(*verifyNotifications); err != nil {
+			slog.Error("notification seeding failed", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("notification seeding finished")
+		return
+	}
+
+	if *check {
+		if err := app.Check(); err != nil {
+			slog.Error("startup check failed", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("startup check passed")
+		return
+	}
+
+	application, err := app.New() // Initialize the application
+	if err != nil {
+		slog.Error("failed to start application", "error", err)
+		os.Exit(1)
+	}
 	wait := application.Start() // Start the application and wait for the termination signal
 	<-wait                      // Wait for the application to receive a termination signal
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+type adminJobData struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Running  bool   `json:"running"`
+	Runs     int64  `json:"runs"`
+	Failures int64  `json:"failures"`
+}
+
+type adminJobsData struct {
+	Jobs []adminJobData `json:"jobs"`
+}
+
+type adminJobRunData struct {
+	Job adminJobData `json:"job"`
+}
+
+func TestAdminJobs(t *testing.T) {
+	token := adminToken(t)
+
+	t.Run("List", func(t *testing.T) {
+		status, body := doJSON(t, http.MethodGet, "/api/v1/admin/jobs", nil, token)
+		if status != http.StatusOK {
+			errEnv := decodeError(t, body)
+			t.Fatalf("list jobs failed: status=%d message=%q", status, errEnv.Message)
+		}
+
+		var data adminJobsData
+		decodeSuccess(t, body, &data)
+
+		found := false
+		for _, job := range data.Jobs {
+			if job.Name == "retention" {
+				found = true
+				if job.Kind != "scheduled" {
+					t.Fatalf("expected retention to be scheduled, got %q", job.Kind)
+				}
+			}
+		}
+		if !found {
+			t.Fatal("expected the retention job in the list")
+		}
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		status, body := doJSON(t, http.MethodPost, "/api/v1/admin/jobs/retention/run", nil, token)
+		if status == http.StatusConflict {
+			// a scheduled or earlier manual run is still going
+			return
+		}
+		if status != http.StatusOK {
+			errEnv := decodeError(t, body)
+			t.Fatalf("run job failed: status=%d message=%q", status, errEnv.Message)
+		}
+
+		var data adminJobRunData
+		decodeSuccess(t, body, &data)
+		if data.Job.Name != "retention" || !data.Job.Running {
+			t.Fatalf("expected retention to be running, got %+v", data.Job)
+		}
+	})
+
+	t.Run("RunUnknown", func(t *testing.T) {
+		status, _ := doJSON(t, http.MethodPost, "/api/v1/admin/jobs/does_not_exist/run", nil, token)
+		if status != http.StatusNotFound {
+			t.Fatalf("expected status %d, got %d", http.StatusNotFound, status)
+		}
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		status, _ := doJSON(t, http.MethodGet, "/api/v1/admin/jobs", nil, "")
+		if status != http.StatusUnauthorized {
+			t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, status)
+		}
+	})
+}
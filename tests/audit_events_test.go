@@ -0,0 +1,214 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type auditEventData struct {
+	ID            string         `json:"id"`
+	Module        string         `json:"module"`
+	Action        string         `json:"action"`
+	ActorID       string         `json:"actor_id"`
+	SubjectID     string         `json:"subject_id"`
+	IP            string         `json:"ip"`
+	CorrelationID string         `json:"correlation_id"`
+	Metadata      map[string]any `json:"metadata"`
+}
+
+type auditEventsData struct {
+	Events []auditEventData `json:"events"`
+}
+
+// waitAuditEvents polls the audit endpoint until the consumer has stored a matching event.
+func waitAuditEvents(t *testing.T, token, query string) []auditEventData {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		status, body := doJSON(t, http.MethodGet, "/api/v1/audit/events?"+query, nil, token)
+		if status != http.StatusOK {
+			errEnv := decodeError(t, body)
+			t.Fatalf("list audit events failed: status=%d message=%q", status, errEnv.Message)
+		}
+
+		var data auditEventsData
+		decodeSuccess(t, body, &data)
+		if len(data.Events) > 0 {
+			return data.Events
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("no audit event matched %q", query)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func TestAuditEventsLogin(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	subject := strconv.FormatInt(user.ID, 10)
+
+	// Act
+	login(t, user.Email, user.Password)
+
+	// Assert
+	events := waitAuditEvents(t, token, "module=identity&action=auth.login&subject_id="+subject)
+	ev := events[0]
+	if ev.ActorID != subject || ev.SubjectID != subject {
+		t.Fatalf("expected actor and subject %s, got actor=%s subject=%s", subject, ev.ActorID, ev.SubjectID)
+	}
+	if ev.IP == "" {
+		t.Fatal("expected client ip on audit event")
+	}
+	if ev.CorrelationID == "" {
+		t.Fatal("expected correlation id on audit event")
+	}
+
+	status, body := doJSON(t, http.MethodGet, "/api/v1/audit/events/"+ev.ID, nil, token)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("audit event detail failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// the admin mutation is recorded with the admin as actor
+	events = waitAuditEvents(t, token, "action=user.create&subject_id="+subject)
+	if events[0].ActorID != "1" {
+		t.Fatalf("expected actor 1, got %s", events[0].ActorID)
+	}
+}
+
+func TestAuditEventsRejections(t *testing.T) {
+	admin := adminToken(t)
+	user := createUser(t, admin)
+	userToken := login(t, user.Email, user.Password).AccessToken
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+	}{
+		{
+			name:  "Unauthenticated",
+			path:  "/api/v1/audit/events",
+			token: "",
+			want:  http.StatusUnauthorized,
+		},
+		{
+			name:  "RegularUser",
+			path:  "/api/v1/audit/events",
+			token: userToken,
+			want:  http.StatusForbidden,
+		},
+		{
+			name:  "InvalidActorID",
+			path:  "/api/v1/audit/events?actor_id=abc",
+			token: admin,
+			want:  http.StatusBadRequest,
+		},
+		{
+			name:  "InvalidDateRange",
+			path:  "/api/v1/audit/events?date_from=2026-02-01T00:00:00Z&date_to=2026-01-01T00:00:00Z",
+			token: admin,
+			want:  http.StatusBadRequest,
+		},
+		{
+			name:  "NotFound",
+			path:  "/api/v1/audit/events/999999999",
+			token: admin,
+			want:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			status, _ := doJSON(t, http.MethodGet, tc.path, nil, tc.token)
+
+			// Assert
+			if status != tc.want {
+				t.Fatalf("expected status %d, got %d", tc.want, status)
+			}
+		})
+	}
+}
+
+func TestAdminAuditTrail(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	first := createUser(t, token)
+	second := createUser(t, token)
+	waitAuditEvents(t, token, "action=user.create&subject_id="+strconv.FormatInt(first.ID, 10))
+	waitAuditEvents(t, token, "action=user.create&subject_id="+strconv.FormatInt(second.ID, 10))
+
+	// Act
+	status, body := doJSON(t, http.MethodGet, "/api/v1/admin/audit?resource_type=user&actor_id=1&size=1", nil, token)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("search audit trail failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var page auditEventsData
+	env := decodeSuccess(t, body, &page)
+	cursor, _ := env.Meta["next_cursor"].(string)
+	if len(page.Events) != 1 || cursor == "" {
+		t.Fatalf("expected one event and a next cursor, got %d events cursor=%q", len(page.Events), cursor)
+	}
+
+	status, body = doJSON(t, http.MethodGet, "/api/v1/admin/audit?resource_type=user&actor_id=1&size=1&cursor="+cursor, nil, token)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("search audit trail next page failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var next auditEventsData
+	decodeSuccess(t, body, &next)
+	if len(next.Events) != 1 || next.Events[0].ID == page.Events[0].ID {
+		t.Fatalf("expected a different event on the next page, got %+v", next.Events)
+	}
+
+	status, body = doJSON(t, http.MethodGet, "/api/v1/admin/audit/export?action=user.create&subject_id="+strconv.FormatInt(first.ID, 10), nil, token)
+	if status != http.StatusOK {
+		t.Fatalf("export audit trail failed: status=%d body=%s", status, body)
+	}
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "id,occurred_at,") || !strings.Contains(lines[1], "user.create") {
+		t.Fatalf("unexpected csv export: %q", body)
+	}
+}
+
+func TestAdminAuditTrailInvalid(t *testing.T) {
+	// Arrange
+	admin := adminToken(t)
+	user := createUser(t, admin)
+	userToken := login(t, user.Email, user.Password).AccessToken
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+	}{
+		{name: "RegularUser", path: "/api/v1/admin/audit", token: userToken, want: http.StatusForbidden},
+		{name: "RegularUserExport", path: "/api/v1/admin/audit/export", token: userToken, want: http.StatusForbidden},
+		{name: "InvalidCursor", path: "/api/v1/admin/audit?cursor=not-a-cursor", token: admin, want: http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			status, _ := doJSON(t, http.MethodGet, tc.path, nil, tc.token)
+
+			// Assert
+			if status != tc.want {
+				t.Fatalf("expected status %d, got %d", tc.want, status)
+			}
+		})
+	}
+}
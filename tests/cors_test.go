@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func doPreflight(t *testing.T, path, origin string) http.Header {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodOptions, strings.TrimRight(baseURL(), "/")+path, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	return resp.Header
+}
+
+func TestCORSPreflight(t *testing.T) {
+	// Act
+	allowed := doPreflight(t, "/api/v1/identity/login", "http://localhost:3330")
+	denied := doPreflight(t, "/api/v1/identity/login", "https://evil.example.org")
+	public := doPreflight(t, "/.well-known/gobite-configuration", "https://sdk.example.org")
+
+	// Assert
+	if got := allowed.Get("Access-Control-Allow-Origin"); got != "http://localhost:3330" {
+		t.Fatalf("expected configured origin to be allowed, got %q", got)
+	}
+	if got := denied.Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected unknown origin to be denied, got %q", got)
+	}
+	if got := public.Get("Access-Control-Allow-Origin"); got == "" {
+		t.Fatalf("expected public route to allow any origin")
+	}
+}
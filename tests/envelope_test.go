@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func doAccept(t *testing.T, method, path, accept, token string) (int, http.Header, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, strings.TrimRight(baseURL(), "/")+path, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Accept", accept)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+
+	return resp.StatusCode, resp.Header, body
+}
+
+func TestEnvelopeV2Success(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, header, body := doAccept(t, http.MethodGet, "/api/v1/identity/users?size=1", "application/vnd.gobite.v2+json", token)
+
+	// Assert
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", status, body)
+	}
+	if ct := header.Get("Content-Type"); ct != "application/vnd.gobite.v2+json" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	var env struct {
+		Message *string `json:"message"`
+		Data    struct {
+			Users []struct {
+				UpdatedAt string `json:"updated_at"`
+			} `json:"users"`
+		} `json:"data"`
+		Meta struct {
+			Pagination struct {
+				Size       int     `json:"size"`
+				HasMore    *bool   `json:"has_more"`
+				NextCursor *string `json:"next_cursor"`
+			} `json:"pagination"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(body, &env); err != nil {
+		t.Fatalf("decode v2 envelope: %v", err)
+	}
+	if env.Message != nil {
+		t.Fatalf("v2 envelope must not carry message")
+	}
+	if env.Meta.Pagination.Size != 1 || env.Meta.Pagination.HasMore == nil {
+		t.Fatalf("unexpected pagination meta: %s", body)
+	}
+	for _, user := range env.Data.Users {
+		ts, err := time.Parse(time.RFC3339, user.UpdatedAt)
+		if err != nil || ts.Location() != time.UTC || strings.Contains(user.UpdatedAt, ".") {
+			t.Fatalf("expected RFC3339 UTC timestamp, got %q", user.UpdatedAt)
+		}
+	}
+}
+
+func TestEnvelopeV2Error(t *testing.T) {
+	// Act
+	status, _, body := doAccept(t, http.MethodGet, "/api/v1/identity/users", "application/vnd.gobite.v2+json", "")
+
+	// Assert
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", status)
+	}
+
+	var env struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &env); err != nil {
+		t.Fatalf("decode v2 error: %v", err)
+	}
+	if env.Error.Code != "ERROR_CODE_UNAUTHORIZED" || env.Error.Message == "" {
+		t.Fatalf("unexpected v2 error: %s", body)
+	}
+}
+
+func TestEnvelopeDefaultsToV1(t *testing.T) {
+	// Act
+	status, header, body := doAccept(t, http.MethodGet, "/api/v1/identity/users", "application/json", "")
+
+	// Assert
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", status)
+	}
+	if ct := header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if env := decodeError(t, body); env.Message == "" {
+		t.Fatalf("expected v1 error message, got %s", body)
+	}
+}
+
+func TestEnvelopeUnsupportedVersion(t *testing.T) {
+	// Act
+	status, _, _ := doAccept(t, http.MethodGet, "/api/v1/identity/users", "application/vnd.gobite.v9+json", "")
+
+	// Assert
+	if status != http.StatusNotAcceptable {
+		t.Fatalf("expected 406, got %d", status)
+	}
+}
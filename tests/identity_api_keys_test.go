@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type apiKeyData struct {
+	ID     int64               `json:"id"`
+	Prefix string              `json:"prefix"`
+	Scopes map[string][]string `json:"scopes"`
+	Key    string              `json:"key"`
+}
+
+func doAPIKey(t *testing.T, method, path, key string) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, strings.TrimRight(baseURL(), "/")+path, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("X-API-Key", key)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	payload := map[string]any{
+		"name":            "reporting",
+		"scopes":          map[string][]string{"identity:management:users": {"read"}},
+		"expires_in_days": 30,
+	}
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/api-keys", payload, token)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("create api key failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var created apiKeyData
+	decodeSuccess(t, body, &created)
+	if created.Key == "" || !strings.HasPrefix(created.Key, created.Prefix) {
+		t.Fatalf("expected key starting with prefix %q, got %q", created.Prefix, created.Key)
+	}
+
+	if status := doAPIKey(t, http.MethodGet, "/api/v1/identity/users", created.Key); status != http.StatusOK {
+		t.Fatalf("expected scoped request to succeed, got status=%d", status)
+	}
+
+	if status := doAPIKey(t, http.MethodGet, "/api/v1/identity/roles", created.Key); status != http.StatusForbidden {
+		t.Fatalf("expected request outside the key scopes to be forbidden, got status=%d", status)
+	}
+
+	if status := doAPIKey(t, http.MethodGet, "/api/v1/identity/api-keys", created.Key); status != http.StatusForbidden {
+		t.Fatalf("expected api key management with an api key to be forbidden, got status=%d", status)
+	}
+
+	for _, path := range []string{"/api/v1/identity/profile", "/api/v1/identity/sessions", "/api/v1/identity/profile/logins"} {
+		if status := doAPIKey(t, http.MethodGet, path, created.Key); status != http.StatusForbidden {
+			t.Fatalf("expected self-service %s with an api key to be forbidden, got status=%d", path, status)
+		}
+	}
+	if status := doAPIKey(t, http.MethodPost, "/api/v1/identity/logout-all", created.Key); status != http.StatusForbidden {
+		t.Fatalf("expected logout-all with an api key to be forbidden, got status=%d", status)
+	}
+
+	status, body = doJSON(t, http.MethodDelete, "/api/v1/identity/api-keys/"+strconv.FormatInt(created.ID, 10), nil, token)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("revoke api key failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	if status := doAPIKey(t, http.MethodGet, "/api/v1/identity/users", created.Key); status != http.StatusUnauthorized {
+		t.Fatalf("expected revoked key to be unauthorized, got status=%d", status)
+	}
+}
+
+func TestAPIKeyCreateScopeNotAllowed(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	loginResp := login(t, user.Email, user.Password)
+	payload := map[string]any{
+		"name":   "escalation",
+		"scopes": map[string][]string{"identity:management:users": {"delete"}},
+	}
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/api-keys", payload, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusForbidden {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected scope beyond the user's permissions to be forbidden, got status=%d message=%q", status, errEnv.Message)
+	}
+}
+
+func TestAPIKeyInvalid(t *testing.T) {
+	// Act
+	status := doAPIKey(t, http.MethodGet, "/api/v1/identity/profile", "gbk_invalid")
+
+	// Assert
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected invalid key to be unauthorized, got status=%d", status)
+	}
+}
+
+func TestAPIKeyCreateWhileImpersonating(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/users/"+strconv.FormatInt(user.ID, 10)+"/impersonate",
+		map[string]any{"reason": "check api key creation is refused"}, token)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("impersonate failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var impersonated struct {
+		AccessToken string `json:"access_token"`
+	}
+	decodeSuccess(t, body, &impersonated)
+	payload := map[string]any{"name": "persistence", "scopes": map[string][]string{}}
+
+	// Act
+	status, body = doJSON(t, http.MethodPost, "/api/v1/identity/api-keys", payload, impersonated.AccessToken)
+
+	// Assert
+	if status != http.StatusForbidden {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected api key creation while impersonating to be forbidden, got status=%d message=%q", status, errEnv.Message)
+	}
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestEmailChange(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	loginResp := login(t, user.Email, user.Password)
+	payload := map[string]string{
+		"new_email":        uniqueEmail("email-change"),
+		"current_password": user.Password,
+	}
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/email/change", payload, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("email change failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// The old address stays active until the change is confirmed.
+	if relogin := login(t, user.Email, user.Password); relogin.AccessToken == "" {
+		t.Fatalf("expected login with current email to keep working")
+	}
+}
+
+func TestEmailChangeRejections(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	other := createUser(t, token)
+	loginResp := login(t, user.Email, user.Password)
+
+	cases := []struct {
+		name    string
+		payload map[string]string
+		token   string
+		status  int
+	}{
+		{
+			name:    "Unauthenticated",
+			payload: map[string]string{"new_email": uniqueEmail("email-change"), "current_password": user.Password},
+			status:  http.StatusUnauthorized,
+		},
+		{
+			name:    "WrongPassword",
+			payload: map[string]string{"new_email": uniqueEmail("email-change"), "current_password": "Wrong123!"},
+			token:   loginResp.AccessToken,
+			status:  http.StatusUnauthorized,
+		},
+		{
+			name:    "EmailTaken",
+			payload: map[string]string{"new_email": other.Email, "current_password": user.Password},
+			token:   loginResp.AccessToken,
+			status:  http.StatusConflict,
+		},
+		{
+			name:    "SameEmail",
+			payload: map[string]string{"new_email": user.Email, "current_password": user.Password},
+			token:   loginResp.AccessToken,
+			status:  http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			status, body := doJSON(t, http.MethodPost, "/api/v1/identity/email/change", tc.payload, tc.token)
+
+			// Assert
+			if status != tc.status {
+				errEnv := decodeError(t, body)
+				t.Fatalf("expected status %d, got %d message=%q", tc.status, status, errEnv.Message)
+			}
+		})
+	}
+}
+
+func TestEmailChangeConfirmInvalidToken(t *testing.T) {
+	// Arrange
+	payload := map[string]string{"challenge_token": "invalid-token"}
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/email/change/confirm", payload, "")
+
+	// Assert
+	if status != http.StatusUnauthorized {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected status 401, got %d message=%q", status, errEnv.Message)
+	}
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+type exportJobData struct {
+	ID          string `json:"id"`
+	Format      string `json:"format"`
+	Status      string `json:"status"`
+	RowsTotal   int64  `json:"rows_total"`
+	RowsWritten int64  `json:"rows_written"`
+	URL         string `json:"url"`
+}
+
+func TestUserExportJob(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/exports", map[string]any{
+		"format": "csv",
+		"status": []int{2},
+	}, token)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("queue export failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var created exportJobData
+	decodeSuccess(t, body, &created)
+	if created.ID == "" || created.Format != "csv" {
+		t.Fatalf("unexpected export job: %+v", created)
+	}
+
+	status, body = doJSON(t, http.MethodGet, "/api/v1/identity/exports/"+created.ID, nil, token)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("get export job failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var polled exportJobData
+	decodeSuccess(t, body, &polled)
+	if polled.ID != created.ID {
+		t.Fatalf("expected job %s, got %s", created.ID, polled.ID)
+	}
+	if !slices.Contains([]string{"Pending", "Running", "Completed", "Failed"}, polled.Status) {
+		t.Fatalf("unexpected job status %q", polled.Status)
+	}
+	if polled.Status == "Completed" && polled.URL == "" {
+		t.Fatalf("expected a download link for a completed job")
+	}
+}
+
+func TestUserExportJobInvalidFormat(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, _ := doJSON(t, http.MethodPost, "/api/v1/identity/exports", map[string]any{"format": "pdf"}, token)
+
+	// Assert
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for an unknown format, got %d", status)
+	}
+}
+
+func TestUserExportJobNotFound(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/exports/1", nil, token)
+
+	// Assert
+	if status != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown job, got %d", status)
+	}
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+type forcedLoginResponse struct {
+	ChallengeToken        string `json:"challenge_token"`
+	AccessToken           string `json:"access_token"`
+	PasswordResetRequired bool   `json:"password_reset_required"`
+	MfaSetupRequired      bool   `json:"mfa_setup_required"`
+}
+
+func TestLoginForcePasswordReset(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	path := "/api/v1/identity/users/" + strconv.FormatInt(user.ID, 10)
+
+	status, body := doJSON(t, http.MethodPut, path, map[string]any{"must_reset_password": true}, token)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("user update failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// Act
+	status, body = doJSON(t, http.MethodPost, "/api/v1/identity/login", map[string]string{
+		"email":    user.Email,
+		"password": user.Password,
+	}, "")
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("login failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var resp forcedLoginResponse
+	decodeSuccess(t, body, &resp)
+	if !resp.PasswordResetRequired || resp.ChallengeToken == "" || resp.AccessToken != "" {
+		t.Fatalf("expected a password reset challenge without tokens, got %+v", resp)
+	}
+
+	newPassword := "Secret456!"
+	status, body = doJSON(t, http.MethodPost, "/api/v1/identity/password/reset", map[string]string{
+		"challenge_token": resp.ChallengeToken,
+		"new_password":    newPassword,
+	}, "")
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("password reset failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	if loginResp := login(t, user.Email, newPassword); loginResp.AccessToken == "" {
+		t.Fatalf("expected tokens once the password is reset")
+	}
+}
+
+func TestLoginForceMFASetup(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	path := "/api/v1/identity/users/" + strconv.FormatInt(user.ID, 10)
+
+	status, body := doJSON(t, http.MethodPut, path, map[string]any{"mfa_required": true}, token)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("user update failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// Act
+	status, body = doJSON(t, http.MethodPost, "/api/v1/identity/login", map[string]string{
+		"email":    user.Email,
+		"password": user.Password,
+	}, "")
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("login failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var resp forcedLoginResponse
+	decodeSuccess(t, body, &resp)
+	if !resp.MfaSetupRequired || resp.ChallengeToken == "" || resp.AccessToken != "" {
+		t.Fatalf("expected an MFA setup challenge without tokens, got %+v", resp)
+	}
+
+	status, body = doJSON(t, http.MethodPost, "/api/v1/identity/login/mfa-setup", map[string]string{
+		"challenge_token": resp.ChallengeToken,
+		"friendly_name":   "Phone",
+	}, "")
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("login mfa setup failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var setup struct {
+		Key string `json:"key"`
+	}
+	decodeSuccess(t, body, &setup)
+	if setup.Key == "" {
+		t.Fatalf("expected a totp key")
+	}
+}
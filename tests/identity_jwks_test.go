@@ -0,0 +1,20 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestJWKSWithSymmetricSigning(t *testing.T) {
+	// Arrange
+	path := "/.well-known/jwks.json"
+
+	// Act
+	status, body := doJSON(t, http.MethodGet, path, nil, "")
+
+	// Assert
+	if status != http.StatusNotFound {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected no key set with HS512 tokens: status=%d message=%q", status, errEnv.Message)
+	}
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"net/http"
 	"testing"
 )
 
@@ -38,4 +39,31 @@ func TestLogin(t *testing.T) {
 			t.Fatalf("expected mfa_required, challenge_token, and available_methods not empty")
 		}
 	})
+
+	t.Run("LockedAfterRepeatedFailures", func(t *testing.T) {
+
+		// Arrange
+		payload := map[string]string{
+			"email":    uniqueEmail("locked"),
+			"password": "Wrong123!",
+		}
+
+		// Act
+		var status int
+		var body []byte
+		for range 6 {
+			status, body = doJSON(t, http.MethodPost, "/api/v1/identity/login", payload, "")
+			if status == http.StatusTooManyRequests {
+				break
+			}
+		}
+
+		// Assert
+		if status != http.StatusTooManyRequests {
+			t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, status)
+		}
+		if errEnv := decodeError(t, body); errEnv.Reason != "LOGIN_ACCOUNT_LOCKED" {
+			t.Fatalf("expected reason LOGIN_ACCOUNT_LOCKED, got %q", errEnv.Reason)
+		}
+	})
 }
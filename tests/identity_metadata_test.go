@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIdentityMetadata(t *testing.T) {
+	// Act
+	status, body := doJSON(t, http.MethodGet, "/.well-known/gobite-configuration", nil, "")
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("metadata failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data struct {
+		TokenEndpoint       string            `json:"token_endpoint"`
+		Endpoints           map[string]string `json:"endpoints"`
+		GrantTypesSupported []string          `json:"grant_types_supported"`
+		MFAMethodsSupported []string          `json:"mfa_methods_supported"`
+		PasswordPolicy      struct {
+			MinLength   int  `json:"min_length"`
+			MaxLength   int  `json:"max_length"`
+			HistorySize int  `json:"history_size"`
+			BreachCheck bool `json:"breach_check"`
+		} `json:"password_policy"`
+		Features map[string]bool `json:"features"`
+	}
+	decodeSuccess(t, body, &data)
+
+	if !strings.HasSuffix(data.TokenEndpoint, "/api/v1/identity/token") {
+		t.Fatalf("unexpected token endpoint %q", data.TokenEndpoint)
+	}
+	if !strings.HasSuffix(data.Endpoints["login"], "/api/v1/identity/login") {
+		t.Fatalf("unexpected login endpoint %q", data.Endpoints["login"])
+	}
+	if !slices.Contains(data.GrantTypesSupported, "client_credentials") {
+		t.Fatalf("expected client_credentials grant, got %v", data.GrantTypesSupported)
+	}
+	if !slices.Contains(data.MFAMethodsSupported, "TOTP") {
+		t.Fatalf("expected TOTP among mfa methods, got %v", data.MFAMethodsSupported)
+	}
+	if data.PasswordPolicy.MinLength <= 0 || data.PasswordPolicy.MaxLength < data.PasswordPolicy.MinLength {
+		t.Fatalf("unexpected password policy %+v", data.PasswordPolicy)
+	}
+	if data.PasswordPolicy.HistorySize < 0 || data.PasswordPolicy.BreachCheck {
+		t.Fatalf("unexpected password policy %+v", data.PasswordPolicy)
+	}
+	if !data.Features["api_keys"] {
+		t.Fatalf("expected api_keys feature, got %v", data.Features)
+	}
+}
@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMFAFactors(t *testing.T) {
+
+	// Arrange
+	adminAccessToken := adminToken(t)
+	user := createUser(t, adminAccessToken)
+	loginResp := login(t, user.Email, user.Password)
+
+	enroll := func(name string) string {
+		t.Helper()
+
+		status, body := doJSON(t, http.MethodPost, "/api/v1/identity/mfa/totp/setup", map[string]string{
+			"friendly_name":    name,
+			"current_password": user.Password,
+		}, loginResp.AccessToken)
+		if status != http.StatusOK {
+			errEnv := decodeError(t, body)
+			t.Fatalf("totp setup failed: status=%d message=%q", status, errEnv.Message)
+		}
+
+		var setup struct {
+			ChallengeToken string `json:"challenge_token"`
+			Key            string `json:"key"`
+		}
+		decodeSuccess(t, body, &setup)
+
+		status, body = doJSON(t, http.MethodPost, "/api/v1/identity/mfa/totp/confirm", map[string]string{
+			"challenge_token": setup.ChallengeToken,
+			"code":            totpCode(t, setup.Key),
+		}, loginResp.AccessToken)
+		if status != http.StatusNoContent {
+			errEnv := decodeError(t, body)
+			t.Fatalf("totp confirm failed: status=%d message=%q", status, errEnv.Message)
+		}
+
+		return setup.Key
+	}
+
+	phoneKey := enroll("Phone")
+	tabletKey := enroll("Tablet")
+
+	// Act & Assert: a name can be used once
+	status, _ := doJSON(t, http.MethodPost, "/api/v1/identity/mfa/totp/setup", map[string]string{
+		"friendly_name":    "phone",
+		"current_password": user.Password,
+	}, loginResp.AccessToken)
+	if status != http.StatusConflict {
+		t.Fatalf("expected 409 for a duplicate factor name, got %d", status)
+	}
+
+	status, body := doJSON(t, http.MethodGet, "/api/v1/identity/mfa/factors", nil, loginResp.AccessToken)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("list mfa factors failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var list struct {
+		Factors []struct {
+			ID           string `json:"id"`
+			Type         string `json:"type"`
+			FriendlyName string `json:"friendly_name"`
+		} `json:"factors"`
+	}
+	decodeSuccess(t, body, &list)
+
+	ids := map[string]string{}
+	for _, f := range list.Factors {
+		if f.Type == "TOTP" {
+			ids[f.FriendlyName] = f.ID
+		}
+	}
+	if ids["Phone"] == "" || ids["Tablet"] == "" {
+		t.Fatalf("expected two TOTP factors, got %+v", list.Factors)
+	}
+
+	status, body = doJSON(t, http.MethodPatch, "/api/v1/identity/mfa/factors/"+ids["Tablet"], map[string]string{
+		"friendly_name": "Old tablet",
+	}, loginResp.AccessToken)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("rename mfa factor failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// Act & Assert: the second app signs in too
+	mfaLogin := login(t, user.Email, user.Password)
+	if !mfaLogin.MfaRequired {
+		t.Fatal("expected MFA challenge on login")
+	}
+
+	status, body = doJSON(t, http.MethodPost, "/api/v1/identity/login/2fa", map[string]any{
+		"challenge_token": mfaLogin.ChallengeToken,
+		"method":          "TOTP",
+		"code":            totpCode(t, tabletKey),
+	}, "")
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("login 2fa with the second factor failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// Act & Assert: removing a factor while another remains needs a code
+	path := "/api/v1/identity/mfa/factors/" + ids["Tablet"]
+	status, _ = doJSON(t, http.MethodDelete, path, map[string]string{
+		"current_password": user.Password,
+	}, loginResp.AccessToken)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected 401 deleting a factor without a code, got %d", status)
+	}
+
+	status, body = doJSON(t, http.MethodDelete, path, map[string]string{
+		"current_password": user.Password,
+		"method":           "TOTP",
+		"code":             totpCode(t, phoneKey),
+	}, loginResp.AccessToken)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("delete mfa factor failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	status, _ = doJSON(t, http.MethodDelete, path, map[string]string{
+		"current_password": user.Password,
+	}, loginResp.AccessToken)
+	if status != http.StatusNotFound {
+		t.Fatalf("expected 404 deleting the factor twice, got %d", status)
+	}
+
+	// Act & Assert: the last factor goes with the password alone and turns MFA off
+	status, body = doJSON(t, http.MethodDelete, "/api/v1/identity/mfa/factors/"+ids["Phone"], map[string]string{
+		"current_password": user.Password,
+	}, loginResp.AccessToken)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("delete last mfa factor failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	if plain := login(t, user.Email, user.Password); plain.MfaRequired || plain.AccessToken == "" {
+		t.Fatal("expected login without MFA after the last factor was removed")
+	}
+}
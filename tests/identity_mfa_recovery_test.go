@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMFARecovery(t *testing.T) {
+
+	t.Run("RequestUnknownEmail", func(t *testing.T) {
+
+		// Arrange
+		payload := map[string]string{"email": uniqueEmail("mfa-recovery")}
+
+		// Act
+		status, body := doJSON(t, http.MethodPost, "/api/v1/identity/mfa/recovery", payload, "")
+
+		// Assert
+		if status != http.StatusOK {
+			errEnv := decodeError(t, body)
+			t.Fatalf("expected status %d, got %d message=%q", http.StatusOK, status, errEnv.Message)
+		}
+	})
+
+	t.Run("VerifyInvalidToken", func(t *testing.T) {
+
+		// Arrange
+		payload := map[string]string{
+			"challenge_token": "invalid-token",
+			"password":        userPassword,
+		}
+
+		// Act
+		status, _ := doJSON(t, http.MethodPost, "/api/v1/identity/mfa/recovery/verify", payload, "")
+
+		// Assert
+		if status != http.StatusUnauthorized {
+			t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, status)
+		}
+	})
+
+	t.Run("CompleteInvalidToken", func(t *testing.T) {
+
+		// Arrange
+		payload := map[string]string{"recovery_token": "invalid-token"}
+
+		// Act
+		status, _ := doJSON(t, http.MethodPost, "/api/v1/identity/mfa/recovery/complete", payload, "")
+
+		// Assert
+		if status != http.StatusUnauthorized {
+			t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, status)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestOAuthAuthorize(t *testing.T) {
+	t.Run("UnsupportedProvider", func(t *testing.T) {
+		// Act
+		status, body := doJSON(t, http.MethodGet, "/api/v1/identity/oauth/unknown/authorize", nil, "")
+
+		// Assert
+		if status != http.StatusNotFound {
+			errEnv := decodeError(t, body)
+			t.Fatalf("expected status %d, got %d message=%q", http.StatusNotFound, status, errEnv.Message)
+		}
+	})
+}
+
+func TestOAuthCallback(t *testing.T) {
+	t.Run("WithoutFlowCookie", func(t *testing.T) {
+		// Act
+		status, body := doJSON(t, http.MethodPost, "/api/v1/identity/oauth/google/callback", map[string]any{"code": "code", "state": "forged.state"}, "")
+
+		// Assert
+		if status != http.StatusUnauthorized {
+			errEnv := decodeError(t, body)
+			t.Fatalf("expected status %d, got %d message=%q", http.StatusUnauthorized, status, errEnv.Message)
+		}
+	})
+
+	t.Run("MissingCode", func(t *testing.T) {
+		// Act
+		status, _ := doJSON(t, http.MethodPost, "/api/v1/identity/oauth/google/callback", map[string]any{"state": "forged.state"}, "")
+
+		// Assert
+		if status != http.StatusUnprocessableEntity {
+			t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, status)
+		}
+	})
+
+	t.Run("GetNotAllowed", func(t *testing.T) {
+		// Act
+		status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/oauth/google/callback?code=code&state=forged.state", nil, "")
+
+		// Assert
+		if status == http.StatusOK {
+			t.Fatalf("expected the callback to refuse GET, got status %d", status)
+		}
+	})
+}
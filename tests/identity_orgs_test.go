@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type orgData struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	Role string `json:"role"`
+}
+
+type orgMembersData struct {
+	Members []struct {
+		UserID int64  `json:"user_id"`
+		Email  string `json:"email"`
+		Role   string `json:"role"`
+	} `json:"members"`
+}
+
+func createOrg(t *testing.T, token, name string) orgData {
+	t.Helper()
+
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/orgs", map[string]string{
+		"name": name,
+		"slug": "org-" + strconv.FormatInt(time.Now().UnixNano(), 10),
+	}, token)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("create org failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data orgData
+	decodeSuccess(t, body, &data)
+
+	return data
+}
+
+func TestOrgMembersAndRoles(t *testing.T) {
+	// Arrange
+	admin := adminToken(t)
+	member := createUser(t, admin)
+	memberToken := login(t, member.Email, member.Password).AccessToken
+
+	org := createOrg(t, admin, "Acme")
+	if org.Role != "owner" {
+		t.Fatalf("expected creator to be owner, got %q", org.Role)
+	}
+	orgPath := "/api/v1/identity/orgs/" + strconv.FormatInt(org.ID, 10)
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, orgPath+"/members", map[string]string{
+		"email": member.Email,
+		"role":  "member",
+	}, admin)
+
+	// Assert
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("invite member failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	unknownStatus, _ := doJSON(t, http.MethodPost, orgPath+"/members", map[string]string{
+		"email": uniqueEmail("nobody"),
+		"role":  "member",
+	}, admin)
+	if unknownStatus != status {
+		t.Fatalf("expected the same answer for an unknown email, got status=%d", unknownStatus)
+	}
+
+	if status, _ := doJSON(t, http.MethodGet, orgPath, nil, memberToken); status != http.StatusForbidden {
+		t.Fatalf("expected an invited user to stay outside until accepting, got status=%d", status)
+	}
+
+	status, body = doJSON(t, http.MethodGet, orgPath+"/members", nil, admin)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("list members failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var members orgMembersData
+	decodeSuccess(t, body, &members)
+	if len(members.Members) != 1 {
+		t.Fatalf("expected only the owner to be a member, got %+v", members.Members)
+	}
+
+	if status, _ := doJSON(t, http.MethodPost, "/api/v1/identity/org-invitations/accept", map[string]string{"challenge_token": "forged"}, memberToken); status != http.StatusNotFound {
+		t.Fatalf("expected a forged invitation to be refused, got status=%d", status)
+	}
+
+	ownerPath := orgPath + "/members/" + strconv.FormatInt(members.Members[0].UserID, 10)
+	if status, _ := doJSON(t, http.MethodPut, ownerPath, map[string]string{"role": "admin"}, admin); status != http.StatusConflict {
+		t.Fatalf("expected the last owner to stay, got status=%d", status)
+	}
+
+	if status, _ := doJSON(t, http.MethodDelete, orgPath, nil, admin); status != http.StatusNoContent {
+		t.Fatalf("expected owner to delete the org, got status=%d", status)
+	}
+}
+
+func TestOrgCreateNeedsPermission(t *testing.T) {
+	// Arrange
+	admin := adminToken(t)
+	user := createUser(t, admin)
+	userToken := login(t, user.Email, user.Password).AccessToken
+
+	// Act
+	status, _ := doJSON(t, http.MethodPost, "/api/v1/identity/orgs", map[string]string{
+		"name": "Mine",
+		"slug": "org-" + strconv.FormatInt(time.Now().UnixNano(), 10),
+	}, userToken)
+
+	// Assert
+	if status != http.StatusForbidden {
+		t.Fatalf("expected a user without the permission to be refused, got status=%d", status)
+	}
+}
+
+func TestOrgTokenIsScopedToOneOrg(t *testing.T) {
+	// Arrange
+	admin := adminToken(t)
+	user := createUser(t, admin)
+	userToken := login(t, user.Email, user.Password).AccessToken
+
+	first := createOrg(t, admin, "First")
+	second := createOrg(t, admin, "Second")
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/orgs/"+strconv.FormatInt(first.ID, 10)+"/token", nil, admin)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("switch org failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var data struct {
+		AccessToken string `json:"access_token"`
+	}
+	decodeSuccess(t, body, &data)
+
+	if status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/orgs/"+strconv.FormatInt(first.ID, 10), nil, data.AccessToken); status != http.StatusOK {
+		t.Fatalf("expected scoped token to read its org, got status=%d", status)
+	}
+
+	if status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/orgs/"+strconv.FormatInt(second.ID, 10), nil, data.AccessToken); status != http.StatusForbidden {
+		t.Fatalf("expected scoped token to be forbidden in another org, got status=%d", status)
+	}
+
+	if status, _ := doJSON(t, http.MethodPost, "/api/v1/identity/orgs/"+strconv.FormatInt(first.ID, 10)+"/token", nil, userToken); status != http.StatusForbidden {
+		t.Fatalf("expected non-member to be refused an org token, got status=%d", status)
+	}
+}
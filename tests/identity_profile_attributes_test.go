@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestProfileAttributes(t *testing.T) {
+	// Arrange
+	loginResp := login(t, adminEmail, adminPassword)
+	path := "/api/v1/identity/profile/attributes"
+
+	status, body := doJSON(t, http.MethodPatch, path, map[string]any{
+		"attributes": map[string]any{"ui.theme": "dark", "ui.sidebar": true},
+	}, loginResp.AccessToken)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("attributes update failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// Act
+	status, body = doJSON(t, http.MethodPatch, path, map[string]any{
+		"attributes": map[string]any{"ui.sidebar": nil},
+	}, loginResp.AccessToken)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("attributes removal failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	status, body = doJSON(t, http.MethodGet, path, nil, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("attributes read failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data struct {
+		Attributes map[string]any `json:"attributes"`
+	}
+	decodeSuccess(t, body, &data)
+	if data.Attributes["ui.theme"] != "dark" {
+		t.Fatalf("expected ui.theme dark, got %v", data.Attributes["ui.theme"])
+	}
+	if _, ok := data.Attributes["ui.sidebar"]; ok {
+		t.Fatalf("expected ui.sidebar to be removed")
+	}
+}
+
+func TestProfileAttributesInvalidKey(t *testing.T) {
+	// Arrange
+	loginResp := login(t, adminEmail, adminPassword)
+	payload := map[string]any{"attributes": map[string]any{"bad key!": 1}}
+
+	// Act
+	status, body := doJSON(t, http.MethodPatch, "/api/v1/identity/profile/attributes", payload, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, status)
+	}
+	if errEnv := decodeError(t, body); errEnv.Error["attributes.bad key!"] == "" {
+		t.Fatalf("expected error on attributes.bad key!, got %v", errEnv.Error)
+	}
+}
@@ -22,7 +22,7 @@ func TestProfileUpdateAvatar(t *testing.T) {
 	}
 
 	// Act
-	status, body := doMultipart(t, "/api/v1/identity/profile/avatar", "avatar", "avatar.png", avatar, loginResp.AccessToken)
+	status, body := doMultipart(t, http.MethodPut, "/api/v1/identity/profile/avatar", "avatar", "avatar.png", avatar, loginResp.AccessToken)
 
 	// Assert
 	if status != http.StatusNoContent {
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestProfileDelete(t *testing.T) {
+	// Arrange
+	user := createUser(t, adminToken(t))
+	loginResp := login(t, user.Email, user.Password)
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/profile/delete", map[string]string{
+		"current_password": user.Password,
+	}, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("profile delete failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data struct {
+		ScheduledAt time.Time `json:"scheduled_at"`
+	}
+	decodeSuccess(t, body, &data)
+	if !data.ScheduledAt.After(time.Now()) {
+		t.Fatalf("expected deletion scheduled in the future, got %s", data.ScheduledAt)
+	}
+}
+
+func TestProfileDeleteWrongPassword(t *testing.T) {
+	// Arrange
+	user := createUser(t, adminToken(t))
+	loginResp := login(t, user.Email, user.Password)
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/profile/delete", map[string]string{
+		"current_password": "Wrong123!",
+	}, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusUnauthorized {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected 401 for wrong password, got status=%d message=%q", status, errEnv.Message)
+	}
+}
+
+func TestProfileDeleteCancel(t *testing.T) {
+	// Arrange
+	user := createUser(t, adminToken(t))
+	loginResp := login(t, user.Email, user.Password)
+
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/profile/delete", map[string]string{
+		"current_password": user.Password,
+	}, loginResp.AccessToken)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("profile delete failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// Act
+	status, body = doJSON(t, http.MethodPost, "/api/v1/identity/profile/delete/cancel", nil, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("profile delete cancel failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	status, _ = doJSON(t, http.MethodPost, "/api/v1/identity/profile/delete/cancel", nil, loginResp.AccessToken)
+	if status != http.StatusNotFound {
+		t.Fatalf("expected 404 once nothing is pending, got status=%d", status)
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestProfileIdentities(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	t.Run("List", func(t *testing.T) {
+		// Act
+		status, body := doJSON(t, http.MethodGet, "/api/v1/identity/profile/identities", nil, token)
+
+		// Assert
+		if status != http.StatusOK {
+			errEnv := decodeError(t, body)
+			t.Fatalf("expected status %d, got %d message=%q", http.StatusOK, status, errEnv.Message)
+		}
+
+		var data struct {
+			Identities []map[string]any `json:"identities"`
+		}
+		decodeSuccess(t, body, &data)
+		if data.Identities == nil {
+			t.Fatalf("expected identities array, got %s", body)
+		}
+	})
+
+	t.Run("LinkUnsupportedProvider", func(t *testing.T) {
+		// Act
+		status, body := doJSON(t, http.MethodPost, "/api/v1/identity/profile/identities/unknown/link", nil, token)
+
+		// Assert
+		if status != http.StatusNotFound {
+			errEnv := decodeError(t, body)
+			t.Fatalf("expected status %d, got %d message=%q", http.StatusNotFound, status, errEnv.Message)
+		}
+	})
+
+	t.Run("UnlinkNotFound", func(t *testing.T) {
+		// Act
+		status, body := doJSON(t, http.MethodDelete, "/api/v1/identity/profile/identities/1", nil, token)
+
+		// Assert
+		if status != http.StatusNotFound {
+			errEnv := decodeError(t, body)
+			t.Fatalf("expected status %d, got %d message=%q", http.StatusNotFound, status, errEnv.Message)
+		}
+	})
+
+	t.Run("CallbackForgedLinkState", func(t *testing.T) {
+		// Act
+		status, body := doJSON(t, http.MethodPost, "/api/v1/identity/profile/identities/google/callback", map[string]any{"code": "code", "state": "link.forged.state"}, token)
+
+		// Assert
+		if status != http.StatusUnauthorized {
+			errEnv := decodeError(t, body)
+			t.Fatalf("expected status %d, got %d message=%q", http.StatusUnauthorized, status, errEnv.Message)
+		}
+	})
+
+	t.Run("CallbackUnauthenticated", func(t *testing.T) {
+		// Act
+		status, body := doJSON(t, http.MethodPost, "/api/v1/identity/profile/identities/google/callback", map[string]any{"code": "code", "state": "link.forged.state"}, "")
+
+		// Assert
+		if status != http.StatusUnauthorized {
+			errEnv := decodeError(t, body)
+			t.Fatalf("expected status %d, got %d message=%q", http.StatusUnauthorized, status, errEnv.Message)
+		}
+	})
+
+	t.Run("SignInCallbackRefusesLinkState", func(t *testing.T) {
+		// Act
+		status, body := doJSON(t, http.MethodPost, "/api/v1/identity/oauth/google/callback", map[string]any{"code": "code", "state": "link.forged.state"}, "")
+
+		// Assert
+		if status != http.StatusUnauthorized {
+			errEnv := decodeError(t, body)
+			t.Fatalf("expected status %d, got %d message=%q", http.StatusUnauthorized, status, errEnv.Message)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+type profileLoginsData struct {
+	Logins []struct {
+		ID            string `json:"id"`
+		Success       bool   `json:"success"`
+		Method        string `json:"method"`
+		FailureReason string `json:"failure_reason"`
+		IP            string `json:"ip"`
+	} `json:"logins"`
+	NextBeforeID string `json:"next_before_id"`
+}
+
+func TestProfileLogins(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+
+	status, _ := doJSON(t, http.MethodPost, "/api/v1/identity/login", map[string]string{
+		"email":    user.Email,
+		"password": "wrong-password",
+	}, "")
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected wrong password to be unauthorized, got status=%d", status)
+	}
+	loginResp := login(t, user.Email, user.Password)
+
+	// Act
+	status, body := doJSON(t, http.MethodGet, "/api/v1/identity/profile/logins", nil, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("profile logins failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data profileLoginsData
+	decodeSuccess(t, body, &data)
+	if len(data.Logins) != 2 {
+		t.Fatalf("expected 2 login entries, got %d", len(data.Logins))
+	}
+
+	// Entries are newest first.
+	if !data.Logins[0].Success || data.Logins[0].Method != "password" || data.Logins[0].IP == "" {
+		t.Fatalf("unexpected successful login entry: %+v", data.Logins[0])
+	}
+	if data.Logins[1].Success || data.Logins[1].FailureReason != "invalid_password" {
+		t.Fatalf("unexpected failed login entry: %+v", data.Logins[1])
+	}
+
+	status, body = doJSON(t, http.MethodGet, "/api/v1/identity/profile/logins?limit=1", nil, loginResp.AccessToken)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("profile logins page failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var page profileLoginsData
+	decodeSuccess(t, body, &page)
+	if len(page.Logins) != 1 || page.NextBeforeID != page.Logins[0].ID {
+		t.Fatalf("expected one entry with a next page cursor, got %+v", page)
+	}
+}
+
+func TestProfileLoginsUnauthorized(t *testing.T) {
+	// Act
+	status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/profile/logins", nil, "")
+
+	// Assert
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized, got status=%d", status)
+	}
+}
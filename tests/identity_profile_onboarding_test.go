@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+type profileOnboardingData struct {
+	Steps []struct {
+		Key       string `json:"key"`
+		Completed bool   `json:"completed"`
+	} `json:"steps"`
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+	Percent   int `json:"percent"`
+}
+
+func TestProfileOnboarding(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	loginResp := login(t, user.Email, user.Password)
+
+	// Act
+	status, body := doJSON(t, http.MethodGet, "/api/v1/identity/profile/onboarding", nil, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("profile onboarding failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data profileOnboardingData
+	decodeSuccess(t, body, &data)
+	if data.Total != 4 || len(data.Steps) != 4 {
+		t.Fatalf("expected 4 onboarding steps, got total=%d steps=%d", data.Total, len(data.Steps))
+	}
+
+	completed := map[string]bool{}
+	for _, step := range data.Steps {
+		completed[step.Key] = step.Completed
+	}
+	if !completed["email_verified"] {
+		t.Fatalf("expected email_verified to be completed for an active user")
+	}
+	if completed["mfa_enabled"] || completed["avatar_set"] {
+		t.Fatalf("expected mfa_enabled and avatar_set to be pending for a new user, got %+v", completed)
+	}
+}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+type profileSecurityScoreData struct {
+	Score  int `json:"score"`
+	Checks []struct {
+		Key            string `json:"key"`
+		Passed         bool   `json:"passed"`
+		Weight         int    `json:"weight"`
+		Recommendation string `json:"recommendation"`
+	} `json:"checks"`
+}
+
+func TestProfileSecurityScore(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	loginResp := login(t, user.Email, user.Password)
+
+	// Act
+	status, body := doJSON(t, http.MethodGet, "/api/v1/identity/profile/security/score", nil, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("profile security score failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data profileSecurityScoreData
+	decodeSuccess(t, body, &data)
+	if len(data.Checks) != 4 {
+		t.Fatalf("expected 4 security checks, got %d", len(data.Checks))
+	}
+
+	passed := map[string]bool{}
+	total := 0
+	for _, check := range data.Checks {
+		passed[check.Key] = check.Passed
+		total += check.Weight
+		if !check.Passed && check.Recommendation == "" {
+			t.Fatalf("expected a recommendation for failed check %q", check.Key)
+		}
+	}
+	if total != 100 {
+		t.Fatalf("expected check weights to add up to 100, got %d", total)
+	}
+	if passed["mfa_enabled"] || passed["recovery_codes"] {
+		t.Fatalf("expected mfa_enabled and recovery_codes to fail for a new user, got %+v", passed)
+	}
+	if !passed["recent_password"] || !passed["no_suspicious_sessions"] {
+		t.Fatalf("expected recent_password and no_suspicious_sessions to pass for a new user, got %+v", passed)
+	}
+	if data.Score != 40 {
+		t.Fatalf("expected score 40 for a new user, got %d", data.Score)
+	}
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestReauth(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	loginResp := login(t, user.Email, user.Password)
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/reauth", map[string]string{
+		"password": user.Password,
+	}, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("reauth failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data struct {
+		AccessToken string `json:"access_token"`
+	}
+	decodeSuccess(t, body, &data)
+	if data.AccessToken == "" {
+		t.Fatal("expected access token in reauth response")
+	}
+
+	// the fresh token is accepted by a sensitive operation
+	status, body = doJSON(t, http.MethodPost, "/api/v1/identity/email/change", map[string]string{
+		"new_email":        uniqueEmail("reauth"),
+		"current_password": user.Password,
+	}, data.AccessToken)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("email change with reauth token failed: status=%d message=%q", status, errEnv.Message)
+	}
+}
+
+func TestReauthRejections(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	loginResp := login(t, user.Email, user.Password)
+
+	cases := []struct {
+		name    string
+		payload map[string]string
+		token   string
+		status  int
+	}{
+		{
+			name:    "Unauthenticated",
+			payload: map[string]string{"password": user.Password},
+			token:   "",
+			status:  http.StatusUnauthorized,
+		},
+		{
+			name:    "WrongPassword",
+			payload: map[string]string{"password": "Wrong123!"},
+			token:   loginResp.AccessToken,
+			status:  http.StatusUnauthorized,
+		},
+		{
+			name:    "MissingPassword",
+			payload: map[string]string{},
+			token:   loginResp.AccessToken,
+			status:  http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			status, _ := doJSON(t, http.MethodPost, "/api/v1/identity/reauth", tc.payload, tc.token)
+
+			// Assert
+			if status != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, status)
+			}
+		})
+	}
+}
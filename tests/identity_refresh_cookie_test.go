@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func doJSONWithCookies(t *testing.T, path string, payload any, header http.Header, cookies []*http.Cookie) (*http.Response, []byte) {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(payload); err != nil {
+		t.Fatalf("encode json: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(baseURL(), "/")+path, buf)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+
+	return resp, body
+}
+
+func TestRefreshCookieModeDisabledByDefault(t *testing.T) {
+	// Arrange
+	header := http.Header{"X-Client-Type": []string{"web"}}
+
+	// Act
+	resp, body := doJSONWithCookies(t, "/api/v1/identity/login",
+		map[string]string{"email": adminEmail, "password": adminPassword}, header, nil)
+
+	// Assert
+	if resp.StatusCode != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("login failed: status=%d message=%q", resp.StatusCode, errEnv.Message)
+	}
+
+	var data struct {
+		RefreshToken string `json:"refresh_token"`
+		CSRFToken    string `json:"csrf_token"`
+	}
+	decodeSuccess(t, body, &data)
+	if data.RefreshToken == "" || data.CSRFToken != "" {
+		t.Fatalf("expected the refresh token in the body without cookie mode")
+	}
+	if len(resp.Cookies()) != 0 {
+		t.Fatalf("expected no cookies without cookie mode, got %v", resp.Cookies())
+	}
+
+	// a refresh that only carries cookies has no refresh token to use
+	cookies := []*http.Cookie{
+		{Name: "gobite_refresh", Value: data.RefreshToken},
+		{Name: "gobite_csrf", Value: "csrf"},
+	}
+	header.Set("X-CSRF-Token", "csrf")
+	resp, _ = doJSONWithCookies(t, "/api/v1/identity/refresh", map[string]string{}, header, cookies)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a cookie refresh without cookie mode, got %d", resp.StatusCode)
+	}
+}
@@ -23,3 +23,45 @@ func TestRegister(t *testing.T) {
 		t.Fatalf("register failed: status=%d message=%q", status, errEnv.Message)
 	}
 }
+
+func TestRegisterPasswordPolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		email    string
+		password string
+	}{
+		{
+			name:     "MissingUppercase",
+			email:    uniqueEmail("real-register"),
+			password: "secret123!",
+		},
+		{
+			name:     "ContainsEmail",
+			email:    uniqueEmail("policy-tester"),
+			password: "Tester123!",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			payload := map[string]string{
+				"email":     tc.email,
+				"password":  tc.password,
+				"full_name": "Test User",
+			}
+
+			// Act
+			status, body := doJSON(t, http.MethodPost, "/api/v1/identity/register", payload, "")
+
+			// Assert
+			errEnv := decodeError(t, body)
+			if status != http.StatusUnprocessableEntity {
+				t.Fatalf("expected status 422, got %d message=%q", status, errEnv.Message)
+			}
+			if errEnv.Error["password"] == "" {
+				t.Fatalf("expected a password field error, got %+v", errEnv.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type roleDetailData struct {
+	Name                 string              `json:"name"`
+	Permissions          map[string][]string `json:"permissions"`
+	EffectivePermissions map[string][]string `json:"effective_permissions"`
+	MemberIDs            []string            `json:"member_ids"`
+}
+
+func createRole(t *testing.T, token string) string {
+	t.Helper()
+
+	name := fmt.Sprintf("role-%d", time.Now().UnixNano())
+	payload := map[string]any{
+		"name": name,
+		"permissions": []map[string]string{
+			{"object": "identity:management:users", "action": "read"},
+		},
+	}
+
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/roles", payload, token)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("create role failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	return name
+}
+
+func TestRolesLifecycle(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	role := createRole(t, token)
+	user := createUser(t, token)
+	path := "/api/v1/identity/roles/" + role
+
+	// Act
+	addPermStatus, _ := doJSON(t, http.MethodPost, path+"/permissions",
+		map[string]string{"object": "identity:management:roles", "action": "read"}, token)
+	addMemberStatus, _ := doJSON(t, http.MethodPost, path+"/users",
+		map[string]string{"user_id": strconv.FormatInt(user.ID, 10)}, token)
+	detailStatus, detailBody := doJSON(t, http.MethodGet, path, nil, token)
+
+	// Assert
+	if addPermStatus != http.StatusNoContent {
+		t.Fatalf("add permission: expected status %d, got %d", http.StatusNoContent, addPermStatus)
+	}
+	if addMemberStatus != http.StatusNoContent {
+		t.Fatalf("add member: expected status %d, got %d", http.StatusNoContent, addMemberStatus)
+	}
+	if detailStatus != http.StatusOK {
+		errEnv := decodeError(t, detailBody)
+		t.Fatalf("role detail failed: status=%d message=%q", detailStatus, errEnv.Message)
+	}
+
+	var data roleDetailData
+	decodeSuccess(t, detailBody, &data)
+	if !slices.Contains(data.Permissions["identity:management:roles"], "read") {
+		t.Fatalf("expected added permission, got %v", data.Permissions)
+	}
+	if !slices.Contains(data.MemberIDs, strconv.FormatInt(user.ID, 10)) {
+		t.Fatalf("expected member %d, got %v", user.ID, data.MemberIDs)
+	}
+
+	// the member now holds the role's permissions
+	memberToken := login(t, user.Email, user.Password).AccessToken
+	status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/roles", nil, memberToken)
+	if status != http.StatusOK {
+		t.Fatalf("member list roles: expected status %d, got %d", http.StatusOK, status)
+	}
+
+	status, _ = doJSON(t, http.MethodDelete, path, nil, token)
+	if status != http.StatusNoContent {
+		t.Fatalf("delete role: expected status %d, got %d", http.StatusNoContent, status)
+	}
+
+	status, _ = doJSON(t, http.MethodGet, path, nil, token)
+	if status != http.StatusNotFound {
+		t.Fatalf("deleted role: expected status %d, got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestRolesRejections(t *testing.T) {
+	token := adminToken(t)
+	role := createRole(t, token)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		want   int
+	}{
+		{
+			name:   "DuplicateRole",
+			method: http.MethodPost,
+			path:   "/api/v1/identity/roles",
+			body: map[string]any{
+				"name":        role,
+				"permissions": []map[string]string{{"object": "x", "action": "read"}},
+			},
+			want: http.StatusConflict,
+		},
+		{
+			name:   "InvalidRoleName",
+			method: http.MethodPost,
+			path:   "/api/v1/identity/roles",
+			body: map[string]any{
+				"name":        "123",
+				"permissions": []map[string]string{{"object": "x", "action": "read"}},
+			},
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "EmptyPermissions",
+			method: http.MethodPost,
+			path:   "/api/v1/identity/roles",
+			body:   map[string]any{"name": "empty-role", "permissions": []any{}},
+			want:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "DeleteProtectedRole",
+			method: http.MethodDelete,
+			path:   "/api/v1/identity/roles/admin",
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "UnknownRole",
+			method: http.MethodGet,
+			path:   "/api/v1/identity/roles/does-not-exist",
+			want:   http.StatusNotFound,
+		},
+		{
+			name:   "RemoveSelfFromProtectedRole",
+			method: http.MethodDelete,
+			path:   "/api/v1/identity/roles/admin/users/1",
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "RemoveMissingPermission",
+			method: http.MethodDelete,
+			path:   "/api/v1/identity/roles/" + role + "/permissions?object=missing&action=read",
+			want:   http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			status, _ := doJSON(t, tc.method, tc.path, tc.body, token)
+
+			// Assert
+			if status != tc.want {
+				t.Fatalf("expected status %d, got %d", tc.want, status)
+			}
+		})
+	}
+}
+
+func TestRolesForbiddenForRegularUser(t *testing.T) {
+	// Arrange
+	admin := adminToken(t)
+	user := createUser(t, admin)
+	token := login(t, user.Email, user.Password).AccessToken
+
+	// Act
+	status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/roles", nil, token)
+
+	// Assert
+	if status != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, status)
+	}
+}
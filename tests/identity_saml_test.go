@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func doForm(t *testing.T, path string, form url.Values) (int, []byte) {
+	t.Helper()
+
+	resp, err := httpClient.PostForm(strings.TrimRight(baseURL(), "/")+path, form)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+
+	return resp.StatusCode, body
+}
+
+func TestSAMLACS(t *testing.T) {
+	t.Run("WithoutFlowCookie", func(t *testing.T) {
+		// Act
+		status, body := doForm(t, "/api/v1/identity/saml/acs", url.Values{
+			"SAMLResponse": {"PHNhbWxwOlJlc3BvbnNlLz4="},
+			"RelayState":   {"forged.state"},
+		})
+
+		// Assert
+		if status != http.StatusUnauthorized {
+			errEnv := decodeError(t, body)
+			t.Fatalf("expected status %d, got %d message=%q", http.StatusUnauthorized, status, errEnv.Message)
+		}
+	})
+
+	t.Run("MissingResponse", func(t *testing.T) {
+		// Act
+		status, _ := doForm(t, "/api/v1/identity/saml/acs", url.Values{"RelayState": {"forged.state"}})
+
+		// Assert
+		if status != http.StatusUnprocessableEntity {
+			t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, status)
+		}
+	})
+}
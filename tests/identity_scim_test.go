@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type scimUserData struct {
+	ID       string `json:"id"`
+	UserName string `json:"userName"`
+	Active   bool   `json:"active"`
+}
+
+type scimGroupData struct {
+	ID      string `json:"id"`
+	Members []struct {
+		Value string `json:"value"`
+	} `json:"members"`
+}
+
+func scimKey(t *testing.T) string {
+	t.Helper()
+
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/api-keys", map[string]any{
+		"name":            "scim",
+		"scopes":          map[string][]string{"identity:scim": {"read", "create", "update", "delete"}},
+		"expires_in_days": 1,
+	}, adminToken(t))
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("create scim api key failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var created apiKeyData
+	decodeSuccess(t, body, &created)
+
+	return created.Key
+}
+
+func decodeSCIM(t *testing.T, body []byte, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(body, v); err != nil {
+		t.Fatalf("decode scim response: %v: %s", err, body)
+	}
+}
+
+func scimCreateUser(t *testing.T, key string) scimUserData {
+	t.Helper()
+
+	status, body := doJSON(t, http.MethodPost, "/scim/v2/Users", map[string]any{
+		"schemas":  []string{"urn:ietf:params:scim:schemas:core:2.0:User"},
+		"userName": "scim-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "@example.com",
+		"name":     map[string]string{"givenName": "Scim", "familyName": "User"},
+		"active":   true,
+	}, key)
+	if status != http.StatusCreated {
+		t.Fatalf("scim create user failed: status=%d body=%s", status, body)
+	}
+
+	var created scimUserData
+	decodeSCIM(t, body, &created)
+
+	return created
+}
+
+func TestSCIMUserLifecycle(t *testing.T) {
+	// Arrange
+	key := scimKey(t)
+	userName := "scim-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "@example.com"
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/scim/v2/Users", map[string]any{
+		"schemas":  []string{"urn:ietf:params:scim:schemas:core:2.0:User"},
+		"userName": userName,
+		"name":     map[string]string{"givenName": "Scim", "familyName": "User"},
+		"active":   true,
+	}, key)
+
+	// Assert
+	if status != http.StatusCreated {
+		t.Fatalf("scim create user failed: status=%d body=%s", status, body)
+	}
+	var created scimUserData
+	decodeSCIM(t, body, &created)
+	if created.UserName != userName || !created.Active {
+		t.Fatalf("unexpected created user %+v", created)
+	}
+
+	if status, _ := doJSON(t, http.MethodPost, "/scim/v2/Users", map[string]any{"userName": userName, "displayName": "Again"}, key); status != http.StatusConflict {
+		t.Fatalf("expected a duplicate userName to conflict, got status=%d", status)
+	}
+
+	status, body = doJSON(t, http.MethodGet, "/scim/v2/Users?filter="+url.QueryEscape(`userName eq "`+userName+`"`), nil, key)
+	if status != http.StatusOK {
+		t.Fatalf("scim filter users failed: status=%d body=%s", status, body)
+	}
+	var list struct {
+		TotalResults int64          `json:"totalResults"`
+		Resources    []scimUserData `json:"Resources"`
+	}
+	decodeSCIM(t, body, &list)
+	if list.TotalResults != 1 || len(list.Resources) != 1 || list.Resources[0].ID != created.ID {
+		t.Fatalf("expected the filter to find the created user, got %+v", list)
+	}
+
+	userPath := "/scim/v2/Users/" + created.ID
+	status, body = doJSON(t, http.MethodPatch, userPath, map[string]any{
+		"schemas":    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+		"Operations": []map[string]any{{"op": "Replace", "path": "active", "value": "False"}},
+	}, key)
+	if status != http.StatusOK {
+		t.Fatalf("scim deactivate user failed: status=%d body=%s", status, body)
+	}
+	var patched scimUserData
+	decodeSCIM(t, body, &patched)
+	if patched.Active {
+		t.Fatalf("expected user to be deactivated, got %+v", patched)
+	}
+
+	if status, _ := doJSON(t, http.MethodDelete, userPath, nil, key); status != http.StatusNoContent {
+		t.Fatalf("expected scim delete to succeed, got status=%d", status)
+	}
+
+	if status, _ := doJSON(t, http.MethodGet, userPath, nil, key); status != http.StatusNotFound {
+		t.Fatalf("expected a deleted user to be gone, got status=%d", status)
+	}
+}
+
+func TestSCIMUserRenameNeedsConfirmation(t *testing.T) {
+	// Arrange
+	key := scimKey(t)
+	user := scimCreateUser(t, key)
+	newUserName := "scim-renamed-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "@example.com"
+
+	// Act
+	status, body := doJSON(t, http.MethodPatch, "/scim/v2/Users/"+user.ID, map[string]any{
+		"schemas":    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+		"Operations": []map[string]any{{"op": "Replace", "path": "userName", "value": newUserName}},
+	}, key)
+
+	// Assert
+	if status != http.StatusOK {
+		t.Fatalf("scim rename user failed: status=%d body=%s", status, body)
+	}
+	var patched scimUserData
+	decodeSCIM(t, body, &patched)
+	if patched.UserName != user.UserName {
+		t.Fatalf("expected the userName to wait for the owner's confirmation, got %q", patched.UserName)
+	}
+}
+
+func TestSCIMUserNotProvisioned(t *testing.T) {
+	// Arrange
+	key := scimKey(t)
+	user := createUser(t, adminToken(t))
+	userPath := "/scim/v2/Users/" + strconv.FormatInt(user.ID, 10)
+
+	// Act
+	status, _ := doJSON(t, http.MethodPatch, userPath, map[string]any{
+		"schemas":    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+		"Operations": []map[string]any{{"op": "Replace", "path": "active", "value": "False"}},
+	}, key)
+
+	// Assert
+	if status != http.StatusForbidden {
+		t.Fatalf("expected scim to leave a user it did not provision alone, got status=%d", status)
+	}
+	if status, _ := doJSON(t, http.MethodDelete, userPath, nil, key); status != http.StatusForbidden {
+		t.Fatalf("expected scim delete of a user it did not provision to be forbidden, got status=%d", status)
+	}
+}
+
+func TestSCIMGroupMembers(t *testing.T) {
+	// Arrange
+	key := scimKey(t)
+	member := scimCreateUser(t, key).ID
+
+	// Act
+	status, body := doJSON(t, http.MethodPatch, "/scim/v2/Groups/viewer", map[string]any{
+		"schemas":    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+		"Operations": []map[string]any{{"op": "add", "path": "members", "value": []map[string]string{{"value": member}}}},
+	}, key)
+
+	// Assert
+	if status != http.StatusOK {
+		t.Fatalf("scim add group member failed: status=%d body=%s", status, body)
+	}
+	var group scimGroupData
+	decodeSCIM(t, body, &group)
+	if !scimHasMember(group, member) {
+		t.Fatalf("expected %s to be a member, got %+v", member, group.Members)
+	}
+
+	status, body = doJSON(t, http.MethodPatch, "/scim/v2/Groups/viewer", map[string]any{
+		"schemas":    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+		"Operations": []map[string]any{{"op": "remove", "path": `members[value eq "` + member + `"]`}},
+	}, key)
+	if status != http.StatusOK {
+		t.Fatalf("scim remove group member failed: status=%d body=%s", status, body)
+	}
+	decodeSCIM(t, body, &group)
+	if scimHasMember(group, member) {
+		t.Fatalf("expected %s to be removed, got %+v", member, group.Members)
+	}
+
+	if status, _ := doJSON(t, http.MethodGet, "/scim/v2/Groups/admin", nil, key); status != http.StatusNotFound {
+		t.Fatalf("expected a protected role to stay out of scim, got status=%d", status)
+	}
+}
+
+func scimHasMember(group scimGroupData, id string) bool {
+	for _, m := range group.Members {
+		if m.Value == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+type serviceAccountData struct {
+	ID           int64    `json:"id"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Roles        []string `json:"roles"`
+}
+
+type clientCredentialsData struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func createServiceAccount(t *testing.T, token string, roles []string) serviceAccountData {
+	t.Helper()
+
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/service-accounts", map[string]any{
+		"name":  "reporting service",
+		"roles": roles,
+	}, token)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("create service account failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data serviceAccountData
+	decodeSuccess(t, body, &data)
+
+	return data
+}
+
+func TestServiceAccountClientCredentials(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	sa := createServiceAccount(t, token, []string{"viewer"})
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/token", map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     sa.ClientID,
+		"client_secret": sa.ClientSecret,
+	}, "")
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("client credentials failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data clientCredentialsData
+	decodeSuccess(t, body, &data)
+	if data.AccessToken == "" || data.TokenType != "Bearer" || data.ExpiresIn <= 0 {
+		t.Fatalf("unexpected token response: %+v", data)
+	}
+
+	if status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/users", nil, data.AccessToken); status != http.StatusOK {
+		t.Fatalf("expected service account with viewer role to list users, got status=%d", status)
+	}
+
+	if status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/roles", nil, data.AccessToken); status != http.StatusForbidden {
+		t.Fatalf("expected service account without roles permission to be forbidden, got status=%d", status)
+	}
+
+	status, body = doJSON(t, http.MethodDelete, "/api/v1/identity/service-accounts/"+strconv.FormatInt(sa.ID, 10), nil, token)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("delete service account failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	if status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/users", nil, data.AccessToken); status != http.StatusForbidden {
+		t.Fatalf("expected deleted service account to lose its permissions, got status=%d", status)
+	}
+}
+
+func TestServiceAccountClientCredentialsInvalidSecret(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	sa := createServiceAccount(t, token, nil)
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/token", map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     sa.ClientID,
+		"client_secret": "gbs_wrong",
+	}, "")
+
+	// Assert
+	if status != http.StatusUnauthorized {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected invalid secret to be unauthorized, got status=%d message=%q", status, errEnv.Message)
+	}
+}
+
+func TestServiceAccountCreateProtectedRole(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/service-accounts", map[string]any{
+		"name":  "root service",
+		"roles": []string{"admin"},
+	}, token)
+
+	// Assert
+	if status != http.StatusForbidden {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected protected role to be refused, got status=%d message=%q", status, errEnv.Message)
+	}
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+type sessionsData struct {
+	Sessions []struct {
+		ID         int64  `json:"id"`
+		IP         string `json:"ip"`
+		UserAgent  string `json:"user_agent"`
+		LastUsedAt string `json:"last_used_at"`
+	} `json:"sessions"`
+}
+
+func TestListSessions(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	login(t, user.Email, user.Password)
+	loginResp := login(t, user.Email, user.Password)
+
+	// Act
+	status, body := doJSON(t, http.MethodGet, "/api/v1/identity/sessions", nil, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("list sessions failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data sessionsData
+	decodeSuccess(t, body, &data)
+	if len(data.Sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(data.Sessions))
+	}
+	if data.Sessions[0].IP == "" {
+		t.Fatalf("expected session ip to be recorded")
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	other := login(t, user.Email, user.Password)
+	loginResp := login(t, user.Email, user.Password)
+
+	_, body := doJSON(t, http.MethodGet, "/api/v1/identity/sessions", nil, loginResp.AccessToken)
+	var data sessionsData
+	decodeSuccess(t, body, &data)
+	if len(data.Sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(data.Sessions))
+	}
+	// Sessions are newest first, so the last one belongs to the first login.
+	target := data.Sessions[1].ID
+
+	// Act
+	status, body := doJSON(t, http.MethodDelete, "/api/v1/identity/sessions/"+strconv.FormatInt(target, 10), nil, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("revoke session failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	status, _ = doJSON(t, http.MethodPost, "/api/v1/identity/refresh", map[string]string{"refresh_token": other.RefreshToken}, "")
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected revoked session refresh to be unauthorized, got status=%d", status)
+	}
+
+	status, _ = doJSON(t, http.MethodDelete, "/api/v1/identity/sessions/"+strconv.FormatInt(target, 10), nil, loginResp.AccessToken)
+	if status != http.StatusNotFound {
+		t.Fatalf("expected revoking an already revoked session to return 404, got status=%d", status)
+	}
+}
+
+func TestListSessionsDeviceName(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	header := http.Header{"X-Client-Type": []string{"web"}, "X-Device-Name": []string{"Test Laptop"}}
+	resp, body := doJSONWithCookies(t, "/api/v1/identity/login",
+		map[string]string{"email": user.Email, "password": user.Password}, header, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("login failed: status=%d", resp.StatusCode)
+	}
+	var loginResp loginData
+	decodeSuccess(t, body, &loginResp)
+
+	// a refresh that does not name the device keeps the name given at sign-in
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/refresh", map[string]string{"refresh_token": loginResp.RefreshToken}, "")
+	if status != http.StatusOK {
+		t.Fatalf("refresh failed: status=%d", status)
+	}
+	var refreshed loginData
+	decodeSuccess(t, body, &refreshed)
+
+	// Act
+	status, body = doJSON(t, http.MethodGet, "/api/v1/identity/sessions", nil, refreshed.AccessToken)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("list sessions failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data struct {
+		Sessions []struct {
+			Client     string `json:"client"`
+			DeviceName string `json:"device_name"`
+		} `json:"sessions"`
+	}
+	decodeSuccess(t, body, &data)
+	if len(data.Sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d", len(data.Sessions))
+	}
+	if data.Sessions[0].DeviceName != "Test Laptop" || data.Sessions[0].Client != "web" {
+		t.Fatalf("expected device name and client on the session, got %+v", data.Sessions[0])
+	}
+}
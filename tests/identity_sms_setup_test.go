@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSMSSetupRejections(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	loginResp := login(t, user.Email, user.Password)
+
+	cases := []struct {
+		name    string
+		payload map[string]string
+		token   string
+		status  int
+	}{
+		{
+			name:    "Unauthenticated",
+			payload: map[string]string{"phone_number": "+6281234567890", "current_password": user.Password},
+			status:  http.StatusUnauthorized,
+		},
+		{
+			name:    "InvalidPhoneNumber",
+			payload: map[string]string{"phone_number": "081234567890", "current_password": user.Password},
+			token:   loginResp.AccessToken,
+			status:  http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			status, body := doJSON(t, http.MethodPost, "/api/v1/identity/mfa/sms/setup", tc.payload, tc.token)
+
+			// Assert
+			if status != tc.status {
+				errEnv := decodeError(t, body)
+				t.Fatalf("expected status %d, got %d message=%q", tc.status, status, errEnv.Message)
+			}
+		})
+	}
+}
+
+func TestLogin2FASMSInvalidChallenge(t *testing.T) {
+	// Arrange
+	payload := map[string]string{"challenge_token": "not-a-challenge"}
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/login/2fa/sms", payload, "")
+
+	// Assert
+	if status != http.StatusUnauthorized {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected status %d, got %d message=%q", http.StatusUnauthorized, status, errEnv.Message)
+	}
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type tokenClaimsData struct {
+	Scope   string `json:"scope"`
+	OrgID   int64  `json:"org_id,string"`
+	OrgRole string `json:"org_role"`
+}
+
+// decodeTokenClaims reads the payload of an access token without verifying it; these tests
+// check what the server put in the token, not its signature.
+func decodeTokenClaims(t *testing.T, token string) tokenClaimsData {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected a JWT, got %d parts", len(parts))
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decode token payload: %v", err)
+	}
+
+	var claims tokenClaimsData
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		t.Fatalf("unmarshal token payload: %v", err)
+	}
+
+	return claims
+}
+
+// These tests need modules.identity.token_claims.permissions and org_role turned on, as in
+// config.example.yaml.
+
+func TestTokenClaimsScope(t *testing.T) {
+	// Arrange
+	admin := adminToken(t)
+	role := createRole(t, admin)
+	user := createUser(t, admin)
+
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/roles/"+role+"/users",
+		map[string]string{"user_id": strconv.FormatInt(user.ID, 10)}, admin)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("add role member failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// Act
+	claims := decodeTokenClaims(t, login(t, user.Email, user.Password).AccessToken)
+
+	// Assert
+	scope := strings.Fields(claims.Scope)
+	if !slices.Contains(scope, "identity:management:users:read") {
+		t.Fatalf("expected the role permission in the scope claim, got %q", claims.Scope)
+	}
+	if claims.OrgRole != "" {
+		t.Fatalf("expected no org_role on a token outside an organization, got %q", claims.OrgRole)
+	}
+}
+
+func TestTokenClaimsOrgRole(t *testing.T) {
+	// Arrange
+	admin := adminToken(t)
+	org := createOrg(t, admin, "Claims")
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/orgs/"+strconv.FormatInt(org.ID, 10)+"/token", nil, admin)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("switch org failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var data struct {
+		AccessToken string `json:"access_token"`
+	}
+	decodeSuccess(t, body, &data)
+
+	claims := decodeTokenClaims(t, data.AccessToken)
+	if claims.OrgID != org.ID {
+		t.Fatalf("expected org_id %d, got %d", org.ID, claims.OrgID)
+	}
+	if claims.OrgRole != "owner" {
+		t.Fatalf("expected org_role owner, got %q", claims.OrgRole)
+	}
+}
@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestLogoutRevokesAccessToken(t *testing.T) {
+	// Arrange
+	resp := login(t, adminEmail, adminPassword)
+	payload := map[string]string{"refresh_token": resp.RefreshToken}
+
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/logout", payload, resp.AccessToken)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("logout failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// Act
+	status, _ = doJSON(t, http.MethodGet, "/api/v1/identity/profile", nil, resp.AccessToken)
+
+	// Assert
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected status %d for a logged out token, got %d", http.StatusUnauthorized, status)
+	}
+}
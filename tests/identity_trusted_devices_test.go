@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestTrustedDevice(t *testing.T) {
+
+	// Arrange
+	loginResp := login(t, userEmail, userPassword)
+	if !loginResp.MfaRequired || loginResp.ChallengeToken == "" {
+		t.Fatalf("expected MFA challenge on login")
+	}
+
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/login/2fa", map[string]any{
+		"challenge_token": loginResp.ChallengeToken,
+		"method":          "TOTP",
+		"code":            totpCode(t, userTOTPSecret),
+		"remember_device": true,
+	}, "")
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("login 2fa failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var mfaData struct {
+		AccessToken string `json:"access_token"`
+		DeviceToken string `json:"device_token"`
+	}
+	decodeSuccess(t, body, &mfaData)
+	if mfaData.DeviceToken == "" {
+		t.Fatal("expected device token in login 2fa response")
+	}
+
+	loginTrusted := func() loginData {
+		t.Helper()
+
+		status, body := doJSON(t, http.MethodPost, "/api/v1/identity/login", map[string]string{
+			"email":        userEmail,
+			"password":     userPassword,
+			"device_token": mfaData.DeviceToken,
+		}, "")
+		if status != http.StatusOK {
+			errEnv := decodeError(t, body)
+			t.Fatalf("login failed: status=%d message=%q", status, errEnv.Message)
+		}
+
+		var data loginData
+		decodeSuccess(t, body, &data)
+		return data
+	}
+
+	// Act & Assert: the remembered device skips the challenge
+	trusted := loginTrusted()
+	if trusted.MfaRequired || trusted.AccessToken == "" {
+		t.Fatalf("expected tokens without MFA challenge, got mfa_required=%v", trusted.MfaRequired)
+	}
+
+	status, body = doJSON(t, http.MethodGet, "/api/v1/identity/trusted-devices", nil, mfaData.AccessToken)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("list trusted devices failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var list struct {
+		Devices []struct {
+			ID int64 `json:"id"`
+		} `json:"devices"`
+	}
+	decodeSuccess(t, body, &list)
+	if len(list.Devices) == 0 {
+		t.Fatal("expected at least one trusted device")
+	}
+
+	// the newest device is the one remembered above
+	target := list.Devices[0].ID
+	status, body = doJSON(t, http.MethodDelete, "/api/v1/identity/trusted-devices/"+strconv.FormatInt(target, 10), nil, mfaData.AccessToken)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("revoke trusted device failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// Act & Assert: a revoked device asks for MFA again
+	revoked := loginTrusted()
+	if !revoked.MfaRequired || revoked.AccessToken != "" {
+		t.Fatal("expected MFA challenge after the device was revoked")
+	}
+
+	status, _ = doJSON(t, http.MethodDelete, "/api/v1/identity/trusted-devices/"+strconv.FormatInt(target, 10), nil, mfaData.AccessToken)
+	if status != http.StatusNotFound {
+		t.Fatalf("expected 404 revoking the device twice, got %d", status)
+	}
+}
@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestProfileUpdateUsernameDisabled(t *testing.T) {
+
+	// Arrange
+	loginResp := login(t, adminEmail, adminPassword)
+	payload := map[string]string{"username": "gobite.admin"}
+
+	// Act
+	status, body := doJSON(t, http.MethodPut, "/api/v1/identity/profile/username", payload, loginResp.AccessToken)
+
+	// Assert
+	if status != http.StatusNotFound {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected username update to be unavailable: status=%d message=%q", status, errEnv.Message)
+	}
+}
+
+func TestLoginUsernameDisabled(t *testing.T) {
+
+	// Arrange
+	payload := map[string]string{"username": "gobite.admin", "password": adminPassword}
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/login", payload, "")
+
+	// Assert
+	if status != http.StatusUnprocessableEntity {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected username login to be rejected: status=%d message=%q", status, errEnv.Message)
+	}
+}
@@ -1,7 +1,9 @@
 package tests
 
 import (
+	"bytes"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -28,3 +30,58 @@ func TestUsersExport(t *testing.T) {
 		t.Fatalf("expected users export data")
 	}
 }
+
+func TestUsersExportCSV(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, header, body := doAccept(t, http.MethodGet, "/api/v1/identity/users-export?format=csv", "", token)
+
+	// Assert
+	if status != http.StatusOK {
+		t.Fatalf("users csv export failed: status=%d body=%s", status, body)
+	}
+	if ct := header.Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if cd := header.Get("Content-Disposition"); !strings.Contains(cd, ".csv") {
+		t.Fatalf("expected a csv attachment, got %q", cd)
+	}
+	if !strings.HasPrefix(string(body), "id,email,full_name,avatar_url,status,updated_at\n") {
+		t.Fatalf("unexpected csv header: %q", strings.SplitN(string(body), "\n", 2)[0])
+	}
+}
+
+func TestUsersExportXLSXByAccept(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, header, body := doAccept(t, http.MethodGet, "/api/v1/identity/users-export",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", token)
+
+	// Assert
+	if status != http.StatusOK {
+		t.Fatalf("users xlsx export failed: status=%d", status)
+	}
+	if cd := header.Get("Content-Disposition"); !strings.Contains(cd, ".xlsx") {
+		t.Fatalf("expected an xlsx attachment, got %q", cd)
+	}
+	if !bytes.HasPrefix(body, []byte("PK")) {
+		t.Fatalf("expected a zip archive")
+	}
+}
+
+func TestUsersExportInvalidFormat(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/users-export?format=pdf", nil, token)
+
+	// Assert
+	if status != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown format, got %d", status)
+	}
+}
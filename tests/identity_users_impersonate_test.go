@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUserImpersonate(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	payload := map[string]any{"reason": "reproduce a reported dashboard issue"}
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/users/"+strconv.FormatInt(user.ID, 10)+"/impersonate", payload, token)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("impersonate failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	decodeSuccess(t, body, &data)
+	if data.AccessToken == "" || data.ExpiresIn <= 0 {
+		t.Fatalf("expected access token with expiry, got %+v", data)
+	}
+
+	parts := strings.Split(data.AccessToken, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected a JWT, got %d parts", len(parts))
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decode token payload: %v", err)
+	}
+	var claims struct {
+		Subject      string `json:"sub"`
+		Impersonated bool   `json:"impersonated"`
+		Actor        struct {
+			Subject string `json:"sub"`
+		} `json:"act"`
+	}
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		t.Fatalf("unmarshal token payload: %v", err)
+	}
+	if claims.Subject != strconv.FormatInt(user.ID, 10) || !claims.Impersonated || claims.Actor.Subject != "1" {
+		t.Fatalf("unexpected impersonation claims: %+v", claims)
+	}
+
+	status, body = doJSON(t, http.MethodGet, "/api/v1/identity/profile", nil, data.AccessToken)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("profile with impersonation token failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var profile struct {
+		Email string `json:"email"`
+	}
+	decodeSuccess(t, body, &profile)
+	if profile.Email != user.Email {
+		t.Fatalf("expected profile email %q, got %q", user.Email, profile.Email)
+	}
+}
+
+func TestUserImpersonateSelf(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	payload := map[string]any{"reason": "trying to impersonate myself"}
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/users/1/impersonate", payload, token)
+
+	// Assert
+	if status != http.StatusForbidden {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected status %d, got %d message=%q", http.StatusForbidden, status, errEnv.Message)
+	}
+}
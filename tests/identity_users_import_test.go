@@ -35,3 +35,62 @@ func TestUsersImport(t *testing.T) {
 		t.Fatalf("expected one import result, got created=%d updated=%d", data.Created, data.Updated)
 	}
 }
+
+func TestUsersImportFile(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	email := uniqueEmail("file-import")
+	csv := "email,password,full_name,status\n" +
+		email + ",Secret123!,File Import User,active\n" +
+		"not-an-email,,Broken Row,2\n" +
+		email + ",,Duplicate Row,2\n"
+
+	// Act
+	status, body := doMultipart(t, http.MethodPost, "/api/v1/identity/users-import/file", "file", "users.csv", []byte(csv), token)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("users file import failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data struct {
+		Created int `json:"created"`
+		Updated int `json:"updated"`
+		Failed  int `json:"failed"`
+		Errors  []struct {
+			Row   int    `json:"row"`
+			Field string `json:"field"`
+		} `json:"errors"`
+	}
+	decodeSuccess(t, body, &data)
+	if data.Created+data.Updated != 1 {
+		t.Fatalf("expected one imported row, got created=%d updated=%d", data.Created, data.Updated)
+	}
+	if data.Failed != 2 || len(data.Errors) != 2 {
+		t.Fatalf("expected two rejected rows, got failed=%d errors=%+v", data.Failed, data.Errors)
+	}
+	if data.Errors[0].Row != 3 || data.Errors[0].Field != "email" {
+		t.Fatalf("unexpected first row error: %+v", data.Errors[0])
+	}
+	if data.Errors[1].Row != 4 || data.Errors[1].Field != "email" {
+		t.Fatalf("unexpected second row error: %+v", data.Errors[1])
+	}
+}
+
+func TestUsersImportFileInvalid(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, _ := doMultipart(t, http.MethodPost, "/api/v1/identity/users-import/file", "file", "users.csv", []byte("full_name\nNo Email\n"), token)
+	statusExt, _ := doMultipart(t, http.MethodPost, "/api/v1/identity/users-import/file", "file", "users.txt", []byte("email\n"), token)
+
+	// Assert
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a file without an email column, got %d", status)
+	}
+	if statusExt != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for an unsupported file, got %d", statusExt)
+	}
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestUsersInvite(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	payload := map[string]string{
+		"email":     uniqueEmail("invited-user"),
+		"full_name": "Invited User",
+	}
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/users/invite", payload, token)
+
+	// Assert
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("user invite failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	// inviting a pending address again renews the invitation
+	status, body = doJSON(t, http.MethodPost, "/api/v1/identity/users/invite", payload, token)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("user re-invite failed: status=%d message=%q", status, errEnv.Message)
+	}
+}
+
+func TestUsersInviteActiveUser(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/users/invite", map[string]string{
+		"email":     user.Email,
+		"full_name": user.FullName,
+	}, token)
+
+	// Assert
+	if status != http.StatusConflict {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected 409 for active user, got status=%d message=%q", status, errEnv.Message)
+	}
+}
+
+func TestUsersInviteAcceptInvalidToken(t *testing.T) {
+	// Arrange
+	payload := map[string]string{
+		"challenge_token": "invalid-token",
+		"password":        "Secret123!",
+	}
+
+	// Act
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/invite/accept", payload, "")
+
+	// Assert
+	if status != http.StatusUnauthorized {
+		errEnv := decodeError(t, body)
+		t.Fatalf("expected 401 for invalid token, got status=%d message=%q", status, errEnv.Message)
+	}
+}
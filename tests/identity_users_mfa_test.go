@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+type userMFAData struct {
+	Factors []struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"factors"`
+	BackupCodesRemaining int `json:"backup_codes_remaining"`
+}
+
+func confirmTOTP(t *testing.T, token, password string) {
+	t.Helper()
+
+	challengeToken, key := setupTOTP(t, token, password)
+	payload := map[string]string{
+		"challenge_token": challengeToken,
+		"code":            totpCode(t, key),
+	}
+
+	status, body := doJSON(t, http.MethodPost, "/api/v1/identity/mfa/totp/confirm", payload, token)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("totp confirm failed: status=%d message=%q", status, errEnv.Message)
+	}
+}
+
+func TestUsersMFA(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	loginResp := login(t, user.Email, user.Password)
+	confirmTOTP(t, loginResp.AccessToken, user.Password)
+	path := "/api/v1/identity/users/" + strconv.FormatInt(user.ID, 10) + "/mfa"
+
+	// Act
+	status, body := doJSON(t, http.MethodGet, path, nil, token)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("user mfa failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data userMFAData
+	decodeSuccess(t, body, &data)
+	if len(data.Factors) != 1 || data.Factors[0].Type != "TOTP" {
+		t.Fatalf("expected a single TOTP factor, got %+v", data.Factors)
+	}
+}
+
+func TestUsersMFARevoke(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	loginResp := login(t, user.Email, user.Password)
+	confirmTOTP(t, loginResp.AccessToken, user.Password)
+	path := "/api/v1/identity/users/" + strconv.FormatInt(user.ID, 10) + "/mfa"
+	payload := map[string]string{"reason": "identity verified via support ticket"}
+
+	// Act
+	status, body := doJSON(t, http.MethodDelete, path, payload, token)
+
+	// Assert
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("user mfa revoke failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	status, body = doJSON(t, http.MethodGet, path, nil, token)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("user mfa failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data userMFAData
+	decodeSuccess(t, body, &data)
+	if len(data.Factors) != 0 || data.BackupCodesRemaining != 0 {
+		t.Fatalf("expected no factors after revoke, got %+v", data)
+	}
+}
+
+func TestUsersMFARevokeMissingReason(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	path := "/api/v1/identity/users/" + strconv.FormatInt(user.ID, 10) + "/mfa"
+
+	// Act
+	status, _ := doJSON(t, http.MethodDelete, path, map[string]string{"reason": ""}, token)
+
+	// Assert
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, status)
+	}
+}
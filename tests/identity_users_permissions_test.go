@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+type userPermissionsData struct {
+	Roles       []string            `json:"roles"`
+	Permissions map[string][]string `json:"permissions"`
+}
+
+func TestUsersPermissions(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, body := doJSON(t, http.MethodGet, "/api/v1/identity/users/1/permissions", nil, token)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("user permissions failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data userPermissionsData
+	decodeSuccess(t, body, &data)
+	if len(data.Roles) == 0 {
+		t.Fatalf("expected roles for seeded admin, got none")
+	}
+	if len(data.Permissions) == 0 {
+		t.Fatalf("expected permissions for seeded admin, got none")
+	}
+}
+
+func TestUsersPermissionsNotFound(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, _ := doJSON(t, http.MethodGet, "/api/v1/identity/users/999999999/permissions", nil, token)
+
+	// Assert
+	if status != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, status)
+	}
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+type userRolesData struct {
+	Roles []string `json:"roles"`
+}
+
+func TestUsersRolesUpdate(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	role := createRole(t, token)
+	user := createUser(t, token)
+	path := "/api/v1/identity/users/" + strconv.FormatInt(user.ID, 10) + "/roles"
+
+	// Act
+	status, body := doJSON(t, http.MethodPut, path, map[string]any{"roles": []string{role}}, token)
+
+	// Assert
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("update user roles failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	var data userRolesData
+	decodeSuccess(t, body, &data)
+	if !slices.Equal(data.Roles, []string{role}) {
+		t.Fatalf("expected roles [%s], got %v", role, data.Roles)
+	}
+
+	status, body = doJSON(t, http.MethodGet, "/api/v1/identity/users/"+strconv.FormatInt(user.ID, 10)+"/permissions", nil, token)
+	if status != http.StatusOK {
+		t.Fatalf("user permissions: expected status %d, got %d", http.StatusOK, status)
+	}
+
+	var perms userPermissionsData
+	decodeSuccess(t, body, &perms)
+	if !slices.Contains(perms.Roles, role) {
+		t.Fatalf("expected role %s in permissions, got %v", role, perms.Roles)
+	}
+
+	status, body = doJSON(t, http.MethodPut, path, map[string]any{"roles": []string{}}, token)
+	if status != http.StatusOK {
+		t.Fatalf("clear user roles: expected status %d, got %d", http.StatusOK, status)
+	}
+
+	decodeSuccess(t, body, &data)
+	if len(data.Roles) != 0 {
+		t.Fatalf("expected no roles, got %v", data.Roles)
+	}
+}
+
+func TestUsersRolesUpdateRejections(t *testing.T) {
+	token := adminToken(t)
+	user := createUser(t, token)
+
+	tests := []struct {
+		name  string
+		id    string
+		roles []string
+		want  int
+	}{
+		{
+			name:  "UnknownRole",
+			id:    strconv.FormatInt(user.ID, 10),
+			roles: []string{"does-not-exist"},
+			want:  http.StatusUnprocessableEntity,
+		},
+		{
+			name:  "UserNotFound",
+			id:    "999999999",
+			roles: []string{"viewer"},
+			want:  http.StatusNotFound,
+		},
+		{
+			name:  "RemoveOwnProtectedRole",
+			id:    "1",
+			roles: []string{},
+			want:  http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			status, _ := doJSON(t, http.MethodPut, "/api/v1/identity/users/"+tc.id+"/roles", map[string]any{"roles": tc.roles}, token)
+
+			// Assert
+			if status != tc.want {
+				t.Fatalf("expected status %d, got %d", tc.want, status)
+			}
+		})
+	}
+}
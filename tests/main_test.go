@@ -29,6 +29,7 @@ type successEnvelope struct {
 
 type errorEnvelope struct {
 	Message string            `json:"message"`
+	Reason  string            `json:"reason"`
 	Error   map[string]string `json:"error"`
 }
 
@@ -92,7 +93,7 @@ func doJSON(t *testing.T, method, path string, payload any, token string) (int,
 	return resp.StatusCode, respBody
 }
 
-func doMultipart(t *testing.T, path, fieldName, filename string, content []byte, token string) (int, []byte) {
+func doMultipart(t *testing.T, method, path, fieldName, filename string, content []byte, token string) (int, []byte) {
 	t.Helper()
 
 	buf := &bytes.Buffer{}
@@ -108,7 +109,7 @@ func doMultipart(t *testing.T, path, fieldName, filename string, content []byte,
 		t.Fatalf("close writer: %v", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPut, strings.TrimRight(baseURL(), "/")+path, buf)
+	req, err := http.NewRequest(method, strings.TrimRight(baseURL(), "/")+path, buf)
 	if err != nil {
 		t.Fatalf("new request: %v", err)
 	}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNotificationTemplateSave(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	tests := []struct {
+		name       string
+		path       string
+		payload    map[string]any
+		wantStatus int
+		wantField  string
+	}{
+		{
+			name:       "unknown email placeholder",
+			path:       "/api/v1/notification/templates/password_reset/email",
+			payload:    map[string]any{"subject": "[GoBite] Reset your password", "body": `<a href="{{.reset_link}}">Reset</a>`},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantField:  "body",
+		},
+		{
+			name:       "placeholder in email subject",
+			path:       "/api/v1/notification/templates/password_reset/email",
+			payload:    map[string]any{"subject": "Hi {{.full_name}}", "body": `<a href="{{.reset_url}}">Reset</a>`},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantField:  "subject",
+		},
+		{
+			name:       "unknown in-app placeholder",
+			path:       "/api/v1/notification/templates/user_welcome/in_app",
+			payload:    map[string]any{"subject": "Welcome", "body": "Hi {{first_name}}"},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantField:  "body",
+		},
+		{
+			name:       "unknown trigger",
+			path:       "/api/v1/notification/templates/not_a_trigger/email",
+			payload:    map[string]any{"subject": "Hello", "body": "Hello"},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			status, body := doJSON(t, http.MethodPut, tc.path, tc.payload, token)
+
+			// Assert
+			if status != tc.wantStatus {
+				errEnv := decodeError(t, body)
+				t.Fatalf("expected status %d, got %d message=%q", tc.wantStatus, status, errEnv.Message)
+			}
+			if tc.wantField != "" {
+				errEnv := decodeError(t, body)
+				if errEnv.Error[tc.wantField] == "" {
+					t.Fatalf("expected %s error, got %v", tc.wantField, errEnv.Error)
+				}
+			}
+		})
+	}
+}
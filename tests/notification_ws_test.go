@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestNotificationWebSocket(t *testing.T) {
+	// Arrange
+	url := "ws" + strings.TrimPrefix(strings.TrimRight(baseURL(), "/"), "http") + "/api/v1/notification/ws"
+	token := adminToken(t)
+
+	// Act
+	_, anonResp, anonErr := websocket.DefaultDialer.Dial(url, nil)
+	dialer := websocket.Dialer{Subprotocols: []string{"bearer", token}}
+	conn, resp, err := dialer.Dial(url, nil)
+
+	// Assert
+	if anonErr == nil || anonResp == nil || anonResp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for an unauthenticated upgrade, got %v", anonErr)
+	}
+	if err != nil {
+		t.Fatalf("dial websocket: %v", err)
+	}
+	defer conn.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+	if got := conn.Subprotocol(); got != "bearer" {
+		t.Fatalf("expected the bearer subprotocol, got %q", got)
+	}
+}
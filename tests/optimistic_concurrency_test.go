@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestUsersUpdateExpectedVersion(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	user := createUser(t, token)
+	path := "/api/v1/identity/users/" + strconv.FormatInt(user.ID, 10)
+
+	status, body := doJSON(t, http.MethodGet, path, nil, token)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("user detail failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var data struct {
+		User struct {
+			Version string `json:"version"`
+		} `json:"user"`
+	}
+	decodeSuccess(t, body, &data)
+	if data.User.Version == "" {
+		t.Fatalf("expected user version")
+	}
+
+	// Act
+	status, body = doJSON(t, http.MethodPut, path, map[string]any{
+		"full_name":        "First Admin",
+		"expected_version": data.User.Version,
+	}, token)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("user update failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	status, body = doJSON(t, http.MethodPut, path, map[string]any{
+		"full_name":        "Second Admin",
+		"expected_version": data.User.Version,
+	}, token)
+
+	// Assert
+	if status != http.StatusConflict {
+		t.Fatalf("expected status %d for a stale version, got %d", http.StatusConflict, status)
+	}
+	if errEnv := decodeError(t, body); errEnv.Reason != "version_mismatch" {
+		t.Fatalf("expected reason version_mismatch, got %q", errEnv.Reason)
+	}
+}
+
+func TestNotificationTemplateSaveExpectedVersion(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+	path := "/api/v1/notification/templates/user_welcome/in_app"
+
+	status, body := doJSON(t, http.MethodGet, path, nil, token)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("template detail failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var tpl struct {
+		Subject string `json:"subject"`
+		Body    string `json:"body"`
+		Version string `json:"version"`
+	}
+	decodeSuccess(t, body, &tpl)
+
+	// Act
+	status, body = doJSON(t, http.MethodPut, path, map[string]any{
+		"subject":          tpl.Subject,
+		"body":             tpl.Body,
+		"expected_version": tpl.Version,
+	}, token)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("template save failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	status, _ = doJSON(t, http.MethodPut, path, map[string]any{
+		"subject":          tpl.Subject,
+		"body":             tpl.Body,
+		"expected_version": tpl.Version,
+	}, token)
+
+	// Assert
+	if status != http.StatusConflict {
+		t.Fatalf("expected status %d for a stale version, got %d", http.StatusConflict, status)
+	}
+}
+
+func TestNotificationSettingsFirstUpdateExpectedVersion(t *testing.T) {
+	// Arrange
+	admin := adminToken(t)
+	user := createUser(t, admin)
+	token := login(t, user.Email, user.Password).AccessToken
+	path := "/api/v1/notification/settings"
+
+	status, body := doJSON(t, http.MethodGet, path, nil, token)
+	if status != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("list settings failed: status=%d message=%q", status, errEnv.Message)
+	}
+	var data struct {
+		Settings []struct {
+			CategoryID int64  `json:"category_id"`
+			Channel    string `json:"channel"`
+		} `json:"settings"`
+		Version string `json:"version"`
+	}
+	decodeSuccess(t, body, &data)
+	if len(data.Settings) == 0 {
+		t.Fatalf("expected notification settings")
+	}
+	// a user who stored nothing still gets a version, so the first writes can conflict
+	if data.Version == "" || data.Version == "0" {
+		t.Fatalf("expected a non-zero version before any update, got %q", data.Version)
+	}
+	settings := []map[string]any{{
+		"category_id": data.Settings[0].CategoryID,
+		"channel":     data.Settings[0].Channel,
+		"is_enabled":  true,
+	}}
+
+	// Act
+	status, body = doJSON(t, http.MethodPut, path, map[string]any{
+		"settings":         settings,
+		"expected_version": data.Version,
+	}, token)
+	if status != http.StatusNoContent {
+		errEnv := decodeError(t, body)
+		t.Fatalf("first settings update failed: status=%d message=%q", status, errEnv.Message)
+	}
+
+	status, body = doJSON(t, http.MethodPut, path, map[string]any{
+		"settings":         settings,
+		"expected_version": data.Version,
+	}, token)
+
+	// Assert
+	if status != http.StatusConflict {
+		t.Fatalf("expected status %d for a stale version, got %d", http.StatusConflict, status)
+	}
+	if errEnv := decodeError(t, body); errEnv.Reason != "version_mismatch" {
+		t.Fatalf("expected reason version_mismatch, got %q", errEnv.Reason)
+	}
+}
@@ -0,0 +1,23 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRateLimitDisabledByDefault(t *testing.T) {
+	// Act
+	resp, body := doJSONWithCookies(t, "/api/v1/identity/login",
+		map[string]string{"email": adminEmail, "password": adminPassword}, nil, nil)
+
+	// Assert
+	if resp.StatusCode != http.StatusOK {
+		errEnv := decodeError(t, body)
+		t.Fatalf("login failed: status=%d message=%q", resp.StatusCode, errEnv.Message)
+	}
+	for _, name := range []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy"} {
+		if got := resp.Header.Get(name); got != "" {
+			t.Fatalf("expected no %s header without rate limits, got %q", name, got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func doFormat(t *testing.T, path, casing, timeFormat, token string) (int, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(baseURL(), "/")+path, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("X-Response-Casing", casing)
+	req.Header.Set("X-Response-Time-Format", timeFormat)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+
+	return resp.StatusCode, body
+}
+
+func TestResponseFormatCamelEpoch(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, body := doFormat(t, "/api/v1/identity/users?size=1", "camel", "epoch", token)
+
+	// Assert
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", status, body)
+	}
+
+	var env struct {
+		Data struct {
+			Users []map[string]any `json:"users"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &env); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(env.Data.Users) == 0 {
+		t.Fatalf("expected a user, got %s", body)
+	}
+	user := env.Data.Users[0]
+	if _, ok := user["updated_at"]; ok {
+		t.Fatalf("expected camelCase keys, got %s", body)
+	}
+	if _, ok := user["updatedAt"].(float64); !ok {
+		t.Fatalf("expected updatedAt as Unix seconds, got %s", body)
+	}
+}
+
+func TestResponseFormatUnsupported(t *testing.T) {
+	// Arrange
+	token := adminToken(t)
+
+	// Act
+	status, body := doFormat(t, "/api/v1/identity/users?size=1", "kebab", "rfc3339", token)
+
+	// Assert
+	if status != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d body=%s", status, body)
+	}
+}